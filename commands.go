@@ -0,0 +1,492 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/tickerapp"
+	"github.com/micutio/airspottr/tuiapp"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// serveCommand spots planes without any output, e.g. as a service.
+	serveCommand = "serve"
+	// exportCommand writes the sighting history out, e.g. to move it into a spreadsheet.
+	exportCommand = "export"
+	// replayCommand replays the sighting history through alert rules, watchlists and scorers.
+	replayCommand = "replay"
+	// completionCommand prints the shell completion script.
+	completionCommand = "completion"
+	// tickerCommand prints plane spotting information on the command line.
+	tickerCommand = "ticker"
+)
+
+// command is a subcommand of airspottr with flags of its own, e.g. "airspottr ticker -L hamburg".
+type command struct {
+	name    string // name is the first argument which runs the command.
+	args    string // args describes the arguments after the flags, empty if there are none.
+	summary string // summary tells what the command does, as listed by the usage.
+	// setup registers the flags of the command and returns the function which runs it, with the
+	// arguments after the flags, once they are parsed.
+	setup func(flags *pflag.FlagSet) func(args []string)
+}
+
+// commands returns all commands, the first of which runs if none is given. It's a function rather
+// than a global since the completion command refers to all commands.
+func commands() []command {
+	return []command{
+		{name: "tui", args: "", summary: "spot planes in the terminal UI", setup: setupTUI},
+		{
+			name:    tickerCommand,
+			args:    "",
+			summary: "print plane spotting information on the command line without TUI",
+			setup:   setupTicker,
+		},
+		{
+			name:    serveCommand,
+			args:    "",
+			summary: "spot planes without any output, only serving the endpoints and event sinks",
+			setup:   setupServe,
+		},
+		{
+			name:    exportCommand,
+			args:    "",
+			summary: "write the sighting history out as CSV, JSON, a Markdown log, plane-alert CSV or posts",
+			setup:   setupExport,
+		},
+		{
+			name:    replayCommand,
+			args:    "",
+			summary: "replay the sighting history through an alert rule, a watchlist or another scorer",
+			setup:   setupReplay,
+		},
+		{
+			name:    "import-history",
+			args:    "DIR",
+			summary: "add the flights of a readsb globe_history directory to the sighting history",
+			setup:   setupImportHistory,
+		},
+		{
+			name:    updateDataCommand,
+			args:    "",
+			summary: "download and install updated datasets, or roll them back",
+			setup:   setupUpdateData,
+		},
+		{
+			name:    notificationsCommand,
+			args:    "",
+			summary: "print the last notifications",
+			setup:   setupNotifications,
+		},
+		{
+			name:    "healthcheck",
+			args:    "",
+			summary: "query the health endpoint of a running instance, exiting with 0 if healthy",
+			setup:   setupHealthcheck,
+		},
+		{
+			name:    completionCommand,
+			args:    strings.Join(completionShells, "|"),
+			summary: "print the shell completion script",
+			setup:   setupCompletion,
+		},
+	}
+}
+
+// findCommand returns the command named by the first argument and the arguments after it, or the
+// first command and all arguments if they start with a flag. It exits on an unknown command.
+func findCommand(args []string) (command, []string) {
+	cmd, rest, ok := lookupCommand(args)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		os.Exit(1)
+	}
+	if cmd.name == tickerCommand && strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Flag --ticker has been deprecated, use the ticker command instead")
+	}
+	return cmd, rest
+}
+
+// lookupCommand is findCommand without exiting, which tells whether the command is known. Before
+// there were commands, the ticker was run by the -t/--ticker flag, so without a command that flag
+// still runs the ticker, although it isn't listed anymore.
+func lookupCommand(args []string) (command, []string, bool) {
+	all := commands()
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if rest, isTicker := withoutTickerFlag(args); isTicker {
+			args = append([]string{tickerCommand}, rest...)
+		} else {
+			return all[0], rest, true
+		}
+	}
+	for _, cmd := range all {
+		if cmd.name == args[0] {
+			return cmd, args[1:], true
+		}
+	}
+	return command{}, nil, false //nolint:exhaustruct // unknown
+}
+
+// withoutTickerFlag removes the deprecated -t/--ticker flags from the arguments before "--", and
+// tells whether the last of them asked for the ticker, as "--ticker=false" doesn't.
+func withoutTickerFlag(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	isTicker := false
+	for idx, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[idx:]...)
+			break
+		}
+		switch {
+		case arg == "-t" || arg == "--ticker":
+			isTicker = true
+		case strings.HasPrefix(arg, "--ticker="):
+			value, err := strconv.ParseBool(strings.TrimPrefix(arg, "--ticker="))
+			isTicker = err == nil && value
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, isTicker
+}
+
+// runCommand parses the flags of the command from the arguments and the environment and runs it.
+func runCommand(cmd command, args []string) {
+	flags := pflag.NewFlagSet(cmd.name, pflag.ExitOnError)
+	flags.Usage = func() {
+		usage := strings.TrimSpace(fmt.Sprintf("%s %s [flags] %s", thisAppName, cmd.name, cmd.args))
+		fmt.Fprintf(os.Stderr, "Usage: %s\n\n%s\n\nFlags:\n", usage, cmd.summary)
+		flags.PrintDefaults()
+	}
+	run := cmd.setup(flags)
+	_ = flags.Parse(args)
+
+	if envErr := internal.ApplyEnv(flags); envErr != nil {
+		fmt.Fprintf(os.Stderr, "invalid environment: %v\n", envErr)
+		os.Exit(1)
+	}
+	run(flags.Args())
+}
+
+// printUsage lists all commands.
+func printUsage(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "Usage: %s [command] [flags]\n\nCommands:\n", thisAppName)
+	for _, cmd := range commands() {
+		_, _ = fmt.Fprintf(out, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintf(out, "\nWithout a command, %s runs %s. "+
+		"See %s <command> --help for the flags of each command.\n",
+		thisAppName, commands()[0].name, thisAppName)
+}
+
+// loadConfig loads the config file and sets the flags which were given neither on the command line
+// nor in the environment from its "flags", or exits if it fails.
+func loadConfig(flags *pflag.FlagSet, path string) internal.Config {
+	config, configErr := internal.LoadConfig(path)
+	if configErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", configErr)
+		os.Exit(1)
+	}
+
+	if flagsErr := internal.ApplyConfigFlags(flags, commandFlags(flags, config.Flags)); flagsErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", flagsErr)
+		os.Exit(1)
+	}
+	return config
+}
+
+// commandFlags leaves out the values of the flags which only other commands have, so that the
+// config file can hold the flags of all commands. Flags no command has are kept, to be rejected.
+func commandFlags(flags *pflag.FlagSet, values map[string]string) map[string]string {
+	others := make(map[string]bool)
+	for _, cmd := range commands() {
+		other := pflag.NewFlagSet(cmd.name, pflag.ContinueOnError)
+		cmd.setup(other)
+		other.VisitAll(func(flag *pflag.Flag) { others[flag.Name] = true })
+	}
+
+	kept := make(map[string]string, len(values))
+	for name, value := range values {
+		if flags.Lookup(name) != nil || !others[name] {
+			kept[name] = value
+		}
+	}
+	return kept
+}
+
+func setupTUI(flags *pflag.FlagSet) func([]string) {
+	var args spotArgs
+	args.register(flags)
+	args.registerTUI(flags)
+	return func([]string) {
+		options := args.options(flags)
+		if err := tuiapp.ValidateImages(options.Images); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := tuiapp.ValidateSnapshotFormat(options.Export.SnapshotFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := tuiapp.ValidateTheme(options.Theme); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		tuiapp.Run(thisAppName, options)
+	}
+}
+
+func setupTicker(flags *pflag.FlagSet) func([]string) {
+	var args spotArgs
+	args.register(flags)
+	args.registerTicker(flags)
+	return func([]string) {
+		tickerapp.Run(thisAppName, args.options(flags))
+	}
+}
+
+func setupServe(flags *pflag.FlagSet) func([]string) {
+	var args spotArgs
+	args.register(flags)
+	return func([]string) {
+		options := args.options(flags)
+		if options.Health.Addr == "" {
+			fmt.Fprintln(os.Stderr, serveCommand+" requires --health-addr")
+			os.Exit(1)
+		}
+		tickerapp.Serve(thisAppName, options)
+	}
+}
+
+func setupImportHistory(flags *pflag.FlagSet) func([]string) {
+	var args spotArgs
+	args.register(flags)
+	return func(dirs []string) {
+		if len(dirs) != 1 {
+			fmt.Fprintln(os.Stderr, "import-history requires the globe_history directory to import")
+			os.Exit(1)
+		}
+		runImportHistory(dirs[0], args.options(flags))
+	}
+}
+
+// exportFormats lists the formats the history can be exported in.
+func exportFormats() []string {
+	return []string{
+		internal.HistoryFormatCSV, internal.HistoryFormatJSON,
+		internal.ShareFormatMarkdown, internal.ShareFormatPlaneAlert, internal.ShareFormatPost,
+	}
+}
+
+func setupExport(flags *pflag.FlagSet) func([]string) {
+	configPath := flags.StringP("config", "c", internal.DefaultConfigPath, "path to the JSON config file")
+	historyPath := flags.String("history", internal.DefaultDataPath(internal.HistoryFileName),
+		"path to the sighting history file, or sqlite:PATH, bolt:PATH or a postgres:// URL of a database")
+	observer := flags.String("observer", defaultObserver(), "name of the instance whose sightings to export")
+	format := flags.String("format", internal.HistoryFormatCSV,
+		"format to export in: "+strings.Join(exportFormats(), ", "))
+	since := flags.Duration("since", 0, "how far back to export the history, 0 exports all of it")
+	output := flags.StringP("output", "o", "", "file to export to, empty writes to stdout")
+	templatePath := flags.String("template", "",
+		"file of a Go text/template to write the markdown, plane-alert or post format with instead")
+	rarityScorer := flags.String("rarity-scorer", internal.LogScorerName,
+		"rarity scoring strategy of the rare catches, one of: "+strings.Join(internal.RarityScorerNames(), ", "))
+	dayStartHour := flags.Int("day-start-hour", 0,
+		"hour of the day (0-23, local time) at which a new spotting day starts, e.g. 3")
+	return func([]string) {
+		loadConfig(flags, *configPath)
+		if internal.IsShareFormat(*format) {
+			runShareExport(*historyPath, *observer, *format, *since, *output,
+				shareOptions{templatePath: *templatePath, rarityScorer: *rarityScorer, dayStartHour: *dayStartHour})
+			return
+		}
+		runExport(*historyPath, *observer, *format, *since, *output)
+	}
+}
+
+func setupReplay(flags *pflag.FlagSet) func([]string) {
+	configPath := flags.StringP("config", "c", internal.DefaultConfigPath, "path to the JSON config file")
+	historyPath := flags.String("history", internal.DefaultDataPath(internal.HistoryFileName),
+		"path to the sighting history file, or sqlite:PATH, bolt:PATH or a postgres:// URL of a database")
+	observer := flags.String("observer", defaultObserver(), "name of the instance whose sightings to replay")
+	rarityScorer := flags.String("rarity-scorer", internal.LogScorerName,
+		"rarity scoring strategy, one of: "+strings.Join(internal.RarityScorerNames(), ", "))
+	compareScorer := flags.String("compare-scorer", "",
+		"rarity scorer to compare with --rarity-scorer by how many notifications each would have produced")
+	rule := flags.String("rule", "",
+		"print the sightings an alert rule with this condition would have fired for")
+	watch := flags.StringSlice("watch", nil, "print the sightings of the aircraft with these hexes")
+	since := flags.Duration("since", internal.DefaultBacktestPeriod,
+		"how far back --rule and --watch look into the history, 0 looks at all of it")
+	dayStartHour := flags.Int("day-start-hour", 0,
+		"hour of the day (0-23, local time) at which a new spotting day starts, e.g. 3")
+	timeZone := flags.String("time-zone", internal.TimeZoneLocal,
+		"time zone to show times in: local, utc or a name like Europe/Berlin")
+	timeFormat := flags.String("time-format", internal.DefaultTimeFormat,
+		"layout to show times in, as in Go's time package")
+	return func([]string) {
+		loadConfig(flags, *configPath)
+		if *rule == "" && len(*watch) == 0 && *compareScorer == "" {
+			fmt.Fprintln(os.Stderr, replayCommand+" requires --rule, --watch or --compare-scorer")
+			os.Exit(1)
+		}
+		if *historyPath == "" {
+			fmt.Fprintln(os.Stderr, replayCommand+" requires --history")
+			os.Exit(1)
+		}
+		timeDisplay, timeErr := internal.NewTimeDisplay(*timeZone, *timeFormat)
+		if timeErr != nil {
+			fmt.Fprintf(os.Stderr, "invalid time display: %v\n", timeErr)
+			os.Exit(1)
+		}
+
+		if *rule != "" || len(*watch) > 0 {
+			runBacktest(*historyPath, *observer, *rule, *watch, *since, *dayStartHour, timeDisplay)
+		}
+		if *compareScorer != "" {
+			runCompareHistory(*historyPath, *observer, *rarityScorer, *compareScorer)
+		}
+	}
+}
+
+func setupUpdateData(flags *pflag.FlagSet) func([]string) {
+	configPath := flags.StringP(
+		"config", "c", internal.DefaultConfigPath, "path to the JSON config file")
+	dataDir := flags.String("data-dir", defaultDataDir(), "directory to install the datasets into")
+	isRollback := flags.Bool("rollback", false, "go back to the datasets installed before")
+	return func([]string) {
+		runUpdateData(*configPath, *dataDir, *isRollback)
+	}
+}
+
+func setupNotifications(flags *pflag.FlagSet) func([]string) {
+	path := flags.String(
+		"notification-log", "", "path to a file of all notifications, empty reads them from the store of --history")
+	historyPath := flags.String("history", internal.DefaultDataPath(internal.HistoryFileName),
+		"path to the sighting history file, or sqlite:PATH, bolt:PATH or a postgres:// URL of a database")
+	observer := flags.String("observer", defaultObserver(), "name of the instance whose notifications to print")
+	count := flags.IntP("count", "n", defaultNotificationsShown, "how many notifications to print")
+	return func([]string) {
+		runNotifications(*path, *historyPath, *observer, *count)
+	}
+}
+
+func setupHealthcheck(flags *pflag.FlagSet) func([]string) {
+	configPath := flags.StringP("config", "c", internal.DefaultConfigPath, "path to the JSON config file")
+	healthAddr := flags.String("health-addr", "", "address the health endpoint is served on, e.g. :8080")
+	return func([]string) {
+		loadConfig(flags, *configPath)
+		runHealthcheck(*healthAddr)
+	}
+}
+
+func setupCompletion(*pflag.FlagSet) func([]string) {
+	return func(shells []string) {
+		if len(shells) != 1 {
+			fmt.Fprintf(os.Stderr, "%s requires the shell, one of: %s\n",
+				completionCommand, strings.Join(completionShells, ", "))
+			os.Exit(1)
+		}
+		if err := writeCompletion(os.Stdout, shells[0], commands()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runExport writes the sightings of the observer since the given period, or all if it is 0, to
+// the output file or stdout.
+func runExport(historyPath string, observer string, format string, period time.Duration, output string) {
+	entries, historyErr := loadHistory(historyPath, observer)
+	if historyErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load sighting history: %v\n", historyErr)
+		os.Exit(1)
+	}
+	if period > 0 {
+		since := time.Now().Add(-period)
+		recent := entries[:0]
+		for _, entry := range entries {
+			if !entry.Time.Before(since) {
+				recent = append(recent, entry)
+			}
+		}
+		entries = recent
+	}
+
+	writeErr := writeExport(output, func(out io.Writer) error {
+		return internal.WriteHistory(out, format, entries)
+	})
+	if writeErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to export sighting history: %v\n", writeErr)
+		os.Exit(1)
+	}
+}
+
+// shareOptions are the options of the export in a share format.
+type shareOptions struct {
+	templatePath string // templatePath is the file of the template to use, empty for the built-in one.
+	rarityScorer string
+	dayStartHour int
+}
+
+// runShareExport writes the sightings of the observer since the given period, or all if it is 0, in
+// a share format, i.e. as a Markdown log, a plane-alert CSV or posts of the rare catches.
+func runShareExport(
+	historyPath string,
+	observer string,
+	format string,
+	period time.Duration,
+	output string,
+	options shareOptions,
+) {
+	scorer, scorerErr := internal.NewRarityScorer(options.rarityScorer)
+	spottingDay, dayErr := internal.NewSpottingDay(options.dayStartHour)
+	var templateText []byte
+	var templateErr error
+	if options.templatePath != "" {
+		templateText, templateErr = os.ReadFile(options.templatePath)
+	}
+	if err := errors.Join(scorerErr, dayErr, templateErr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	entries, historyErr := loadHistory(historyPath, observer)
+	if historyErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load sighting history: %v\n", historyErr)
+		os.Exit(1)
+	}
+	since := time.Time{}
+	if period > 0 {
+		since = time.Now().Add(-period)
+	}
+
+	report := internal.NewShareReport(entries, since, spottingDay, scorer)
+	writeErr := writeExport(output, func(out io.Writer) error {
+		return internal.WriteShare(out, format, string(templateText), report)
+	})
+	if writeErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to export sighting history: %v\n", writeErr)
+		os.Exit(1)
+	}
+}
+
+// writeExport writes an export to the output file, or to stdout if it is empty.
+func writeExport(output string, write func(out io.Writer) error) error {
+	if output == "" {
+		return write(os.Stdout)
+	}
+	file, createErr := os.Create(output)
+	if createErr != nil {
+		return fmt.Errorf("writeExport: %w", createErr)
+	}
+	return errors.Join(write(file), file.Close())
+}
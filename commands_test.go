@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCommandFlags(t *testing.T) {
+	flags := pflag.NewFlagSet(tickerCommand, pflag.ContinueOnError)
+	setupTicker(flags)
+
+	values := map[string]string{
+		"quiet":   "true",          // the ticker's own
+		"history": "history.jsonl", // shared by several commands
+		"theme":   "dark",          // only the TUI has it
+		"bogus":   "1",             // no command has it
+	}
+	expected := map[string]string{"quiet": "true", "history": "history.jsonl", "bogus": "1"}
+	if kept := commandFlags(flags, values); !reflect.DeepEqual(kept, expected) {
+		t.Errorf("commandFlags() = %v, expected %v", kept, expected)
+	}
+}
+
+func TestLookupCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		command  string
+		rest     []string
+		expected bool
+	}{
+		{args: nil, command: "tui", rest: nil, expected: true},
+		{args: []string{"-L", "hamburg"}, command: "tui", rest: []string{"-L", "hamburg"}, expected: true},
+		{args: []string{"export", "-f", "csv"}, command: exportCommand, rest: []string{"-f", "csv"}, expected: true},
+		{args: []string{"ticker", "-q"}, command: tickerCommand, rest: []string{"-q"}, expected: true},
+		{args: []string{"fly"}, command: "", rest: nil, expected: false},
+		// The deprecated flag of the ticker still runs it.
+		{args: []string{"-L", "ham", "-t"}, command: tickerCommand, rest: []string{"-L", "ham"}, expected: true},
+		{args: []string{"--ticker", "-q"}, command: tickerCommand, rest: []string{"-q"}, expected: true},
+		{args: []string{"--ticker=false"}, command: "tui", rest: []string{}, expected: true},
+		{args: []string{"-q", "--", "-t"}, command: "tui", rest: []string{"-q", "--", "-t"}, expected: true},
+	}
+	for _, test := range tests {
+		cmd, rest, ok := lookupCommand(test.args)
+		isRestEqual := len(rest) == 0 && len(test.rest) == 0 || reflect.DeepEqual(rest, test.rest)
+		if ok != test.expected || cmd.name != test.command || !isRestEqual {
+			t.Errorf("lookupCommand(%q) = %s, %q, %t, expected %s, %q, %t",
+				test.args, cmd.name, rest, ok, test.command, test.rest, test.expected)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/tuiapp"
+	"github.com/spf13/pflag"
+)
+
+// completeCommand prints the values of a flag for the completion scripts. It isn't listed with
+// the other commands since it's only run by the scripts.
+const completeCommand = "__complete"
+
+// completedFlags are the flags whose values are completed, see flagValues.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var completedFlags = []string{
+	"location", "sources", "theme", "rarity-scorer", "compare-scorer", "type-rarity", "alert-tier",
+	"images", "snapshot-format", "format",
+}
+
+// completionShells lists the shells there are completion scripts for.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var completionShells = []string{"bash", "zsh", "fish"}
+
+var errUnknownShell = errors.New("unknown shell")
+
+// writeCompletion writes the completion script of the given shell, which completes the commands
+// and the flags of each command.
+func writeCompletion(out io.Writer, shell string, cmds []command) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion(cmds)
+	case "zsh":
+		// zsh understands the bash completion with bashcompinit.
+		script = "autoload -U +X bashcompinit && bashcompinit\n" + bashCompletion(cmds)
+	case "fish":
+		script = fishCompletion(cmds)
+	default:
+		return fmt.Errorf("writeCompletion: %w %q, expected one of: %s",
+			errUnknownShell, shell, strings.Join(completionShells, ", "))
+	}
+	if _, err := io.WriteString(out, script); err != nil {
+		return fmt.Errorf("writeCompletion: %w", err)
+	}
+	return nil
+}
+
+// runComplete prints the values of the flag given first, one per line. The words typed so far
+// follow it, to find the config file, e.g. for the names of the locations configured in it.
+func runComplete(args []string) {
+	if len(args) == 0 {
+		return
+	}
+	name := strings.TrimLeft(args[0], "-")
+	if len(name) == 1 {
+		if flag := commandFlagSet(commands()[0]).ShorthandLookup(name); flag != nil {
+			name = flag.Name
+		}
+	}
+	// Without a valid config file there's only less to complete.
+	config, _ := internal.LoadConfig(completionConfigPath(args[1:]))
+	for _, value := range flagValues(name, config) {
+		fmt.Println(value)
+	}
+}
+
+// completionConfigPath returns the path of the config file given with the typed words or in the
+// environment, or the default path.
+func completionConfigPath(words []string) string {
+	path := internal.DefaultConfigPath
+	if value, ok := os.LookupEnv(internal.EnvName("config")); ok {
+		path = value
+	}
+	for idx, word := range words {
+		switch {
+		case (word == "--config" || word == "-c") && idx+1 < len(words):
+			path = words[idx+1]
+		case strings.HasPrefix(word, "--config="):
+			path = strings.TrimPrefix(word, "--config=")
+		}
+	}
+	return path
+}
+
+// flagValues returns the values the flag of the given name can take, one of completedFlags, or
+// nil for other flags.
+func flagValues(name string, config internal.Config) []string {
+	switch name {
+	case "location":
+		names := slices.Collect(maps.Keys(predefinedLocations))
+		for location := range config.Locations {
+			if !slices.Contains(names, location) {
+				names = append(names, location)
+			}
+		}
+		slices.Sort(names)
+		return names
+	case "sources":
+		return internal.SourceNames()
+	case "theme":
+		return tuiapp.ThemeNames()
+	case "rarity-scorer", "compare-scorer":
+		return internal.RarityScorerNames()
+	case "type-rarity":
+		return []string{internal.TypeRarityType, internal.TypeRarityFamily}
+	case "alert-tier":
+		tiers := config.Tiers
+		if len(tiers) == 0 {
+			tiers = internal.DefaultProximityTiers()
+		}
+		names := make([]string, 0, len(tiers))
+		for _, tier := range tiers {
+			names = append(names, tier.Name)
+		}
+		return names
+	case "images":
+		return []string{tuiapp.ImagesAuto, tuiapp.ImagesKitty, tuiapp.ImagesITerm, tuiapp.ImagesOff}
+	case "snapshot-format":
+		return []string{tuiapp.SnapshotText, tuiapp.SnapshotCSV, tuiapp.SnapshotJSON}
+	case "format":
+		return exportFormats()
+	default:
+		return nil
+	}
+}
+
+// valueWords lists the completed flags of the command as they are typed, e.g. "--location|-L".
+func valueWords(flags *pflag.FlagSet) []string {
+	var words []string
+	for _, name := range completedFlags {
+		if flag := flags.Lookup(name); flag != nil {
+			words = append(words, "--"+flag.Name)
+			if flag.Shorthand != "" {
+				words = append(words, "-"+flag.Shorthand)
+			}
+		}
+	}
+	return words
+}
+
+// commandFlagSet returns the flags of the command, without running it.
+func commandFlagSet(cmd command) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(cmd.name, pflag.ContinueOnError)
+	cmd.setup(flags)
+	return flags
+}
+
+// flagWords lists the flags as they are typed, e.g. "--config" and "-c".
+func flagWords(flags *pflag.FlagSet) []string {
+	var words []string
+	flags.VisitAll(func(flag *pflag.Flag) {
+		words = append(words, "--"+flag.Name)
+		if flag.Shorthand != "" {
+			words = append(words, "-"+flag.Shorthand)
+		}
+	})
+	return words
+}
+
+// bashCompletion completes the commands and their flags, and falls back to file names, e.g. for
+// paths given to flags. Flags without a command are those of the first command.
+func bashCompletion(cmds []command) string {
+	names := make([]string, 0, len(cmds))
+	var cases strings.Builder
+	var valueFlags []string
+	for _, cmd := range cmds {
+		names = append(names, cmd.name)
+		flags := commandFlagSet(cmd)
+		fmt.Fprintf(&cases, "    %s) words=%q ;;\n", cmd.name, strings.Join(flagWords(flags), " "))
+		for _, word := range valueWords(flags) {
+			if !slices.Contains(valueFlags, word) {
+				valueFlags = append(valueFlags, word)
+			}
+		}
+	}
+
+	return fmt.Sprintf(`# bash completion for %[1]s
+_%[1]s() {
+  local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]} command=${COMP_WORDS[1]} words
+  case $prev in
+    %[5]s)
+      # Lists like --sources are completed after the last comma.
+      local prefix=""
+      [[ $cur == *,* ]] && prefix=${cur%%,*},
+      words=$(%[1]s %[6]s "$prev" "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)
+      COMPREPLY=($(compgen -P "$prefix" -W "$words" -- "${cur##*,}"))
+      return ;;
+  esac
+  if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+    COMPREPLY=($(compgen -W %[2]q -- "$cur"))
+    return
+  fi
+  [[ $command == -* || $COMP_CWORD -eq 1 ]] && command=%[3]s
+  case $command in
+%[4]s  esac
+  [[ $cur == -* ]] && COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -o default -F _%[1]s %[1]s
+`, thisAppName, strings.Join(names, " "), cmds[0].name, cases.String(), strings.Join(valueFlags, "|"),
+		completeCommand)
+}
+
+// fishCompletion completes the commands and their flags, with their descriptions.
+func fishCompletion(cmds []command) string {
+	var script strings.Builder
+	fmt.Fprintf(&script, "# fish completion for %s\n", thisAppName)
+	for _, cmd := range cmds {
+		fmt.Fprintf(&script, "complete -c %s -f -n __fish_use_subcommand -a %s -d %s\n",
+			thisAppName, cmd.name, fishQuote(cmd.summary))
+	}
+	for _, cmd := range cmds {
+		commandFlagSet(cmd).VisitAll(func(flag *pflag.Flag) {
+			fmt.Fprintf(&script, "complete -c %s -n '__fish_seen_subcommand_from %s' -l %s",
+				thisAppName, cmd.name, flag.Name)
+			if flag.Shorthand != "" {
+				fmt.Fprintf(&script, " -s %s", flag.Shorthand)
+			}
+			if slices.Contains(completedFlags, flag.Name) {
+				fmt.Fprintf(&script, " -x -a '(%s %s --%s (commandline -opc))'",
+					thisAppName, completeCommand, flag.Name)
+			}
+			fmt.Fprintf(&script, " -d %s\n", fishQuote(flag.Usage))
+		})
+	}
+	return script.String()
+}
+
+// fishQuote quotes the text for fish, in which only \ and ' are special within single quotes.
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text) + "'"
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestFlagValues(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected []string
+	}{
+		{flag: "format", expected: []string{"csv", "jsonl", "markdown", "plane-alert", "post"}},
+		{flag: "snapshot-format", expected: []string{"text", "csv", "json"}},
+		{flag: "history", expected: nil},
+	}
+	for _, test := range tests {
+		//nolint:exhaustruct // no config needed
+		if values := flagValues(test.flag, internal.Config{}); !slices.Equal(values, test.expected) {
+			t.Errorf("flagValues(%q) = %q, expected %q", test.flag, values, test.expected)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"math"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAcarsMessages is how many of the latest ACARS messages are kept per aircraft.
+	maxAcarsMessages = 20
+	// maxAcarsDatagram is the size of the largest JSON message read from acarsdec or vdlm2dec.
+	maxAcarsDatagram = 65535
+)
+
+var errAcarsUnidentified = errors.New("ACARS message without registration or hex")
+
+// AcarsOptions configures the listener for ACARS messages.
+type AcarsOptions struct {
+	// Addr is the UDP address acarsdec or vdlm2dec send their JSON output to, empty disables it.
+	Addr string
+}
+
+// AcarsMessage is an ACARS message as decoded by acarsdec or vdlm2dec. The OOOI times (gate out,
+// wheels off, wheels on, gate in) are only set for messages reporting them, as HHMM.
+type AcarsMessage struct {
+	Time         time.Time
+	Registration string // Registration is the tail number sent along, without the leading dots.
+	Hex          string // Hex is the ICAO address, which only vdlm2dec reports.
+	Flight       string
+	Label        string // Label is the two-character ACARS label telling the kind of message.
+	Text         string
+	Departure    string
+	Destination  string
+	GateOut      string
+	WheelsOff    string
+	WheelsOn     string
+	GateIn       string
+}
+
+// OOOI returns the OOOI times of the message, e.g. "out 1402, off 1415", or an empty string if it
+// reports none.
+func (msg AcarsMessage) OOOI() string {
+	var events []string
+	for _, event := range []struct{ name, time string }{
+		{"out", msg.GateOut},
+		{"off", msg.WheelsOff},
+		{"on", msg.WheelsOn},
+		{"in", msg.GateIn},
+	} {
+		if event.time != "" {
+			events = append(events, event.name+" "+event.time)
+		}
+	}
+	return strings.Join(events, ", ")
+}
+
+// acarsdecMessage is the JSON output of acarsdec and vdlm2dec, of which vdlm2dec adds the hex.
+type acarsdecMessage struct {
+	Timestamp float64 `json:"timestamp"`
+	Tail      string  `json:"tail"`
+	Icao      int     `json:"icao"`
+	Flight    string  `json:"flight"`
+	Label     string  `json:"label"`
+	Text      string  `json:"text"`
+	Depa      string  `json:"depa"`
+	Dsta      string  `json:"dsta"`
+	GateOut   string  `json:"gtout"`
+	WheelsOff string  `json:"wloff"`
+	WheelsOn  string  `json:"wlin"`
+	GateIn    string  `json:"gtin"`
+}
+
+// ParseAcarsMessage decodes a message of acarsdec or vdlm2dec. Messages which can't be related to
+// an aircraft are rejected.
+func ParseAcarsMessage(data []byte) (AcarsMessage, error) {
+	var raw acarsdecMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return AcarsMessage{}, fmt.Errorf("ParseAcarsMessage: %w", err) //nolint:exhaustruct // error
+	}
+
+	hex := ""
+	if raw.Icao != 0 {
+		hex = fmt.Sprintf("%06x", raw.Icao)
+	}
+	registration := strings.TrimLeft(strings.TrimSpace(raw.Tail), ".")
+	if registration == "" && hex == "" {
+		return AcarsMessage{}, fmt.Errorf("ParseAcarsMessage: %w", errAcarsUnidentified) //nolint:exhaustruct // error
+	}
+
+	seconds, fraction := math.Modf(raw.Timestamp)
+	return AcarsMessage{
+		Time:         time.Unix(int64(seconds), int64(fraction*float64(time.Second))),
+		Registration: registration,
+		Hex:          hex,
+		Flight:       strings.TrimSpace(raw.Flight),
+		Label:        raw.Label,
+		Text:         strings.TrimSpace(raw.Text),
+		Departure:    raw.Depa,
+		Destination:  raw.Dsta,
+		GateOut:      raw.GateOut,
+		WheelsOff:    raw.WheelsOff,
+		WheelsOn:     raw.WheelsOn,
+		GateIn:       raw.GateIn,
+	}, nil
+}
+
+// AcarsLog keeps the latest ACARS messages of every aircraft, to be correlated with the tracked
+// ones by registration or hex.
+type AcarsLog struct {
+	mutex    sync.Mutex
+	messages map[string][]AcarsMessage // messages are keyed by acarsKey, oldest first.
+}
+
+// NewAcarsLog creates an empty log.
+func NewAcarsLog() *AcarsLog {
+	return &AcarsLog{mutex: sync.Mutex{}, messages: make(map[string][]AcarsMessage)}
+}
+
+// acarsKey normalises registrations, since ACARS sends them without dashes, e.g. "DAIBL" for
+// "D-AIBL". Messages without registration are keyed by hex.
+func acarsKey(registration string, hex string) string {
+	if registration == "" {
+		return "hex:" + strings.ToLower(hex)
+	}
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(registration))
+}
+
+// Add keeps the message, dropping the oldest one of its aircraft beyond maxAcarsMessages.
+func (l *AcarsLog) Add(msg AcarsMessage) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	key := acarsKey(msg.Registration, msg.Hex)
+	messages := append(l.messages[key], msg)
+	if len(messages) > maxAcarsMessages {
+		messages = messages[len(messages)-maxAcarsMessages:]
+	}
+	l.messages[key] = messages
+}
+
+// Messages returns the messages of the aircraft with the given registration or hex, newest first.
+func (l *AcarsLog) Messages(registration string, hex string) []AcarsMessage {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var found []AcarsMessage
+	if registration != "" {
+		found = append(found, l.messages[acarsKey(registration, "")]...)
+	}
+	if hex != "" {
+		found = append(found, l.messages[acarsKey("", hex)]...)
+	}
+	slices.SortStableFunc(found, func(a, b AcarsMessage) int { return b.Time.Compare(a.Time) })
+	return found
+}
+
+// ListenAcars receives the JSON output of acarsdec (-j) or vdlm2dec (-j) on the given UDP address
+// in the background and adds the messages to the log.
+// Only failing to listen is returned, messages which can't be decoded are written to stderr.
+func ListenAcars(addr string, acars *AcarsLog, stderr *io.Writer) error {
+	conn, listenErr := net.ListenPacket("udp", addr)
+	if listenErr != nil {
+		return fmt.Errorf("ListenAcars: %w", listenErr)
+	}
+
+	errOut := log.New(*stderr, "acars ", log.LstdFlags)
+	go func() {
+		buffer := make([]byte, maxAcarsDatagram)
+		for {
+			size, _, readErr := conn.ReadFrom(buffer)
+			if readErr != nil {
+				errOut.Println(fmt.Errorf("ListenAcars: %w", readErr))
+				return
+			}
+			msg, parseErr := ParseAcarsMessage(buffer[:size])
+			if errors.Is(parseErr, errAcarsUnidentified) {
+				continue // e.g. ground station squitters, which belong to no aircraft
+			}
+			if parseErr != nil {
+				errOut.Println(fmt.Errorf("ListenAcars: %w", parseErr))
+				continue
+			}
+			acars.Add(msg)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAcarsMessage(t *testing.T) {
+	msg, err := ParseAcarsMessage([]byte(`{"timestamp": 1760796120.5, "channel": 1, "freq": 131.525,
+		"tail": ".D-AIBL", "flight": "LH0400", "label": "QP", "text": "", "depa": "EDDF", "dsta": "KJFK",
+		"gtout": "1402"}`))
+	if err != nil {
+		t.Fatalf("ParseAcarsMessage() error = %v", err)
+	}
+	if msg.Registration != "D-AIBL" || msg.Flight != "LH0400" || msg.Label != "QP" {
+		t.Errorf("ParseAcarsMessage() = %+v", msg)
+	}
+	if !msg.Time.Equal(time.Unix(1760796120, int64(time.Second/2))) {
+		t.Errorf("ParseAcarsMessage() time = %v", msg.Time)
+	}
+	if oooi := msg.OOOI(); oooi != "out 1402" {
+		t.Errorf("OOOI() = %q, expected out 1402", oooi)
+	}
+
+	vdl2, vdl2Err := ParseAcarsMessage([]byte(`{"timestamp": 1760796180, "icao": 3958200, "label": "H1"}`))
+	if vdl2Err != nil || vdl2.Hex != "3c65b8" {
+		t.Errorf("ParseAcarsMessage(vdlm2dec) = %+v, %v, expected hex 3c65b8", vdl2, vdl2Err)
+	}
+
+	if _, squitterErr := ParseAcarsMessage([]byte(`{"timestamp": 1760796180, "label": "SQ"}`)); squitterErr == nil {
+		t.Error("ParseAcarsMessage() accepted a message of no aircraft")
+	}
+}
+
+func TestAcarsLogCorrelation(t *testing.T) {
+	acars := NewAcarsLog()
+	start := time.Unix(1760796000, 0)
+	acars.Add(AcarsMessage{Time: start, Registration: "DAIBL", Label: "QP"})                       //nolint:exhaustruct // correlation only
+	acars.Add(AcarsMessage{Time: start.Add(2 * time.Minute), Hex: "3C65B8", Label: "H1"})          //nolint:exhaustruct // correlation only
+	acars.Add(AcarsMessage{Time: start.Add(time.Minute), Registration: "D-AIBL", Label: "5Z"})     //nolint:exhaustruct // correlation only
+	acars.Add(AcarsMessage{Time: start.Add(3 * time.Minute), Registration: "G-EUPT", Label: "QP"}) //nolint:exhaustruct // other aircraft
+
+	messages := acars.Messages("D-AIBL", "3c65b8")
+	if len(messages) != 3 {
+		t.Fatalf("Messages() returned %d messages, expected 3", len(messages))
+	}
+	for idx, label := range []string{"H1", "5Z", "QP"} {
+		if messages[idx].Label != label {
+			t.Errorf("Messages()[%d] = %s, expected %s, newest first", idx, messages[idx].Label, label)
+		}
+	}
+
+	for range maxAcarsMessages {
+		acars.Add(AcarsMessage{Time: start, Registration: "G-EUPT"}) //nolint:exhaustruct // count only
+	}
+	if count := len(acars.Messages("G-EUPT", "")); count != maxAcarsMessages {
+		t.Errorf("Messages() kept %d messages, expected %d", count, maxAcarsMessages)
+	}
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// The achievements which can be unlocked.
+const (
+	AchievementFirstMilitary = "first_military"
+	AchievementTypes100      = "types_100"
+	AchievementA320Family    = "a320_family"
+	AchievementCountries50   = "countries_50"
+
+	// achievementFamily is the type family of which every type has to be spotted.
+	achievementFamily = "Airbus A320 family"
+)
+
+// Achievement is a milestone of spotting, e.g. 100 different types, which is unlocked once its
+// goal is reached.
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	Goal        int // Goal is how many sightings, types or countries it takes.
+}
+
+// AchievementProgress is how far an achievement has come, and when it was unlocked.
+type AchievementProgress struct {
+	Achievement
+
+	Count    int
+	Unlocked time.Time // Unlocked is zero while the achievement is still locked.
+}
+
+// UnlockedAchievement is an achievement unlocked by a sighting.
+type UnlockedAchievement struct {
+	Achievement Achievement
+	Sighting    *AircraftSighting
+}
+
+// AchievementStats tracks the progress of all achievements across sessions, by recording the
+// sighting history first and every new sighting after.
+type AchievementStats struct {
+	achievements []Achievement
+	familyTypes  map[string]bool // familyTypes are the types of achievementFamily.
+	// milCodes maps military callsign codes to their operators, which are only loaded once needed.
+	milCodes   func() map[string]string
+	militaries map[string]bool // militaries are the military operators, nil until needed.
+	military   int
+	types      map[string]bool
+	countries  map[string]bool
+	familySeen map[string]bool
+	unlocked   map[string]time.Time
+}
+
+// NewAchievementStats creates the achievements, none of them unlocked. The types of the families
+// are by make and model, see familiesByType.
+func NewAchievementStats(typeFamilies map[string]string, milCodes func() map[string]string) *AchievementStats {
+	familyTypes := make(map[string]bool)
+	for aircraftType, family := range typeFamilies {
+		if family == achievementFamily {
+			familyTypes[aircraftType] = true
+		}
+	}
+	achievements := []Achievement{
+		{ID: AchievementFirstMilitary, Title: "First military catch", Description: "Spot a military aircraft", Goal: 1},
+		{ID: AchievementTypes100, Title: "Type collector", Description: "Spot 100 different types", Goal: 100},
+		{
+			ID:          AchievementA320Family,
+			Title:       "A320 family complete",
+			Description: "Spot every variant of the Airbus A320 family",
+			Goal:        len(familyTypes),
+		},
+		{ID: AchievementCountries50, Title: "Globetrotter", Description: "Spot aircraft of 50 countries", Goal: 50},
+	}
+	return &AchievementStats{
+		achievements: achievements,
+		familyTypes:  familyTypes,
+		milCodes:     milCodes,
+		militaries:   nil,
+		military:     0,
+		types:        make(map[string]bool),
+		countries:    make(map[string]bool),
+		familySeen:   make(map[string]bool),
+		unlocked:     make(map[string]time.Time),
+	}
+}
+
+// Record counts the sighting towards the achievements and returns those it unlocked, which are
+// unlocked at the time of the sighting.
+func (as *AchievementStats) Record(entry HistoryEntry) []Achievement {
+	if isKnownProperty(entry.Type) {
+		as.types[entry.Type] = true
+		if as.familyTypes[entry.Type] {
+			as.familySeen[entry.Type] = true
+		}
+	}
+	if isKnownProperty(entry.Country) {
+		as.countries[entry.Country] = true
+	}
+	if as.isMilitary(entry.Operator) {
+		as.military++
+	}
+
+	var unlocked []Achievement
+	for _, achievement := range as.achievements {
+		_, isUnlocked := as.unlocked[achievement.ID]
+		if isUnlocked || achievement.Goal == 0 || as.count(achievement.ID) < achievement.Goal {
+			continue
+		}
+		as.unlocked[achievement.ID] = entry.Time
+		unlocked = append(unlocked, achievement)
+	}
+	return unlocked
+}
+
+// isKnownProperty tells whether the type, operator or country of a sighting is known.
+func isKnownProperty(property string) bool {
+	return property != "" && !strings.EqualFold(property, typeUnknown)
+}
+
+// isMilitary tells whether the operator is a military one, e.g. an air force.
+func (as *AchievementStats) isMilitary(operator string) bool {
+	if !isKnownProperty(operator) {
+		return false
+	}
+	if as.militaries == nil {
+		as.militaries = make(map[string]bool)
+		for _, military := range as.milCodes() {
+			as.militaries[military] = true
+		}
+	}
+	return as.militaries[operator]
+}
+
+// count returns how far the achievement has come.
+func (as *AchievementStats) count(id string) int {
+	switch id {
+	case AchievementFirstMilitary:
+		return as.military
+	case AchievementTypes100:
+		return len(as.types)
+	case AchievementA320Family:
+		return len(as.familySeen)
+	case AchievementCountries50:
+		return len(as.countries)
+	default:
+		return 0
+	}
+}
+
+// Progress returns the progress of all achievements, in their order.
+func (as *AchievementStats) Progress() []AchievementProgress {
+	progress := make([]AchievementProgress, len(as.achievements))
+	for idx, achievement := range as.achievements {
+		progress[idx] = AchievementProgress{
+			Achievement: achievement,
+			Count:       min(as.count(achievement.ID), achievement.Goal),
+			Unlocked:    as.unlocked[achievement.ID],
+		}
+	}
+	return progress
+}
+
+// Unlocked returns how many achievements are unlocked.
+func (as *AchievementStats) Unlocked() int {
+	return len(as.unlocked)
+}
+
+// EmitAchievements sends an event for every unlocked achievement to all enabled sinks.
+func (notify *Notify) EmitAchievements(unlocked []UnlockedAchievement, now time.Time) {
+	for _, achievement := range unlocked {
+		notify.alert(achievementEvent(notify.language, achievement, now), NotifyAchievement)
+	}
+}
+
+func achievementEvent(lang i18n.Language, unlocked UnlockedAchievement, now time.Time) Event {
+	sighting := unlocked.Sighting
+	msgBody := fmt.Sprintf(
+		"%s\n%s %s (%s)",
+		lang.T(unlocked.Achievement.Description),
+		sighting.lastFlightNo,
+		sighting.typeDesc,
+		sighting.registration)
+	return Event{
+		Kind:     EventKindAchievement,
+		Severity: SeverityNotable,
+		Title:    lang.Sprintf("Achievement unlocked: %s", lang.T(unlocked.Achievement.Title)),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("achievement %s: %s", unlocked.Achievement.ID, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func achievementEntry(day int, aircraftType string, operator string, country string) HistoryEntry {
+	//nolint:exhaustruct // achievement fields only
+	return HistoryEntry{
+		Time:     time.Date(2026, 5, day, 12, 0, 0, 0, time.UTC),
+		Type:     aircraftType,
+		Operator: operator,
+		Country:  country,
+	}
+}
+
+func newTestAchievementStats() (*AchievementStats, *int) {
+	loads := 0
+	typeFamilies := map[string]string{
+		"AIRBUS, A-319":   achievementFamily,
+		"AIRBUS, A-320":   achievementFamily,
+		"BOEING, 737-800": "Boeing 737 NG",
+	}
+	stats := NewAchievementStats(typeFamilies, func() map[string]string {
+		loads++
+		return map[string]string{"GAF": "German Air Force", "RRR": "Royal Air Force"}
+	})
+	return stats, &loads
+}
+
+func TestAchievementStatsRecord(t *testing.T) {
+	stats, loads := newTestAchievementStats()
+
+	tests := []struct {
+		name     string
+		entry    HistoryEntry
+		expected []string
+	}{
+		{
+			name:     "airliner",
+			entry:    achievementEntry(1, "AIRBUS, A-320", "Lufthansa", "Germany"),
+			expected: nil,
+		},
+		{
+			name:     "unknown operator",
+			entry:    achievementEntry(2, "BOEING, 737-800", "unknown", "unknown"),
+			expected: nil,
+		},
+		{
+			name:     "military and the rest of the family",
+			entry:    achievementEntry(3, "AIRBUS, A-319", "German Air Force", "Germany"),
+			expected: []string{AchievementFirstMilitary, AchievementA320Family},
+		},
+		{
+			name:     "military again",
+			entry:    achievementEntry(4, "AIRBUS, A-319", "Royal Air Force", "United Kingdom"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		unlocked := stats.Record(tt.entry)
+		ids := make([]string, 0, len(unlocked))
+		for _, achievement := range unlocked {
+			ids = append(ids, achievement.ID)
+		}
+		if !slices.Equal(ids, tt.expected) {
+			t.Errorf("%s: Record() unlocked %v, expected %v", tt.name, ids, tt.expected)
+		}
+	}
+
+	if *loads != 1 {
+		t.Errorf("military codes were loaded %d times, expected once", *loads)
+	}
+	if stats.Unlocked() != 2 {
+		t.Errorf("Unlocked() = %d, expected 2", stats.Unlocked())
+	}
+}
+
+func TestAchievementStatsProgress(t *testing.T) {
+	stats, _ := newTestAchievementStats()
+	for idx := range 120 {
+		stats.Record(achievementEntry(1+idx/10, fmt.Sprintf("TYPE %d", idx), "", fmt.Sprintf("Country %d", idx%30)))
+	}
+
+	expected := map[string]struct {
+		count    int
+		goal     int
+		unlocked time.Time
+	}{
+		AchievementFirstMilitary: {count: 0, goal: 1, unlocked: time.Time{}},
+		AchievementTypes100:      {count: 100, goal: 100, unlocked: time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)},
+		AchievementA320Family:    {count: 0, goal: 2, unlocked: time.Time{}},
+		AchievementCountries50:   {count: 30, goal: 50, unlocked: time.Time{}},
+	}
+	progress := stats.Progress()
+	if len(progress) != len(expected) {
+		t.Fatalf("Progress() has %d achievements, expected %d", len(progress), len(expected))
+	}
+	for _, achievement := range progress {
+		want := expected[achievement.ID]
+		isUnlockedThen := achievement.Unlocked.Equal(want.unlocked)
+		if achievement.Count != want.count || achievement.Goal != want.goal || !isUnlockedThen {
+			t.Errorf("%s is at %d/%d, unlocked %v, expected %d/%d, unlocked %v", achievement.ID,
+				achievement.Count, achievement.Goal, achievement.Unlocked, want.count, want.goal, want.unlocked)
+		}
+	}
+}
+
+func TestAchievementWithoutFamily(t *testing.T) {
+	stats := NewAchievementStats(nil, func() map[string]string { return nil })
+	for _, achievement := range stats.Record(achievementEntry(1, "AIRBUS, A-320", "", "")) {
+		t.Errorf("Record() unlocked %s without any types of the family", achievement.ID)
+	}
+}
+
+func TestAchievementEvent(t *testing.T) {
+	now := time.Date(2026, 5, 3, 12, 0, 0, 0, time.UTC)
+	//nolint:exhaustruct // only the described fields
+	sighting := &AircraftSighting{lastFlightNo: "DLH400", typeDesc: "BOEING 747-8", registration: "D-ABYA"}
+	event := achievementEvent(i18n.English, UnlockedAchievement{
+		Achievement: Achievement{ID: "first", Title: "First catch", Description: "Spot an aircraft", Goal: 1},
+		Sighting:    sighting,
+	}, now)
+	if event.Kind != EventKindAchievement || !event.Time.Equal(now) {
+		t.Errorf("achievementEvent() = %+v, expected an achievement at %v", event, now)
+	}
+}
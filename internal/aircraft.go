@@ -1,105 +1,125 @@
 package internal
 
 import (
-	"fmt"
+	"encoding/json"
 	"strings"
-	"unicode"
 )
 
 // See https://www.adsbexchange.com/version-2-api-wip/
 // for further explanations of the fields
 
-// aircraftResult mirrors the JSON which is returned for aircraft queries within a given distance.
-type aircraftResult struct {
-	Now         float64          `json:"now"`         // time this file was generated in [ms]
-	ResultCount int              `json:"resultCount"` // total count of aircraft returned
-	Ptime       float64          `json:"ptime"`       // server processing time required in [ms]
-	Aircraft    []AircraftRecord `json:"aircraft"`    // list of Aircraft records
-}
-
 // AircraftRecord is used by both civilian and military aircraft queries.
 type AircraftRecord struct {
-	Alert           int      `json:"alert"`            // Flight status alert bit
-	AltBaro         any      `json:"alt_baro"`         // altitude in [feet] or string "ground"
-	AltGeom         int      `json:"alt_geom"`         // altitude in [feet]
-	BaroRate        float64  `json:"baro_rate"`        // rate of change of baro alt in [feet/minute]
-	EmitterCategory string   `json:"category"`         // emitter category to identify aircraft or vehicle classes (A0-D7)
-	Emergency       string   `json:"emergency"`        // emergency/priority status, 7X00
-	Flight          string   `json:"Flight"`           // Flight number, a.k.a. callsign
-	GroundSpeed     float64  `json:"gs"`               // ground speed in [knots]
-	Gva             float64  `json:"gva"`              // geometric vertical accuracy
-	Hex             string   `json:"hex"`              // hex code ID for aircraft, assumed to be unique
-	Lat             float64  `json:"lat"`              // Latitude in [decimal degrees]
-	Lon             float64  `json:"lon"`              // Longitude in [decimal degrees]
-	Messages        int      `json:"messages"`         // total number of Mode-S msg received from aircraft
-	Mlat            []string `json:"mlat"`             // position calculation arrival time diffs
-	NacP            float64  `json:"nac_p"`            // navigation accuracy for position
-	NacV            float64  `json:"nac_v"`            // navigation accuracy for velocity
-	NavAltitudeMcp  int      `json:"nav_altitude_mcp"` // selected from mode or Flight control panel (MCP)/(FCP) or other
-	NavHeading      float64  `json:"nav_heading"`      // selected heading (True/Magnetic), magnetic is de-facto standard
-	NavQNH          float64  `json:"nav_qnh"`          // altimeter setting (QFE  or QNH/QNE) in [hPa]
-	Nic             int      `json:"nic"`              // Navigation Integrity Category
-	NicBaro         int      `json:"nic_baro"`         // NIC for barometric altitude
-	Registration    string   `json:"r"`                // Registration of the aircraft
-	RadiusOfCtn     float64  `json:"rc"`               // Radius of containment, measure of position integrity in [meters]
-	Rssi            float64  `json:"rssi"`             // recent average signal power, always negative, in [dbFS]
-	Sda             int      `json:"sda"`              // system design assurance
-	Seen            float64  `json:"seen"`             // last message received from aircraft in [seconds] from 'now'
-	SeenPos         float64  `json:"seen_pos"`         // last update of position from aircraft in [seconds] from 'now'
-	Sil             int      `json:"sil"`              // Source integrity level
-	SilType         string   `json:"sil_type"`         // Source integrity level type
-	Spi             int      `json:"spi"`              // Flight status special position identification bit
-	Squawk          string   `json:"squawk"`           // Mode A code (Squawk) encoded as 4 octal digits
-	IcaoType        string   `json:"t"`                // aircraft ICAO type pulled from database
-	Tisb            []string `json:"tisb"`             // list of fields derived from TIS-B data
-	Track           float64  `json:"track"`            // true track over ground in degrees (0-359)
-	Type            string   `json:"type"`             // type of underlying messages
-	Version         int      `json:"version"`          // ADS-B Version number 0,1,2 (3-7 are reserved)
-	GeomRate        float64  `json:"geom_rate"`        // Rate of change of geometric (GNSS/INS) altitude in [ft/min]
-	DBFlags         int      `json:"dbFlags"`          // bitfield for certain database flags (programming language)
-	NavModes        []string `json:"nav_modes"`        // (autopilot, vnav, althold, approach, lnav, tcas)
-	TrueHeading     float64  `json:"true_heading"`     // Heading clockwise from true north in [degrees]
-	Ias             float64  `json:"ias"`              // indicated airspeed in [knots]
-	Mach            float64  `json:"mach"`             // Mach number
-	MagHeading      float64  `json:"mag_heading"`      // Heading clockwise from magnetic north in [degrees]
-	Oat             float64  `json:"oat"`              // outer air temperature
-	Roll            float64  `json:"roll"`             // roll, negative is left, in [degrees]
-	Tas             float64  `json:"tas"`              // true airspeed in [knots]
-	Tat             float32  `json:"tat"`              // total air temperature, might be inaccurate at lower alt, in [C]
-	TrackRate       float64  `json:"track_rate"`       // rate of change of track in [degrees/second]
-	WindDirection   float64  `json:"wd"`               // wind direction
-	WindSpeed       float64  `json:"ws"`               // wind speed
-	GpsOkBefore     float64  `json:"gpsOkBefore"`      // experimental, last timestamp of working GPS
-	GpsOkLat        float64  `json:"gpsOkLat"`         // experimental, last timestamp of working Latitude
-	GpsOkLon        float64  `json:"gpsOkLon"`         // experimental, last timestamp of working Longitude
-	LastPosition    any      `json:"lastPosition"`     // TODO: Type
-	RrLat           float64  `json:"rr_lat"`           // rough estimated latitude if no ADS-B or MLAT available
-	RrLon           float64  `json:"rr_lon"`           // rough estimated longitude if no ADS-B or MLAT available
-	CalcTrack       any      `json:"calc_track"`       // ? TODO
-	NavAltitudeFMS  float64  `json:"nav_altitude_fms"` // selected altitude from the Flight management system (FMS)
+	Alert           int           `json:"alert"`            // Flight status alert bit
+	AltBaro         Altitude      `json:"alt_baro"`         // altitude in [feet] or "ground"
+	AltGeom         int           `json:"alt_geom"`         // altitude in [feet]
+	BaroRate        float64       `json:"baro_rate"`        // rate of change of baro alt in [feet/minute]
+	EmitterCategory string        `json:"category"`         // emitter category to identify aircraft or vehicle classes (A0-D7)
+	Emergency       string        `json:"emergency"`        // emergency/priority status, 7X00
+	Flight          string        `json:"Flight"`           // Flight number, a.k.a. callsign
+	GroundSpeed     float64       `json:"gs"`               // ground speed in [knots]
+	Gva             float64       `json:"gva"`              // geometric vertical accuracy
+	Hex             string        `json:"hex"`              // hex code ID for aircraft, assumed to be unique
+	Lat             float64       `json:"lat"`              // Latitude in [decimal degrees]
+	Lon             float64       `json:"lon"`              // Longitude in [decimal degrees]
+	Messages        int           `json:"messages"`         // total number of Mode-S msg received from aircraft
+	Mlat            []string      `json:"mlat"`             // position calculation arrival time diffs
+	NacP            float64       `json:"nac_p"`            // navigation accuracy for position
+	NacV            float64       `json:"nac_v"`            // navigation accuracy for velocity
+	NavAltitudeMcp  int           `json:"nav_altitude_mcp"` // selected from mode or Flight control panel (MCP)/(FCP) or other
+	NavHeading      float64       `json:"nav_heading"`      // selected heading (True/Magnetic), magnetic is de-facto standard
+	NavQNH          float64       `json:"nav_qnh"`          // altimeter setting (QFE  or QNH/QNE) in [hPa]
+	Nic             int           `json:"nic"`              // Navigation Integrity Category
+	NicBaro         int           `json:"nic_baro"`         // NIC for barometric altitude
+	Registration    string        `json:"r"`                // Registration of the aircraft
+	RadiusOfCtn     float64       `json:"rc"`               // Radius of containment, measure of position integrity in [meters]
+	Rssi            float64       `json:"rssi"`             // recent average signal power, always negative, in [dbFS]
+	Sda             int           `json:"sda"`              // system design assurance
+	Seen            float64       `json:"seen"`             // last message received from aircraft in [seconds] from 'now'
+	SeenPos         float64       `json:"seen_pos"`         // last update of position from aircraft in [seconds] from 'now'
+	Sil             int           `json:"sil"`              // Source integrity level
+	SilType         string        `json:"sil_type"`         // Source integrity level type
+	Spi             int           `json:"spi"`              // Flight status special position identification bit
+	Squawk          string        `json:"squawk"`           // Mode A code (Squawk) encoded as 4 octal digits
+	IcaoType        string        `json:"t"`                // aircraft ICAO type pulled from database
+	Tisb            []string      `json:"tisb"`             // list of fields derived from TIS-B data
+	Track           float64       `json:"track"`            // true track over ground in degrees (0-359)
+	Type            string        `json:"type"`             // type of underlying messages
+	Version         int           `json:"version"`          // ADS-B Version number 0,1,2 (3-7 are reserved)
+	GeomRate        float64       `json:"geom_rate"`        // Rate of change of geometric (GNSS/INS) altitude in [ft/min]
+	DBFlags         int           `json:"dbFlags"`          // bitfield for certain database flags (programming language)
+	NavModes        []string      `json:"nav_modes"`        // (autopilot, vnav, althold, approach, lnav, tcas)
+	TrueHeading     float64       `json:"true_heading"`     // Heading clockwise from true north in [degrees]
+	Ias             float64       `json:"ias"`              // indicated airspeed in [knots]
+	Mach            float64       `json:"mach"`             // Mach number
+	MagHeading      float64       `json:"mag_heading"`      // Heading clockwise from magnetic north in [degrees]
+	Oat             *float64      `json:"oat"`              // outer air temperature in [C], nil if not reported
+	Roll            float64       `json:"roll"`             // roll, negative is left, in [degrees]
+	Tas             float64       `json:"tas"`              // true airspeed in [knots]
+	Tat             float32       `json:"tat"`              // total air temperature, might be inaccurate at lower alt, in [C]
+	TrackRate       float64       `json:"track_rate"`       // rate of change of track in [degrees/second]
+	WindDirection   float64       `json:"wd"`               // wind direction
+	WindSpeed       float64       `json:"ws"`               // wind speed
+	GpsOkBefore     float64       `json:"gpsOkBefore"`      // experimental, last timestamp of working GPS
+	GpsOkLat        float64       `json:"gpsOkLat"`         // experimental, last timestamp of working Latitude
+	GpsOkLon        float64       `json:"gpsOkLon"`         // experimental, last timestamp of working Longitude
+	LastPosition    *LastPosition `json:"lastPosition"`     // last known position if the live one is outdated, nil otherwise
+	RrLat           float64       `json:"rr_lat"`           // rough estimated latitude if no ADS-B or MLAT available
+	RrLon           float64       `json:"rr_lon"`           // rough estimated longitude if no ADS-B or MLAT available
+	CalcTrack       any           `json:"calc_track"`       // ? TODO
+	NavAltitudeFMS  float64       `json:"nav_altitude_fms"` // selected altitude from the Flight management system (FMS)
 	// found by my own investigation
 	OwnOp       string `json:"ownOp"` // owner or operator, only rarely set
 	Description string `json:"desc"`  // aircraft type description
 	// cached data
 	CachedDist float64
 	CachedType string
+	CachedTier string // CachedTier is the name of the proximity tier of CachedDist, if any.
+	Source     string // Source is the data source the position was taken from.
 }
 
-// GetAltitudeAsStr reads the altitude of an aircraft and returns it as a string.
-// The altitude is stored either as a string 'ground' or as a float denoting the measured
-// barometric altitude.
-// If the latter is the case, the float will be formatted without any decimal places
-// (unnecessary accuracy) and converted to string.
-func (ac *AircraftRecord) GetAltitudeAsStr() string {
-	if num, numOk := ac.AltBaro.(float64); numOk {
-		return fmt.Sprintf("%5.0f", num)
-	}
+// LastPosition is the last known position of an aircraft, which readsb based feeds report in place
+// of lat and lon once the position is older than a minute.
+type LastPosition struct {
+	Lat     float64 `json:"lat"`      // Latitude in [decimal degrees]
+	Lon     float64 `json:"lon"`      // Longitude in [decimal degrees]
+	Nic     int     `json:"nic"`      // Navigation Integrity Category
+	Rc      float64 `json:"rc"`       // Radius of containment in [meters]
+	SeenPos float64 `json:"seen_pos"` // last update of position in [seconds] from 'now'
+}
 
-	if str, strOk := ac.AltBaro.(string); strOk {
-		return str
+// UnmarshalJSON leaves the last position unknown if a feed reports it in another format, instead
+// of failing the whole response over a field which is only a fallback anyway.
+func (p *LastPosition) UnmarshalJSON(data []byte) error {
+	type plainLastPosition LastPosition
+	var decoded plainLastPosition
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		*p = LastPosition{Lat: 0, Lon: 0, Nic: 0, Rc: 0, SeenPos: 0}
+		return nil //nolint:nilerr // see above
 	}
+	*p = LastPosition(decoded)
+	return nil
+}
 
-	return altitudeUnknown
+// KnownPosition is where an aircraft is, or was last seen.
+type KnownPosition struct {
+	Lat     float64
+	Lon     float64
+	SeenPos float64 // SeenPos is how many seconds ago the position was received.
+	Stale   bool    // Stale is true if the aircraft has no live position and this is its last one.
+}
+
+// KnownPosition returns the live position of the aircraft or, without one, its last known
+// position. It returns false if the aircraft has no position at all.
+func (ac *AircraftRecord) KnownPosition() (KnownPosition, bool) {
+	if ac.Lat != 0 || ac.Lon != 0 {
+		return KnownPosition{Lat: ac.Lat, Lon: ac.Lon, SeenPos: ac.SeenPos, Stale: false}, true
+	}
+	last := ac.LastPosition
+	if last == nil || (last.Lat == 0 && last.Lon == 0) {
+		return KnownPosition{Lat: 0, Lon: 0, SeenPos: 0, Stale: false}, false
+	}
+	return KnownPosition{Lat: last.Lat, Lon: last.Lon, SeenPos: last.SeenPos, Stale: true}, true
 }
 
 // GetFlightNoAsStr converts the Flight number to a string.
@@ -112,15 +132,16 @@ func (ac *AircraftRecord) GetFlightNoAsStr() string {
 	return strings.TrimSpace(ac.Flight)
 }
 
-// GetFlightNoAsIcaoCode trims whitespaces and digits from the Flight number,
-// resulting in the three-digit icao code for civilian flights and arbitrary length codes
-// for military, government and private flights.
+// GetFlightNoAsIcaoCode returns the letters of the Flight number before its numeric suffix,
+// resulting in the three-letter icao code for civilian flights, e.g. "DLH" of "DLH9TK", and
+// arbitrary length codes for military, government and private flights.
 func (ac *AircraftRecord) GetFlightNoAsIcaoCode() string {
-	if len(ac.Flight) == 0 {
+	designator, _ := splitCallsign(ac.Flight)
+	if designator == "" {
 		return flightUnknownCode
 	}
 
-	return stripDigits(strings.TrimSpace(ac.Flight))
+	return designator
 }
 
 // GetRegistrationPrefix returns the prefix of the registration if it exists,
@@ -134,15 +155,6 @@ func (ac *AircraftRecord) GetRegistrationPrefix() string {
 	return ac.Registration
 }
 
-func stripDigits(str string) string {
-	return strings.Map(func(r rune) rune {
-		if unicode.IsDigit(r) {
-			return -1 // Remove the digit
-		}
-		return r // Keep the character
-	}, str)
-}
-
 // ByFlight implements the comparator interface and allows sorting a list of aircraft records
 // by Flight.
 type ByFlight []AircraftRecord
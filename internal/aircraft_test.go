@@ -1,6 +1,9 @@
 package internal
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 type testFlight struct {
 	flightNo        string
@@ -11,6 +14,10 @@ type testFlight struct {
 func getTestFlights() []testFlight {
 	return []testFlight{
 		{"SIA106  ", "SIA", "SINGAPORE AIRLINES LIMITED"},
+		{"DLH9TK", "DLH", "DEUTSCHE LUFTHANSA, AG, KOELN"},
+		{"GAF681", "GAF", ""},
+		{"DEABC", "DEABC", ""},
+		{"", flightUnknownCode, ""},
 	}
 }
 
@@ -27,3 +34,51 @@ func TestFlightToAirlineConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestLastPosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		want      KnownPosition
+		wantKnown bool
+	}{
+		{
+			name:      "live position",
+			json:      `{"hex":"3c6444","lat":53.6,"lon":10.0,"seen_pos":1.5}`,
+			want:      KnownPosition{Lat: 53.6, Lon: 10.0, SeenPos: 1.5, Stale: false},
+			wantKnown: true,
+		},
+		{
+			name: "last position",
+			json: `{"hex":"3c6444","lastPosition":` +
+				`{"lat":53.6,"lon":10.0,"nic":8,"rc":186,"seen_pos":95.2}}`,
+			want:      KnownPosition{Lat: 53.6, Lon: 10.0, SeenPos: 95.2, Stale: true},
+			wantKnown: true,
+		},
+		{
+			name:      "malformed last position",
+			json:      `{"hex":"3c6444","lastPosition":"unknown"}`,
+			want:      KnownPosition{Lat: 0, Lon: 0, SeenPos: 0, Stale: false},
+			wantKnown: false,
+		},
+		{
+			name:      "no position",
+			json:      `{"hex":"3c6444","lastPosition":null}`,
+			want:      KnownPosition{Lat: 0, Lon: 0, SeenPos: 0, Stale: false},
+			wantKnown: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var aircraft AircraftRecord
+			if err := json.Unmarshal([]byte(tt.json), &aircraft); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			got, known := aircraft.KnownPosition()
+			if got != tt.want || known != tt.wantKnown {
+				t.Errorf("KnownPosition() = %+v, %v, expected %+v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// airframeLegGap is how long an airframe may be out of sight before the same callsign counts
+	// as another flight, e.g. the same shuttle on the next day.
+	airframeLegGap = time.Hour
+	// maxAirframeLegs is how many flights are kept per airframe.
+	maxAirframeLegs = 10
+)
+
+// FlightLeg is a flight of an airframe, from when it was first to when it was last seen.
+type FlightLeg struct {
+	Flight    string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Describe tells when the leg was flown, relative to now, e.g. "SIA305 3 days ago".
+func (leg FlightLeg) Describe(now time.Time) string {
+	return fmt.Sprintf("%s %s", leg.Flight, describeAgo(now.Sub(leg.LastSeen)))
+}
+
+// describeAgo describes the duration as a time in the past, in its largest unit.
+func describeAgo(elapsed time.Duration) string {
+	const day = 24 * time.Hour
+	plural := func(count int, unit string) string {
+		if count == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", count, unit)
+	}
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return plural(int(elapsed/time.Minute), "minute")
+	case elapsed < 2*day:
+		return plural(int(elapsed/time.Hour), "hour")
+	default:
+		return plural(int(elapsed/day), "day")
+	}
+}
+
+// AirframeHistory links the flights of each airframe, by hex, across callsign changes and
+// sessions, so that an aircraft can be recognised as one seen before under another callsign.
+type AirframeHistory struct {
+	legs map[string][]FlightLeg // hex mapped to flights, oldest first
+}
+
+// NewAirframeHistory creates an empty airframe history.
+func NewAirframeHistory() *AirframeHistory {
+	return &AirframeHistory{legs: make(map[string][]FlightLeg)}
+}
+
+// Record notes that the airframe was seen flying the given flight at the given time. Unknown
+// flights are left out, since they can't tell legs apart.
+func (h *AirframeHistory) Record(hex string, flight string, seen time.Time) {
+	flight = strings.TrimSpace(flight)
+	if hex == "" || flight == "" || flight == strings.TrimSpace(flightUnknown) {
+		return
+	}
+
+	legs := h.legs[hex]
+	if last := len(legs) - 1; last >= 0 && legs[last].Flight == flight &&
+		seen.Sub(legs[last].LastSeen) < airframeLegGap {
+		if seen.After(legs[last].LastSeen) {
+			legs[last].LastSeen = seen
+		}
+		return
+	}
+
+	legs = append(legs, FlightLeg{Flight: flight, FirstSeen: seen, LastSeen: seen})
+	if len(legs) > maxAirframeLegs {
+		legs = legs[len(legs)-maxAirframeLegs:]
+	}
+	h.legs[hex] = legs
+}
+
+// Len returns how many airframes are known.
+func (h *AirframeHistory) Len() int {
+	return len(h.legs)
+}
+
+// Evict forgets the least recently seen airframes once there are more than the cap, until there
+// are few enough.
+func (h *AirframeHistory) Evict(limit int) {
+	if len(h.legs) <= limit {
+		return
+	}
+	lastSeen := func(hex string) time.Time {
+		legs := h.legs[hex]
+		return legs[len(legs)-1].LastSeen
+	}
+	hexes := make([]string, 0, len(h.legs))
+	for hex := range h.legs {
+		hexes = append(hexes, hex)
+	}
+	slices.SortFunc(hexes, func(a string, b string) int {
+		return lastSeen(a).Compare(lastSeen(b))
+	})
+	for _, hex := range hexes[:len(hexes)-keptBelow(limit)] {
+		delete(h.legs, hex)
+	}
+}
+
+// Previous returns the flights of the airframe before the current one, newest first.
+// The latest leg is the current one if it has the current callsign and was seen recently.
+func (h *AirframeHistory) Previous(hex string, current string, now time.Time) []FlightLeg {
+	legs := h.legs[hex]
+	if last := len(legs) - 1; last >= 0 && legs[last].Flight == strings.TrimSpace(current) &&
+		now.Sub(legs[last].LastSeen) < airframeLegGap {
+		legs = legs[:last]
+	}
+	previous := slices.Clone(legs)
+	slices.Reverse(previous)
+	return previous
+}
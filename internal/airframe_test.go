@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAirframeHistoryLegs(t *testing.T) {
+	history := NewAirframeHistory()
+	start := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	history.Record("76cdb1", "SIA305 ", start)
+	history.Record("76cdb1", "SIA305", start.Add(10*time.Minute))
+	history.Record("76cdb1", flightUnknown, start.Add(20*time.Minute))
+	history.Record("76cdb1", "SIA306", start.Add(3*time.Hour))
+	// The same callsign a day later is another flight.
+	history.Record("76cdb1", "SIA306", start.Add(27*time.Hour))
+
+	now := start.Add(27*time.Hour + 5*time.Minute)
+	previous := history.Previous("76cdb1", "SIA306", now)
+	if len(previous) != 2 {
+		t.Fatalf("Previous() = %v, expected 2 earlier flights", previous)
+	}
+	if previous[0].Flight != "SIA306" || previous[1].Flight != "SIA305" {
+		t.Errorf("Previous() = %v, expected newest first", previous)
+	}
+	if !previous[1].LastSeen.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("LastSeen = %v, expected the leg to be extended", previous[1].LastSeen)
+	}
+
+	// Once out of sight, the latest flight counts as a previous one as well.
+	if later := history.Previous("76cdb1", "SIA306", now.Add(2*time.Hour)); len(later) != 3 {
+		t.Errorf("Previous() = %v, expected 3 earlier flights", later)
+	}
+	if other := history.Previous("3c6444", "DLH1", now); len(other) != 0 {
+		t.Errorf("Previous() = %v, expected no flights of an unknown airframe", other)
+	}
+}
+
+func TestFlightLegDescribe(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lastSeen time.Time
+		expected string
+	}{
+		{now.Add(-30 * time.Second), "SIA305 just now"},
+		{now.Add(-time.Minute), "SIA305 1 minute ago"},
+		{now.Add(-5 * time.Hour), "SIA305 5 hours ago"},
+		{now.Add(-3 * 24 * time.Hour), "SIA305 3 days ago"},
+	}
+	for _, tt := range tests {
+		leg := FlightLeg{Flight: "SIA305", FirstSeen: tt.lastSeen, LastSeen: tt.lastSeen}
+		if described := leg.Describe(now); described != tt.expected {
+			t.Errorf("Describe() = %q, expected %q", described, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/micutio/airspottr/internal/rules"
+)
+
+// RuleMatch combines an aircraft sighting with the custom alert rule it matched.
+type RuleMatch struct {
+	Rule     *rules.Rule
+	Sighting *AircraftSighting
+}
+
+// compileRules parses the conditions of all configured rules. Names have to be unique, since a rule
+// fires once per flight by its name.
+func compileRules(ruleConfigs []RuleConfig) ([]*rules.Rule, error) {
+	compiled := make([]*rules.Rule, 0, len(ruleConfigs))
+	names := make(map[string]bool, len(ruleConfigs))
+	for _, ruleConfig := range ruleConfigs {
+		if names[ruleConfig.Name] {
+			return nil, fmt.Errorf("compileRules: %w: duplicate rule name %q", errInvalidConfig, ruleConfig.Name)
+		}
+		names[ruleConfig.Name] = true
+		rule, err := rules.New(ruleConfig.Name, ruleConfig.Condition, ruleConfig.Actions, ruleConfig.Webhook)
+		if err != nil {
+			return nil, fmt.Errorf("compileRules: %w", err)
+		}
+		if ruleConfig.Severity != "" {
+			severity, severityErr := ParseSeverity(ruleConfig.Severity)
+			if severityErr != nil {
+				return nil, fmt.Errorf("compileRules: rule %q: %w", ruleConfig.Name, severityErr)
+			}
+			rule.Severity = string(severity)
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// evaluateRules checks all rules against the given aircraft.
+// Every rule fires at most once per flight of an aircraft.
+func (db *Dashboard) evaluateRules(sighting *AircraftSighting, aircraft *AircraftRecord) []RuleMatch {
+	if len(db.alertRules) == 0 {
+		return nil
+	}
+
+	var matches []RuleMatch
+	fields := sightingFields(sighting, aircraft)
+	for _, rule := range db.alertRules {
+		if sighting.firedRules[rule.Name] || !rule.Matches(fields) {
+			continue
+		}
+
+		if sighting.firedRules == nil {
+			sighting.firedRules = make(map[string]bool)
+		}
+		sighting.firedRules[rule.Name] = true
+		matches = append(matches, RuleMatch{Rule: rule, Sighting: sighting})
+	}
+	return matches
+}
+
+// sightingFields collects the fields of an aircraft which rule conditions can refer to. The altitude
+// is zero on the ground and missing if it is unknown, so that no comparison with it matches.
+func sightingFields(sighting *AircraftSighting, aircraft *AircraftRecord) rules.Fields {
+	fields := rules.Fields{
+		"hex":          aircraft.Hex,
+		"flight":       aircraft.GetFlightNoAsStr(),
+		"registration": sighting.registration,
+		"type":         aircraft.IcaoType,
+		"model":        sighting.typeDesc,
+		"description":  aircraft.Description,
+		"operator":     sighting.operator,
+		"country":      sighting.country,
+		"squawk":       aircraft.Squawk,
+		"category":     aircraft.EmitterCategory,
+		"speed":        aircraft.GroundSpeed,
+		"distance":     aircraft.CachedDist,
+		"tier":         aircraft.CachedTier,
+		"source":       aircraft.Source,
+		"heading":      aircraft.NavHeading,
+	}
+	if altitude, isAirborne := aircraft.AltBaro.Feet(); isAirborne {
+		fields["altitude"] = altitude
+	} else if aircraft.AltBaro.IsGround() {
+		fields["altitude"] = 0.0
+	}
+	return fields
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/micutio/airspottr/internal/rules"
+)
+
+func TestRuleAltitudeUnknown(t *testing.T) {
+	rule, err := rules.New("low", "altitude < 3000", []string{"log"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		altitude Altitude
+		expected bool
+	}{
+		{name: "low", altitude: NewAltitude(1500), expected: true},
+		{name: "high", altitude: NewAltitude(35000), expected: false},
+		{name: "ground", altitude: GroundAltitude(), expected: true},
+		{name: "unknown", altitude: Altitude{}, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aircraft := &AircraftRecord{Hex: "3c6444", AltBaro: test.altitude} //nolint:exhaustruct // altitude only
+			fields := sightingFields(&AircraftSighting{}, aircraft)            //nolint:exhaustruct // no identity
+			if matches := rule.Matches(fields); matches != test.expected {
+				t.Errorf("Matches(altitude %v) = %t, expected %t", test.altitude, matches, test.expected)
+			}
+		})
+	}
+}
+
+func TestCompileRulesDuplicateName(t *testing.T) {
+	_, err := compileRules([]RuleConfig{
+		{Name: "low", Condition: "altitude < 1000", Actions: []string{"log"}},       //nolint:exhaustruct // no webhook
+		{Name: "low", Condition: "altitude < 3000", Actions: []string{"notify"}},    //nolint:exhaustruct // no webhook
+		{Name: "heavy", Condition: `category == "A5"`, Actions: []string{"notify"}}, //nolint:exhaustruct // no webhook
+	})
+	if !errors.Is(err, errInvalidConfig) {
+		t.Errorf("compileRules() = %v, expected errInvalidConfig for the duplicate name", err)
+	}
+}
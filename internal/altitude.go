@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// altitudeGround is how the APIs report aircraft on the ground instead of an altitude.
+const altitudeGround = "ground"
+
+var errInvalidAltitude = errors.New("invalid altitude")
+
+// Altitude is the barometric altitude of an aircraft, which the APIs report either as a number of
+// feet or as "ground". The zero value is an unknown altitude.
+type Altitude struct {
+	feet   float64
+	known  bool
+	ground bool
+}
+
+// NewAltitude creates the altitude of an airborne aircraft.
+func NewAltitude(feet float64) Altitude {
+	return Altitude{feet: feet, known: true, ground: false}
+}
+
+// GroundAltitude creates the altitude of an aircraft on the ground.
+func GroundAltitude() Altitude {
+	return Altitude{feet: 0, known: true, ground: true}
+}
+
+// IsKnown tells whether the altitude was reported at all.
+func (a Altitude) IsKnown() bool {
+	return a.known
+}
+
+// IsGround tells whether the aircraft is on the ground.
+func (a Altitude) IsGround() bool {
+	return a.ground
+}
+
+// Feet returns the altitude in [feet], if the aircraft is airborne and its altitude is known.
+func (a Altitude) Feet() (float64, bool) {
+	return a.feet, a.known && !a.ground
+}
+
+// String formats the altitude without decimal places, which would be unnecessary accuracy.
+func (a Altitude) String() string {
+	switch {
+	case !a.known:
+		return altitudeUnknown
+	case a.ground:
+		return altitudeGround
+	default:
+		return fmt.Sprintf("%5.0f", a.feet)
+	}
+}
+
+// UnmarshalJSON reads the altitude from a number, from "ground" or from a number in a string.
+// Null leaves the altitude unknown.
+func (a *Altitude) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*a = Altitude{feet: 0, known: false, ground: false}
+		return nil
+	}
+
+	var feet float64
+	if err := json.Unmarshal(data, &feet); err == nil {
+		*a = NewAltitude(feet)
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("Altitude.UnmarshalJSON: %w: %s", errInvalidAltitude, data)
+	}
+
+	str = strings.TrimSpace(str)
+	if strings.EqualFold(str, altitudeGround) {
+		*a = GroundAltitude()
+		return nil
+	}
+
+	feet, parseErr := strconv.ParseFloat(str, 64)
+	if parseErr != nil {
+		return fmt.Errorf("Altitude.UnmarshalJSON: %w: %q", errInvalidAltitude, str)
+	}
+	*a = NewAltitude(feet)
+	return nil
+}
+
+// MarshalJSON writes the altitude the way the APIs report it.
+func (a Altitude) MarshalJSON() ([]byte, error) {
+	switch {
+	case !a.known:
+		return []byte("null"), nil
+	case a.ground:
+		return json.Marshal(altitudeGround) //nolint:wrapcheck // marshalling a string can't fail
+	default:
+		return json.Marshal(a.feet) //nolint:wrapcheck // marshalling a number can't fail
+	}
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAltitudeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		feet     float64
+		airborne bool
+		ground   bool
+		known    bool
+		wantErr  bool
+	}{ //nolint:exhaustruct // unset fields are zero on purpose
+		{name: "number", json: `35000`, feet: 35000, airborne: true, known: true},
+		{name: "integer-like float", json: `1250.0`, feet: 1250, airborne: true, known: true},
+		{name: "ground", json: `"ground"`, ground: true, known: true},
+		{name: "numeric string", json: `"4200"`, feet: 4200, airborne: true, known: true},
+		{name: "null", json: `null`},
+		{name: "garbage", json: `"high"`, wantErr: true},
+		{name: "object", json: `{}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var altitude Altitude
+			err := json.Unmarshal([]byte(test.json), &altitude)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", test.json, err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+
+			feet, airborne := altitude.Feet()
+			if feet != test.feet || airborne != test.airborne {
+				t.Errorf("Feet() = %v, %v, expected %v, %v", feet, airborne, test.feet, test.airborne)
+			}
+			if altitude.IsGround() != test.ground || altitude.IsKnown() != test.known {
+				t.Errorf("IsGround() = %v, IsKnown() = %v", altitude.IsGround(), altitude.IsKnown())
+			}
+		})
+	}
+}
+
+func TestAltitudeRecordAndString(t *testing.T) {
+	var record AircraftRecord
+	if err := json.Unmarshal([]byte(`{"hex":"3c6444","alt_baro":"ground"}`), &record); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if record.AltBaro.String() != altitudeGround {
+		t.Errorf("String() = %q, expected %q", record.AltBaro.String(), altitudeGround)
+	}
+
+	var missing AircraftRecord
+	if err := json.Unmarshal([]byte(`{"hex":"3c6444"}`), &missing); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if missing.AltBaro.String() != altitudeUnknown {
+		t.Errorf("String() = %q, expected %q", missing.AltBaro.String(), altitudeUnknown)
+	}
+
+	if str := NewAltitude(3500.4).String(); str != " 3500" {
+		t.Errorf("String() = %q, expected %q", str, " 3500")
+	}
+
+	for _, altitude := range []Altitude{NewAltitude(1200), GroundAltitude(), {}} {
+		data, err := json.Marshal(altitude)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		var decoded Altitude
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded != altitude {
+			t.Errorf("round trip of %v gave %v, %v", altitude, decoded, err)
+		}
+	}
+}
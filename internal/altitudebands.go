@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// altitudeBandWidth is the height of each altitude band in [feet], the last band is open.
+	altitudeBandWidth = 10000
+	altitudeBandCount = 4
+
+	// altitudeBarWidth is the number of characters of the longest histogram bar.
+	altitudeBarWidth = 20
+)
+
+// AltitudeBand is the count of aircraft within a range of altitudes.
+type AltitudeBand struct {
+	Label   string  // Label names the range, e.g. "10-20k".
+	Current int     // Current is how many aircraft of the latest poll are within the band.
+	Share   float64 // Share is the fraction of all airborne aircraft of all polls within the band.
+}
+
+// AltitudeBandStats counts the airborne aircraft of every poll by altitude band, to show the mix of
+// local traffic: approaches and departures below, overflights above.
+// Aircraft on the ground or without a known altitude aren't counted.
+type AltitudeBandStats struct {
+	current [altitudeBandCount]int
+	total   [altitudeBandCount]int
+	polls   int
+}
+
+// NewAltitudeBandStats creates empty altitude band statistics.
+func NewAltitudeBandStats() *AltitudeBandStats {
+	return &AltitudeBandStats{
+		current: [altitudeBandCount]int{},
+		total:   [altitudeBandCount]int{},
+		polls:   0,
+	}
+}
+
+// altitudeBandIndex returns the band of the given altitude in [feet].
+func altitudeBandIndex(feet float64) int {
+	return min(altitudeBandCount-1, max(0, int(feet/altitudeBandWidth)))
+}
+
+// altitudeBandLabel names the band of the given index, e.g. "0-10k" or "30k+".
+func altitudeBandLabel(idx int) string {
+	lower := idx * altitudeBandWidth / 1000
+	if idx == altitudeBandCount-1 {
+		return fmt.Sprintf("%dk+", lower)
+	}
+	return fmt.Sprintf("%d-%dk", lower, lower+altitudeBandWidth/1000)
+}
+
+// Record counts the airborne aircraft of a poll by altitude band.
+func (abs *AltitudeBandStats) Record(aircraft []AircraftRecord) {
+	abs.current = [altitudeBandCount]int{}
+	for idx := range aircraft {
+		feet, ok := aircraft[idx].AltBaro.Feet()
+		if !ok {
+			continue
+		}
+		abs.current[altitudeBandIndex(feet)]++
+	}
+	for idx, count := range abs.current {
+		abs.total[idx] += count
+	}
+	abs.polls++
+}
+
+// Bands returns the counts of all bands, lowest first.
+func (abs *AltitudeBandStats) Bands() []AltitudeBand {
+	sum := 0
+	for _, count := range abs.total {
+		sum += count
+	}
+
+	bands := make([]AltitudeBand, altitudeBandCount)
+	for idx := range bands {
+		bands[idx] = AltitudeBand{Label: altitudeBandLabel(idx), Current: abs.current[idx], Share: 0}
+		if sum > 0 {
+			bands[idx].Share = float64(abs.total[idx]) / float64(sum)
+		}
+	}
+	return bands
+}
+
+// AltitudeHistogram renders the share of each band as a horizontal bar, one line per band with
+// the highest band on top like in the sky, e.g. "10-20k ██████     3  25%".
+func AltitudeHistogram(bands []AltitudeBand) []string {
+	highest := 0.0
+	for _, band := range bands {
+		highest = max(highest, band.Share)
+	}
+
+	lines := make([]string, 0, len(bands))
+	for idx := len(bands) - 1; idx >= 0; idx-- {
+		band := bands[idx]
+		length := 0
+		if highest > 0 {
+			length = int(band.Share / highest * altitudeBarWidth)
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%-6s %-*s %3d %3.0f%%",
+			band.Label,
+			altitudeBarWidth,
+			strings.Repeat("█", length),
+			band.Current,
+			band.Share*100)) //nolint:mnd // percent
+	}
+	return lines
+}
+
+// WriteCSV writes the counts of all bands, lowest first, as CSV with a header row.
+func (abs *AltitudeBandStats) WriteCSV(out io.Writer) error {
+	bands := abs.Bands()
+	records := make([][]string, 0, len(bands))
+	for idx, band := range bands {
+		average := 0.0
+		if abs.polls > 0 {
+			average = float64(abs.total[idx]) / float64(abs.polls)
+		}
+		records = append(records, []string{
+			band.Label,
+			strconv.Itoa(band.Current),
+			strconv.FormatFloat(average, 'f', 1, 64),
+			strconv.FormatFloat(band.Share, 'f', 3, 64),
+		})
+	}
+	if err := writeCSV(out, []string{"band", "current", "avg_aircraft", "share"}, records); err != nil {
+		return fmt.Errorf("AltitudeBandStats.WriteCSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func altitudeRecords(altitudes ...Altitude) []AircraftRecord {
+	records := make([]AircraftRecord, len(altitudes))
+	for idx, altitude := range altitudes {
+		records[idx] = AircraftRecord{AltBaro: altitude} //nolint:exhaustruct // altitude only
+	}
+	return records
+}
+
+func TestAltitudeBandStats(t *testing.T) {
+	stats := NewAltitudeBandStats()
+	stats.Record(altitudeRecords(NewAltitude(3000), NewAltitude(35000), GroundAltitude(), Altitude{}))
+	stats.Record(altitudeRecords(NewAltitude(9999), NewAltitude(10000), NewAltitude(41000)))
+
+	expected := []AltitudeBand{
+		{Label: "0-10k", Current: 1, Share: 0.4},
+		{Label: "10-20k", Current: 1, Share: 0.2},
+		{Label: "20-30k", Current: 0, Share: 0},
+		{Label: "30k+", Current: 1, Share: 0.4},
+	}
+	bands := stats.Bands()
+	if len(bands) != len(expected) {
+		t.Fatalf("Bands() returned %d bands, expected %d", len(bands), len(expected))
+	}
+	for idx, band := range bands {
+		if band != expected[idx] {
+			t.Errorf("Bands()[%d] = %+v, expected %+v", idx, band, expected[idx])
+		}
+	}
+
+	lines := AltitudeHistogram(bands)
+	if !strings.HasPrefix(lines[0], "30k+") || !strings.HasSuffix(lines[0], "1  40%") {
+		t.Errorf("AltitudeHistogram()[0] = %q, expected the highest band on top", lines[0])
+	}
+
+	var out bytes.Buffer
+	if err := stats.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	csvLines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(csvLines) != 5 || csvLines[1] != "0-10k,1,1.0,0.400" {
+		t.Errorf("WriteCSV() = %q", out.String())
+	}
+}
+
+func TestAltitudeBandStatsEmpty(t *testing.T) {
+	for _, line := range AltitudeHistogram(NewAltitudeBandStats().Bands()) {
+		if !strings.HasSuffix(line, "0   0%") {
+			t.Errorf("AltitudeHistogram() line %q, expected no aircraft", line)
+		}
+	}
+}
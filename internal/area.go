@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// MovementArrival and MovementDeparture tell whether an aircraft entered or left a watch area.
+	MovementArrival   = "arrival"
+	MovementDeparture = "departure"
+
+	minAreaCorners = 3
+	maxLatitude    = 90
+	maxLongitude   = 180
+)
+
+var errInvalidArea = errors.New("invalid watch area")
+
+// AreaConfig defines a watch area, e.g. the ramp of an airport, as a polygon of [lat, lon]
+// corners:
+//
+//	{
+//	  "name": "ramp",
+//	  "polygon": [[53.6335, 9.9905], [53.6355, 9.9905], [53.6355, 9.9985], [53.6335, 9.9985]]
+//	}
+type AreaConfig struct {
+	Name    string      `json:"name"`
+	Polygon [][]float64 `json:"polygon"`
+}
+
+// WatchArea is an area in which arrivals and departures of aircraft are reported.
+type WatchArea struct {
+	name    string
+	corners []dash.Coordinates
+	inside  map[string]bool // inside maps the hex of every aircraft to whether it was last inside.
+}
+
+// AreaMovement is an aircraft which entered or left a watch area since the previous update.
+type AreaMovement struct {
+	Area     string // Area is the name of the watch area.
+	Movement string // Movement is MovementArrival or MovementDeparture.
+	Sighting *AircraftSighting
+}
+
+// compileAreas checks the corners of all configured watch areas.
+func compileAreas(areaConfigs []AreaConfig) ([]*WatchArea, error) {
+	areas := make([]*WatchArea, 0, len(areaConfigs))
+	names := make(map[string]bool, len(areaConfigs))
+	for _, areaConfig := range areaConfigs {
+		if areaConfig.Name == "" || names[areaConfig.Name] {
+			return nil, fmt.Errorf("compileAreas: %w: missing or duplicate name %q",
+				errInvalidArea, areaConfig.Name)
+		}
+		names[areaConfig.Name] = true
+
+		if len(areaConfig.Polygon) < minAreaCorners {
+			return nil, fmt.Errorf("compileAreas: %w: %s needs at least %d corners",
+				errInvalidArea, areaConfig.Name, minAreaCorners)
+		}
+		corners := make([]dash.Coordinates, 0, len(areaConfig.Polygon))
+		for _, corner := range areaConfig.Polygon {
+			if !isValidCorner(corner) {
+				return nil, fmt.Errorf("compileAreas: %w: %s has corner %v, expected [lat, lon]",
+					errInvalidArea, areaConfig.Name, corner)
+			}
+			corners = append(corners, dash.NewCoordinates(corner[0], corner[1]))
+		}
+
+		areas = append(areas, &WatchArea{
+			name:    areaConfig.Name,
+			corners: corners,
+			inside:  make(map[string]bool),
+		})
+	}
+	return areas, nil
+}
+
+func isValidCorner(corner []float64) bool {
+	return len(corner) == 2 &&
+		corner[0] >= -maxLatitude && corner[0] <= maxLatitude &&
+		corner[1] >= -maxLongitude && corner[1] <= maxLongitude
+}
+
+// Contains tells whether the position lies within the area. Latitude and longitude are treated as
+// plane coordinates, which is exact enough for areas the size of an airport.
+func (area *WatchArea) Contains(pos dash.Coordinates) bool {
+	return polygonContains(area.corners, pos)
+}
+
+// polygonContains tells whether the position lies within the polygon of the given corners, by
+// counting how often a ray from the position crosses its edges.
+func polygonContains(corners []dash.Coordinates, pos dash.Coordinates) bool {
+	contains := false
+	prev := corners[len(corners)-1]
+	for _, corner := range corners {
+		if (corner.Latitude > pos.Latitude) != (prev.Latitude > pos.Latitude) {
+			crossing := corner.Longitude + (pos.Latitude-corner.Latitude)*
+				(prev.Longitude-corner.Longitude)/(prev.Latitude-corner.Latitude)
+			if pos.Longitude < crossing {
+				contains = !contains
+			}
+		}
+		prev = corner
+	}
+	return contains
+}
+
+// update remembers whether the aircraft is inside the area and reports if it entered or left.
+// The first position of an aircraft is no movement, so that aircraft which are already inside on
+// startup or which switch on their transponder inside aren't reported as arrivals. Aircraft which
+// disappear inside keep being counted as inside, since it can't be told whether they left.
+func (area *WatchArea) update(hex string, pos dash.Coordinates, sighting *AircraftSighting) (AreaMovement, bool) {
+	isInside := area.Contains(pos)
+	wasInside, known := area.inside[hex]
+	area.inside[hex] = isInside
+	if !known || wasInside == isInside {
+		return AreaMovement{}, false
+	}
+
+	movement := MovementDeparture
+	if isInside {
+		movement = MovementArrival
+	}
+	return AreaMovement{Area: area.name, Movement: movement, Sighting: sighting}, true
+}
+
+// updateAreas reports the watch areas the aircraft entered or left. Aircraft without a live
+// position are left out until they report one again.
+func (db *Dashboard) updateAreas(sighting *AircraftSighting, aircraft *AircraftRecord) []AreaMovement {
+	position, ok := aircraft.KnownPosition()
+	if !ok || position.Stale {
+		return nil
+	}
+
+	var movements []AreaMovement
+	pos := dash.NewCoordinates(position.Lat, position.Lon)
+	for _, area := range db.watchAreas {
+		if movement, moved := area.update(aircraft.Hex, pos, sighting); moved {
+			movements = append(movements, movement)
+		}
+	}
+	return movements
+}
+
+// areaMovementEvent describes the movement, e.g. "DLH400 arrived at ramp".
+func areaMovementEvent(lang i18n.Language, movement AreaMovement, now time.Time) Event {
+	sighting := movement.Sighting
+	title := lang.Sprintf("Arrival at %s", movement.Area)
+	description := lang.Sprintf("%s arrived at %s", sighting.lastFlightNo, movement.Area)
+	if movement.Movement == MovementDeparture {
+		title = lang.Sprintf("Departure from %s", movement.Area)
+		description = lang.Sprintf("%s left %s", sighting.lastFlightNo, movement.Area)
+	}
+	return Event{
+		Kind:     EventKindArea,
+		Severity: SeverityInfo,
+		Title:    title,
+		Body:     fmt.Sprintf("%s\n%s (%s)", description, sighting.typeDesc, sighting.registration),
+		Summary:  fmt.Sprintf("%s %s: %s", lang.T(movement.Movement), movement.Area, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: &movement,
+	}
+}
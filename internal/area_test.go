@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+var testRamp = AreaConfig{ //nolint:gochecknoglobals // test fixture
+	Name:    "ramp",
+	Polygon: [][]float64{{53.0, 10.0}, {54.0, 10.0}, {54.0, 11.0}, {53.0, 11.0}},
+}
+
+func TestWatchAreaContains(t *testing.T) {
+	areas, err := compileAreas([]AreaConfig{testRamp})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		pos      dash.Coordinates
+		expected bool
+	}{
+		{name: "inside", pos: dash.NewCoordinates(53.5, 10.5), expected: true},
+		{name: "north", pos: dash.NewCoordinates(54.5, 10.5), expected: false},
+		{name: "west", pos: dash.NewCoordinates(53.5, 9.5), expected: false},
+		{name: "east", pos: dash.NewCoordinates(53.5, 11.5), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if contains := areas[0].Contains(tt.pos); contains != tt.expected {
+				t.Errorf("Contains(%v) = %v, expected %v", tt.pos, contains, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWatchAreaMovements(t *testing.T) {
+	areas, err := compileAreas([]AreaConfig{testRamp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	area := areas[0]
+	sighting := &AircraftSighting{} //nolint:exhaustruct // not reported on
+
+	steps := []struct {
+		pos      dash.Coordinates
+		expected string // expected movement, empty if none
+	}{
+		{pos: dash.NewCoordinates(53.5, 10.5), expected: ""}, // first position, already inside
+		{pos: dash.NewCoordinates(53.6, 10.5), expected: ""},
+		{pos: dash.NewCoordinates(55.0, 10.5), expected: MovementDeparture},
+		{pos: dash.NewCoordinates(55.1, 10.5), expected: ""},
+		{pos: dash.NewCoordinates(53.5, 10.5), expected: MovementArrival},
+	}
+
+	for idx, step := range steps {
+		movement, moved := area.update("3c6444", step.pos, sighting)
+		if step.expected == "" {
+			if moved {
+				t.Errorf("step %d: unexpected %s", idx, movement.Movement)
+			}
+			continue
+		}
+		if !moved || movement.Movement != step.expected || movement.Area != "ramp" {
+			t.Errorf("step %d: update() = %+v, %v, expected %s", idx, movement, moved, step.expected)
+		}
+	}
+}
+
+func TestCompileAreasInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		areas []AreaConfig
+	}{
+		{name: "no name", areas: []AreaConfig{{Name: "", Polygon: testRamp.Polygon}}},
+		{name: "duplicate name", areas: []AreaConfig{testRamp, testRamp}},
+		{name: "too few corners", areas: []AreaConfig{{Name: "line", Polygon: [][]float64{{53, 10}, {54, 10}}}}},
+		{
+			name:  "not a coordinate",
+			areas: []AreaConfig{{Name: "far", Polygon: [][]float64{{53, 10}, {54, 10}, {91, 10}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileAreas(tt.areas); !errors.Is(err, errInvalidArea) {
+				t.Errorf("compileAreas() error = %v, expected %v", err, errInvalidArea)
+			}
+		})
+	}
+}
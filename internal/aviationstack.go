@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	feetPerMeter  = 3.28084
+	kmPerNautical = 1.852
+
+	// aviationstackMaxPages caps the pages of live flights requested per poll, since every page
+	// counts against the request quota. With the 100 flights per page of the smaller plans, that's
+	// the first 2000 of usually several thousand live flights.
+	aviationstackMaxPages = 20
+)
+
+// aviationstackResult mirrors the JSON which is returned by the flights endpoint of aviationstack.
+// See https://aviationstack.com/documentation for the full set of fields.
+type aviationstackResult struct {
+	Pagination aviationstackPagination `json:"pagination"`
+	Data       []aviationstackFlight   `json:"data"`
+}
+
+// aviationstackPagination tells which page of the results a response holds.
+type aviationstackPagination struct {
+	Limit  int `json:"limit"`  // Limit is the most flights a page holds, which depends on the plan.
+	Offset int `json:"offset"` // Offset is the index of the first flight of the page.
+	Count  int `json:"count"`  // Count is how many flights the page holds.
+	Total  int `json:"total"`  // Total is how many flights there are over all pages.
+}
+
+type aviationstackFlight struct {
+	Airline struct {
+		Name string `json:"name"`
+	} `json:"airline"`
+	Flight struct {
+		Icao string `json:"icao"`
+	} `json:"flight"`
+	Aircraft *struct {
+		Registration string `json:"registration"`
+		Icao         string `json:"icao"`
+		Icao24       string `json:"icao24"`
+	} `json:"aircraft"`
+	Live *struct {
+		Updated         time.Time `json:"updated"`
+		Latitude        float64   `json:"latitude"`
+		Longitude       float64   `json:"longitude"`
+		Altitude        float64   `json:"altitude"`         // altitude in [m]
+		Direction       float64   `json:"direction"`        // track in [degrees]
+		SpeedHorizontal float64   `json:"speed_horizontal"` // ground speed in [km/h]
+		IsGround        bool      `json:"is_ground"`
+	} `json:"live"`
+}
+
+// parseAviationstackAircraft converts a page of the live flights of aviationstack into aircraft
+// records and returns which page it was.
+// aviationstack can't be asked for a particular area, so flights without live position or
+// further away than the other sources look are left out, as are those the filter doesn't keep.
+func parseAviationstackAircraft(
+	body io.Reader,
+	opts RequestOptions,
+	keep recordFilter,
+	now time.Time,
+) ([]AircraftRecord, aviationstackPagination, error) {
+	var data aviationstackResult
+	if err := json.NewDecoder(body).Decode(&data); err != nil {
+		return nil, data.Pagination, fmt.Errorf("parseAviationstackAircraft: failed to unmarshal Json: %w", err)
+	}
+
+	circles := opts.queryCircles()
+
+	var aircraft []AircraftRecord
+	for _, flight := range data.Data {
+		if flight.Live == nil || flight.Aircraft == nil || flight.Aircraft.Icao24 == "" {
+			continue
+		}
+		live := flight.Live
+		if !slices.ContainsFunc(circles, func(circle queryCircle) bool {
+			return circle.contains(live.Latitude, live.Longitude)
+		}) {
+			continue
+		}
+
+		altitude := NewAltitude(live.Altitude * feetPerMeter)
+		if live.IsGround {
+			altitude = GroundAltitude()
+		}
+
+		record := AircraftRecord{ //nolint:exhaustruct // aviationstack has less data
+			Hex:          strings.ToLower(flight.Aircraft.Icao24),
+			Flight:       flight.Flight.Icao,
+			Registration: flight.Aircraft.Registration,
+			IcaoType:     flight.Aircraft.Icao,
+			OwnOp:        flight.Airline.Name,
+			Lat:          live.Latitude,
+			Lon:          live.Longitude,
+			AltBaro:      altitude,
+			GroundSpeed:  live.SpeedHorizontal / kmPerNautical,
+			Track:        live.Direction,
+			SeenPos:      now.Sub(live.Updated).Seconds(),
+		}
+		if keep == nil || keep(&record) {
+			aircraft = append(aircraft, record)
+		}
+	}
+	return aircraft, data.Pagination, nil
+}
+
+// aviationstackNextPage returns the URL of the page which follows the given page of the request,
+// empty if it was the last one or aviationstackMaxPages have been requested.
+func aviationstackNextPage(reqURL string, page aviationstackPagination) (string, error) {
+	next := page.Offset + page.Count
+	if page.Count == 0 || next >= page.Total || next >= aviationstackMaxPages*page.Limit {
+		return "", nil
+	}
+
+	// The URL carries the access key, so it must not end up in the error.
+	nextURL, parseErr := url.Parse(reqURL)
+	if parseErr != nil {
+		return "", fmt.Errorf("aviationstackNextPage: %w", ErrInvalidURL)
+	}
+	query := nextURL.Query()
+	query.Set("offset", strconv.Itoa(next))
+	nextURL.RawQuery = query.Encode()
+	return nextURL.String(), nil
+}
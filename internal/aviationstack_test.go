@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseAviationstackAircraft(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 30, 0, time.UTC)
+	body := []byte(`{"data": [
+		{
+			"airline": {"name": "Lufthansa"},
+			"flight": {"icao": "DLH400"},
+			"aircraft": {"registration": "D-ABYA", "icao": "B748", "icao24": "3C4B21"},
+			"live": {
+				"updated": "2025-06-01T12:00:00+00:00",
+				"latitude": 53.6,
+				"longitude": 10.0,
+				"altitude": 1000,
+				"direction": 90,
+				"speed_horizontal": 926,
+				"is_ground": false
+			}
+		},
+		{
+			"flight": {"icao": "UAL1"},
+			"aircraft": {"registration": "N12345", "icao": "B789", "icao24": "A00001"},
+			"live": {"updated": "2025-06-01T12:00:00+00:00", "latitude": 40.7, "longitude": -74.0}
+		},
+		{
+			"flight": {"icao": "SCHEDULED"},
+			"aircraft": null,
+			"live": null
+		}
+	]}`)
+	opts := testRequestOptions(53.55, 9.99)
+
+	aircraft, _, err := parseAviationstackAircraft(bytes.NewReader(body), opts, nil, now)
+	if err != nil {
+		t.Fatalf("parseAviationstackAircraft() error = %v", err)
+	}
+	if len(aircraft) != 1 {
+		t.Fatalf("parseAviationstackAircraft() returned %d aircraft, expected only the nearby one", len(aircraft))
+	}
+
+	record := aircraft[0]
+	if record.Hex != "3c4b21" || record.Flight != "DLH400" || record.OwnOp != "Lufthansa" {
+		t.Errorf("parseAviationstackAircraft() = %+v, expected DLH400 of Lufthansa", record)
+	}
+	if altitude, ok := record.AltBaro.Feet(); !ok || altitude < 3280 || altitude > 3281 {
+		t.Errorf("AltBaro = %v, expected about 3281 ft", record.AltBaro)
+	}
+	if record.GroundSpeed != 500 || record.SeenPos != 30 {
+		t.Errorf("GroundSpeed = %f, SeenPos = %f, expected 500 kt and 30 s", record.GroundSpeed, record.SeenPos)
+	}
+}
+
+func TestNewAircraftSourceAuthentication(t *testing.T) {
+	opts := testRequestOptions(53.55, 9.99)
+	for _, source := range []string{SourceAdsbExchange, SourceAviationstack} {
+		if _, err := newAircraftSource(source, opts); err == nil {
+			t.Errorf("newAircraftSource(%s) accepted missing API key", source)
+		}
+	}
+
+	opts.APIKeys = map[string]string{SourceAdsbExchange: "secret", SourceAviationstack: "secret"}
+	adsbx, err := newAircraftSource(SourceAdsbExchange, opts)
+	if err != nil {
+		t.Fatalf("newAircraftSource(%s) error = %v", SourceAdsbExchange, err)
+	}
+	if adsbx.headers["X-RapidAPI-Key"] != "secret" || adsbx.headers["X-RapidAPI-Host"] != adsbExchangeReqHost {
+		t.Errorf("newAircraftSource(%s) headers = %v", SourceAdsbExchange, adsbx.headers)
+	}
+
+	aviationstack, err := newAircraftSource(SourceAviationstack, opts)
+	if err != nil {
+		t.Fatalf("newAircraftSource(%s) error = %v", SourceAviationstack, err)
+	}
+	expectedURL := "https://api.aviationstack.com/v1/flights?access_key=secret&flight_status=active"
+	if len(aviationstack.reqURLs) != 1 || aviationstack.reqURLs[0] != expectedURL {
+		t.Errorf("newAircraftSource(%s) URLs = %s, expected %s", SourceAviationstack, aviationstack.reqURLs, expectedURL)
+	}
+}
+
+func TestAviationstackNextPage(t *testing.T) {
+	reqURL := "https://api.aviationstack.com/v1/flights?access_key=secret&flight_status=active"
+	tests := []struct {
+		name     string
+		page     aviationstackPagination
+		expected string
+	}{
+		{
+			name:     "first page",
+			page:     aviationstackPagination{Limit: 100, Offset: 0, Count: 100, Total: 250},
+			expected: reqURL + "&offset=100",
+		},
+		{
+			name:     "last page",
+			page:     aviationstackPagination{Limit: 100, Offset: 200, Count: 50, Total: 250},
+			expected: "",
+		},
+		{
+			name:     "empty page",
+			page:     aviationstackPagination{Limit: 100, Offset: 0, Count: 0, Total: 250},
+			expected: "",
+		},
+		{
+			name:     "page cap",
+			page:     aviationstackPagination{Limit: 100, Offset: 1900, Count: 100, Total: 5000},
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, err := aviationstackNextPage(reqURL, test.page)
+			if err != nil {
+				t.Fatalf("aviationstackNextPage() error = %v", err)
+			}
+			if next != test.expected {
+				t.Errorf("aviationstackNextPage() = %q, expected %q", next, test.expected)
+			}
+		})
+	}
+}
+
+func TestRequestAviationstackPages(t *testing.T) {
+	var offsets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset")) // the first page has no offset
+		offsets = append(offsets, offset)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"pagination": {"limit": 1, "offset": %d, "count": 1, "total": 2}, "data": [{
+			"aircraft": {"icao24": "3c4b2%d"},
+			"live": {"updated": "2025-06-01T12:00:00+00:00", "latitude": 53.6, "longitude": 10.0}
+		}]}`, offset, offset)
+	}))
+	t.Cleanup(server.Close)
+
+	opts := testRequestOptions(53.55, 9.99)
+	opts.Sources = []string{SourceLocal}
+	opts.LocalURL = server.URL
+	opts.APIKeys = map[string]string{SourceAviationstack: "secret"}
+	var stderr io.Writer = io.Discard
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	source, err := newAircraftSource(SourceAviationstack, opts)
+	if err != nil {
+		t.Fatalf("newAircraftSource() error = %v", err)
+	}
+	source.reqURLs = []string{server.URL + "/v1/flights?access_key=secret"}
+
+	aircraft, err := request.requestAircraftFromSource(source)
+	if err != nil {
+		t.Fatalf("requestAircraftFromSource() error = %v", err)
+	}
+	if len(aircraft) != 2 || !slices.Equal(offsets, []int{0, 1}) {
+		t.Errorf("requestAircraftFromSource() = %+v from offsets %v, expected both pages", aircraft, offsets)
+	}
+}
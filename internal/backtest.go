@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal/rules"
+)
+
+// DefaultBacktestPeriod is how far back a backtest looks into the sighting history by default.
+const DefaultBacktestPeriod = 30 * 24 * time.Hour
+
+// BacktestResult tells which sightings of the history a rule or a watchlist would have fired for.
+type BacktestResult struct {
+	Name      string         // Name describes what was backtested, e.g. the condition of the rule.
+	Sightings int            // Sightings is how many sightings of the period were checked.
+	Matches   []HistoryEntry // Matches are the sightings it would have fired for, oldest first.
+}
+
+// Days tells on how many days, of the given spotting days, there were matches.
+func (result BacktestResult) Days(day SpottingDay) int {
+	days := make(map[time.Time]bool)
+	for _, entry := range result.Matches {
+		days[day.Start(entry.Time.Local())] = true
+	}
+	return len(days)
+}
+
+// BacktestRule replays the sightings of the history since the given time against the condition of
+// an alert rule. Like live rules, it fires once per flight, and every entry of the history is a
+// flight. The history only records the hex, flight, registration, model, operator and country of
+// sightings, so comparisons of other fields, e.g. altitude, never match.
+func BacktestRule(entries []HistoryEntry, condition string, since time.Time) (BacktestResult, error) {
+	expr, err := rules.Parse(condition)
+	if err != nil {
+		return BacktestResult{}, fmt.Errorf("BacktestRule: %w", err)
+	}
+	return backtest("rule "+condition, entries, since, func(entry HistoryEntry) bool {
+		return expr.Eval(historyFields(entry))
+	}), nil
+}
+
+// BacktestWatchlist replays the sightings of the history since the given time against a
+// watchlist of hexes, to see how often the aircraft on it would have been reported.
+func BacktestWatchlist(entries []HistoryEntry, hexes []string, since time.Time) BacktestResult {
+	watched := make([]string, len(hexes))
+	for idx, hex := range hexes {
+		watched[idx] = strings.ToLower(strings.TrimSpace(hex))
+	}
+	name := "watchlist " + strings.Join(watched, ", ")
+	return backtest(name, entries, since, func(entry HistoryEntry) bool {
+		return slices.Contains(watched, strings.ToLower(entry.Hex))
+	})
+}
+
+func backtest(
+	name string,
+	entries []HistoryEntry,
+	since time.Time,
+	matches func(entry HistoryEntry) bool,
+) BacktestResult {
+	result := BacktestResult{Name: name, Sightings: 0, Matches: nil}
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		result.Sightings++
+		if matches(entry) {
+			result.Matches = append(result.Matches, entry)
+		}
+	}
+	slices.SortStableFunc(result.Matches, func(a, b HistoryEntry) int { return a.Time.Compare(b.Time) })
+	return result
+}
+
+// historyFields collects the fields of a sighting of the history which rule conditions can refer
+// to, with the same names as for live aircraft.
+func historyFields(entry HistoryEntry) rules.Fields {
+	return rules.Fields{
+		"hex":          entry.Hex,
+		"flight":       entry.Flight,
+		"registration": entry.Registration,
+		"model":        entry.Type,
+		"operator":     entry.Operator,
+		"country":      entry.Country,
+	}
+}
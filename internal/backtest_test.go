@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func testBacktestHistory() []HistoryEntry {
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.Local)
+	entry := func(hours int, hex string, operator string) HistoryEntry {
+		return HistoryEntry{
+			Time:         start.Add(time.Duration(hours) * time.Hour),
+			Hex:          hex,
+			Flight:       "",
+			Registration: "",
+			Type:         "BOEING 747-8",
+			Operator:     operator,
+			Country:      "GERMANY",
+		}
+	}
+	return []HistoryEntry{
+		entry(50, "3c4b26", "Lufthansa"),
+		entry(0, "3c6444", "Lufthansa"),
+		entry(1, "4ca123", "Ryanair"),
+		entry(26, "3C6444", "Lufthansa Cargo"),
+	}
+}
+
+func TestBacktestRule(t *testing.T) {
+	entries := testBacktestHistory()
+	since := entries[1].Time
+
+	tests := []struct {
+		name        string
+		condition   string
+		since       time.Time
+		wantMatches int
+		wantDays    int
+	}{
+		{"operator", `operator contains "lufthansa"`, since, 3, 3},
+		{"since", `operator contains "lufthansa"`, since.Add(2 * time.Hour), 2, 2},
+		{"unrecorded field", "altitude < 3000", since, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BacktestRule(entries, tt.condition, tt.since)
+			if err != nil {
+				t.Fatalf("BacktestRule() error = %v", err)
+			}
+			if len(result.Matches) != tt.wantMatches || result.Days(SpottingDay{}) != tt.wantDays {
+				t.Errorf("BacktestRule() = %d matches on %d days, expected %d on %d",
+					len(result.Matches), result.Days(SpottingDay{}), tt.wantMatches, tt.wantDays)
+			}
+			for idx := 1; idx < len(result.Matches); idx++ {
+				if result.Matches[idx].Time.Before(result.Matches[idx-1].Time) {
+					t.Errorf("BacktestRule() matches aren't sorted: %v", result.Matches)
+				}
+			}
+		})
+	}
+
+	if _, err := BacktestRule(entries, "operator ==", since); err == nil {
+		t.Error("BacktestRule() accepted an invalid condition")
+	}
+}
+
+func TestBacktestWatchlist(t *testing.T) {
+	result := BacktestWatchlist(testBacktestHistory(), []string{" 3c6444"}, time.Time{})
+	if result.Sightings != 4 || len(result.Matches) != 2 {
+		t.Errorf("BacktestWatchlist() = %d of %d sightings, expected 2 of 4",
+			len(result.Matches), result.Sightings)
+	}
+	if result.Name != "watchlist 3c6444" {
+		t.Errorf("BacktestWatchlist() name = %q", result.Name)
+	}
+}
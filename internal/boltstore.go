@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// boltOpenTimeout is how long to wait for another airspottr to release the database, which only
+// a single process can have open.
+const boltOpenTimeout = time.Second
+
+//nolint:gochecknoglobals // constant, but Go can't have constant byte slices
+var (
+	boltSightingsBucket     = []byte("sightings")
+	boltNotificationsBucket = []byte("notifications")
+)
+
+// BoltStore keeps the sightings in a BoltDB database, as JSON by the order they were appended in.
+type BoltStore struct {
+	db        *bbolt.DB
+	mutex     sync.Mutex
+	appendErr error // appendErr is the error of the most recent append, nil if it succeeded.
+}
+
+// OpenBoltStore opens the BoltDB database at the given path, creating it if necessary.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	//nolint:exhaustruct // defaults
+	db, openErr := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: boltOpenTimeout})
+	if openErr != nil {
+		return nil, fmt.Errorf("OpenBoltStore: failed to open %s: %w", path, openErr)
+	}
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		if _, bucketErr := tx.CreateBucketIfNotExists(boltSightingsBucket); bucketErr != nil {
+			return bucketErr //nolint:wrapcheck // wrapped below
+		}
+		_, bucketErr := tx.CreateBucketIfNotExists(boltNotificationsBucket)
+		return bucketErr //nolint:wrapcheck // wrapped below
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("OpenBoltStore: failed to create buckets: %w", err)
+	}
+
+	return &BoltStore{db: db, mutex: sync.Mutex{}, appendErr: nil}, nil
+}
+
+// Append adds the sightings in a single transaction, so that they are either all stored or none.
+func (s *BoltStore) Append(entries []HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(boltSightingsBucket)
+		for _, entry := range entries {
+			entry.Time = entry.Time.UTC()
+			value, marshalErr := json.Marshal(entry)
+			if marshalErr != nil {
+				return fmt.Errorf("failed to encode entry: %w", marshalErr)
+			}
+			sequence, seqErr := bucket.NextSequence()
+			if seqErr != nil {
+				return seqErr //nolint:wrapcheck // wrapped below
+			}
+			if putErr := bucket.Put(binary.BigEndian.AppendUint64(nil, sequence), value); putErr != nil {
+				return putErr //nolint:wrapcheck // wrapped below
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("BoltStore.Append: %w", err)
+	}
+
+	s.mutex.Lock()
+	s.appendErr = err
+	s.mutex.Unlock()
+	return err
+}
+
+// Load returns all sightings, in the order they were appended in.
+func (s *BoltStore) Load() ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltSightingsBucket).ForEach(func(_ []byte, value []byte) error {
+			var entry HistoryEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return fmt.Errorf("failed to decode entry: %w", err)
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return entries, fmt.Errorf("BoltStore.Load: %w", err)
+	}
+	return entries, nil
+}
+
+// AppendNotification adds the notification to its own bucket.
+func (s *BoltStore) AppendNotification(entry NotificationEntry) error {
+	value, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return fmt.Errorf("BoltStore.AppendNotification: failed to encode entry: %w", marshalErr)
+	}
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(boltNotificationsBucket)
+		sequence, seqErr := bucket.NextSequence()
+		if seqErr != nil {
+			return seqErr //nolint:wrapcheck // wrapped below
+		}
+		return bucket.Put(binary.BigEndian.AppendUint64(nil, sequence), value) //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return fmt.Errorf("BoltStore.AppendNotification: %w", err)
+	}
+	return nil
+}
+
+// LoadNotifications returns the last notifications, oldest first, at most limit of them. Entries
+// which can't be decoded are skipped and counted.
+func (s *BoltStore) LoadNotifications(limit int) ([]NotificationEntry, int, error) {
+	var entries []NotificationEntry
+	skipped := 0
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(boltNotificationsBucket).Cursor()
+		for key, value := cursor.Last(); key != nil && len(entries) < limit; key, value = cursor.Prev() {
+			var entry NotificationEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				skipped++
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, skipped, fmt.Errorf("BoltStore.LoadNotifications: %w", err)
+	}
+	slices.Reverse(entries)
+	return entries, skipped, nil
+}
+
+// Check tells whether the database can be written to, which it can unless the most recent append
+// failed. It is locked for this process as long as it is open.
+func (s *BoltStore) Check() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.appendErr
+}
+
+// Close closes the database, which releases it to other processes.
+func (s *BoltStore) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("BoltStore.Close: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+const (
+	iataDesignatorLen = 2
+	iataMaxFlightNo   = 4
+)
+
+// splitCallsign splits the callsign into the designator of its operator and the flight number, at
+// the first digit: "DLH9TK" into "DLH" and "9TK", "LH400" into "LH" and "400". Callsigns without a
+// flight number, e.g. registrations like "DEABC", are all designator.
+func splitCallsign(callsign string) (string, string) {
+	callsign = strings.ToUpper(strings.TrimSpace(callsign))
+	idx := strings.IndexFunc(callsign, unicode.IsDigit)
+	if idx < 0 {
+		return callsign, ""
+	}
+	return callsign[:idx], callsign[idx:]
+}
+
+// iataDesignator returns the IATA code at the start of the callsign, if it is followed by an IATA
+// flight number of up to four digits and an optional letter, e.g. "LH" of "LH400" or "U2" of
+// "U21234". IATA codes may contain a digit, so they can't be split off at the first digit.
+func iataDesignator(callsign string) (string, bool) {
+	callsign = strings.ToUpper(strings.TrimSpace(callsign))
+	if len(callsign) <= iataDesignatorLen {
+		return "", false
+	}
+	designator, flightNo := callsign[:iataDesignatorLen], callsign[iataDesignatorLen:]
+
+	digits := strings.TrimRightFunc(flightNo, unicode.IsLetter)
+	if len(flightNo)-len(digits) > 1 || len(digits) == 0 || len(digits) > iataMaxFlightNo {
+		return "", false
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return "", false
+		}
+	}
+	return designator, true
+}
+
+// lookupAirline finds the airline of the callsign by its ICAO designator, e.g. "DLH" of "DLH400",
+// or otherwise by its IATA code, e.g. "LH" of "LH400", which some feeds report instead. The
+// confidence is exact for ICAO designators and heuristic for IATA codes, or none if neither is
+// known.
+func (db *Dashboard) lookupAirline(callsign string) (dash.IcaoOperator, Confidence) {
+	designator, _ := splitCallsign(callsign)
+	if airline, exists := db.IcaoToAirline[designator]; exists {
+		return airline, ConfidenceExact
+	}
+
+	iata, ok := iataDesignator(callsign)
+	if !ok {
+		return dash.IcaoOperator{}, ConfidenceNone
+	}
+	icao, exists := db.iataToIcaoAirline[iata]
+	if !exists {
+		return dash.IcaoOperator{}, ConfidenceNone
+	}
+	if airline, exists := db.IcaoToAirline[icao]; exists {
+		return airline, ConfidenceHeuristic
+	}
+	return dash.IcaoOperator{}, ConfidenceNone
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+func TestSplitCallsign(t *testing.T) {
+	tests := []struct {
+		callsign       string
+		wantDesignator string
+		wantFlightNo   string
+	}{
+		{"DLH400  ", "DLH", "400"},
+		{"DLH9TK", "DLH", "9TK"},
+		{"lh400", "LH", "400"},
+		{"U21234", "U", "21234"},
+		{"DEABC", "DEABC", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		designator, flightNo := splitCallsign(tt.callsign)
+		if designator != tt.wantDesignator || flightNo != tt.wantFlightNo {
+			t.Errorf("splitCallsign(%q) = %q, %q, expected %q, %q",
+				tt.callsign, designator, flightNo, tt.wantDesignator, tt.wantFlightNo)
+		}
+	}
+}
+
+func TestLookupAirline(t *testing.T) {
+	lufthansa := dash.IcaoOperator{Company: "DEUTSCHE LUFTHANSA", Country: "GERMANY"}
+	easyJet := dash.IcaoOperator{Company: "EASYJET UK LTD", Country: "UNITED KINGDOM"}
+	db := &Dashboard{ //nolint:exhaustruct // airline datasets only
+		IcaoToAirline:     map[string]dash.IcaoOperator{"DLH": lufthansa, "EZY": easyJet},
+		iataToIcaoAirline: map[string]string{"LH": "DLH", "U2": "EZY", "BA": "BAW"},
+	}
+
+	tests := []struct {
+		name     string
+		callsign string
+		want     dash.IcaoOperator
+		wantConf Confidence
+	}{
+		{"icao", "DLH400", lufthansa, ConfidenceExact},
+		{"icao with alphanumeric suffix", "DLH9TK", lufthansa, ConfidenceExact},
+		{"iata", "LH400", lufthansa, ConfidenceHeuristic},
+		{"iata with letter suffix", "LH400A", lufthansa, ConfidenceHeuristic},
+		{"iata with digit", "U21234", easyJet, ConfidenceHeuristic},
+		{"iata flight number too long", "LH40000", dash.IcaoOperator{}, ConfidenceNone},
+		{"iata with alphanumeric suffix", "LH4AB", dash.IcaoOperator{}, ConfidenceNone},
+		{"iata of unknown airline", "BA123", dash.IcaoOperator{}, ConfidenceNone},
+		{"registration", "N123AB", dash.IcaoOperator{}, ConfidenceNone},
+		{"empty", "", dash.IcaoOperator{}, ConfidenceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := db.lookupAirline(tt.callsign)
+			if got != tt.want || confidence != tt.wantConf {
+				t.Errorf("lookupAirline(%q) = %+v, %v, expected %+v, %v",
+					tt.callsign, got, confidence, tt.want, tt.wantConf)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"math"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// DefaultCirclingAlert is the distance in [km] within which circling aircraft are alerted.
+	DefaultCirclingAlert = 30.0
+
+	// circlingTurn is how far in [degrees] an aircraft has to turn in total, left or right, within
+	// circlingArea and circlingWindow to count as circling: one and a half orbits, or a lap and a
+	// half of a holding pattern, which an approach with a few turns doesn't add up to.
+	circlingTurn = 540.0
+	// circlingArea is how far in [km] an aircraft may get from where its turns began. A holding
+	// pattern with one minute legs fits into it.
+	circlingArea = 10.0
+	// circlingWindow is how long the turns may take in total.
+	circlingWindow = 15 * time.Minute
+	// circlingStep is how far in [degrees] the track has to change to count as a turn, so that the
+	// jitter of the reported track doesn't add up over many polls.
+	circlingStep = 15.0
+)
+
+// circlingState adds up the turns of an aircraft while it stays in a small area.
+type circlingState struct {
+	anchor  positionFix // anchor is where and when the turns began, zero before the first position.
+	track   float64     // track is the track in [degrees] the next turn is measured from.
+	turned  float64     // turned is how far the aircraft turned since the anchor in [degrees].
+	alerted bool        // alerted tells whether the current flight was alerted as circling.
+}
+
+// noCircling is the state of an aircraft which hasn't started turning.
+func noCircling() circlingState {
+	return circlingState{anchor: positionFix{lat: 0, lon: 0, time: time.Time{}}, track: 0, turned: 0, alerted: false}
+}
+
+// CirclingAlert is an aircraft flying circles or a holding pattern near our location.
+type CirclingAlert struct {
+	Turned   float64       // Turned is how far the aircraft turned in total in [degrees].
+	Duration time.Duration // Duration is how long the turns took.
+	Sighting *AircraftSighting
+}
+
+// checkCircling adds up the turns of an airborne aircraft and reports it once per flight when it
+// turned by circlingTurn within circlingArea and circlingWindow, as long as it is within the
+// circling alert distance. The turns start over whenever the aircraft leaves the area or the
+// window.
+func (db *Dashboard) checkCircling(
+	sighting *AircraftSighting,
+	aircraft *AircraftRecord,
+	now time.Time,
+) (CirclingAlert, bool) {
+	noAlert := CirclingAlert{Turned: 0, Duration: 0, Sighting: nil}
+	if db.circlingAlert <= 0 || sighting.circling.alerted || aircraft.AltBaro.IsGround() ||
+		aircraft.GroundSpeed <= 0 || (aircraft.Lat == 0 && aircraft.Lon == 0) {
+		return noAlert, false
+	}
+
+	state := &sighting.circling
+	here := positionFix{lat: aircraft.Lat, lon: aircraft.Lon, time: now}
+	left := dash.Distance(
+		dash.NewCoordinates(state.anchor.lat, state.anchor.lon),
+		dash.NewCoordinates(here.lat, here.lon)).Kilometers()
+	if state.anchor.time.IsZero() || left > circlingArea || now.Sub(state.anchor.time) > circlingWindow {
+		*state = circlingState{anchor: here, track: aircraft.Track, turned: 0, alerted: false}
+		return noAlert, false
+	}
+
+	if turn := math.Abs(trackChange(state.track, aircraft.Track)); turn >= circlingStep {
+		state.turned += turn
+		state.track = aircraft.Track
+	}
+	if state.turned < circlingTurn || aircraft.CachedDist > db.circlingAlert {
+		return noAlert, false
+	}
+	state.alerted = true
+	return CirclingAlert{Turned: state.turned, Duration: now.Sub(state.anchor.time), Sighting: sighting}, true
+}
+
+// trackChange returns the change from one track to another in [degrees], between -180 and 180,
+// positive for a right turn.
+func trackChange(from float64, to float64) float64 {
+	return math.Mod(to-from+540, 360) - 180 //nolint:mnd // degrees
+}
+
+// EmitCirclingAlerts sends an event for every aircraft circling near our location to all enabled
+// sinks.
+func (notify *Notify) EmitCirclingAlerts(alerts []CirclingAlert, now time.Time) {
+	for _, alert := range alerts {
+		notify.alert(circlingEvent(notify.language, alert, now), NotifyCircling)
+	}
+}
+
+func circlingEvent(lang i18n.Language, alert CirclingAlert, now time.Time) Event {
+	sighting := alert.Sighting
+	orbits := alert.Turned / 360 //nolint:mnd // degrees
+	msgBody := lang.Sprintf(
+		"%s %s (%s) circling for %.0f min\n%s",
+		sighting.lastFlightNo,
+		sighting.typeDesc,
+		sighting.registration,
+		alert.Duration.Minutes(),
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindCircling,
+		Severity: SeverityNotable,
+		Title:    lang.T("Circling aircraft"),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("circling %.1f times: %s", orbits, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
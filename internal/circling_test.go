@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+// circlingPoll is where an aircraft is and which way it flies on a poll.
+type circlingPoll struct {
+	lat   float64
+	track float64
+}
+
+// orbitPolls circles in place, turning by the given step on every poll.
+func orbitPolls(polls int, step float64) []circlingPoll {
+	path := make([]circlingPoll, polls)
+	for idx := range path {
+		path[idx] = circlingPoll{lat: 53.6, track: float64(idx) * step}
+	}
+	return path
+}
+
+func TestCheckCircling(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []circlingPoll
+		distance float64
+		ground   bool
+		alerts   int
+	}{
+		{name: "orbiting", path: orbitPolls(40, 30), distance: 5, ground: false, alerts: 1},
+		{name: "beyond the alert distance", path: orbitPolls(20, 30), distance: 50, ground: false, alerts: 0},
+		{name: "taxiing in circles", path: orbitPolls(20, 30), distance: 5, ground: true, alerts: 0},
+		{name: "jittery track", path: orbitPolls(40, 0.5), distance: 5, ground: false, alerts: 0},
+		{
+			name: "joining the approach",
+			path: []circlingPoll{
+				{lat: 53.6, track: 90}, {lat: 53.6, track: 180}, {lat: 53.6, track: 270}, {lat: 53.6, track: 0},
+				{lat: 53.6, track: 0}, {lat: 53.6, track: 0},
+			},
+			distance: 5,
+			ground:   false,
+			alerts:   0,
+		},
+		{
+			name: "zigzagging through",
+			path: []circlingPoll{
+				{lat: 53.6, track: 0}, {lat: 53.65, track: 90}, {lat: 53.7, track: 0}, {lat: 53.75, track: 270},
+				{lat: 53.8, track: 0}, {lat: 53.85, track: 90}, {lat: 53.9, track: 0}, {lat: 53.95, track: 270},
+				{lat: 54.0, track: 0},
+			},
+			distance: 5,
+			ground:   false,
+			alerts:   0,
+		},
+	}
+
+	db := &Dashboard{circlingAlert: DefaultCirclingAlert} //nolint:exhaustruct // only the alert distance
+	start := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sighting := &AircraftSighting{circling: noCircling()} //nolint:exhaustruct // turns only
+			altitude := NewAltitude(2000)
+			if test.ground {
+				altitude = GroundAltitude()
+			}
+			alerts := 0
+			for idx, poll := range test.path {
+				//nolint:exhaustruct // position and motion only
+				aircraft := &AircraftRecord{
+					Lat:         poll.lat,
+					Lon:         10.0,
+					Track:       poll.track,
+					GroundSpeed: 90,
+					AltBaro:     altitude,
+					CachedDist:  test.distance,
+				}
+				if _, ok := db.checkCircling(sighting, aircraft, start.Add(time.Duration(idx)*20*time.Second)); ok {
+					alerts++
+				}
+			}
+			if alerts != test.alerts {
+				t.Errorf("checkCircling() alerted %d times, expected %d", alerts, test.alerts)
+			}
+		})
+	}
+}
+
+func TestTrackChange(t *testing.T) {
+	tests := []struct {
+		from     float64
+		to       float64
+		expected float64
+	}{
+		{from: 10, to: 40, expected: 30},
+		{from: 40, to: 10, expected: -30},
+		{from: 350, to: 20, expected: 30},
+		{from: 20, to: 350, expected: -30},
+	}
+	for _, test := range tests {
+		if change := trackChange(test.from, test.to); change != test.expected {
+			t.Errorf("trackChange(%v, %v) = %v, expected %v", test.from, test.to, change, test.expected)
+		}
+	}
+}
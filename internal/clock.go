@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and schedules what happens over time, like the updates, summaries and the
+// end of the warmup. Besides the SystemClock there is the ManualClock, which only moves on when
+// told to, so that time dependent behaviour can be tested deterministically and sighting data can
+// be replayed faster than real time.
+type Clock interface {
+	Now() time.Time
+	// NewTicker delivers the time on the channel of the ticker once every period.
+	NewTicker(period time.Duration) Ticker
+	// AfterFunc calls the function once the delay has passed.
+	AfterFunc(delay time.Duration, fn func()) Timer
+}
+
+// Ticker is a time.Ticker of a Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer is a time.Timer of a Clock, which can be stopped before it fires.
+type Timer interface {
+	Stop() bool
+}
+
+// SystemClock is the Clock of the system, i.e. the functions of the time package.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) NewTicker(period time.Duration) Ticker {
+	return systemTicker{Ticker: time.NewTicker(period)}
+}
+
+func (SystemClock) AfterFunc(delay time.Duration, fn func()) Timer {
+	return time.AfterFunc(delay, fn)
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// ManualClock is a Clock which stands still until it is advanced.
+type ManualClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*manualWaiter
+}
+
+// manualWaiter is a ticker or timer of a ManualClock. Timers call their function, tickers deliver
+// the time on their channel and are due again a period later.
+type manualWaiter struct {
+	clock   *ManualClock
+	due     time.Time
+	period  time.Duration // period is zero for timers.
+	channel chan time.Time
+	fn      func()
+	stopped bool
+}
+
+// NewManualClock creates a ManualClock which stands at the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{mutex: sync.Mutex{}, now: now, waiters: nil}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) NewTicker(period time.Duration) Ticker {
+	if period <= 0 {
+		panic("NewTicker: non-positive period")
+	}
+	// Like time.Ticker, ticks are dropped if the receiver falls behind.
+	return manualTicker{manualWaiter: c.addWaiter(period, period, make(chan time.Time, 1), nil)}
+}
+
+func (c *ManualClock) AfterFunc(delay time.Duration, fn func()) Timer {
+	return c.addWaiter(delay, 0, nil, fn)
+}
+
+func (c *ManualClock) addWaiter(
+	delay time.Duration,
+	period time.Duration,
+	channel chan time.Time,
+	fn func(),
+) *manualWaiter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	waiter := &manualWaiter{
+		clock:   c,
+		due:     c.now.Add(delay),
+		period:  period,
+		channel: channel,
+		fn:      fn,
+		stopped: false,
+	}
+	c.waiters = append(c.waiters, waiter)
+	return waiter
+}
+
+// Advance moves the clock forward by the duration. Tickers and timers which become due on the way
+// fire in order, each at the time it is due, as if the time had actually passed.
+func (c *ManualClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	target := c.now.Add(duration)
+	for {
+		next := c.nextDue(target)
+		if next == nil {
+			break
+		}
+		c.now = next.due
+		if next.fn != nil {
+			next.stopped = true
+			// The function may use the clock itself.
+			c.mutex.Unlock()
+			next.fn()
+			c.mutex.Lock()
+			continue
+		}
+		select {
+		case next.channel <- next.due:
+		default:
+		}
+		next.due = next.due.Add(next.period)
+	}
+	c.now = target
+	c.waiters = slices.DeleteFunc(c.waiters, func(waiter *manualWaiter) bool { return waiter.stopped })
+	c.mutex.Unlock()
+}
+
+// nextDue returns the waiter which is due first, unless that is after the target time.
+// Requires the mutex.
+func (c *ManualClock) nextDue(target time.Time) *manualWaiter {
+	var next *manualWaiter
+	for _, waiter := range c.waiters {
+		if waiter.stopped || waiter.due.After(target) {
+			continue
+		}
+		if next == nil || waiter.due.Before(next.due) {
+			next = waiter
+		}
+	}
+	return next
+}
+
+// Stop stops the ticker or timer and tells whether it was still waiting.
+func (w *manualWaiter) Stop() bool {
+	w.clock.mutex.Lock()
+	defer w.clock.mutex.Unlock()
+	wasWaiting := !w.stopped
+	w.stopped = true
+	return wasWaiting
+}
+
+// manualTicker is a manualWaiter with a channel, which is stopped like a time.Ticker.
+type manualTicker struct {
+	*manualWaiter
+}
+
+func (t manualTicker) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t manualTicker) Stop() {
+	t.manualWaiter.Stop()
+}
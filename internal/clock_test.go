@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClockAfterFunc(t *testing.T) {
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+
+	var firedAt []time.Time
+	clock.AfterFunc(DashboardWarmup, func() { firedAt = append(firedAt, clock.Now()) })
+	stopped := clock.AfterFunc(time.Minute, func() { t.Error("stopped timer fired") })
+	if !stopped.Stop() {
+		t.Error("Stop() of a waiting timer = false")
+	}
+
+	clock.Advance(DashboardWarmup - time.Second)
+	if len(firedAt) != 0 {
+		t.Fatalf("timer fired %v before it was due", firedAt)
+	}
+
+	clock.Advance(2 * time.Hour)
+	if len(firedAt) != 1 || !firedAt[0].Equal(start.Add(DashboardWarmup)) {
+		t.Errorf("timer fired at %v, expected once at %v", firedAt, start.Add(DashboardWarmup))
+	}
+	if expected := start.Add(DashboardWarmup + 2*time.Hour - time.Second); !clock.Now().Equal(expected) {
+		t.Errorf("Now() = %v, expected %v", clock.Now(), expected)
+	}
+}
+
+func TestManualClockTicker(t *testing.T) {
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	ticker := clock.NewTicker(AircraftUpdateInterval)
+
+	for tick := 1; tick <= 3; tick++ {
+		clock.Advance(AircraftUpdateInterval)
+		select {
+		case tickTime := <-ticker.C():
+			if expected := start.Add(time.Duration(tick) * AircraftUpdateInterval); !tickTime.Equal(expected) {
+				t.Errorf("tick %d at %v, expected %v", tick, tickTime, expected)
+			}
+		default:
+			t.Fatalf("no tick %d", tick)
+		}
+	}
+
+	// Like time.Ticker, ticks which aren't received in time are dropped.
+	clock.Advance(3 * AircraftUpdateInterval)
+	<-ticker.C()
+	select {
+	case tickTime := <-ticker.C():
+		t.Errorf("unexpected tick at %v", tickTime)
+	default:
+	}
+
+	ticker.Stop()
+	clock.Advance(AircraftUpdateInterval)
+	select {
+	case tickTime := <-ticker.C():
+		t.Errorf("stopped ticker ticked at %v", tickTime)
+	default:
+	}
+}
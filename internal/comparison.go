@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScorerTally counts what a rarity scorer found rare.
+type ScorerTally struct {
+	Scorer        string // Scorer is the name and parameters of the rarity scorer.
+	Notifications int    // Notifications counts the sightings with at least one rare property.
+	Types         int
+	Operators     int
+	Countries     int
+}
+
+// String describes the tally in a single line, e.g.
+// "log: 12 notifications (5 types, 4 operators, 3 countries)".
+func (t ScorerTally) String() string {
+	return fmt.Sprintf(
+		"%s: %d notifications (%d types, %d operators, %d countries)",
+		t.Scorer,
+		t.Notifications,
+		t.Types,
+		t.Operators,
+		t.Countries)
+}
+
+func (t *ScorerTally) add(category string) {
+	switch category {
+	case "type":
+		t.Types++
+	case "operator":
+		t.Operators++
+	case "country":
+		t.Countries++
+	}
+}
+
+// ScorerComparison evaluates a second rarity scorer alongside the active one on the same
+// sightings and counts how many notifications each of them produces, to tune the rarity settings
+// without running each of them for days.
+type ScorerComparison struct {
+	A         ScorerTally // A is the tally of the active scorer.
+	B         ScorerTally // B is the tally of the scorer it is compared to.
+	Sightings int         // Sightings counts the sightings with at least one known property.
+	Both      int         // Both counts the notifications which both scorers produce.
+	scorerB   RarityScorer
+	pending   [2]RarityFlag // pending are the rarities of the current sighting found by A and B.
+	observed  bool          // observed tells whether the current sighting had anything to score.
+}
+
+// NewScorerComparison compares the active scorer a to the scorer b, which must be a separate
+// instance, since scorers may keep state.
+func NewScorerComparison(a RarityScorer, b RarityScorer) *ScorerComparison {
+	//nolint:exhaustruct // tallies start at zero
+	return &ScorerComparison{
+		A:       ScorerTally{Scorer: scorerLabel(a)},
+		B:       ScorerTally{Scorer: scorerLabel(b)},
+		scorerB: b,
+	}
+}
+
+func scorerLabel(scorer RarityScorer) string {
+	return fmt.Sprintf("%s (%s)", scorer.Name(), scorer.Parameters())
+}
+
+// observe records whether the active scorer found the observed property rare and evaluates the
+// compared scorer on the same observation.
+func (sc *ScorerComparison) observe(observation RarityObservation, isRareA bool) {
+	sc.observed = true
+	flag := categoryFlag(observation.Category)
+	if isRareA {
+		sc.A.add(observation.Category)
+		sc.pending[0] |= flag
+	}
+	if sc.scorerB.IsRare(observation) {
+		sc.B.add(observation.Category)
+		sc.pending[1] |= flag
+	}
+}
+
+// finishSighting counts the notifications of the current sighting, since a sighting with several
+// rare properties is notified only once.
+func (sc *ScorerComparison) finishSighting() {
+	if sc.observed {
+		sc.Sightings++
+	}
+	if sc.pending[0] != NoRarity {
+		sc.A.Notifications++
+	}
+	if sc.pending[1] != NoRarity {
+		sc.B.Notifications++
+	}
+	if sc.pending[0] != NoRarity && sc.pending[1] != NoRarity {
+		sc.Both++
+	}
+	sc.pending = [2]RarityFlag{NoRarity, NoRarity}
+	sc.observed = false
+}
+
+// String describes the comparison in a single line.
+func (sc *ScorerComparison) String() string {
+	return fmt.Sprintf("%s vs. %s, %d by both, over %d sightings", sc.A, sc.B, sc.Both, sc.Sightings)
+}
+
+func categoryFlag(category string) RarityFlag {
+	switch category {
+	case "type":
+		return RareType
+	case "operator":
+		return RareOperator
+	case "country":
+		return RareCountry
+	}
+	return NoRarity
+}
+
+// ReplayScorerComparison compares two rarity scorers over the given sighting history, as if it had
+// been seen live from the start. The scorers must be fresh instances.
+func ReplayScorerComparison(entries []HistoryEntry, a RarityScorer, b RarityScorer) *ScorerComparison {
+	comparison := NewScorerComparison(a, b)
+	replayObservations(entries,
+		func(observation RarityObservation) { comparison.observe(observation, a.IsRare(observation)) },
+		func(HistoryEntry) { comparison.finishSighting() })
+	return comparison
+}
+
+// replayObservations replays the sighting history in the order it was seen as rarity observations,
+// calling observe for every known property of a sighting and finish after each sighting.
+func replayObservations(
+	entries []HistoryEntry,
+	observe func(observation RarityObservation),
+	finish func(entry HistoryEntry),
+) {
+	sorted := make([]HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	counts := make(map[string]map[string]int, len(discoveryCategories))
+	totals := make(map[string]int, len(discoveryCategories))
+	for _, category := range discoveryCategories {
+		counts[category] = make(map[string]int)
+	}
+
+	for _, entry := range sorted {
+		properties := []struct{ category, property string }{
+			{"type", entry.Type},
+			{"operator", entry.Operator},
+			{"country", entry.Country},
+		}
+		for _, prop := range properties {
+			if prop.property == "" || strings.EqualFold(prop.property, typeUnknown) {
+				continue
+			}
+			counts[prop.category][prop.property]++
+			totals[prop.category]++
+			observe(RarityObservation{
+				Category: prop.category,
+				Property: prop.property,
+				Count:    counts[prop.category][prop.property],
+				Total:    totals[prop.category],
+				Counts:   counts[prop.category],
+				Time:     entry.Time,
+			})
+		}
+		finish(entry)
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayScorerComparison(t *testing.T) {
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(minutes int, aType string) HistoryEntry {
+		return HistoryEntry{
+			Time:         start.Add(time.Duration(minutes) * time.Minute),
+			Hex:          "",
+			Flight:       "",
+			Registration: "",
+			Type:         aType,
+			Operator:     "Lufthansa",
+			Country:      countryUnknown,
+		}
+	}
+	// Out of order, as the comparison must replay them by time.
+	entries := []HistoryEntry{entry(3, "A320"), entry(0, "A320"), entry(1, "A320"), entry(2, "B748")}
+
+	tests := []struct {
+		name      string
+		scorerB   RarityScorer
+		expectedB int
+		both      int
+	}{
+		{
+			name:      "never rare",
+			scorerB:   &LogScorer{Constant: 100},
+			expectedB: 0,
+			both:      0,
+		},
+		{
+			name:      "same scorer",
+			scorerB:   &RatioScorer{Ratio: 0.5, MinTotal: 1},
+			expectedB: 1,
+			both:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorerA := &RatioScorer{Ratio: 0.5, MinTotal: 1}
+			comparison := ReplayScorerComparison(entries, scorerA, tt.scorerB)
+
+			// Only the B748, seen third as one of three types, is below half of all sightings.
+			if comparison.A.Notifications != 1 || comparison.A.Types != 1 || comparison.A.Operators != 0 {
+				t.Errorf("A = %+v, expected a single rare type", comparison.A)
+			}
+			if comparison.B.Notifications != tt.expectedB {
+				t.Errorf("B.Notifications = %d, expected %d", comparison.B.Notifications, tt.expectedB)
+			}
+			if comparison.Both != tt.both {
+				t.Errorf("Both = %d, expected %d", comparison.Both, tt.both)
+			}
+			if comparison.Sightings != len(entries) {
+				t.Errorf("Sightings = %d, expected %d", comparison.Sightings, len(entries))
+			}
+		})
+	}
+}
+
+func TestScorerComparisonCountsSightingsOnce(t *testing.T) {
+	always := &RatioScorer{Ratio: 2, MinTotal: 0}
+	comparison := NewScorerComparison(always, &RatioScorer{Ratio: 2, MinTotal: 0})
+	for _, category := range discoveryCategories {
+		observation := RarityObservation{
+			Category: category,
+			Property: "x",
+			Count:    1,
+			Total:    1,
+			Counts:   nil,
+			Time:     time.Time{},
+		}
+		comparison.observe(observation, always.IsRare(observation))
+	}
+	comparison.finishSighting()
+	comparison.finishSighting() // nothing observed
+
+	if comparison.A.Notifications != 1 || comparison.B.Notifications != 1 || comparison.Sightings != 1 {
+		t.Errorf("comparison = %s, expected one notification each over one sighting", comparison)
+	}
+	if comparison.A.Types != 1 || comparison.A.Operators != 1 || comparison.A.Countries != 1 {
+		t.Errorf("A = %+v, expected every category rare once", comparison.A)
+	}
+}
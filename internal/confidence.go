@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnknownConfidence = errors.New("unknown confidence")
+
+// Confidence tells how reliable a resolved type, operator or country of an aircraft is, since each
+// is found along a different lookup path.
+type Confidence int
+
+const (
+	// ConfidenceNone means that nothing was resolved.
+	ConfidenceNone Confidence = iota
+	// ConfidenceExact is an exact hit in a database, e.g. of the ICAO designator of an airline.
+	ConfidenceExact
+	// ConfidenceHeuristic is a match of a prefix, e.g. of the registration or of an IATA code.
+	ConfidenceHeuristic
+	// ConfidenceGuess is a guess, e.g. the country to which the range of the hex address belongs.
+	ConfidenceGuess
+)
+
+// Confidences tells how reliable each of the resolved fields of an aircraft is.
+type Confidences struct {
+	Type     Confidence `json:"type"`
+	Operator Confidence `json:"operator"`
+	Country  Confidence `json:"country"`
+}
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceNone:
+		return "none"
+	case ConfidenceExact:
+		return "exact"
+	case ConfidenceHeuristic:
+		return "heuristic"
+	case ConfidenceGuess:
+		return "guess"
+	}
+	return "unknown"
+}
+
+// MarshalText lets the confidence be exported by its name.
+func (c Confidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads a confidence exported by its name.
+func (c *Confidence) UnmarshalText(text []byte) error {
+	for _, confidence := range []Confidence{
+		ConfidenceNone, ConfidenceExact, ConfidenceHeuristic, ConfidenceGuess,
+	} {
+		if string(text) == confidence.String() {
+			*c = confidence
+			return nil
+		}
+	}
+	return fmt.Errorf("Confidence.UnmarshalText: %w: %q", errUnknownConfidence, text)
+}
+
+// Marker is a subtle mark for values which are less than exact: "*" for heuristics and "?" for
+// guesses.
+func (c Confidence) Marker() string {
+	switch c {
+	case ConfidenceHeuristic:
+		return "*"
+	case ConfidenceGuess:
+		return "?"
+	case ConfidenceNone, ConfidenceExact:
+	}
+	return ""
+}
+
+// Resolved is what has been resolved about an aircraft from the datasets.
+type Resolved struct {
+	Type       string
+	Operator   string
+	Country    string
+	Confidence Confidences
+}
+
+// Resolved returns the type, operator and country resolved for the aircraft with the given hex.
+func (db *Dashboard) Resolved(hex string) (Resolved, bool) {
+	sighting, exists := db.aircraftSightings[hex]
+	if !exists {
+		return Resolved{}, false
+	}
+	return Resolved{
+		Type:       sighting.typeDesc,
+		Operator:   sighting.operator,
+		Country:    sighting.country,
+		Confidence: sighting.confidence,
+	}, true
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConfidencesJSON(t *testing.T) {
+	confidences := Confidences{
+		Type:     ConfidenceExact,
+		Operator: ConfidenceHeuristic,
+		Country:  ConfidenceGuess,
+	}
+	content, err := json.Marshal(confidences)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	expected := `{"type":"exact","operator":"heuristic","country":"guess"}`
+	if string(content) != expected {
+		t.Errorf("Marshal() = %s, expected %s", content, expected)
+	}
+
+	var decoded Confidences
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded != confidences {
+		t.Errorf("Unmarshal() = %+v, expected %+v", decoded, confidences)
+	}
+	if err := json.Unmarshal([]byte(`{"type":"certain"}`), &decoded); err == nil {
+		t.Error("Unmarshal() accepted unknown confidence")
+	}
+}
+
+func TestConfidenceMarker(t *testing.T) {
+	tests := map[Confidence]string{
+		ConfidenceNone:      "",
+		ConfidenceExact:     "",
+		ConfidenceHeuristic: "*",
+		ConfidenceGuess:     "?",
+	}
+	for confidence, expected := range tests {
+		if marker := confidence.Marker(); marker != expected {
+			t.Errorf("%s.Marker() = %q, expected %q", confidence, marker, expected)
+		}
+	}
+}
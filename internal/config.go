@@ -0,0 +1,407 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// DefaultConfigPath is where the config file is looked for if no other path is given.
+	DefaultConfigPath = "./airspottr.json"
+	// EnvPrefix starts the names of the environment variables which set command line options,
+	// e.g. AIRSPOTTR_RARITY_SCORER sets --rarity-scorer.
+	EnvPrefix = "AIRSPOTTR_"
+
+	configFlagName  = "config"
+	configLayoutKey = "layout"
+	// layoutSplits is the number of splits between the three rarity tables.
+	layoutSplits = 2
+)
+
+var errInvalidConfig = errors.New("invalid config")
+
+// Sort orders of the summary lists.
+const (
+	SummaryOrderAscending  = "asc"  // least common first
+	SummaryOrderDescending = "desc" // most common first
+)
+
+// AppOptions bundles the options of all parts of the application.
+type AppOptions struct {
+	Request   RequestOptions
+	Dashboard DashboardOptions
+	Notify    NotifyOptions
+	Health    HealthOptions
+	Acars     AcarsOptions
+	Export    ExportOptions
+	Polling   PollingOptions
+	Layout    LayoutConfig // Layout is how the panels of the TUI were last arranged.
+	// Images is how the TUI shows photos of aircraft: auto, kitty, iterm or off.
+	Images string
+	// Theme is the name of the colours of the TUI, e.g. "mono".
+	Theme string
+	// ConfigPath is where the config file was read from, to reload it from.
+	ConfigPath string
+}
+
+// ExportOptions determines which statistics are exported to files.
+type ExportOptions struct {
+	TrafficCSVPath string // TrafficCSVPath is where the traffic volume is exported, empty disables it.
+	// AltitudeCSVPath is where the altitude band distribution is exported, empty disables it.
+	AltitudeCSVPath string
+	// WindCSVPath is where the winds-aloft profile is exported, empty disables it.
+	WindCSVPath string
+	// TemperatureCSVPath is where the temperature profile is exported, empty disables it.
+	TemperatureCSVPath string
+	// StatsPath is where the session statistics are written on quitting, empty disables it.
+	StatsPath string
+	// SnapshotFormat is how the TUI writes snapshots of what it shows: text, csv or json.
+	SnapshotFormat string
+	// SnapshotClipboard copies a summary of each snapshot to the clipboard of the terminal.
+	SnapshotClipboard bool
+}
+
+// Config mirrors the optional JSON config file.
+type Config struct {
+	Flags   map[string]string `json:"flags"`    // command line options, e.g. {"location": "hamburg"}
+	APIKeys map[string]string `json:"api_keys"` // API keys by data source, e.g. {"adsbx": "..."}
+	Rules   []RuleConfig      `json:"rules"`
+	Areas   []AreaConfig      `json:"areas"` // where arrivals and departures are reported
+	Summary SummaryConfig     `json:"summary"`
+	Sinks   SinksConfig       `json:"sinks"`
+	// Notifications switches categories of desktop notifications off, e.g. {"rare_country": false}.
+	Notifications NotificationsConfig `json:"notifications"`
+	// Routing sends the events of each severity to other sinks, e.g. {"info": ["tui"]}.
+	Routing RoutingConfig `json:"routing"`
+	// DataURLs are where to download updated datasets from, by dataset, e.g.
+	// {"types": "https://example.com/ICAOList.csv"}. See UpdatableDatasets. The airports are
+	// downloaded from OurAirports unless another URL is given.
+	DataURLs map[string]string `json:"data_urls"`
+	Layout   LayoutConfig      `json:"layout"` // how the panels of the TUI are arranged
+	// Tiers tag aircraft by their distance, empty uses DefaultProximityTiers.
+	Tiers ProximityTiers `json:"tiers"`
+	// OperatorGroups roll up operators to the brands they belong to.
+	OperatorGroups OperatorGroups `json:"operator_groups"`
+	// Language is what the TUI, notifications and reports are shown in, e.g. "de", English if empty.
+	Language i18n.Language `json:"language"`
+	// Locations name places to spot planes at, chosen with --location like the predefined ones.
+	Locations Locations `json:"locations"`
+	// Region is a bounding box or polygon to request aircraft from instead of the circle around the location.
+	Region RegionConfig `json:"region"`
+}
+
+// Locations map names to the lat,lon coordinates of places, e.g. {"home": [53.63, 9.99]}.
+type Locations map[string][]float64
+
+func (locations Locations) validate() error {
+	for name, latLon := range locations {
+		//nolint:mnd // latitudes and longitudes
+		if len(latLon) != 2 || math.Abs(latLon[0]) > 90 || math.Abs(latLon[1]) > 180 {
+			return fmt.Errorf("%w: location %q must be [lat, lon], got %v", errInvalidConfig, name, latLon)
+		}
+	}
+	return nil
+}
+
+// LayoutConfig keeps how the panels of the TUI are arranged, as they were last adjusted, e.g.
+//
+//	"layout": {"hide_header": false, "hide_stats": true, "splits": [0.5, 0.25]}
+//
+// The zero value shows all panels and splits the width evenly between the rarity tables.
+type LayoutConfig struct {
+	HideHeader bool `json:"hide_header"` // HideHeader collapses the header above the tables.
+	HideStats  bool `json:"hide_stats"`  // HideStats collapses the statistics above the rarity tables.
+	// Splits are the shares of the width of the type and the operator table, the country table
+	// takes the rest. Empty splits the width evenly.
+	Splits []float64 `json:"splits"`
+}
+
+func (c LayoutConfig) validate() error {
+	if len(c.Splits) == 0 {
+		return nil
+	}
+	if len(c.Splits) != layoutSplits {
+		return fmt.Errorf("%w: layout needs %d splits", errInvalidConfig, layoutSplits)
+	}
+	sum := 0.0
+	for _, split := range c.Splits {
+		if split <= 0 {
+			return fmt.Errorf("%w: layout splits must be positive", errInvalidConfig)
+		}
+		sum += split
+	}
+	if sum >= 1 {
+		return fmt.Errorf("%w: layout splits must leave room for the country table", errInvalidConfig)
+	}
+	return nil
+}
+
+// RuleConfig defines a custom alert: a condition evaluated against every aircraft and the
+// actions to take when it matches, e.g.
+//
+//	{
+//	  "name": "low and close",
+//	  "condition": "altitude < 3000 AND distance < 5",
+//	  "actions": ["notify", "log"]
+//	}
+type RuleConfig struct {
+	Name      string   `json:"name"`
+	Condition string   `json:"condition"`
+	Actions   []string `json:"actions"` // any of "notify", "webhook" and "log"
+	Webhook   string   `json:"webhook"` // URL to post to, required for the "webhook" action
+	// Severity of the events of the rule, see Severities. If the severity is routed, the events are
+	// sent to its sinks instead of carrying out the actions.
+	Severity string `json:"severity"`
+}
+
+// SummaryConfig determines what the periodic summary lists for each property category.
+type SummaryConfig struct {
+	Types     SummaryListConfig `json:"types"`
+	Operators SummaryListConfig `json:"operators"`
+	Countries SummaryListConfig `json:"countries"`
+}
+
+// SummaryListConfig determines which entries a summary list contains and in which order, e.g.
+// the ten rarest types are `{"limit": 10}`, the five most common `{"limit": 5, "order": "desc"}`.
+type SummaryListConfig struct {
+	Limit    int    `json:"limit"`     // how many entries to list, 0 lists all
+	MinCount int    `json:"min_count"` // entries seen less often are left out
+	Order    string `json:"order"`     // "asc" lists least common first (default), "desc" most common
+}
+
+// SinksConfig determines where events like rare sightings are sent to, e.g.
+//
+//	{
+//	  "console": {"format": "json"},
+//	  "file": {"enabled": true, "path": "./events.log"},
+//	  "desktop": {"enabled": false},
+//	  "sound": {"enabled": true},
+//	  "social": {"enabled": true, "service": "bluesky", "handle": "...", "password": "..."}
+//	}
+//
+// The console and desktop sinks are enabled by default, the file, webhook, sound and social sinks
+// are not.
+type SinksConfig struct {
+	Console SinkConfig   `json:"console"`
+	File    SinkConfig   `json:"file"`
+	Webhook SinkConfig   `json:"webhook"`
+	Desktop SinkConfig   `json:"desktop"`
+	Sound   SoundConfig  `json:"sound"`
+	Social  SocialConfig `json:"social"`
+}
+
+// SinkConfig configures a single event sink.
+type SinkConfig struct {
+	Enabled *bool  `json:"enabled"` // nil keeps the default of the sink
+	Format  string `json:"format"`  // "text" (default) or "json", console and file only
+	Path    string `json:"path"`    // file only, defaults to DefaultEventFilePath
+	URL     string `json:"url"`     // webhook only
+}
+
+// IsEnabled tells whether the sink is enabled, falling back to the given default.
+func (c SinkConfig) IsEnabled(byDefault bool) bool {
+	if c.Enabled == nil {
+		return byDefault
+	}
+	return *c.Enabled
+}
+
+func (c SummaryListConfig) validate() error {
+	if c.Limit < 0 || c.MinCount < 0 {
+		return fmt.Errorf("%w: summary limit and min_count must not be negative", errInvalidConfig)
+	}
+
+	switch c.Order {
+	case "", SummaryOrderAscending, SummaryOrderDescending:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown summary order %q", errInvalidConfig, c.Order)
+}
+
+// LoadConfig reads the config file at the given path.
+// A missing config file is not an error, in that case the default config is returned.
+func LoadConfig(path string) (Config, error) {
+	//nolint:exhaustruct // zero values list all and keep the default sinks
+	config := Config{
+		Flags:          nil,
+		APIKeys:        nil,
+		Rules:          nil,
+		Summary:        SummaryConfig{},
+		Sinks:          SinksConfig{},
+		Notifications:  nil,
+		Routing:        nil,
+		DataURLs:       map[string]string{"airports": dash.OurAirportsURL},
+		Layout:         LayoutConfig{HideHeader: false, HideStats: false, Splits: nil},
+		Tiers:          nil,
+		OperatorGroups: nil,
+		Language:       i18n.English,
+		Locations:      nil,
+		Region:         RegionConfig{BBox: nil, Polygon: nil},
+	}
+
+	content, readErr := os.ReadFile(path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return config, nil
+	}
+	if readErr != nil {
+		return config, fmt.Errorf("LoadConfig: failed to read %s: %w", path, readErr)
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return config, fmt.Errorf("LoadConfig: failed to parse %s: %w", path, err)
+	}
+
+	for _, listConfig := range []SummaryListConfig{
+		config.Summary.Types,
+		config.Summary.Operators,
+		config.Summary.Countries,
+	} {
+		if err := listConfig.validate(); err != nil {
+			return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+		}
+	}
+
+	if err := config.Notifications.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if err := config.Routing.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if err := config.Layout.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if err := config.Tiers.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if err := config.OperatorGroups.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if err := config.Locations.validate(); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	if _, err := NewRegion(config.Region); err != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w", path, err)
+	}
+
+	language, languageErr := i18n.ParseLanguage(string(config.Language))
+	if languageErr != nil {
+		return config, fmt.Errorf("LoadConfig: %s: %w: %w", path, errInvalidConfig, languageErr)
+	}
+	config.Language = language
+
+	return config, nil
+}
+
+// SaveLayout writes the layout into the config file at the given path, creating it if necessary.
+// Everything else in the config file is kept, though not its formatting.
+func SaveLayout(path string, layout LayoutConfig) error {
+	fields := make(map[string]json.RawMessage)
+	content, readErr := os.ReadFile(path)
+	if readErr != nil && !errors.Is(readErr, fs.ErrNotExist) {
+		return fmt.Errorf("SaveLayout: failed to read %s: %w", path, readErr)
+	}
+	if readErr == nil {
+		if err := json.Unmarshal(content, &fields); err != nil {
+			return fmt.Errorf("SaveLayout: failed to parse %s: %w", path, err)
+		}
+	}
+
+	encoded, layoutErr := json.Marshal(layout)
+	if layoutErr != nil {
+		return fmt.Errorf("SaveLayout: %w", layoutErr)
+	}
+	fields[configLayoutKey] = encoded
+
+	data, marshalErr := json.MarshalIndent(fields, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("SaveLayout: %w", marshalErr)
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("SaveLayout: failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("SaveLayout: failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// EnvName returns the name of the environment variable for the command line option of the given
+// name, e.g. AIRSPOTTR_STATS_HALF_LIFE for --stats-half-life.
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// ApplyEnv sets all command line options which were not given on the command line from their
+// environment variables, so that the command line takes precedence over the environment.
+func ApplyEnv(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		value, ok := os.LookupEnv(EnvName(flag.Name))
+		if !ok {
+			return
+		}
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("ApplyEnv: %s: %w", EnvName(flag.Name), setErr)
+		}
+	})
+	return err
+}
+
+// ApplyConfigFlags sets all command line options which were given neither on the command line
+// nor in the environment from the "flags" of the config file, which therefore has the lowest
+// precedence. The path of the config file itself can't be set this way.
+func ApplyConfigFlags(flags *pflag.FlagSet, values map[string]string) error {
+	for name, value := range values {
+		flag := flags.Lookup(name)
+		if flag == nil || name == configFlagName {
+			return fmt.Errorf("ApplyConfigFlags: %w: unknown flag %q", errInvalidConfig, name)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("ApplyConfigFlags: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// APIKeyEnvName returns the name of the environment variable for the API key of the given data
+// source, e.g. AIRSPOTTR_API_KEY_ADSBX.
+func APIKeyEnvName(source string) string {
+	return EnvPrefix + "API_KEY_" + strings.ToUpper(source)
+}
+
+// ResolveAPIKeys returns the API keys of all data sources, taken from the environment or, if not
+// set there, from the given keys of the config file.
+func ResolveAPIKeys(fileKeys map[string]string) map[string]string {
+	apiKeys := make(map[string]string)
+	for _, source := range SourceNames() {
+		if key, ok := os.LookupEnv(APIKeyEnvName(source)); ok {
+			apiKeys[source] = key
+		} else if key, ok := fileKeys[source]; ok {
+			apiKeys[source] = key
+		}
+	}
+	return apiKeys
+}
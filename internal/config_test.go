@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/micutio/airspottr/internal/i18n"
+	"github.com/spf13/pflag"
+)
+
+func newTestFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("location", "", "")
+	flags.String("rarity-scorer", LogScorerName, "")
+	flags.Bool("quiet", false, "")
+	flags.String("config", DefaultConfigPath, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return flags
+}
+
+func TestEnvName(t *testing.T) {
+	if name := EnvName("stats-half-life"); name != "AIRSPOTTR_STATS_HALF_LIFE" {
+		t.Errorf("EnvName() = %q, expected AIRSPOTTR_STATS_HALF_LIFE", name)
+	}
+}
+
+func TestOptionPrecedence(t *testing.T) {
+	t.Setenv("AIRSPOTTR_LOCATION", "singapore")
+	t.Setenv("AIRSPOTTR_RARITY_SCORER", "ratio")
+	flags := newTestFlags(t, "--location", "hamburg")
+
+	if err := ApplyEnv(flags); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+	fileFlags := map[string]string{"location": "new-york", "rarity-scorer": "smoothed", "quiet": "true"}
+	if err := ApplyConfigFlags(flags, fileFlags); err != nil {
+		t.Fatalf("ApplyConfigFlags() error = %v", err)
+	}
+
+	expected := map[string]string{
+		"location":      "hamburg", // command line beats environment and file
+		"rarity-scorer": "ratio",   // environment beats file
+		"quiet":         "true",    // file beats default
+		"config":        DefaultConfigPath,
+	}
+	for name, value := range expected {
+		if actual := flags.Lookup(name).Value.String(); actual != value {
+			t.Errorf("--%s = %q, expected %q", name, actual, value)
+		}
+	}
+}
+
+func TestApplyEnvRejectsInvalidValue(t *testing.T) {
+	t.Setenv("AIRSPOTTR_QUIET", "maybe")
+	if err := ApplyEnv(newTestFlags(t)); err == nil {
+		t.Error("ApplyEnv() accepted invalid boolean")
+	}
+}
+
+func TestApplyConfigFlagsRejectsUnknownFlags(t *testing.T) {
+	for _, name := range []string{"no-such-flag", "config"} {
+		if err := ApplyConfigFlags(newTestFlags(t), map[string]string{name: "x"}); err == nil {
+			t.Errorf("ApplyConfigFlags() accepted flag %q", name)
+		}
+	}
+}
+
+func TestResolveAPIKeys(t *testing.T) {
+	t.Setenv(APIKeyEnvName(SourceAdsbExchange), "from-env")
+	fileKeys := map[string]string{SourceAdsbExchange: "from-file", SourceAviationstack: "from-file"}
+
+	apiKeys := ResolveAPIKeys(fileKeys)
+	if apiKeys[SourceAdsbExchange] != "from-env" {
+		t.Errorf("key of %s = %q, expected the environment to win", SourceAdsbExchange, apiKeys[SourceAdsbExchange])
+	}
+	if apiKeys[SourceAviationstack] != "from-file" {
+		t.Errorf("key of %s = %q, expected the key of the file", SourceAviationstack, apiKeys[SourceAviationstack])
+	}
+}
+
+func TestSaveLayoutKeepsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airspottr.json")
+	if err := os.WriteFile(path, []byte(`{"flags": {"location": "hamburg"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := LayoutConfig{HideHeader: false, HideStats: true, Splits: []float64{0.5, 0.25}}
+	if err := SaveLayout(path, layout); err != nil {
+		t.Fatalf("SaveLayout() error = %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Flags["location"] != "hamburg" {
+		t.Errorf("Flags = %v, expected the location to be kept", config.Flags)
+	}
+	if !config.Layout.HideStats || len(config.Layout.Splits) != 2 || config.Layout.Splits[1] != 0.25 {
+		t.Errorf("Layout = %+v, expected %+v", config.Layout, layout)
+	}
+}
+
+func TestLoadConfigRejectsInvalidLayout(t *testing.T) {
+	for _, content := range []string{
+		`{"layout": {"splits": [0.5]}}`,
+		`{"layout": {"splits": [0.6, 0.4]}}`,
+		`{"layout": {"splits": [0.5, -0.1]}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded, expected an error", content)
+		}
+	}
+}
+
+func TestLoadConfigLanguage(t *testing.T) {
+	tests := []struct {
+		content string
+		want    i18n.Language
+		wantErr bool
+	}{
+		{content: `{}`, want: i18n.English, wantErr: false},
+		{content: `{"language": "DE"}`, want: i18n.German, wantErr: false},
+		{content: `{"language": "fr"}`, want: "", wantErr: true},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(test.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		config, err := LoadConfig(path)
+		if (err != nil) != test.wantErr {
+			t.Errorf("LoadConfig(%s) error = %v, wantErr %v", test.content, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && config.Language != test.want {
+			t.Errorf("LoadConfig(%s) language = %q, want %q", test.content, config.Language, test.want)
+		}
+	}
+}
+
+func TestLoadConfigRejectsInvalidLocation(t *testing.T) {
+	for _, content := range []string{
+		`{"locations": {"home": [53.6]}}`,
+		`{"locations": {"home": [95, 10]}}`,
+		`{"locations": {"home": [53.6, 190]}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded, expected an error", content)
+		}
+	}
+}
+
+func TestLoadConfigRejectsInvalidRegion(t *testing.T) {
+	for _, content := range []string{
+		`{"region": {"bbox": [55.1, 5.9, 47.3, 15.0]}}`,
+		`{"region": {"bbox": [47.3, 5.9, 55.1]}}`,
+		`{"region": {"polygon": [[53.6, 9.9], [50.0, 8.5]]}}`,
+		`{"region": {"bbox": [47.3, 5.9, 55.1, 15.0], "polygon": [[53.6, 9.9], [50.0, 8.5], [48.3, 11.8]]}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded, expected an error", content)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// csvExporter is a statistic which can be written as CSV.
+type csvExporter interface {
+	WriteCSV(out io.Writer) error
+}
+
+// writeCSV writes the header row followed by the records as CSV.
+func writeCSV(out io.Writer, header []string, records [][]string) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("writeCSV: %w", err)
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("writeCSV: %w", err)
+	}
+	return nil
+}
+
+// exportCSV writes the statistic as CSV to the file at the given path, replacing it.
+func exportCSV(path string, stats csvExporter) error {
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return fmt.Errorf("exportCSV: failed to create %s: %w", path, createErr)
+	}
+
+	writeErr := stats.WriteCSV(file)
+	closeErr := file.Close()
+	if writeErr != nil {
+		return fmt.Errorf("exportCSV: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("exportCSV: failed to close %s: %w", path, closeErr)
+	}
+	return nil
+}
+
+// ExportCSV writes the traffic volume, altitude bands, winds aloft and temperature profile to the
+// CSV files given by the options. Statistics without a path aren't exported, and a failure to
+// export one doesn't keep the others from being exported.
+func (db *Dashboard) ExportCSV(opts ExportOptions) error {
+	exports := []struct {
+		name  string
+		path  string
+		stats csvExporter
+	}{
+		{name: "traffic", path: opts.TrafficCSVPath, stats: db.Traffic},
+		{name: "altitude bands", path: opts.AltitudeCSVPath, stats: db.Altitudes},
+		{name: "winds aloft", path: opts.WindCSVPath, stats: db.Winds},
+		{name: "temperature profile", path: opts.TemperatureCSVPath, stats: db.Temperatures},
+	}
+
+	var errs []error
+	for _, export := range exports {
+		if export.path == "" {
+			continue
+		}
+		if err := exportCSV(export.path, export.stats); err != nil {
+			errs = append(errs, fmt.Errorf("Dashboard.ExportCSV: failed to export %s: %w", export.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
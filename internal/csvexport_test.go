@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDashboardExportCSV(t *testing.T) {
+	dir := t.TempDir()
+	dashboard := &Dashboard{ //nolint:exhaustruct // statistics only
+		Traffic:      NewTrafficStats(SpottingDay{}),
+		Altitudes:    NewAltitudeBandStats(),
+		Winds:        NewWindStats(),
+		Temperatures: NewTemperatureStats(),
+	}
+	opts := ExportOptions{ //nolint:exhaustruct // CSV paths only
+		WindCSVPath: filepath.Join(dir, "winds.csv"),
+		// Can't be created, but doesn't keep the others from being exported.
+		TemperatureCSVPath: filepath.Join(dir, "missing", "temperatures.csv"),
+	}
+
+	err := dashboard.ExportCSV(opts)
+	if err == nil || !strings.Contains(err.Error(), "temperature profile") {
+		t.Errorf("ExportCSV() = %v, expected the temperature profile to fail", err)
+	}
+	content, readErr := os.ReadFile(opts.WindCSVPath)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	if !strings.HasPrefix(string(content), "band,reports,direction_deg,speed_kt\n") {
+		t.Errorf("exported winds %q, expected the header first", content)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("exported %d files, expected only the winds", len(entries))
+	}
+}
@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// DailyFileName is the name of the file in the data directory of the user which the daily counts
+// of distinct aircraft are written to if no other path is given.
+const DailyFileName = "daily.csv"
+
+// DailyCountStats counts the distinct airframes, by hex, seen on the current spotting day. The
+// count of every day is a row of a CSV file, which makes a long-term record of how busy every day
+// was.
+type DailyCountStats struct {
+	path   string          // path is the CSV file the days are written to, empty disables it.
+	day    SpottingDay     // day tells when the days start.
+	date   string          // date is the date of the current day, empty before the first aircraft.
+	hexes  map[string]bool // hexes are the aircraft seen on the current day.
+	polled bool            // polled tells whether any poll was counted, not only past sightings.
+}
+
+// NewDailyCountStats creates an empty count, whose days are written to the file at the given path.
+func NewDailyCountStats(path string, day SpottingDay) *DailyCountStats {
+	return &DailyCountStats{path: path, day: day, date: "", hexes: make(map[string]bool), polled: false}
+}
+
+// Today returns how many distinct aircraft were seen on the spotting day of the given time.
+func (ds *DailyCountStats) Today(now time.Time) int {
+	if ds.dateOf(now) != ds.date {
+		return 0
+	}
+	return len(ds.hexes)
+}
+
+// Restore counts the aircraft of past sightings of today, so that a restart doesn't start the
+// count of the day over.
+func (ds *DailyCountStats) Restore(entries []HistoryEntry, now time.Time) {
+	today := ds.dateOf(now)
+	for _, entry := range entries {
+		if ds.dateOf(entry.Time.In(now.Location())) == today {
+			ds.date = today
+			ds.hexes[entry.Hex] = true
+		}
+	}
+}
+
+// Record counts the aircraft of a poll at the given time. If a new day started since the previous
+// poll, the count of the previous day is written to the file first.
+func (ds *DailyCountStats) Record(now time.Time, aircraft []AircraftRecord) error {
+	ds.polled = true
+	var writeErr error
+	if date := ds.dateOf(now); date != ds.date {
+		if ds.date != "" {
+			writeErr = ds.writeDay()
+		}
+		ds.date = date
+		clear(ds.hexes)
+	}
+	for idx := range aircraft {
+		ds.hexes[aircraft[idx].Hex] = true
+	}
+	if writeErr != nil {
+		return fmt.Errorf("DailyCountStats.Record: %w", writeErr)
+	}
+	return nil
+}
+
+// Flush writes the count of the current day so far, e.g. on quitting, replacing the row of the day
+// written before. Nothing is written unless a poll was counted.
+func (ds *DailyCountStats) Flush() error {
+	if !ds.polled || ds.date == "" {
+		return nil
+	}
+	if err := ds.writeDay(); err != nil {
+		return fmt.Errorf("DailyCountStats.Flush: %w", err)
+	}
+	return nil
+}
+
+// dateOf returns the date of the spotting day of the given time.
+func (ds *DailyCountStats) dateOf(t time.Time) string {
+	return ds.day.Start(t).Format(time.DateOnly)
+}
+
+// writeDay writes the date and the count of the current day to the file, replacing the row of the
+// day if the file has one already, so that every day has a single row.
+func (ds *DailyCountStats) writeDay() error {
+	if ds.path == "" {
+		return nil
+	}
+
+	days, readErr := readDays(ds.path)
+	if readErr != nil {
+		return fmt.Errorf("writeDay: %w", readErr)
+	}
+	days = slices.DeleteFunc(days, func(day []string) bool { return day[0] == ds.date })
+	days = append(days, []string{ds.date, strconv.Itoa(len(ds.hexes))})
+
+	var content bytes.Buffer
+	if err := writeCSV(&content, []string{"date", "aircraft"}, days); err != nil {
+		return fmt.Errorf("writeDay: %w", err)
+	}
+	if err := replaceFile(ds.path, content.Bytes()); err != nil {
+		return fmt.Errorf("writeDay: %w", err)
+	}
+	return nil
+}
+
+// readDays reads the rows of the days from the file, without the header row. A missing file has no
+// days.
+func readDays(path string) ([][]string, error) {
+	file, openErr := os.Open(path)
+	if errors.Is(openErr, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if openErr != nil {
+		return nil, fmt.Errorf("readDays: failed to open %s: %w", path, openErr)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	rows, readErr := reader.ReadAll()
+	if readErr != nil {
+		return nil, fmt.Errorf("readDays: failed to read %s: %w", path, readErr)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[1:], nil
+}
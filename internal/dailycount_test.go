@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDailyCountRollover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.csv")
+	stats := NewDailyCountStats(path, SpottingDay{})
+	evening := time.Date(2026, time.May, 1, 23, 50, 0, 0, time.Local)
+
+	//nolint:exhaustruct // hex only
+	if err := stats.Record(evening, []AircraftRecord{{Hex: "3c6444"}, {Hex: "4b1805"}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	//nolint:exhaustruct // hex only
+	if err := stats.Record(evening.Add(5*time.Minute), []AircraftRecord{{Hex: "3c6444"}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if today := stats.Today(evening); today != 2 {
+		t.Errorf("Today() = %d, expected the 2 distinct aircraft", today)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("the file exists before the day ended, error = %v", err)
+	}
+
+	// The first poll after midnight writes the day which ended and starts the next one.
+	//nolint:exhaustruct // hex only
+	if err := stats.Record(evening.Add(15*time.Minute), []AircraftRecord{{Hex: "3c6444"}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if today := stats.Today(evening.Add(15 * time.Minute)); today != 1 {
+		t.Errorf("Today() = %d after midnight, expected the count to start over", today)
+	}
+	if err := stats.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// Flushing again replaces the row of today instead of adding another one.
+	//nolint:exhaustruct // hex only
+	if err := stats.Record(evening.Add(20*time.Minute), []AircraftRecord{{Hex: "4b1805"}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := stats.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	expected := "date,aircraft\n2026-05-01,2\n2026-05-02,2\n"
+	if string(data) != expected {
+		t.Errorf("file = %q, expected %q", data, expected)
+	}
+}
+
+func TestDailyCountRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.csv")
+	stats := NewDailyCountStats(path, SpottingDay{})
+	now := time.Date(2026, time.May, 2, 12, 0, 0, 0, time.Local)
+
+	//nolint:exhaustruct // hex and time only
+	stats.Restore([]HistoryEntry{
+		{Hex: "3c6444", Time: now.AddDate(0, 0, -1)},
+		{Hex: "4b1805", Time: now.Add(-time.Hour)},
+		{Hex: "a0b1c2", Time: now.Add(-2 * time.Hour)},
+	}, now)
+	if today := stats.Today(now); today != 2 {
+		t.Errorf("Today() = %d, expected the 2 aircraft seen earlier today", today)
+	}
+
+	// Past sightings alone don't make a day worth appending, e.g. when importing the history.
+	if err := stats.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Flush() wrote the file without a poll, error = %v", err)
+	}
+}
+
+func TestDailyCountMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airspottr", "daily.csv")
+	stats := NewDailyCountStats(path, SpottingDay{})
+	now := time.Date(2026, time.May, 2, 12, 0, 0, 0, time.Local)
+
+	//nolint:exhaustruct // hex only
+	if err := stats.Record(now, []AircraftRecord{{Hex: "3c6444"}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := stats.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v, expected the directory to be created", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "date,aircraft\n2026-05-02,1\n" {
+		t.Errorf("file = %q, error = %v", data, err)
+	}
+}
@@ -0,0 +1,202 @@
+package dash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AirportsFile lists airports by their ICAO code with their position and elevation. It is
+	// optional, without it no go-arounds are detected.
+	AirportsFile      = "Airports.csv"
+	airportsHeaderLen = 5
+	// OurAirportsURL is the complete list of airports of OurAirports, which AirportsFile may be
+	// replaced with as it is.
+	OurAirportsURL = "https://davidmegginson.github.io/ourairports-data/airports.csv"
+)
+
+var errInvalidAirport = errors.New("invalid airport")
+
+// ourAirportsColumns are the columns of the airports of OurAirports which are read, by the name in
+// its header. It has many more.
+//
+//nolint:gochecknoglobals // constant
+var ourAirportsColumns = []string{"ident", "type", "name", "latitude_deg", "longitude_deg", "elevation_ft", "icao_code"}
+
+// ourAirportsTypes are the types of the airports of OurAirports which are kept, leaving out
+// heliports, seaplane bases, balloon ports and closed airports.
+//
+//nolint:gochecknoglobals // constant
+var ourAirportsTypes = map[string]bool{"large_airport": true, "medium_airport": true, "small_airport": true}
+
+// Airport is an airport with the position and elevation of its reference point.
+type Airport struct {
+	Icao      string
+	Name      string
+	Lat       float64
+	Lon       float64
+	Elevation float64 // Elevation is the elevation above sea level in [ft].
+}
+
+// GetAirports returns all airports of the dataset.
+func GetAirports(dataDirs []string) ([]Airport, error) {
+	airports, err := parseAirportsCsv(FindDataFile(dataDirs, AirportsFile))
+	if err != nil {
+		return nil, fmt.Errorf("GetAirports: %w: %w", errParseCSV, err)
+	}
+
+	return airports, nil
+}
+
+// NearestAirport returns the airport closest to the position and its distance in [km], false if
+// there are no airports.
+func NearestAirport(airports []Airport, lat float64, lon float64) (Airport, float64, bool) {
+	position := NewCoordinates(lat, lon)
+	nearestIdx := -1
+	nearestDist := 0.0
+	for idx := range airports {
+		dist := Distance(position, NewCoordinates(airports[idx].Lat, airports[idx].Lon)).Kilometers()
+		if nearestIdx < 0 || dist < nearestDist {
+			nearestIdx, nearestDist = idx, dist
+		}
+	}
+	if nearestIdx < 0 {
+		return Airport{Icao: "", Name: "", Lat: 0, Lon: 0, Elevation: 0}, 0, false
+	}
+	return airports[nearestIdx], nearestDist, true
+}
+
+// parseAirportsCsv reads a CSV file and parses it into a list of airports.
+func parseAirportsCsv(filePath string) ([]Airport, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseAirportsCsv: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readAirports(file)
+}
+
+// readAirports parses airports with the headers ICAO, name, latitude, longitude, elevation, or the
+// airports of OurAirports, see readOurAirports.
+func readAirports(input io.Reader) ([]Airport, error) {
+	reader := csv.NewReader(input)
+
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("readAirports: failed to read header: %w", headerErr)
+	}
+	if columns, ok := findColumns(headers, ourAirportsColumns); ok {
+		return readOurAirports(reader, columns)
+	}
+	if len(headers) != airportsHeaderLen {
+		return nil, fmt.Errorf("readAirports: %w", errHeaderLen)
+	}
+
+	var airports []Airport
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readAirports: failed to read record: %w", err)
+		}
+
+		icao := strings.ToUpper(strings.TrimSpace(record[0]))
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		elevation, elevationErr := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		if icao == "" {
+			return nil, fmt.Errorf("readAirports: %w without ICAO code", errInvalidAirport)
+		}
+		if err := errors.Join(latErr, lonErr, elevationErr); err != nil {
+			return nil, fmt.Errorf("readAirports: %w %s: %w", errInvalidAirport, icao, err)
+		}
+		airports = append(airports, Airport{
+			Icao:      icao,
+			Name:      strings.TrimSpace(record[1]),
+			Lat:       lat,
+			Lon:       lon,
+			Elevation: elevation,
+		})
+	}
+
+	return airports, nil
+}
+
+// readOurAirports parses the airports of OurAirports (https://ourairports.com/data/), whose columns
+// are at the given indices in the order of ourAirportsColumns. Airports of other types, without an
+// ICAO code or without an elevation are left out, since go-arounds can't be told at them.
+func readOurAirports(reader *csv.Reader, columns []int) ([]Airport, error) {
+	ident, kind, name, latitude, longitude, elevationFt, icaoCode :=
+		columns[0], columns[1], columns[2], columns[3], columns[4], columns[5], columns[6]
+
+	var airports []Airport
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readOurAirports: failed to read record: %w", err)
+		}
+
+		icao := strings.ToUpper(strings.TrimSpace(record[icaoCode]))
+		if icao == "" && isIcaoAirportCode(record[ident]) {
+			icao = record[ident]
+		}
+		if !ourAirportsTypes[record[kind]] || icao == "" || strings.TrimSpace(record[elevationFt]) == "" {
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[latitude]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[longitude]), 64)
+		elevation, elevationErr := strconv.ParseFloat(strings.TrimSpace(record[elevationFt]), 64)
+		if err := errors.Join(latErr, lonErr, elevationErr); err != nil {
+			return nil, fmt.Errorf("readOurAirports: %w %s: %w", errInvalidAirport, icao, err)
+		}
+		airports = append(airports, Airport{
+			Icao:      icao,
+			Name:      strings.TrimSpace(record[name]),
+			Lat:       lat,
+			Lon:       lon,
+			Elevation: elevation,
+		})
+	}
+
+	return airports, nil
+}
+
+// findColumns returns the indices of the named columns in the header, false unless all are there.
+func findColumns(headers []string, names []string) ([]int, bool) {
+	columns := make([]int, len(names))
+	for idx, name := range names {
+		columns[idx] = slices.Index(headers, name)
+		if columns[idx] < 0 {
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
+// isIcaoAirportCode tells whether the code is four capital letters, like the ICAO codes of
+// airports, rather than a local code like "US-0042" or "00AK".
+func isIcaoAirportCode(code string) bool {
+	if len(code) != 4 { //nolint:mnd // ICAO airport codes have four letters
+		return false
+	}
+	for _, char := range code {
+		if char < 'A' || char > 'Z' {
+			return false
+		}
+	}
+	return true
+}
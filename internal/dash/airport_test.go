@@ -0,0 +1,98 @@
+package dash
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadAirports(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []Airport
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			csv:  "ICAO,Name,Latitude,Longitude,Elevation\n eddh ,Hamburg,53.6304,9.9882,53\n",
+			want: []Airport{
+				{Icao: "EDDH", Name: "Hamburg", Lat: 53.6304, Lon: 9.9882, Elevation: 53},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OurAirports",
+			csv: `"id","ident","type","name","latitude_deg","longitude_deg","elevation_ft","continent","icao_code"
+2434,"EDDH","large_airport","Hamburg Helmut Schmidt Airport",53.630402,9.98823,53,"EU","EDDH"
+2451,"EDHI","medium_airport","Hamburg-Finkenwerder Airport",53.5352,9.83555,23,"EU",""
+42,"DE-0042","heliport","Asklepios Klinik",53.6,10.0,30,"EU",""
+43,"DE-0043","small_airport","Farm Strip",53.7,10.1,,"EU",""
+44,"EDXX","closed","Closed Field",53.8,10.2,40,"EU","EDXX"
+`,
+			want: []Airport{
+				{Icao: "EDDH", Name: "Hamburg Helmut Schmidt Airport", Lat: 53.630402, Lon: 9.98823, Elevation: 53},
+				{Icao: "EDHI", Name: "Hamburg-Finkenwerder Airport", Lat: 53.5352, Lon: 9.83555, Elevation: 23},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "wrong header",
+			csv:     "ICAO,Name\nEDDH,Hamburg\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty ICAO code",
+			csv:     "ICAO,Name,Latitude,Longitude,Elevation\n,Hamburg,53.6304,9.9882,53\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid elevation",
+			csv:     "ICAO,Name,Latitude,Longitude,Elevation\nEDDH,Hamburg,53.6304,9.9882,high\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readAirports(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readAirports() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("readAirports() = %+v, expected %+v", got, tt.want)
+			}
+			for idx := range tt.want {
+				if got[idx] != tt.want[idx] {
+					t.Errorf("readAirports()[%d] = %+v, expected %+v", idx, got[idx], tt.want[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestNearestAirport(t *testing.T) {
+	airports := []Airport{
+		{Icao: "EDDH", Name: "Hamburg", Lat: 53.6304, Lon: 9.9882, Elevation: 53},
+		{Icao: "EDHI", Name: "Hamburg Finkenwerder", Lat: 53.5353, Lon: 9.8356, Elevation: 23},
+	}
+	if airport, dist, ok := NearestAirport(airports, 53.54, 9.84); !ok || airport.Icao != "EDHI" || dist > 1 {
+		t.Errorf("NearestAirport() = %s, %.1f km, %t, expected EDHI", airport.Icao, dist, ok)
+	}
+	if _, _, ok := NearestAirport(nil, 53.54, 9.84); ok {
+		t.Error("NearestAirport() found an airport without any")
+	}
+}
+
+func TestBundledAirports(t *testing.T) {
+	airports, err := parseAirportsCsv(filepath.Join("../..", BundledDataDir, AirportsFile))
+	if err != nil {
+		t.Fatalf("parseAirportsCsv() error = %v", err)
+	}
+	if airport, _, _ := NearestAirport(airports, 53.63, 9.99); airport.Icao != "EDDH" {
+		t.Errorf("nearest airport of Hamburg = %+v, expected EDDH", airport)
+	}
+}
@@ -0,0 +1,74 @@
+package dash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// TypeFamilyFile groups ICAO types into families, e.g. the A320 family. It is optional, types
+	// which aren't listed make a family of their own.
+	TypeFamilyFile      = "TypeFamilies.csv"
+	typeFamilyHeaderLen = 2
+)
+
+var errEmptyFamily = errors.New("empty type family")
+
+// GetTypeFamilyMap returns an ICAO type designator to family mapping.
+func GetTypeFamilyMap(dataDirs []string) (map[string]string, error) {
+	typeFamilyMap, err := parseTypeFamilyCsvToMap(FindDataFile(dataDirs, TypeFamilyFile))
+	if err != nil {
+		return nil, fmt.Errorf("GetTypeFamilyMap: %w: %w", errParseCSV, err)
+	}
+
+	return typeFamilyMap, nil
+}
+
+// parseTypeFamilyCsvToMap reads a CSV file and parses it into a map ICAO type -> family.
+func parseTypeFamilyCsvToMap(filePath string) (map[string]string, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseTypeFamilyCsvToMap: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readTypeFamilies(file)
+}
+
+// readTypeFamilies parses type families with the headers type designator, family.
+func readTypeFamilies(input io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(input)
+
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("readTypeFamilies: failed to read header: %w", headerErr)
+	}
+	if len(headers) != typeFamilyHeaderLen {
+		return nil, fmt.Errorf("readTypeFamilies: %w", errHeaderLen)
+	}
+
+	records := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readTypeFamilies: failed to read record: %w", err)
+		}
+
+		family := strings.TrimSpace(record[1])
+		if family == "" {
+			return nil, fmt.Errorf("readTypeFamilies: %w of %s", errEmptyFamily, record[0])
+		}
+		records[strings.TrimSpace(record[0])] = family
+	}
+
+	return records, nil
+}
@@ -0,0 +1,59 @@
+package dash
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTypeFamilies(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			csv:     "type,family\nA320,Airbus A320 family\n B38M , Boeing 737 family \n",
+			want:    map[string]string{"A320": "Airbus A320 family", "B38M": "Boeing 737 family"},
+			wantErr: false,
+		},
+		{
+			name:    "wrong header",
+			csv:     "type\nA320\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty family",
+			csv:     "type,family\nA320, \n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTypeFamilies(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readTypeFamilies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for icaoType, family := range tt.want {
+				if got[icaoType] != family {
+					t.Errorf("readTypeFamilies()[%s] = %q, expected %q", icaoType, got[icaoType], family)
+				}
+			}
+		})
+	}
+}
+
+func TestBundledTypeFamilies(t *testing.T) {
+	families, err := parseTypeFamilyCsvToMap(filepath.Join("../..", BundledDataDir, TypeFamilyFile))
+	if err != nil {
+		t.Fatalf("parseTypeFamilyCsvToMap() error = %v", err)
+	}
+	if families["A20N"] != "Airbus A320 family" {
+		t.Errorf("family of A20N = %q, expected Airbus A320 family", families["A20N"])
+	}
+}
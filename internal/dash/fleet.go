@@ -0,0 +1,75 @@
+package dash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FleetSizeFile lists how many aircraft of a type are in service worldwide. It is optional,
+	// types which aren't listed simply have no global rarity.
+	FleetSizeFile      = "FleetSizes.csv"
+	fleetSizeHeaderLen = 2
+)
+
+var errParseFleetSize = errors.New("unable to parse fleet size")
+
+// GetFleetSizeMap returns an ICAO type designator to worldwide fleet size mapping.
+func GetFleetSizeMap(dataDirs []string) (map[string]int, error) {
+	fleetSizeMap, err := parseFleetSizeCsvToMap(FindDataFile(dataDirs, FleetSizeFile))
+	if err != nil {
+		return nil, fmt.Errorf("getFleetSizeMap: %w: %w", errParseCSV, err)
+	}
+
+	return fleetSizeMap, nil
+}
+
+// parseFleetSizeCsvToMap reads a CSV file and parses it into a map ICAO type -> fleet size.
+func parseFleetSizeCsvToMap(filePath string) (map[string]int, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseFleetSizeCsvToMap: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readFleetSizes(file)
+}
+
+// readFleetSizes parses fleet sizes with the headers type designator, fleet size.
+func readFleetSizes(input io.Reader) (map[string]int, error) {
+	reader := csv.NewReader(input)
+
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("readFleetSizes: failed to read header: %w", headerErr)
+	}
+	if len(headers) != fleetSizeHeaderLen {
+		return nil, fmt.Errorf("readFleetSizes: %w", errHeaderLen)
+	}
+
+	records := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readFleetSizes: failed to read record: %w", err)
+		}
+
+		size, sizeErr := strconv.Atoi(strings.TrimSpace(record[1]))
+		if sizeErr != nil || size < 0 {
+			return nil, fmt.Errorf("readFleetSizes: %w of %s: %q", errParseFleetSize, record[0], record[1])
+		}
+		records[strings.TrimSpace(record[0])] = size
+	}
+
+	return records, nil
+}
@@ -0,0 +1,59 @@
+package dash
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadFleetSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			csv:     "type,fleet\nA124,20\nB74S, 2\n",
+			want:    map[string]int{"A124": 20, "B74S": 2},
+			wantErr: false,
+		},
+		{
+			name:    "wrong header",
+			csv:     "type,fleet,built\nA124,20,55\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative fleet",
+			csv:     "type,fleet\nA124,-1\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFleetSizes(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readFleetSizes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for icaoType, size := range tt.want {
+				if got[icaoType] != size {
+					t.Errorf("readFleetSizes()[%s] = %d, expected %d", icaoType, got[icaoType], size)
+				}
+			}
+		})
+	}
+}
+
+func TestBundledFleetSizes(t *testing.T) {
+	entries, err := ValidateDataFile(FleetSizeFile, filepath.Join("../..", BundledDataDir, FleetSizeFile))
+	if err != nil {
+		t.Fatalf("ValidateDataFile() error = %v", err)
+	}
+	if entries == 0 {
+		t.Error("bundled fleet sizes are empty")
+	}
+}
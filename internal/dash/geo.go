@@ -83,3 +83,19 @@ func Distance(p, q Coordinates) DistanceStruct {
 
 	return newDistanceStruct(c)
 }
+
+// Destination returns where one ends up going the given distance in [km] from p along the great
+// circle of the given initial bearing in [degrees].
+func Destination(p Coordinates, bearing float64, kilometers float64) Coordinates {
+	from := p.toRadians()
+	theta := degreesToRadian(bearing)
+	delta := kilometers / earthRadiusKilometers
+
+	lat := math.Asin(math.Sin(from.Latitude)*math.Cos(delta) +
+		math.Cos(from.Latitude)*math.Sin(delta)*math.Cos(theta))
+	lon := from.Longitude + math.Atan2(
+		math.Sin(theta)*math.Sin(delta)*math.Cos(from.Latitude),
+		math.Cos(delta)-math.Sin(from.Latitude)*math.Sin(lat))
+
+	return NewCoordinates(lat/piHalf, math.Remainder(lon/piHalf, 360)) //nolint:mnd // full circle
+}
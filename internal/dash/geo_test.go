@@ -71,3 +71,30 @@ func TestHaversineDistance(t *testing.T) {
 		}
 	}
 }
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name       string
+		bearing    float64
+		kilometers float64
+	}{
+		{name: "north", bearing: 0, kilometers: 100},
+		{name: "east", bearing: 90, kilometers: 25},
+		{name: "south west", bearing: 225, kilometers: 400},
+		{name: "nowhere", bearing: 123, kilometers: 0},
+	}
+	hamburg := NewCoordinates(53.5511, 9.9937)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			destination := Destination(hamburg, test.bearing, test.kilometers)
+			// The destination is as far away as asked.
+			if kilometers := Distance(hamburg, destination).Kilometers(); math.Abs(kilometers-test.kilometers) > 1e-6 {
+				t.Errorf("Destination() is %v km away, expected %v km", kilometers, test.kilometers)
+			}
+		})
+	}
+	north := Destination(hamburg, 0, 111.2)
+	if north.Latitude <= hamburg.Latitude || math.Abs(north.Longitude-hamburg.Longitude) > 1e-9 {
+		t.Errorf("Destination() north = %v, expected due north of %v", north, hamburg)
+	}
+}
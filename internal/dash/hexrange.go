@@ -0,0 +1,89 @@
+package dash
+
+import (
+	"sort"
+)
+
+// hexSegment is a part of the hex address space which belongs to a single range.
+type hexSegment struct {
+	lowerBound int64
+	upperBound int64
+	country    string
+}
+
+// HexRangeIndex finds the country of a hex address with a binary search.
+// The ranges may be nested, e.g. a country within a block reserved for a region, in which case
+// the narrowest range containing the address wins. To make that a single search, the ranges are
+// flattened into non-overlapping segments when the index is built.
+type HexRangeIndex struct {
+	segments []hexSegment // segments are sorted by bounds and don't overlap.
+}
+
+// NewHexRangeIndex builds the index of the given hex ranges, whose bounds are inclusive.
+func NewHexRangeIndex(hexRangeToCountry map[HexRange]string) *HexRangeIndex {
+	ranges := make([]hexSegment, 0, len(hexRangeToCountry))
+	boundaries := make([]int64, 0, 2*len(hexRangeToCountry)) //nolint:mnd // two per range
+	for hexRange, country := range hexRangeToCountry {
+		if hexRange.UpperBound < hexRange.LowerBound {
+			continue
+		}
+		ranges = append(ranges, hexSegment{hexRange.LowerBound, hexRange.UpperBound, country})
+		boundaries = append(boundaries, hexRange.LowerBound, hexRange.UpperBound+1)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	// Every pair of consecutive boundaries delimits a part of the address space which is covered
+	// by the same ranges, of which the narrowest one is taken.
+	var segments []hexSegment
+	for idx := 0; idx+1 < len(boundaries); idx++ {
+		lower, upper := boundaries[idx], boundaries[idx+1]-1
+		if upper < lower {
+			continue // duplicate boundary
+		}
+
+		narrowest := -1
+		for rangeIdx, candidate := range ranges {
+			if candidate.lowerBound > lower || candidate.upperBound < upper {
+				continue
+			}
+			if narrowest < 0 || isNarrower(candidate, ranges[narrowest]) {
+				narrowest = rangeIdx
+			}
+		}
+		if narrowest < 0 {
+			continue // gap between ranges
+		}
+
+		country := ranges[narrowest].country
+		if last := len(segments) - 1; last >= 0 &&
+			segments[last].upperBound+1 == lower &&
+			segments[last].country == country {
+			segments[last].upperBound = upper
+			continue
+		}
+		segments = append(segments, hexSegment{lower, upper, country})
+	}
+
+	return &HexRangeIndex{segments: segments}
+}
+
+// isNarrower tells whether range a is narrower than range b, breaking ties by name so that the
+// index doesn't depend on the order of the map.
+func isNarrower(a hexSegment, b hexSegment) bool {
+	widthA, widthB := a.upperBound-a.lowerBound, b.upperBound-b.lowerBound
+	if widthA != widthB {
+		return widthA < widthB
+	}
+	return a.country < b.country
+}
+
+// Lookup returns the country of the narrowest range containing the hex address.
+func (index *HexRangeIndex) Lookup(hex int64) (string, bool) {
+	idx := sort.Search(len(index.segments), func(i int) bool {
+		return index.segments[i].upperBound >= hex
+	})
+	if idx == len(index.segments) || index.segments[idx].lowerBound > hex {
+		return "", false
+	}
+	return index.segments[idx].country, true
+}
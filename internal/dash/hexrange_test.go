@@ -0,0 +1,104 @@
+package dash
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestHexRangeIndex(t *testing.T) {
+	index := NewHexRangeIndex(map[HexRange]string{
+		{LowerBound: 0x000000, UpperBound: 0x003FFF}: "(unallocated)",
+		{LowerBound: 0x008000, UpperBound: 0x00FFFF}: "South Africa",
+		{LowerBound: 0x500000, UpperBound: 0x5FFFFF}: "(reserved, EUR/NAT)",
+		{LowerBound: 0x500000, UpperBound: 0x5003FF}: "San Marino",
+		{LowerBound: 0x501000, UpperBound: 0x5013FF}: "Albania",
+	})
+
+	tests := []struct {
+		name    string
+		hex     int64
+		country string
+		found   bool
+	}{
+		{name: "lower bound", hex: 0x008000, country: "South Africa", found: true},
+		{name: "upper bound", hex: 0x00FFFF, country: "South Africa", found: true},
+		{name: "first address", hex: 0x000000, country: "(unallocated)", found: true},
+		{name: "gap", hex: 0x004000, country: "", found: false},
+		{name: "beyond all ranges", hex: 0xFFFFFF, country: "", found: false},
+		{name: "nested at shared lower bound", hex: 0x500000, country: "San Marino", found: true},
+		{name: "nested in the middle", hex: 0x501234, country: "Albania", found: true},
+		{name: "between nested ranges", hex: 0x500400, country: "(reserved, EUR/NAT)", found: true},
+		{name: "after nested ranges", hex: 0x501400, country: "(reserved, EUR/NAT)", found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			country, found := index.Lookup(tt.hex)
+			if country != tt.country || found != tt.found {
+				t.Errorf("Lookup(%06X) = %q, %v, expected %q, %v",
+					tt.hex, country, found, tt.country, tt.found)
+			}
+		})
+	}
+}
+
+// lookupLinear is the straightforward lookup which the index replaces, for comparison.
+func lookupLinear(hexRangeToCountry map[HexRange]string, hex int64) (string, bool) {
+	country, width, found := "", int64(0), false
+	for hexRange, candidate := range hexRangeToCountry {
+		if hex < hexRange.LowerBound || hex > hexRange.UpperBound {
+			continue
+		}
+		if !found || hexRange.UpperBound-hexRange.LowerBound < width {
+			country, width, found = candidate, hexRange.UpperBound-hexRange.LowerBound, true
+		}
+	}
+	return country, found
+}
+
+func loadBundledHexRanges(tb testing.TB) map[HexRange]string {
+	tb.Helper()
+	hexRanges, err := parseHexRangeCsvToMap(filepath.Join("../..", BundledDataDir, hexRangeListFile))
+	if err != nil {
+		tb.Fatalf("parseHexRangeCsvToMap() error = %v", err)
+	}
+	return hexRanges
+}
+
+func TestHexRangeIndexMatchesLinearScan(t *testing.T) {
+	hexRanges := loadBundledHexRanges(t)
+	index := NewHexRangeIndex(hexRanges)
+	for hex := int64(0); hex <= 0xFFFFFF; hex += 0x155 {
+		country, found := index.Lookup(hex)
+		expectedCountry, expectedFound := lookupLinear(hexRanges, hex)
+		if country != expectedCountry || found != expectedFound {
+			t.Fatalf("Lookup(%06X) = %q, %v, linear scan found %q, %v",
+				hex, country, found, expectedCountry, expectedFound)
+		}
+	}
+}
+
+func BenchmarkHexRangeIndexLookup(b *testing.B) {
+	index := NewHexRangeIndex(loadBundledHexRanges(b))
+	hex := int64(0)
+	for b.Loop() {
+		index.Lookup(hex)
+		hex = (hex + 0x1F3) & 0xFFFFFF
+	}
+}
+
+func BenchmarkHexRangeLinearLookup(b *testing.B) {
+	hexRanges := loadBundledHexRanges(b)
+	hex := int64(0)
+	for b.Loop() {
+		lookupLinear(hexRanges, hex)
+		hex = (hex + 0x1F3) & 0xFFFFFF
+	}
+}
+
+func BenchmarkNewHexRangeIndex(b *testing.B) {
+	hexRanges := loadBundledHexRanges(b)
+	for b.Loop() {
+		NewHexRangeIndex(hexRanges)
+	}
+}
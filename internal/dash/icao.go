@@ -6,23 +6,35 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 const (
-	icaoListPath      = "./data/ICAOList.csv"
-	airlineListPath   = "./data/Airlines.csv"
-	regPrefixListPath = "./data/RegPrefixList.csv"
-	hexRangeListPath  = "./data/ICAOHexRange.csv"
-	milCodeFilePath   = "./data/MilICAOOperatorLookUp.csv"
-	milCodeHeaderLen  = 2
+	// BundledDataDir is where the datasets shipped with airspottr are.
+	BundledDataDir = "./data"
+
+	// Files of the datasets. The aircraft types, airlines and military codes can be updated.
+	IcaoListFile      = "ICAOList.csv"
+	AirlineListFile   = "Airlines.csv"
+	MilCodeFile       = "MilICAOOperatorLookUp.csv"
+	regPrefixListFile = "RegPrefixList.csv"
+	hexRangeListFile  = "ICAOHexRange.csv"
+	iataAirlineFile   = "IataAirlines.csv"
+
+	milCodeHeaderLen     = 2
+	iataAirlineHeaderLen = 2
+	iataCodeLen          = 2
 )
 
 var (
-	errParseCSV  = errors.New("error parsing CSV")
-	errHeaderLen = errors.New("unexpected header length")
-	errParseHex  = errors.New("unable to parse hexadecimal string")
+	errParseCSV    = errors.New("error parsing CSV")
+	errUnknownFile = errors.New("unknown dataset file")
+	errNoEntries   = errors.New("dataset without entries")
+	errHeaderLen   = errors.New("unexpected header length")
+	errHeaderName  = errors.New("unexpected header")
+	errParseHex    = errors.New("unable to parse hexadecimal string")
 )
 
 type IcaoAircraft struct {
@@ -32,9 +44,9 @@ type IcaoAircraft struct {
 }
 
 // GetIcaoToAircraftMap returns an ICAO id to aircraft record mapping.
-func GetIcaoToAircraftMap() (map[string]IcaoAircraft, error) {
+func GetIcaoToAircraftMap(dataDirs []string) (map[string]IcaoAircraft, error) {
 	// Parse the CSV file
-	icaoAircraftMap, err := parseIcaoCsvToMap(icaoListPath)
+	icaoAircraftMap, err := parseIcaoCsvToMap(FindDataFile(dataDirs, IcaoListFile))
 	if err != nil {
 		return nil, fmt.Errorf("getIcaoToAircraftMap: %w: %w", errParseCSV, err)
 	}
@@ -105,9 +117,9 @@ type IcaoOperator struct {
 }
 
 // GetIcaoToAirlineMap returns a three-letter code to airline record mapping.
-func GetIcaoToAirlineMap() (map[string]IcaoOperator, error) {
+func GetIcaoToAirlineMap(dataDirs []string) (map[string]IcaoOperator, error) {
 	// Parse the CSV file
-	icaoAirlineMap, err := parseAirlineCsvToMap(airlineListPath)
+	icaoAirlineMap, err := parseAirlineCsvToMap(FindDataFile(dataDirs, AirlineListFile))
 	if err != nil {
 		return nil, fmt.Errorf("getIcaoToAirlineMap: %w: %w", errParseCSV, err)
 	}
@@ -160,6 +172,9 @@ func parseAirlineCsvToMap(filePath string) (map[string]IcaoOperator, error) {
 		company := record[0]
 		country := record[1]
 		// skipping telephony, record[2] is unused
+		if len(record[3]) < 3 { //nolint:mnd // three-letter code
+			continue // not a three-letter code, e.g. in a broken download
+		}
 		threeLtrCode := record[3][0:3]
 		records[threeLtrCode] = IcaoOperator{company, country}
 	}
@@ -173,9 +188,9 @@ type HexRange struct {
 }
 
 // GetHexRangeToCountryMap returns a hex registration range to country mapping.
-func GetHexRangeToCountryMap() (map[HexRange]string, error) {
+func GetHexRangeToCountryMap(dataDirs []string) (map[HexRange]string, error) {
 	// Parse the CSV file
-	hexRangeMap, err := parseHexRangeCsvToMap(hexRangeListPath)
+	hexRangeMap, err := parseHexRangeCsvToMap(FindDataFile(dataDirs, hexRangeListFile))
 	if err != nil {
 		return nil, fmt.Errorf("getRegPrefixMap: %w: %w", errParseCSV, err)
 	}
@@ -231,9 +246,9 @@ func parseHexRangeCsvToMap(filePath string) (map[HexRange]string, error) {
 }
 
 // GetRegPrefixMap returns a registration prefix to country mapping.
-func GetRegPrefixMap() (map[string]string, error) {
+func GetRegPrefixMap(dataDirs []string) (map[string]string, error) {
 	// Parse the CSV file
-	regPrefixMap, err := parseRegPrefixCsvToMap(regPrefixListPath)
+	regPrefixMap, err := parseRegPrefixCsvToMap(FindDataFile(dataDirs, regPrefixListFile))
 	if err != nil {
 		return nil, fmt.Errorf("getRegPrefixMap: %w: %w", errParseCSV, err)
 	}
@@ -286,6 +301,11 @@ func parseRegPrefixCsvToMap(filePath string) (map[string]string, error) {
 		country := record[0]
 		prefix := record[1]
 		// skipping comment, record[2] is unused
+		// Some prefixes are shared, e.g. "OY-" by Denmark, the Faroe Islands and Greenland.
+		// The country listed first is the one the prefix is allocated to.
+		if _, exists := records[prefix]; exists {
+			continue
+		}
 		records[prefix] = country
 	}
 
@@ -293,9 +313,9 @@ func parseRegPrefixCsvToMap(filePath string) (map[string]string, error) {
 }
 
 // GetMilCodeToOperatorMap returns a military code to operator mapping.
-func GetMilCodeToOperatorMap() (map[string]string, error) {
+func GetMilCodeToOperatorMap(dataDirs []string) (map[string]string, error) {
 	// Parse the CSV file
-	icaoAircraftMap, err := parseMilCodeToMap(milCodeFilePath)
+	icaoAircraftMap, err := parseMilCodeToMap(FindDataFile(dataDirs, MilCodeFile))
 	if err != nil {
 		return nil, fmt.Errorf("milCodeFilePath: %w", err)
 	}
@@ -355,3 +375,159 @@ func parseMilCodeToMap(filePath string) (map[string]string, error) {
 
 	return records, nil
 }
+
+// GetIataToIcaoAirlineMap returns a mapping of the two-letter IATA codes of airlines to their
+// three-letter ICAO codes, for callsigns which use the IATA code, e.g. "LH400".
+func GetIataToIcaoAirlineMap(dataDirs []string) (map[string]string, error) {
+	iataToIcao, err := parseIataCsvToMap(FindDataFile(dataDirs, iataAirlineFile))
+	if err != nil {
+		return nil, fmt.Errorf("GetIataToIcaoAirlineMap: %w", err)
+	}
+
+	return iataToIcao, nil
+}
+
+// parseIataCsvToMap reads a CSV file and parses it into a map IATA code -> ICAO code.
+func parseIataCsvToMap(filePath string) (map[string]string, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseIataCsvToMap: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("parseIataCsvToMap: failed to read headers: %w", headerErr)
+	}
+	if len(headers) != iataAirlineHeaderLen {
+		return nil, fmt.Errorf("parseIataCsvToMap: %w", errHeaderLen)
+	}
+
+	records := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parseIataCsvToMap: failed to read record: %w", err)
+		}
+
+		iata := strings.ToUpper(strings.TrimSpace(record[0]))
+		icao := strings.ToUpper(strings.TrimSpace(record[1]))
+		if len(iata) != iataCodeLen || icao == "" {
+			continue
+		}
+		records[iata] = icao
+	}
+
+	return records, nil
+}
+
+// FindDataFile returns the path of the dataset file in the first of the directories which has it,
+// so that updated datasets take precedence over the bundled ones. If none of them has it, the
+// path in the last directory is returned, for the error when opening it to tell where it was
+// expected. Without directories, the bundled datasets are used.
+func FindDataFile(dataDirs []string, file string) string {
+	if len(dataDirs) == 0 {
+		return filepath.Join(BundledDataDir, file)
+	}
+	for _, dir := range dataDirs {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dataDirs[len(dataDirs)-1], file)
+}
+
+// ValidateDataFile parses the file at the path as the given dataset file and returns how many
+// entries it has. Datasets without any entries, or with the header of another dataset, are invalid.
+func ValidateDataFile(file string, path string) (int, error) {
+	firstHeader, known := dataFileFirstHeader[file]
+	if !known {
+		return 0, fmt.Errorf("ValidateDataFile: %w: %s", errUnknownFile, file)
+	}
+	if err := checkFirstHeader(path, firstHeader); err != nil {
+		return 0, fmt.Errorf("ValidateDataFile: %s: %w", file, err)
+	}
+
+	var entries int
+	var err error
+	switch file {
+	case IcaoListFile:
+		var records map[string]IcaoAircraft
+		records, err = parseIcaoCsvToMap(path)
+		entries = len(records)
+	case AirlineListFile:
+		var records map[string]IcaoOperator
+		records, err = parseAirlineCsvToMap(path)
+		entries = len(records)
+	case MilCodeFile:
+		var records map[string]string
+		records, err = parseMilCodeToMap(path)
+		entries = len(records)
+	case FleetSizeFile:
+		var records map[string]int
+		records, err = parseFleetSizeCsvToMap(path)
+		entries = len(records)
+	case TypeFamilyFile:
+		var records map[string]string
+		records, err = parseTypeFamilyCsvToMap(path)
+		entries = len(records)
+	case NotableAircraftFile:
+		var records map[string]NotableAircraft
+		records, err = parseNotableAircraftCsvToMap(path)
+		entries = len(records)
+	case AirportsFile:
+		var records []Airport
+		records, err = parseAirportsCsv(path)
+		entries = len(records)
+	default:
+		return 0, fmt.Errorf("ValidateDataFile: %w: %s", errUnknownFile, file)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("ValidateDataFile: %s: %w", file, err)
+	}
+	if entries == 0 {
+		return 0, fmt.Errorf("ValidateDataFile: %s: %w", file, errNoEntries)
+	}
+	return entries, nil
+}
+
+// dataFileFirstHeader is the first column of the header of the datasets which can be validated,
+// since the datasets differ too little in their number of columns to tell them apart.
+var dataFileFirstHeader = map[string]string{ //nolint:gochecknoglobals // constant lookup
+	IcaoListFile:        "Aircraft TypeDesignator",
+	AirlineListFile:     "Company",
+	MilCodeFile:         "RegisteredOwner",
+	FleetSizeFile:       "Aircraft TypeDesignator",
+	TypeFamilyFile:      "Aircraft TypeDesignator",
+	NotableAircraftFile: "Hex",
+	AirportsFile:        "ICAO",
+}
+
+// checkFirstHeader checks the first column of the header of the CSV file at the path.
+func checkFirstHeader(path string, expected string) error {
+	file, fileErr := os.Open(path)
+	if fileErr != nil {
+		return fmt.Errorf("checkFirstHeader: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	headers, headerErr := csv.NewReader(file).Read()
+	if headerErr != nil {
+		return fmt.Errorf("checkFirstHeader: %w", headerErr)
+	}
+	actual := strings.TrimPrefix(strings.TrimSpace(headers[0]), "\ufeff")
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checkFirstHeader: %w %q instead of %q", errHeaderName, actual, expected)
+	}
+	return nil
+}
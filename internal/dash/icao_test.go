@@ -0,0 +1,88 @@
+package dash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDataFile(t *testing.T) {
+	updated := t.TempDir()
+	if err := os.WriteFile(filepath.Join(updated, IcaoListFile), []byte{}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dataDirs := []string{updated, BundledDataDir}
+
+	tests := []struct {
+		name     string
+		dataDirs []string
+		file     string
+		want     string
+	}{
+		{"updated", dataDirs, IcaoListFile, filepath.Join(updated, IcaoListFile)},
+		{"bundled", dataDirs, AirlineListFile, filepath.Join(BundledDataDir, AirlineListFile)},
+		{"no dirs", nil, IcaoListFile, filepath.Join(BundledDataDir, IcaoListFile)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindDataFile(tt.dataDirs, tt.file); got != tt.want {
+				t.Errorf("FindDataFile() = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDataFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		csv     string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "types",
+			file:    IcaoListFile,
+			csv:     "Aircraft TypeDesignator,Class,Number+Engine Type,\"MANUFACTURER, Model\"\nA388,LandPlane,4/Jet,\"AIRBUS, A-380-800\"\n",
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name:    "airlines as types",
+			file:    IcaoListFile,
+			csv:     "Company,country,Telephony,3Ltr\nLUFTHANSA,GERMANY,LUFTHANSA,DLH\n",
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:    "military without entries",
+			file:    MilCodeFile,
+			csv:     "RegisteredOwner,ICAOOperatorCode\n",
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:    "not updatable",
+			file:    regPrefixListFile,
+			csv:     "country,Registration prefix,comment\nGermany,D-,\n",
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.csv), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ValidateDataFile(tt.file, path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDataFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateDataFile() = %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package dash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// NotableAircraftFile lists notable airframes by their hex address, e.g. head-of-state transports,
+	// test aircraft and famous airframes. It is optional, without it no aircraft is notable.
+	NotableAircraftFile      = "NotableAircraft.csv"
+	notableAircraftHeaderLen = 4
+)
+
+var errEmptyNotableDescription = errors.New("empty description of notable aircraft")
+
+// NotableAircraft is an airframe which is worth a look regardless of how rare its type is.
+type NotableAircraft struct {
+	Registration string
+	Category     string // Category is what makes it notable, e.g. "head of state" or "test".
+	Description  string
+}
+
+// GetNotableAircraftMap returns a hex address to notable aircraft mapping, with the hex addresses
+// in lower case like those of the aircraft reports.
+func GetNotableAircraftMap(dataDirs []string) (map[string]NotableAircraft, error) {
+	notableMap, err := parseNotableAircraftCsvToMap(FindDataFile(dataDirs, NotableAircraftFile))
+	if err != nil {
+		return nil, fmt.Errorf("GetNotableAircraftMap: %w: %w", errParseCSV, err)
+	}
+
+	return notableMap, nil
+}
+
+// parseNotableAircraftCsvToMap reads a CSV file and parses it into a map hex -> notable aircraft.
+func parseNotableAircraftCsvToMap(filePath string) (map[string]NotableAircraft, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseNotableAircraftCsvToMap: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readNotableAircraft(file)
+}
+
+// readNotableAircraft parses notable aircraft with the headers hex, registration, category,
+// description.
+func readNotableAircraft(input io.Reader) (map[string]NotableAircraft, error) {
+	reader := csv.NewReader(input)
+
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("readNotableAircraft: failed to read header: %w", headerErr)
+	}
+	if len(headers) != notableAircraftHeaderLen {
+		return nil, fmt.Errorf("readNotableAircraft: %w", errHeaderLen)
+	}
+
+	records := make(map[string]NotableAircraft)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readNotableAircraft: failed to read record: %w", err)
+		}
+
+		description := strings.TrimSpace(record[3])
+		if description == "" {
+			return nil, fmt.Errorf("readNotableAircraft: %w %s", errEmptyNotableDescription, record[0])
+		}
+		records[strings.ToLower(strings.TrimSpace(record[0]))] = NotableAircraft{
+			Registration: strings.TrimSpace(record[1]),
+			Category:     strings.TrimSpace(record[2]),
+			Description:  description,
+		}
+	}
+
+	return records, nil
+}
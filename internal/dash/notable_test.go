@@ -0,0 +1,61 @@
+package dash
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadNotableAircraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    map[string]NotableAircraft
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			csv:  "hex,registration,category,description\n ADFDF8 ,82-8000, head of state ,VC-25A\n",
+			want: map[string]NotableAircraft{
+				"adfdf8": {Registration: "82-8000", Category: "head of state", Description: "VC-25A"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "wrong header",
+			csv:     "hex,description\nadfdf8,VC-25A\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty description",
+			csv:     "hex,registration,category,description\nadfdf8,82-8000,head of state, \n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readNotableAircraft(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readNotableAircraft() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for hex, notable := range tt.want {
+				if got[hex] != notable {
+					t.Errorf("readNotableAircraft()[%s] = %+v, expected %+v", hex, got[hex], notable)
+				}
+			}
+		})
+	}
+}
+
+func TestBundledNotableAircraft(t *testing.T) {
+	notable, err := parseNotableAircraftCsvToMap(filepath.Join("../..", BundledDataDir, NotableAircraftFile))
+	if err != nil {
+		t.Fatalf("parseNotableAircraftCsvToMap() error = %v", err)
+	}
+	if notable["adfdf8"].Category != "head of state" {
+		t.Errorf("adfdf8 = %+v, expected the head-of-state transport", notable["adfdf8"])
+	}
+}
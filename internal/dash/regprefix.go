@@ -0,0 +1,72 @@
+package dash
+
+import (
+	"strings"
+)
+
+// regPrefixNode is a node of the RegPrefixTrie, i.e. the registration prefix spelled out by the
+// path from the root.
+type regPrefixNode struct {
+	children map[rune]*regPrefixNode
+	country  string // country is the country of the prefix, empty if the prefix isn't one.
+}
+
+// RegPrefixTrie finds the country of a registration by its longest matching prefix, so that e.g.
+// "CC-" (Chile) wins over "C-" (Canada) and "VP-B" (Bermuda) needs all four characters to match.
+// Prefixes include the dash if there is one, e.g. "9V-", which keeps "N" (United States) from
+// matching anything but registrations which start with "N".
+type RegPrefixTrie struct {
+	root *regPrefixNode
+}
+
+// NewRegPrefixTrie builds the trie of the given registration prefixes.
+func NewRegPrefixTrie(regPrefixToCountry map[string]string) *RegPrefixTrie {
+	trie := &RegPrefixTrie{root: newRegPrefixNode()}
+	for prefix, country := range regPrefixToCountry {
+		trie.insert(prefix, country)
+	}
+	return trie
+}
+
+func newRegPrefixNode() *regPrefixNode {
+	return &regPrefixNode{children: make(map[rune]*regPrefixNode), country: ""}
+}
+
+func (trie *RegPrefixTrie) insert(prefix string, country string) {
+	prefix = normaliseRegistration(prefix)
+	if prefix == "" {
+		return
+	}
+
+	node := trie.root
+	for _, char := range prefix {
+		child, ok := node.children[char]
+		if !ok {
+			child = newRegPrefixNode()
+			node.children[char] = child
+		}
+		node = child
+	}
+	node.country = country
+}
+
+// Lookup returns the country of the longest prefix of the registration.
+func (trie *RegPrefixTrie) Lookup(registration string) (string, bool) {
+	country := ""
+	node := trie.root
+	for _, char := range normaliseRegistration(registration) {
+		child, ok := node.children[char]
+		if !ok {
+			break
+		}
+		node = child
+		if node.country != "" {
+			country = node.country
+		}
+	}
+	return country, country != ""
+}
+
+func normaliseRegistration(registration string) string {
+	return strings.ToUpper(strings.TrimSpace(registration))
+}
@@ -0,0 +1,80 @@
+package dash
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRegPrefixTrie(t *testing.T) {
+	trie := NewRegPrefixTrie(map[string]string{
+		"N":    "United States",
+		"9V-":  "Singapore",
+		"9M-":  "Malaysia",
+		"C-":   "Canada",
+		"CC-":  "Chile",
+		"C6-":  "Bahamas",
+		"D-":   "Germany",
+		"F-":   "France",
+		"F-O":  "French Guiana",
+		"F-OG": "Guadeloupe",
+		"VP-B": "Bermuda",
+		"YA":   "Afghanistan",
+	})
+
+	tests := []struct {
+		name         string
+		registration string
+		country      string
+		found        bool
+	}{
+		{name: "N without dash", registration: "N12345", country: "United States", found: true},
+		{name: "N within other registration", registration: "D-AINA", country: "Germany", found: true},
+		{name: "digit prefix", registration: "9V-SKA", country: "Singapore", found: true},
+		{name: "digit prefix sibling", registration: "9M-MNA", country: "Malaysia", found: true},
+		{name: "single letter prefix", registration: "C-FABC", country: "Canada", found: true},
+		{name: "double letter prefix", registration: "CC-BGA", country: "Chile", found: true},
+		{name: "letter digit prefix", registration: "C6-BFW", country: "Bahamas", found: true},
+		{name: "longest prefix", registration: "F-OGUA", country: "Guadeloupe", found: true},
+		{name: "shorter prefix", registration: "F-ONET", country: "French Guiana", found: true},
+		{name: "shortest prefix", registration: "F-GKXA", country: "France", found: true},
+		{name: "prefix after dash", registration: "VP-BDL", country: "Bermuda", found: true},
+		{name: "incomplete prefix", registration: "VP-CAB", country: "", found: false},
+		{name: "lower case and spaces", registration: " d-aibl ", country: "Germany", found: true},
+		{name: "unknown prefix", registration: "Z-WPA", country: "", found: false},
+		{name: "prefix only matches at start", registration: "XC-N9V", country: "", found: false},
+		{name: "empty", registration: "", country: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			country, found := trie.Lookup(tt.registration)
+			if country != tt.country || found != tt.found {
+				t.Errorf("Lookup(%q) = %q, %v, expected %q, %v",
+					tt.registration, country, found, tt.country, tt.found)
+			}
+		})
+	}
+}
+
+func TestBundledRegPrefixes(t *testing.T) {
+	regPrefixes, err := parseRegPrefixCsvToMap(filepath.Join("../..", BundledDataDir, regPrefixListFile))
+	if err != nil {
+		t.Fatalf("parseRegPrefixCsvToMap() error = %v", err)
+	}
+	trie := NewRegPrefixTrie(regPrefixes)
+
+	expected := map[string]string{
+		"N123AB": "United States",
+		"9V-SKA": "Singapore",
+		"C-FABC": "Canada",
+		"CC-BGA": "Chile",
+		"D-AIBL": "Germany",
+		"OY-KBA": "Denmark",
+		"VP-BDL": "Bermuda",
+	}
+	for registration, expectedCountry := range expected {
+		if country, _ := trie.Lookup(registration); country != expectedCountry {
+			t.Errorf("Lookup(%q) = %q, expected %q", registration, country, expectedCountry)
+		}
+	}
+}
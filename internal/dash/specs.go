@@ -0,0 +1,105 @@
+package dash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	typeSpecListFile  = "TypeSpecs.csv"
+	typeSpecHeaderLen = 5
+)
+
+var errParseSpec = errors.New("unable to parse aircraft spec")
+
+// TypeSpec holds the basic specs of an aircraft type.
+type TypeSpec struct {
+	Wingspan float64 // Wingspan in [m], the rotor diameter for helicopters.
+	Length   float64 // Length in [m].
+	MTOW     int     // MTOW is the maximum take-off weight in [kg].
+	Cruise   int     // Cruise is the typical cruise speed in [knots].
+}
+
+// GetTypeSpecMap returns an ICAO type designator to aircraft spec mapping.
+func GetTypeSpecMap(dataDirs []string) (map[string]TypeSpec, error) {
+	typeSpecMap, err := parseTypeSpecCsvToMap(FindDataFile(dataDirs, typeSpecListFile))
+	if err != nil {
+		return nil, fmt.Errorf("getTypeSpecMap: %w: %w", errParseCSV, err)
+	}
+
+	return typeSpecMap, nil
+}
+
+// parseTypeSpecCsvToMap reads a CSV file and parses it into a map ICAO type -> spec.
+func parseTypeSpecCsvToMap(filePath string) (map[string]TypeSpec, error) {
+	file, fileErr := os.Open(filePath)
+	if fileErr != nil {
+		return nil, fmt.Errorf("parseTypeSpecCsvToMap: failed to open file: %w", fileErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readTypeSpecs(file)
+}
+
+// readTypeSpecs parses type specs with the headers
+// type designator, wingspan [m], length [m], MTOW [kg], typical cruise [kt].
+func readTypeSpecs(input io.Reader) (map[string]TypeSpec, error) {
+	reader := csv.NewReader(input)
+
+	headers, headerErr := reader.Read()
+	if headerErr != nil {
+		return nil, fmt.Errorf("readTypeSpecs: failed to read header: %w", headerErr)
+	}
+	if len(headers) != typeSpecHeaderLen {
+		return nil, fmt.Errorf("readTypeSpecs: %w", errHeaderLen)
+	}
+
+	records := make(map[string]TypeSpec)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readTypeSpecs: failed to read record: %w", err)
+		}
+
+		wingspan, wingspanErr := strconv.ParseFloat(record[1], 64)
+		length, lengthErr := strconv.ParseFloat(record[2], 64)
+		mtow, mtowErr := strconv.Atoi(record[3])
+		cruise, cruiseErr := strconv.Atoi(record[4])
+		if err := errors.Join(wingspanErr, lengthErr, mtowErr, cruiseErr); err != nil {
+			return nil, fmt.Errorf("readTypeSpecs: %w of %s: %w", errParseSpec, record[0], err)
+		}
+		records[record[0]] = TypeSpec{Wingspan: wingspan, Length: length, MTOW: mtow, Cruise: cruise}
+	}
+
+	return records, nil
+}
+
+// String formats the specs as a single line, e.g. "35.8 m span, 39.5 m long, 79010 kg MTOW,
+// cruise 453 kt".
+func (spec TypeSpec) String() string {
+	return fmt.Sprintf(
+		"%.1f m span, %.1f m long, %d kg MTOW, cruise %d kt",
+		spec.Wingspan,
+		spec.Length,
+		spec.MTOW,
+		spec.Cruise)
+}
+
+// ThreeViewLink returns a link to look up drawings of the given aircraft model, as listed in the
+// ICAO type list, e.g. "BOEING, 737 MAX 8". Encyclopedia articles on aircraft types usually include
+// a three-view drawing.
+func ThreeViewLink(model string) string {
+	query := strings.Join(strings.Fields(strings.ReplaceAll(model, ",", " ")), " ")
+	return "https://en.wikipedia.org/w/index.php?search=" + url.QueryEscape(query+" three-view")
+}
@@ -0,0 +1,71 @@
+package dash
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTypeSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    map[string]TypeSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			csv:  "type,span,length,mtow,cruise\nB738,35.8,39.5,79010,453\n",
+			want: map[string]TypeSpec{
+				"B738": {Wingspan: 35.8, Length: 39.5, MTOW: 79010, Cruise: 453},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "wrong header",
+			csv:     "type,span\nB738,35.8\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid number",
+			csv:     "type,span,length,mtow,cruise\nB738,wide,39.5,79010,453\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTypeSpecs(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readTypeSpecs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for icaoType, spec := range tt.want {
+				if got[icaoType] != spec {
+					t.Errorf("readTypeSpecs()[%s] = %+v, expected %+v", icaoType, got[icaoType], spec)
+				}
+			}
+		})
+	}
+}
+
+func TestBundledTypeSpecs(t *testing.T) {
+	specs, err := parseTypeSpecCsvToMap(filepath.Join("../..", BundledDataDir, typeSpecListFile))
+	if err != nil {
+		t.Fatalf("parseTypeSpecCsvToMap() error = %v", err)
+	}
+	for icaoType, spec := range specs {
+		if spec.Wingspan <= 0 || spec.Length <= 0 || spec.MTOW <= 0 || spec.Cruise <= 0 {
+			t.Errorf("spec of %s is incomplete: %+v", icaoType, spec)
+		}
+	}
+}
+
+func TestThreeViewLink(t *testing.T) {
+	link := ThreeViewLink("BOEING, 737 MAX 8")
+	expected := "https://en.wikipedia.org/w/index.php?search=BOEING+737+MAX+8+three-view"
+	if link != expected {
+		t.Errorf("ThreeViewLink() = %q, expected %q", link, expected)
+	}
+}
@@ -7,12 +7,15 @@ import (
 	"io"
 	"log" //nolint:depguard // Don't feel like using slog
 	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/rules"
 )
 
 const (
@@ -33,91 +36,402 @@ const (
 	operatorUnknown = "unknown"
 	// countryUnknown is what we use for aircraft with a type that's either empty or can't be found.
 	countryUnknown = "unknown"
+	// photoRetryAfter is how long the photo of a registration isn't looked up again after the
+	// lookup failed, so that planespotters.net isn't asked on every poll while it's unreachable.
+	photoRetryAfter = 30 * time.Minute
 )
 
 // Errors used by the Dashboard.
 var (
 	errParseIcaoAircraftMap      = errors.New("failed to parse ICAO to aircraft map")
 	errParseIcaoAirlineMap       = errors.New("failed to parse ICAO to airline map")
+	errParseIataAirlineMap       = errors.New("failed to parse IATA to ICAO airline map")
 	errParseRegToCountryMap      = errors.New("failed to parse reg-prefix to country map")
 	errParseHexRangeToCountryMap = errors.New("failed to parse hex-range to country map")
 	errParseMilCodeMap           = errors.New("failed to parse mil code to operator map")
+	errParseTypeSpecMap          = errors.New("failed to parse type to spec map")
+	errParseFleetSizeMap         = errors.New("failed to parse type to fleet size map")
+	errParseTypeFamilyMap        = errors.New("failed to parse type to family map")
+	errParseNotableAircraftMap   = errors.New("failed to parse hex to notable aircraft map")
+	errParseAirports             = errors.New("failed to parse airports")
+	errCreateRarityScorer        = errors.New("failed to create rarity scorer")
+	errCompileRules              = errors.New("failed to compile alert rules")
+	errCompileAreas              = errors.New("failed to compile watch areas")
+	errLoadNotes                 = errors.New("failed to load notes")
+	errOpenSharedStore           = errors.New("failed to open shared store")
+	errLoadPeak                  = errors.New("failed to load peak")
+	errLoadRecords               = errors.New("failed to load records")
+	errOpenHistory               = errors.New("failed to open sighting history")
 )
 
+// DashboardOptions configures how the Dashboard evaluates sightings.
+type DashboardOptions struct {
+	RarityScorer string // RarityScorer is the name of the rarity scoring strategy.
+	// StatsHalfLife lets the seen-counts decay exponentially, so that the rarity model reflects
+	// recent sightings rather than the entire history. Zero disables decay.
+	StatsHalfLife time.Duration
+	Rules         []RuleConfig // Rules are custom alerts evaluated against every aircraft.
+	Areas         []AreaConfig // Areas are where arrivals and departures of aircraft are reported.
+	HistoryPath   string       // HistoryPath is where sightings are persisted, see OpenSightingStore.
+	NotesPath     string       // NotesPath is where notes on aircraft are kept, empty disables it.
+	PeakPath      string       // PeakPath is where the all-time peak is kept, empty disables it.
+	// RecordsPath is where the daily and all-time highest and fastest aircraft are kept, empty
+	// keeps them for the session only.
+	RecordsPath string
+	// DailyPath is the CSV file the count of distinct aircraft of every day is appended to, empty
+	// keeps the count for the session only.
+	DailyPath string
+	// FleetRareBelow makes types with fewer aircraft in service worldwide always rare, no matter
+	// how often they have been seen here. Zero disables it.
+	FleetRareBelow int
+	// MachAlert is the Mach number beyond which supersonic-capable aircraft are alerted, zero
+	// disables it.
+	MachAlert float64
+	// CirclingAlert is the distance in [km] within which aircraft flying circles or holding patterns
+	// are alerted, zero disables it.
+	CirclingAlert float64
+	// CompareScorer is the name of a rarity scorer to evaluate alongside the active one, to compare
+	// how many notifications each produces. Empty disables the comparison.
+	CompareScorer string
+	// Clock tells the time of sightings and schedules the updates, the SystemClock if nil.
+	Clock Clock
+	// LoadProgress is told about the progress of loading the datasets, if it isn't nil.
+	LoadProgress LoadProgress
+	// DataDir is where updated datasets are installed, empty uses only the bundled datasets.
+	DataDir string
+	// DayStartHour is the hour of the day at which the days of the daily statistics and reports
+	// start, e.g. 3 to count a night's sightings until 03:00 to the day before.
+	DayStartHour int
+	// SyncTarget is the SQLite database, PostgreSQL URL or sync endpoint URL where sightings are
+	// shared with other observers, to tell rarity against all of their sightings. Empty disables
+	// sharing.
+	SyncTarget string
+	SyncToken  string // SyncToken is sent to the sync endpoint as bearer token, if any.
+	Observer   string // Observer tells our sightings apart from those of others in the shared store.
+	// WarmupTypes and WarmupOperators are how many distinct types and operators have to be seen
+	// before rare sightings are reported. Without either, only FinishWarmupPeriod ends the warmup.
+	WarmupTypes     int
+	WarmupOperators int
+	// BaselineFromHistory counts the sightings of the history towards rarity, so that there's no
+	// need to warm up again if the history already makes a baseline.
+	BaselineFromHistory bool
+	// Tiers tag the aircraft by their distance, empty leaves them untagged.
+	Tiers ProximityTiers
+	// OperatorGroups roll up operators to the brands they belong to, empty leaves them on their own.
+	OperatorGroups OperatorGroups
+	// TypeRarity tells the rarity of every type on its own or of their families, see TypeRarityType
+	// and TypeRarityFamily. Empty is TypeRarityType.
+	TypeRarity string
+	// Follow is the aircraft to follow along its flight, nil if none.
+	Follow *FollowTarget
+	// Limits cap how much is kept in memory, none by default.
+	Limits Limits
+}
+
 type Dashboard struct {
-	isWarmup           bool
-	Lat                float64
-	Lon                float64
-	Fastest            *AircraftRecord
-	Highest            *AircraftRecord
-	CurrentAircraft    []AircraftRecord
-	RareSightings      []RareSighting
-	CachedFlightRoutes map[string]*FlightRouteRecord
-	aircraftSightings  map[string]AircraftSighting // set of all seen aircraft, maps hex to last seen time
-	totalTypeCount     int
-	totalOperatorCount int
-	totalCountryCount  int
-	SeenTypeCount      map[string]int // types mapped to how often seen
-	SeenOperatorCount  map[string]int // airlines mapped to how often seen
-	SeenCountryCount   map[string]int // airlines mapped to how often seen
-	IcaoToAircraft     map[string]dash.IcaoAircraft
-	IcaoToAirline      map[string]dash.IcaoOperator
-	regPrefixToCountry map[string]string
-	hexRangeToCountry  map[dash.HexRange]string
-	milCodeToOperator  map[string]string
-	errOut             log.Logger
-}
-
-func NewDashboard(lat float64, lon float64, stderr *io.Writer) (*Dashboard, error) {
+	isWarmup        bool       // isWarmup holds back rare sightings until there's a baseline.
+	warmupTypes     int        // warmupTypes is how many distinct types end the warmup.
+	warmupOperators int        // warmupOperators is how many distinct operators end the warmup.
+	warmupMutex     sync.Mutex // warmupMutex guards isWarmup, which a timer may end.
+	Lat             float64
+	Lon             float64
+	CurrentAircraft []AircraftRecord
+	NewAircraft     []*AircraftRecord // aircraft of CurrentAircraft which started a new flight
+	PollDiff        PollDiff          // how CurrentAircraft differ from the aircraft of the recent updates
+	RareSightings   []RareSighting
+	RuleMatches     []RuleMatch
+	NoteSightings   []NoteSighting   // aircraft with a note which started a new flight
+	IdentityChanges []IdentityChange // squawk and callsign changes of the latest update
+	AreaMovements   []AreaMovement   // arrivals and departures of the latest update
+	NewPeak         *PeakRecord      // NewPeak is the all-time peak beaten by the latest update, if any.
+	FirstSightings  []FirstSighting  // types, operators and countries never seen before
+	MachAlerts      []MachAlert      // supersonic-capable aircraft beyond the Mach threshold
+	CirclingAlerts  []CirclingAlert  // aircraft circling or holding near our location
+	GoAroundAlerts  []GoAroundAlert  // go-arounds and missed approaches at nearby airports
+	// UnlockedAchievements are the achievements unlocked by the latest update.
+	UnlockedAchievements []UnlockedAchievement
+	NotableSightings     []NotableSighting // notable aircraft which started a new flight
+	Follow               *FollowTrack      // Follow is the followed aircraft and its track, nil if none.
+	FollowEvents         []FollowEvent     // changes of the followed aircraft in the latest update
+	CachedFlightRoutes   map[string]*FlightRouteRecord
+	CachedPhotos         map[string]*PhotoRecord     // registrations mapped to photos
+	failedPhotos         map[string]time.Time        // registrations mapped to their last failed lookup
+	aircraftSightings    map[string]AircraftSighting // set of all seen aircraft, maps hex to last seen time
+	totalTypeCount       int
+	totalOperatorCount   int
+	totalCountryCount    int
+	SeenTypeCount        map[string]int     // types mapped to how often seen
+	SeenOperatorCount    map[string]int     // airlines mapped to how often seen
+	SeenCountryCount     map[string]int     // airlines mapped to how often seen
+	otherCounts          map[string]int     // categories mapped to the sightings folded away by the caps
+	Traffic              *TrafficStats      // aircraft counts of all polls, bucketed by hour
+	Altitudes            *AltitudeBandStats // airborne aircraft of all polls by altitude band
+	MessageTypes         *MessageTypeStats  // aircraft of all polls by how their position was received
+	Winds                *WindStats         // wind reported by aircraft of the last half hour by altitude band
+	Temperatures         *TemperatureStats  // temperature reported by aircraft of the last half hour by altitude
+	Peaks                *PeakStats         // most aircraft visible at once, in this session and ever
+	Records              *RecordStats       // highest and fastest aircraft of the session, by day and ever
+	DailyAircraft        *DailyCountStats   // distinct aircraft seen today
+	GoArounds            *GoAroundStats     // go-arounds at nearby airports, by airport and of today
+	Notes                *Notes             // notes on aircraft, including the watchlist
+	Acars                *AcarsLog          // ACARS messages of acarsdec or vdlm2dec, by aircraft
+	Discovery            *DiscoveryStats    // first sightings of all types, operators and countries
+	Achievements         *AchievementStats  // progress of the achievements, across sessions
+	IcaoToAircraft       map[string]dash.IcaoAircraft
+	IcaoToAirline        map[string]dash.IcaoOperator
+	iataToIcaoAirline    map[string]string        // IATA codes of airlines mapped to their ICAO codes
+	TypeSpecs            map[string]dash.TypeSpec // ICAO types mapped to basic specs
+	FleetSizes           map[string]int           // ICAO types mapped to their worldwide fleet size
+	regPrefixToCountry   *dash.RegPrefixTrie
+	hexRangeIndex        func() *dash.HexRangeIndex // hexRangeIndex is loaded on first use.
+	hexToCountry         map[string]string          // hexToCountry memoises the lookups in hexRangeIndex.
+	milCodeToOperator    func() map[string]string   // milCodeToOperator is loaded on first use.
+	rarityScorer         RarityScorer
+	comparison           *ScorerComparison // comparison is nil unless a scorer is compared to.
+	statsHalfLife        time.Duration
+	fleetRareBelow       int                        // fleet size below which types are always rare
+	decayedCounts        map[string]*DecayedCounter // categories mapped to decayed seen-counts
+	alertRules           []*rules.Rule
+	watchAreas           []*WatchArea
+	notableAircraft      map[string]dash.NotableAircraft
+	airports             []dash.Airport     // airports within airportRange, watched for go-arounds.
+	tiers                ProximityTiers     // tiers tag the aircraft by their distance.
+	operatorParents      map[string]string  // operatorParents maps operators in lower case to their group.
+	typeFamilies         map[string]string  // typeFamilies maps types to their family.
+	typeRarity           string             // typeRarity is the granularity at which types are rare.
+	history              SightingStore      // history persists all sightings, nil if disabled
+	airframes            *AirframeHistory   // airframes are the flights of every airframe seen so far.
+	rejected             *DecodeDiagnostics // rejected counts the reports rejected as implausible.
+	polls                pollTracker        // polls are the aircraft of the recent updates, to diff them.
+	reportsFirsts        bool               // reportsFirsts is false without past sessions, where all are firsts.
+	machAlert            float64            // machAlert is the Mach threshold of supersonic alerts, 0 if disabled.
+	circlingAlert        float64            // circlingAlert is the distance of circling alerts in [km], 0 if disabled.
+	limits               Limits             // limits cap how much is kept in memory.
+	shared               *SharedStats       // shared are the sightings of other observers, nil if disabled
+	baseline             *SharedStats       // baseline are the sightings of past sessions, nil if disabled
+	clock                Clock
+	sessionStart         time.Time      // sessionStart is when the dashboard was created.
+	session              sessionCounter // session counts what was seen in the session, uncapped.
+	rareCatches          []RareCatch    // rareCatches are all rare sightings of the session.
+	spottingDay          SpottingDay
+	datasetMutex         sync.Mutex       // datasetMutex guards the datasets and rules, which may be reloaded.
+	dataStore            *DataStore       // dataStore tells which versions of the datasets to load.
+	missingDatasets      []MissingDataset // missingDatasets couldn't be loaded, their enrichments are disabled.
+	errOut               log.Logger
+}
+
+func NewDashboard(lat float64, lon float64, opts DashboardOptions, stderr *io.Writer) (*Dashboard, error) {
 	const initError = "newDashboard: %w caused by %w"
 
-	icaoToAircraftMap, aircraftErr := dash.GetIcaoToAircraftMap()
-	if aircraftErr != nil {
-		return nil, fmt.Errorf(initError, errParseIcaoAircraftMap, aircraftErr)
+	rarityScorer, scorerErr := NewRarityScorer(opts.RarityScorer)
+	if scorerErr != nil {
+		return nil, fmt.Errorf(initError, errCreateRarityScorer, scorerErr)
+	}
+	if err := ValidateTypeRarity(opts.TypeRarity); err != nil {
+		return nil, fmt.Errorf("newDashboard: %w", err)
+	}
+
+	var comparison *ScorerComparison
+	if opts.CompareScorer != "" {
+		compareScorer, compareErr := NewRarityScorer(opts.CompareScorer)
+		if compareErr != nil {
+			return nil, fmt.Errorf(initError, errCreateRarityScorer, compareErr)
+		}
+		comparison = NewScorerComparison(rarityScorer, compareScorer)
+	}
+
+	alertRules, rulesErr := compileRules(opts.Rules)
+	if rulesErr != nil {
+		return nil, fmt.Errorf(initError, errCompileRules, rulesErr)
+	}
+
+	watchAreas, areasErr := compileAreas(opts.Areas)
+	if areasErr != nil {
+		return nil, fmt.Errorf(initError, errCompileAreas, areasErr)
+	}
+
+	var sharedStore SharedStore
+	if opts.SyncTarget != "" {
+		if err := ValidateObserver(opts.Observer); err != nil {
+			return nil, fmt.Errorf(initError, errOpenSharedStore, err)
+		}
+		store, storeErr := NewSharedStore(opts.SyncTarget, opts.SyncToken)
+		if storeErr != nil {
+			return nil, fmt.Errorf(initError, errOpenSharedStore, storeErr)
+		}
+		sharedStore = store
 	}
 
-	icaoToAirlineMap, airlineErr := dash.GetIcaoToAirlineMap()
-	if airlineErr != nil {
-		return nil, fmt.Errorf(initError, errParseIcaoAirlineMap, airlineErr)
+	dataStore := NewDataStore(opts.DataDir)
+	loaded := loadDatasets(dataStore.Dirs(), opts.LoadProgress)
+
+	spottingDay, dayErr := NewSpottingDay(opts.DayStartHour)
+	if dayErr != nil {
+		return nil, fmt.Errorf("newDashboard: %w", dayErr)
 	}
 
-	regPrefixToCountryMap, regErr := dash.GetRegPrefixMap()
-	if regErr != nil {
-		return nil, fmt.Errorf(initError, errParseRegToCountryMap, regErr)
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock{}
 	}
 
-	hexRangeToCountryMap, hexRangeErr := dash.GetHexRangeToCountryMap()
-	if hexRangeErr != nil {
-		return nil, fmt.Errorf(initError, errParseHexRangeToCountryMap, hexRangeErr)
+	notes, notesErr := LoadNotes(opts.NotesPath)
+	if notesErr != nil {
+		return nil, fmt.Errorf(initError, errLoadNotes, notesErr)
 	}
 
-	milCodeToOperatorMap, milCodeErr := dash.GetMilCodeToOperatorMap()
-	if milCodeErr != nil {
-		return nil, fmt.Errorf(initError, errParseMilCodeMap, milCodeErr)
+	peaks, peakErr := LoadPeakStats(opts.PeakPath)
+	if peakErr != nil {
+		return nil, fmt.Errorf(initError, errLoadPeak, peakErr)
+	}
+
+	records, recordsErr := LoadRecordStats(opts.RecordsPath, spottingDay)
+	if recordsErr != nil {
+		return nil, fmt.Errorf(initError, errLoadRecords, recordsErr)
+	}
+
+	var history SightingStore
+	if opts.HistoryPath != "" {
+		store, historyErr := OpenSightingStore(opts.HistoryPath, opts.Observer)
+		if historyErr != nil {
+			return nil, fmt.Errorf(initError, errOpenHistory, historyErr)
+		}
+		history = store
 	}
 
 	dashboard := Dashboard{
-		isWarmup:           true,
-		Lat:                lat,
-		Lon:                lon,
-		Fastest:            nil,
-		Highest:            nil,
-		CurrentAircraft:    nil,
-		RareSightings:      nil,
-		CachedFlightRoutes: make(map[string]*FlightRouteRecord),
-		aircraftSightings:  make(map[string]AircraftSighting),
-		totalTypeCount:     0,
-		totalOperatorCount: 0,
-		totalCountryCount:  0,
-		SeenTypeCount:      make(map[string]int),
-		SeenOperatorCount:  make(map[string]int),
-		SeenCountryCount:   make(map[string]int),
-		IcaoToAircraft:     icaoToAircraftMap,
-		IcaoToAirline:      icaoToAirlineMap,
-		regPrefixToCountry: regPrefixToCountryMap,
-		hexRangeToCountry:  hexRangeToCountryMap,
-		milCodeToOperator:  milCodeToOperatorMap,
-		errOut:             *log.New(*stderr, "dashboard ", log.LstdFlags),
+		isWarmup:             true,
+		warmupTypes:          opts.WarmupTypes,
+		warmupOperators:      opts.WarmupOperators,
+		warmupMutex:          sync.Mutex{},
+		Lat:                  lat,
+		Lon:                  lon,
+		CurrentAircraft:      nil,
+		NewAircraft:          nil,
+		PollDiff:             PollDiff{New: nil, Lost: nil, Changed: nil},
+		RareSightings:        nil,
+		RuleMatches:          nil,
+		NoteSightings:        nil,
+		IdentityChanges:      nil,
+		AreaMovements:        nil,
+		NewPeak:              nil,
+		FirstSightings:       nil,
+		CirclingAlerts:       nil,
+		GoAroundAlerts:       nil,
+		UnlockedAchievements: nil,
+		NotableSightings:     nil,
+		Follow:               nil,
+		FollowEvents:         nil,
+		CachedFlightRoutes:   make(map[string]*FlightRouteRecord),
+		CachedPhotos:         make(map[string]*PhotoRecord),
+		failedPhotos:         make(map[string]time.Time),
+		aircraftSightings:    make(map[string]AircraftSighting),
+		totalTypeCount:       0,
+		totalOperatorCount:   0,
+		totalCountryCount:    0,
+		SeenTypeCount:        make(map[string]int),
+		SeenOperatorCount:    make(map[string]int),
+		SeenCountryCount:     make(map[string]int),
+		otherCounts:          make(map[string]int),
+		Traffic:              NewTrafficStats(spottingDay),
+		Altitudes:            NewAltitudeBandStats(),
+		MessageTypes:         NewMessageTypeStats(),
+		Winds:                NewWindStats(),
+		Temperatures:         NewTemperatureStats(),
+		Peaks:                peaks,
+		Records:              records,
+		DailyAircraft:        NewDailyCountStats(opts.DailyPath, spottingDay),
+		GoArounds:            NewGoAroundStats(spottingDay),
+		Notes:                notes,
+		Acars:                NewAcarsLog(),
+		Discovery:            NewDiscoveryStats(spottingDay),
+		Achievements:         nil,
+		IcaoToAircraft:       loaded.icaoToAircraft,
+		IcaoToAirline:        loaded.icaoToAirline,
+		iataToIcaoAirline:    loaded.iataToIcaoAirline,
+		TypeSpecs:            loaded.typeSpecs,
+		FleetSizes:           loaded.fleetSizes,
+		regPrefixToCountry:   dash.NewRegPrefixTrie(loaded.regPrefixToCountry),
+		hexRangeIndex:        nil,
+		hexToCountry:         make(map[string]string),
+		milCodeToOperator:    nil,
+		rarityScorer:         rarityScorer,
+		comparison:           comparison,
+		statsHalfLife:        opts.StatsHalfLife,
+		fleetRareBelow:       opts.FleetRareBelow,
+		decayedCounts:        nil,
+		alertRules:           alertRules,
+		watchAreas:           watchAreas,
+		tiers:                opts.Tiers,
+		operatorParents:      opts.OperatorGroups.parents(),
+		typeFamilies:         familiesByType(loaded.icaoToAircraft, loaded.typeFamilies),
+		notableAircraft:      loaded.notableAircraft,
+		airports:             airportsNear(loaded.airports, lat, lon),
+		typeRarity:           opts.TypeRarity,
+		history:              history,
+		airframes:            NewAirframeHistory(),
+		rejected:             NewDecodeDiagnostics(),
+		polls:                newPollTracker(),
+		reportsFirsts:        false,
+		machAlert:            opts.MachAlert,
+		circlingAlert:        opts.CirclingAlert,
+		limits:               opts.Limits,
+		shared:               nil,
+		baseline:             nil,
+		clock:                clock,
+		sessionStart:         clock.Now(),
+		session:              newSessionCounter(),
+		rareCatches:          nil,
+		spottingDay:          spottingDay,
+		datasetMutex:         sync.Mutex{},
+		dataStore:            dataStore,
+		missingDatasets:      loaded.missing,
+		errOut:               *log.New(*stderr, "dashboard ", log.LstdFlags),
+	}
+	for _, missing := range loaded.missing {
+		dashboard.errOut.Printf("running without %s: %v", missing.Enrichment, missing.Err)
+	}
+	dashboard.hexRangeIndex = lazyHexRanges(dataStore.Dirs(), &dashboard.errOut)
+	dashboard.milCodeToOperator = lazyMilCodes(dataStore.Dirs(), &dashboard.errOut)
+	dashboard.Achievements = NewAchievementStats(dashboard.typeFamilies, func() map[string]string {
+		return dashboard.milCodeToOperator()
+	})
+	if opts.Follow != nil {
+		dashboard.Follow = NewFollowTrack(*opts.Follow)
+	}
+
+	if history != nil {
+		// Discoveries are tracked across sessions, so past sightings count as discovered already.
+		entries, historyErr := dashboard.history.Load()
+		if historyErr != nil {
+			dashboard.errOut.Println(fmt.Errorf("NewDashboard: %w", historyErr))
+		}
+		for _, entry := range entries {
+			dashboard.Discovery.Record(entry)
+			dashboard.Achievements.Record(entry)
+			dashboard.airframes.Record(entry.Hex, entry.Flight, entry.Time)
+		}
+		dashboard.DailyAircraft.Restore(entries, clock.Now())
+		dashboard.reportsFirsts = len(entries) > 0
+
+		if opts.BaselineFromHistory && len(entries) > 0 {
+			if err := dashboard.loadBaseline(entries, opts.StatsHalfLife, clock.Now()); err != nil {
+				dashboard.errOut.Println(fmt.Errorf("NewDashboard: %w", err))
+			}
+			dashboard.checkWarmup()
+		}
+	}
+
+	if sharedStore != nil {
+		dashboard.shared = NewSharedStats(sharedStore, opts.Observer, opts.StatsHalfLife, &dashboard.errOut)
+	}
+
+	if opts.StatsHalfLife > 0 {
+		dashboard.decayedCounts = map[string]*DecayedCounter{
+			"type":     NewDecayedCounter(opts.StatsHalfLife),
+			"operator": NewDecayedCounter(opts.StatsHalfLife),
+			"country":  NewDecayedCounter(opts.StatsHalfLife),
+		}
 	}
 
 	dashboard.errOut.Println("Dashboard init")
@@ -125,46 +439,275 @@ func NewDashboard(lat float64, lon float64, stderr *io.Writer) (*Dashboard, erro
 	return &dashboard, nil
 }
 
-func (db *Dashboard) FinishWarmupPeriod() {
-	db.isWarmup = false
+// swapDatasets replaces the datasets and the alert rules with reloaded ones. The lazily loaded
+// datasets are loaded again on their next use.
+func (db *Dashboard) swapDatasets(
+	loaded datasets,
+	dataDirs []string,
+	alertRules []*rules.Rule,
+	groups OperatorGroups,
+) {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	db.IcaoToAircraft = loaded.icaoToAircraft
+	db.IcaoToAirline = loaded.icaoToAirline
+	db.iataToIcaoAirline = loaded.iataToIcaoAirline
+	db.TypeSpecs = loaded.typeSpecs
+	db.FleetSizes = loaded.fleetSizes
+	db.regPrefixToCountry = dash.NewRegPrefixTrie(loaded.regPrefixToCountry)
+	db.hexRangeIndex = lazyHexRanges(dataDirs, &db.errOut)
+	db.hexToCountry = make(map[string]string)
+	db.milCodeToOperator = lazyMilCodes(dataDirs, &db.errOut)
+	db.alertRules = alertRules
+	db.operatorParents = groups.parents()
+	db.typeFamilies = familiesByType(loaded.icaoToAircraft, loaded.typeFamilies)
+	db.notableAircraft = loaded.notableAircraft
+	db.airports = airportsNear(loaded.airports, db.Lat, db.Lon)
+	db.missingDatasets = loaded.missing
+	db.errOut.Println("Dashboard datasets reloaded")
+}
+
+// MissingDatasets returns the datasets which couldn't be loaded, whose enrichments are disabled.
+func (db *Dashboard) MissingDatasets() []MissingDataset {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return db.missingDatasets
+}
+
+// DataDirs returns the directories the datasets are loaded from, the current version of the
+// updated datasets first.
+func (db *Dashboard) DataDirs() []string {
+	if db.dataStore == nil {
+		return NewDataStore("").Dirs()
+	}
+	return db.dataStore.Dirs()
+}
+
+// SpottingDay returns when the days of the daily statistics and reports start.
+func (db *Dashboard) SpottingDay() SpottingDay {
+	return db.spottingDay
+}
+
+// Clock returns the clock which tells the time of the sightings.
+func (db *Dashboard) Clock() Clock {
+	if db.clock == nil {
+		return SystemClock{}
+	}
+	return db.clock
+}
+
+// RarityScorer returns the active rarity scoring strategy.
+func (db *Dashboard) RarityScorer() RarityScorer {
+	return db.rarityScorer
+}
+
+// ScorerComparison returns how the active rarity scorer compares to another one, nil if no other
+// one is compared to.
+func (db *Dashboard) ScorerComparison() *ScorerComparison {
+	return db.comparison
+}
+
+// StatsHalfLife returns the half life of the seen-counts used for rarity, zero if they don't decay.
+func (db *Dashboard) StatsHalfLife() time.Duration {
+	return db.statsHalfLife
+}
+
+// isRare asks the rarity scorer whether the given property, which has just been counted, is rare.
+// If the statistics decay, the scorer sees the decayed counts instead of the all-time counts.
+// If sightings are shared, the counts of the other observers are added to ours, and so are the
+// counts of past sessions if the baseline is loaded from the history.
+func (db *Dashboard) isRare(
+	category string,
+	property string,
+	count int,
+	total int,
+	counts map[string]int,
+	now time.Time,
+) bool {
+	observation := RarityObservation{
+		Category: category,
+		Property: property,
+		Count:    count,
+		Total:    total,
+		Counts:   counts,
+		Time:     now,
+	}
+
+	if counter, ok := db.decayedCounts[category]; ok {
+		observation.Count = roundCount(counter.Add(property, now))
+		observation.Total = roundCount(counter.Total(now))
+		observation.Counts = counter.Counts(now)
+	}
+	if db.shared != nil {
+		observation = db.shared.merge(observation, db.rarityRollUp(category))
+	}
+	if db.baseline != nil {
+		observation = db.baseline.merge(observation, db.rarityRollUp(category))
+	}
+
+	isRare := db.rarityScorer.IsRare(observation)
+	if db.comparison != nil {
+		db.comparison.observe(observation, isRare)
+	}
+	return isRare
 }
 
 //////////////////////////////////////////////////////////////////////////////
 /// Processing of all aircraft: civilian, military, government, private.    //
 //////////////////////////////////////////////////////////////////////////////
 
+// eventList is the events of one kind of a poll, each referring to the sighting it is about.
+type eventList[E any] struct {
+	events []E
+	pinned int // pinned is how many of the events refer to a sighting of their own already.
+	// sightingOf returns where the event refers to its sighting.
+	sightingOf func(event *E) **AircraftSighting
+}
+
+// newEventList creates an empty list of the events whose sighting is found by sightingOf.
+func newEventList[E any](sightingOf func(event *E) **AircraftSighting) eventList[E] {
+	return eventList[E]{events: nil, pinned: 0, sightingOf: sightingOf}
+}
+
+// add appends the events.
+func (list *eventList[E]) add(events ...E) {
+	list.events = append(list.events, events...)
+}
+
+// unpinned tells whether any events were added since they were pinned last.
+func (list *eventList[E]) unpinned() bool {
+	return len(list.events) > list.pinned
+}
+
+// pin points the events added since they were pinned last at the sighting.
+func (list *eventList[E]) pin(sighting *AircraftSighting) {
+	for idx := list.pinned; idx < len(list.events); idx++ {
+		*list.sightingOf(&list.events[idx]) = sighting
+	}
+	list.pinned = len(list.events)
+}
+
+// eventPinner is an eventList of any kind of event.
+type eventPinner interface {
+	unpinned() bool
+	pin(sighting *AircraftSighting)
+}
+
+// pollEvents are the events of a poll, each referring to the sighting it is about.
+type pollEvents struct {
+	rareSightings   eventList[RareSighting]
+	ruleMatches     eventList[RuleMatch]
+	noteSightings   eventList[NoteSighting]
+	identityChanges eventList[IdentityChange]
+	areaMovements   eventList[AreaMovement]
+	firstSightings  eventList[FirstSighting]
+	machAlerts      eventList[MachAlert]
+	achievements    eventList[UnlockedAchievement]
+	notable         eventList[NotableSighting]
+	circling        eventList[CirclingAlert]
+	goArounds       eventList[GoAroundAlert]
+	// all are the event lists of all kinds, to pin them together.
+	all []eventPinner
+}
+
+// newPollEvents creates the empty event lists of a poll.
+func newPollEvents() *pollEvents {
+	events := &pollEvents{
+		rareSightings:   newEventList(func(event *RareSighting) **AircraftSighting { return &event.Sighting }),
+		ruleMatches:     newEventList(func(event *RuleMatch) **AircraftSighting { return &event.Sighting }),
+		noteSightings:   newEventList(func(event *NoteSighting) **AircraftSighting { return &event.Sighting }),
+		identityChanges: newEventList(func(event *IdentityChange) **AircraftSighting { return &event.Sighting }),
+		areaMovements:   newEventList(func(event *AreaMovement) **AircraftSighting { return &event.Sighting }),
+		firstSightings:  newEventList(func(event *FirstSighting) **AircraftSighting { return &event.Sighting }),
+		machAlerts:      newEventList(func(event *MachAlert) **AircraftSighting { return &event.Sighting }),
+		achievements:    newEventList(func(event *UnlockedAchievement) **AircraftSighting { return &event.Sighting }),
+		notable:         newEventList(func(event *NotableSighting) **AircraftSighting { return &event.Sighting }),
+		circling:        newEventList(func(event *CirclingAlert) **AircraftSighting { return &event.Sighting }),
+		goArounds:       newEventList(func(event *GoAroundAlert) **AircraftSighting { return &event.Sighting }),
+		all:             nil,
+	}
+	events.all = []eventPinner{
+		&events.rareSightings,
+		&events.ruleMatches,
+		&events.noteSightings,
+		&events.identityChanges,
+		&events.areaMovements,
+		&events.firstSightings,
+		&events.machAlerts,
+		&events.achievements,
+		&events.notable,
+		&events.circling,
+		&events.goArounds,
+	}
+	return events
+}
+
+// unpinned tells whether any events were added since they were pinned last.
+func (events *pollEvents) unpinned() bool {
+	return slices.ContainsFunc(events.all, eventPinner.unpinned)
+}
+
+// pin points the events added since they were pinned last at a copy of the sighting, so that they
+// keep it as it is now while the scratch sighting moves on to the next aircraft.
+func (events *pollEvents) pin(scratch *AircraftSighting) {
+	sighting := new(AircraftSighting)
+	*sighting = *scratch
+	for _, list := range events.all {
+		list.pin(sighting)
+	}
+}
+
+// ProcessPoll processes the aircraft of a poll like ProcessAircraftRecords. If the poll failed,
+// its aircraft aren't diffed, so that the aircraft missing from it aren't reported as lost.
+func (db *Dashboard) ProcessPoll(aircraftRecords []AircraftRecord, pollErr error) {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	db.processAircraftRecords(aircraftRecords, pollErr == nil)
+}
+
+// ProcessAircraftRecords processes the aircraft of a successful poll.
 func (db *Dashboard) ProcessAircraftRecords(aircraftRecords []AircraftRecord) {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	db.processAircraftRecords(aircraftRecords, true)
+}
+
+// processAircraftRecords processes the aircraft of a poll, which are diffed with those of the
+// recent polls if isDiffed. Requires the datasetMutex.
+func (db *Dashboard) processAircraftRecords(aircraftRecords []AircraftRecord, isDiffed bool) {
 	db.CurrentAircraft = aircraftRecords
 	sort.Sort(ByFlight(db.CurrentAircraft))
 	thisPos := dash.NewCoordinates(db.Lat, db.Lon)
-	var rareSightings []RareSighting
+	now := db.Clock().Now()
+	if db.shared != nil {
+		db.shared.Refresh(now)
+	}
+	events := newPollEvents()
+	var historyEntries []HistoryEntry
+	// The aircraft are processed in a scratch sighting, which is only copied for the events.
+	sighting := new(AircraftSighting)
+	var newAircraft []*AircraftRecord
 
 	for idx := range len(db.CurrentAircraft) {
 		// Get aircraft and time of sighting
 		aircraft := &(db.CurrentAircraft)[idx]
 		lastSeenMsBeforeNow := time.Duration(aircraft.Seen) * time.Second
-		lastSeenTime := time.Now().Add(-lastSeenMsBeforeNow)
+		lastSeenTime := now.Add(-lastSeenMsBeforeNow)
 
-		// Retrieve previous sighting or create new one.
-		sighting, exists := db.aircraftSightings[aircraft.Hex]
+		// Retrieve previous sighting or create new one. The reports are checked first so that
+		// implausible ones don't seed the sighting of a new aircraft.
+		previous, exists := db.aircraftSightings[aircraft.Hex]
+		*sighting = previous
+		db.checkPlausibility(sighting, aircraft, now)
 		if !exists {
-			sighting = AircraftSighting{
-				lastSeen:     lastSeenTime,
-				lastFlightNo: flightUnknown,
-				registration: aircraft.Registration,
-				latitude:     aircraft.Lat,
-				longitude:    aircraft.Lon,
-				direction:    getDirection(db.Lat, db.Lon, aircraft.Lat, aircraft.Lon),
-				distance:     math.MaxInt,
-				typeShort:    "",
-				typeDesc:     typeUnknown,
-				operator:     operatorUnknown,
-				country:      countryUnknown,
-				info:         "",
-				flightroute:  nil,
-			}
+			lastFix := sighting.lastFix
+			*sighting = newAircraftSighting(aircraft, lastSeenTime)
+			sighting.lastFix = lastFix
+			db.session.add("aircraft", aircraft.Hex)
 		}
 
+		previousSeen := sighting.lastSeen
+		sighting.lastSeen = lastSeenTime
 		if sighting.registration == "" {
 			sighting.registration = aircraft.Registration
 		}
@@ -179,41 +722,288 @@ func (db *Dashboard) ProcessAircraftRecords(aircraftRecords []AircraftRecord) {
 
 		isNewFlight := !exists || isFlightUpdated
 
+		if isFlightUpdated && lastSeenTime.Sub(previousSeen) > midFlightGap {
+			// The next flight of the airframe, which starts with a squawk of its own.
+			sighting.squawk = ""
+		} else if isFlightUpdated {
+			events.identityChanges.add(IdentityChange{
+				Field:    ChangeCallsign,
+				From:     sighting.lastFlightNo,
+				To:       thisFlightNo,
+				Sighting: sighting,
+			})
+		}
 		if isFlightIdentified || isFlightUpdated {
 			sighting.lastFlightNo = thisFlightNo
 		}
+		if change, changed := detectSquawkChange(sighting, aircraft.Squawk); changed {
+			events.identityChanges.add(change)
+		}
 
-		// Update distance
+		if isFlightUpdated {
+			// Allow custom alerts to fire again for the new flight.
+			sighting.firedRules = nil
+			sighting.rarities = NoRarity
+			sighting.machAlerted = false
+			sighting.circling = noCircling()
+			sighting.goAround = noGoAround()
+		}
+
+		// Update distance, from the last known position if there is no live one.
 		acPos := dash.NewCoordinates(aircraft.Lat, aircraft.Lon)
-		(db.CurrentAircraft)[idx].CachedDist = dash.Distance(thisPos, acPos).Kilometers()
+		if position, ok := aircraft.KnownPosition(); ok {
+			acPos = dash.NewCoordinates(position.Lat, position.Lon)
+		}
 		aircraft.CachedDist = dash.Distance(thisPos, acPos).Kilometers()
 		sighting.distance = aircraft.CachedDist
+		_, aircraft.CachedTier = db.tiers.Tier(aircraft.CachedDist)
+		sighting.tier = aircraft.CachedTier
+		sighting.updatePosition(db.Lat, db.Lon, aircraft)
+		events.areaMovements.add(db.updateAreas(sighting, aircraft)...)
 
 		// Update all aircraft, type, operator and country statistics
-		db.updateHighest(aircraft)
-		db.updateFastest(aircraft)
-
 		newRarities := NoRarity
-		rareTypeFlag := db.updateType(&sighting, aircraft, isNewFlight)
-		rareOperatorFlag := db.updateOperator(&sighting, aircraft, isNewFlight)
-		rareCountryFlag := db.updateCountry(&sighting, aircraft, isNewFlight)
+		rareTypeFlag := db.updateType(sighting, aircraft, isNewFlight)
+		rareOperatorFlag := db.updateOperator(sighting, aircraft, isNewFlight)
+		rareCountryFlag := db.updateCountry(sighting, aircraft, isNewFlight)
 
 		newRarities |= rareTypeFlag << 0
 		newRarities |= rareOperatorFlag << 1
 		newRarities |= rareCountryFlag << 2 //nolint:mnd // okay for bit shifting
+		if db.comparison != nil {
+			db.comparison.finishSighting()
+		}
 
-		if newRarities != NoRarity {
-			rareSightings = append(rareSightings, RareSighting{
-				Rarities: newRarities,
-				Sighting: &sighting,
-			})
+		// Everything is rare at first, so rare sightings are held back until there's a baseline.
+		if newRarities != NoRarity && !db.isWarmingUp() {
+			sighting.rarities |= newRarities
+			rareSighting := RareSighting{Rarities: newRarities, Sighting: sighting}
+			events.rareSightings.add(rareSighting)
+			db.rareCatches = append(db.rareCatches, newRareCatch(aircraft.Hex, rareSighting))
 		}
 
 		// Finally, update the records
-		sighting.info = aircraftToString(aircraft)
-		db.aircraftSightings[aircraft.Hex] = sighting
+		db.airframes.Record(aircraft.Hex, sighting.lastFlightNo, lastSeenTime)
+		events.ruleMatches.add(db.evaluateRules(sighting, aircraft)...)
+		if alert, ok := db.checkMach(sighting, aircraft); ok {
+			events.machAlerts.add(alert)
+		}
+		if alert, ok := db.checkCircling(sighting, aircraft, now); ok {
+			events.circling.add(alert)
+		}
+		if alert, ok := db.checkGoAround(sighting, aircraft); ok {
+			events.goArounds.add(alert)
+		}
+		if isNewFlight {
+			historyEntry := sightingToHistoryEntry(aircraft.Hex, sighting)
+			historyEntries = append(historyEntries, historyEntry)
+			if firsts := db.Discovery.Record(historyEntry); len(firsts) > 0 && db.reportsFirsts {
+				events.firstSightings.add(FirstSighting{Firsts: firsts, Sighting: sighting})
+			}
+			for _, achievement := range db.Achievements.Record(historyEntry) {
+				events.achievements.add(UnlockedAchievement{Achievement: achievement, Sighting: sighting})
+			}
+			newAircraft = append(newAircraft, aircraft)
+			if note, ok := db.Notes.Get(aircraft.Hex); ok {
+				events.noteSightings.add(NoteSighting{Note: note, Sighting: sighting})
+			}
+			if notable, ok := db.notableAircraft[aircraft.Hex]; ok {
+				events.notable.add(NotableSighting{Aircraft: notable, Sighting: sighting})
+			}
+		}
+		// Describing the aircraft is only worth it for the events, which describe it as it is now.
+		if events.unpinned() {
+			sighting.info = aircraftToString(aircraft)
+			events.pin(sighting)
+		}
+		db.aircraftSightings[aircraft.Hex] = *sighting
+	}
+	db.RareSightings = events.rareSightings.events
+	db.RuleMatches = events.ruleMatches.events
+	db.NoteSightings = events.noteSightings.events
+	db.IdentityChanges = events.identityChanges.events
+	db.AreaMovements = events.areaMovements.events
+	db.FirstSightings = events.firstSightings.events
+	db.MachAlerts = events.machAlerts.events
+	db.UnlockedAchievements = events.achievements.events
+	db.NotableSightings = events.notable.events
+	db.CirclingAlerts = events.circling.events
+	db.GoAroundAlerts = events.goArounds.events
+	if db.Follow != nil {
+		db.FollowEvents = db.followAircraft(now)
+	}
+	db.NewAircraft = newAircraft
+	db.PollDiff = PollDiff{New: nil, Lost: nil, Changed: nil}
+	if isDiffed {
+		db.PollDiff = db.polls.diff(newAircraft, db.CurrentAircraft, now)
+	}
+	db.enforceLimits(now)
+	db.checkWarmup()
+	db.Traffic.Record(now, len(db.CurrentAircraft))
+	db.Altitudes.Record(db.CurrentAircraft)
+	db.MessageTypes.Record(db.CurrentAircraft)
+	db.Winds.Record(now, db.CurrentAircraft)
+	db.Temperatures.Record(now, db.CurrentAircraft)
+	newPeak, isNewPeak, peakErr := db.Peaks.Record(now, len(db.CurrentAircraft))
+	if peakErr != nil {
+		db.errOut.Println(fmt.Errorf("ProcessAircraftRecords: %w", peakErr))
+	}
+	db.NewPeak = nil
+	if isNewPeak {
+		db.NewPeak = &newPeak
+	}
+	if err := db.Records.Record(now, db.CurrentAircraft); err != nil {
+		db.errOut.Println(fmt.Errorf("ProcessAircraftRecords: %w", err))
+	}
+	if err := db.DailyAircraft.Record(now, db.CurrentAircraft); err != nil {
+		db.errOut.Println(fmt.Errorf("ProcessAircraftRecords: %w", err))
+	}
+	db.GoArounds.Record(now, db.GoAroundAlerts)
+
+	if db.history != nil {
+		if err := db.history.Append(historyEntries); err != nil {
+			db.errOut.Println(fmt.Errorf("ProcessAircraftRecords: %w", err))
+		}
+	}
+	if db.shared != nil {
+		db.shared.Push(historyEntries)
+	}
+}
+
+// InterestingNearby tells whether any of the current aircraft within the given distance in [km] is
+// a rare sighting of its current flight, on the watchlist or a favourite.
+func (db *Dashboard) InterestingNearby(radius float64) bool {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+
+	for idx := range db.CurrentAircraft {
+		aircraft := &db.CurrentAircraft[idx]
+		if aircraft.CachedDist > radius {
+			continue
+		}
+		if sighting, ok := db.aircraftSightings[aircraft.Hex]; ok && sighting.rarities != NoRarity {
+			return true
+		}
+		if db.Notes.IsWatched(aircraft.Hex) || db.Notes.IsFavourite(aircraft.Hex) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tiers returns the proximity tiers the aircraft are tagged with.
+func (db *Dashboard) Tiers() ProximityTiers {
+	return db.tiers
+}
+
+// PreviousFlights returns the earlier flights of the airframe with the given hex, newest first,
+// from this and past sessions.
+func (db *Dashboard) PreviousFlights(hex string, currentFlight string) []FlightLeg {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return db.airframes.Previous(hex, currentFlight, db.Clock().Now())
+}
+
+// SharedStore returns where sightings are shared with other observers, nil if sharing is disabled.
+func (db *Dashboard) SharedStore() SharedStore {
+	if db.shared == nil {
+		return nil
+	}
+	return db.shared.store
+}
+
+// NotificationStore returns the sighting store to keep the notifications in, or nil if the history
+// is disabled.
+func (db *Dashboard) NotificationStore() NotificationStore {
+	store, _ := db.history.(NotificationStore)
+	return store
+}
+
+// CheckStorage tells whether the sighting history is enabled and can be written to.
+func (db *Dashboard) CheckStorage() (bool, error) {
+	if db.history == nil {
+		return false, nil
+	}
+
+	if err := db.history.Check(); err != nil {
+		return true, fmt.Errorf("CheckStorage: %w", err)
+	}
+	return true, nil
+}
+
+// Close writes the count of distinct aircraft of today so far and the records and the peak which
+// weren't saved yet, and closes the sighting history, if any. The dashboard can't persist sightings
+// afterwards.
+func (db *Dashboard) Close() error {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+
+	flushErr := errors.Join(db.DailyAircraft.Flush(), db.Records.Flush(), db.Peaks.Flush())
+	var closeErr error
+	if db.history != nil {
+		closeErr = db.history.Close()
+	}
+	if err := errors.Join(flushErr, closeErr); err != nil {
+		return fmt.Errorf("Close: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns all sightings persisted so far, or nothing if the history is disabled.
+func (db *Dashboard) LoadHistory() ([]HistoryEntry, error) {
+	if db.history == nil {
+		return nil, nil
+	}
+
+	entries, err := db.history.Load()
+	if err != nil {
+		return entries, fmt.Errorf("LoadHistory: %w", err)
+	}
+	return entries, nil
+}
+
+// newAircraftSighting creates the sighting of an aircraft which hasn't been seen before.
+func newAircraftSighting(aircraft *AircraftRecord, seen time.Time) AircraftSighting {
+	return AircraftSighting{
+		lastSeen:     seen,
+		lastFlightNo: flightUnknown,
+		registration: aircraft.Registration,
+		latitude:     aircraft.Lat,
+		longitude:    aircraft.Lon,
+		lastFix:      positionFix{lat: 0, lon: 0, time: time.Time{}},
+		direction:    dirUnknown,
+		bearing:      0,
+		track:        0,
+		speed:        0,
+		distance:     math.MaxInt,
+		tier:         "",
+		typeShort:    "",
+		typeDesc:     typeUnknown,
+		operator:     operatorUnknown,
+		country:      countryUnknown,
+		info:         "",
+		flightroute:  nil,
+		photo:        nil,
+		firedRules:   nil,
+		squawk:       "",
+		confidence:   Confidences{},
+		rarityScore:  RarityScore{Local: 0, Global: 0, HasGlobal: false},
+		rarities:     NoRarity,
+		circling:     noCircling(),
+		goAround:     noGoAround(),
+	}
+}
+
+func sightingToHistoryEntry(hex string, sighting *AircraftSighting) HistoryEntry {
+	return HistoryEntry{
+		Time:         sighting.lastSeen,
+		Hex:          hex,
+		Flight:       sighting.lastFlightNo,
+		Registration: sighting.registration,
+		Type:         sighting.typeDesc,
+		Operator:     sighting.operator,
+		Country:      sighting.country,
 	}
-	db.RareSightings = rareSightings
 }
 
 func (db *Dashboard) updateType(
@@ -240,14 +1030,31 @@ func (db *Dashboard) updateType(
 	}
 
 	sighting.typeDesc = aType
+	sighting.confidence.Type = ConfidenceExact
 	aircraft.CachedType = aType
 
 	// Valid type found! Record type and update type rarities.
 	thisTypeCountNew := db.SeenTypeCount[aType] + 1
 	db.SeenTypeCount[aType] = thisTypeCountNew
+	db.session.add("type", aType)
 	db.totalTypeCount++
-	rarityThreshold := math.Log(float64(db.totalTypeCount)) - RarityConstant
-	isRareType := float64(thisTypeCountNew) < rarityThreshold
+	fleetSize, hasFleet := db.FleetSizes[aircraft.IcaoType]
+	sighting.rarityScore = newRarityScore(thisTypeCountNew, db.totalTypeCount, fleetSize, hasFleet)
+	// Types may be rare by their family rather than on their own.
+	rarityType, rarityCount, rarityCounts := aType, thisTypeCountNew, db.SeenTypeCount
+	if rollUp := db.rarityRollUp("type"); rollUp != nil {
+		rarityType = rollUp(aType)
+		rarityCounts = rollUpCounts(db.SeenTypeCount, rollUp)
+		rarityCount = rarityCounts[rarityType]
+	}
+	isRareType := db.isRare(
+		"type",
+		rarityType,
+		rarityCount,
+		db.totalTypeCount,
+		rarityCounts,
+		sighting.lastSeen)
+	isRareType = isRareType || db.isGloballyRare(fleetSize, hasFleet)
 
 	// fmt.Println(
 	//	"type rarity calculation: ",
@@ -299,22 +1106,23 @@ func (db *Dashboard) updateOperator(
 
 	// First option: try to detect the airline and get operator & country from it.
 	flightCode := aircraft.GetFlightNoAsIcaoCode()
-	if flightCode != flightUnknownCode {
-		if operatorRecord, opExists := db.IcaoToAirline[flightCode]; opExists {
-			sighting.operator = operatorRecord.Company
-		}
+	if operatorRecord, confidence := db.lookupAirline(aircraft.Flight); confidence != ConfidenceNone {
+		sighting.operator = operatorRecord.Company
+		sighting.confidence.Operator = confidence
 	}
 
 	// Unable to detect airline, maybe it's military or government.
 	if sighting.operator == operatorUnknown {
-		if militaryOperator, milOpExists := db.milCodeToOperator[flightCode]; milOpExists {
+		if militaryOperator, milOpExists := db.milCodeToOperator()[flightCode]; milOpExists {
 			sighting.operator = militaryOperator
+			sighting.confidence.Operator = ConfidenceHeuristic
 		}
 	}
 
 	// operator still not found, check whether the 'ownOp' field in the aircraft record is set.
 	if sighting.operator == operatorUnknown && aircraft.OwnOp != "" {
 		sighting.operator = aircraft.OwnOp
+		sighting.confidence.Operator = ConfidenceExact
 	}
 
 	// Did not manage to find out the operator of this aircraft.
@@ -324,9 +1132,15 @@ func (db *Dashboard) updateOperator(
 
 	thisOperatorCountNew := db.SeenOperatorCount[sighting.operator] + 1
 	db.SeenOperatorCount[sighting.operator] = thisOperatorCountNew
+	db.session.add("operator", sighting.operator)
 	db.totalOperatorCount++
-	rarityThreshold := math.Log(float64(db.totalOperatorCount)) - RarityConstant
-	isRareOperator := float64(thisOperatorCountNew) < rarityThreshold
+	isRareOperator := db.isRare(
+		"operator",
+		sighting.operator,
+		thisOperatorCountNew,
+		db.totalOperatorCount,
+		db.SeenOperatorCount,
+		sighting.lastSeen)
 
 	// fmt.Println(
 	//	"operator rarity calculation:",
@@ -366,22 +1180,24 @@ func (db *Dashboard) updateCountry(
 	}
 
 	// Option #1: Try to detect the airline and get operator & country from it.
-	flightCode := aircraft.GetFlightNoAsIcaoCode()
-	if flightCode != flightUnknownCode {
-		if operatorRecord, exists := db.IcaoToAirline[flightCode]; exists {
-			sighting.country = strings.ToUpper(operatorRecord.Country)
-		}
+	if operatorRecord, confidence := db.lookupAirline(aircraft.Flight); confidence != ConfidenceNone {
+		sighting.country = strings.ToUpper(operatorRecord.Country)
+		sighting.confidence.Country = confidence
 	}
 
 	// Option #2: Detect country by the range of it's hex registration.
 	if sighting.country == countryUnknown {
-		sighting.country = strings.ToUpper(db.getCountryByHexRange(aircraft.Hex))
+		if country := db.getCountryByHexRange(aircraft.Hex); country != countryUnknown {
+			sighting.country = strings.ToUpper(country)
+			sighting.confidence.Country = ConfidenceGuess
+		}
 	}
 
 	// Option #3: Detect country by its ICAO registration prefix.
 	if sighting.country == countryUnknown {
 		if country, exists := db.getCountryByRegPrefix(aircraft.Registration); exists {
 			sighting.country = strings.ToUpper(country)
+			sighting.confidence.Country = ConfidenceHeuristic
 		}
 	}
 
@@ -392,9 +1208,15 @@ func (db *Dashboard) updateCountry(
 
 	thisCountryCountNew := db.SeenCountryCount[sighting.country] + 1
 	db.SeenCountryCount[sighting.country] = thisCountryCountNew
+	db.session.add("country", sighting.country)
 	db.totalCountryCount++
-	rarityThreshold := math.Log(float64(db.totalCountryCount)) - RarityConstant
-	isRareCountry := float64(thisCountryCountNew) < rarityThreshold
+	isRareCountry := db.isRare(
+		"country",
+		sighting.country,
+		thisCountryCountNew,
+		db.totalCountryCount,
+		db.SeenCountryCount,
+		sighting.lastSeen)
 
 	// db.logger.Debug(
 	//	"country rarity calculation:",
@@ -418,50 +1240,27 @@ func (db *Dashboard) updateCountry(
 	return 1
 }
 
+// getCountryByHexRange looks up the country to which the hex address of an aircraft is
+// allocated. As aircraft are seen on many polls, the result is memoised per hex.
 func (db *Dashboard) getCountryByHexRange(hexAsStr string) string {
+	if country, ok := db.hexToCountry[hexAsStr]; ok {
+		return country
+	}
+
+	country := countryUnknown
 	hexAsInt, err := strconv.ParseInt(hexAsStr, 16, 64)
 	if err != nil {
 		db.errOut.Printf("unable to convert hex to int: %s\n", hexAsStr)
-		return countryUnknown
-	}
-	for key, value := range db.hexRangeToCountry {
-		if hexAsInt > key.LowerBound && hexAsInt < key.UpperBound {
-			return value
-		}
-	}
-	return countryUnknown
-}
-
-func (db *Dashboard) getCountryByRegPrefix(reg string) (string, bool) {
-	for key, value := range db.regPrefixToCountry {
-		if strings.Contains(reg, key) {
-			return value, true
-		}
+	} else if allocated, ok := db.hexRangeIndex().Lookup(hexAsInt); ok {
+		country = allocated
 	}
 
-	return "", false
+	db.hexToCountry[hexAsStr] = country
+	return country
 }
 
-func (db *Dashboard) updateHighest(aircraft *AircraftRecord) {
-	thisAltitude, thisAltOk := aircraft.AltBaro.(float64)
-	if !thisAltOk {
-		return
-	}
-
-	//nolint:errcheck // If highest is initialized the altBaro is always valid.
-	if db.Highest != nil && db.Highest.AltBaro.(float64) > thisAltitude {
-		return
-	}
-
-	db.Highest = aircraft
-}
-
-func (db *Dashboard) updateFastest(aircraft *AircraftRecord) {
-	if db.Fastest != nil && db.Fastest.GroundSpeed > aircraft.GroundSpeed {
-		return
-	}
-
-	db.Fastest = aircraft
+func (db *Dashboard) getCountryByRegPrefix(reg string) (string, bool) {
+	return db.regPrefixToCountry.Lookup(reg)
 }
 
 func (db *Dashboard) AssignRouteToCallsigns() []string {
@@ -518,3 +1317,74 @@ func (db *Dashboard) AssignFlightRoutes(flightRouteRecords []FlightRouteRecord)
 		db.CachedFlightRoutes[sighting.lastFlightNo] = GetDefaultFlightrouteRecord()
 	}
 }
+
+// RegistrationsWithoutPhoto returns the registrations of all rare sightings for which no photo
+// has been cached yet, except those whose lookup failed less than photoRetryAfter ago.
+func (db *Dashboard) RegistrationsWithoutPhoto() []string {
+	now := db.Clock().Now()
+	var registrations []string
+	for _, rareSighting := range db.RareSightings {
+		registration := rareSighting.Sighting.registration
+		if registration == "" {
+			// Can't look up photos without registration.
+			continue
+		}
+		if _, ok := db.CachedPhotos[registration]; ok {
+			continue
+		}
+		if failed, ok := db.failedPhotos[registration]; ok && now.Sub(failed) < photoRetryAfter {
+			continue
+		}
+		if !slices.Contains(registrations, registration) {
+			registrations = append(registrations, registration)
+		}
+	}
+	return registrations
+}
+
+// AssignCachedPhotos assigns the cached photos to the current rare sightings, so that they can be
+// notified with their photo without looking it up again.
+func (db *Dashboard) AssignCachedPhotos() {
+	for _, rareSighting := range db.RareSightings {
+		if photo, ok := db.CachedPhotos[rareSighting.Sighting.registration]; ok {
+			rareSighting.Sighting.photo = photo
+		}
+	}
+}
+
+// AssignPhotos caches the photos looked up for the requested registrations and assigns them to
+// all sightings with a matching registration, including the current rare sightings.
+// Requested registrations without a photo, whose lookup failed, aren't looked up again for
+// photoRetryAfter.
+func (db *Dashboard) AssignPhotos(requested []string, photos map[string]PhotoRecord) {
+	now := db.Clock().Now()
+	for registration, failed := range db.failedPhotos {
+		if now.Sub(failed) >= photoRetryAfter {
+			delete(db.failedPhotos, registration)
+		}
+	}
+	for _, registration := range requested {
+		if _, ok := photos[registration]; !ok {
+			db.failedPhotos[registration] = now
+		}
+	}
+	for registration, photo := range photos {
+		db.CachedPhotos[registration] = &photo
+		delete(db.failedPhotos, registration)
+	}
+
+	db.AssignCachedPhotos()
+
+	for hex, sighting := range db.aircraftSightings {
+		if photo, ok := db.CachedPhotos[sighting.registration]; ok {
+			sighting.photo = photo
+			db.aircraftSightings[hex] = sighting
+		}
+	}
+}
+
+// GetPhotoForRegistration returns the cached photo for the given registration or nil if there
+// is none.
+func (db *Dashboard) GetPhotoForRegistration(registration string) *PhotoRecord {
+	return db.CachedPhotos[registration]
+}
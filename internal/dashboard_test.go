@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// benchmarkAircraft is the size of a poll of a busy receiver.
+const benchmarkAircraft = 200
+
+// benchmarkPoll creates a poll of aircraft which all stay in sight, as after the first poll of a
+// session.
+func benchmarkPoll() []AircraftRecord {
+	types := []string{"A320", "B738", "A20N", "E190", "B77W", "A359", "CRJ9", "DH8D"}
+	poll := make([]AircraftRecord, benchmarkAircraft)
+	for idx := range poll {
+		poll[idx] = AircraftRecord{ //nolint:exhaustruct // fields of a typical readsb record
+			Hex:          fmt.Sprintf("3c%04x", idx),
+			Flight:       fmt.Sprintf("DLH%-5d", idx),
+			Registration: fmt.Sprintf("D-A%03d", idx),
+			IcaoType:     types[idx%len(types)],
+			Lat:          53 + float64(idx%50)/25,
+			Lon:          9 + float64(idx/50)/2,
+			AltBaro:      NewAltitude(float64(1000 + idx*150)),
+			GroundSpeed:  float64(150 + idx*2),
+			Track:        float64(idx % 360),
+			Squawk:       "1000",
+		}
+	}
+	return poll
+}
+
+func BenchmarkProcessAircraftRecords(b *testing.B) {
+	b.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(53.63, 9.99, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		b.Fatalf("NewDashboard() error = %v", err)
+	}
+	poll := benchmarkPoll()
+	// Polls are taken over by the dashboard, so that every iteration processes a fresh copy.
+	buffers := [][]AircraftRecord{make([]AircraftRecord, len(poll)), make([]AircraftRecord, len(poll))}
+	dashboard.ProcessAircraftRecords(append([]AircraftRecord(nil), poll...))
+
+	b.ReportAllocs()
+	iteration := 0
+	for b.Loop() {
+		buffer := buffers[iteration%len(buffers)]
+		copy(buffer, poll)
+		dashboard.ProcessAircraftRecords(buffer)
+		iteration++
+	}
+}
+
+func TestPollEventsPinnedToTheirAircraft(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	clock := NewManualClock(time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC))
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio", Clock: clock}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Squawk: "1000"}, //nolint:exhaustruct // identity only
+	})
+
+	// Several aircraft raise events in the same poll, one of them two, and one in between none.
+	clock.Advance(time.Minute)
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH401", Squawk: "7700"}, //nolint:exhaustruct // identity only
+		{Hex: "3c6445", Flight: "EWG1", Squawk: "1000"},   //nolint:exhaustruct // identity only
+		{Hex: "3c6446", Flight: "BAW12", Squawk: "7600"},  //nolint:exhaustruct // identity only
+		{Hex: "3c6447", Flight: "SWR3", Squawk: "7500"},   //nolint:exhaustruct // identity only
+	})
+
+	expected := []struct {
+		field  string
+		flight string
+	}{
+		{ChangeSquawk, "BAW12"},
+		{ChangeCallsign, "DLH401"},
+		{ChangeSquawk, "DLH401"},
+		{ChangeSquawk, "SWR3"},
+	}
+	changes := dashboard.IdentityChanges
+	if len(changes) != len(expected) {
+		t.Fatalf("IdentityChanges = %+v, expected %d changes", changes, len(expected))
+	}
+	for idx, change := range changes {
+		if change.Field != expected[idx].field || change.Sighting.lastFlightNo != expected[idx].flight {
+			t.Errorf("IdentityChanges[%d] = %s of %s, expected %s of %s", idx, change.Field,
+				change.Sighting.lastFlightNo, expected[idx].field, expected[idx].flight)
+		}
+	}
+	if changes[1].Sighting != changes[2].Sighting || changes[0].Sighting == changes[1].Sighting ||
+		changes[2].Sighting == changes[3].Sighting {
+		t.Error("IdentityChanges share sightings across aircraft, expected one copy per aircraft")
+	}
+}
@@ -0,0 +1,224 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log" //nolint:depguard // Don't feel like using slog
+	"sync"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// LoadProgress is told about every dataset which finished loading, how many have been loaded so
+// far and how many there are.
+type LoadProgress func(dataset string, loaded int, total int)
+
+// PrintLoadProgress reports the progress of loading the datasets on a single line of the output,
+// which is finished once all of them are loaded.
+func PrintLoadProgress(out io.Writer) LoadProgress {
+	return func(dataset string, loaded int, total int) {
+		_, _ = fmt.Fprintf(out, "\rloading datasets %d/%d: %-20s", loaded, total, dataset)
+		if loaded == total {
+			_, _ = fmt.Fprintln(out)
+		}
+	}
+}
+
+// MissingDataset is a dataset which couldn't be loaded, so the enrichment it provides is disabled.
+type MissingDataset struct {
+	Name       string // Name of the dataset, e.g. "airlines".
+	Enrichment string // Enrichment which is disabled without it, e.g. "operator names".
+	Err        error  // Err is why the dataset couldn't be loaded.
+}
+
+// datasets are the datasets which are needed from the start.
+type datasets struct {
+	icaoToAircraft     map[string]dash.IcaoAircraft
+	icaoToAirline      map[string]dash.IcaoOperator
+	iataToIcaoAirline  map[string]string
+	regPrefixToCountry map[string]string
+	typeSpecs          map[string]dash.TypeSpec
+	fleetSizes         map[string]int
+	typeFamilies       map[string]string // typeFamilies maps ICAO types to their family.
+	notableAircraft    map[string]dash.NotableAircraft
+	airports           []dash.Airport
+	missing            []MissingDataset // missing are the datasets which couldn't be loaded.
+}
+
+// err joins the errors of all missing datasets, nil if all of them were loaded.
+func (loaded datasets) err() error {
+	var err error
+	for _, missing := range loaded.missing {
+		err = errors.Join(err, missing.Err)
+	}
+	return err
+}
+
+// loadDatasets loads the datasets concurrently, since they are read from disk one after another
+// otherwise, which takes a while on slow storage like SD cards.
+// A dataset which can't be loaded is left empty and listed as missing, so that airspottr still
+// runs with the others, just without the enrichment of the missing one.
+func loadDatasets(dataDirs []string, progress LoadProgress) datasets {
+	var loaded datasets
+	loaders := []struct {
+		name       string
+		enrichment string
+		load       func() error
+	}{
+		{"aircraft types", "type names and type rarity", func() error {
+			var err error
+			if loaded.icaoToAircraft, err = dash.GetIcaoToAircraftMap(dataDirs); err != nil {
+				loaded.icaoToAircraft = make(map[string]dash.IcaoAircraft)
+				return fmt.Errorf("%w caused by %w", errParseIcaoAircraftMap, err)
+			}
+			return nil
+		}},
+		{"airlines", "operator names and operator rarity", func() error {
+			var err error
+			if loaded.icaoToAirline, err = dash.GetIcaoToAirlineMap(dataDirs); err != nil {
+				loaded.icaoToAirline = make(map[string]dash.IcaoOperator)
+				return fmt.Errorf("%w caused by %w", errParseIcaoAirlineMap, err)
+			}
+			return nil
+		}},
+		{"IATA airline codes", "operators of IATA callsigns", func() error {
+			var err error
+			if loaded.iataToIcaoAirline, err = dash.GetIataToIcaoAirlineMap(dataDirs); err != nil {
+				loaded.iataToIcaoAirline = make(map[string]string)
+				return fmt.Errorf("%w caused by %w", errParseIataAirlineMap, err)
+			}
+			return nil
+		}},
+		{"registration prefixes", "countries by registration", func() error {
+			var err error
+			if loaded.regPrefixToCountry, err = dash.GetRegPrefixMap(dataDirs); err != nil {
+				loaded.regPrefixToCountry = make(map[string]string)
+				return fmt.Errorf("%w caused by %w", errParseRegToCountryMap, err)
+			}
+			return nil
+		}},
+		{"type specs", "type specifications", func() error {
+			var err error
+			if loaded.typeSpecs, err = dash.GetTypeSpecMap(dataDirs); err != nil {
+				loaded.typeSpecs = make(map[string]dash.TypeSpec)
+				return fmt.Errorf("%w caused by %w", errParseTypeSpecMap, err)
+			}
+			return nil
+		}},
+		{"fleet sizes", "global rarity", func() error {
+			// The fleet sizes are optional, without them there is no global rarity.
+			var err error
+			loaded.fleetSizes, err = dash.GetFleetSizeMap(dataDirs)
+			if errors.Is(err, fs.ErrNotExist) {
+				loaded.fleetSizes = make(map[string]int)
+				return nil
+			}
+			if err != nil {
+				loaded.fleetSizes = make(map[string]int)
+				return fmt.Errorf("%w caused by %w", errParseFleetSizeMap, err)
+			}
+			return nil
+		}},
+		{"type families", "type families", func() error {
+			// The type families are optional, without them every type is a family of its own.
+			var err error
+			loaded.typeFamilies, err = dash.GetTypeFamilyMap(dataDirs)
+			if errors.Is(err, fs.ErrNotExist) {
+				loaded.typeFamilies = make(map[string]string)
+				return nil
+			}
+			if err != nil {
+				loaded.typeFamilies = make(map[string]string)
+				return fmt.Errorf("%w caused by %w", errParseTypeFamilyMap, err)
+			}
+			return nil
+		}},
+		{"notable aircraft", "notable aircraft", func() error {
+			// The notable aircraft are optional, without them no aircraft is notable.
+			var err error
+			loaded.notableAircraft, err = dash.GetNotableAircraftMap(dataDirs)
+			if errors.Is(err, fs.ErrNotExist) {
+				loaded.notableAircraft = make(map[string]dash.NotableAircraft)
+				return nil
+			}
+			if err != nil {
+				loaded.notableAircraft = make(map[string]dash.NotableAircraft)
+				return fmt.Errorf("%w caused by %w", errParseNotableAircraftMap, err)
+			}
+			return nil
+		}},
+		{"airports", "go-around detection", func() error {
+			// The airports are optional, without them no go-arounds are detected.
+			var err error
+			loaded.airports, err = dash.GetAirports(dataDirs)
+			if errors.Is(err, fs.ErrNotExist) {
+				loaded.airports = nil
+				return nil
+			}
+			if err != nil {
+				loaded.airports = nil
+				return fmt.Errorf("%w caused by %w", errParseAirports, err)
+			}
+			return nil
+		}},
+	}
+
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	loadErrs := make([]error, len(loaders))
+	loadedCount := 0
+	for idx, loader := range loaders {
+		waitGroup.Go(func() {
+			err := loader.load()
+			mutex.Lock()
+			defer mutex.Unlock()
+			loadErrs[idx] = err
+			loadedCount++
+			if progress != nil {
+				progress(loader.name, loadedCount, len(loaders))
+			}
+		})
+	}
+	waitGroup.Wait()
+
+	// The missing datasets are listed in the order of the loaders, not in the order they failed.
+	for idx, err := range loadErrs {
+		if err != nil {
+			loaded.missing = append(loaded.missing, MissingDataset{
+				Name:       loaders[idx].name,
+				Enrichment: loaders[idx].enrichment,
+				Err:        fmt.Errorf("loadDatasets: %w", err),
+			})
+		}
+	}
+	return loaded
+}
+
+// lazyMilCodes loads the military callsign codes on first use, since most aircraft are identified
+// by their airline already. If they can't be loaded, no operators are found by them.
+func lazyMilCodes(dataDirs []string, errOut *log.Logger) func() map[string]string {
+	return sync.OnceValue(func() map[string]string {
+		milCodeToOperator, err := dash.GetMilCodeToOperatorMap(dataDirs)
+		if err != nil {
+			errOut.Println(fmt.Errorf("lazyMilCodes: %w caused by %w", errParseMilCodeMap, err))
+			return map[string]string{}
+		}
+		return milCodeToOperator
+	})
+}
+
+// lazyHexRanges loads the allocation of hex addresses to countries on first use, since most
+// aircraft are identified by their registration already. If it can't be loaded, no countries are
+// found by it.
+func lazyHexRanges(dataDirs []string, errOut *log.Logger) func() *dash.HexRangeIndex {
+	return sync.OnceValue(func() *dash.HexRangeIndex {
+		hexRangeToCountry, err := dash.GetHexRangeToCountryMap(dataDirs)
+		if err != nil {
+			errOut.Println(
+				fmt.Errorf("lazyHexRanges: %w caused by %w", errParseHexRangeToCountryMap, err))
+		}
+		return dash.NewHexRangeIndex(hexRangeToCountry)
+	})
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"testing"
+)
+
+func TestLoadDatasets(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var reported []string
+	loaded := loadDatasets(nil, func(dataset string, loadedCount int, total int) {
+		reported = append(reported, dataset)
+		if loadedCount != len(reported) || total != 9 {
+			t.Errorf("progress %d/%d after %d datasets", loadedCount, total, len(reported))
+		}
+	})
+	if err := loaded.err(); err != nil {
+		t.Fatalf("loadDatasets() error = %v", err)
+	}
+	if len(reported) != 9 {
+		t.Errorf("progress reported %v, expected all 9 datasets", reported)
+	}
+	if len(loaded.icaoToAircraft) == 0 || len(loaded.icaoToAirline) == 0 ||
+		len(loaded.iataToIcaoAirline) == 0 ||
+		len(loaded.regPrefixToCountry) == 0 || len(loaded.typeSpecs) == 0 || len(loaded.notableAircraft) == 0 ||
+		len(loaded.airports) == 0 {
+		t.Error("loadDatasets() left datasets empty")
+	}
+
+	var errOut bytes.Buffer
+	logger := log.New(&errOut, "", 0)
+	if len(lazyMilCodes(nil, logger)()) == 0 {
+		t.Error("lazyMilCodes() loaded no codes")
+	}
+	if _, ok := lazyHexRanges(nil, logger)().Lookup(0x3c6444); !ok {
+		t.Error("lazyHexRanges() didn't find the country of 3c6444")
+	}
+	if errOut.Len() > 0 {
+		t.Errorf("lazy loading logged %q", errOut.String())
+	}
+}
+
+func TestLoadDatasetsMissing(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	loaded := loadDatasets(nil, nil)
+	if err := loaded.err(); !errors.Is(err, errParseIcaoAircraftMap) || !errors.Is(err, errParseTypeSpecMap) {
+		t.Errorf("loadDatasets() error = %v, expected all missing datasets", err)
+	}
+	// The optional datasets aren't missed, the five others are left empty to run without them.
+	if len(loaded.missing) != 5 || loaded.missing[0].Name != "aircraft types" ||
+		loaded.missing[1].Enrichment != "operator names and operator rarity" {
+		t.Errorf("loadDatasets() missing %+v, expected the five required datasets in order", loaded.missing)
+	}
+	if loaded.icaoToAircraft == nil || loaded.icaoToAirline == nil || loaded.iataToIcaoAirline == nil ||
+		loaded.regPrefixToCountry == nil || loaded.typeSpecs == nil {
+		t.Error("loadDatasets() left missing datasets nil, expected them empty")
+	}
+
+	// Rarely used datasets don't stop airspottr if they are missing.
+	var errOut bytes.Buffer
+	logger := log.New(&errOut, "", 0)
+	if codes := lazyMilCodes(nil, logger)(); len(codes) != 0 {
+		t.Errorf("lazyMilCodes() = %v, expected none", codes)
+	}
+	if _, ok := lazyHexRanges(nil, logger)().Lookup(0x3c6444); ok {
+		t.Error("lazyHexRanges() found a country without data")
+	}
+	if errOut.Len() == 0 {
+		t.Error("lazy loading didn't log the missing datasets")
+	}
+}
+
+func TestNewDashboardMissingDatasets(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v, expected to run without the datasets", err)
+	}
+	if missing := dashboard.MissingDatasets(); len(missing) != 5 {
+		t.Errorf("MissingDatasets() = %+v, expected the five required datasets", missing)
+	}
+
+	// Aircraft are still processed, just without types, operators and countries.
+	//nolint:exhaustruct // a few fields only
+	dashboard.ProcessAircraftRecords([]AircraftRecord{{Hex: "3c6444", IcaoType: "A320", Flight: "DLH400"}})
+	if len(dashboard.CurrentAircraft) != 1 || dashboard.CurrentAircraft[0].CachedType != "" {
+		t.Errorf("ProcessAircraftRecords() = %+v, expected the aircraft without a type", dashboard.CurrentAircraft)
+	}
+}
@@ -0,0 +1,312 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+const (
+	dataVersionsDir    = "versions"
+	dataCurrentFile    = "current"
+	dataVersionFormat  = "20060102T150405Z"
+	dataVersionsKept   = 3
+	dataStoreDirPerm   = 0o750
+	dataStoreFilePerm  = 0o600
+	dataStagingPrefix  = ".staging-"
+	dataUserDirName    = "airspottr"
+	dataUserDirXDGName = "XDG_DATA_HOME"
+	// legacyDataPrefix prefixes the names of the files earlier versions kept in the working
+	// directory.
+	legacyDataPrefix = "airspottr_"
+
+	// saveInterval is how often a file which may change with every poll is written at most.
+	saveInterval = time.Minute
+)
+
+var (
+	errNoDataStore     = errors.New("no data directory")
+	errNoPriorVersion  = errors.New("no prior dataset version to roll back to")
+	errUnknownDataFile = errors.New("unknown dataset")
+)
+
+// UserDataDir returns where updated datasets are installed and the statistics are kept,
+// $XDG_DATA_HOME/airspottr or ~/.local/share/airspottr.
+func UserDataDir() (string, error) {
+	if dataHome := os.Getenv(dataUserDirXDGName); dataHome != "" {
+		return filepath.Join(dataHome, dataUserDirName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("UserDataDir: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", dataUserDirName), nil
+}
+
+// DefaultDataPath returns where the file of the given name is kept unless another path is given,
+// in the data directory of the user, or in the working directory if the user has none. A file
+// which earlier versions kept in the working directory, e.g. ./airspottr_history.jsonl, is still
+// used as long as it is there.
+func DefaultDataPath(name string) string {
+	if _, err := os.Stat(legacyDataPrefix + name); err == nil {
+		return legacyDataPrefix + name
+	}
+	dir, err := UserDataDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// openAppend opens the file at the given path for appending, creating it and its directory if
+// needed.
+func openAppend(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dataStoreDirPerm); err != nil {
+		return nil, fmt.Errorf("openAppend: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, dataStoreFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("openAppend: %w", err)
+	}
+	return file, nil
+}
+
+// saveSchedule tells when to write a file which may change with every poll, e.g. the records, so
+// that it is written at most once per saveInterval. What changed since is written on closing.
+type saveSchedule struct {
+	unsaved bool      // unsaved tells whether anything changed since the file was written.
+	saved   time.Time // saved is when the file was written last.
+}
+
+// due tells whether the file is to be written at the given time, noting first if it changed.
+func (s *saveSchedule) due(now time.Time, changed bool) bool {
+	s.unsaved = s.unsaved || changed
+	return s.unsaved && now.Sub(s.saved) >= saveInterval
+}
+
+// done notes that the file was tried to be written at the given time, which failed with saveErr
+// unless nil. A failed write is tried again once the next one is due.
+func (s *saveSchedule) done(now time.Time, saveErr error) {
+	s.unsaved = saveErr != nil
+	s.saved = now
+}
+
+// replaceFile writes the data to a temporary file next to the given path first, so that a failed
+// write can't destroy the existing file, and creates the directory if needed.
+func replaceFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), dataStoreDirPerm); err != nil {
+		return fmt.Errorf("replaceFile: %w", err)
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmpPath, data, dataStoreFilePerm); err != nil {
+		return fmt.Errorf("replaceFile: failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replaceFile: failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// DataStore keeps versions of updated datasets in a directory, one subdirectory per version,
+// together with which of the versions is current:
+//
+//	<dir>/current
+//	<dir>/versions/20260101T120000Z/ICAOList.csv
+//
+// Datasets which were never updated are taken from the bundled ones.
+type DataStore struct {
+	dir string // dir is empty if only the bundled datasets are used.
+}
+
+// NewDataStore creates the store of the datasets in the given directory, which is created on the
+// first install. An empty directory uses only the bundled datasets.
+func NewDataStore(dir string) *DataStore {
+	return &DataStore{dir: dir}
+}
+
+// Dirs returns the directories to look datasets up in, the current version first and the bundled
+// datasets last.
+func (s *DataStore) Dirs() []string {
+	current, err := s.Current()
+	if err != nil || current == "" {
+		return []string{dash.BundledDataDir}
+	}
+	return []string{s.versionDir(current), dash.BundledDataDir}
+}
+
+// Current returns the current version, which is empty if there is none.
+func (s *DataStore) Current() (string, error) {
+	if s.dir == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(filepath.Join(s.dir, dataCurrentFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Current: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// Versions returns all installed versions, oldest first.
+func (s *DataStore) Versions() ([]string, error) {
+	if s.dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(filepath.Join(s.dir, dataVersionsDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Versions: %w", err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), dataStagingPrefix) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	slices.Sort(versions) // versions are timestamps, so they sort by age
+	return versions, nil
+}
+
+// Stage creates a staging directory for the files of a new version.
+func (s *DataStore) Stage() (string, error) {
+	if s.dir == "" {
+		return "", fmt.Errorf("Stage: %w", errNoDataStore)
+	}
+	versionsDir := filepath.Join(s.dir, dataVersionsDir)
+	if err := os.MkdirAll(versionsDir, dataStoreDirPerm); err != nil {
+		return "", fmt.Errorf("Stage: %w", err)
+	}
+	staging, err := os.MkdirTemp(versionsDir, dataStagingPrefix)
+	if err != nil {
+		return "", fmt.Errorf("Stage: %w", err)
+	}
+	return staging, nil
+}
+
+// Install turns the staging directory into a new version and makes it the current one. Files
+// which aren't staged are carried over from the current version, so that updating some of the
+// datasets keeps the others. Only the newest versions are kept.
+func (s *DataStore) Install(staging string, now time.Time) (string, error) {
+	current, currentErr := s.Current()
+	if currentErr != nil {
+		return "", fmt.Errorf("Install: %w", currentErr)
+	}
+	if current != "" {
+		if err := carryOver(s.versionDir(current), staging); err != nil {
+			return "", fmt.Errorf("Install: %w", err)
+		}
+	}
+
+	version := now.UTC().Format(dataVersionFormat)
+	if err := os.Rename(staging, s.versionDir(version)); err != nil {
+		return "", fmt.Errorf("Install: %w", err)
+	}
+	if err := s.setCurrent(version); err != nil {
+		return "", fmt.Errorf("Install: %w", err)
+	}
+	if err := s.prune(version); err != nil {
+		return version, fmt.Errorf("Install: %w", err)
+	}
+	return version, nil
+}
+
+// Rollback makes the version before the current one current again. Without a version before it,
+// the bundled datasets are used again.
+func (s *DataStore) Rollback() (string, error) {
+	current, currentErr := s.Current()
+	if currentErr != nil {
+		return "", fmt.Errorf("Rollback: %w", currentErr)
+	}
+	if current == "" {
+		return "", fmt.Errorf("Rollback: %w", errNoPriorVersion)
+	}
+	versions, versionsErr := s.Versions()
+	if versionsErr != nil {
+		return "", fmt.Errorf("Rollback: %w", versionsErr)
+	}
+
+	previous := ""
+	if idx := slices.Index(versions, current); idx > 0 {
+		previous = versions[idx-1]
+	}
+	if err := s.setCurrent(previous); err != nil {
+		return "", fmt.Errorf("Rollback: %w", err)
+	}
+	return previous, nil
+}
+
+func (s *DataStore) versionDir(version string) string {
+	return filepath.Join(s.dir, dataVersionsDir, version)
+}
+
+// setCurrent switches to the version atomically, so that a running airspottr which reloads the
+// datasets never sees half of it. An empty version switches to the bundled datasets.
+func (s *DataStore) setCurrent(version string) error {
+	path := filepath.Join(s.dir, dataCurrentFile)
+	if version == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("setCurrent: %w", err)
+		}
+		return nil
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(version+"\n"), dataStoreFilePerm); err != nil {
+		return fmt.Errorf("setCurrent: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("setCurrent: %w", err)
+	}
+	return nil
+}
+
+// prune removes all but the newest versions, and never the current one.
+func (s *DataStore) prune(current string) error {
+	versions, err := s.Versions()
+	if err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+	for len(versions) > dataVersionsKept {
+		if versions[0] != current {
+			if removeErr := os.RemoveAll(s.versionDir(versions[0])); removeErr != nil {
+				return fmt.Errorf("prune: %w", removeErr)
+			}
+		}
+		versions = versions[1:]
+	}
+	return nil
+}
+
+// carryOver copies the files of the version directory which aren't in the staging directory.
+func carryOver(versionDir string, staging string) error {
+	entries, err := os.ReadDir(versionDir)
+	if err != nil {
+		return fmt.Errorf("carryOver: %w", err)
+	}
+	for _, entry := range entries {
+		target := filepath.Join(staging, entry.Name())
+		if _, statErr := os.Stat(target); statErr == nil {
+			continue
+		}
+		content, readErr := os.ReadFile(filepath.Join(versionDir, entry.Name()))
+		if readErr != nil {
+			return fmt.Errorf("carryOver: %w", readErr)
+		}
+		if writeErr := os.WriteFile(target, content, dataStoreFilePerm); writeErr != nil {
+			return fmt.Errorf("carryOver: %w", writeErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+const (
+	// maxDatasetSize limits the download of a dataset, which are a few MB at most. A larger one
+	// fails instead of being cut off and installed truncated.
+	maxDatasetSize = 64 << 20
+	// minDatasetShare is how many entries an updated dataset needs to have at least, relative to
+	// the one in use, e.g. to keep a truncated download from replacing a complete dataset.
+	minDatasetShare = 0.5
+)
+
+var errDatasetShrunk = errors.New("dataset has far fewer entries than the one in use")
+
+// UpdatableDatasets maps the names of the datasets which can be updated, as used in the
+// "data_urls" of the config, to their files.
+var UpdatableDatasets = map[string]string{ //nolint:gochecknoglobals // constant lookup
+	"types":    dash.IcaoListFile,
+	"airlines": dash.AirlineListFile,
+	"military": dash.MilCodeFile,
+	"fleet":    dash.FleetSizeFile,
+	"families": dash.TypeFamilyFile,
+	"notable":  dash.NotableAircraftFile,
+	"airports": dash.AirportsFile,
+}
+
+// DatasetUpdate tells how many entries an updated dataset has, compared to the one it replaced.
+type DatasetUpdate struct {
+	Name     string
+	Entries  int
+	Previous int // Previous is the number of entries of the dataset in use before the update.
+}
+
+// UpdateData downloads the datasets from the given URLs, by dataset name, validates them and
+// installs them as a new version of the store. Nothing is installed unless all of them are valid,
+// so the datasets in use are either all updated or all kept.
+func UpdateData(
+	store *DataStore,
+	urls map[string]string,
+	client *http.Client,
+	now time.Time,
+) (string, []DatasetUpdate, error) {
+	if len(urls) == 0 {
+		return "", nil, fmt.Errorf("UpdateData: %w: no data_urls configured", errInvalidConfig)
+	}
+
+	staging, stageErr := store.Stage()
+	if stageErr != nil {
+		return "", nil, fmt.Errorf("UpdateData: %w", stageErr)
+	}
+	installed := false
+	defer func() {
+		if !installed {
+			_ = os.RemoveAll(staging)
+		}
+	}()
+
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	inUse := store.Dirs()
+	updates := make([]DatasetUpdate, 0, len(names))
+	for _, name := range names {
+		update, err := updateDataset(client, name, urls[name], staging, inUse)
+		if err != nil {
+			return "", nil, fmt.Errorf("UpdateData: %w", err)
+		}
+		updates = append(updates, update)
+	}
+
+	version, installErr := store.Install(staging, now)
+	if installErr != nil {
+		return "", nil, fmt.Errorf("UpdateData: %w", installErr)
+	}
+	installed = true
+	return version, updates, nil
+}
+
+// updateDataset downloads a dataset into the staging directory and validates it against the one
+// in use.
+func updateDataset(
+	client *http.Client,
+	name string,
+	datasetURL string,
+	staging string,
+	inUse []string,
+) (DatasetUpdate, error) {
+	update := DatasetUpdate{Name: name, Entries: 0, Previous: 0}
+	file, ok := UpdatableDatasets[name]
+	if !ok {
+		return update, fmt.Errorf("updateDataset: %w %q", errUnknownDataFile, name)
+	}
+
+	path := filepath.Join(staging, file)
+	if err := downloadDataset(client, datasetURL, path); err != nil {
+		return update, fmt.Errorf("updateDataset: %s: %w", name, err)
+	}
+
+	entries, validateErr := dash.ValidateDataFile(file, path)
+	if validateErr != nil {
+		return update, fmt.Errorf("updateDataset: %s: %w", name, validateErr)
+	}
+	update.Entries = entries
+
+	// The dataset in use may be missing or broken, in which case anything valid is better.
+	if previous, err := dash.ValidateDataFile(file, dash.FindDataFile(inUse, file)); err == nil {
+		update.Previous = previous
+		if float64(entries) < minDatasetShare*float64(previous) {
+			return update, fmt.Errorf(
+				"updateDataset: %s: %w: %d instead of %d", name, errDatasetShrunk, entries, previous)
+		}
+	}
+	return update, nil
+}
+
+// downloadDataset writes the dataset at the URL to the path. The URLs are configured by the user,
+// so they aren't restricted to the known hosts.
+func downloadDataset(client *http.Client, datasetURL string, path string) error {
+	parsed, parseErr := url.Parse(datasetURL)
+	if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("downloadDataset: %w: %q", ErrInvalidURL, datasetURL)
+	}
+
+	req, reqErr := http.NewRequestWithContext(context.Background(), http.MethodGet, datasetURL, nil)
+	if reqErr != nil {
+		return fmt.Errorf("downloadDataset: %w", reqErr)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, respErr := client.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("downloadDataset: %w", respErr)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloadDataset: %w %s", ErrNonOkResponse, resp.Status)
+	}
+
+	content, readErr := io.ReadAll(newCappedReader(resp.Body, maxDatasetSize))
+	if readErr != nil {
+		return fmt.Errorf("downloadDataset: %w", readErr)
+	}
+	if len(content) == 0 {
+		return fmt.Errorf("downloadDataset: %w", ErrEmptyResponseBody)
+	}
+	if err := os.WriteFile(path, content, dataStoreFilePerm); err != nil {
+		return fmt.Errorf("downloadDataset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+const (
+	testTypesCSV = "Aircraft TypeDesignator,Class,Number+Engine Type,\"MANUFACTURER, Model\"\n" +
+		"A388,LandPlane,4/Jet,\"AIRBUS, A-380-800\"\n" +
+		"B748,LandPlane,4/Jet,\"BOEING, 747-8\"\n"
+	testAirlinesCSV = "Company,country,Telephony,3Ltr\n" +
+		"LUFTHANSA,GERMANY,LUFTHANSA,DLH\n"
+)
+
+func TestUpdateData(t *testing.T) {
+	served := map[string]string{"/types.csv": testTypesCSV, "/airlines.csv": testAirlinesCSV}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		content, ok := served[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = io.WriteString(w, content)
+	}))
+	defer server.Close()
+
+	store := NewDataStore(t.TempDir())
+	start := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	version, updates, err := UpdateData(
+		store, map[string]string{"types": server.URL + "/types.csv"}, server.Client(), start)
+	if err != nil {
+		t.Fatalf("UpdateData() error = %v", err)
+	}
+	if version != "20260101T120000Z" || len(updates) != 1 || updates[0].Entries != 2 {
+		t.Errorf("UpdateData() = %q, %+v", version, updates)
+	}
+	if dirs := store.Dirs(); len(dirs) != 2 || dirs[1] != dash.BundledDataDir {
+		t.Errorf("Dirs() = %v, expected the new version and the bundled datasets", dirs)
+	}
+	if path := dash.FindDataFile(store.Dirs(), dash.IcaoListFile); !strings.Contains(path, version) {
+		t.Errorf("FindDataFile() = %s, expected the updated types", path)
+	}
+
+	// Updating the airlines keeps the types of the version before.
+	next, _, err := UpdateData(
+		store, map[string]string{"airlines": server.URL + "/airlines.csv"}, server.Client(),
+		start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("UpdateData() error = %v", err)
+	}
+	for _, file := range []string{dash.IcaoListFile, dash.AirlineListFile} {
+		if _, err := os.Stat(filepath.Join(store.versionDir(next), file)); err != nil {
+			t.Errorf("version %s lacks %s: %v", next, file, err)
+		}
+	}
+
+	// Invalid downloads leave the datasets in use alone.
+	failing := []map[string]string{
+		{"types": server.URL + "/missing.csv"},
+		{"types": server.URL + "/airlines.csv"},
+		{"weather": server.URL + "/types.csv"},
+		{"types": "ftp://example.com/types.csv"},
+	}
+	for _, urls := range failing {
+		if _, _, err := UpdateData(store, urls, server.Client(), start.Add(2*time.Hour)); err == nil {
+			t.Errorf("UpdateData(%v) succeeded", urls)
+		}
+		if current, _ := store.Current(); current != next {
+			t.Errorf("UpdateData(%v) switched to %q", urls, current)
+		}
+	}
+	if versions, _ := store.Versions(); len(versions) != 2 {
+		t.Errorf("Versions() = %v, expected the two installed ones without staging leftovers", versions)
+	}
+
+	// Rolling back goes to the version before, and then to the bundled datasets.
+	if previous, err := store.Rollback(); err != nil || previous != version {
+		t.Errorf("Rollback() = %q, %v, expected %q", previous, err, version)
+	}
+	if previous, err := store.Rollback(); err != nil || previous != "" {
+		t.Errorf("Rollback() = %q, %v, expected the bundled datasets", previous, err)
+	}
+	if _, err := store.Rollback(); !errors.Is(err, errNoPriorVersion) {
+		t.Errorf("Rollback() error = %v, expected %v", err, errNoPriorVersion)
+	}
+}
+
+func TestUpdateDataRejectsShrunkDataset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, testTypesCSV)
+	}))
+	defer server.Close()
+
+	t.Chdir("..") // the bundled types have far more than the two served
+	store := NewDataStore(t.TempDir())
+	_, _, err := UpdateData(
+		store, map[string]string{"types": server.URL}, server.Client(), time.Now())
+	if !errors.Is(err, errDatasetShrunk) {
+		t.Errorf("UpdateData() error = %v, expected %v", err, errDatasetShrunk)
+	}
+}
+
+func TestDataStorePrunesOldVersions(t *testing.T) {
+	store := NewDataStore(t.TempDir())
+	start := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	for idx := range dataVersionsKept + 2 {
+		staging, err := store.Stage()
+		if err != nil {
+			t.Fatalf("Stage() error = %v", err)
+		}
+		if _, err := store.Install(staging, start.Add(time.Duration(idx)*time.Hour)); err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+	}
+
+	versions, _ := store.Versions()
+	current, _ := store.Current()
+	if len(versions) != dataVersionsKept || versions[len(versions)-1] != current {
+		t.Errorf("Versions() = %v with current %q, expected the newest %d", versions, current,
+			dataVersionsKept)
+	}
+}
+
+func TestDefaultDataPath(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Chdir(t.TempDir())
+
+	if path := DefaultDataPath(HistoryFileName); path != filepath.Join(dataHome, "airspottr", "history.jsonl") {
+		t.Errorf("DefaultDataPath() = %q, expected the data directory", path)
+	}
+
+	// The file of an earlier version in the working directory is still used.
+	if err := os.WriteFile("airspottr_history.jsonl", nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if path := DefaultDataPath(HistoryFileName); path != "airspottr_history.jsonl" {
+		t.Errorf("DefaultDataPath() = %q, expected the file in the working directory", path)
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// maxDeadReckoning is how long after its last position an aircraft is estimated to have kept its
+// track and speed. Beyond that it has likely turned, so it is left where it was seen.
+const maxDeadReckoning = 2 * time.Minute
+
+// EstimatedPosition dead-reckons where the aircraft is now, given how long ago it was polled, by
+// moving its live position along its track at its ground speed. It returns false for aircraft
+// without a live position, on the ground or standing still, and if the position is too old.
+func (ac *AircraftRecord) EstimatedPosition(sincePoll time.Duration) (KnownPosition, bool) {
+	position, ok := ac.KnownPosition()
+	if !ok || position.Stale || ac.AltBaro.IsGround() || ac.GroundSpeed <= 0 {
+		return position, false
+	}
+	age := time.Duration(position.SeenPos*float64(time.Second)) + sincePoll
+	if age <= 0 || age > maxDeadReckoning {
+		return position, false
+	}
+
+	kilometers := ac.GroundSpeed * kmPerNautical * age.Hours()
+	estimated := dash.Destination(dash.NewCoordinates(position.Lat, position.Lon), ac.Track, kilometers)
+	return KnownPosition{Lat: estimated.Latitude, Lon: estimated.Longitude, SeenPos: 0, Stale: false}, true
+}
+
+// EstimatedDistance is the distance in [km] from our location to where the aircraft is estimated
+// to be now, given how long ago it was polled. It returns false if there is no estimate, see
+// EstimatedPosition.
+func (db *Dashboard) EstimatedDistance(aircraft *AircraftRecord, sincePoll time.Duration) (float64, bool) {
+	position, ok := aircraft.EstimatedPosition(sincePoll)
+	if !ok {
+		return aircraft.CachedDist, false
+	}
+	here := dash.NewCoordinates(db.Lat, db.Lon)
+	return dash.Distance(here, dash.NewCoordinates(position.Lat, position.Lon)).Kilometers(), true
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimatedPosition(t *testing.T) {
+	//nolint:exhaustruct // only what dead reckoning needs
+	flying := AircraftRecord{
+		Lat: 53.5, Lon: 10, SeenPos: 5, AltBaro: NewAltitude(35000), GroundSpeed: 480, Track: 90,
+	}
+	unchanged := func(ac AircraftRecord) AircraftRecord { return ac }
+	tests := []struct {
+		name      string
+		aircraft  func(AircraftRecord) AircraftRecord
+		sincePoll time.Duration
+		expected  bool
+	}{
+		{name: "flying", aircraft: unchanged, sincePoll: 25 * time.Second, expected: true},
+		{name: "too long ago", aircraft: unchanged, sincePoll: 3 * time.Minute, expected: false},
+		{
+			name:      "on the ground",
+			aircraft:  func(ac AircraftRecord) AircraftRecord { ac.AltBaro = GroundAltitude(); return ac },
+			sincePoll: 25 * time.Second,
+			expected:  false,
+		},
+		{
+			name:      "standing still",
+			aircraft:  func(ac AircraftRecord) AircraftRecord { ac.GroundSpeed = 0; return ac },
+			sincePoll: 25 * time.Second,
+			expected:  false,
+		},
+		{
+			name: "only last known position",
+			aircraft: func(ac AircraftRecord) AircraftRecord {
+				ac.LastPosition = &LastPosition{Lat: ac.Lat, Lon: ac.Lon, Nic: 0, Rc: 0, SeenPos: 40}
+				ac.Lat, ac.Lon = 0, 0
+				return ac
+			},
+			sincePoll: 25 * time.Second,
+			expected:  false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aircraft := test.aircraft(flying)
+			if _, ok := aircraft.EstimatedPosition(test.sincePoll); ok != test.expected {
+				t.Errorf("EstimatedPosition() ok = %v, expected %v", ok, test.expected)
+			}
+		})
+	}
+
+	// 480 kt for 30 s are 7.4 km, due east.
+	dashboard := &Dashboard{Lat: 53.5, Lon: 10} //nolint:exhaustruct // only the location
+	distance, ok := dashboard.EstimatedDistance(&flying, 25*time.Second)
+	if !ok || math.Abs(distance-7.408) > 0.01 {
+		t.Errorf("EstimatedDistance() = %v, %v, expected 7.4 km", distance, ok)
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"math"
+	"time"
+)
+
+// DecayedCounter counts how often properties have been seen, but lets every sighting lose weight
+// exponentially over time.
+// After one half life, a sighting only counts half, after two half lives a quarter and so on.
+type DecayedCounter struct {
+	halfLife time.Duration
+	weights  map[string]decayedWeight
+	total    decayedWeight
+}
+
+type decayedWeight struct {
+	value   float64
+	updated time.Time
+}
+
+// NewDecayedCounter creates an empty DecayedCounter with the given half life.
+func NewDecayedCounter(halfLife time.Duration) *DecayedCounter {
+	return &DecayedCounter{
+		halfLife: halfLife,
+		weights:  make(map[string]decayedWeight),
+		total:    decayedWeight{value: 0, updated: time.Time{}},
+	}
+}
+
+// Add records a sighting of the property at the given time and returns its new decayed weight.
+func (c *DecayedCounter) Add(property string, now time.Time) float64 {
+	weight := c.decay(c.weights[property], now)
+	weight.value++
+	c.weights[property] = weight
+
+	c.total = c.decay(c.total, now)
+	c.total.value++
+
+	return weight.value
+}
+
+// Weight returns the decayed weight of the property at the given time.
+func (c *DecayedCounter) Weight(property string, now time.Time) float64 {
+	return c.decay(c.weights[property], now).value
+}
+
+// Total returns the decayed weight of all sightings at the given time.
+func (c *DecayedCounter) Total(now time.Time) float64 {
+	return c.decay(c.total, now).value
+}
+
+// Counts returns the decayed weights of all properties at the given time, rounded to the nearest
+// integer, but never below one.
+func (c *DecayedCounter) Counts(now time.Time) map[string]int {
+	counts := make(map[string]int, len(c.weights))
+	for property, weight := range c.weights {
+		counts[property] = roundCount(c.decay(weight, now).value)
+	}
+	return counts
+}
+
+// decay reduces the given weight according to the time passed since its last update.
+func (c *DecayedCounter) decay(weight decayedWeight, now time.Time) decayedWeight {
+	if weight.updated.IsZero() || !now.After(weight.updated) {
+		return decayedWeight{value: weight.value, updated: now}
+	}
+	halfLives := float64(now.Sub(weight.updated)) / float64(c.halfLife)
+	return decayedWeight{value: weight.value * math.Pow(0.5, halfLives), updated: now} //nolint:mnd // half
+}
+
+// roundCount turns a decayed weight back into a count.
+// Anything that has been seen at all is counted at least once.
+func roundCount(weight float64) int {
+	return max(1, int(math.Round(weight)))
+}
+
+// foldTail forgets the properties of the least decayed weight once there are more than the cap,
+// until there are few enough. Their sightings still count towards the total.
+func (c *DecayedCounter) foldTail(limit int, now time.Time) {
+	if len(c.weights) <= limit {
+		return
+	}
+	counts := make(map[string]int, len(c.weights))
+	for property := range c.weights {
+		// The weights are compared in thousandths, which is precise enough to find the least.
+		counts[property] = int(c.Weight(property, now) * 1000) //nolint:mnd // thousandths
+	}
+	foldTail(counts, limit)
+	for property := range c.weights {
+		if _, kept := counts[property]; !kept {
+			delete(c.weights, property)
+		}
+	}
+}
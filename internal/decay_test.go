@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayedCounter(t *testing.T) {
+	halfLife := 24 * time.Hour
+	start := time.Unix(0, 0)
+	counter := NewDecayedCounter(halfLife)
+
+	for range 8 {
+		counter.Add("A320", start)
+	}
+	counter.Add("A380", start)
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		expectedA320  float64
+		expectedTotal float64
+	}{
+		{name: "no time passed", now: start, expectedA320: 8, expectedTotal: 9},
+		{name: "one half life", now: start.Add(halfLife), expectedA320: 4, expectedTotal: 4.5},
+		{name: "three half lives", now: start.Add(3 * halfLife), expectedA320: 1, expectedTotal: 1.125},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := counter.Weight("A320", test.now); math.Abs(got-test.expectedA320) > 1e-9 {
+				t.Errorf("Weight() = %v, want %v", got, test.expectedA320)
+			}
+			if got := counter.Total(test.now); math.Abs(got-test.expectedTotal) > 1e-9 {
+				t.Errorf("Total() = %v, want %v", got, test.expectedTotal)
+			}
+		})
+	}
+
+	// Long forgotten properties are still counted at least once.
+	counts := counter.Counts(start.Add(100 * halfLife))
+	if counts["A380"] != 1 {
+		t.Errorf("Counts()[A380] = %d, want 1", counts["A380"])
+	}
+}
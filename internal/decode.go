@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// diagRecord counts aircraft records which aren't JSON objects at all.
+	diagRecord = "(record)"
+	// diagHex counts aircraft records without hex, which can't be told apart and are left out.
+	diagHex = "hex"
+)
+
+var errUnexpectedJSON = errors.New("unexpected JSON")
+
+// DecodeDiagnostics counts what couldn't be decoded in the responses of the aircraft sources, by
+// JSON field name. A field which can't be decoded is left empty rather than failing the response.
+type DecodeDiagnostics struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+// NewDecodeDiagnostics creates empty diagnostics.
+func NewDecodeDiagnostics() *DecodeDiagnostics {
+	return &DecodeDiagnostics{mutex: sync.Mutex{}, counts: make(map[string]int)}
+}
+
+func (d *DecodeDiagnostics) add(field string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.counts[field]++
+}
+
+// Counts returns how often each field couldn't be decoded so far.
+func (d *DecodeDiagnostics) Counts() map[string]int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	counts := make(map[string]int, len(d.counts))
+	for field, count := range d.counts {
+		counts[field] = count
+	}
+	return counts
+}
+
+// Total returns how many fields couldn't be decoded so far.
+func (d *DecodeDiagnostics) Total() int {
+	total := 0
+	for _, count := range d.Counts() {
+		total += count
+	}
+	return total
+}
+
+// String lists the fields which couldn't be decoded, most frequent first, e.g. "alt_baro 3, gs 1".
+func (d *DecodeDiagnostics) String() string {
+	counts := d.Counts()
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	fields := make([]string, 0, len(counts))
+	for field := range counts {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if counts[fields[i]] != counts[fields[j]] {
+			return counts[fields[i]] > counts[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+
+	entries := make([]string, len(fields))
+	for idx, field := range fields {
+		entries[idx] = fmt.Sprintf("%s %d", field, counts[field])
+	}
+	return strings.Join(entries, ", ")
+}
+
+// aircraftRecordFields maps the lower-cased JSON names of the fields of AircraftRecord to their
+// index, matching field names case-insensitively like encoding/json does.
+//
+//nolint:gochecknoglobals // computed once, like a constant
+var aircraftRecordFields = sync.OnceValue(func() map[string]int {
+	recordType := reflect.TypeFor[AircraftRecord]()
+	fields := make(map[string]int, recordType.NumField())
+	for idx := range recordType.NumField() {
+		name, _, _ := strings.Cut(recordType.Field(idx).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[strings.ToLower(name)] = idx
+	}
+	return fields
+})
+
+// recordFilter tells whether to keep an aircraft record while it is decoded, nil keeps all.
+type recordFilter func(record *AircraftRecord) bool
+
+// parseReadsbAircraft reads the readsb-style JSON served by most sources, i.e. an object listing
+// the aircraft as "aircraft" or "ac". It tolerates what the upstream APIs occasionally get wrong:
+// unknown fields are ignored, numbers in strings are read as numbers, missing or broken aircraft
+// lists are skipped and fields which can't be decoded are left empty. Only a response which isn't
+// a JSON object at all is an error.
+// The aircraft lists are decoded one record at a time, straight from the body, and only the
+// records the filter keeps are collected, so that large responses aren't held in memory twice.
+// Everything that couldn't be decoded is counted in the diagnostics.
+func parseReadsbAircraft(body io.Reader, diag *DecodeDiagnostics, keep recordFilter) ([]AircraftRecord, error) {
+	decoder := json.NewDecoder(body)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, fmt.Errorf("parseReadsbAircraft: failed to unmarshal Json: %w", err)
+	}
+
+	var aircraft []AircraftRecord
+	for decoder.More() {
+		nameToken, nameErr := decoder.Token()
+		if nameErr != nil {
+			return nil, fmt.Errorf("parseReadsbAircraft: failed to unmarshal Json: %w", nameErr)
+		}
+		// Some sources list the aircraft as "ac" rather than "aircraft".
+		name, _ := nameToken.(string)
+		if name != "aircraft" && name != "ac" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, fmt.Errorf("parseReadsbAircraft: failed to unmarshal Json: %w", err)
+			}
+			continue
+		}
+
+		listAircraft, listErr := decodeAircraftList(decoder, name, diag, keep)
+		if listErr != nil {
+			return nil, fmt.Errorf("parseReadsbAircraft: failed to unmarshal Json: %w", listErr)
+		}
+		aircraft = append(aircraft, listAircraft...)
+	}
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, fmt.Errorf("parseReadsbAircraft: failed to unmarshal Json: %w", err)
+	}
+	return aircraft, nil
+}
+
+// decodeAircraftList decodes the aircraft list of the given name, one record at a time. A list
+// which isn't an array is skipped and counted in the diagnostics, only broken JSON is an error.
+func decodeAircraftList(
+	decoder *json.Decoder,
+	name string,
+	diag *DecodeDiagnostics,
+	keep recordFilter,
+) ([]AircraftRecord, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("decodeAircraftList: %w", err)
+	}
+	delim, isDelim := token.(json.Delim)
+	switch {
+	case token == nil:
+		return nil, nil
+	case !isDelim:
+		diag.add(name)
+		return nil, nil
+	case delim == '{':
+		diag.add(name)
+		return nil, skipRest(decoder)
+	}
+
+	var aircraft []AircraftRecord
+	for decoder.More() {
+		var rawRecord json.RawMessage
+		if err := decoder.Decode(&rawRecord); err != nil {
+			return nil, fmt.Errorf("decodeAircraftList: %w", err)
+		}
+		if record, ok := decodeAircraftRecord(rawRecord, diag); ok && (keep == nil || keep(&record)) {
+			aircraft = append(aircraft, record)
+		}
+	}
+	return aircraft, expectDelim(decoder, ']')
+}
+
+// expectDelim reads the next token, which must be the given delimiter.
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("expectDelim: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("%w: %v instead of %v", errUnexpectedJSON, token, expected)
+	}
+	return nil
+}
+
+// skipRest skips the rest of the object or array whose opening delimiter has just been read.
+func skipRest(decoder *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("skipRest: %w", err)
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// decodeAircraftRecord decodes every field of the record on its own, so that a single broken
+// field doesn't cost the entire record.
+func decodeAircraftRecord(
+	rawRecord json.RawMessage,
+	diag *DecodeDiagnostics,
+) (AircraftRecord, bool) {
+	var record AircraftRecord
+	// Most records are well-formed, which is much faster to decode in one go.
+	if err := json.Unmarshal(rawRecord, &record); err == nil {
+		if record.Hex == "" {
+			diag.add(diagHex)
+			return record, false
+		}
+		return record, true
+	}
+	record = AircraftRecord{} //nolint:exhaustruct // start over field by field
+
+	var rawFields map[string]json.RawMessage
+	if err := json.Unmarshal(rawRecord, &rawFields); err != nil {
+		diag.add(diagRecord)
+		return record, false
+	}
+
+	fieldIndices := aircraftRecordFields()
+	recordValue := reflect.ValueOf(&record).Elem()
+	for name, rawValue := range rawFields {
+		idx, ok := fieldIndices[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		if err := decodeField(recordValue.Field(idx), rawValue); err != nil {
+			diag.add(strings.ToLower(name))
+		}
+	}
+
+	if record.Hex == "" {
+		diag.add(diagHex)
+		return record, false
+	}
+	return record, true
+}
+
+// decodeField decodes the raw JSON value into the field, converting between numbers and strings
+// where the field expects the other. On failure, the field is left empty.
+func decodeField(field reflect.Value, rawValue json.RawMessage) error {
+	if isJSONNull(rawValue) {
+		return nil
+	}
+
+	decodeErr := json.Unmarshal(rawValue, field.Addr().Interface())
+	if decodeErr == nil {
+		return nil
+	}
+	field.SetZero()
+
+	var str string
+	isString := json.Unmarshal(rawValue, &str) == nil
+	str = strings.TrimSpace(str)
+
+	switch field.Kind() { //nolint:exhaustive // other kinds can't be converted
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var number float64
+		if isString {
+			parsed, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				break
+			}
+			number = parsed
+		} else if err := json.Unmarshal(rawValue, &number); err != nil {
+			break
+		}
+		field.SetInt(int64(number))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if !isString {
+			break
+		}
+		if number, err := strconv.ParseFloat(str, 64); err == nil {
+			field.SetFloat(number)
+			return nil
+		}
+	case reflect.String:
+		var number json.Number
+		if err := json.Unmarshal(rawValue, &number); err == nil {
+			field.SetString(number.String())
+			return nil
+		}
+	case reflect.Slice:
+		if isString && field.Type().Elem().Kind() == reflect.String {
+			field.Set(reflect.ValueOf([]string{str}))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("decodeField: %w", decodeErr)
+}
+
+func isJSONNull(rawValue json.RawMessage) bool {
+	return strings.TrimSpace(string(rawValue)) == "null"
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReadsbAircraft(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		hexes     []string
+		decodeErr map[string]int
+		wantErr   bool
+	}{ //nolint:exhaustruct // errors have no results
+		{
+			name:      "well-formed",
+			body:      `{"now": 1, "aircraft": [{"hex": "3c6444", "flight": "DLH4AB  ", "alt_baro": 35000}]}`,
+			hexes:     []string{"3c6444"},
+			decodeErr: map[string]int{},
+		},
+		{
+			name:      "ac list and unknown fields",
+			body:      `{"ac": [{"hex": "3c6444", "new_field": {"nested": true}}], "extra": [1, 2]}`,
+			hexes:     []string{"3c6444"},
+			decodeErr: map[string]int{},
+		},
+		{
+			name:      "missing aircraft list",
+			body:      `{"now": 1, "msg": "No error"}`,
+			hexes:     nil,
+			decodeErr: map[string]int{},
+		},
+		{
+			name:      "null aircraft list",
+			body:      `{"aircraft": null}`,
+			hexes:     nil,
+			decodeErr: map[string]int{},
+		},
+		{
+			name:      "broken aircraft list",
+			body:      `{"aircraft": "none", "ac": [{"hex": "4b1805"}]}`,
+			hexes:     []string{"4b1805"},
+			decodeErr: map[string]int{"aircraft": 1},
+		},
+		{
+			name:      "partial and broken records",
+			body:      `{"aircraft": [{"hex": "3c6444", "gs": "fast", "lat": [1]}, 42, {"flight": "X"}]}`,
+			hexes:     []string{"3c6444"},
+			decodeErr: map[string]int{"gs": 1, "lat": 1, diagRecord: 1, diagHex: 1},
+		},
+		{
+			name:      "object as aircraft list",
+			body:      `{"aircraft": {"hex": "3c6444", "nested": [{}]}, "ac": [{"hex": "4b1805"}]}`,
+			hexes:     []string{"4b1805"},
+			decodeErr: map[string]int{"aircraft": 1},
+		},
+		{
+			name:    "not an object",
+			body:    `[{"hex": "3c6444"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			body:    `{"aircraft": [{"hex": "3c6444"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := NewDecodeDiagnostics()
+			aircraft, err := parseReadsbAircraft(strings.NewReader(tt.body), diag, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReadsbAircraft() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var hexes []string
+			for _, record := range aircraft {
+				hexes = append(hexes, record.Hex)
+			}
+			if !reflect.DeepEqual(hexes, tt.hexes) {
+				t.Errorf("parseReadsbAircraft() hexes = %v, expected %v", hexes, tt.hexes)
+			}
+			if counts := diag.Counts(); !reflect.DeepEqual(counts, tt.decodeErr) {
+				t.Errorf("Counts() = %v, expected %v", counts, tt.decodeErr)
+			}
+		})
+	}
+}
+
+func TestParseReadsbAircraftNumbersAsStrings(t *testing.T) {
+	body := `{"aircraft": [{
+		"hex": "3c6444",
+		"gs": "431.5",
+		"messages": "1200",
+		"nic": 8.0,
+		"alt_geom": 35125.7,
+		"squawk": 1000,
+		"alt_baro": "35000",
+		"mlat": "lat"
+	}]}`
+
+	diag := NewDecodeDiagnostics()
+	aircraft, err := parseReadsbAircraft(strings.NewReader(body), diag, nil)
+	if err != nil || len(aircraft) != 1 {
+		t.Fatalf("parseReadsbAircraft() = %v, %v", aircraft, err)
+	}
+
+	record := aircraft[0]
+	if record.GroundSpeed != 431.5 || record.Messages != 1200 || record.Nic != 8 {
+		t.Errorf("numbers weren't converted: %+v", record)
+	}
+	if record.AltGeom != 35125 || record.Squawk != "1000" {
+		t.Errorf("AltGeom = %d, Squawk = %q", record.AltGeom, record.Squawk)
+	}
+	if feet, ok := record.AltBaro.Feet(); !ok || feet != 35000 {
+		t.Errorf("AltBaro.Feet() = %v, %v", feet, ok)
+	}
+	if !reflect.DeepEqual(record.Mlat, []string{"lat"}) {
+		t.Errorf("Mlat = %v, expected [lat]", record.Mlat)
+	}
+	if total := diag.Total(); total != 0 {
+		t.Errorf("Total() = %d, expected no decode errors: %s", total, diag)
+	}
+}
+
+func TestParseReadsbAircraftFilter(t *testing.T) {
+	body := `{"aircraft": [{"hex": "3c6444", "type": "adsb_icao"}, {"hex": "4b1805", "type": "tisb_icao"}],
+		"ac": [{"hex": "a0b1c2", "type": "mlat"}]}`
+	keep := func(record *AircraftRecord) bool { return !IsTISB(record.Type) }
+
+	aircraft, err := parseReadsbAircraft(strings.NewReader(body), NewDecodeDiagnostics(), keep)
+	if err != nil {
+		t.Fatalf("parseReadsbAircraft() error = %v", err)
+	}
+	if len(aircraft) != 2 || aircraft[0].Hex != "3c6444" || aircraft[1].Hex != "a0b1c2" {
+		t.Errorf("parseReadsbAircraft() = %+v, expected the aircraft without TIS-B", aircraft)
+	}
+}
+
+func TestDecodeDiagnosticsString(t *testing.T) {
+	diag := NewDecodeDiagnostics()
+	if str := diag.String(); str != "none" {
+		t.Errorf("String() = %q, expected none", str)
+	}
+
+	diag.add("gs")
+	diag.add("alt_baro")
+	diag.add("alt_baro")
+	diag.add("lat")
+	if str := diag.String(); str != "alt_baro 2, gs 1, lat 1" {
+		t.Errorf("String() = %q", str)
+	}
+}
+
+func BenchmarkParseReadsbAircraft(b *testing.B) {
+	records := make([]string, benchmarkAircraft)
+	for idx := range records {
+		records[idx] = fmt.Sprintf(
+			`{"hex": "3c%04x", "flight": "DLH%-5d", "r": "D-A%03d", "t": "A320", "alt_baro": %d, `+
+				`"gs": %d, "track": %d, "lat": 53.6, "lon": 9.9, "squawk": "1000", "seen": 0.5}`,
+			idx, idx, idx, 1000+idx*150, 150+idx*2, idx%360)
+	}
+	body := []byte(`{"now": 1760796120, "aircraft": [` + strings.Join(records, ", ") + `]}`)
+	diag := NewDecodeDiagnostics()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := parseReadsbAircraft(bytes.NewReader(body), diag, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// discoveryChartDays is the extent of the discovery chart.
+	discoveryChartDays = 30
+)
+
+// discoveryCategories are what can be discovered, in the order they are reported.
+var discoveryCategories = []string{"type", "operator", "country"} //nolint:gochecknoglobals // constant
+
+// DiscoveryDay counts the types, operators and countries which were seen for the first time on a
+// particular day.
+type DiscoveryDay struct {
+	Start     time.Time // Start of the day.
+	Types     int
+	Operators int
+	Countries int
+}
+
+// Total returns how many discoveries were made on the day.
+func (d DiscoveryDay) Total() int {
+	return d.Types + d.Operators + d.Countries
+}
+
+// LifetimeFirst is a type, operator or country which has never been seen before.
+type LifetimeFirst struct {
+	Category string // Category is either "type", "operator" or "country".
+	Property string
+}
+
+// FirstSighting combines an aircraft sighting with what it showed for the first time ever.
+type FirstSighting struct {
+	Firsts   []LifetimeFirst
+	Sighting *AircraftSighting
+}
+
+// DiscoveryStats tracks when every type, operator and country was first seen, to show how fast
+// new ones are still being discovered. Over time, the discovery rate declines as the local
+// airspace is explored more and more thoroughly.
+type DiscoveryStats struct {
+	firstSeen map[string]map[string]time.Time // categories mapped to properties and first sighting
+	counts    map[string]map[string]int       // categories mapped to properties and sightings
+	day       SpottingDay                     // day tells when the days of the daily discoveries start.
+}
+
+// NewDiscoveryStats creates empty discovery statistics, counted by the given spotting days.
+func NewDiscoveryStats(day SpottingDay) *DiscoveryStats {
+	stats := &DiscoveryStats{
+		firstSeen: make(map[string]map[string]time.Time),
+		counts:    make(map[string]map[string]int),
+		day:       day,
+	}
+	for _, category := range discoveryCategories {
+		stats.firstSeen[category] = make(map[string]time.Time)
+		stats.counts[category] = make(map[string]int)
+	}
+	return stats
+}
+
+// Record counts the type, operator and country of a sighting, as far as they are known, and
+// returns those which haven't been recorded before, in the order of discoveryCategories.
+// Entries may be recorded in any order, e.g. from the sighting history first.
+func (ds *DiscoveryStats) Record(entry HistoryEntry) []LifetimeFirst {
+	properties := map[string]string{
+		"type":     entry.Type,
+		"operator": entry.Operator,
+		"country":  entry.Country,
+	}
+	var firsts []LifetimeFirst
+	for _, category := range discoveryCategories {
+		property := properties[category]
+		if property == "" || strings.EqualFold(property, typeUnknown) {
+			continue // operatorUnknown and countryUnknown are the same as typeUnknown
+		}
+		ds.counts[category][property]++
+		first, ok := ds.firstSeen[category][property]
+		if !ok {
+			firsts = append(firsts, LifetimeFirst{Category: category, Property: property})
+		}
+		if !ok || entry.Time.Before(first) {
+			ds.firstSeen[category][property] = entry.Time
+		}
+	}
+	return firsts
+}
+
+// FirstSeen returns when each property of the category was first seen.
+func (ds *DiscoveryStats) FirstSeen(category string) map[string]time.Time {
+	firstSeen := make(map[string]time.Time, len(ds.firstSeen[category]))
+	for property, first := range ds.firstSeen[category] {
+		firstSeen[property] = first
+	}
+	return firstSeen
+}
+
+// Distinct returns how many different properties of the category have been discovered.
+func (ds *DiscoveryStats) Distinct(category string) int {
+	return len(ds.firstSeen[category])
+}
+
+// Daily returns the discoveries of the last given number of spotting days up to now, oldest first.
+func (ds *DiscoveryStats) Daily(now time.Time, days int) []DiscoveryDay {
+	buckets := make([]DiscoveryDay, days)
+	dayToIdx := make(map[time.Time]int, days)
+	today := ds.day.Start(now)
+	for idx := range days {
+		start := today.AddDate(0, 0, idx-days+1)
+		buckets[idx] = DiscoveryDay{Start: start, Types: 0, Operators: 0, Countries: 0}
+		dayToIdx[start] = idx
+	}
+
+	for category, properties := range ds.firstSeen {
+		for _, first := range properties {
+			idx, ok := dayToIdx[ds.day.Start(first.In(now.Location()))]
+			if !ok {
+				continue
+			}
+			switch category {
+			case "type":
+				buckets[idx].Types++
+			case "operator":
+				buckets[idx].Operators++
+			case "country":
+				buckets[idx].Countries++
+			}
+		}
+	}
+	return buckets
+}
+
+// Exploration estimates how thoroughly the local airspace has been explored, as the share of
+// sightings whose type, operator or country has been seen before (Good-Turing sample coverage):
+// one minus the share of properties seen exactly once among all sightings.
+// It is zero until something has been seen and approaches one as discoveries become rare.
+func (ds *DiscoveryStats) Exploration() float64 {
+	singletons, sightings := 0, 0
+	for _, properties := range ds.counts {
+		for _, count := range properties {
+			sightings += count
+			if count == 1 {
+				singletons++
+			}
+		}
+	}
+	if sightings == 0 {
+		return 0
+	}
+	return 1 - float64(singletons)/float64(sightings)
+}
+
+// Summary describes the progress of the exploration in a single line, e.g.
+// "42 types, 17 operators, 9 countries, 87% explored".
+func (ds *DiscoveryStats) Summary() string {
+	return fmt.Sprintf(
+		"%d types, %d operators, %d countries, %.0f%% explored",
+		ds.Distinct("type"),
+		ds.Distinct("operator"),
+		ds.Distinct("country"),
+		ds.Exploration()*100) //nolint:mnd // percent
+}
+
+// DiscoverySparkline renders the number of discoveries of each day as a bar, scaled to the day
+// with the most discoveries.
+func DiscoverySparkline(days []DiscoveryDay) string {
+	values := make([]float64, len(days))
+	for idx, day := range days {
+		values[idx] = float64(day.Total())
+	}
+	return sparkline(values)
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestDiscoveryStats(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	entry := func(age time.Duration, aType, operator, country string) HistoryEntry {
+		return HistoryEntry{
+			Time:         now.Add(-age),
+			Hex:          "3c6444",
+			Flight:       "DLH4AB",
+			Registration: "D-AIBL",
+			Type:         aType,
+			Operator:     operator,
+			Country:      country,
+		}
+	}
+
+	stats := NewDiscoveryStats(SpottingDay{})
+	// Recorded out of order, as when the history is loaded after some live sightings.
+	stats.Record(entry(0, "A320", "Lufthansa", "GERMANY"))
+	stats.Record(entry(2*day, "A320", "Lufthansa", "GERMANY"))
+	stats.Record(entry(day, "B738", "Lufthansa", "UNKNOWN"))
+	stats.Record(entry(0, typeUnknown, operatorUnknown, countryUnknown))
+	stats.Record(entry(40*day, "A388", "Emirates", "UAE"))
+
+	if types, operators, countries := stats.Distinct("type"), stats.Distinct("operator"),
+		stats.Distinct("country"); types != 3 || operators != 2 || countries != 2 {
+		t.Errorf("Distinct() = %d, %d, %d, expected 3, 2, 2", types, operators, countries)
+	}
+
+	days := stats.Daily(now, 3)
+	expected := []DiscoveryDay{
+		{Start: now.Add(-2 * day).Truncate(day), Types: 1, Operators: 1, Countries: 1},
+		{Start: now.Add(-day).Truncate(day), Types: 1, Operators: 0, Countries: 0},
+		{Start: now.Truncate(day), Types: 0, Operators: 0, Countries: 0},
+	}
+	for idx := range expected {
+		if !days[idx].Start.Equal(expected[idx].Start) || days[idx].Total() != expected[idx].Total() ||
+			days[idx].Types != expected[idx].Types {
+			t.Errorf("Daily()[%d] = %+v, expected %+v", idx, days[idx], expected[idx])
+		}
+	}
+
+	// Seen twice: A320, GERMANY; Lufthansa three times. Seen once: B738, A388, Emirates, UAE.
+	singletons, sightings := 4.0, 11.0
+	if exploration := stats.Exploration(); math.Abs(exploration-(1-singletons/sightings)) > 1e-9 {
+		t.Errorf("Exploration() = %v, expected %v", exploration, 1-singletons/sightings)
+	}
+
+	if sparkline := DiscoverySparkline(days); sparkline != "█▃▁" {
+		t.Errorf("DiscoverySparkline() = %q", sparkline)
+	}
+}
+
+func TestDiscoveryStatsEmpty(t *testing.T) {
+	stats := NewDiscoveryStats(SpottingDay{})
+	if exploration := stats.Exploration(); exploration != 0 {
+		t.Errorf("Exploration() = %v, expected 0", exploration)
+	}
+	if summary := stats.Summary(); summary != "0 types, 0 operators, 0 countries, 0% explored" {
+		t.Errorf("Summary() = %q", summary)
+	}
+}
+
+func TestDiscoveryStatsLifetimeFirsts(t *testing.T) {
+	seen := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	entry := func(age time.Duration, aType, operator, country string) HistoryEntry {
+		return HistoryEntry{
+			Time:         seen.Add(-age),
+			Hex:          "3c6444",
+			Flight:       "DLH4AB",
+			Registration: "D-AIBL",
+			Type:         aType,
+			Operator:     operator,
+			Country:      country,
+		}
+	}
+
+	stats := NewDiscoveryStats(SpottingDay{})
+	tests := []struct {
+		entry  HistoryEntry
+		firsts []LifetimeFirst
+	}{
+		{
+			entry: entry(time.Hour, "A320", "Lufthansa", "GERMANY"),
+			firsts: []LifetimeFirst{
+				{Category: "type", Property: "A320"},
+				{Category: "operator", Property: "Lufthansa"},
+				{Category: "country", Property: "GERMANY"},
+			},
+		},
+		{entry: entry(0, "A320", "Lufthansa", "GERMANY"), firsts: nil},
+		{
+			entry:  entry(0, "B738", "Lufthansa", countryUnknown),
+			firsts: []LifetimeFirst{{Category: "type", Property: "B738"}},
+		},
+		// Recorded out of order, but not a first anymore.
+		{entry: entry(2*time.Hour, "A320", "Lufthansa", "GERMANY"), firsts: nil},
+	}
+
+	for _, test := range tests {
+		firsts := stats.Record(test.entry)
+		if len(firsts) != len(test.firsts) {
+			t.Errorf("Record(%s) = %v, expected %v", test.entry.Type, firsts, test.firsts)
+			continue
+		}
+		for idx := range firsts {
+			if firsts[idx] != test.firsts[idx] {
+				t.Errorf("Record(%s) = %v, expected %v", test.entry.Type, firsts, test.firsts)
+			}
+		}
+	}
+
+	firstSeen := stats.FirstSeen("type")
+	if !firstSeen["A320"].Equal(seen.Add(-2*time.Hour)) || !firstSeen["B738"].Equal(seen) {
+		t.Errorf("FirstSeen(type) = %v", firstSeen)
+	}
+}
+
+func TestFirstSightingEvent(t *testing.T) {
+	sighting := &AircraftSighting{ //nolint:exhaustruct // only the reported fields matter
+		lastFlightNo: "UAE45",
+		registration: "A6-EUA",
+		info:         "Emirates A388",
+	}
+	event := firstSightingEvent(i18n.English, FirstSighting{
+		Firsts: []LifetimeFirst{
+			{Category: "type", Property: "A388"},
+			{Category: "operator", Property: "Emirates"},
+		},
+		Sighting: sighting,
+	})
+
+	if event.Kind != EventKindFirst || event.Title != "First A388 ever!" {
+		t.Errorf("event = %q, %q", event.Kind, event.Title)
+	}
+	if want := "FIRST EVER type A388, operator Emirates: Emirates A388"; event.Summary != want {
+		t.Errorf("Summary = %q, expected %q", event.Summary, want)
+	}
+}
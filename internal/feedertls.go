@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+var errClientCert = errors.New("invalid client certificate")
+
+// newTLSConfig returns the TLS config of all requests.
+func newTLSConfig() *tls.Config {
+	return &tls.Config{ //nolint:exhaustruct // too large
+		MinVersion: tls.VersionTLS13,
+		MaxVersion: tls.VersionTLS13,
+	}
+}
+
+// hasFeederTLS tells whether a client certificate or CA is configured for the private feeder.
+func (opts RequestOptions) hasFeederTLS() bool {
+	return opts.ClientCertFile != "" || opts.ClientKeyFile != "" || opts.CACertFile != ""
+}
+
+// newFeederClient returns the client which requests aircraft from a private feeder, i.e. the local
+// source, with mutual TLS if a client certificate is configured. The certificate is only ever
+// presented to the feeder, never to the public APIs.
+// A CA certificate can be given for feeders whose server certificate is signed by a private CA,
+// with or without client certificate.
+func newFeederClient(opts RequestOptions, apiClient *http.Client) (*http.Client, error) {
+	if !opts.hasFeederTLS() {
+		return apiClient, nil
+	}
+	if (opts.ClientCertFile == "") != (opts.ClientKeyFile == "") {
+		return nil, fmt.Errorf("newFeederClient: %w: needs both certificate and key", errClientCert)
+	}
+
+	tlsConfig := newTLSConfig()
+	// Private feeders may well be older servers which don't speak TLS 1.3 yet.
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	if opts.ClientCertFile != "" {
+		cert, certErr := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if certErr != nil {
+			return nil, fmt.Errorf("newFeederClient: %w: %w", errClientCert, certErr)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.CACertFile != "" {
+		caPEM, readErr := os.ReadFile(opts.CACertFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("newFeederClient: failed to read CA certificate: %w", readErr)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf(
+				"newFeederClient: %w: no CA certificate in %s", errClientCert, opts.CACertFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig}, //nolint:exhaustruct // too large
+	}, nil
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert creates a self-signed client certificate and returns the paths of its PEM files
+// together with the certificate itself.
+func writeClientCert(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, keyErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if keyErr != nil {
+		t.Fatal(keyErr)
+	}
+	template := &x509.Certificate{ //nolint:exhaustruct // too large
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, certErr := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if certErr != nil {
+		t.Fatal(certErr)
+	}
+	cert, parseErr := x509.ParseCertificate(der)
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+	keyDER, marshalErr := x509.MarshalECPrivateKey(key)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client.key")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+	return certPath, keyPath, cert
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+	block := &pem.Block{Type: blockType, Headers: nil, Bytes: der}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewFeederClient(t *testing.T) {
+	certPath, keyPath, _ := writeClientCert(t)
+	apiClient := &http.Client{} //nolint:exhaustruct // only compared
+
+	tests := []struct {
+		name     string
+		cert     string
+		key      string
+		caCert   string
+		isAPI    bool
+		expected error
+	}{
+		{name: "none", cert: "", key: "", caCert: "", isAPI: true, expected: nil},
+		{name: "cert and key", cert: certPath, key: keyPath, caCert: "", isAPI: false, expected: nil},
+		{name: "with CA", cert: certPath, key: keyPath, caCert: certPath, isAPI: false, expected: nil},
+		{name: "only CA", cert: "", key: "", caCert: certPath, isAPI: false, expected: nil},
+		{name: "no key", cert: certPath, key: "", caCert: "", isAPI: false, expected: errClientCert},
+		{name: "key as cert", cert: keyPath, key: keyPath, caCert: "", isAPI: false, expected: errClientCert},
+		{name: "key as CA", cert: certPath, key: keyPath, caCert: keyPath, isAPI: false, expected: errClientCert},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testRequestOptions(0, 0)
+			opts.ClientCertFile, opts.ClientKeyFile, opts.CACertFile = tt.cert, tt.key, tt.caCert
+			client, err := newFeederClient(opts, apiClient)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("newFeederClient() error = %v, expected %v", err, tt.expected)
+			}
+			if err == nil && (client == apiClient) != tt.isAPI {
+				t.Errorf("newFeederClient() is API client = %v, expected %v", client == apiClient, tt.isAPI)
+			}
+		})
+	}
+}
+
+func TestRequestAircraftWithClientCert(t *testing.T) {
+	certPath, keyPath, clientCert := writeClientCert(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"aircraft": [{"hex": "3c6444"}]}`)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ //nolint:exhaustruct // too large
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, caPath, "CERTIFICATE", server.Certificate().Raw)
+
+	for _, withCert := range []bool{false, true} {
+		opts := testRequestOptions(0, 0)
+		opts.Sources = []string{SourceLocal}
+		opts.LocalURL = server.URL + "/data/aircraft.json"
+		opts.CACertFile = caPath
+		if withCert {
+			opts.ClientCertFile, opts.ClientKeyFile = certPath, keyPath
+		}
+
+		var stderr io.Writer = io.Discard
+		request, err := NewRequest(opts, &stderr)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+
+		aircraft := request.RequestAircraft()
+		_, pollErr := request.LastPoll()
+		if withCert && (pollErr != nil || len(aircraft) != 1) {
+			t.Errorf("RequestAircraft() = %v, %v", aircraft, pollErr)
+		}
+		if !withCert && pollErr == nil {
+			t.Error("RequestAircraft() succeeded without client certificate")
+		}
+	}
+}
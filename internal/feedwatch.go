@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// DefaultStallAfter is how long all polls may fail before the feed counts as stalled.
+const DefaultStallAfter = 10 * time.Minute
+
+// FeedChange is how the state of the feed changed since it was last checked.
+type FeedChange int
+
+const (
+	// FeedUnchanged means that the feed is still working or still stalled.
+	FeedUnchanged FeedChange = iota
+	// FeedStalled means that every poll has been failing for longer than allowed.
+	FeedStalled
+	// FeedRecovered means that a poll succeeded again after the feed stalled.
+	FeedRecovered
+)
+
+// FeedWatch notices when the feed dies: a single failed poll goes by without notice, but once
+// every poll has been failing for a while, the feed counts as stalled until a poll succeeds again.
+// Without it, the last aircraft would silently stay on display.
+type FeedWatch struct {
+	stallAfter time.Duration // stallAfter is how long polls may fail, 0 never stalls.
+	stalled    bool
+	since      time.Time // since is when the polls started to fail, if stalled.
+}
+
+// NewFeedWatch creates a watch which considers the feed stalled after all polls failed for the
+// given duration, 0 disables it.
+func NewFeedWatch(stallAfter time.Duration) *FeedWatch {
+	return &FeedWatch{stallAfter: stallAfter, stalled: false, since: time.Time{}}
+}
+
+// Check updates the state of the feed from when the polls started to fail, which is zero if the
+// last poll succeeded, and returns how it changed.
+func (fw *FeedWatch) Check(failingSince time.Time, now time.Time) FeedChange {
+	if fw.stallAfter <= 0 {
+		return FeedUnchanged
+	}
+
+	switch {
+	case !fw.stalled && !failingSince.IsZero() && now.Sub(failingSince) >= fw.stallAfter:
+		fw.stalled = true
+		fw.since = failingSince
+		return FeedStalled
+	case fw.stalled && failingSince.IsZero():
+		fw.stalled = false
+		return FeedRecovered
+	}
+	return FeedUnchanged
+}
+
+// Stalled tells whether the feed is stalled and since when the polls have been failing.
+func (fw *FeedWatch) Stalled() (bool, time.Time) {
+	return fw.stalled, fw.since
+}
+
+// feedStalledEvent reports that no poll succeeded since the given time.
+func feedStalledEvent(lang i18n.Language, since time.Time, pollErr error, now time.Time) Event {
+	outage := now.Sub(since).Round(time.Second)
+	reason := lang.T("unknown error")
+	if pollErr != nil {
+		reason = pollErr.Error()
+	}
+	return Event{
+		Kind:     EventKindFeed,
+		Severity: SeverityCritical,
+		Title:    lang.T("Feed stalled"),
+		Body:     lang.Sprintf("No aircraft data for %s\n%s", outage, reason),
+		Summary:  lang.Sprintf("FEED STALLED: no aircraft data for %s: %s", outage, reason),
+		Time:     now,
+		Sighting: nil,
+		Change:   nil,
+		Movement: nil,
+	}
+}
+
+// feedRecoveredEvent reports that aircraft data arrives again after it stalled at the given time.
+func feedRecoveredEvent(lang i18n.Language, since time.Time, now time.Time) Event {
+	outage := now.Sub(since).Round(time.Second)
+	return Event{
+		Kind:     EventKindFeed,
+		Severity: SeverityNotable,
+		Title:    lang.T("Feed recovered"),
+		Body:     lang.Sprintf("Aircraft data is back after %s", outage),
+		Summary:  lang.Sprintf("feed recovered after %s without aircraft data", outage),
+		Time:     now,
+		Sighting: nil,
+		Change:   nil,
+		Movement: nil,
+	}
+}
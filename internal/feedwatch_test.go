@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestFeedWatchCheck(t *testing.T) {
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	failing := start.Add(time.Minute)
+
+	steps := []struct {
+		failingSince time.Time
+		now          time.Time
+		expected     FeedChange
+	}{
+		{failingSince: time.Time{}, now: start, expected: FeedUnchanged},
+		{failingSince: failing, now: failing, expected: FeedUnchanged},
+		{failingSince: failing, now: failing.Add(9 * time.Minute), expected: FeedUnchanged},
+		{failingSince: failing, now: failing.Add(10 * time.Minute), expected: FeedStalled},
+		{failingSince: failing, now: failing.Add(20 * time.Minute), expected: FeedUnchanged},
+		{failingSince: time.Time{}, now: failing.Add(21 * time.Minute), expected: FeedRecovered},
+		{failingSince: time.Time{}, now: failing.Add(22 * time.Minute), expected: FeedUnchanged},
+	}
+
+	watch := NewFeedWatch(DefaultStallAfter)
+	for idx, step := range steps {
+		if change := watch.Check(step.failingSince, step.now); change != step.expected {
+			t.Errorf("step %d: Check() = %v, expected %v", idx, change, step.expected)
+		}
+	}
+
+	disabled := NewFeedWatch(0)
+	if change := disabled.Check(failing, failing.Add(24*time.Hour)); change != FeedUnchanged {
+		t.Errorf("disabled Check() = %v, expected %v", change, FeedUnchanged)
+	}
+}
+
+func TestRequestFailingSince(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC))
+	request := &Request{clock: clock} //nolint:exhaustruct // poll state only
+	first := clock.Now()
+	errPoll := errors.New("connection refused")
+
+	request.recordPoll(errPoll, first)
+	request.recordPoll(errPoll, first.Add(AircraftUpdateInterval))
+	if since := request.FailingSince(); !since.Equal(first) {
+		t.Errorf("FailingSince() = %v, expected the first failed poll %v", since, first)
+	}
+
+	clock.Advance(2 * AircraftUpdateInterval)
+	request.recordPoll(nil, clock.Now())
+	if since := request.FailingSince(); !since.IsZero() {
+		t.Errorf("FailingSince() = %v after success, expected zero", since)
+	}
+	// The polls are timed by the clock, which the dashboard compares the feed status with.
+	if lastPoll, _ := request.LastPoll(); !lastPoll.Equal(clock.Now()) {
+		t.Errorf("LastPoll() = %v, expected the time of the clock %v", lastPoll, clock.Now())
+	}
+}
+
+func TestFeedEventsWithoutSighting(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC)
+	stalled := feedStalledEvent(i18n.English, now.Add(-15*time.Minute), errors.New("503"), now)
+
+	var text bytes.Buffer
+	if err := writeEvent(&text, SinkFormatText, stalled); err != nil {
+		t.Fatalf("writeEvent() error = %v", err)
+	}
+	if expected := "FEED STALLED: no aircraft data for 15m0s: 503\n"; text.String() != expected {
+		t.Errorf("writeEvent() wrote %q, expected %q", text.String(), expected)
+	}
+
+	var line bytes.Buffer
+	if err := writeEvent(&line, SinkFormatJSON, feedRecoveredEvent(i18n.English, now.Add(-time.Hour), now)); err != nil {
+		t.Fatalf("writeEvent() error = %v", err)
+	}
+	var payload EventPayload
+	if err := json.Unmarshal(line.Bytes(), &payload); err != nil {
+		t.Fatalf("writeEvent() wrote invalid JSON %q: %v", line.String(), err)
+	}
+	if payload.Event != EventKindFeed || !strings.Contains(payload.Summary, "1h0m0s") {
+		t.Errorf("writeEvent() wrote unexpected payload %+v", payload)
+	}
+}
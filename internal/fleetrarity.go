@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// globalFleetReference is about the fleet size of the most common types in service, which
+	// aren't rare worldwide at all.
+	globalFleetReference = 10000
+)
+
+// RarityScore tells how rare a type is in orders of magnitude: locally compared to all types seen
+// here, and globally compared to the most common types in service.
+// A type seen once in a thousand sightings with a fleet of ten has a local and global score of 3.
+type RarityScore struct {
+	Local     float64
+	Global    float64
+	HasGlobal bool // HasGlobal tells whether the fleet size of the type is known.
+}
+
+// newRarityScore scores a type which has been seen count out of total times here, with the given
+// worldwide fleet size if it is known.
+func newRarityScore(count int, total int, fleetSize int, hasFleet bool) RarityScore {
+	score := RarityScore{Local: 0, Global: 0, HasGlobal: hasFleet}
+	if count > 0 && total > count {
+		score.Local = math.Log10(float64(total) / float64(count))
+	}
+	if hasFleet {
+		score.Global = max(0, math.Log10(globalFleetReference/float64(max(1, fleetSize))))
+	}
+	return score
+}
+
+// Combined returns the sum of the local and the global score.
+func (s RarityScore) Combined() float64 {
+	return s.Local + s.Global
+}
+
+// String describes the score, e.g. "rarity 5.3 (local 2.1, global 3.2)".
+func (s RarityScore) String() string {
+	if !s.HasGlobal {
+		return fmt.Sprintf("rarity %.1f (local)", s.Local)
+	}
+	return fmt.Sprintf("rarity %.1f (local %.1f, global %.1f)", s.Combined(), s.Local, s.Global)
+}
+
+// isGloballyRare tells whether the type has so few aircraft in service worldwide that it is rare
+// regardless of how often it has been seen here.
+func (db *Dashboard) isGloballyRare(fleetSize int, hasFleet bool) bool {
+	return hasFleet && fleetSize < db.fleetRareBelow
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"io"
+	"math"
+	"testing"
+)
+
+func TestNewRarityScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		total     int
+		fleetSize int
+		hasFleet  bool
+		local     float64
+		global    float64
+		text      string
+	}{
+		{"common here, unknown fleet", 10, 10, 0, false, 0, 0, "rarity 0.0 (local)"},
+		{"rare here, unknown fleet", 1, 1000, 0, false, 3, 0, "rarity 3.0 (local)"},
+		{"rare here and worldwide", 1, 1000, 10, true, 3, 3, "rarity 6.0 (local 3.0, global 3.0)"},
+		{"larger fleet than the reference", 1, 100, 20000, true, 2, 0, "rarity 2.0 (local 2.0, global 0.0)"},
+		{"retired fleet", 1, 10, 0, true, 1, 4, "rarity 5.0 (local 1.0, global 4.0)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score := newRarityScore(test.count, test.total, test.fleetSize, test.hasFleet)
+			if math.Abs(score.Local-test.local) > 1e-9 || math.Abs(score.Global-test.global) > 1e-9 {
+				t.Errorf("newRarityScore() = %+v, want local %.1f, global %.1f", score, test.local, test.global)
+			}
+			if score.String() != test.text {
+				t.Errorf("String() = %q, want %q", score.String(), test.text)
+			}
+		})
+	}
+}
+
+func TestGloballyRareTypes(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio", FleetRareBelow: 50}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	dashboard.FleetSizes = map[string]int{"A124": 20, "B738": 5000}
+	dashboard.FinishWarmupPeriod()
+
+	// The ratio scorer needs far more sightings before anything is rare locally.
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "508035", Flight: "ADB3042", IcaoType: "A124"}, //nolint:exhaustruct // type only
+		{Hex: "4ca7b5", Flight: "RYR1AB", IcaoType: "B738"},  //nolint:exhaustruct // type only
+	})
+
+	if len(dashboard.RareSightings) != 1 {
+		t.Fatalf("RareSightings = %+v, want only the An-124", dashboard.RareSightings)
+	}
+	rare := dashboard.RareSightings[0]
+	if rare.Rarities&RareType == 0 || rare.Sighting.typeDesc != "ANTONOV, An-124 Ruslan" {
+		t.Errorf("RareSightings[0] = %+v, want a rare An-124 type", rare)
+	}
+	if !rare.Sighting.rarityScore.HasGlobal || rare.Sighting.rarityScore.Global < 2 {
+		t.Errorf("rarityScore = %+v, want a global score for a fleet of 20", rare.Sighting.rarityScore)
+	}
+}
@@ -0,0 +1,432 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// followTrackLength is how many points of the track of the followed aircraft are kept, two
+	// hours at the default polling.
+	followTrackLength = 240
+	// followClimbRate is the vertical rate from which the followed aircraft climbs or descends, and
+	// followLevelRate the one within which it is level again, in [ft/min]. In between, it keeps
+	// what it was doing, so that a wobbling rate doesn't report every poll.
+	followClimbRate = 500
+	followLevelRate = 200
+	// followSpeedStep is how much the ground speed has to change to be reported, in [knots].
+	followSpeedStep = 50
+	// followLostAfter is how long the followed aircraft may be out of sight before it is reported
+	// lost, so that a single missed poll doesn't report it.
+	followLostAfter = 5 * time.Minute
+)
+
+var (
+	errInvalidFollowTarget = errors.New("invalid aircraft to follow")
+
+	//nolint:gochecknoglobals // constant
+	followHexPattern = regexp.MustCompile(`^~?[0-9a-f]{6}$`)
+	//nolint:gochecknoglobals // constant
+	followCallsignPattern = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+)
+
+// FollowTarget is an aircraft to follow, by its hex or by its callsign.
+type FollowTarget struct {
+	Hex      string // Hex in lower case, empty if the aircraft is followed by its callsign.
+	Callsign string // Callsign in upper case, empty if the aircraft is followed by its hex.
+}
+
+// Prefixes of follow targets which tell a hex and a callsign apart, since callsigns like "CFE123"
+// or "4021" are six hex digits or fewer as well.
+const (
+	followHexPrefix      = "hex"
+	followCallsignPrefix = "callsign"
+)
+
+// ParseFollowTarget reads the aircraft to follow: six hex digits are its hex, e.g. "3c6586" or
+// "~2a1f3c" for a non-ICAO address, anything else of up to eight letters and digits is its
+// callsign, e.g. "DLH4AB". The prefixes "hex:" and "callsign:" say which it is, e.g.
+// "callsign:CFE123" for a callsign which is six hex digits.
+func ParseFollowTarget(target string) (FollowTarget, error) {
+	target = strings.TrimSpace(target)
+	isHex, isCallsign := true, true
+	if prefix, rest, found := strings.Cut(target, ":"); found {
+		switch strings.ToLower(prefix) {
+		case followHexPrefix:
+			isCallsign = false
+			target = strings.TrimSpace(rest)
+		case followCallsignPrefix:
+			isHex = false
+			target = strings.TrimSpace(rest)
+		}
+	}
+
+	if hex := strings.ToLower(target); isHex && followHexPattern.MatchString(hex) {
+		return FollowTarget{Hex: hex, Callsign: ""}, nil
+	}
+	if callsign := strings.ToUpper(target); isCallsign && followCallsignPattern.MatchString(callsign) {
+		return FollowTarget{Hex: "", Callsign: callsign}, nil
+	}
+	return FollowTarget{Hex: "", Callsign: ""}, fmt.Errorf(
+		"ParseFollowTarget: %w: %q, expected a hex like 3c6586 or a callsign like DLH4AB",
+		errInvalidFollowTarget, target)
+}
+
+// String returns the hex or the callsign of the target.
+func (target FollowTarget) String() string {
+	if target.Hex != "" {
+		return target.Hex
+	}
+	return target.Callsign
+}
+
+// Matches tells whether the aircraft is the target.
+func (target FollowTarget) Matches(aircraft *AircraftRecord) bool {
+	if target.Hex != "" {
+		return strings.ToLower(aircraft.Hex) == target.Hex
+	}
+	return strings.ToUpper(strings.TrimSpace(aircraft.Flight)) == target.Callsign
+}
+
+// Filter returns the aircraft which are the target, reusing the given slice. Sources without
+// per-aircraft endpoints return all aircraft, of which only the target is kept.
+func (target FollowTarget) Filter(aircraft []AircraftRecord) []AircraftRecord {
+	return slices.DeleteFunc(aircraft, func(record AircraftRecord) bool {
+		return !target.Matches(&record)
+	})
+}
+
+// createFollowReqURL builds the URL of the per-aircraft endpoint of the source, which only returns
+// the target. aviationstack can only be asked for a callsign, all its flights are requested to
+// follow a hex.
+func createFollowReqURL(source string, target FollowTarget) (string, error) {
+	by, key := "hex", target.Hex
+	if target.Hex == "" {
+		by, key = "callsign", target.Callsign
+	}
+
+	var fullURL *url.URL
+	switch source {
+	case SourceAdsbFi:
+		fullURL = (&url.URL{Scheme: "https", Host: aircraftReqHost}).JoinPath("api", "v2", by, key)
+	case SourceAdsbLol:
+		fullURL = (&url.URL{Scheme: "https", Host: adsbLolReqHost}).JoinPath("v2", by, key)
+	case SourceAdsbOne:
+		fullURL = (&url.URL{Scheme: "https", Host: adsbOneReqHost}).JoinPath("v2", by, key)
+	case SourceAdsbExchange:
+		fullURL = (&url.URL{Scheme: "https", Host: adsbExchangeReqHost}).JoinPath("v2", by, key, "/")
+	case SourceAviationstack:
+		fullURL = (&url.URL{Scheme: "https", Host: aviationstackReqHost}).JoinPath("v1", "flights")
+		query := url.Values{"flight_status": {"active"}}
+		if target.Callsign != "" {
+			query.Set("flight_icao", target.Callsign)
+		}
+		fullURL.RawQuery = query.Encode()
+	default:
+		return "", fmt.Errorf("createFollowReqURL: %w: %s", errUnknownSource, source)
+	}
+
+	validatedURL, valErr := validateURL(fullURL.String())
+	if valErr != nil {
+		return "", fmt.Errorf("createFollowReqURL: %w", valErr)
+	}
+	return validatedURL, nil
+}
+
+// VerticalPhase is what the followed aircraft is doing: standing on the ground, climbing, flying
+// level or descending.
+type VerticalPhase string
+
+const (
+	PhaseUnknown VerticalPhase = ""
+	PhaseGround  VerticalPhase = "on ground"
+	PhaseClimb   VerticalPhase = "climbing"
+	PhaseLevel   VerticalPhase = "level"
+	PhaseDescent VerticalPhase = "descending"
+)
+
+// nextPhase returns the phase of the aircraft at the track point, given the phase it was in.
+func nextPhase(previous VerticalPhase, point TrackPoint) VerticalPhase {
+	switch {
+	case point.Altitude.IsGround():
+		return PhaseGround
+	case !point.Altitude.IsKnown():
+		return previous
+	case point.VerticalRate >= followClimbRate:
+		return PhaseClimb
+	case point.VerticalRate <= -followClimbRate:
+		return PhaseDescent
+	case math.Abs(point.VerticalRate) <= followLevelRate:
+		return PhaseLevel
+	case previous == PhaseGround || previous == PhaseUnknown:
+		// Leaving the ground, or first seen, slowly climbing or descending.
+		if point.VerticalRate > 0 {
+			return PhaseClimb
+		}
+		return PhaseDescent
+	default:
+		return previous
+	}
+}
+
+// TrackPoint is where the followed aircraft was at a poll, and how high and fast.
+type TrackPoint struct {
+	Time         time.Time
+	Lat          float64
+	Lon          float64
+	Altitude     Altitude
+	GroundSpeed  float64 // GroundSpeed in [knots]
+	VerticalRate float64 // VerticalRate in [ft/min], negative when descending
+	Track        float64 // Track over ground in [degrees]
+}
+
+// newTrackPoint takes the track point of the aircraft at the given time.
+func newTrackPoint(aircraft *AircraftRecord, now time.Time) TrackPoint {
+	rate := aircraft.BaroRate
+	if rate == 0 {
+		rate = aircraft.GeomRate
+	}
+	point := TrackPoint{
+		Time:         now.Add(-time.Duration(aircraft.Seen * float64(time.Second))),
+		Lat:          0,
+		Lon:          0,
+		Altitude:     aircraft.AltBaro,
+		GroundSpeed:  aircraft.GroundSpeed,
+		VerticalRate: rate,
+		Track:        aircraft.Track,
+	}
+	if position, ok := aircraft.KnownPosition(); ok {
+		point.Lat, point.Lon = position.Lat, position.Lon
+	}
+	return point
+}
+
+// FollowEventKind is what happened to the followed aircraft.
+type FollowEventKind string
+
+const (
+	FollowFound   FollowEventKind = "found" // FollowFound is the aircraft coming into sight, or back.
+	FollowLost    FollowEventKind = "lost"  // FollowLost is the aircraft out of sight for a while.
+	FollowTakeoff FollowEventKind = "takeoff"
+	FollowLanding FollowEventKind = "landing"
+	FollowClimb   FollowEventKind = "climb"
+	FollowDescent FollowEventKind = "descent"
+	FollowLevel   FollowEventKind = "level"
+	FollowSpeed   FollowEventKind = "speed" // FollowSpeed is a change of the ground speed.
+)
+
+// FollowEvent is a change of the followed aircraft, e.g. its landing.
+type FollowEvent struct {
+	Kind     FollowEventKind
+	Point    TrackPoint // Point is where it happened, the last one in sight for FollowLost.
+	From     float64    // From is the ground speed before a FollowSpeed change, in [knots].
+	Sighting *AircraftSighting
+}
+
+// FollowTrack follows a single aircraft along its flight, keeping its track.
+type FollowTrack struct {
+	Target FollowTarget
+	// Aircraft is as of the latest poll it was in sight, nil until it was seen.
+	Aircraft *AircraftRecord
+	Points   []TrackPoint // Points of the track, oldest first.
+	phase    VerticalPhase
+	last     TrackPoint // last is the latest track point, also without a position.
+	speed    float64    // speed is the last reported ground speed in [knots], 0 on the ground.
+	inSight  bool       // inSight tells whether the aircraft was in the latest poll.
+	lost     bool       // lost is true once the aircraft has been reported lost.
+}
+
+// NewFollowTrack starts following the target, which hasn't been seen yet.
+func NewFollowTrack(target FollowTarget) *FollowTrack {
+	return &FollowTrack{
+		Target:   target,
+		Aircraft: nil,
+		Points:   nil,
+		phase:    PhaseUnknown,
+		last:     TrackPoint{}, //nolint:exhaustruct // not seen yet
+		speed:    0,
+		inSight:  false,
+		lost:     false,
+	}
+}
+
+// InSight tells whether the aircraft was in the latest poll.
+func (f *FollowTrack) InSight() bool {
+	return f.inSight
+}
+
+// Phase returns what the aircraft is doing, PhaseUnknown until it was seen.
+func (f *FollowTrack) Phase() VerticalPhase {
+	return f.phase
+}
+
+// AltitudeSparkline charts the altitude of the last points of the track, as many as the width.
+// Points without an altitude are shown as blanks.
+func (f *FollowTrack) AltitudeSparkline(width int) string {
+	points := f.Points[max(0, len(f.Points)-width):]
+	values := make([]float64, len(points))
+	for idx, point := range points {
+		values[idx] = -1
+		if feet, ok := point.Altitude.Feet(); ok {
+			values[idx] = feet
+		} else if point.Altitude.IsGround() {
+			values[idx] = 0
+		}
+	}
+	return sparkline(values)
+}
+
+// update follows the aircraft in the aircraft of a poll and returns what changed about it.
+func (f *FollowTrack) update(aircraft []AircraftRecord, now time.Time) []FollowEvent {
+	idx := slices.IndexFunc(aircraft, func(record AircraftRecord) bool { return f.Target.Matches(&record) })
+	if idx < 0 {
+		f.inSight = false
+		if f.Aircraft == nil || f.lost || now.Sub(f.last.Time) < followLostAfter {
+			return nil
+		}
+		f.lost = true
+		return []FollowEvent{{Kind: FollowLost, Point: f.last, From: 0, Sighting: nil}}
+	}
+
+	record := aircraft[idx]
+	point := newTrackPoint(&record, now)
+	var events []FollowEvent
+	if f.Aircraft == nil || f.lost {
+		events = append(events, FollowEvent{Kind: FollowFound, Point: point, From: 0, Sighting: nil})
+	}
+	f.Aircraft = &record
+	f.last = point
+	f.inSight = true
+	f.lost = false
+
+	phase := nextPhase(f.phase, point)
+	if kind, ok := phaseChange(f.phase, phase); ok {
+		events = append(events, FollowEvent{Kind: kind, Point: point, From: 0, Sighting: nil})
+	}
+	f.phase = phase
+
+	switch {
+	case phase == PhaseGround:
+		f.speed = 0
+	case f.speed == 0:
+		f.speed = point.GroundSpeed
+	case math.Abs(point.GroundSpeed-f.speed) >= followSpeedStep:
+		events = append(events, FollowEvent{Kind: FollowSpeed, Point: point, From: f.speed, Sighting: nil})
+		f.speed = point.GroundSpeed
+	}
+
+	if point.Lat != 0 || point.Lon != 0 {
+		f.Points = append(f.Points, point)
+		if len(f.Points) > followTrackLength {
+			f.Points = slices.Delete(f.Points, 0, len(f.Points)-followTrackLength)
+		}
+	}
+	return events
+}
+
+// phaseChange returns the event of a change from one phase to the next, if it's worth reporting.
+// Leaving the ground is reported as the takeoff rather than as the climb which follows.
+func phaseChange(from VerticalPhase, to VerticalPhase) (FollowEventKind, bool) {
+	if from == to || from == PhaseUnknown || to == PhaseUnknown {
+		return "", false
+	}
+	switch {
+	case from == PhaseGround:
+		return FollowTakeoff, true
+	case to == PhaseGround:
+		return FollowLanding, true
+	case to == PhaseClimb:
+		return FollowClimb, true
+	case to == PhaseDescent:
+		return FollowDescent, true
+	default:
+		return FollowLevel, true
+	}
+}
+
+// followAircraft updates the track of the followed aircraft with the current aircraft and points
+// the events at its sighting.
+func (db *Dashboard) followAircraft(now time.Time) []FollowEvent {
+	events := db.Follow.update(db.CurrentAircraft, now)
+	if len(events) == 0 {
+		return nil
+	}
+	sighting := new(AircraftSighting)
+	*sighting = db.aircraftSightings[db.Follow.Aircraft.Hex]
+	sighting.info = aircraftToString(db.Follow.Aircraft)
+	for idx := range events {
+		events[idx].Sighting = sighting
+	}
+	return events
+}
+
+// EmitFollowEvents sends the changes of the followed aircraft to all enabled sinks.
+func (notify *Notify) EmitFollowEvents(events []FollowEvent, now time.Time) {
+	for _, event := range events {
+		notify.alert(followEvent(notify.language, event, now), NotifyFollow)
+	}
+}
+
+// PrintFollowUpdate prints where the followed aircraft is after every poll, unless quiet.
+func (notify *Notify) PrintFollowUpdate(track *FollowTrack) {
+	if notify.verbosity == VerbosityQuiet || track == nil || !track.InSight() {
+		return
+	}
+	phase := notify.language.T(string(track.Phase()))
+	notify.Stdout.Printf("follow %s %s\n", aircraftToString(track.Aircraft), phase)
+}
+
+// followEvent describes the change of the followed aircraft, e.g. "DLH4AB levelled off at 36000 ft".
+func followEvent(lang i18n.Language, event FollowEvent, now time.Time) Event {
+	sighting := event.Sighting
+	flight := sighting.lastFlightNo
+	altitude := strings.TrimSpace(event.Point.Altitude.String())
+	var title, description string
+	switch event.Kind {
+	case FollowFound:
+		title = lang.T("Followed aircraft in sight")
+		description = lang.Sprintf("%s in sight at %s ft", flight, altitude)
+	case FollowLost:
+		title = lang.T("Followed aircraft out of sight")
+		description = lang.Sprintf("%s out of sight, last at %s ft", flight, altitude)
+	case FollowTakeoff:
+		title = lang.T("Followed aircraft took off")
+		description = lang.Sprintf("%s took off", flight)
+	case FollowLanding:
+		title = lang.T("Followed aircraft landed")
+		description = lang.Sprintf("%s landed", flight)
+	case FollowClimb:
+		title = lang.T("Followed aircraft climbing")
+		description = lang.Sprintf("%s climbing from %s ft", flight, altitude)
+	case FollowDescent:
+		title = lang.T("Followed aircraft descending")
+		description = lang.Sprintf("%s descending from %s ft", flight, altitude)
+	case FollowLevel:
+		title = lang.T("Followed aircraft level")
+		description = lang.Sprintf("%s levelled off at %s ft", flight, altitude)
+	case FollowSpeed:
+		title = lang.T("Followed aircraft changed speed")
+		description = lang.Sprintf("%s at %.0f kt instead of %.0f kt", flight, event.Point.GroundSpeed, event.From)
+	}
+	return Event{
+		Kind:     EventKindFollow,
+		Severity: SeverityNotable,
+		Title:    title,
+		Body: fmt.Sprintf(
+			"%s\n%s (%s)\n%s",
+			description, sighting.typeDesc, sighting.registration, sighting.whereabouts(lang)),
+		Summary:  lang.Sprintf("follow: %s: %s", description, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
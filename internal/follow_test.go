@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFollowTarget(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FollowTarget
+		valid    bool
+	}{
+		{input: "3c6444", expected: FollowTarget{Hex: "3c6444", Callsign: ""}, valid: true},
+		{input: " 3C6444 ", expected: FollowTarget{Hex: "3c6444", Callsign: ""}, valid: true},
+		{input: "~2a1f3c", expected: FollowTarget{Hex: "~2a1f3c", Callsign: ""}, valid: true},
+		{input: "dlh400", expected: FollowTarget{Hex: "", Callsign: "DLH400"}, valid: true},
+		{input: "BAW1", expected: FollowTarget{Hex: "", Callsign: "BAW1"}, valid: true},
+		// Callsigns which are six hex digits need the prefix.
+		{input: "CFE123", expected: FollowTarget{Hex: "cfe123", Callsign: ""}, valid: true},
+		{input: "callsign:CFE123", expected: FollowTarget{Hex: "", Callsign: "CFE123"}, valid: true},
+		{input: "Callsign: aca850", expected: FollowTarget{Hex: "", Callsign: "ACA850"}, valid: true},
+		{input: "callsign:402100", expected: FollowTarget{Hex: "", Callsign: "402100"}, valid: true},
+		{input: "hex:3C6444", expected: FollowTarget{Hex: "3c6444", Callsign: ""}, valid: true},
+		{input: "hex:DLH400", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+		{input: "callsign:", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+		{input: "reg:DAIBD", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+		{input: "", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+		{input: "DLH 400", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+		{input: "RYANAIR123", expected: FollowTarget{Hex: "", Callsign: ""}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			target, err := ParseFollowTarget(tt.input)
+			if tt.valid && (err != nil || target != tt.expected) {
+				t.Errorf("ParseFollowTarget(%q) = %+v, %v, expected %+v", tt.input, target, err, tt.expected)
+			}
+			if !tt.valid && !errors.Is(err, errInvalidFollowTarget) {
+				t.Errorf("ParseFollowTarget(%q) = %v, expected errInvalidFollowTarget", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestFollowTargetFilter(t *testing.T) {
+	//nolint:exhaustruct // identity only
+	aircraft := []AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400  "},
+		{Hex: "4ca7b5", Flight: "RYR1AB  "},
+		{Hex: "3C6444", Flight: ""},
+	}
+
+	byHex := FollowTarget{Hex: "3c6444", Callsign: ""}.Filter(slices.Clone(aircraft))
+	if len(byHex) != 2 || byHex[0].Flight != "DLH400  " || byHex[1].Hex != "3C6444" {
+		t.Errorf("Filter() by hex = %+v, expected both records of 3c6444", byHex)
+	}
+
+	byCallsign := FollowTarget{Hex: "", Callsign: "RYR1AB"}.Filter(slices.Clone(aircraft))
+	if len(byCallsign) != 1 || byCallsign[0].Hex != "4ca7b5" {
+		t.Errorf("Filter() by callsign = %+v, expected 4ca7b5", byCallsign)
+	}
+}
+
+func TestCreateFollowReqURL(t *testing.T) {
+	byHex := FollowTarget{Hex: "3c6444", Callsign: ""}
+	byCallsign := FollowTarget{Hex: "", Callsign: "DLH400"}
+	tests := []struct {
+		source   string
+		target   FollowTarget
+		expected string
+	}{
+		{source: SourceAdsbFi, target: byHex, expected: "https://opendata.adsb.fi/api/v2/hex/3c6444"},
+		{source: SourceAdsbFi, target: byCallsign, expected: "https://opendata.adsb.fi/api/v2/callsign/DLH400"},
+		{source: SourceAdsbLol, target: byHex, expected: "https://api.adsb.lol/v2/hex/3c6444"},
+		{source: SourceAdsbOne, target: byCallsign, expected: "https://api.adsb.one/v2/callsign/DLH400"},
+		{
+			source:   SourceAdsbExchange,
+			target:   byHex,
+			expected: "https://adsbexchange-com1.p.rapidapi.com/v2/hex/3c6444/",
+		},
+		{
+			source:   SourceAviationstack,
+			target:   byCallsign,
+			expected: "https://api.aviationstack.com/v1/flights?flight_icao=DLH400&flight_status=active",
+		},
+		{
+			source:   SourceAviationstack,
+			target:   byHex,
+			expected: "https://api.aviationstack.com/v1/flights?flight_status=active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source+" "+tt.target.String(), func(t *testing.T) {
+			reqURL, err := createFollowReqURL(tt.source, tt.target)
+			if err != nil || reqURL != tt.expected {
+				t.Errorf("createFollowReqURL() = %s, %v, expected %s", reqURL, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := createFollowReqURL("nosuchsource", byHex); !errors.Is(err, errUnknownSource) {
+		t.Errorf("createFollowReqURL() = %v, expected errUnknownSource", err)
+	}
+}
+
+func TestFollowTrackUpdate(t *testing.T) {
+	track := NewFollowTrack(FollowTarget{Hex: "3c6444", Callsign: ""})
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	poll := func(altitude Altitude, speed float64, rate float64) AircraftRecord {
+		//nolint:exhaustruct // followed fields only
+		return AircraftRecord{
+			Hex: "3c6444", Lat: 53.63, Lon: 9.99, AltBaro: altitude, GroundSpeed: speed, BaroRate: rate,
+		}
+	}
+	other := AircraftRecord{Hex: "4ca7b5"} //nolint:exhaustruct // not the target
+
+	tests := []struct {
+		name     string
+		aircraft []AircraftRecord
+		after    time.Duration
+		expected []FollowEventKind
+	}{
+		{name: "not seen yet", aircraft: []AircraftRecord{other}, after: 0, expected: nil},
+		{
+			name:     "found on the ground",
+			aircraft: []AircraftRecord{other, poll(GroundAltitude(), 20, 0)},
+			after:    0,
+			expected: []FollowEventKind{FollowFound},
+		},
+		{
+			name:     "takeoff",
+			aircraft: []AircraftRecord{poll(NewAltitude(800), 160, 2000)},
+			after:    time.Minute,
+			expected: []FollowEventKind{FollowTakeoff},
+		},
+		{
+			name:     "still climbing",
+			aircraft: []AircraftRecord{poll(NewAltitude(9000), 190, 300)},
+			after:    5 * time.Minute,
+			expected: nil,
+		},
+		{
+			name:     "level and faster",
+			aircraft: []AircraftRecord{poll(NewAltitude(36000), 460, 0)},
+			after:    25 * time.Minute,
+			expected: []FollowEventKind{FollowLevel, FollowSpeed},
+		},
+		{
+			name:     "descent",
+			aircraft: []AircraftRecord{poll(NewAltitude(30000), 440, -1800)},
+			after:    time.Hour,
+			expected: []FollowEventKind{FollowDescent},
+		},
+		{name: "missed a poll", aircraft: nil, after: time.Hour + 2*time.Minute, expected: nil},
+		{
+			name:     "lost",
+			aircraft: []AircraftRecord{other},
+			after:    time.Hour + 5*time.Minute,
+			expected: []FollowEventKind{FollowLost},
+		},
+		{name: "lost only once", aircraft: nil, after: time.Hour + 6*time.Minute, expected: nil},
+		{
+			name:     "found again and landed",
+			aircraft: []AircraftRecord{poll(GroundAltitude(), 30, 0)},
+			after:    time.Hour + 30*time.Minute,
+			expected: []FollowEventKind{FollowFound, FollowLanding},
+		},
+	}
+
+	for _, tt := range tests {
+		events := track.update(tt.aircraft, start.Add(tt.after))
+		kinds := make([]FollowEventKind, 0, len(events))
+		for _, event := range events {
+			kinds = append(kinds, event.Kind)
+		}
+		if !slices.Equal(kinds, tt.expected) {
+			t.Errorf("%s: update() = %v, expected %v", tt.name, kinds, tt.expected)
+		}
+	}
+
+	if track.Phase() != PhaseGround || !track.InSight() || len(track.Points) != 6 {
+		t.Errorf("track is %s, in sight %v, with %d points, expected on the ground, in sight, with 6 points",
+			track.Phase(), track.InSight(), len(track.Points))
+	}
+}
+
+func TestFollowTrackLength(t *testing.T) {
+	track := NewFollowTrack(FollowTarget{Hex: "", Callsign: "DLH400"})
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for idx := range followTrackLength + 10 {
+		//nolint:exhaustruct // followed fields only
+		aircraft := AircraftRecord{Flight: "DLH400", Lat: 53 + float64(idx)/100, Lon: 10, AltBaro: NewAltitude(36000)}
+		track.update([]AircraftRecord{aircraft}, start.Add(time.Duration(idx)*30*time.Second))
+	}
+
+	if oldest := 53 + float64(10)/100; len(track.Points) != followTrackLength || track.Points[0].Lat != oldest {
+		t.Errorf("track has %d points from %v, expected %d from %v",
+			len(track.Points), track.Points[0].Lat, followTrackLength, oldest)
+	}
+}
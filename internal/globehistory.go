@@ -0,0 +1,388 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log" //nolint:depguard // Don't feel like using slog
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The files of readsb's globe_history directory, which tar1090 shows the history and heatmap of.
+// Both are usually gzip-compressed, whatever their extension.
+const (
+	traceFilePrefix   = "trace_full_" // traceFilePrefix starts the names of the daily trace files.
+	heatmapFileSuffix = ".bin.ttf"    // heatmapFileSuffix ends the names of the heatmap files.
+)
+
+const (
+	// traceNewLeg is the flag of a trace point which starts a new leg.
+	traceNewLeg = 2
+	// traceMinFields is how many fields a trace point has at least, up to its flags.
+	traceMinFields = 7
+	// traceDetailsField is the index of the details of a trace point, e.g. the callsign.
+	traceDetailsField = 8
+	// heatmapEntrySize is the size of an entry of a heatmap.
+	heatmapEntrySize = 16
+	// heatmapSliceMarker is the hex of the entries which start the slices of a heatmap.
+	heatmapSliceMarker = 0xe7f7c9d
+	// heatmapCallsignMarker is the least latitude of the entries which carry a callsign.
+	heatmapCallsignMarker = 1 << 30
+	// heatmapNonICAO flags the addresses which aren't ICAO hexes, e.g. of TIS-B.
+	heatmapNonICAO = 1 << 24
+	// heatmapHexMask masks the ICAO hex of the address of an entry.
+	heatmapHexMask = 1<<24 - 1
+)
+
+var (
+	errHistoryDisabled = errors.New("sighting history is disabled")
+	errNoImportFiles   = errors.New("no trace or heatmap files found")
+)
+
+// ImportSummary tells what was imported from the history of a receiver.
+type ImportSummary struct {
+	Files   int // Files is how many trace and heatmap files were read.
+	Failed  int // Failed is how many files couldn't be read.
+	Flights int // Flights is how many flights were added to the sighting history.
+	Skipped int // Skipped is how many flights were in the sighting history already.
+}
+
+// importedFlight is a flight of an aircraft, as put together from the points of its history.
+type importedFlight struct {
+	record    AircraftRecord
+	firstSeen time.Time
+	lastSeen  time.Time
+	newLeg    bool // newLeg tells whether the flight starts a new leg rather than continue one.
+}
+
+// flightLog tells the flights of imported points apart like the dashboard: by their callsign, and
+// also by the legs of the trace or long gaps between them.
+type flightLog struct {
+	flights []*importedFlight
+	current map[string]*importedFlight // current maps hexes to their latest flight.
+}
+
+func newFlightLog() *flightLog {
+	return &flightLog{flights: nil, current: make(map[string]*importedFlight)}
+}
+
+// add continues the current flight of the aircraft with the given one, or starts a new flight.
+func (l *flightLog) add(flight importedFlight) {
+	current, exists := l.current[flight.record.Hex]
+	if !exists || !current.continuedBy(&flight) {
+		added := flight
+		l.flights = append(l.flights, &added)
+		l.current[flight.record.Hex] = &added
+		return
+	}
+
+	current.firstSeen = minTime(current.firstSeen, flight.firstSeen)
+	current.lastSeen = maxTime(current.lastSeen, flight.lastSeen)
+	record := &current.record
+	if record.GetFlightNoAsStr() == flightUnknown {
+		record.Flight = flight.record.Flight
+	}
+	record.Registration = cmp.Or(record.Registration, flight.record.Registration)
+	record.IcaoType = cmp.Or(record.IcaoType, flight.record.IcaoType)
+	record.OwnOp = cmp.Or(record.OwnOp, flight.record.OwnOp)
+	record.Description = cmp.Or(record.Description, flight.record.Description)
+}
+
+// continuedBy tells whether the given flight of the same aircraft is a continuation of this one.
+func (f *importedFlight) continuedBy(next *importedFlight) bool {
+	if next.newLeg || next.firstSeen.Sub(f.lastSeen) > airframeLegGap {
+		return false
+	}
+	flight, nextFlight := f.record.GetFlightNoAsStr(), next.record.GetFlightNoAsStr()
+	return flight == flightUnknown || nextFlight == flightUnknown || flight == nextFlight
+}
+
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// ImportHistory backfills the sighting history with the flights found in the globe_history
+// directory of readsb, from the daily traces of the aircraft or the heatmaps of tar1090, so that
+// rarity starts from the baseline of all the years the receiver has been running. Flights in the
+// history already, by their hex, callsign and day, are skipped, so importing again adds only the
+// new ones. The imported flights count towards the statistics like live ones.
+func (db *Dashboard) ImportHistory(dir string) (ImportSummary, error) {
+	summary := ImportSummary{Files: 0, Failed: 0, Flights: 0, Skipped: 0}
+	if db.history == nil {
+		return summary, fmt.Errorf("ImportHistory: %w", errHistoryDisabled)
+	}
+
+	existing, loadErr := db.history.Load()
+	if loadErr != nil {
+		return summary, fmt.Errorf("ImportHistory: %w", loadErr)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, entry := range existing {
+		known[importKey(entry)] = true
+	}
+
+	flights, readErr := readGlobeHistory(dir, &summary, &db.errOut)
+	if readErr != nil {
+		return summary, fmt.Errorf("ImportHistory: %w", readErr)
+	}
+
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+
+	var entries []HistoryEntry
+	for _, flight := range flights {
+		entry := db.describeImportedFlight(flight)
+		if known[importKey(entry)] {
+			summary.Skipped++
+			continue
+		}
+		known[importKey(entry)] = true
+		entries = append(entries, entry)
+		db.Discovery.Record(entry)
+		db.airframes.Record(entry.Hex, entry.Flight, flight.lastSeen)
+	}
+	slices.SortStableFunc(entries, func(a HistoryEntry, b HistoryEntry) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	if err := db.history.Append(entries); err != nil {
+		return summary, fmt.Errorf("ImportHistory: %w", err)
+	}
+	summary.Flights = len(entries)
+	return summary, nil
+}
+
+// describeImportedFlight looks up the type, operator and country of the flight like those of a
+// live one.
+func (db *Dashboard) describeImportedFlight(flight *importedFlight) HistoryEntry {
+	sighting := newAircraftSighting(&flight.record, flight.firstSeen)
+	sighting.lastFlightNo = flight.record.GetFlightNoAsStr()
+	db.updateType(&sighting, &flight.record, true)
+	db.updateOperator(&sighting, &flight.record, true)
+	db.updateCountry(&sighting, &flight.record, true)
+	if db.comparison != nil {
+		db.comparison.finishSighting()
+	}
+	return sightingToHistoryEntry(flight.record.Hex, &sighting)
+}
+
+// importKey tells the flights of the sighting history apart by aircraft, callsign and day.
+func importKey(entry HistoryEntry) string {
+	return entry.Hex + "/" + strings.TrimSpace(entry.Flight) + "/" + entry.Time.UTC().Format(time.DateOnly)
+}
+
+// readGlobeHistory reads all trace and heatmap files below the directory and returns their
+// flights by aircraft and time. Files which can't be read are logged and skipped, since a history
+// of years usually has a few which were cut off.
+func readGlobeHistory(dir string, summary *ImportSummary, errOut *log.Logger) ([]*importedFlight, error) {
+	var flights []*importedFlight
+	walkErr := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		var read func(io.Reader) ([]*importedFlight, error)
+		switch name := entry.Name(); {
+		case entry.IsDir():
+			return nil
+		case strings.HasPrefix(name, traceFilePrefix):
+			read = readTrace
+		case strings.HasSuffix(name, heatmapFileSuffix):
+			read = readHeatmap
+		default:
+			return nil
+		}
+
+		summary.Files++
+		fileFlights, readErr := readHistoryFile(path, read)
+		if readErr != nil {
+			summary.Failed++
+			errOut.Println(fmt.Errorf("readGlobeHistory: %w", readErr))
+			return nil
+		}
+		flights = append(flights, fileFlights...)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("readGlobeHistory: %w", walkErr)
+	}
+	if summary.Files == 0 {
+		return nil, fmt.Errorf("readGlobeHistory: %s: %w", dir, errNoImportFiles)
+	}
+
+	// Flights may continue across the files of several days, or be in both traces and heatmaps.
+	// New legs go first, so that they are continued by what was seen at the same time.
+	slices.SortStableFunc(flights, func(a *importedFlight, b *importedFlight) int {
+		return cmp.Or(
+			strings.Compare(a.record.Hex, b.record.Hex),
+			a.firstSeen.Compare(b.firstSeen),
+			compareBool(b.newLeg, a.newLeg))
+	})
+	merged := newFlightLog()
+	for _, flight := range flights {
+		merged.add(*flight)
+	}
+	return merged.flights, nil
+}
+
+// readHistoryFile reads a trace or heatmap file, decompressing it if necessary.
+func readHistoryFile(path string, read func(io.Reader) ([]*importedFlight, error)) ([]*importedFlight, error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, fmt.Errorf("readHistoryFile: %w", openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	var input io.Reader = reader
+	if magic, _ := reader.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, gzipErr := gzip.NewReader(reader)
+		if gzipErr != nil {
+			return nil, fmt.Errorf("readHistoryFile: %s: %w", path, gzipErr)
+		}
+		input = gzipReader
+	}
+
+	flights, err := read(input)
+	if err != nil {
+		return nil, fmt.Errorf("readHistoryFile: %s: %w", path, err)
+	}
+	return flights, nil
+}
+
+// globeTrace is the trace of an aircraft over a day, as written by readsb.
+type globeTrace struct {
+	Hex          string              `json:"icao"`
+	Registration string              `json:"r"`
+	IcaoType     string              `json:"t"`
+	OwnOp        string              `json:"ownOp"`
+	Description  string              `json:"desc"`
+	Timestamp    float64             `json:"timestamp"` // Timestamp is when the trace starts, in seconds.
+	Points       [][]json.RawMessage `json:"trace"`
+}
+
+// tracePointDetails are the details of a trace point, which are only given now and then.
+type tracePointDetails struct {
+	Flight string `json:"flight"`
+}
+
+// readTrace returns the flights of a trace, i.e. its legs and callsigns.
+func readTrace(input io.Reader) ([]*importedFlight, error) {
+	var trace globeTrace
+	if err := json.NewDecoder(input).Decode(&trace); err != nil {
+		return nil, fmt.Errorf("readTrace: %w", err)
+	}
+	// Other addresses than ICAO hexes start with "~", they can't be looked up.
+	if trace.Hex == "" || strings.HasPrefix(trace.Hex, "~") {
+		return nil, nil
+	}
+
+	start := time.UnixMilli(int64(trace.Timestamp * float64(time.Second/time.Millisecond)))
+	flights := newFlightLog()
+	for _, point := range trace.Points {
+		if len(point) < traceMinFields {
+			continue
+		}
+		var offset float64
+		var flags int
+		if json.Unmarshal(point[0], &offset) != nil || json.Unmarshal(point[6], &flags) != nil {
+			continue
+		}
+		var details tracePointDetails
+		if len(point) > traceDetailsField {
+			// Points without details have null instead, which leaves them empty.
+			_ = json.Unmarshal(point[traceDetailsField], &details)
+		}
+
+		seen := start.Add(time.Duration(offset * float64(time.Second)))
+		flights.add(importedFlight{
+			record: AircraftRecord{ //nolint:exhaustruct // the identity of the aircraft only
+				Hex:          strings.ToLower(trace.Hex),
+				Flight:       details.Flight,
+				Registration: trace.Registration,
+				IcaoType:     trace.IcaoType,
+				OwnOp:        trace.OwnOp,
+				Description:  trace.Description,
+			},
+			firstSeen: seen,
+			lastSeen:  seen,
+			newLeg:    flags&traceNewLeg != 0,
+		})
+	}
+	return flights.flights, nil
+}
+
+// readHeatmap returns the flights of a heatmap, which only knows the hexes and callsigns of the
+// aircraft. Its entries are the hex, latitude, longitude, altitude and speed of an aircraft, as
+// written by readsb. Slices of positions start with a marker entry which holds their time
+// instead, callsigns are given by entries of their own.
+func readHeatmap(input io.Reader) ([]*importedFlight, error) {
+	flights := newFlightLog()
+	callsigns := make(map[string]string)
+	var sliceTime time.Time
+	var entry [heatmapEntrySize]byte
+	for {
+		if _, err := io.ReadFull(input, entry[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return flights.flights, nil
+			}
+			return flights.flights, fmt.Errorf("readHeatmap: %w", err)
+		}
+
+		address := binary.LittleEndian.Uint32(entry[0:4])
+		lat := binary.LittleEndian.Uint32(entry[4:8])
+		if address == heatmapSliceMarker {
+			millis := uint64(lat)<<32 | uint64(binary.LittleEndian.Uint32(entry[8:12]))
+			sliceTime = time.UnixMilli(int64(millis)) //nolint:gosec // readsb writes the time as two halves
+			continue
+		}
+		if sliceTime.IsZero() || address&heatmapNonICAO != 0 {
+			continue
+		}
+
+		hex := fmt.Sprintf("%06x", address&heatmapHexMask)
+		if int32(lat) >= heatmapCallsignMarker { //nolint:gosec // latitudes are signed
+			callsigns[hex] = strings.TrimSpace(string(bytes.TrimRight(entry[8:16], "\x00")))
+			continue
+		}
+		flights.add(importedFlight{
+			record:    AircraftRecord{Hex: hex, Flight: callsigns[hex]}, //nolint:exhaustruct // all a heatmap knows
+			firstSeen: sliceTime,
+			lastSeen:  sliceTime,
+			newLeg:    false,
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	traceDLH = `{"icao":"3c6444","r":"D-AIBD","t":"A320","timestamp":1792231200.0,"trace":[
+		[0,53.6,9.9,35000,450.1,270,0,0,{"flight":"DLH400  ","squawk":"1000"},"adsb_icao",35500],
+		[60,53.6,9.8,35000,450.1,270,0,0,null,"adsb_icao",35500],
+		[7200,53.6,9.9,"ground",0,0,2,0,{"flight":"DLH401  "},"adsb_icao",null]]}`
+	traceRYR = `{"icao":"4ca7b5","r":"EI-DCL","t":"B738","timestamp":1792231200.0,"trace":[
+		[0,53.5,10.0,12000,300,90,0,0,null,"adsb_icao",12200]]}`
+)
+
+// heatmapEntry encodes an entry of a heatmap as written by readsb.
+func heatmapEntry(address uint32, lat uint32, rest []byte) []byte {
+	entry := binary.LittleEndian.AppendUint32(nil, address)
+	entry = binary.LittleEndian.AppendUint32(entry, lat)
+	return append(entry, append(rest, make([]byte, heatmapEntrySize-8-len(rest))...)...)
+}
+
+func writeGzip(t *testing.T, path string, content []byte) {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buffer.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeGlobeHistory creates a globe_history directory of a day with two traces and a heatmap.
+func writeGlobeHistory(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	day := filepath.Join(dir, "2026", "10", "17")
+	for _, sub := range []string{"traces/44", "traces/b5", "heatmap"} {
+		if err := os.MkdirAll(filepath.Join(day, sub), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(day, "traces/44", "trace_full_3c6444.json"), []byte(traceDLH), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeGzip(t, filepath.Join(day, "traces/b5", "trace_full_4ca7b5.json"), []byte(traceRYR))
+
+	noon := uint64(time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC).UnixMilli())
+	var heatmap []byte
+	heatmap = append(heatmap, heatmapEntry(0x3c6444, 53600000, nil)...) // before any slice
+	heatmap = append(heatmap, heatmapEntry(heatmapSliceMarker, uint32(noon>>32),
+		binary.LittleEndian.AppendUint32(nil, uint32(noon)))...)
+	heatmap = append(heatmap, heatmapEntry(0x3c6444, heatmapCallsignMarker, []byte("DLH401  "))...)
+	heatmap = append(heatmap, heatmapEntry(0x3c6444, 53600000, nil)...)
+	heatmap = append(heatmap, heatmapEntry(0x440123, 53600000, nil)...)
+	heatmap = append(heatmap, heatmapEntry(heatmapNonICAO|0x123456, 53600000, nil)...)
+	writeGzip(t, filepath.Join(day, "heatmap", "24.bin.ttf"), heatmap)
+	return dir
+}
+
+func TestReadGlobeHistory(t *testing.T) {
+	dir := writeGlobeHistory(t)
+	var summary ImportSummary
+	flights, err := readGlobeHistory(dir, &summary, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Files != 3 || summary.Failed != 0 {
+		t.Errorf("summary = %+v, expected 3 files read", summary)
+	}
+
+	start := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	expected := []struct {
+		hex       string
+		flight    string
+		firstSeen time.Time
+		lastSeen  time.Time
+	}{
+		{hex: "3c6444", flight: "DLH400", firstSeen: start, lastSeen: start.Add(time.Minute)},
+		// The new leg of the trace continues in the heatmap.
+		{hex: "3c6444", flight: "DLH401", firstSeen: start.Add(2 * time.Hour), lastSeen: start.Add(2 * time.Hour)},
+		{hex: "440123", flight: flightUnknown, firstSeen: start.Add(2 * time.Hour), lastSeen: start.Add(2 * time.Hour)},
+		{hex: "4ca7b5", flight: flightUnknown, firstSeen: start, lastSeen: start},
+	}
+	if len(flights) != len(expected) {
+		t.Fatalf("readGlobeHistory() = %d flights, expected %d", len(flights), len(expected))
+	}
+	for idx, flight := range flights {
+		want := expected[idx]
+		if flight.record.Hex != want.hex || flight.record.GetFlightNoAsStr() != want.flight ||
+			!flight.firstSeen.Equal(want.firstSeen) || !flight.lastSeen.Equal(want.lastSeen) {
+			t.Errorf("flight %d = %s %q %v-%v, expected %+v", idx, flight.record.Hex,
+				flight.record.GetFlightNoAsStr(), flight.firstSeen, flight.lastSeen, want)
+		}
+	}
+	if flights[0].record.IcaoType != "A320" || flights[0].record.Registration != "D-AIBD" {
+		t.Errorf("flight 0 = %+v, expected the A320 D-AIBD", flights[0].record)
+	}
+}
+
+func TestImportHistory(t *testing.T) {
+	dir := writeGlobeHistory(t)
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio", HistoryPath: historyPath}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	summary, importErr := dashboard.ImportHistory(dir)
+	if importErr != nil {
+		t.Fatal(importErr)
+	}
+	if summary.Flights != 4 || summary.Skipped != 0 {
+		t.Errorf("ImportHistory() = %+v, expected 4 flights", summary)
+	}
+	entries, loadErr := dashboard.LoadHistory()
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if len(entries) != 4 || entries[0].Type != dashboard.IcaoToAircraft["A320"].Make {
+		t.Errorf("LoadHistory() = %+v, expected 4 entries starting with the A320", entries)
+	}
+
+	// Importing again adds nothing.
+	again, againErr := dashboard.ImportHistory(dir)
+	if againErr != nil {
+		t.Fatal(againErr)
+	}
+	if again.Flights != 0 || again.Skipped != 4 {
+		t.Errorf("ImportHistory() again = %+v, expected all 4 flights skipped", again)
+	}
+}
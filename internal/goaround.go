@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// airportRange is how far in [km] airports may be from our location to watch them for
+	// go-arounds. Aircraft close to the ground are rarely received from farther away.
+	airportRange = 250.0
+
+	// goAroundRadius is how far in [km] from the reference point of an airport an aircraft has to
+	// be for its approach to count, which covers the runways and the final few miles before them.
+	goAroundRadius = 10.0
+	// goAroundCeiling is the height in [ft] above the airport below which approaches are watched.
+	goAroundCeiling = 3000.0
+	// goAroundLowest is the height in [ft] above the airport an approach has to get down to before
+	// climbing away counts as a go-around rather than a level-off on the way in.
+	goAroundLowest = 1000.0
+	// goAroundDescent is the vertical rate in [ft/min] an aircraft has to sink with to be on an
+	// approach.
+	goAroundDescent = -300.0
+	// goAroundClimb is the vertical rate in [ft/min] an aircraft has to climb with after the
+	// approach, together with goAroundGain.
+	goAroundClimb = 500.0
+	// goAroundGain is how high in [ft] an aircraft has to climb above the lowest point of its
+	// approach, so that the jitter of the reported altitude doesn't count as a climb.
+	goAroundGain = 200.0
+)
+
+// goAroundState follows an aircraft descending towards an airport, to notice it climbing away
+// instead of landing.
+type goAroundState struct {
+	airport    string  // airport is the ICAO code of the airport approached, empty if none.
+	lowest     float64 // lowest is the lowest height above the airport of the approach in [ft].
+	descending bool    // descending tells whether the aircraft was seen sinking on the approach.
+}
+
+// noGoAround is the state of an aircraft which isn't approaching any airport.
+func noGoAround() goAroundState {
+	return goAroundState{airport: "", lowest: 0, descending: false}
+}
+
+// GoAroundAlert is an aircraft which went around or flew a missed approach at a nearby airport.
+type GoAroundAlert struct {
+	Airport  dash.Airport
+	Lowest   float64 // Lowest is the lowest height above the airport of the approach in [ft].
+	Sighting *AircraftSighting
+}
+
+// airportsNear returns the airports within airportRange of the given location.
+func airportsNear(airports []dash.Airport, lat float64, lon float64) []dash.Airport {
+	here := dash.NewCoordinates(lat, lon)
+	var near []dash.Airport
+	for _, airport := range airports {
+		if dash.Distance(here, dash.NewCoordinates(airport.Lat, airport.Lon)).Kilometers() <= airportRange {
+			near = append(near, airport)
+		}
+	}
+	return near
+}
+
+// checkGoAround follows airborne aircraft close to a nearby airport and reports one which sank
+// to within goAroundLowest of it and then climbed away by goAroundGain. Landing, leaving the
+// airport or climbing above goAroundCeiling starts over, so that only a new approach can go
+// around again. The height above the airport is taken from the geometric altitude if it is
+// reported, since the barometric one is off from the elevation by the air pressure of the day.
+func (db *Dashboard) checkGoAround(sighting *AircraftSighting, aircraft *AircraftRecord) (GoAroundAlert, bool) {
+	noAlert := GoAroundAlert{
+		Airport:  dash.Airport{Icao: "", Name: "", Lat: 0, Lon: 0, Elevation: 0},
+		Lowest:   0,
+		Sighting: nil,
+	}
+	feet, hasAltitude := aircraft.AltBaro.Feet()
+	if hasAltitude && aircraft.AltGeom != 0 {
+		feet = float64(aircraft.AltGeom)
+	}
+	if !hasAltitude || (aircraft.Lat == 0 && aircraft.Lon == 0) {
+		sighting.goAround = noGoAround()
+		return noAlert, false
+	}
+	airport, distance, ok := dash.NearestAirport(db.airports, aircraft.Lat, aircraft.Lon)
+	height := feet - airport.Elevation
+	if !ok || distance > goAroundRadius || height > goAroundCeiling {
+		sighting.goAround = noGoAround()
+		return noAlert, false
+	}
+
+	state := &sighting.goAround
+	if state.airport != airport.Icao {
+		*state = goAroundState{airport: airport.Icao, lowest: height, descending: false}
+	}
+	state.lowest = min(state.lowest, height)
+	rate := aircraft.BaroRate
+	if rate == 0 {
+		rate = aircraft.GeomRate
+	}
+	if rate <= goAroundDescent {
+		state.descending = true
+	}
+	if !state.descending || state.lowest > goAroundLowest || rate < goAroundClimb ||
+		height-state.lowest < goAroundGain {
+		return noAlert, false
+	}
+
+	lowest := state.lowest
+	*state = goAroundState{airport: airport.Icao, lowest: height, descending: false}
+	return GoAroundAlert{Airport: airport, Lowest: lowest, Sighting: sighting}, true
+}
+
+// GoAroundStats counts the go-arounds by airport over the session and of the current spotting
+// day.
+type GoAroundStats struct {
+	day      SpottingDay    // day tells when the days start.
+	date     string         // date is the date of the current day, empty before the first go-around.
+	today    int            // today is how many go-arounds there were on the current day.
+	airports map[string]int // airports maps the ICAO codes of airports to their go-arounds.
+}
+
+// NewGoAroundStats creates empty go-around statistics.
+func NewGoAroundStats(day SpottingDay) *GoAroundStats {
+	return &GoAroundStats{day: day, date: "", today: 0, airports: make(map[string]int)}
+}
+
+// Record counts the go-arounds of a poll at the given time.
+func (gs *GoAroundStats) Record(now time.Time, alerts []GoAroundAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+	if date := gs.dateOf(now); date != gs.date {
+		gs.date = date
+		gs.today = 0
+	}
+	gs.today += len(alerts)
+	for _, alert := range alerts {
+		gs.airports[alert.Airport.Icao]++
+	}
+}
+
+// Today returns how many go-arounds there were on the spotting day of the given time.
+func (gs *GoAroundStats) Today(now time.Time) int {
+	if gs.dateOf(now) != gs.date {
+		return 0
+	}
+	return gs.today
+}
+
+// Total returns how many go-arounds there were in the session.
+func (gs *GoAroundStats) Total() int {
+	total := 0
+	for _, count := range gs.airports {
+		total += count
+	}
+	return total
+}
+
+// String lists the go-arounds of the session by airport, the most first.
+func (gs *GoAroundStats) String() string {
+	if len(gs.airports) == 0 {
+		return "none yet"
+	}
+	airports := make([]string, 0, len(gs.airports))
+	for airport := range gs.airports {
+		airports = append(airports, airport)
+	}
+	slices.SortFunc(airports, func(a, b string) int {
+		if byCount := gs.airports[b] - gs.airports[a]; byCount != 0 {
+			return byCount
+		}
+		return strings.Compare(a, b)
+	})
+	parts := make([]string, len(airports))
+	for idx, airport := range airports {
+		parts[idx] = fmt.Sprintf("%s %d", airport, gs.airports[airport])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (gs *GoAroundStats) dateOf(t time.Time) string {
+	return gs.day.Start(t).Format(time.DateOnly)
+}
+
+// EmitGoArounds reports go-arounds at nearby airports to the console and file sinks, or to all
+// enabled sinks if go-around alerts are enabled.
+func (notify *Notify) EmitGoArounds(alerts []GoAroundAlert, now time.Time) {
+	for _, alert := range alerts {
+		event := goAroundEvent(notify.language, alert, now)
+		if notify.goAround {
+			event.Severity = SeverityNotable
+			notify.alert(event, NotifyGoAround)
+		} else {
+			notify.logEvent(event)
+		}
+	}
+}
+
+func goAroundEvent(lang i18n.Language, alert GoAroundAlert, now time.Time) Event {
+	sighting := alert.Sighting
+	msgBody := lang.Sprintf(
+		"%s %s (%s) went around at %s\n%s",
+		sighting.lastFlightNo,
+		sighting.typeDesc,
+		sighting.registration,
+		alert.Airport.Name,
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindGoAround,
+		Severity: SeverityInfo,
+		Title:    lang.T("Go-around"),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("go-around at %s from %.0f ft: %s", alert.Airport.Icao, alert.Lowest, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// approachPoll is the altitude in [ft] and vertical rate in [ft/min] of an aircraft on a poll.
+type approachPoll struct {
+	altitude float64
+	rate     float64
+}
+
+// lowPressure is how far in [ft] the barometric altitude is above the geometric one on a day of
+// low air pressure, 980 instead of 1013 hPa.
+const lowPressure = 900
+
+func TestCheckGoAround(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      []approachPoll
+		lat       float64
+		geometric bool // geometric tells whether the geometric altitude is reported as well.
+		alerts    int
+	}{
+		{
+			name:      "going around",
+			path:      []approachPoll{{1500, -700}, {900, -700}, {400, -700}, {600, 1500}, {1200, 2000}},
+			lat:       53.65,
+			geometric: false,
+			alerts:    1,
+		},
+		{
+			name:      "landing",
+			path:      []approachPoll{{1500, -700}, {900, -700}, {400, -700}, {-1, 0}},
+			lat:       53.65,
+			geometric: false,
+			alerts:    0,
+		},
+		{
+			name:      "touch-and-go",
+			path:      []approachPoll{{900, -700}, {400, -700}, {-1, 0}, {400, 1500}, {1000, 2000}},
+			lat:       53.65,
+			geometric: false,
+			alerts:    0,
+		},
+		{
+			name:      "departing",
+			path:      []approachPoll{{-1, 0}, {300, 2000}, {1200, 2500}, {2200, 2500}},
+			lat:       53.65,
+			geometric: false,
+			alerts:    0,
+		},
+		{
+			name:      "leveling off high on the approach",
+			path:      []approachPoll{{2800, -800}, {2000, -800}, {2000, 0}, {2300, 600}},
+			lat:       53.65,
+			geometric: false,
+			alerts:    0,
+		},
+		{
+			name:      "far from the airport",
+			path:      []approachPoll{{1500, -700}, {900, -700}, {400, -700}, {600, 1500}, {1200, 2000}},
+			lat:       54.5,
+			geometric: false,
+			alerts:    0,
+		},
+		{
+			name:      "going around on a day of low pressure",
+			path:      []approachPoll{{1500, -700}, {900, -700}, {400, -700}, {600, 1500}, {1200, 2000}},
+			lat:       53.65,
+			geometric: true,
+			alerts:    1,
+		},
+		{
+			name: "going around twice",
+			path: []approachPoll{
+				{900, -700}, {400, -700}, {700, 1500}, {1500, 1000}, {900, -700}, {400, -700}, {700, 1500},
+			},
+			lat:       53.65,
+			geometric: false,
+			alerts:    2,
+		},
+	}
+
+	hamburg := dash.Airport{Icao: "EDDH", Name: "Hamburg", Lat: 53.6304, Lon: 9.9882, Elevation: 53}
+	db := &Dashboard{airports: []dash.Airport{hamburg}} //nolint:exhaustruct // only the airports
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sighting := &AircraftSighting{goAround: noGoAround()} //nolint:exhaustruct // approach only
+			alerts := 0
+			for _, poll := range test.path {
+				altitude := NewAltitude(poll.altitude)
+				if poll.altitude < 0 {
+					altitude = GroundAltitude()
+				}
+				//nolint:exhaustruct // position and altitude only
+				aircraft := &AircraftRecord{Lat: test.lat, Lon: 9.99, AltBaro: altitude, BaroRate: poll.rate}
+				if test.geometric && poll.altitude >= 0 {
+					// The barometric altitude alone never gets low enough.
+					aircraft.AltBaro = NewAltitude(poll.altitude + lowPressure)
+					aircraft.AltGeom = int(poll.altitude)
+				}
+				if alert, ok := db.checkGoAround(sighting, aircraft); ok {
+					alerts++
+					if alert.Airport.Icao != "EDDH" {
+						t.Errorf("checkGoAround() at %s, expected EDDH", alert.Airport.Icao)
+					}
+				}
+			}
+			if alerts != test.alerts {
+				t.Errorf("checkGoAround() alerted %d times, expected %d", alerts, test.alerts)
+			}
+		})
+	}
+}
+
+func TestGoAroundStats(t *testing.T) {
+	stats := NewGoAroundStats(SpottingDay{})
+	evening := time.Date(2026, time.May, 1, 22, 0, 0, 0, time.Local)
+	hamburg := GoAroundAlert{Airport: dash.Airport{Icao: "EDDH"}}      //nolint:exhaustruct // airport only
+	finkenwerder := GoAroundAlert{Airport: dash.Airport{Icao: "EDHI"}} //nolint:exhaustruct // airport only
+
+	if stats.String() != "none yet" {
+		t.Errorf("String() = %q without go-arounds", stats.String())
+	}
+	stats.Record(evening, []GoAroundAlert{finkenwerder, hamburg})
+	stats.Record(evening.Add(3*time.Hour), []GoAroundAlert{hamburg})
+	if today := stats.Today(evening.Add(3 * time.Hour)); today != 1 {
+		t.Errorf("Today() = %d after midnight, expected 1", today)
+	}
+	if stats.Total() != 3 || stats.String() != "EDDH 2, EDHI 1" {
+		t.Errorf("Total() = %d, String() = %q, expected 3 as EDDH 2, EDHI 1", stats.Total(), stats.String())
+	}
+}
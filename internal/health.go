@@ -0,0 +1,258 @@
+package internal
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HealthPath is where the health status is served.
+	HealthPath = "/healthz"
+	// SourcesPath is where the data sources are listed and switched.
+	SourcesPath = "/sources"
+
+	// pollAgeFactor is how many of the longest poll intervals the last successful poll may be ago
+	// before the instance is unhealthy.
+	pollAgeFactor = 3
+
+	healthTimeout = 5 * time.Second
+)
+
+var errUnhealthy = errors.New("unhealthy")
+
+// HealthOptions configures the HTTP endpoints for supervision and control.
+type HealthOptions struct {
+	Addr string // Addr is the address to serve the endpoints on, empty disables them.
+	// Token has to be sent as bearer token to change anything through the endpoints. Without it,
+	// changes are only accepted from the loopback interface.
+	Token string
+}
+
+// HealthStatus is the state of a running instance, as served by the health endpoint.
+type HealthStatus struct {
+	Healthy     bool       `json:"healthy"`
+	Source      string     `json:"source"`              // "ok", "starting" or the last poll error
+	LastPoll    *time.Time `json:"last_poll,omitempty"` // time of the last successful poll
+	LastPollAge string     `json:"last_poll_age,omitempty"`
+	Storage     string     `json:"storage"` // "ok", "disabled" or the storage error
+	// DecodeErrors counts the fields of the source responses which couldn't be decoded, by name.
+	// They are left empty, so they don't make the instance unhealthy.
+	DecodeErrors map[string]int `json:"decode_errors,omitempty"`
+	// RejectedRecords counts the reports of aircraft which were rejected as implausible, by reason.
+	RejectedRecords map[string]int `json:"rejected_records,omitempty"`
+	// DisabledEnrichments are the enrichments whose datasets couldn't be loaded. They don't make the
+	// instance unhealthy either, airspottr runs without them.
+	DisabledEnrichments []string `json:"disabled_enrichments,omitempty"`
+}
+
+// Health reports whether the data source is reachable, how long ago aircraft were last polled
+// successfully and whether the sighting history can be written to.
+type Health struct {
+	request    *Request
+	dashboard  *Dashboard
+	maxPollAge time.Duration // maxPollAge is how long ago the last successful poll may be.
+}
+
+// NewHealth creates the health report of the given request and dashboard, which poll at most every
+// pollInterval.
+func NewHealth(request *Request, dashboard *Dashboard, pollInterval time.Duration) *Health {
+	return &Health{request: request, dashboard: dashboard, maxPollAge: pollAgeFactor * pollInterval}
+}
+
+// Status determines the health at the given time.
+// Until the first poll of the current sources succeeds, the instance counts as healthy for as long
+// as a poll may be old.
+func (h *Health) Status(now time.Time) HealthStatus {
+	status := HealthStatus{
+		Healthy:             true,
+		Source:              "ok",
+		LastPoll:            nil,
+		LastPollAge:         "",
+		Storage:             "ok",
+		DecodeErrors:        h.request.DecodeDiagnostics().Counts(),
+		RejectedRecords:     h.dashboard.RejectedRecords().Counts(),
+		DisabledEnrichments: nil,
+	}
+	for _, missing := range h.dashboard.MissingDatasets() {
+		status.DisabledEnrichments = append(status.DisabledEnrichments, missing.Enrichment)
+	}
+
+	lastPoll, pollErr := h.request.LastPoll()
+	if pollErr != nil {
+		status.Source = pollErr.Error()
+	}
+
+	if lastPoll.IsZero() {
+		if pollErr == nil {
+			status.Source = "starting"
+		}
+		if now.Sub(h.request.PollingSince()) > h.maxPollAge {
+			status.Healthy = false
+		}
+	} else {
+		age := now.Sub(lastPoll)
+		status.LastPoll = &lastPoll
+		status.LastPollAge = age.Round(time.Second).String()
+		if age > h.maxPollAge {
+			status.Healthy = false
+		}
+	}
+
+	enabled, storageErr := h.dashboard.CheckStorage()
+	switch {
+	case !enabled:
+		status.Storage = "disabled"
+	case storageErr != nil:
+		status.Storage = storageErr.Error()
+		status.Healthy = false
+	}
+
+	return status
+}
+
+// ServeHTTP writes the health status as JSON, with status code 200 if healthy and 503 otherwise.
+func (h *Health) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	status := h.Status(h.dashboard.Clock().Now())
+
+	writer.Header().Set("Content-Type", "application/json")
+	if status.Healthy {
+		writer.WriteHeader(http.StatusOK)
+	} else {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(writer).Encode(status)
+}
+
+// sourcesState lists the data sources, as served by the sources endpoint.
+type sourcesState struct {
+	Active    []string `json:"active"`
+	Available []string `json:"available"`
+}
+
+// sourcesRequest switches the data sources, as posted to the sources endpoint.
+type sourcesRequest struct {
+	Sources []string `json:"sources"`
+}
+
+// authorize tells whether the given request may change anything or read the sightings of others,
+// writing the error response if not. With a token, it has to be sent as bearer token, otherwise
+// the request has to come from the loopback interface.
+func authorize(writer http.ResponseWriter, req *http.Request, token string) bool {
+	if token != "" {
+		sent, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			writer.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(writer, "missing or wrong bearer token", http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	host, _, splitErr := net.SplitHostPort(req.RemoteAddr)
+	if ip := net.ParseIP(host); splitErr != nil || ip == nil || !ip.IsLoopback() {
+		http.Error(writer, "only requests from localhost are accepted without a token", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// sourcesHandler lists the active and available data sources on GET and switches to the posted
+// sources on POST, e.g. `{"sources": ["local"]}`, if authorized with the given token.
+func sourcesHandler(request *Request, token string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			if !authorize(writer, req, token) {
+				return
+			}
+			var body sourcesRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := request.SwitchSources(body.Sources); err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(writer, "only GET and POST are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(sourcesState{
+			Active:    request.ActiveSources(),
+			Available: request.AvailableSources(),
+		})
+	}
+}
+
+// ServeHealth serves the health and sources endpoints on the configured address in the
+// background, as well as the sync endpoint if sightings are shared.
+// Only failing to listen is returned, later errors are written to stderr.
+func ServeHealth(opts HealthOptions, health *Health, stderr *io.Writer) error {
+	listener, listenErr := net.Listen("tcp", opts.Addr)
+	if listenErr != nil {
+		return fmt.Errorf("ServeHealth: %w", listenErr)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(HealthPath, health)
+	mux.Handle(SourcesPath, sourcesHandler(health.request, opts.Token))
+	if store := health.dashboard.SharedStore(); store != nil {
+		mux.Handle(SyncPath, syncHandler(store, opts.Token))
+	}
+	server := &http.Server{ //nolint:exhaustruct // too large
+		Handler:           mux,
+		ReadHeaderTimeout: healthTimeout,
+	}
+
+	errOut := log.New(*stderr, "health ", log.LstdFlags)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			errOut.Println(fmt.Errorf("ServeHealth: %w", err))
+		}
+	}()
+	return nil
+}
+
+// CheckHealth queries the health endpoint of an instance running at the given address and
+// returns an error unless it is healthy.
+func CheckHealth(addr string) error {
+	host := addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+HealthPath, nil)
+	if reqErr != nil {
+		return fmt.Errorf("CheckHealth: invalid request: %w", reqErr)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, respErr := http.DefaultClient.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("CheckHealth: %w", respErr)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("CheckHealth: %w: %s", errUnhealthy, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// maxPollAge is the longest a poll may be ago with the default poll interval.
+const maxPollAge = pollAgeFactor * AircraftUpdateInterval
+
+func TestHealthStatus(t *testing.T) {
+	started := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	errPoll := errors.New("connection refused")
+	blocked := filepath.Join(t.TempDir(), "blocked")
+	if err := os.WriteFile(blocked, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		lastPoll    time.Time
+		lastPollErr error
+		historyPath string
+		healthy     bool
+		source      string
+		storage     string
+	}{
+		{
+			name:    "starting",
+			now:     started.Add(time.Minute),
+			healthy: true,
+			source:  "starting",
+			storage: "disabled",
+		},
+		{
+			name:    "never polled",
+			now:     started.Add(maxPollAge + time.Second),
+			healthy: false,
+			source:  "starting",
+			storage: "disabled",
+		},
+		{
+			name:     "recent poll",
+			now:      started.Add(time.Hour),
+			lastPoll: started.Add(time.Hour - AircraftUpdateInterval),
+			healthy:  true,
+			source:   "ok",
+			storage:  "disabled",
+		},
+		{
+			name:        "single failed poll",
+			now:         started.Add(time.Hour),
+			lastPoll:    started.Add(time.Hour - AircraftUpdateInterval),
+			lastPollErr: errPoll,
+			healthy:     true,
+			source:      errPoll.Error(),
+			storage:     "disabled",
+		},
+		{
+			name:        "stale poll",
+			now:         started.Add(time.Hour),
+			lastPoll:    started.Add(time.Hour - maxPollAge - time.Second),
+			lastPollErr: errPoll,
+			healthy:     false,
+			source:      errPoll.Error(),
+			storage:     "disabled",
+		},
+		{
+			name:        "writable history",
+			now:         started.Add(time.Hour),
+			lastPoll:    started.Add(time.Hour - AircraftUpdateInterval),
+			historyPath: filepath.Join(t.TempDir(), "history.jsonl"),
+			healthy:     true,
+			source:      "ok",
+			storage:     "ok",
+		},
+		{
+			name:        "missing history directory is created",
+			now:         started.Add(time.Hour),
+			lastPoll:    started.Add(time.Hour - AircraftUpdateInterval),
+			historyPath: filepath.Join(t.TempDir(), "missing", "history.jsonl"),
+			healthy:     true,
+			source:      "ok",
+			storage:     "ok",
+		},
+		{
+			name:        "history directory is a file",
+			now:         started.Add(time.Hour),
+			lastPoll:    started.Add(time.Hour - AircraftUpdateInterval),
+			historyPath: filepath.Join(blocked, "history.jsonl"),
+			healthy:     false,
+			source:      "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//nolint:exhaustruct // poll state only
+			request := &Request{
+				pollingSince: started,
+				lastPoll:     tt.lastPoll,
+				lastPollErr:  tt.lastPollErr,
+				decodeDiag:   NewDecodeDiagnostics(),
+			}
+			dashboard := &Dashboard{rejected: NewDecodeDiagnostics()} //nolint:exhaustruct // history only
+			if tt.historyPath != "" {
+				dashboard.history = NewHistory(tt.historyPath)
+			}
+			health := NewHealth(request, dashboard, AircraftUpdateInterval)
+
+			status := health.Status(tt.now)
+			if status.Healthy != tt.healthy {
+				t.Errorf("Status().Healthy = %v, expected %v", status.Healthy, tt.healthy)
+			}
+			if status.Source != tt.source {
+				t.Errorf("Status().Source = %q, expected %q", status.Source, tt.source)
+			}
+			if tt.storage != "" && status.Storage != tt.storage {
+				t.Errorf("Status().Storage = %q, expected %q", status.Storage, tt.storage)
+			}
+		})
+	}
+}
+
+func TestHealthServeHTTP(t *testing.T) {
+	// The health is judged by the clock of the dashboard, e.g. of a replay, rather than wall time.
+	clock := NewManualClock(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+	//nolint:exhaustruct // poll state only
+	request := &Request{pollingSince: clock.Now(), decodeDiag: NewDecodeDiagnostics()}
+	//nolint:exhaustruct // history disabled
+	dashboard := &Dashboard{clock: clock, rejected: NewDecodeDiagnostics()}
+	health := NewHealth(request, dashboard, AircraftUpdateInterval)
+
+	recorder := httptest.NewRecorder()
+	health.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("ServeHTTP() status = %d while starting, expected %d", recorder.Code, http.StatusOK)
+	}
+
+	clock.Advance(maxPollAge + time.Second)
+	recorder = httptest.NewRecorder()
+	health.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf(
+			"ServeHTTP() status = %d without poll, expected %d",
+			recorder.Code,
+			http.StatusServiceUnavailable)
+	}
+}
+
+func TestSourcesHandler(t *testing.T) {
+	var stderr io.Writer = io.Discard
+	opts := testRequestOptions(53.5, 9.9)
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	handler := sourcesHandler(request, "")
+
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"sources": ["adsblol"]}`)
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SourcesPath, body))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("POST from afar status = %d, expected %d", recorder.Code, http.StatusForbidden)
+	}
+
+	recorder = httptest.NewRecorder()
+	body = strings.NewReader(`{"sources": ["adsblol"]}`)
+	handler.ServeHTTP(recorder, localRequest(http.MethodPost, SourcesPath, body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, expected %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+
+	var state sourcesState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("POST returned invalid JSON: %v", err)
+	}
+	if len(state.Active) != 1 || state.Active[0] != SourceAdsbLol {
+		t.Errorf("active sources = %v, expected [%s]", state.Active, SourceAdsbLol)
+	}
+
+	recorder = httptest.NewRecorder()
+	body = strings.NewReader(`{"sources": ["local"]}`)
+	handler.ServeHTTP(recorder, localRequest(http.MethodPost, SourcesPath, body))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("POST of unconfigured source status = %d, expected %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSourcesHandlerToken(t *testing.T) {
+	var stderr io.Writer = io.Discard
+	opts := testRequestOptions(53.5, 9.9)
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	handler := sourcesHandler(request, "secret")
+
+	tests := []struct {
+		name          string
+		authorization string
+		local         bool
+		expected      int
+	}{
+		{name: "without token", authorization: "", local: true, expected: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer guess", local: false, expected: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "secret", local: false, expected: http.StatusUnauthorized},
+		{name: "right token", authorization: "Bearer secret", local: false, expected: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := strings.NewReader(`{"sources": ["adsblol"]}`)
+			req := httptest.NewRequest(http.MethodPost, SourcesPath, body)
+			if test.local {
+				req = localRequest(http.MethodPost, SourcesPath, body)
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != test.expected {
+				t.Errorf("POST status = %d, expected %d", recorder.Code, test.expected)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SourcesPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("GET status = %d without token, expected %d", recorder.Code, http.StatusOK)
+	}
+}
+
+// localRequest creates a request coming from the loopback interface.
+func localRequest(method string, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.RemoteAddr = "127.0.0.1:40000"
+	return req
+}
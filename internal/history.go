@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HistoryFileName is the name of the file in the data directory of the user which the sighting
+// history is kept in if no other path is given.
+const HistoryFileName = "history.jsonl"
+
+// HistoryEntry is the long-term record of a single sighting, i.e. one flight of an aircraft.
+type HistoryEntry struct {
+	Time         time.Time `json:"time"`
+	Hex          string    `json:"hex"`
+	Flight       string    `json:"flight"`
+	Registration string    `json:"registration"`
+	Type         string    `json:"type"`
+	Operator     string    `json:"operator"`
+	Country      string    `json:"country"`
+}
+
+// History persists sightings as newline-delimited JSON, one entry per line.
+type History struct {
+	path      string
+	mutex     sync.Mutex
+	appendErr error // appendErr is the error of the most recent append, nil if it succeeded.
+}
+
+// NewHistory creates a History which reads from and appends to the file at the given path.
+func NewHistory(path string) *History {
+	return &History{path: path, mutex: sync.Mutex{}, appendErr: nil}
+}
+
+// Append adds the given entries to the end of the history file, creating it if necessary.
+func (h *History) Append(entries []HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := h.append(entries)
+	h.mutex.Lock()
+	h.appendErr = err
+	h.mutex.Unlock()
+	return err
+}
+
+// Check tells whether the history can be written to: it fails if the most recent append failed,
+// or if the history file, or the directory to create it in, isn't writable. The directory is
+// created if it is missing, as the first append would.
+func (h *History) Check() error {
+	h.mutex.Lock()
+	appendErr := h.appendErr
+	h.mutex.Unlock()
+	if appendErr != nil {
+		return appendErr
+	}
+
+	file, openErr := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if errors.Is(openErr, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(h.path), dataStoreDirPerm); err != nil {
+			return fmt.Errorf("History.Check: %w", err)
+		}
+		return nil
+	}
+	if openErr != nil {
+		return fmt.Errorf("History.Check: %w", openErr)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("History.Check: %w", err)
+	}
+	return nil
+}
+
+func (h *History) append(entries []HistoryEntry) error {
+	file, openErr := openAppend(h.path)
+	if openErr != nil {
+		return fmt.Errorf("History.Append: failed to open %s: %w", h.path, openErr)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		entry.Time = entry.Time.UTC()
+		if err := encoder.Encode(entry); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("History.Append: failed to encode entry: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("History.Append: failed to write %s: %w", h.path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("History.Append: failed to close %s: %w", h.path, err)
+	}
+
+	return nil
+}
+
+// Load reads all entries of the history file.
+// A missing history file is not an error, it simply means that nothing has been recorded yet.
+func (h *History) Load() ([]HistoryEntry, error) {
+	file, openErr := os.Open(h.path)
+	if errors.Is(openErr, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if openErr != nil {
+		return nil, fmt.Errorf("History.Load: failed to open %s: %w", h.path, openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries []HistoryEntry
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var entry HistoryEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return entries, fmt.Errorf("History.Load: failed to decode entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Formats the sighting history can be exported in.
+const (
+	HistoryFormatCSV  = "csv"   // HistoryFormatCSV is a CSV table with a header row.
+	HistoryFormatJSON = "jsonl" // HistoryFormatJSON is newline-delimited JSON, as in history files.
+)
+
+var errInvalidHistoryFormat = errors.New("invalid history format")
+
+// WriteHistory writes the entries in the given format, one HistoryFormatXxx.
+func WriteHistory(out io.Writer, format string, entries []HistoryEntry) error {
+	switch format {
+	case HistoryFormatCSV:
+		return writeHistoryCSV(out, entries)
+	case HistoryFormatJSON:
+		encoder := json.NewEncoder(out)
+		for _, entry := range entries {
+			entry.Time = entry.Time.UTC()
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("WriteHistory: %w", err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("WriteHistory: %w: %q, expected %s or %s",
+			errInvalidHistoryFormat, format, HistoryFormatCSV, HistoryFormatJSON)
+	}
+}
+
+func writeHistoryCSV(out io.Writer, entries []HistoryEntry) error {
+	rows := make([][]string, 0, len(entries)+1)
+	rows = append(rows, []string{"time", "hex", "flight", "registration", "type", "operator", "country"})
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			entry.Time.UTC().Format(time.RFC3339),
+			entry.Hex,
+			entry.Flight,
+			entry.Registration,
+			entry.Type,
+			entry.Operator,
+			entry.Country,
+		})
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("WriteHistory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteHistory(t *testing.T) {
+	seen := time.Date(2026, time.October, 18, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	entries := []HistoryEntry{sharedEntry(seen, "A320", "Lufthansa", "Germany")}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: HistoryFormatCSV,
+			expected: "time,hex,flight,registration,type,operator,country\n" +
+				"2026-10-18T12:00:00Z,3c6444,DLH123,D-AIBD,A320,Lufthansa,Germany\n",
+		},
+		{
+			format: HistoryFormatJSON,
+			expected: `{"time":"2026-10-18T12:00:00Z","hex":"3c6444","flight":"DLH123",` +
+				`"registration":"D-AIBD","type":"A320","operator":"Lufthansa","country":"Germany"}` + "\n",
+		},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := WriteHistory(&out, test.format, entries); err != nil {
+			t.Fatalf("WriteHistory(%s) error = %v", test.format, err)
+		}
+		if out.String() != test.expected {
+			t.Errorf("WriteHistory(%s) = %q, expected %q", test.format, out.String(), test.expected)
+		}
+	}
+
+	if err := WriteHistory(&bytes.Buffer{}, "xml", entries); err == nil {
+		t.Error("WriteHistory(xml) succeeded, expected an error")
+	}
+}
@@ -0,0 +1,259 @@
+package i18n
+
+// german translates the English texts into German, grouped by where they are shown.
+var german = map[string]string{ //nolint:gochecknoglobals // constant lookup
+	// notifications
+	"Alert: %s":                                   "Alarm: %s",
+	"alert %s: %s":                                "Alarm %s: %s",
+	"First %s ever!":                              "Zum ersten Mal %s!",
+	"first %s ever: %s":                           "zum ersten Mal %s: %s",
+	"FIRST EVER %s: %s":                           "ZUM ERSTEN MAL %s: %s",
+	"Noted aircraft":                              "Notiertes Flugzeug",
+	"Favourite aircraft":                          "Lieblingsflugzeug",
+	"Watchlist aircraft":                          "Flugzeug der Beobachtungsliste",
+	"note %q: %s":                                 "Notiz %q: %s",
+	"Rare Aircraft Type Spotted":                  "Seltener Flugzeugtyp gesichtet",
+	"found rare type %s":                          "seltener Typ gefunden: %s",
+	"Rare Operator Spotted":                       "Seltener Betreiber gesichtet",
+	"%s flying %s (%s)\n%s":                       "%s fliegt %s (%s)\n%s",
+	"found rare operator: %s":                     "seltener Betreiber gefunden: %s",
+	"Rare Aircraft Country Spotted":               "Seltenes Herkunftsland gesichtet",
+	"%s-based %s (%s)\n%s":                        "%[2]s (%[3]s) aus %[1]s\n%[4]s",
+	"found rare country: %s":                      "seltenes Land gefunden: %s",
+	"Rare Type & Operator Spotted":                "Seltener Typ & Betreiber gesichtet",
+	"%s (%s) operated by\n%s\n%s":                 "%s (%s) betrieben von\n%s\n%s",
+	"found rare type and operator: %s run by %s":  "seltener Typ und Betreiber gefunden: %s von %s",
+	"Rare Type & Country Spotted":                 "Seltener Typ & Land gesichtet",
+	"%s (%s) registered in\n%s\n%s":               "%s (%s) registriert in\n%s\n%s",
+	"found rare type and country: %s -> %s":       "seltener Typ und Land gefunden: %s -> %s",
+	"Rare Operator & Country Spotted":             "Seltener Betreiber & Land gesichtet",
+	"%s\nflying aircraft registered in\n%s\n%s":   "%s\nfliegt Flugzeuge registriert in\n%s\n%s",
+	"found rare operator and country: %s -> %s":   "seltener Betreiber und Land gefunden: %s -> %s",
+	"TRIFECTA Spotted!":                           "TRIFECTA gesichtet!",
+	"%s (%s),\nrun by %s,\nregistered in\n%s\n%s": "%s (%s),\nbetrieben von %s,\nregistriert in\n%s\n%s",
+	"found the TRIFECTA: %s -> %s -> %s":          "TRIFECTA gefunden: %s -> %s -> %s",
+	"Callsign changed":                            "Rufzeichen geändert",
+	"Squawk changed":                              "Squawk geändert",
+	"Emergency squawk":                            "Notfall-Squawk",
+	"%s is now %s":                                "%s ist jetzt %s",
+	"%s squawks %s":                               "%s squawkt %s",
+	"%s squawks %s instead of %s":                 "%s squawkt %s statt %s",
+	"%s changed: %s: %s":                          "%s geändert: %s: %s",
+	"Arrival at %s":                               "Ankunft in %s",
+	"%s arrived at %s":                            "%s ist in %s angekommen",
+	"Departure from %s":                           "Abflug aus %s",
+	"%s left %s":                                  "%s hat %s verlassen",
+	"New peak of aircraft":                        "Neuer Höchststand an Flugzeugen",
+	"%d on %s %s":                                 "%d am %s %s",
+	"%d aircraft visible at once\nprevious peak: %s": "%d Flugzeuge gleichzeitig " +
+		"sichtbar\nbisheriger Höchststand: %s",
+	"NEW PEAK: %d aircraft visible at once, previous peak %s": "NEUER HÖCHSTSTAND: %d " +
+		"Flugzeuge gleichzeitig sichtbar, bisher %s",
+	"Feed stalled":                                  "Feed ausgefallen",
+	"unknown error":                                 "unbekannter Fehler",
+	"No aircraft data for %s\n%s":                   "Keine Flugzeugdaten seit %s\n%s",
+	"FEED STALLED: no aircraft data for %s: %s":     "FEED AUSGEFALLEN: keine Flugzeugdaten seit %s: %s",
+	"Feed recovered":                                "Feed wieder da",
+	"Aircraft data is back after %s":                "Flugzeugdaten sind nach %s wieder da",
+	"feed recovered after %s without aircraft data": "Feed nach %s ohne Flugzeugdaten wieder da",
+	"Supersonic aircraft":                           "Überschallflugzeug",
+	"%s %s (%s) at Mach %.2f\n%s":                   "%s %s (%s) mit Mach %.2f\n%s",
+	"supersonic at Mach %.2f: %s":                   "Überschall mit Mach %.2f: %s",
+	"Notable aircraft":                              "Bemerkenswertes Flugzeug",
+	"notable %s: %s":                                "bemerkenswert %s: %s",
+	"Circling aircraft":                             "Kreisendes Flugzeug",
+	"%s %s (%s) circling for %.0f min\n%s":          "%s %s (%s) kreist seit %.0f min\n%s",
+	"circling %.1f times: %s":                       "kreist %.1f Mal: %s",
+	"Go-around":                                     "Durchstarten",
+	"%s %s (%s) went around at %s\n%s":              "%s %s (%s) ist in %s durchgestartet\n%s",
+	"go-around at %s from %.0f ft: %s":              "Durchstarten in %s aus %.0f ft: %s",
+	"Followed aircraft in sight":                    "Verfolgtes Flugzeug in Sicht",
+	"%s in sight at %s ft":                          "%s in Sicht auf %s ft",
+	"Followed aircraft out of sight":                "Verfolgtes Flugzeug außer Sicht",
+	"%s out of sight, last at %s ft":                "%s außer Sicht, zuletzt auf %s ft",
+	"Followed aircraft took off":                    "Verfolgtes Flugzeug gestartet",
+	"%s took off":                                   "%s ist gestartet",
+	"Followed aircraft landed":                      "Verfolgtes Flugzeug gelandet",
+	"%s landed":                                     "%s ist gelandet",
+	"Followed aircraft climbing":                    "Verfolgtes Flugzeug steigt",
+	"%s climbing from %s ft":                        "%s steigt von %s ft",
+	"Followed aircraft descending":                  "Verfolgtes Flugzeug sinkt",
+	"%s descending from %s ft":                      "%s sinkt von %s ft",
+	"Followed aircraft level":                       "Verfolgtes Flugzeug im Horizontalflug",
+	"%s levelled off at %s ft":                      "%s fliegt jetzt auf %s ft",
+	"Followed aircraft changed speed":               "Verfolgtes Flugzeug hat die Geschwindigkeit geändert",
+	"%s at %.0f kt instead of %.0f kt":              "%s mit %.0f kt statt %.0f kt",
+	"follow: %s: %s":                                "verfolgt: %s: %s",
+	"Achievement unlocked: %s":                      "Erfolg freigeschaltet: %s",
+	"achievement %s: %s":                            "Erfolg %s: %s",
+
+	// achievements
+	"First military catch":                         "Erster Militärfang",
+	"Spot a military aircraft":                     "Sichte ein Militärflugzeug",
+	"Type collector":                               "Typensammler",
+	"Spot 100 different types":                     "Sichte 100 verschiedene Typen",
+	"A320 family complete":                         "A320-Familie komplett",
+	"Spot every variant of the Airbus A320 family": "Sichte jede Variante der Airbus-A320-Familie",
+	"Globetrotter":                                 "Weltenbummler",
+	"Spot aircraft of 50 countries":                "Sichte Flugzeuge aus 50 Ländern",
+
+	// whereabouts of aircraft
+	"heading your way": "kommt auf dich zu",
+	"passing by":       "fliegt vorbei",
+	"moving away":      "entfernt sich",
+	"NNE":              "NNO",
+	"NE":               "NO",
+	"ENE":              "ONO",
+	"E":                "O",
+	"ESE":              "OSO",
+	"SE":               "SO",
+	"SSE":              "SSO",
+
+	// properties, periods and changes
+	"type":          "Typ",
+	"operator":      "Betreiber",
+	"country":       "Land",
+	"aircraft":      "Flugzeug",
+	"session":       "Sitzung",
+	"today":         "heute",
+	"all-time":      "aller Zeiten",
+	"squawk":        "Squawk",
+	"callsign":      "Rufzeichen",
+	"arrival":       "Ankunft",
+	"departure":     "Abflug",
+	"least to most": "von selten bis häufig",
+	"on ground":     "am Boden",
+	"climbing":      "steigt",
+	"level":         "im Horizontalflug",
+	"descending":    "sinkt",
+	"most to least": "von häufig bis selten",
+
+	// notification categories
+	"Rare type":                "Seltener Typ",
+	"Rare operator":            "Seltener Betreiber",
+	"Rare country":             "Seltenes Land",
+	"Watchlist and favourites": "Beobachtungsliste und Favoriten",
+	"Record broken":            "Rekord gebrochen",
+	"Supersonic":               "Überschall",
+	"Followed aircraft":        "Verfolgtes Flugzeug",
+	"Achievements":             "Erfolge",
+
+	// summary and weekly report
+	"=== Summary ===":                        "=== Zusammenfassung ===",
+	"=== End Summary ===":                    "=== Ende der Zusammenfassung ===",
+	"Rarity scorer: %s (%s)\n":               "Seltenheitsbewertung: %s (%s)\n",
+	"Seen-counts decay with half life %s\n":  "Sichtungszahlen verfallen mit Halbwertszeit %s\n",
+	"Scorer comparison: %s\n":                "Vergleich der Bewertungen: %s\n",
+	"Rarity baseline: %s\n":                  "Grundlage der Seltenheit: %s\n",
+	"Rarity from %s common %s%s\n":           "Seltenheit nach %[2]s, %[1]s%[3]s\n",
+	"%6d others beyond the cap\n":            "%6d weitere jenseits der Obergrenze\n",
+	", top %d":                               ", die ersten %d",
+	"Fastest aircraft (%s): %s\n":            "Schnellstes Flugzeug (%s): %s\n",
+	"Fastest by Mach (%s): %s\n":             "Schnellstes nach Mach (%s): %s\n",
+	"Highest aircraft (%s): %s\n":            "Höchstes Flugzeug (%s): %s\n",
+	"Farthest aircraft (%s): %s\n":           "Entferntestes Flugzeug (%s): %s\n",
+	"Discoveries last %d days: %s\n":         "Entdeckungen der letzten %d Tage: %s\n",
+	"Explored so far: %s\n":                  "Bisher erkundet: %s\n",
+	"Achievements unlocked: %d of %d\n":      "Erfolge freigeschaltet: %d von %d\n",
+	"Go-arounds: %d today, session: %s\n":    "Durchstarts: %d heute, Sitzung: %s\n",
+	"Altitude bands (now, share):":           "Höhenbänder (jetzt, Anteil):",
+	"Received by (now, share): %s\n":         "Empfangen über (jetzt, Anteil): %s\n",
+	"Winds aloft (from, speed, reports):":    "Höhenwinde (aus, Stärke, Meldungen):",
+	"Temperatures aloft (OAT, reports):":     "Temperaturen in der Höhe (OAT, Meldungen):",
+	"Traffic last 24 hours: %s\n":            "Verkehr der letzten 24 Stunden: %s\n",
+	"Traffic last 14 days:  %s\n":            "Verkehr der letzten 14 Tage:    %s\n",
+	"Busiest hours:":                         "Verkehrsreichste Stunden:",
+	"  %02d:00 - %.1f aircraft on average\n": "  %02d:00 - %.1f Flugzeuge im Schnitt\n",
+	"Peak: %d aircraft at %s, all-time %d on %s %s\n": "Höchststand: %d Flugzeuge um %s, " +
+		"aller Zeiten %d am %s %s\n",
+	"Distinct aircraft today: %d\n":    "Verschiedene Flugzeuge heute: %d\n",
+	"Data sources:":                    "Datenquellen:",
+	"=== Weekly Report ===":            "=== Wochenbericht ===",
+	"=== End Weekly Report ===":        "=== Ende des Wochenberichts ===",
+	"Sightings this week: %d\n":        "Sichtungen dieser Woche: %d\n",
+	"Distinct types: %d\n":             "Verschiedene Typen: %d\n",
+	"Distinct operators: %d\n":         "Verschiedene Betreiber: %d\n",
+	"Distinct countries: %d\n":         "Verschiedene Länder: %d\n",
+	"No seasonal patterns found (yet)": "(Noch) keine saisonalen Muster gefunden",
+	"Seasonal patterns:":               "Saisonale Muster:",
+
+	// TUI
+	"Type":                              "Typ",
+	"Type family":                       "Typfamilie",
+	"Operator":                          "Betreiber",
+	"Operator group":                    "Betreibergruppe",
+	"Country":                           "Land",
+	"Count":                             "Anzahl",
+	"Share":                             "Anteil",
+	"Per h":                             "Pro h",
+	"First seen":                        "Zuerst",
+	"Compared to %s:":                   "Verglichen mit %s:",
+	"Rarity:":                           "Seltenheit:",
+	"Traffic 24h:":                      "Verkehr 24h:",
+	"Busiest:":                          "Stoßzeiten:",
+	"Altitudes (now, share):":           "Höhen (jetzt, Anteil):",
+	"Received by (now, share):":         "Empfangen über (jetzt, Anteil):",
+	"Discoveries 30d:":                  "Entdeckungen 30T:",
+	"Decode errors:":                    "Dekodierfehler:",
+	"Rejected:":                         "Verworfen:",
+	"Disabled:":                         "Deaktiviert:",
+	"Reload failed:":                    "Neu laden fehlgeschlagen:",
+	"Reloaded:":                         "Neu geladen:",
+	"Snapshot:":                         "Schnappschuss:",
+	"Sources:":                          "Quellen:",
+	"Flight":                            "Flug",
+	"Previously":                        "Zuvor",
+	"Registration":                      "Kennzeichen",
+	"Hex":                               "Hex",
+	"Description":                       "Beschreibung",
+	"Specs":                             "Daten",
+	"3-view":                            "Dreiseitenansicht",
+	"Origin":                            "Abflugort",
+	"Destination":                       "Ziel",
+	"Distance":                          "Entfernung",
+	"Source":                            "Quelle",
+	"Accuracy":                          "Genauigkeit",
+	"Altitude":                          "Höhe",
+	"Speed":                             "Geschwindigkeit",
+	"Airspeed":                          "Fahrt",
+	"Heading":                           "Steuerkurs",
+	"Squawk":                            "Squawk",
+	"Photo":                             "Foto",
+	"Note":                              "Notiz",
+	"Watchlist":                         "Beobachtet",
+	"Favourite":                         "Favorit",
+	"Notable":                           "Besonders",
+	"Profile":                           "Höhenprofil",
+	"out of sight":                      "außer Sicht",
+	"Following %s (T to go back)":       "Verfolge %s (T zurück)",
+	"Waiting for %s to come into sight": "Warte, bis %s in Sicht kommt",
+	"locked":                            "gesperrt",
+	"unlocked %s":                       "freigeschaltet %s",
+	"Achievements, %d of %d unlocked (a to go back)": "Erfolge, %d von %d freigeschaltet " +
+		"(a zurück)",
+	"Errors, newest first, %d kept (E to go back, c to clear)": "Fehler, neueste zuerst, " +
+		"%d behalten (E zurück, c leeren)",
+	"Favourites, %d in sight (F to go back)":    "Favoriten, %d in Sicht (F zurück)",
+	"Log, %d lines (L to go back, e to export)": "Log, %d Zeilen (L zurück, e exportieren)",
+	"Log, %d lines, %d older dropped (L to go back, e to export)": "Log, %d Zeilen, %d ältere " +
+		"verworfen (L zurück, e exportieren)",
+	"Notifications, newest first, %d kept (A to go back)": "Benachrichtigungen, " +
+		"neueste zuerst, %d behalten (A zurück)",
+	"Desktop notifications for this session (enter to switch, N to go back)": "Desktop-" +
+		"Benachrichtigungen dieser Sitzung (Enter umschalten, N zurück)",
+	"airspottr is starting up":         "airspottr startet",
+	"config parsed (%s)":               "Konfiguration gelesen (%s)",
+	"loading datasets":                 "lade Datensätze",
+	"loading datasets %d/%d (%s)":      "lade Datensätze %d/%d (%s)",
+	"first aircraft request in flight": "erste Abfrage der Flugzeuge läuft",
+	"Startup failed: %s":               "Start fehlgeschlagen: %s",
+	"Press q to quit.":                 "q drücken zum Beenden.",
+	"%s doesn't exist. Datasets are read from ./data, so start airspottr from the directory which " +
+		"contains it, and check the paths given on the command line.": "%s existiert nicht. Datensätze " +
+		"werden aus ./data gelesen, also starte airspottr aus dem Verzeichnis, das es enthält, und " +
+		"prüfe die Pfade auf der Kommandozeile.",
+	"%s can't be read, check its permissions.": "%s kann nicht gelesen werden, prüfe die Berechtigungen.",
+	"Check the command line options (airspottr --help) and the log, which is printed on " +
+		"quitting.": "Prüfe " +
+		"die Optionen der Kommandozeile (airspottr --help) und das Log, das beim Beenden ausgegeben wird.",
+}
@@ -0,0 +1,59 @@
+// Package i18n translates the user-facing texts of airspottr.
+//
+// Texts are written in English in the code and looked up in the bundle of the chosen language by
+// their English text, so that a missing translation falls back to English rather than to a key.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Language is a language the texts are shown in, by its ISO 639-1 code.
+// The zero value shows them in English.
+type Language string
+
+const (
+	English Language = "en"
+	German  Language = "de"
+)
+
+// Languages lists all languages there are bundles for.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var Languages = []Language{English, German}
+
+var errUnknownLanguage = errors.New("unknown language")
+
+// bundles map the English texts to their translation, by language. English needs no bundle.
+var bundles = map[Language]map[string]string{ //nolint:gochecknoglobals // constant lookup
+	German: german,
+}
+
+// ParseLanguage returns the language of the given code, e.g. "de", or English if it is empty.
+func ParseLanguage(code string) (Language, error) {
+	if code == "" {
+		return English, nil
+	}
+	lang := Language(strings.ToLower(code))
+	if !slices.Contains(Languages, lang) {
+		return English, fmt.Errorf(
+			"ParseLanguage: %w %q, expected one of %v", errUnknownLanguage, code, Languages)
+	}
+	return lang, nil
+}
+
+// T translates the English text, or returns it as it is if there is no translation.
+func (lang Language) T(text string) string {
+	if translated, ok := bundles[lang][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Sprintf translates the English format, like T, and formats it with the given arguments.
+func (lang Language) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(lang.T(format), args...)
+}
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    Language
+		wantErr bool
+	}{
+		{code: "", want: English, wantErr: false},
+		{code: "en", want: English, wantErr: false},
+		{code: "de", want: German, wantErr: false},
+		{code: "DE", want: German, wantErr: false},
+		{code: "fr", want: English, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseLanguage(test.code)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseLanguage(%q) error = %v, wantErr %v", test.code, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("ParseLanguage(%q) = %q, want %q", test.code, got, test.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		lang Language
+		text string
+		want string
+	}{
+		{lang: English, text: "Feed stalled", want: "Feed stalled"},
+		{lang: German, text: "Feed stalled", want: "Feed ausgefallen"},
+		{lang: German, text: "not translated", want: "not translated"},
+		{lang: "", text: "Feed stalled", want: "Feed stalled"},
+	}
+	for _, test := range tests {
+		if got := test.lang.T(test.text); got != test.want {
+			t.Errorf("%q.T(%q) = %q, want %q", test.lang, test.text, got, test.want)
+		}
+	}
+}
+
+func TestSprintfReorders(t *testing.T) {
+	got := German.Sprintf("Rarity from %s common %s%s\n", "most to least", "type", ", top 5")
+	want := "Seltenheit nach type, most to least, top 5\n"
+	if got != want {
+		t.Errorf("Sprintf() = %q, want %q", got, want)
+	}
+}
+
+// formatVerb matches a formatting verb, with an optional argument index.
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`) //nolint:gochecknoglobals // test
+
+// verbs returns the formatting verbs of the format without their argument index, sorted, since a
+// translation may reorder them.
+func verbs(format string) []string {
+	var found []string
+	for _, match := range formatVerb.FindAllStringSubmatch(format, -1) {
+		if match[0] == "%%" {
+			continue
+		}
+		verb := match[0]
+		if match[1] != "" {
+			verb = "%" + verb[len(match[1])+1:]
+		}
+		found = append(found, verb)
+	}
+	slices.Sort(found)
+	return found
+}
+
+func TestTranslationsKeepVerbs(t *testing.T) {
+	for lang, bundle := range bundles {
+		for text, translated := range bundle {
+			if !slices.Equal(verbs(text), verbs(translated)) {
+				t.Errorf("%s: %q has verbs %v, but its translation %q has %v",
+					lang, text, verbs(text), translated, verbs(translated))
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// ChangeSquawk and ChangeCallsign are what changed about an aircraft in an IdentityChange.
+	ChangeSquawk   = "squawk"
+	ChangeCallsign = "callsign"
+)
+
+// midFlightGap is how long an aircraft may be out of sight for a new callsign to be a change
+// mid-flight. After a longer gap, e.g. a turnaround, the new callsign is the next flight.
+const midFlightGap = 10 * time.Minute
+
+// emergencySquawks are the squawks for hijacking, radio failure and general emergencies.
+var emergencySquawks = []string{"7500", "7600", "7700"} //nolint:gochecknoglobals // constant lookup
+
+// IdentityChange is a change of the squawk or the callsign of a tracked aircraft mid-flight, which
+// often means something is going on, e.g. an emergency or a flight being handed to another
+// controller.
+type IdentityChange struct {
+	Field    string // Field is ChangeSquawk or ChangeCallsign.
+	From     string
+	To       string
+	Sighting *AircraftSighting
+}
+
+// IsEmergency tells whether the aircraft changed to an emergency squawk.
+func (change IdentityChange) IsEmergency() bool {
+	return change.Field == ChangeSquawk && slices.Contains(emergencySquawks, change.To)
+}
+
+// detectSquawkChange updates the squawk of the sighting and reports if it changed. Records
+// without a squawk leave the last known one, so that a squawk missing from a single update isn't
+// mistaken for a change. Aircraft which squawk an emergency from the start are reported as well,
+// with an empty previous squawk.
+func detectSquawkChange(sighting *AircraftSighting, squawk string) (IdentityChange, bool) {
+	if squawk == "" {
+		return IdentityChange{}, false
+	}
+	previous := sighting.squawk
+	sighting.squawk = squawk
+	change := IdentityChange{Field: ChangeSquawk, From: previous, To: squawk, Sighting: sighting}
+	if previous == squawk || (previous == "" && !change.IsEmergency()) {
+		return IdentityChange{}, false
+	}
+	return change, true
+}
+
+// identityChangeEvent describes the change, e.g. "DLH400 squawks 7700 instead of 1000".
+func identityChangeEvent(lang i18n.Language, change IdentityChange, now time.Time) Event {
+	sighting := change.Sighting
+	title := lang.T("Callsign changed")
+	severity := SeverityInfo
+	description := lang.Sprintf("%s is now %s", change.From, change.To)
+	if change.Field == ChangeSquawk {
+		title = lang.T("Squawk changed")
+		if change.IsEmergency() {
+			title = lang.T("Emergency squawk")
+			severity = SeverityCritical
+		}
+		description = lang.Sprintf("%s squawks %s", sighting.lastFlightNo, change.To)
+		if change.From != "" {
+			description = lang.Sprintf("%s squawks %s instead of %s", sighting.lastFlightNo, change.To, change.From)
+		}
+	}
+	return Event{
+		Kind:     EventKindChange,
+		Severity: severity,
+		Title:    title,
+		Body: fmt.Sprintf(
+			"%s\n%s (%s)\n%s",
+			description, sighting.typeDesc, sighting.registration, sighting.whereabouts(lang)),
+		Summary:  lang.Sprintf("%s changed: %s: %s", lang.T(change.Field), description, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   &change,
+		Movement: nil,
+	}
+}
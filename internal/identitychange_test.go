@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestIdentityChanges(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	updates := []struct {
+		flight  string
+		squawk  string
+		changes []IdentityChange
+	}{
+		{"DLH400", "1000", nil},
+		{"DLH400", "", nil}, // a missing squawk is no change
+		{"DLH400", "7700", []IdentityChange{{Field: ChangeSquawk, From: "1000", To: "7700", Sighting: nil}}},
+		{"DLH401", "7700", []IdentityChange{{Field: ChangeCallsign, From: "DLH400", To: "DLH401", Sighting: nil}}},
+	}
+	emergency := []IdentityChange{{Field: ChangeSquawk, From: "", To: "7600", Sighting: nil}}
+	for idx, update := range updates {
+		dashboard.ProcessAircraftRecords([]AircraftRecord{
+			{Hex: "3c6444", Flight: update.flight, Squawk: update.squawk}, //nolint:exhaustruct // identity only
+		})
+		checkIdentityChanges(t, idx, dashboard.IdentityChanges, update.changes)
+	}
+
+	// Aircraft which squawk an emergency when they first show up are reported as well.
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6445", Flight: "BAW12", Squawk: "7600"}, //nolint:exhaustruct // identity only
+	})
+	checkIdentityChanges(t, len(updates), dashboard.IdentityChanges, emergency)
+}
+
+func TestIdentityChangeTurnaround(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	clock := NewManualClock(time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC))
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio", Clock: clock}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Squawk: "1000"}, //nolint:exhaustruct // identity only
+	})
+	// After a turnaround, the next flight has a callsign and squawk of its own, which changed nothing.
+	clock.Advance(time.Hour)
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH401", Squawk: "2000"}, //nolint:exhaustruct // identity only
+	})
+	checkIdentityChanges(t, 1, dashboard.IdentityChanges, nil)
+	if len(dashboard.NewAircraft) != 1 {
+		t.Errorf("NewAircraft = %d, expected the next flight", len(dashboard.NewAircraft))
+	}
+
+	// Its squawk changes mid-flight as usual.
+	clock.Advance(time.Minute)
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH401", Squawk: "3000"}, //nolint:exhaustruct // identity only
+	})
+	checkIdentityChanges(t, 2, dashboard.IdentityChanges,
+		[]IdentityChange{{Field: ChangeSquawk, From: "2000", To: "3000", Sighting: nil}})
+}
+
+func checkIdentityChanges(t *testing.T, update int, changes []IdentityChange, expected []IdentityChange) {
+	t.Helper()
+	if len(changes) != len(expected) {
+		t.Fatalf("update %d: IdentityChanges = %+v, expected %+v", update, changes, expected)
+	}
+	for idx, want := range expected {
+		got := changes[idx]
+		if got.Field != want.Field || got.From != want.From || got.To != want.To {
+			t.Errorf("update %d: IdentityChanges[%d] = %+v, expected %+v", update, idx, got, want)
+		}
+	}
+}
+
+func TestIdentityChangeEvent(t *testing.T) {
+	sighting := &AircraftSighting{lastFlightNo: "DLH400"} //nolint:exhaustruct // flight only
+	change := IdentityChange{Field: ChangeSquawk, From: "1000", To: "7700", Sighting: sighting}
+	if !change.IsEmergency() {
+		t.Error("IsEmergency() = false for squawk 7700")
+	}
+
+	event := identityChangeEvent(i18n.English, change, time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC))
+	if event.Title != "Emergency squawk" {
+		t.Errorf("identityChangeEvent() title = %q", event.Title)
+	}
+
+	content, err := json.Marshal(newEventPayload(event))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload EventPayload
+	if err := json.Unmarshal(content, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Event != EventKindChange || payload.Change != ChangeSquawk ||
+		payload.From != "1000" || payload.To != "7700" {
+		t.Errorf("newEventPayload() = %+v, expected the squawk change", payload)
+	}
+}
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"cmp"
+	"maps"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// evictionSlack is the percentage of a cap which is freed at once when it is exceeded, so that
+	// the eviction doesn't run again on the next poll.
+	evictionSlack = 10
+
+	// heapObjectsMetric is the size of the live and not yet collected objects on the heap.
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+)
+
+// Limits cap how much the dashboard keeps in memory, e.g. for ticker runs of several weeks on
+// small devices. Zero leaves the respective cap out.
+type Limits struct {
+	// MaxSightings is how many aircraft are kept. Beyond, the least recently seen are forgotten
+	// and count as new when they are seen again.
+	MaxSightings int
+	// MaxCounterKeys is how many types, operators and countries are counted each. Beyond, the least
+	// seen are only added up as others and count as never seen when they are seen again.
+	MaxCounterKeys int
+	// MaxMemory is how large the heap may grow in [bytes]. Beyond, half of the aircraft and of the
+	// counted properties are let go, however many the other caps allow.
+	MaxMemory uint64
+}
+
+// keptBelow returns how many entries are kept once the cap is exceeded.
+func keptBelow(limit int) int {
+	return limit - max(1, limit*evictionSlack/100) //nolint:mnd // percent
+}
+
+// squeezed returns the cap of a map of the given size, which is half of its size if memory is
+// short and that is less than the cap.
+func squeezed(limit int, size int, short bool) int {
+	if short && (limit == 0 || size/2 < limit) {
+		return max(1, size/2)
+	}
+	return limit
+}
+
+// heapBytes returns the size of the objects on the heap.
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric, Value: metrics.Value{}}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// enforceLimits forgets the least recently seen aircraft and adds up the least seen types,
+// operators and countries as others, if there are more than the limits allow.
+// Requires the dataset mutex.
+func (db *Dashboard) enforceLimits(now time.Time) {
+	short := db.limits.MaxMemory > 0 && heapBytes() > db.limits.MaxMemory
+	if short {
+		db.errOut.Printf("enforceLimits: heap beyond %d MB, letting go of half of the sightings\n",
+			db.limits.MaxMemory>>20) //nolint:mnd // MB
+	}
+
+	maxSightings := squeezed(db.limits.MaxSightings, len(db.aircraftSightings), short)
+	if maxSightings > 0 && len(db.aircraftSightings) > maxSightings {
+		evicted := db.evictSightings(keptBelow(maxSightings))
+		db.errOut.Printf("enforceLimits: forgot the %d least recently seen aircraft\n", evicted)
+	}
+	if maxAirframes := squeezed(db.limits.MaxSightings, db.airframes.Len(), short); maxAirframes > 0 {
+		db.airframes.Evict(maxAirframes)
+	}
+
+	counters := map[string]map[string]int{
+		"type":     db.SeenTypeCount,
+		"operator": db.SeenOperatorCount,
+		"country":  db.SeenCountryCount,
+	}
+	for category, counts := range counters {
+		if maxKeys := squeezed(db.limits.MaxCounterKeys, len(counts), short); maxKeys > 0 {
+			db.otherCounts[category] += foldTail(counts, maxKeys)
+		}
+	}
+	for _, counter := range db.decayedCounts {
+		if maxKeys := squeezed(db.limits.MaxCounterKeys, len(counter.weights), short); maxKeys > 0 {
+			counter.foldTail(maxKeys, now)
+		}
+	}
+}
+
+// evictSightings forgets the least recently seen aircraft until only the given number is left,
+// except for the current aircraft, and the flight routes and photos only they referred to.
+// It returns how many aircraft were forgotten.
+func (db *Dashboard) evictSightings(kept int) int {
+	current := make(map[string]bool, len(db.CurrentAircraft))
+	for idx := range db.CurrentAircraft {
+		current[db.CurrentAircraft[idx].Hex] = true
+	}
+	hexes := make([]string, 0, len(db.aircraftSightings))
+	for hex := range db.aircraftSightings {
+		if !current[hex] {
+			hexes = append(hexes, hex)
+		}
+	}
+	slices.SortFunc(hexes, func(a string, b string) int {
+		return db.aircraftSightings[a].lastSeen.Compare(db.aircraftSightings[b].lastSeen)
+	})
+
+	evicted := hexes[:min(len(db.aircraftSightings)-kept, len(hexes))]
+	for _, hex := range evicted {
+		delete(db.aircraftSightings, hex)
+		delete(db.hexToCountry, hex)
+	}
+
+	flights := make(map[string]bool, len(db.aircraftSightings))
+	registrations := make(map[string]bool, len(db.aircraftSightings))
+	for _, sighting := range db.aircraftSightings {
+		flights[sighting.lastFlightNo] = true
+		registrations[sighting.registration] = true
+	}
+	for flight := range db.CachedFlightRoutes {
+		if !flights[flight] {
+			delete(db.CachedFlightRoutes, flight)
+		}
+	}
+	for registration := range db.CachedPhotos {
+		if !registrations[registration] {
+			delete(db.CachedPhotos, registration)
+		}
+	}
+	return len(evicted)
+}
+
+// foldTail removes the least seen properties once there are more than the cap, until there are
+// few enough. It returns the sum of their counts, which the caller keeps apart as others so that
+// they don't show up as a property of their own.
+func foldTail(counts map[string]int, limit int) int {
+	if len(counts) <= limit {
+		return 0
+	}
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a string, b string) int {
+		return cmp.Or(cmp.Compare(counts[a], counts[b]), strings.Compare(a, b))
+	})
+
+	others := 0
+	for _, key := range keys[:len(counts)-keptBelow(limit)] {
+		others += counts[key]
+		delete(counts, key)
+	}
+	return others
+}
+
+// OtherCount returns how often the types, operators or countries of the given category were seen
+// which were folded away since there were more of them than MaxCounterKeys allows.
+func (db *Dashboard) OtherCount(category string) int {
+	return db.otherCounts[category]
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoldTail(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   map[string]int
+		limit    int
+		expected map[string]int
+		others   int
+	}{
+		{
+			name:     "within the cap",
+			counts:   map[string]int{"A320": 5, "B738": 1},
+			limit:    3,
+			expected: map[string]int{"A320": 5, "B738": 1},
+			others:   0,
+		},
+		{
+			name:     "beyond the cap",
+			counts:   map[string]int{"A320": 5, "B738": 1, "A20N": 2, "E190": 3},
+			limit:    3,
+			expected: map[string]int{"A320": 5, "E190": 3},
+			others:   3,
+		},
+		{
+			name:     "far beyond the cap",
+			counts:   map[string]int{"A320": 5, "B738": 1, "A20N": 2, "E190": 3, "A388": 4},
+			limit:    2,
+			expected: map[string]int{"A320": 5},
+			others:   10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			others := foldTail(tt.counts, tt.limit)
+			if !maps.Equal(tt.counts, tt.expected) || others != tt.others {
+				t.Errorf("foldTail() = %v, %d others, expected %v, %d others", tt.counts, others, tt.expected, tt.others)
+			}
+		})
+	}
+}
+
+func TestDecayedCounterFoldTail(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	counter := NewDecayedCounter(time.Hour)
+	for property, count := range map[string]int{"A320": 4, "B738": 1, "A20N": 2, "E190": 5} {
+		for range count {
+			counter.Add(property, now)
+		}
+	}
+
+	counter.foldTail(3, now)
+	if len(counter.weights) != 2 || counter.Weight("E190", now) != 5 || counter.Weight("A320", now) != 4 {
+		t.Errorf("foldTail() left %v, expected E190 and A320", counter.Counts(now))
+	}
+	if total := counter.Total(now); total != 12 {
+		t.Errorf("Total() = %v after folding, expected 12", total)
+	}
+}
+
+func TestEnforceSightingLimit(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	opts := DashboardOptions{RarityScorer: "ratio", Limits: Limits{MaxSightings: 10}}
+	dashboard, err := NewDashboard(0, 0, opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	// The current aircraft are never forgotten, however many there are.
+	records := make([]AircraftRecord, 11)
+	for idx := range records {
+		records[idx] = AircraftRecord{ //nolint:exhaustruct // identity and age only
+			Hex:    fmt.Sprintf("3c64%02d", idx),
+			Flight: fmt.Sprintf("DLH%d", idx),
+			Seen:   float64(idx),
+		}
+	}
+	dashboard.ProcessAircraftRecords(records)
+	if len(dashboard.aircraftSightings) != 11 {
+		t.Fatalf("%d sightings after the first poll, expected all 11 current ones", len(dashboard.aircraftSightings))
+	}
+	dashboard.AssignFlightRoutes([]FlightRouteRecord{{Callsign: "DLH10"}}) //nolint:exhaustruct // callsign only
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{{Hex: "4b1805", Flight: "SWR1"}}) //nolint:exhaustruct // identity
+	if len(dashboard.aircraftSightings) != 9 {
+		t.Errorf("%d sightings beyond the cap, expected 9", len(dashboard.aircraftSightings))
+	}
+	for _, hex := range []string{"3c6410", "3c6409", "3c6408"} {
+		if _, ok := dashboard.aircraftSightings[hex]; ok {
+			t.Errorf("%s is still remembered, expected the least recently seen to be forgotten", hex)
+		}
+	}
+	if _, ok := dashboard.CachedFlightRoutes["DLH10"]; ok {
+		t.Error("the route of a forgotten aircraft is still cached")
+	}
+}
+
+func TestSessionStatsBeyondLimits(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	opts := DashboardOptions{RarityScorer: "ratio", Limits: Limits{MaxSightings: 3, MaxCounterKeys: 2}}
+	dashboard, err := NewDashboard(0, 0, opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	types := []string{"A320", "B738", "E190", "A388", "B77W"}
+	for idx, icaoType := range types {
+		dashboard.ProcessAircraftRecords([]AircraftRecord{{ //nolint:exhaustruct // identity and type only
+			Hex:      fmt.Sprintf("3c64%02d", idx),
+			Flight:   fmt.Sprintf("DLH%d", idx),
+			IcaoType: icaoType,
+		}})
+	}
+	// The first aircraft was forgotten, seeing it again doesn't make it another one.
+	dashboard.ProcessAircraftRecords([]AircraftRecord{{ //nolint:exhaustruct // identity and type only
+		Hex:      "3c6400",
+		Flight:   "DLH0",
+		IcaoType: "A320",
+	}})
+	if dashboard.OtherCount("type") == 0 {
+		t.Fatalf("SeenTypeCount = %v, expected the least seen types folded", dashboard.SeenTypeCount)
+	}
+
+	stats := dashboard.SessionStats()
+	if stats.Aircraft != len(types) || stats.Types != len(types) {
+		t.Errorf("SessionStats() = %d aircraft of %d types, expected %d of %d",
+			stats.Aircraft, stats.Types, len(types), len(types))
+	}
+}
+
+func TestCappedDashboardDoesntListOthersAsType(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	opts := DashboardOptions{RarityScorer: "ratio", Limits: Limits{MaxCounterKeys: 2}}
+	dashboard, err := NewDashboard(0, 0, opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	for idx, icaoType := range []string{"A320", "B738", "E190", "A388"} {
+		dashboard.ProcessAircraftRecords([]AircraftRecord{{ //nolint:exhaustruct // identity and type only
+			Hex:      fmt.Sprintf("3c64%02d", idx),
+			Flight:   fmt.Sprintf("DLH%d", idx),
+			IcaoType: icaoType,
+		}})
+	}
+	if _, ok := dashboard.SeenTypeCount["other"]; ok || dashboard.OtherCount("type") == 0 {
+		t.Fatalf("SeenTypeCount = %v with %d others, expected the others kept apart",
+			dashboard.SeenTypeCount, dashboard.OtherCount("type"))
+	}
+
+	var stdout bytes.Buffer
+	notify, err := NewNotify("test", NotifyOptions{}, &stdout, io.Discard) //nolint:exhaustruct // defaults
+	if err != nil {
+		t.Fatalf("NewNotify() error = %v", err)
+	}
+	notify.PrintSummary(dashboard)
+	if summary := stdout.String(); strings.Contains(summary, " - other\n") ||
+		!strings.Contains(summary, fmt.Sprintf("%6d others beyond the cap\n", dashboard.OtherCount("type"))) {
+		t.Errorf("PrintSummary() = %q, expected the others on a line of their own", summary)
+	}
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultLogRingBytes is how much log output is kept in memory, enough for several hours of
+	// the usual few lines per update.
+	DefaultLogRingBytes = 1 << 20
+	logExportFilePerm   = 0o600
+)
+
+// LogRing keeps the most recent lines of log output in memory, within a budget of bytes. The
+// oldest lines are dropped to make room for new ones. It is safe for concurrent use, since the
+// request, dashboard and notifier all log to it.
+type LogRing struct {
+	mutex    sync.Mutex
+	maxBytes int
+	lines    []string
+	start    int    // start is the index of the oldest line in lines.
+	size     int    // size is the number of bytes of all lines kept.
+	partial  []byte // partial is the start of a line which hasn't been finished yet.
+	dropped  int    // dropped is the number of lines dropped to stay within the budget.
+}
+
+// NewLogRing creates a log ring which keeps at most maxBytes of lines.
+func NewLogRing(maxBytes int) *LogRing {
+	return &LogRing{
+		mutex:    sync.Mutex{},
+		maxBytes: maxBytes,
+		lines:    nil,
+		start:    0,
+		size:     0,
+		partial:  nil,
+		dropped:  0,
+	}
+}
+
+// Write adds the output to the ring, line by line. A line without its newline yet is kept back
+// until it is finished. Lines longer than the whole budget are cut short.
+func (r *LogRing) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rest := p
+	for {
+		idx := bytes.IndexByte(rest, '\n')
+		if idx < 0 {
+			r.partial = append(r.partial, rest...)
+			return len(p), nil
+		}
+		line := string(r.partial) + string(rest[:idx])
+		r.partial = r.partial[:0]
+		rest = rest[idx+1:]
+		r.add(line)
+	}
+}
+
+// add appends the line and drops the oldest lines which no longer fit.
+func (r *LogRing) add(line string) {
+	if len(line) > r.maxBytes {
+		line = line[:r.maxBytes]
+	}
+	r.lines = append(r.lines, line)
+	r.size += len(line)
+	for r.size > r.maxBytes {
+		r.size -= len(r.lines[r.start])
+		r.lines[r.start] = ""
+		r.start++
+		r.dropped++
+	}
+	// Compact once the dropped lines take up half of the slice, so it doesn't grow forever.
+	if r.start > len(r.lines)/2 {
+		r.lines = append([]string(nil), r.lines[r.start:]...)
+		r.start = 0
+	}
+}
+
+// Lines returns the lines kept, oldest first.
+func (r *LogRing) Lines() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.lines[r.start:]...)
+}
+
+// Dropped returns how many of the oldest lines have been dropped to stay within the budget.
+func (r *LogRing) Dropped() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.dropped
+}
+
+// Export writes the lines kept to the file at the path, noting first how many were dropped.
+func (r *LogRing) Export(path string) error {
+	r.mutex.Lock()
+	var content strings.Builder
+	if r.dropped > 0 {
+		fmt.Fprintf(&content, "(%d older lines dropped)\n", r.dropped)
+	}
+	for _, line := range r.lines[r.start:] {
+		content.WriteString(line)
+		content.WriteByte('\n')
+	}
+	r.mutex.Unlock()
+
+	if err := os.WriteFile(path, []byte(content.String()), logExportFilePerm); err != nil {
+		return fmt.Errorf("LogRing.Export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"log" //nolint:depguard // Don't feel like using slog
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLogRing(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBytes    int
+		writes      []string
+		wantLines   []string
+		wantDropped int
+	}{
+		{
+			name:        "within budget",
+			maxBytes:    100,
+			writes:      []string{"one\n", "two\nthree\n"},
+			wantLines:   []string{"one", "two", "three"},
+			wantDropped: 0,
+		},
+		{
+			name:        "oldest dropped",
+			maxBytes:    8,
+			writes:      []string{"one\n", "two\n", "three\n"},
+			wantLines:   []string{"two", "three"},
+			wantDropped: 1,
+		},
+		{
+			name:        "partial lines joined",
+			maxBytes:    100,
+			writes:      []string{"on", "e\ntw", "o\nthr"},
+			wantLines:   []string{"one", "two"},
+			wantDropped: 0,
+		},
+		{
+			name:        "long line cut short",
+			maxBytes:    4,
+			writes:      []string{"airspottr\n"},
+			wantLines:   []string{"airs"},
+			wantDropped: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := NewLogRing(tt.maxBytes)
+			for _, write := range tt.writes {
+				if n, err := ring.Write([]byte(write)); err != nil || n != len(write) {
+					t.Fatalf("Write(%q) = %d, %v", write, n, err)
+				}
+			}
+			if lines := ring.Lines(); !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("Lines() = %q, expected %q", lines, tt.wantLines)
+			}
+			if dropped := ring.Dropped(); dropped != tt.wantDropped {
+				t.Errorf("Dropped() = %d, expected %d", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestLogRingExport(t *testing.T) {
+	ring := NewLogRing(20)
+	logger := log.New(ring, "request ", 0)
+	for _, msg := range []string{"first", "second", "third"} {
+		logger.Println(msg)
+	}
+
+	path := filepath.Join(t.TempDir(), "airspottr.log")
+	if err := ring.Export(path); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "(2 older lines dropped)\nrequest third\n"
+	if string(content) != expected {
+		t.Errorf("Export() wrote %q, expected %q", content, expected)
+	}
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The kinds of messages positions are received by, in the order of the statistics.
+const (
+	messageADSB = iota
+	messageADSR
+	messageTISB
+	messageADSC
+	messageMLAT
+	messageModeS
+	messageOther
+	messageTypeCount
+)
+
+// messageTypeLabels name the kinds of messages.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant arrays
+var messageTypeLabels = [messageTypeCount]string{"ADS-B", "ADS-R", "TIS-B", "ADS-C", "MLAT", "Mode S", "other"}
+
+// messageTypeIndex returns the kind of the readsb message type, e.g. ADS-B for adsb_icao and
+// adsb_other. Unknown types and aircraft without a type, e.g. those of aviationstack, are other.
+func messageTypeIndex(messageType string) int {
+	switch {
+	case strings.HasPrefix(messageType, "adsb"):
+		return messageADSB
+	case strings.HasPrefix(messageType, "adsr"):
+		return messageADSR
+	case IsTISB(messageType):
+		return messageTISB
+	case strings.HasPrefix(messageType, messageTypeADSC):
+		return messageADSC
+	case messageType == "mlat":
+		return messageMLAT
+	case messageType == "mode_s":
+		return messageModeS
+	default:
+		return messageOther
+	}
+}
+
+// MessageTypeLabel names how the position of an aircraft was received, e.g. "ADS-B" for
+// adsb_icao, or returns the readsb message type itself if it isn't known.
+func MessageTypeLabel(messageType string) string {
+	idx := messageTypeIndex(messageType)
+	if idx == messageOther {
+		return messageType
+	}
+	return messageTypeLabels[idx]
+}
+
+// IsTISB tells whether the readsb message type is TIS-B, i.e. a position of a ground radar
+// rebroadcast by a ground station, which is often coarse and late.
+func IsTISB(messageType string) bool {
+	return strings.HasPrefix(messageType, "tisb")
+}
+
+// MessageTypeShare is the count of aircraft whose position was received by a kind of message.
+type MessageTypeShare struct {
+	Label   string  // Label names the kind of message, e.g. "MLAT".
+	Current int     // Current is how many aircraft of the latest poll were received by it.
+	Share   float64 // Share is the fraction of the aircraft of all polls received by it.
+}
+
+// MessageTypeStats counts the aircraft of every poll by the kind of message their position was
+// received by, to show how much of the picture comes from ADS-B and how much from MLAT or TIS-B.
+type MessageTypeStats struct {
+	current [messageTypeCount]int
+	total   [messageTypeCount]int
+}
+
+// NewMessageTypeStats creates empty message type statistics.
+func NewMessageTypeStats() *MessageTypeStats {
+	return &MessageTypeStats{
+		current: [messageTypeCount]int{},
+		total:   [messageTypeCount]int{},
+	}
+}
+
+// Record counts the aircraft of a poll by message type.
+func (mts *MessageTypeStats) Record(aircraft []AircraftRecord) {
+	mts.current = [messageTypeCount]int{}
+	for idx := range aircraft {
+		mts.current[messageTypeIndex(aircraft[idx].Type)]++
+	}
+	for idx, count := range mts.current {
+		mts.total[idx] += count
+	}
+}
+
+// Types returns the counts of the kinds of messages which were seen at all, in a fixed order.
+func (mts *MessageTypeStats) Types() []MessageTypeShare {
+	sum := 0
+	for _, count := range mts.total {
+		sum += count
+	}
+
+	var types []MessageTypeShare
+	for idx, count := range mts.total {
+		if count == 0 {
+			continue
+		}
+		types = append(types, MessageTypeShare{
+			Label:   messageTypeLabels[idx],
+			Current: mts.current[idx],
+			Share:   float64(count) / float64(sum),
+		})
+	}
+	return types
+}
+
+// String lists the kinds of messages with the aircraft of the latest poll and their share of all
+// polls, e.g. "ADS-B 12 (80%), MLAT 3 (20%)".
+func (mts *MessageTypeStats) String() string {
+	types := mts.Types()
+	if len(types) == 0 {
+		return "none yet"
+	}
+	parts := make([]string, len(types))
+	for idx, share := range types {
+		parts[idx] = fmt.Sprintf("%s %d (%.0f%%)", share.Label, share.Current, share.Share*100) //nolint:mnd // percent
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"testing"
+)
+
+func messageTypeRecords(messageTypes ...string) []AircraftRecord {
+	records := make([]AircraftRecord, len(messageTypes))
+	for idx, messageType := range messageTypes {
+		records[idx] = AircraftRecord{Hex: messageType, Type: messageType} //nolint:exhaustruct // type only
+	}
+	return records
+}
+
+func TestMessageTypeLabel(t *testing.T) {
+	tests := []struct {
+		messageType string
+		expected    string
+	}{
+		{messageType: "adsb_icao", expected: "ADS-B"},
+		{messageType: "adsb_icao_nt", expected: "ADS-B"},
+		{messageType: "adsr_icao", expected: "ADS-R"},
+		{messageType: "tisb_trackfile", expected: "TIS-B"},
+		{messageType: "adsc", expected: "ADS-C"},
+		{messageType: "mlat", expected: "MLAT"},
+		{messageType: "mode_s", expected: "Mode S"},
+		{messageType: "unknown", expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if label := MessageTypeLabel(tt.messageType); label != tt.expected {
+			t.Errorf("MessageTypeLabel(%s) = %s, expected %s", tt.messageType, label, tt.expected)
+		}
+	}
+}
+
+func TestMessageTypeStats(t *testing.T) {
+	stats := NewMessageTypeStats()
+	if stats.String() != "none yet" {
+		t.Errorf("String() = %s before any poll, expected none yet", stats)
+	}
+
+	stats.Record(messageTypeRecords("adsb_icao", "adsb_icao", "mlat", "tisb_other"))
+	stats.Record(messageTypeRecords("adsb_icao", "adsb_other", "mlat", ""))
+	expected := "ADS-B 2 (50%), TIS-B 0 (12%), MLAT 1 (25%), other 1 (12%)"
+	if stats.String() != expected {
+		t.Errorf("String() = %s, expected %s", stats, expected)
+	}
+}
+
+func TestExcludeTISB(t *testing.T) {
+	request := &Request{opts: RequestOptions{ExcludeTISB: true}} //nolint:exhaustruct // only the option
+	var kept []string
+	for _, record := range messageTypeRecords("adsb_icao", "tisb_icao", "mlat", "tisb_trackfile", "") {
+		if request.keepRecord(&record) {
+			kept = append(kept, record.Type)
+		}
+	}
+	if len(kept) != 3 || kept[0] != "adsb_icao" || kept[1] != "mlat" || kept[2] != "" {
+		t.Errorf("keepRecord() kept %q, expected adsb_icao, mlat and the one without a type", kept)
+	}
+}
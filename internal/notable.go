@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// NotableSighting is a notable aircraft, like a head-of-state transport, which started a new flight.
+type NotableSighting struct {
+	Aircraft dash.NotableAircraft
+	Sighting *AircraftSighting
+}
+
+// Notable returns the entry of the aircraft in the notable aircraft dataset, if it is notable.
+func (db *Dashboard) Notable(hex string) (dash.NotableAircraft, bool) {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	notable, ok := db.notableAircraft[hex]
+	return notable, ok
+}
+
+// EmitNotableSightings sends an event for every notable aircraft which started a new flight to all
+// enabled sinks. They are sent no matter how rare the aircraft is otherwise.
+func (notify *Notify) EmitNotableSightings(sightings []NotableSighting) {
+	for _, sighting := range sightings {
+		notify.alert(notableEvent(notify.language, sighting), NotifyNotable)
+	}
+}
+
+func notableEvent(lang i18n.Language, notable NotableSighting) Event {
+	sighting := notable.Sighting
+	msgBody := fmt.Sprintf(
+		"%s: %s\n%s %s (%s)\n%s",
+		notable.Aircraft.Category,
+		notable.Aircraft.Description,
+		sighting.lastFlightNo,
+		sighting.typeDesc,
+		sighting.registration,
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindNotable,
+		Severity: SeverityNotable,
+		Title:    lang.T("Notable aircraft"),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("notable %s: %s", notable.Aircraft.Description, sighting.info),
+		Time:     sighting.lastSeen,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestNotableSightings(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	updates := []struct {
+		flight   string
+		expected int
+	}{
+		{flight: "AF1", expected: 1},
+		{flight: "AF1", expected: 0}, // the same flight is reported once
+		{flight: "SAM28000", expected: 1},
+	}
+	for idx, update := range updates {
+		dashboard.ProcessAircraftRecords([]AircraftRecord{
+			{Hex: "adfdf8", Flight: update.flight}, //nolint:exhaustruct // identity only
+			{Hex: "3c6444", Flight: "DLH400"},      //nolint:exhaustruct // identity only
+		})
+		if len(dashboard.NotableSightings) != update.expected {
+			t.Fatalf("update %d: NotableSightings = %+v, expected %d", idx, dashboard.NotableSightings, update.expected)
+		}
+	}
+
+	notable := dashboard.NotableSightings[0]
+	if notable.Aircraft.Category != "head of state" || notable.Sighting.lastFlightNo != "SAM28000" {
+		t.Errorf("NotableSightings[0] = %+v, expected the head-of-state transport", notable)
+	}
+	events := dashboard.PriorityEvents(i18n.English, time.Now())
+	if len(events) != 1 || events[0].Kind != EventKindNotable {
+		t.Errorf("PriorityEvents() = %+v, expected the notable aircraft", events)
+	}
+	if _, ok := dashboard.Notable("3c6444"); ok {
+		t.Error("Notable() = true for an airliner")
+	}
+}
+
+func TestNotableEvent(t *testing.T) {
+	//nolint:exhaustruct // only the described fields
+	sighting := &AircraftSighting{lastFlightNo: "AF1", typeDesc: "BOEING VC-25A", registration: "82-8000"}
+	event := notableEvent(i18n.English, NotableSighting{
+		//nolint:exhaustruct // category and description only
+		Aircraft: dash.NotableAircraft{Category: "head of state", Description: "VC-25A Air Force One"},
+		Sighting: sighting,
+	})
+	if event.Kind != EventKindNotable || event.Title != "Notable aircraft" ||
+		!strings.HasPrefix(event.Body, "head of state: VC-25A Air Force One\nAF1 BOEING VC-25A (82-8000)\n") {
+		t.Errorf("notableEvent() = %+v, expected the description and the aircraft", event)
+	}
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NotesFileName is the name of the file in the data directory of the user which the notes on
+// aircraft are kept in if no other path is given.
+const NotesFileName = "notes.json"
+
+// Note is a free-text note on an aircraft, e.g. "saw this one at the airshow".
+type Note struct {
+	Hex       string    `json:"hex"`
+	Text      string    `json:"text"`
+	Watch     bool      `json:"watch"`     // Watch puts the aircraft on the watchlist.
+	Favourite bool      `json:"favourite"` // Favourite pins the aircraft to the top of the TUI.
+	Updated   time.Time `json:"updated"`
+}
+
+// isAlerting tells whether the reappearance of the aircraft is sent to all sinks, which it is for
+// aircraft of the watchlist and favourites.
+func (note Note) isAlerting() bool {
+	return note.Watch || note.Favourite
+}
+
+// NoteSighting combines an aircraft sighting with the note on that aircraft.
+type NoteSighting struct {
+	Note     Note
+	Sighting *AircraftSighting
+}
+
+// Notes keeps the notes on aircraft by hex and persists them as a JSON file, which can also be
+// edited by hand. The aircraft whose notes are marked to watch form the watchlist.
+type Notes struct {
+	path  string // path is where the notes are persisted, empty keeps them in memory only.
+	mutex sync.Mutex
+	byHex map[string]Note
+}
+
+// LoadNotes reads the notes from the file at the given path.
+// A missing notes file is not an error, it simply means that nothing has been noted yet.
+func LoadNotes(path string) (*Notes, error) {
+	notes := &Notes{path: path, mutex: sync.Mutex{}, byHex: make(map[string]Note)}
+	if path == "" {
+		return notes, nil
+	}
+
+	data, readErr := os.ReadFile(path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return notes, nil
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("LoadNotes: failed to read %s: %w", path, readErr)
+	}
+
+	var entries []Note
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("LoadNotes: failed to unmarshal %s: %w", path, err)
+	}
+	for _, note := range entries {
+		notes.byHex[strings.ToLower(note.Hex)] = note
+	}
+	return notes, nil
+}
+
+// Get returns the note on the aircraft with the given hex, if there is one.
+func (n *Notes) Get(hex string) (Note, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	note, ok := n.byHex[strings.ToLower(hex)]
+	return note, ok
+}
+
+// Set stores the note and persists all notes.
+// A note without text which is neither watched nor a favourite is removed.
+func (n *Notes) Set(note Note) error {
+	note.Hex = strings.ToLower(note.Hex)
+	note.Text = strings.TrimSpace(note.Text)
+	note.Updated = note.Updated.UTC()
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if note.Text == "" && !note.Watch && !note.Favourite {
+		delete(n.byHex, note.Hex)
+	} else {
+		n.byHex[note.Hex] = note
+	}
+
+	if err := n.save(); err != nil {
+		return fmt.Errorf("Notes.Set: %w", err)
+	}
+	return nil
+}
+
+// IsWatched tells whether the aircraft with the given hex is on the watchlist.
+func (n *Notes) IsWatched(hex string) bool {
+	note, ok := n.Get(hex)
+	return ok && note.Watch
+}
+
+// IsFavourite tells whether the aircraft with the given hex is a favourite.
+func (n *Notes) IsFavourite(hex string) bool {
+	note, ok := n.Get(hex)
+	return ok && note.Favourite
+}
+
+// Favourites returns the notes on all favourite aircraft, sorted by hex.
+func (n *Notes) Favourites() []Note {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var favourites []Note
+	for _, note := range n.byHex {
+		if note.Favourite {
+			favourites = append(favourites, note)
+		}
+	}
+	sort.Slice(favourites, func(i, j int) bool { return favourites[i].Hex < favourites[j].Hex })
+	return favourites
+}
+
+// Watchlist returns the hexes of all aircraft on the watchlist, sorted.
+func (n *Notes) Watchlist() []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var hexes []string
+	for hex, note := range n.byHex {
+		if note.Watch {
+			hexes = append(hexes, hex)
+		}
+	}
+	sort.Strings(hexes)
+	return hexes
+}
+
+// save writes all notes, sorted by hex, to a temporary file first so that a failed write can't
+// destroy the existing notes.
+func (n *Notes) save() error {
+	if n.path == "" {
+		return nil
+	}
+
+	entries := make([]Note, 0, len(n.byHex))
+	for _, note := range n.byHex {
+		entries = append(entries, note)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Hex < entries[j].Hex })
+
+	data, marshalErr := json.MarshalIndent(entries, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("save: %w", marshalErr)
+	}
+
+	if err := replaceFile(n.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	notes, err := LoadNotes(path)
+	if err != nil {
+		t.Fatalf("LoadNotes() of missing file error = %v", err)
+	}
+
+	updated := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	toSet := []Note{
+		{Hex: "3C6444", Text: " local med-evac ", Watch: true, Favourite: false, Updated: updated},
+		{Hex: "4b1805", Text: "saw this one at the airshow", Watch: false, Favourite: false, Updated: updated},
+		{Hex: "a12345", Text: "", Watch: true, Favourite: false, Updated: updated},
+		{Hex: "440123", Text: "", Watch: false, Favourite: true, Updated: updated},
+	}
+	for _, note := range toSet {
+		if err := notes.Set(note); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	reloaded, err := LoadNotes(path)
+	if err != nil {
+		t.Fatalf("LoadNotes() error = %v", err)
+	}
+
+	note, ok := reloaded.Get("3c6444")
+	if !ok || note.Text != "local med-evac" || !note.Watch || !note.Updated.Equal(updated) {
+		t.Errorf("Get() = %+v, %v after reload", note, ok)
+	}
+	if watchlist := reloaded.Watchlist(); !reflect.DeepEqual(watchlist, []string{"3c6444", "a12345"}) {
+		t.Errorf("Watchlist() = %v, expected [3c6444 a12345]", watchlist)
+	}
+	if favourites := reloaded.Favourites(); len(favourites) != 1 || favourites[0].Hex != "440123" {
+		t.Errorf("Favourites() = %v, expected only 440123", favourites)
+	}
+	if !reloaded.IsFavourite("440123") || reloaded.IsFavourite("3c6444") {
+		t.Error("IsFavourite() doesn't reflect the favourites")
+	}
+
+	// Removing both text and watch removes the note entirely.
+	if err := reloaded.Set(Note{Hex: "a12345", Text: "", Watch: false, Favourite: false, Updated: updated}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, ok := reloaded.Get("a12345"); ok {
+		t.Error("Get() returned a removed note")
+	}
+	if reloaded.IsWatched("a12345") || !reloaded.IsWatched("3C6444") {
+		t.Error("IsWatched() doesn't reflect the watchlist")
+	}
+}
+
+func TestNotesInMemory(t *testing.T) {
+	notes, err := LoadNotes("")
+	if err != nil {
+		t.Fatalf("LoadNotes() error = %v", err)
+	}
+	note := Note{Hex: "3c6444", Text: "noted", Watch: false, Favourite: false, Updated: time.Now()}
+	if err := notes.Set(note); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if note, ok := notes.Get("3c6444"); !ok || note.Text != "noted" {
+		t.Errorf("Get() = %+v, %v", note, ok)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"io"
 	"log" //nolint:depguard // Don't feel like using slog
+	"strings"
+	"time"
 
 	"github.com/gen2brain/beeep"
+	"github.com/micutio/airspottr/internal/i18n"
+	"github.com/micutio/airspottr/internal/rules"
 )
 
 const (
@@ -13,177 +17,843 @@ const (
 	appIconPath = "./assets/icon.png"
 )
 
+// Verbosity determines how much the console output reports about individual aircraft.
+type Verbosity int
+
+const (
+	// VerbosityQuiet only reports rarity events and summaries.
+	VerbosityQuiet Verbosity = iota
+	// VerbosityNormal additionally reports how the aircraft differ from the previous update.
+	VerbosityNormal
+	// VerbosityVerbose additionally reports every aircraft on every update.
+	VerbosityVerbose
+)
+
+// NotifyOptions configures the console output and notifications.
+type NotifyOptions struct {
+	Summary   SummaryConfig // Summary determines what the periodic summary lists.
+	Verbosity Verbosity     // Verbosity determines how much is reported about individual aircraft.
+	Sinks     SinksConfig   // Sinks determines where events are sent to.
+	TeePath   string        // TeePath is where to log the session as NDJSON, empty disables it.
+	// StallAfter is how long all polls may fail before the feed counts as stalled, 0 never stalls.
+	StallAfter time.Duration
+	// TimeDisplay formats the times shown in reports and logs.
+	TimeDisplay TimeDisplay
+	// Language is what notifications and reports are written in, English if empty.
+	Language i18n.Language
+	// PeakAlert sends new all-time peaks of aircraft to all sinks instead of only logging them.
+	PeakAlert bool
+	// GoAroundAlert sends go-arounds at nearby airports to all sinks instead of only logging them.
+	GoAroundAlert bool
+	// RarityAlertDistance is the distance in [km] beyond which rare sightings are only logged
+	// instead of sent to all sinks, zero sends them at any distance.
+	RarityAlertDistance float64
+	// Notifications switches categories of desktop notifications off, nil keeps all of them on.
+	Notifications NotificationsConfig
+	// NotificationLogPath is a file to keep every notification in, empty keeps them in the sighting
+	// store, see Notify.KeepNotifications.
+	NotificationLogPath string
+	// Routing sends the events of each severity to the sinks of the given names, nil keeps the
+	// defaults.
+	Routing RoutingConfig
+}
+
+// Notify reports to the user. Human-readable reports like summaries are printed to the console,
+// while events like rare sightings are sent to the enabled event sinks.
 type Notify struct {
-	Stdout log.Logger
+	Stdout       *log.Logger // Stdout is nil if there is no console, e.g. in the TUI.
+	errOut       *log.Logger
+	summary      SummaryConfig
+	verbosity    Verbosity
+	sinks        []EventSink          // sinks receive all rarity events.
+	logSinks     []EventSink          // logSinks receive the events of rules with the "log" action.
+	ruleWebhooks map[string]EventSink // ruleWebhooks caches the webhook sinks of rules by URL.
+	tee          *Tee                 // tee logs the session as NDJSON, nil if disabled.
+	notified     *NotificationLog     // notified keeps every notification.
+	feedWatch    *FeedWatch           // feedWatch notices when the feed stalls.
+	timeDisplay  TimeDisplay
+	language     i18n.Language
+	peakAlert    bool    // peakAlert sends new all-time peaks to all sinks.
+	goAround     bool    // goAround sends go-arounds to all sinks.
+	alertWithin  float64 // alertWithin is the distance in [km] up to which rarity is alerted, 0 if any.
+	// desktopOff are the categories of desktop notifications which are switched off.
+	desktopOff map[NotificationCategory]bool
+	routing    RoutingConfig // routing sends the events of some severities to other sinks.
 }
 
-func NewNotify(appName string, consoleOut *io.Writer) *Notify {
+// NewNotify creates the notifier and its event sinks.
+// If consoleOut is nil, neither the reports nor the console sink are available.
+func NewNotify(
+	appName string,
+	opts NotifyOptions,
+	consoleOut io.Writer,
+	errOut io.Writer,
+) (*Notify, error) {
 	beeep.AppName = appName //nolint:reassign // This is the only way to set app name in beeep.
-	return &Notify{
-		Stdout: *log.New(*consoleOut, "", 0),
+
+	notify := Notify{
+		Stdout:       nil,
+		errOut:       log.New(errOut, "", opts.TimeDisplay.LogFlags(log.Ldate|log.Ltime)),
+		summary:      opts.Summary,
+		verbosity:    opts.Verbosity,
+		sinks:        nil,
+		logSinks:     nil,
+		ruleWebhooks: make(map[string]EventSink),
+		tee:          nil,
+		notified:     NewNotificationLog(),
+		feedWatch:    NewFeedWatch(opts.StallAfter),
+		timeDisplay:  opts.TimeDisplay,
+		language:     opts.Language,
+		peakAlert:    opts.PeakAlert,
+		goAround:     opts.GoAroundAlert,
+		alertWithin:  opts.RarityAlertDistance,
+		desktopOff:   make(map[NotificationCategory]bool),
+		routing:      opts.Routing,
+	}
+	for category, enabled := range opts.Notifications {
+		notify.desktopOff[category] = !enabled
+	}
+
+	if consoleOut != nil {
+		notify.Stdout = log.New(consoleOut, "", 0)
+		if opts.Sinks.Console.IsEnabled(true) {
+			sink, err := NewConsoleSink(consoleOut, opts.Sinks.Console.Format)
+			if err != nil {
+				return nil, fmt.Errorf("NewNotify: %w", err)
+			}
+			notify.sinks = append(notify.sinks, sink)
+			notify.logSinks = append(notify.logSinks, sink)
+		}
+	}
+
+	if opts.Sinks.File.IsEnabled(false) {
+		path := opts.Sinks.File.Path
+		if path == "" {
+			path = DefaultEventFilePath
+		}
+		sink, err := NewFileSink(path, opts.Sinks.File.Format)
+		if err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+		notify.sinks = append(notify.sinks, sink)
+		notify.logSinks = append(notify.logSinks, sink)
+	}
+
+	if opts.Sinks.Webhook.IsEnabled(false) {
+		sink, err := NewWebhookSink(opts.Sinks.Webhook.URL, notify.errOut)
+		if err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+		notify.sinks = append(notify.sinks, sink)
+	}
+
+	if opts.Sinks.Desktop.IsEnabled(true) {
+		notify.sinks = append(notify.sinks, &DesktopSink{})
+	}
+
+	if opts.Sinks.Sound.IsEnabled() {
+		sink, err := NewSoundSink(opts.Sinks.Sound)
+		if err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+		notify.sinks = append(notify.sinks, sink)
+	}
+
+	if opts.Sinks.Social.IsEnabled() {
+		sink, err := NewSocialSink(opts.Sinks.Social, notify.errOut)
+		if err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+		notify.sinks = append(notify.sinks, sink)
+	}
+
+	if opts.TeePath != "" {
+		tee, err := NewTee(opts.TeePath)
+		if err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+		notify.tee = tee
+		notify.sinks = append(notify.sinks, tee)
+		notify.logSinks = append(notify.logSinks, tee)
+	}
+
+	notify.sinks = append(notify.sinks, notify.notified)
+	if opts.NotificationLogPath != "" {
+		if err := notify.keepNotificationsIn(NewNotificationFile(opts.NotificationLogPath)); err != nil {
+			return nil, fmt.Errorf("NewNotify: %w", err)
+		}
+	}
+
+	return &notify, nil
+}
+
+// Close closes the tee output, if there is one.
+func (notify *Notify) Close() error {
+	if notify.tee == nil {
+		return nil
+	}
+	if err := notify.tee.Close(); err != nil {
+		return fmt.Errorf("Notify.Close: %w", err)
+	}
+	return nil
+}
+
+// TeeAircraftUpdates logs all aircraft of the latest update to the tee output, if there is one.
+func (notify *Notify) TeeAircraftUpdates(dash *Dashboard) {
+	if notify.tee == nil {
+		return
+	}
+	if err := notify.tee.WriteAircraft(dash, dash.Clock().Now()); err != nil {
+		notify.errOut.Println(fmt.Errorf("TeeAircraftUpdates: %w", err))
+	}
+}
+
+// PrintAircraftUpdates prints the aircraft of the latest update according to the verbosity:
+// nothing when quiet, how they differ from the previous update by default and all of them when
+// verbose.
+func (notify *Notify) PrintAircraftUpdates(dash *Dashboard) {
+	switch notify.verbosity {
+	case VerbosityQuiet:
+		return
+	case VerbosityNormal:
+		notify.printPollDiff(dash)
+	case VerbosityVerbose:
+		notify.Stdout.Printf(
+			"--- %s: %d aircraft, %d new ---\n",
+			notify.timeDisplay.Format(dash.Clock().Now()),
+			len(dash.CurrentAircraft),
+			len(dash.NewAircraft))
+		for idx := range dash.CurrentAircraft {
+			notify.Stdout.Println(aircraftToString(&dash.CurrentAircraft[idx]))
+		}
+	}
+}
+
+// printPollDiff prints how the aircraft of the latest update differ from those of the previous
+// one, unless they don't: the new aircraft, the callsigns of the lost ones and what changed.
+func (notify *Notify) printPollDiff(dash *Dashboard) {
+	diff := dash.PollDiff
+	if diff.IsEmpty() {
+		return
+	}
+	notify.Stdout.Printf(
+		"--- %s: %d aircraft, %d new, %d lost, %d changed ---\n",
+		notify.timeDisplay.Format(dash.Clock().Now()),
+		len(dash.CurrentAircraft),
+		len(diff.New),
+		len(diff.Lost),
+		len(diff.Changed))
+	for _, aircraft := range diff.New {
+		notify.Stdout.Printf("new %s\n", aircraftToString(aircraft))
+	}
+	if len(diff.Lost) > 0 {
+		lost := make([]string, len(diff.Lost))
+		for idx := range diff.Lost {
+			lost[idx] = pollDiffLabel(&diff.Lost[idx])
+		}
+		notify.Stdout.Printf("lost %s\n", strings.Join(lost, ", "))
+	}
+	for _, change := range diff.Changed {
+		notify.Stdout.Printf("changed %s: %s\n", pollDiffLabel(change.Aircraft), strings.Join(change.Changes, ", "))
 	}
 }
 
 // PrintSummary prints the highest, fastest and the most and the least common types.
 func (notify *Notify) PrintSummary(dash *Dashboard) {
-	notify.Stdout.Println("=== Summary ===")
-	notify.listByRarity("aircraft", dash.SeenTypeCount)
-	notify.listByRarity("operator", dash.SeenOperatorCount)
-	notify.listByRarity("country", dash.SeenCountryCount)
-	notify.Stdout.Println("Fastest Aircraft:")
-	notify.Stdout.Println(aircraftToString(dash.Fastest))
-	notify.Stdout.Println("Highest Aircraft:")
-	notify.Stdout.Println(aircraftToString(dash.Highest))
-	notify.Stdout.Println("=== End Summary ===")
+	notify.printLine("=== Summary ===")
+	notify.printf(
+		"Rarity scorer: %s (%s)\n",
+		dash.RarityScorer().Name(),
+		dash.RarityScorer().Parameters())
+	if halfLife := dash.StatsHalfLife(); halfLife > 0 {
+		notify.printf("Seen-counts decay with half life %s\n", halfLife)
+	}
+	if comparison := dash.ScorerComparison(); comparison != nil {
+		notify.printf("Scorer comparison: %s\n", comparison)
+	}
+	notify.printf("Rarity baseline: %s\n", dash.Warmup())
+	notify.listByRarity("aircraft", dash.SeenTypeCount, dash.OtherCount("type"), notify.summary.Types)
+	notify.listByRarity("operator", dash.SeenOperatorCount, dash.OtherCount("operator"), notify.summary.Operators)
+	notify.listByRarity("country", dash.SeenCountryCount, dash.OtherCount("country"), notify.summary.Countries)
+	now := dash.Clock().Now()
+	notify.printTraffic(dash.Traffic, now)
+	notify.printAltitudeBands(dash.Altitudes)
+	notify.printf("Received by (now, share): %s\n", dash.MessageTypes)
+	notify.printWinds(dash.Winds)
+	notify.printTemperatures(dash.Temperatures)
+	notify.printPeaks(dash.Peaks)
+	notify.printf("Distinct aircraft today: %d\n", dash.DailyAircraft.Today(now))
+	notify.printf("Go-arounds: %d today, session: %s\n", dash.GoArounds.Today(now), dash.GoArounds)
+	notify.printDiscovery(dash.Discovery, now)
+	notify.printf("Achievements unlocked: %d of %d\n", dash.Achievements.Unlocked(), len(dash.Achievements.Progress()))
+	notify.printRecords(dash.Records, now)
+	notify.printLine("=== End Summary ===")
 }
 
-func (notify *Notify) listByRarity(propertyName string, propertyCountMap map[string]int) {
-	propertyCounts := GetSortedCountsForProperty(propertyCountMap)
+// printRecords lists the fastest and highest aircraft of the session, of today and of all time.
+func (notify *Notify) printRecords(records *RecordStats, now time.Time) {
+	for _, period := range []struct {
+		name string
+		set  RecordSet
+	}{
+		{name: "session", set: records.Session()},
+		{name: "today", set: records.Day(now)},
+		{name: "all-time", set: records.AllTime()},
+	} {
+		if period.set.Fastest != nil {
+			notify.printf("Fastest aircraft (%s): %s\n", notify.language.T(period.name), period.set.Fastest.String("kt"))
+		}
+		if period.set.FastestMach != nil {
+			notify.printf("Fastest by Mach (%s): %s\n", notify.language.T(period.name), period.set.FastestMach.MachString())
+		}
+		if period.set.Highest != nil {
+			notify.printf("Highest aircraft (%s): %s\n", notify.language.T(period.name), period.set.Highest.String("ft"))
+		}
+		if period.set.Farthest != nil {
+			notify.printf("Farthest aircraft (%s): %s\n",
+				notify.language.T(period.name), period.set.Farthest.String("km"))
+		}
+	}
+}
 
-	notify.Stdout.Printf("Rarity from least to most common %s", propertyName)
+// printDiscovery charts how many new types, operators and countries were discovered per day.
+func (notify *Notify) printDiscovery(discovery *DiscoveryStats, now time.Time) {
+	days := discovery.Daily(now, discoveryChartDays)
+	notify.printf(
+		"Discoveries last %d days: %s\n",
+		discoveryChartDays,
+		DiscoverySparkline(days))
+	notify.printf("Explored so far: %s\n", discovery.Summary())
+}
+
+// printAltitudeBands charts the share of airborne aircraft in each altitude band.
+func (notify *Notify) printAltitudeBands(altitudes *AltitudeBandStats) {
+	notify.printLine("Altitude bands (now, share):")
+	for _, line := range AltitudeHistogram(altitudes.Bands()) {
+		notify.Stdout.Println("  " + line)
+	}
+}
+
+// printWinds lists the wind reported by aircraft in each altitude band.
+func (notify *Notify) printWinds(winds *WindStats) {
+	notify.printLine("Winds aloft (from, speed, reports):")
+	for _, line := range WindProfile(winds.Bands()) {
+		notify.Stdout.Println("  " + line)
+	}
+}
+
+// printTemperatures lists the temperature reported by aircraft by altitude, flagging inversions.
+func (notify *Notify) printTemperatures(temperatures *TemperatureStats) {
+	notify.printLine("Temperatures aloft (OAT, reports):")
+	for _, line := range TemperatureProfile(temperatures.Bands()) {
+		notify.Stdout.Println("  " + line)
+	}
+}
+
+// printTraffic charts the traffic volume of the last day and the last two weeks, together with
+// the hours of the day in which the airspace is busiest.
+func (notify *Notify) printTraffic(traffic *TrafficStats, now time.Time) {
+	notify.printf("Traffic last 24 hours: %s\n", Sparkline(traffic.Hourly(now, trafficChartHours)))
+	notify.printf("Traffic last 14 days:  %s\n", Sparkline(traffic.Daily(now, trafficChartDays)))
+
+	averages := traffic.ByHourOfDay()
+	busiest := traffic.BusiestHours(trafficBusiestHours)
+	if len(busiest) == 0 {
+		return
+	}
+	notify.printLine("Busiest hours:")
+	for _, hour := range busiest {
+		notify.printf("  %02d:00 - %.1f aircraft on average\n", hour, averages[hour])
+	}
+}
+
+// printPeaks prints the most aircraft visible at once, in this session and ever.
+func (notify *Notify) printPeaks(peaks *PeakStats) {
+	session := peaks.Session()
+	allTime := peaks.AllTime()
+	notify.printf("Peak: %d aircraft at %s, all-time %d on %s %s\n",
+		session.Aircraft,
+		notify.timeDisplay.Format(session.Time),
+		allTime.Aircraft,
+		allTime.Time.In(notify.timeDisplay.Location()).Format(time.DateOnly),
+		notify.timeDisplay.Format(allTime.Time))
+}
+
+// PrintSourceStats prints how many aircraft each data source contributed, if there are several.
+func (notify *Notify) PrintSourceStats(names []string, stats []SourceStats) {
+	if len(names) < 2 { //nolint:mnd // nothing to attribute with a single source
+		return
+	}
+
+	notify.printLine("Data sources:")
+	for idx, name := range names {
+		notify.printf("  %s: %s\n", name, stats[idx])
+	}
+}
+
+// PrintWeeklyReport prints what has been seen during the past week, together with any seasonal
+// patterns found in the entire sighting history. The week covers the current and the six previous
+// spotting days, so a night's sightings are either all in the report or not at all.
+func (notify *Notify) PrintWeeklyReport(entries []HistoryEntry, now time.Time, day SpottingDay) {
+	weekStart := day.Start(now).AddDate(0, 0, -(daysPerWeek - 1))
+	weekTypeCount := make(map[string]int)
+	weekOperatorCount := make(map[string]int)
+	weekCountryCount := make(map[string]int)
+	weekSightings := 0
+	for _, entry := range entries {
+		if entry.Time.Before(weekStart) {
+			continue
+		}
+		weekSightings++
+		weekTypeCount[entry.Type]++
+		weekOperatorCount[entry.Operator]++
+		weekCountryCount[entry.Country]++
+	}
+
+	notify.printLine("=== Weekly Report ===")
+	notify.printf("Sightings this week: %d\n", weekSightings)
+	notify.printf("Distinct types: %d\n", len(weekTypeCount))
+	notify.printf("Distinct operators: %d\n", len(weekOperatorCount))
+	notify.printf("Distinct countries: %d\n", len(weekCountryCount))
+
+	patterns := DetectSeasonalPatterns(entries)
+	if len(patterns) == 0 {
+		notify.printLine("No seasonal patterns found (yet)")
+	} else {
+		notify.printLine("Seasonal patterns:")
+		for _, pattern := range patterns {
+			notify.printf("  %s\n", pattern)
+		}
+	}
+	notify.printLine("=== End Weekly Report ===")
+}
+
+func (notify *Notify) listByRarity(
+	propertyName string,
+	propertyCountMap map[string]int,
+	others int,
+	config SummaryListConfig,
+) {
+	propertyCounts := SelectCounts(GetSortedCountsForProperty(propertyCountMap), config)
+
+	order := "least to most"
+	if config.Order == SummaryOrderDescending {
+		order = "most to least"
+	}
+	limit := ""
+	if config.Limit > 0 {
+		limit = notify.language.Sprintf(", top %d", config.Limit)
+	}
+	notify.printf(
+		"Rarity from %s common %s%s\n",
+		notify.language.T(order),
+		notify.language.T(propertyName),
+		limit)
 	for j := range propertyCounts {
-		notify.Stdout.Printf("%6d - %s\n", propertyCounts[j].Count, propertyCounts[j].Property)
+		notify.printf("%6d - %s\n", propertyCounts[j].Count, propertyCounts[j].Property)
+	}
+	if others > 0 {
+		notify.printf("%6d others beyond the cap\n", others)
 	}
 }
 
+// printf prints a line of a report, translated into the language of the notifier.
+func (notify *Notify) printf(format string, args ...any) {
+	notify.Stdout.Print(notify.language.Sprintf(format, args...))
+}
+
+// printLine prints a line of a report, translated into the language of the notifier.
+func (notify *Notify) printLine(text string) {
+	notify.Stdout.Println(notify.language.T(text))
+}
+
+// EmitRarityNotifications sends an event for every rare sighting to all enabled sinks, or only
+// logs it if it's farther away than the alert tier reaches.
 func (notify *Notify) EmitRarityNotifications(rareSightings []RareSighting) {
 	for _, rareSighting := range rareSightings {
+		var event Event
 		switch rareSighting.Rarities {
 		case NoRarity:
 			return
 		case RareType:
-			notify.Stdout.Printf("found rare type %s\n", rareSighting.Sighting.info)
-			notifyRareType(rareSighting.Sighting)
+			event = rareTypeEvent(notify.language, rareSighting.Sighting)
 		case RareOperator:
-			notify.Stdout.Printf("found rare operator: %s\n", rareSighting.Sighting.operator)
-			notifyRareOperator(rareSighting.Sighting)
+			event = rareOperatorEvent(notify.language, rareSighting.Sighting)
 		case RareCountry:
-			notify.Stdout.Printf("found rare country: %s\n", rareSighting.Sighting.country)
-			notifyRareCountry(rareSighting.Sighting)
+			event = rareCountryEvent(notify.language, rareSighting.Sighting)
 		case RareTypeAndOperator:
-			notify.Stdout.Printf(
-				"found rare type and operator: %s run by %s\n",
-				rareSighting.Sighting.info,
-				rareSighting.Sighting.operator)
-			notifyRareTypeAndOperator(rareSighting.Sighting)
+			event = rareTypeAndOperatorEvent(notify.language, rareSighting.Sighting)
 		case RareTypeAndCountry:
-			notify.Stdout.Printf(
-				"found rare type and country: %s -> %s\n",
-				rareSighting.Sighting.info,
-				rareSighting.Sighting.country)
-			notifyRareTypeAndCountry(rareSighting.Sighting)
+			event = rareTypeAndCountryEvent(notify.language, rareSighting.Sighting)
 		case RareOperatorAndCountry:
-			notify.Stdout.Printf(
-				"found rare operator and country: %s -> %s\n",
-				rareSighting.Sighting.operator,
-				rareSighting.Sighting.country)
-			notifyRareOperatorAndCountry(rareSighting.Sighting)
+			event = rareOperatorAndCountryEvent(notify.language, rareSighting.Sighting)
 		case RareTypeOperatorCountry:
-			notify.Stdout.Printf(
-				"found the TRIFECTA: %s -> %s -> %s\n",
-				rareSighting.Sighting.info,
-				rareSighting.Sighting.operator,
-				rareSighting.Sighting.country)
-			notifyRareTypeOperatorCountry(rareSighting.Sighting)
+			event = rareTypeOperatorCountryEvent(notify.language, rareSighting.Sighting)
+		}
+		if rareSighting.Rarities&RareType != 0 {
+			event = withRarityScore(event, rareSighting.Sighting.rarityScore)
+		}
+
+		if notify.alertWithin > 0 && rareSighting.Sighting.distance > notify.alertWithin {
+			event.Severity = SeverityInfo
+			notify.logEvent(event)
+			continue
+		}
+		notify.alert(event, rarityCategories(rareSighting.Rarities)...)
+	}
+}
+
+// EmitFirstSightings sends an event for every sighting of a type, operator or country never seen
+// before to all enabled sinks, in addition to any rarity event of the same sighting.
+func (notify *Notify) EmitFirstSightings(firstSightings []FirstSighting) {
+	for _, firstSighting := range firstSightings {
+		notify.alert(firstSightingEvent(notify.language, firstSighting))
+	}
+}
+
+// EmitRuleAlerts carries out the actions of all custom alert rules which matched.
+// Rule actions are explicit, so they are carried out regardless of which sinks are enabled for
+// rarity events: "log" writes to the console and file sinks, "notify" shows a desktop
+// notification, which is kept in the notification log, and "webhook" posts to the webhook of the
+// rule. Rules with a severity of their own are routed by it instead, if it is routed.
+func (notify *Notify) EmitRuleAlerts(ruleMatches []RuleMatch) {
+	for _, match := range ruleMatches {
+		severity := Severity(match.Rule.Severity)
+		if severity == "" {
+			severity = SeverityNotable
+		}
+		event := ruleMatchEvent(notify.language, match.Rule.Name, severity, match.Sighting)
+		if _, isRouted := notify.routing[severity]; isRouted && match.Rule.Severity != "" {
+			notify.emit(event, notify.route(event, nil)...)
+			continue
+		}
+		for _, action := range match.Rule.Actions {
+			switch action {
+			case rules.ActionLog:
+				notify.emit(event, notify.logSinks...)
+			case rules.ActionNotify:
+				notify.emit(event, &DesktopSink{})
+				notify.emit(event, notify.notified)
+			case rules.ActionWebhook:
+				sink, err := notify.ruleWebhook(match.Rule.Webhook)
+				if err != nil {
+					notify.errOut.Println(fmt.Errorf("EmitRuleAlerts: rule %s: %w", match.Rule.Name, err))
+					continue
+				}
+				notify.emit(event, sink)
+			}
+		}
+	}
+}
+
+// EmitNoteNotifications reminds of the notes on aircraft which reappeared.
+// Notes on aircraft of the watchlist and on favourites are sent to all enabled sinks, like rare
+// sightings, while other notes are only written to the console and file sinks.
+func (notify *Notify) EmitNoteNotifications(noteSightings []NoteSighting) {
+	for _, noteSighting := range noteSightings {
+		event := noteEvent(notify.language, noteSighting.Note, noteSighting.Sighting)
+		if noteSighting.Note.isAlerting() {
+			notify.alert(event, NotifyWatchlist)
+		} else {
+			notify.logEvent(event)
+		}
+	}
+}
+
+// EmitIdentityChanges reports the squawk and callsign changes of the latest update to the console
+// and file sinks. Changes to an emergency squawk are sent to all enabled sinks, like rare
+// sightings.
+func (notify *Notify) EmitIdentityChanges(changes []IdentityChange, now time.Time) {
+	for _, change := range changes {
+		event := identityChangeEvent(notify.language, change, now)
+		if change.IsEmergency() {
+			notify.alert(event, NotifyEmergency)
+		} else {
+			notify.logEvent(event)
+		}
+	}
+}
+
+// EmitAreaMovements logs the arrivals and departures of the latest update to the console and file
+// sinks, as a log of the movements in the watch areas.
+func (notify *Notify) EmitAreaMovements(movements []AreaMovement, now time.Time) {
+	for _, movement := range movements {
+		notify.logEvent(areaMovementEvent(notify.language, movement, now))
+	}
+}
+
+// EmitPeak reports a new all-time peak of aircraft visible at once to the console and file sinks,
+// or to all enabled sinks if peak alerts are enabled.
+func (notify *Notify) EmitPeak(record *PeakRecord) {
+	if record == nil {
+		return
+	}
+	event := peakEvent(notify.language, *record, notify.timeDisplay)
+	if notify.peakAlert {
+		event.Severity = SeverityNotable
+		notify.alert(event, NotifyRecord)
+	} else {
+		notify.logEvent(event)
+	}
+}
+
+// CheckFeed escalates to all enabled sinks once every poll has been failing for too long, and
+// again once the feed recovers, given when the polls started to fail and the last poll error.
+func (notify *Notify) CheckFeed(failingSince time.Time, pollErr error, now time.Time) {
+	_, since := notify.feedWatch.Stalled()
+	switch notify.feedWatch.Check(failingSince, now) {
+	case FeedUnchanged:
+	case FeedStalled:
+		notify.alert(feedStalledEvent(notify.language, failingSince, pollErr, now))
+	case FeedRecovered:
+		notify.alert(feedRecoveredEvent(notify.language, since, now))
+	}
+}
+
+// Notifications returns the latest notifications, newest first.
+func (notify *Notify) Notifications() []NotificationEntry {
+	return notify.notified.Recent()
+}
+
+// KeepNotifications keeps every notification in the given store, usually the sighting store of
+// the dashboard, unless they are kept in the file of NotifyOptions.NotificationLogPath already.
+// Without either they are kept for this session only.
+func (notify *Notify) KeepNotifications(store NotificationStore) error {
+	if store == nil || notify.notified.hasStore() {
+		return nil
+	}
+	return notify.keepNotificationsIn(store)
+}
+
+// keepNotificationsIn reads the latest notifications of the store and keeps every further one in
+// it. Entries of the store which can't be read are skipped, which is logged.
+func (notify *Notify) keepNotificationsIn(store NotificationStore) error {
+	if err := notify.notified.KeepIn(store); err != nil {
+		return fmt.Errorf("Notify.KeepNotifications: %w", err)
+	}
+	if skipped := notify.notified.Skipped(); skipped > 0 {
+		notify.errOut.Printf("skipped %d notifications which couldn't be read", skipped)
+	}
+	return nil
+}
+
+// FeedStalled tells whether the feed is stalled and since when the polls have been failing.
+func (notify *Notify) FeedStalled() (bool, time.Time) {
+	return notify.feedWatch.Stalled()
+}
+
+// emit delivers the event to the given sinks.
+// A failing sink doesn't keep the event from the others.
+func (notify *Notify) emit(event Event, sinks ...EventSink) {
+	for _, sink := range sinks {
+		if err := sink.Emit(event); err != nil {
+			notify.errOut.Printf("failed to emit %s event to %s sink: %s\n", event.Kind, sink.Name(), err)
 		}
 	}
 }
 
-func notifyRareType(sighting *AircraftSighting) {
-	msgTitle := "Rare Aircraft Type Spotted"
+// ruleWebhook returns the sink for the webhook of a rule, creating it on first use.
+func (notify *Notify) ruleWebhook(webhookURL string) (EventSink, error) {
+	if sink, ok := notify.ruleWebhooks[webhookURL]; ok {
+		return sink, nil
+	}
+
+	sink, err := NewWebhookSink(webhookURL, notify.errOut)
+	if err != nil {
+		return nil, fmt.Errorf("ruleWebhook: %w", err)
+	}
+	notify.ruleWebhooks[webhookURL] = sink
+	return sink, nil
+}
+
+func ruleMatchEvent(lang i18n.Language, ruleName string, severity Severity, sighting *AircraftSighting) Event {
 	msgBody := fmt.Sprintf(
-		"%s (%s)\n%3.0f %s",
+		"%s %s (%s)\n%s",
+		sighting.lastFlightNo,
 		sighting.typeDesc,
 		sighting.registration,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindRule,
+		Severity: severity,
+		Title:    lang.Sprintf("Alert: %s", ruleName),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("alert %s: %s", ruleName, sighting.info),
+		Time:     time.Now(),
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
 	}
 }
 
-func notifyRareOperator(sighting *AircraftSighting) {
-	operator := sighting.operator
-	msgTitle := "Rare Operator Spotted"
+// firstSightingEvent celebrates the lifetime firsts of a sighting, headlined by the first of them,
+// e.g. "First EMBRAER, E195-E2 ever!".
+func firstSightingEvent(lang i18n.Language, firstSighting FirstSighting) Event {
+	sighting := firstSighting.Sighting
+	firsts := make([]string, len(firstSighting.Firsts))
+	lines := make([]string, len(firstSighting.Firsts))
+	for idx, first := range firstSighting.Firsts {
+		firsts[idx] = fmt.Sprintf("%s %s", lang.T(first.Category), first.Property)
+		lines[idx] = lang.Sprintf("first %s ever: %s", lang.T(first.Category), first.Property)
+	}
 	msgBody := fmt.Sprintf(
-		"%s flying %s (%s)\n%3.0f %s",
-		operator,
+		"%s\n%s (%s)\n%s",
+		strings.Join(lines, "\n"),
+		sighting.lastFlightNo,
+		sighting.registration,
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindFirst,
+		Severity: SeverityNotable,
+		Title:    lang.Sprintf("First %s ever!", firstSighting.Firsts[0].Property),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("FIRST EVER %s: %s", strings.Join(firsts, ", "), sighting.info),
+		Time:     time.Now(),
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
+
+func noteEvent(lang i18n.Language, note Note, sighting *AircraftSighting) Event {
+	msgTitle := lang.T("Noted aircraft")
+	severity := SeverityInfo
+	if note.isAlerting() {
+		severity = SeverityNotable
+	}
+	if note.Favourite {
+		msgTitle = lang.T("Favourite aircraft")
+	} else if note.Watch {
+		msgTitle = lang.T("Watchlist aircraft")
+	}
+	msgBody := fmt.Sprintf(
+		"%s %s (%s)\n%s",
+		sighting.lastFlightNo,
 		sighting.typeDesc,
 		sighting.registration,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
+		note.Text)
+	return Event{
+		Kind:     EventKindNote,
+		Severity: severity,
+		Title:    msgTitle,
+		Body:     msgBody,
+		Summary:  lang.Sprintf("note %q: %s", note.Text, sighting.info),
+		Time:     time.Now(),
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
+
+// rarityEvent creates the event for a rare sighting.
+func rarityEvent(msgTitle, msgBody, summary string, sighting *AircraftSighting) Event {
+	return Event{
+		Kind:     EventKindRarity,
+		Severity: SeverityRare,
+		Title:    msgTitle,
+		Body:     msgBody,
+		Summary:  summary,
+		Time:     time.Now(),
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
+
+// withRarityScore adds how rare the type is here and worldwide to the description of the event.
+func withRarityScore(event Event, score RarityScore) Event {
+	event.Body += "\n" + score.String()
+	event.Summary += " - " + score.String()
+	return event
+}
+
+// withPhotoLink appends the link to a photo of the sighted aircraft to the message body,
+// if there is one.
+func withPhotoLink(msgBody string, sighting *AircraftSighting) string {
+	if sighting == nil || !sighting.photo.HasLink() {
+		return msgBody
 	}
+
+	return msgBody + "\n" + sighting.photo.Link
 }
 
-func notifyRareCountry(sighting *AircraftSighting) {
+func rareTypeEvent(lang i18n.Language, sighting *AircraftSighting) Event {
+	msgTitle := lang.T("Rare Aircraft Type Spotted")
+	msgBody := lang.Sprintf(
+		"%s (%s)\n%s",
+		sighting.typeDesc,
+		sighting.registration,
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare type %s", sighting.info)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
+}
+
+func rareOperatorEvent(lang i18n.Language, sighting *AircraftSighting) Event {
+	operator := sighting.operator
+	msgTitle := lang.T("Rare Operator Spotted")
+	msgBody := lang.Sprintf(
+		"%s flying %s (%s)\n%s",
+		operator,
+		sighting.typeDesc,
+		sighting.registration,
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare operator: %s", operator)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
+}
+
+func rareCountryEvent(lang i18n.Language, sighting *AircraftSighting) Event {
 	country := sighting.country
-	msgTitle := "Rare Aircraft Country Spotted"
-	msgBody := fmt.Sprintf(
-		"%s-based %s (%s)\n%3.0f %s",
+	msgTitle := lang.T("Rare Aircraft Country Spotted")
+	msgBody := lang.Sprintf(
+		"%s-based %s (%s)\n%s",
 		country,
 		sighting.typeDesc,
 		sighting.registration,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
-	}
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare country: %s", country)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
 }
 
-func notifyRareTypeAndOperator(sighting *AircraftSighting) {
+func rareTypeAndOperatorEvent(lang i18n.Language, sighting *AircraftSighting) Event {
 	operator := sighting.operator
-	msgTitle := "Rare Type & Operator Spotted"
-	msgBody := fmt.Sprintf(
-		"%s (%s) operated by\n%s\n%3.0f %s",
+	msgTitle := lang.T("Rare Type & Operator Spotted")
+	msgBody := lang.Sprintf(
+		"%s (%s) operated by\n%s\n%s",
 		sighting.typeDesc,
 		sighting.registration,
 		operator,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
-	}
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare type and operator: %s run by %s", sighting.info, operator)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
 }
 
-func notifyRareTypeAndCountry(sighting *AircraftSighting) {
+func rareTypeAndCountryEvent(lang i18n.Language, sighting *AircraftSighting) Event {
 	country := sighting.country
-	msgTitle := "Rare Type & Country Spotted"
-	msgBody := fmt.Sprintf(
-		"%s (%s) registered in\n%s\n%3.0f %s",
+	msgTitle := lang.T("Rare Type & Country Spotted")
+	msgBody := lang.Sprintf(
+		"%s (%s) registered in\n%s\n%s",
 		sighting.typeDesc,
 		sighting.registration,
 		country,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
-	}
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare type and country: %s -> %s", sighting.info, country)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
 }
 
-func notifyRareOperatorAndCountry(sighting *AircraftSighting) {
+func rareOperatorAndCountryEvent(lang i18n.Language, sighting *AircraftSighting) Event {
 	operator := sighting.operator
 	country := sighting.country
-	msgTitle := "Rare Operator & Country Spotted"
-	msgBody := fmt.Sprintf(
-		"%s\nflying aircraft registered in\n%s\n%3.0f %s",
+	msgTitle := lang.T("Rare Operator & Country Spotted")
+	msgBody := lang.Sprintf(
+		"%s\nflying aircraft registered in\n%s\n%s",
 		operator,
 		country,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
-	}
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found rare operator and country: %s -> %s", operator, country)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
 }
 
-func notifyRareTypeOperatorCountry(sighting *AircraftSighting) {
+func rareTypeOperatorCountryEvent(lang i18n.Language, sighting *AircraftSighting) Event {
 	var aType string
 	if sighting.typeShort != "" {
 		aType = sighting.typeShort
@@ -193,26 +863,23 @@ func notifyRareTypeOperatorCountry(sighting *AircraftSighting) {
 
 	operator := sighting.operator
 	country := sighting.country
-	msgTitle := "TRIFECTA Spotted!"
-	msgBody := fmt.Sprintf(
-		"%s (%s),\nrun by %s,\nregistered in\n%s\n%3.0f %s",
+	msgTitle := lang.T("TRIFECTA Spotted!")
+	msgBody := lang.Sprintf(
+		"%s (%s),\nrun by %s,\nregistered in\n%s\n%s",
 		aType,
 		sighting.registration,
 		operator,
 		country,
-		sighting.distance,
-		sighting.direction)
-	err := beeep.Notify(msgTitle, msgBody, appIconPath)
-	if err != nil {
-		panic(err)
-	}
+		sighting.whereabouts(lang))
+	summary := lang.Sprintf("found the TRIFECTA: %s -> %s -> %s", sighting.info, operator, country)
+	return rarityEvent(msgTitle, msgBody, summary, sighting)
 }
 
 // aircraftToString generates a one-liner consisting of the most relevant information about the
 // given aircraft.
 func aircraftToString(aircraft *AircraftRecord) string {
 	flight := aircraft.GetFlightNoAsStr()
-	altitude := aircraft.GetAltitudeAsStr()
+	altitude := aircraft.AltBaro.String()
 	var aType string
 	if aircraft.Description != "" {
 		aType = aircraft.Description
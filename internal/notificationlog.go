@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// recentNotifications is how many notifications are kept in memory to review them.
+const recentNotifications = 200
+
+// NotificationEntry is the record of a notification, as sent to all enabled sinks.
+type NotificationEntry struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`
+	Title        string    `json:"title"`
+	Body         string    `json:"body"`
+	Flight       string    `json:"flight,omitempty"`
+	Registration string    `json:"registration,omitempty"`
+	Type         string    `json:"type,omitempty"`
+}
+
+func newNotificationEntry(event Event) NotificationEntry {
+	entry := NotificationEntry{
+		Time:         event.Time.UTC(),
+		Kind:         event.Kind,
+		Title:        event.Title,
+		Body:         event.Body,
+		Flight:       "",
+		Registration: "",
+		Type:         "",
+	}
+	if event.Sighting != nil {
+		entry.Flight = event.Sighting.lastFlightNo
+		entry.Registration = event.Sighting.registration
+		entry.Type = event.Sighting.typeDesc
+	}
+	return entry
+}
+
+// Aircraft describes the aircraft of the notification, e.g. "DLH400 Airbus A320 (D-AIBD)", or is
+// empty if there is none.
+func (entry NotificationEntry) Aircraft() string {
+	if entry.Flight == "" && entry.Type == "" && entry.Registration == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s (%s)", entry.Flight, entry.Type, entry.Registration)
+}
+
+// NotificationLog is a sink which keeps the latest notifications in memory, so that missed ones can
+// be reviewed, and every notification in a NotificationStore, if there is one, so that they can be
+// reviewed after a restart as well.
+type NotificationLog struct {
+	mutex   sync.Mutex
+	store   NotificationStore   // store keeps every notification, nil for this session only.
+	recent  []NotificationEntry // recent are the latest notifications, oldest first.
+	skipped int                 // skipped is how many entries of the store couldn't be read.
+}
+
+// NewNotificationLog creates a log which keeps the notifications of this session only, until it
+// is given a store with KeepIn.
+func NewNotificationLog() *NotificationLog {
+	return &NotificationLog{mutex: sync.Mutex{}, store: nil, recent: nil, skipped: 0}
+}
+
+// KeepIn keeps every further notification in the given store, and puts its latest notifications
+// before those of this session. Entries of the store which can't be read, e.g. a line cut off by
+// a crash, are skipped and counted by Skipped.
+func (l *NotificationLog) KeepIn(store NotificationStore) error {
+	stored, skipped, err := store.LoadNotifications(recentNotifications)
+	if err != nil {
+		return fmt.Errorf("NotificationLog.KeepIn: %w", err)
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.store = store
+	l.skipped = skipped
+	l.recent = append(stored, l.recent...)
+	if len(l.recent) > recentNotifications {
+		l.recent = l.recent[len(l.recent)-recentNotifications:]
+	}
+	return nil
+}
+
+func (l *NotificationLog) Emit(event Event) error {
+	entry := newNotificationEntry(event)
+	l.mutex.Lock()
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > recentNotifications {
+		l.recent = l.recent[len(l.recent)-recentNotifications:]
+	}
+	store := l.store
+	l.mutex.Unlock()
+
+	if store == nil {
+		return nil
+	}
+	if err := store.AppendNotification(entry); err != nil {
+		return fmt.Errorf("NotificationLog.Emit: %w", err)
+	}
+	return nil
+}
+
+func (l *NotificationLog) Name() string { return "notification log" }
+
+// Recent returns the latest notifications, newest first.
+func (l *NotificationLog) Recent() []NotificationEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	recent := make([]NotificationEntry, 0, len(l.recent))
+	for idx := len(l.recent) - 1; idx >= 0; idx-- {
+		recent = append(recent, l.recent[idx])
+	}
+	return recent
+}
+
+// Skipped returns how many entries of the store were skipped since they couldn't be read.
+func (l *NotificationLog) Skipped() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.skipped
+}
+
+// hasStore tells whether the notifications are kept in a store, rather than for this session only.
+func (l *NotificationLog) hasStore() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.store != nil
+}
+
+// NotificationFile keeps notifications as newline-delimited JSON, one entry per line.
+type NotificationFile struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewNotificationFile creates a NotificationFile which reads from and appends to the file at the
+// given path, which is created with the first notification.
+func NewNotificationFile(path string) *NotificationFile {
+	return &NotificationFile{path: path, mutex: sync.Mutex{}}
+}
+
+// AppendNotification adds the notification as a line to the file.
+func (f *NotificationFile) AppendNotification(entry NotificationEntry) error {
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return fmt.Errorf("NotificationFile.AppendNotification: failed to encode entry: %w", marshalErr)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	file, openErr := openAppend(f.path)
+	if openErr != nil {
+		return fmt.Errorf("NotificationFile.AppendNotification: failed to open %s: %w", f.path, openErr)
+	}
+	_, writeErr := file.Write(append(line, '\n'))
+	closeErr := file.Close()
+	if writeErr != nil {
+		return fmt.Errorf("NotificationFile.AppendNotification: failed to write %s: %w", f.path, writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("NotificationFile.AppendNotification: failed to close %s: %w", f.path, closeErr)
+	}
+	return nil
+}
+
+// LoadNotifications reads the last notifications of the file, oldest first, at most limit of them.
+// Lines which aren't a notification, like one cut off by a crash, are skipped and counted. A
+// missing file is not an error, there just weren't any notifications yet.
+func (f *NotificationFile) LoadNotifications(limit int) ([]NotificationEntry, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	file, openErr := os.Open(f.path)
+	if errors.Is(openErr, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if openErr != nil {
+		return nil, 0, fmt.Errorf("NotificationFile.LoadNotifications: failed to open %s: %w", f.path, openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries []NotificationEntry
+	skipped := 0
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry NotificationEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				skipped++
+			} else {
+				entries = append(entries, entry)
+			}
+			if len(entries) > 2*limit {
+				entries = append(entries[:0], entries[len(entries)-limit:]...)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, skipped, fmt.Errorf("NotificationFile.LoadNotifications: failed to read %s: %w", f.path, readErr)
+		}
+	}
+	return entries[max(0, len(entries)-limit):], skipped, nil
+}
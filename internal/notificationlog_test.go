@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotificationLog(t *testing.T) {
+	log := NewNotificationLog()
+	if recent := log.Recent(); len(recent) != 0 {
+		t.Errorf("Recent() = %+v, expected nothing before the first notification", recent)
+	}
+
+	seen := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	//nolint:exhaustruct // only what is logged
+	sighting := &AircraftSighting{lastFlightNo: "GAF681", registration: "10+21", typeDesc: "Airbus A310"}
+	events := []Event{
+		{Kind: EventKindRarity, Title: "Rare type", Body: "GAF681", Time: seen, Sighting: sighting},    //nolint:exhaustruct // no change
+		{Kind: EventKindFeed, Title: "Feed stalled", Body: "no aircraft", Time: seen.Add(time.Minute)}, //nolint:exhaustruct // no aircraft
+		{Kind: EventKindPeak, Title: "New peak", Body: "42 aircraft", Time: seen.Add(2 * time.Minute)}, //nolint:exhaustruct // no aircraft
+	}
+
+	// Without a store, the notifications are kept for this session only.
+	if err := log.Emit(events[0]); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	if err := log.KeepIn(NewNotificationFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events[1:] {
+		if err := log.Emit(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recent := log.Recent()
+	if len(recent) != 3 || recent[0].Title != "New peak" || recent[2].Aircraft() != "GAF681 Airbus A310 (10+21)" {
+		t.Errorf("Recent() = %+v, expected the newest first", recent)
+	}
+
+	// The notifications after KeepIn outlive the session.
+	reopened := NewNotificationLog()
+	if err := reopened.KeepIn(NewNotificationFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	kept := reopened.Recent()
+	if len(kept) != 2 || kept[0].Title != "New peak" || kept[1].Aircraft() != "" {
+		t.Errorf("reopened Recent() = %+v, expected the last two, newest first", kept)
+	}
+}
+
+func TestNotificationFileSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	lines := `{"time":"2026-10-18T12:00:00Z","kind":"rarity","title":"Rare type","body":"GAF681"}
+not a notification
+
+{"time":"2026-10-18T12:01:00Z","kind":"feed","title":"Feed stalled","body":"no aircraft"}
+{"time":"2026-10-18T12:02:00Z","kind":"peak","title":"New pe`
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, skipped, err := NewNotificationFile(path).LoadNotifications(recentNotifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Title != "Rare type" || entries[1].Title != "Feed stalled" || skipped != 2 {
+		t.Errorf("LoadNotifications() = %+v, %d skipped, expected both notifications and 2 skipped", entries, skipped)
+	}
+
+	// The log opens nonetheless, and tells about the skipped lines.
+	log := NewNotificationLog()
+	if keepErr := log.KeepIn(NewNotificationFile(path)); keepErr != nil {
+		t.Fatal(keepErr)
+	}
+	if len(log.Recent()) != 2 || log.Skipped() != 2 {
+		t.Errorf("Recent() = %+v, Skipped() = %d, expected 2 of each", log.Recent(), log.Skipped())
+	}
+}
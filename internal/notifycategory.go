@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// NotificationCategory is a kind of desktop notification which can be switched off on its own.
+type NotificationCategory string
+
+const (
+	NotifyRareType     NotificationCategory = "rare_type"
+	NotifyRareOperator NotificationCategory = "rare_operator"
+	NotifyRareCountry  NotificationCategory = "rare_country"
+	// NotifyEmergency is an aircraft squawking an emergency code.
+	NotifyEmergency NotificationCategory = "emergency"
+	// NotifyWatchlist is an aircraft of the watchlist or a favourite showing up again.
+	NotifyWatchlist NotificationCategory = "watchlist"
+	// NotifyRecord is a new all-time peak of aircraft visible at once, if peak alerts are enabled.
+	NotifyRecord NotificationCategory = "record"
+	// NotifySupersonic is a supersonic-capable aircraft exceeding the Mach threshold.
+	NotifySupersonic NotificationCategory = "supersonic"
+	// NotifyFollow is a change of the aircraft followed with --follow, e.g. its landing.
+	NotifyFollow NotificationCategory = "follow"
+	// NotifyAchievement is an achievement unlocked by a sighting, e.g. 100 different types.
+	NotifyAchievement NotificationCategory = "achievement"
+	// NotifyNotable is a notable aircraft of the dataset, e.g. a head-of-state transport.
+	NotifyNotable NotificationCategory = "notable"
+	// NotifyCircling is an aircraft flying circles or a holding pattern near our location.
+	NotifyCircling NotificationCategory = "circling"
+	// NotifyGoAround is a go-around at a nearby airport, if go-around alerts are enabled.
+	NotifyGoAround NotificationCategory = "go_around"
+)
+
+// NotificationCategories lists all categories of desktop notifications, in the order they are
+// shown in the settings.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var NotificationCategories = []NotificationCategory{
+	NotifyRareType,
+	NotifyRareOperator,
+	NotifyRareCountry,
+	NotifyEmergency,
+	NotifyWatchlist,
+	NotifyRecord,
+	NotifySupersonic,
+	NotifyFollow,
+	NotifyAchievement,
+	NotifyNotable,
+	NotifyCircling,
+	NotifyGoAround,
+}
+
+// Label is the name of the category for the settings, e.g. "Rare operator".
+func (category NotificationCategory) Label() string {
+	switch category {
+	case NotifyRareType:
+		return "Rare type"
+	case NotifyRareOperator:
+		return "Rare operator"
+	case NotifyRareCountry:
+		return "Rare country"
+	case NotifyEmergency:
+		return "Emergency squawk"
+	case NotifyWatchlist:
+		return "Watchlist and favourites"
+	case NotifyRecord:
+		return "Record broken"
+	case NotifySupersonic:
+		return "Supersonic"
+	case NotifyFollow:
+		return "Followed aircraft"
+	case NotifyAchievement:
+		return "Achievements"
+	case NotifyNotable:
+		return "Notable aircraft"
+	case NotifyCircling:
+		return "Circling aircraft"
+	case NotifyGoAround:
+		return "Go-arounds"
+	}
+	return string(category)
+}
+
+// NotificationsConfig switches categories of desktop notifications on or off, e.g.
+//
+//	"notifications": {"rare_country": false, "record": false}
+//
+// Categories which are left out are on. The events are still sent to the other sinks.
+type NotificationsConfig map[NotificationCategory]bool
+
+func (c NotificationsConfig) validate() error {
+	for category := range c {
+		if !slices.Contains(NotificationCategories, category) {
+			return fmt.Errorf("%w: unknown notification category %q", errInvalidConfig, category)
+		}
+	}
+	return nil
+}
+
+// rarityCategories are the categories of a rare sighting, e.g. type and operator for a rare type
+// flown by a rare operator.
+func rarityCategories(rarities RarityFlag) []NotificationCategory {
+	var categories []NotificationCategory
+	if rarities&RareType != 0 {
+		categories = append(categories, NotifyRareType)
+	}
+	if rarities&RareOperator != 0 {
+		categories = append(categories, NotifyRareOperator)
+	}
+	if rarities&RareCountry != 0 {
+		categories = append(categories, NotifyRareCountry)
+	}
+	return categories
+}
+
+// IsNotifying tells whether desktop notifications of the category are on.
+func (notify *Notify) IsNotifying(category NotificationCategory) bool {
+	return !notify.desktopOff[category]
+}
+
+// ToggleNotifying switches desktop notifications of the category on or off, for this session.
+func (notify *Notify) ToggleNotifying(category NotificationCategory) {
+	notify.desktopOff[category] = notify.IsNotifying(category)
+}
+
+// alertSinks are the sinks to alert of an event of the given categories: all enabled sinks, but
+// without the desktop notification if none of the categories is on.
+func (notify *Notify) alertSinks(categories ...NotificationCategory) []EventSink {
+	if slices.ContainsFunc(categories, notify.IsNotifying) {
+		return notify.sinks
+	}
+	return withoutDesktop(notify.sinks)
+}
+
+// withoutDesktop returns the sinks without the desktop sink.
+func withoutDesktop(sinks []EventSink) []EventSink {
+	return slices.DeleteFunc(slices.Clone(sinks), func(sink EventSink) bool {
+		_, isDesktop := sink.(*DesktopSink)
+		return isDesktop
+	})
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"io"
+	"testing"
+)
+
+func TestAlertSinksByCategory(t *testing.T) {
+	disabled := false
+	opts := NotifyOptions{ //nolint:exhaustruct // only the sinks and the categories matter
+		Sinks:         SinksConfig{Desktop: SinkConfig{Enabled: &disabled}}, //nolint:exhaustruct // desktop off
+		Notifications: NotificationsConfig{NotifyRareCountry: false, NotifyEmergency: true},
+	}
+	notify, err := NewNotify("test", opts, nil, io.Discard)
+	if err != nil {
+		t.Fatalf("NewNotify() error = %v", err)
+	}
+	// The desktop sink is only inspected, never emitted to. The notification log is left out.
+	notify.sinks = []EventSink{&recordingSink{events: nil}, &DesktopSink{}}
+
+	tests := []struct {
+		name       string
+		categories []NotificationCategory
+		expected   int
+	}{
+		{name: "on by default", categories: []NotificationCategory{NotifyRareType}, expected: 2},
+		{name: "switched on", categories: []NotificationCategory{NotifyEmergency}, expected: 2},
+		{name: "switched off", categories: []NotificationCategory{NotifyRareCountry}, expected: 1},
+		{
+			name:       "any category on",
+			categories: rarityCategories(RareTypeAndCountry),
+			expected:   2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if sinks := notify.alertSinks(test.categories...); len(sinks) != test.expected {
+				t.Errorf("alertSinks(%v) = %d sinks, expected %d", test.categories, len(sinks), test.expected)
+			}
+		})
+	}
+
+	notify.ToggleNotifying(NotifyRareCountry)
+	notify.ToggleNotifying(NotifyRareType)
+	if !notify.IsNotifying(NotifyRareCountry) || notify.IsNotifying(NotifyRareType) {
+		t.Error("ToggleNotifying() didn't switch the categories")
+	}
+	if len(notify.sinks) != 2 {
+		t.Errorf("alertSinks() changed the sinks to %d", len(notify.sinks))
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OperatorGroups roll up operators to the brand they belong to, so that e.g. regional
+// subsidiaries don't fragment the statistics. They map the name of each group to the names of its
+// operators, as shown in the TUI:
+//
+//	"operator_groups": {"Lufthansa Group": ["Lufthansa", "Lufthansa CityLine", "Eurowings"]}
+type OperatorGroups map[string][]string
+
+func (groups OperatorGroups) validate() error {
+	seen := make(map[string]string)
+	for group, operators := range groups {
+		if group == "" {
+			return fmt.Errorf("%w: operator group names must not be empty", errInvalidConfig)
+		}
+		for _, operator := range operators {
+			key := strings.ToLower(operator)
+			if other, ok := seen[key]; ok && other != group {
+				return fmt.Errorf("%w: operator %q is in the groups %q and %q",
+					errInvalidConfig, operator, other, group)
+			}
+			seen[key] = group
+		}
+	}
+	return nil
+}
+
+// parents maps the operators, in lower case, to their group.
+func (groups OperatorGroups) parents() map[string]string {
+	parents := make(map[string]string)
+	for group, operators := range groups {
+		for _, operator := range operators {
+			parents[strings.ToLower(operator)] = group
+		}
+	}
+	return parents
+}
+
+// OperatorGroup returns the group of the operator, or the operator itself if it is in none.
+func (db *Dashboard) OperatorGroup(operator string) string {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return db.operatorGroup(operator)
+}
+
+// operatorGroup is OperatorGroup, which requires the dataset mutex.
+func (db *Dashboard) operatorGroup(operator string) string {
+	if group, ok := db.operatorParents[strings.ToLower(operator)]; ok {
+		return group
+	}
+	return operator
+}
+
+// GroupedOperatorCount rolls up the seen-counts of the operators to their groups. Operators in no
+// group are counted on their own.
+func (db *Dashboard) GroupedOperatorCount() map[string]int {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return rollUpCounts(db.SeenOperatorCount, db.operatorGroup)
+}
+
+// GroupedFirstSeen rolls up when the operators were first seen to their groups, which were first
+// seen with their earliest operator.
+func (db *Dashboard) GroupedFirstSeen(firstSeen map[string]time.Time) map[string]time.Time {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return rollUpFirstSeen(firstSeen, db.operatorGroup)
+}
+
+// rollUpCounts sums up the counts or weights of the properties by their group.
+func rollUpCounts[N int | float64](counts map[string]N, groupOf func(string) string) map[string]N {
+	grouped := make(map[string]N, len(counts))
+	for property, count := range counts {
+		grouped[groupOf(property)] += count
+	}
+	return grouped
+}
+
+// rollUpFirstSeen keeps when each group was first seen, which is with its earliest property.
+func rollUpFirstSeen(firstSeen map[string]time.Time, groupOf func(string) string) map[string]time.Time {
+	grouped := make(map[string]time.Time, len(firstSeen))
+	for property, seen := range firstSeen {
+		group := groupOf(property)
+		if earliest, ok := grouped[group]; !ok || seen.Before(earliest) {
+			grouped[group] = seen
+		}
+	}
+	return grouped
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGroupedOperatorCount(t *testing.T) {
+	groups := OperatorGroups{"Lufthansa Group": {"Lufthansa", "Lufthansa CityLine", "Eurowings"}}
+	dashboard := &Dashboard{ //nolint:exhaustruct // operator counts only
+		SeenOperatorCount: map[string]int{"Lufthansa": 5, "Lufthansa Cityline": 2, "Ryanair": 4},
+		operatorParents:   groups.parents(),
+	}
+
+	grouped := dashboard.GroupedOperatorCount()
+	if len(grouped) != 2 || grouped["Lufthansa Group"] != 7 || grouped["Ryanair"] != 4 {
+		t.Errorf("GroupedOperatorCount() = %v, expected Lufthansa Group 7 and Ryanair 4", grouped)
+	}
+	if group := dashboard.OperatorGroup("Eurowings"); group != "Lufthansa Group" {
+		t.Errorf("OperatorGroup(Eurowings) = %s, expected Lufthansa Group", group)
+	}
+	if group := dashboard.OperatorGroup("Ryanair"); group != "Ryanair" {
+		t.Errorf("OperatorGroup(Ryanair) = %s, expected Ryanair itself", group)
+	}
+
+	early := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	firstSeen := dashboard.GroupedFirstSeen(map[string]time.Time{
+		"Lufthansa": early.AddDate(0, 0, 3),
+		"Eurowings": early,
+	})
+	if !firstSeen["Lufthansa Group"].Equal(early) {
+		t.Errorf("GroupedFirstSeen() = %v, expected the earliest operator", firstSeen)
+	}
+}
+
+func TestLoadConfigRejectsOverlappingOperatorGroups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airspottr.json")
+	content := `{"operator_groups": {"IAG": ["British Airways", "Vueling"], "Other": ["vueling"]}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("LoadConfig() accepted an operator in two groups")
+	}
+}
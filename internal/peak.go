@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// PeakFileName is the name of the file in the data directory of the user which the all-time peak is
+// kept in if no other path is given.
+const PeakFileName = "peak.json"
+
+// Peak is the most aircraft that were visible at the same time, and when.
+type Peak struct {
+	Aircraft int       `json:"aircraft"`
+	Time     time.Time `json:"time"`
+}
+
+// PeakRecord is a new all-time peak together with the peak it beat.
+type PeakRecord struct {
+	Peak     Peak
+	Previous Peak
+}
+
+// PeakStats tracks the peaks of concurrently visible aircraft of the session and of all time.
+// The all-time peak is persisted as a small JSON file when it is beaten, at most once per
+// saveInterval and on closing.
+type PeakStats struct {
+	path     string // path is where the all-time peak is persisted, empty keeps it for the session.
+	session  Peak
+	allTime  Peak
+	schedule saveSchedule
+}
+
+// LoadPeakStats reads the all-time peak from the file at the given path.
+// A missing file is not an error, it simply means that no peak has been set yet.
+func LoadPeakStats(path string) (*PeakStats, error) {
+	stats := &PeakStats{
+		path:     path,
+		session:  Peak{Aircraft: 0, Time: time.Time{}},
+		allTime:  Peak{Aircraft: 0, Time: time.Time{}},
+		schedule: saveSchedule{unsaved: false, saved: time.Time{}},
+	}
+	if path == "" {
+		return stats, nil
+	}
+
+	data, readErr := os.ReadFile(path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return stats, nil
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("LoadPeakStats: failed to read %s: %w", path, readErr)
+	}
+	if err := json.Unmarshal(data, &stats.allTime); err != nil {
+		return nil, fmt.Errorf("LoadPeakStats: failed to unmarshal %s: %w", path, err)
+	}
+	return stats, nil
+}
+
+// Session returns the peak of this session.
+func (ps *PeakStats) Session() Peak {
+	return ps.session
+}
+
+// AllTime returns the peak of all sessions, including this one.
+func (ps *PeakStats) AllTime() Peak {
+	return ps.allTime
+}
+
+// Record counts the aircraft visible at the given time and persists a new all-time peak once it is
+// due to be saved.
+// It returns the record and true if an all-time peak was beaten, false otherwise and for the very
+// first peak.
+func (ps *PeakStats) Record(now time.Time, aircraft int) (PeakRecord, bool, error) {
+	if aircraft > ps.session.Aircraft {
+		ps.session = Peak{Aircraft: aircraft, Time: now.UTC()}
+	}
+	record := PeakRecord{Peak: Peak{Aircraft: aircraft, Time: now.UTC()}, Previous: ps.allTime}
+	beaten := aircraft > ps.allTime.Aircraft
+	if beaten {
+		ps.allTime = record.Peak
+	}
+	isRecord := beaten && record.Previous.Aircraft > 0
+	if !ps.schedule.due(now, beaten) {
+		return record, isRecord, nil
+	}
+
+	saveErr := ps.save()
+	ps.schedule.done(now, saveErr)
+	if saveErr != nil {
+		return record, isRecord, fmt.Errorf("PeakStats.Record: %w", saveErr)
+	}
+	return record, isRecord, nil
+}
+
+// Flush persists the all-time peak if it was beaten since it was saved last, e.g. on quitting.
+func (ps *PeakStats) Flush() error {
+	if !ps.schedule.unsaved {
+		return nil
+	}
+	if err := ps.save(); err != nil {
+		return fmt.Errorf("PeakStats.Flush: %w", err)
+	}
+	ps.schedule.unsaved = false
+	return nil
+}
+
+// save writes the all-time peak to a temporary file first so that a failed write can't destroy
+// the existing peak.
+func (ps *PeakStats) save() error {
+	if ps.path == "" {
+		return nil
+	}
+
+	data, marshalErr := json.MarshalIndent(ps.allTime, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("save: %w", marshalErr)
+	}
+
+	if err := replaceFile(ps.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
+
+// peakEvent celebrates a new all-time peak of concurrently visible aircraft.
+func peakEvent(lang i18n.Language, record PeakRecord, display TimeDisplay) Event {
+	previous := lang.Sprintf("%d on %s %s",
+		record.Previous.Aircraft,
+		record.Previous.Time.In(display.Location()).Format(time.DateOnly),
+		display.Format(record.Previous.Time))
+	return Event{
+		Kind:     EventKindPeak,
+		Severity: SeverityInfo,
+		Title:    lang.T("New peak of aircraft"),
+		Body:     lang.Sprintf("%d aircraft visible at once\nprevious peak: %s", record.Peak.Aircraft, previous),
+		Summary:  lang.Sprintf("NEW PEAK: %d aircraft visible at once, previous peak %s", record.Peak.Aircraft, previous),
+		Time:     record.Peak.Time,
+		Sighting: nil,
+		Change:   nil,
+		Movement: nil,
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestPeakStatsRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peak.json")
+	start := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	peaks, err := LoadPeakStats(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := []struct {
+		aircraft int
+		beaten   bool
+	}{
+		{aircraft: 10, beaten: false}, // the very first peak isn't a record
+		{aircraft: 8, beaten: false},
+		{aircraft: 12, beaten: true},
+		{aircraft: 12, beaten: false},
+	}
+	for idx, count := range counts {
+		record, beaten, recordErr := peaks.Record(start.Add(time.Duration(idx)*time.Minute), count.aircraft)
+		if recordErr != nil {
+			t.Fatal(recordErr)
+		}
+		if beaten != count.beaten {
+			t.Errorf("Record(%d) = %v, want beaten %v", count.aircraft, beaten, count.beaten)
+		}
+		if beaten && record.Previous.Aircraft != 10 {
+			t.Errorf("Record(%d) beat %d, want 10", count.aircraft, record.Previous.Aircraft)
+		}
+	}
+
+	// The next session starts with the all-time peak, but a session peak of its own.
+	next, loadErr := LoadPeakStats(path)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if next.AllTime().Aircraft != 12 || !next.AllTime().Time.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("AllTime() = %+v, want 12 aircraft at the third poll", next.AllTime())
+	}
+	if next.Session().Aircraft != 0 {
+		t.Errorf("Session() = %+v, want no peak yet", next.Session())
+	}
+
+	_, beaten, recordErr := next.Record(start.Add(time.Hour), 11)
+	if recordErr != nil || beaten {
+		t.Errorf("Record(11) = %v, %v, want no record", beaten, recordErr)
+	}
+	if next.Session().Aircraft != 11 {
+		t.Errorf("Session() = %+v, want 11 aircraft", next.Session())
+	}
+}
+
+func TestPeakEvent(t *testing.T) {
+	display, err := NewTimeDisplay("utc", DefaultTimeFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := PeakRecord{
+		Peak:     Peak{Aircraft: 57, Time: time.Date(2025, time.June, 2, 18, 4, 0, 0, time.UTC)},
+		Previous: Peak{Aircraft: 42, Time: time.Date(2025, time.May, 30, 17, 30, 0, 0, time.UTC)},
+	}
+
+	event := peakEvent(i18n.English, record, display)
+	if event.Kind != EventKindPeak {
+		t.Errorf("Kind = %q, want %q", event.Kind, EventKindPeak)
+	}
+	want := "NEW PEAK: 57 aircraft visible at once, previous peak 42 on 2025-05-30 17:30:00"
+	if event.Summary != want {
+		t.Errorf("Summary = %q, want %q", event.Summary, want)
+	}
+	if !strings.Contains(event.Body, "57 aircraft") {
+		t.Errorf("Body = %q, want the new peak", event.Body)
+	}
+}
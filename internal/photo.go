@@ -0,0 +1,46 @@
+package internal
+
+// See https://www.planespotters.net/photo/api for the photo API definition.
+
+// PhotoResponse reflects the JSON received from calls to the planespotters.net public API.
+type PhotoResponse struct {
+	Photos []PhotoRecord `json:"photos"`
+}
+
+// PhotoRecord reflects a single photo contained in the PhotoResponse.
+type PhotoRecord struct {
+	ID             string         `json:"id"`
+	Thumbnail      ThumbnailImage `json:"thumbnail"`
+	ThumbnailLarge ThumbnailImage `json:"thumbnail_large"`
+	Link           string         `json:"link"`
+	Photographer   string         `json:"photographer"`
+}
+
+// ThumbnailImage reflects the thumbnail data within PhotoRecord.
+type ThumbnailImage struct {
+	Src  string        `json:"src"`
+	Size ThumbnailSize `json:"size"`
+}
+
+// ThumbnailSize is the size of a thumbnail image in [pixels].
+type ThumbnailSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// GetDefaultPhotoRecord returns a placeholder for registrations without any photo.
+// It is cached just like a real photo to avoid requesting the same registration over and over.
+func GetDefaultPhotoRecord() *PhotoRecord {
+	return &PhotoRecord{
+		ID:             "",
+		Thumbnail:      ThumbnailImage{Src: "", Size: ThumbnailSize{Width: 0, Height: 0}},
+		ThumbnailLarge: ThumbnailImage{Src: "", Size: ThumbnailSize{Width: 0, Height: 0}},
+		Link:           "",
+		Photographer:   "",
+	}
+}
+
+// HasLink returns true if the photo record points to an actual photo.
+func (photo *PhotoRecord) HasLink() bool {
+	return photo != nil && photo.Link != ""
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRegistrationsWithoutPhoto(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC))
+	cached := &PhotoRecord{ //nolint:exhaustruct // link only
+		Link: "https://www.planespotters.net/photo/1",
+	}
+	withPhoto := &AircraftSighting{registration: "D-AIBD"} //nolint:exhaustruct // registration only
+
+	dashboard := &Dashboard{ //nolint:exhaustruct // photos and rare sightings only
+		clock:        clock,
+		CachedPhotos: map[string]*PhotoRecord{"D-AIBD": cached},
+		failedPhotos: make(map[string]time.Time),
+		RareSightings: []RareSighting{
+			{Rarities: RareType, Sighting: withPhoto},
+			{Rarities: RareType, Sighting: &AircraftSighting{registration: "HB-JNA"}},     //nolint:exhaustruct // reg
+			{Rarities: RareOperator, Sighting: &AircraftSighting{registration: "HB-JNA"}}, //nolint:exhaustruct // reg
+			{Rarities: RareCountry, Sighting: &AircraftSighting{registration: ""}},        //nolint:exhaustruct // none
+		},
+	}
+
+	registrations := dashboard.RegistrationsWithoutPhoto()
+	if !slices.Equal(registrations, []string{"HB-JNA"}) {
+		t.Errorf("RegistrationsWithoutPhoto() = %v, expected [HB-JNA]", registrations)
+	}
+	if withPhoto.photo != nil {
+		t.Error("RegistrationsWithoutPhoto() assigned the cached photo, expected it to leave the sightings alone")
+	}
+	dashboard.AssignCachedPhotos()
+	if withPhoto.photo != cached {
+		t.Errorf("AssignCachedPhotos() assigned %v, expected the cached photo", withPhoto.photo)
+	}
+
+	// The lookup failed, so it isn't retried for a while.
+	dashboard.AssignPhotos(registrations, map[string]PhotoRecord{})
+	if registrations := dashboard.RegistrationsWithoutPhoto(); len(registrations) != 0 {
+		t.Errorf("RegistrationsWithoutPhoto() = %v right after the lookup failed, expected none", registrations)
+	}
+	clock.Advance(photoRetryAfter)
+	if registrations := dashboard.RegistrationsWithoutPhoto(); !slices.Equal(registrations, []string{"HB-JNA"}) {
+		t.Errorf("RegistrationsWithoutPhoto() = %v once the retry is due, expected [HB-JNA]", registrations)
+	}
+
+	dashboard.AssignPhotos(registrations, map[string]PhotoRecord{"HB-JNA": *GetDefaultPhotoRecord()})
+	if registrations := dashboard.RegistrationsWithoutPhoto(); len(registrations) != 0 {
+		t.Errorf("RegistrationsWithoutPhoto() = %v once looked up, expected none", registrations)
+	}
+	if len(dashboard.failedPhotos) != 0 {
+		t.Errorf("failed lookups %v are still kept, expected none", dashboard.failedPhotos)
+	}
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const (
+	// photoImageHost serves the thumbnails of the photos found on planespotters.net.
+	photoImageHost = "t.plnspttrs.net"
+
+	photoFileExt      = ".jpg"
+	photoCacheDirPerm = 0o700
+	photoFilePerm     = 0o600
+)
+
+var (
+	errNoPhotoImage      = errors.New("no photo image")
+	photoFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9-]`)
+)
+
+// DefaultPhotoCacheDir returns where downloaded photos are kept, empty if there's no cache
+// directory for the user.
+func DefaultPhotoCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "airspottr", "photos")
+}
+
+// RequestPhotoImage returns the thumbnail of the photo of the given registration, as JPEG.
+// Thumbnails are kept in the PhotoCacheDir, so that each is only downloaded once.
+func (r *Request) RequestPhotoImage(registration string, photo *PhotoRecord) ([]byte, error) {
+	if !photo.HasLink() || photo.Thumbnail.Src == "" {
+		return nil, fmt.Errorf("RequestPhotoImage: %w for %s", errNoPhotoImage, registration)
+	}
+
+	cachePath := r.photoCachePath(registration)
+	if cachePath != "" {
+		if cached, err := os.ReadFile(cachePath); err == nil {
+			return cached, nil
+		}
+	}
+
+	imageURL, urlErr := validatePhotoImageURL(photo.Thumbnail.Src)
+	if urlErr != nil {
+		return nil, fmt.Errorf("RequestPhotoImage: %w", urlErr)
+	}
+	image, reqErr := r.fetch(r.apiClient, imageURL, nil)
+	if reqErr != nil {
+		return nil, fmt.Errorf("RequestPhotoImage: %w", reqErr)
+	}
+
+	if cachePath != "" {
+		if err := writePhotoCache(cachePath, image); err != nil {
+			r.errOut.Println(fmt.Errorf("RequestPhotoImage: %w", err))
+		}
+	}
+	return image, nil
+}
+
+// photoCachePath returns where the photo of the registration is kept, empty if there's no cache.
+func (r *Request) photoCachePath(registration string) string {
+	if r.opts.PhotoCacheDir == "" || registration == "" {
+		return ""
+	}
+	name := photoFileNamePattern.ReplaceAllString(registration, "_")
+	return filepath.Join(r.opts.PhotoCacheDir, name+photoFileExt)
+}
+
+func writePhotoCache(path string, image []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), photoCacheDirPerm); err != nil {
+		return fmt.Errorf("writePhotoCache: %w", err)
+	}
+	if err := os.WriteFile(path, image, photoFilePerm); err != nil {
+		return fmt.Errorf("writePhotoCache: %w", err)
+	}
+	return nil
+}
+
+// validatePhotoImageURL only lets thumbnails of planespotters.net through, since their URLs are
+// taken from the API response.
+func validatePhotoImageURL(imageURL string) (string, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil || parsed.Scheme != "https" {
+		return "", ErrInvalidURL
+	}
+	if parsed.Host != photoImageHost {
+		return "", ErrUnauthorizedHost
+	}
+	return parsed.String(), nil
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidatePhotoImageURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected error
+	}{
+		{"https://t.plnspttrs.net/12345/1234567_abcdef_t.jpg", nil},
+		{"http://t.plnspttrs.net/12345/1234567_abcdef_t.jpg", ErrInvalidURL},
+		{"https://example.com/photo.jpg", ErrUnauthorizedHost},
+	}
+	for _, tt := range tests {
+		if _, err := validatePhotoImageURL(tt.url); !errors.Is(err, tt.expected) {
+			t.Errorf("validatePhotoImageURL(%q) error = %v, expected %v", tt.url, err, tt.expected)
+		}
+	}
+}
+
+func TestRequestPhotoImageCached(t *testing.T) {
+	cacheDir := t.TempDir()
+	opts := testRequestOptions(0, 0)
+	opts.PhotoCacheDir = cacheDir
+	var stderr io.Writer = io.Discard
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	photo := PhotoRecord{
+		ID:             "1",
+		Thumbnail:      ThumbnailImage{Src: "https://t.plnspttrs.net/1/1_t.jpg", Size: ThumbnailSize{Width: 200, Height: 133}},
+		ThumbnailLarge: ThumbnailImage{Src: "", Size: ThumbnailSize{Width: 0, Height: 0}},
+		Link:           "https://www.planespotters.net/photo/1",
+		Photographer:   "someone",
+	}
+	// Registrations are made safe to be file names.
+	cached := []byte("cached image")
+	if err := os.WriteFile(filepath.Join(cacheDir, "D-AI_A.jpg"), cached, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	image, reqErr := request.RequestPhotoImage("D-AI/A", &photo)
+	if reqErr != nil || string(image) != string(cached) {
+		t.Errorf("RequestPhotoImage() = %q, %v, expected the cached image", image, reqErr)
+	}
+
+	if _, err := request.RequestPhotoImage("D-AIXA", GetDefaultPhotoRecord()); !errors.Is(err, errNoPhotoImage) {
+		t.Errorf("RequestPhotoImage() error = %v, expected %v", err, errNoPhotoImage)
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"log" //nolint:depguard // Don't feel like using slog
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pollWorkers bounds how many responses are requested and decoded at once, e.g. of the sources
+// of a poll or of the flight routes of new callsigns, so that load spikes don't hold many large
+// responses in memory at the same time.
+const pollWorkers = 4
+
+// processBounded runs the work on every job, on at most the given number of workers at once. The
+// results are sent over the returned channel in the order they are done, which is closed once all
+// jobs are. The workers wait for their results to be taken rather than piling them up.
+func processBounded[J any, R any](jobs []J, workers int, work func(J) R) <-chan R {
+	queue := make(chan J)
+	results := make(chan R, workers)
+	var waitGroup sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		waitGroup.Go(func() {
+			for job := range queue {
+				results <- work(job)
+			}
+		})
+	}
+	go func() {
+		for _, job := range jobs {
+			queue <- job
+		}
+		close(queue)
+		waitGroup.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// PollResult is the outcome of a poll of the sources, handed over as a whole so that its aircraft
+// are never processed with the error of another poll.
+type PollResult struct {
+	Aircraft     []AircraftRecord // Aircraft are the fused aircraft of all sources.
+	Err          error            // Err is the error of the poll, nil if any source could be reached.
+	FailingSince time.Time        // FailingSince is when the polls started to fail, zero unless this one did.
+}
+
+// AircraftFeed polls the sources in the background and hands the aircraft over to the dashboard
+// through a channel, so that neither the TUI nor the ticker waits for the sources. The dashboard
+// itself is only touched by its owner, which processes the polls it takes from the channel.
+// A poll is skipped while the previous one is still running, and aircraft which haven't been
+// taken by the time the next poll is done are replaced by the newer ones. That way a slow
+// dashboard has at most one poll waiting for it, however long it falls behind.
+type AircraftFeed struct {
+	request *Request
+	polls   chan PollResult
+	running atomic.Bool
+	skipped atomic.Int64 // skipped counts the polls skipped since the previous one still ran.
+	dropped atomic.Int64 // dropped counts the polls replaced before the dashboard took them.
+	errOut  *log.Logger
+}
+
+// NewAircraftFeed creates a feed of the aircraft requested by the given request.
+func NewAircraftFeed(request *Request) *AircraftFeed {
+	return &AircraftFeed{
+		request: request,
+		polls:   make(chan PollResult, 1),
+		running: atomic.Bool{},
+		skipped: atomic.Int64{},
+		dropped: atomic.Int64{},
+		errOut:  &request.errOut,
+	}
+}
+
+// Poll starts a poll in the background, whose result arrives on Polls. It returns false if the
+// poll was skipped, since the previous one is still running.
+func (f *AircraftFeed) Poll() bool {
+	if !f.running.CompareAndSwap(false, true) {
+		f.skipped.Add(1)
+		f.errOut.Println("AircraftFeed: poll skipped, the previous one is still running")
+		return false
+	}
+	go func() {
+		f.deliver(f.request.poll())
+		f.running.Store(false)
+	}()
+	return true
+}
+
+// deliver hands the result of the poll over, in place of that of the previous poll if it is still
+// waiting. Only one poll runs at a time, so nothing else sends in the meantime.
+func (f *AircraftFeed) deliver(result PollResult) {
+	select {
+	case f.polls <- result:
+		return
+	default:
+	}
+	select {
+	case <-f.polls:
+		f.dropped.Add(1)
+		f.errOut.Println("AircraftFeed: poll dropped, the dashboard didn't take it in time")
+	default:
+	}
+	f.polls <- result
+}
+
+// Polls returns the channel over which the result of every poll arrives.
+func (f *AircraftFeed) Polls() <-chan PollResult {
+	return f.polls
+}
+
+// Backlog returns how many polls were skipped since the previous one still ran, and how many were
+// dropped since the dashboard didn't take them in time.
+func (f *AircraftFeed) Backlog() (int64, int64) {
+	return f.skipped.Load(), f.dropped.Load()
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProcessBounded(t *testing.T) {
+	const workers = 3
+	jobs := make([]int, 20)
+	for idx := range jobs {
+		jobs[idx] = idx + 1
+	}
+
+	var running, maxRunning atomic.Int64
+	results := processBounded(jobs, workers, func(job int) int {
+		now := running.Add(1)
+		for {
+			highest := maxRunning.Load()
+			if now <= highest || maxRunning.CompareAndSwap(highest, now) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return job * 2
+	})
+
+	sum := 0
+	for result := range results {
+		sum += result
+	}
+	if sum != 420 {
+		t.Errorf("sum of results = %d, expected 420", sum)
+	}
+	if highest := maxRunning.Load(); highest > workers {
+		t.Errorf("%d jobs ran at once, expected at most %d", highest, workers)
+	}
+}
+
+// newFeedServer serves a local receiver whose responses list one aircraft, whose hex counts the
+// requests. Each response waits for the release channel.
+func newFeedServer(t *testing.T, release <-chan struct{}) *httptest.Server {
+	t.Helper()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"aircraft": [{"hex": "%06d"}]}`, requests.Add(1))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestFeed(t *testing.T, url string) *AircraftFeed {
+	t.Helper()
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // only the local receiver
+	request, err := NewRequest(RequestOptions{Sources: []string{SourceLocal}, LocalURL: url}, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	return NewAircraftFeed(request)
+}
+
+// waitForFeed waits until the running poll of the feed is done.
+func waitForFeed(t *testing.T, feed *AircraftFeed) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for feed.running.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("poll didn't finish in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAircraftFeedSkipsWhileRunning(t *testing.T) {
+	release := make(chan struct{})
+	feed := newTestFeed(t, newFeedServer(t, release).URL)
+
+	if !feed.Poll() {
+		t.Fatal("Poll() = false, expected the first poll to start")
+	}
+	if feed.Poll() {
+		t.Error("Poll() = true while the previous poll is still running")
+	}
+	close(release)
+
+	poll := <-feed.Polls()
+	if len(poll.Aircraft) != 1 || poll.Aircraft[0].Hex != "000001" || poll.Err != nil {
+		t.Errorf("Polls() = %+v, expected the aircraft of the first request", poll)
+	}
+	if skipped, dropped := feed.Backlog(); skipped != 1 || dropped != 0 {
+		t.Errorf("Backlog() = %d, %d, expected 1 skipped and none dropped", skipped, dropped)
+	}
+}
+
+func TestAircraftFeedKeepsLatestPoll(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	feed := newTestFeed(t, newFeedServer(t, release).URL)
+
+	// Nobody takes the aircraft of the polls, like a dashboard which falls behind.
+	for range 3 {
+		if !feed.Poll() {
+			t.Fatal("Poll() = false, expected the poll to start")
+		}
+		waitForFeed(t, feed)
+	}
+
+	poll := <-feed.Polls()
+	if len(poll.Aircraft) != 1 || poll.Aircraft[0].Hex != "000003" {
+		t.Errorf("Polls() = %+v, expected the aircraft of the latest request", poll)
+	}
+	select {
+	case stale := <-feed.Polls():
+		t.Errorf("Polls() = %+v after the latest poll, expected nothing waiting", stale)
+	default:
+	}
+	if skipped, dropped := feed.Backlog(); skipped != 0 || dropped != 2 {
+		t.Errorf("Backlog() = %d, %d, expected none skipped and 2 dropped", skipped, dropped)
+	}
+}
+
+func TestAircraftFeedCarriesPollError(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"aircraft": [{"hex": "3c6444"}]}`)
+	}))
+	t.Cleanup(server.Close)
+	feed := newTestFeed(t, server.URL)
+
+	feed.Poll()
+	waitForFeed(t, feed)
+	failed := <-feed.Polls()
+
+	// The next poll succeeds before the failed one has been looked at.
+	failing.Store(false)
+	feed.Poll()
+	waitForFeed(t, feed)
+
+	if failed.Err == nil || failed.FailingSince.IsZero() || len(failed.Aircraft) != 0 {
+		t.Errorf("Polls() = %+v, expected the failed poll with its error", failed)
+	}
+	if succeeded := <-feed.Polls(); succeeded.Err != nil || !succeeded.FailingSince.IsZero() {
+		t.Errorf("Polls() = %+v, expected the successful poll without error", succeeded)
+	}
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"math"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// Limits beyond which the reports of an aircraft are taken for bogus.
+const (
+	maxPlausibleSpeed    = 2000   // maxPlausibleSpeed is the highest ground speed in [knots].
+	maxPlausibleMach     = 3.5    // maxPlausibleMach is the highest Mach number, above the SR-71's.
+	minPlausibleAltitude = -2000  // minPlausibleAltitude is the lowest altitude in [feet].
+	maxPlausibleAltitude = 100000 // maxPlausibleAltitude is the highest altitude in [feet].
+	nullIslandRadius     = 0.1    // nullIslandRadius is how close to 0,0 in [degrees] positions are bogus.
+	// positionJitter is how far in [km] a position may jump regardless of speed, e.g. with MLAT.
+	positionJitter     = 5.0
+	knotsInKmPerSecond = 1.852 / 3600 //nolint:mnd // 1 knot = 1.852 km/h
+)
+
+// Reasons for rejecting reports, counted in the rejected records like decode errors by field.
+const (
+	rejectPosition = "lat/lon"    // rejectPosition counts positions out of range or at 0,0.
+	rejectSpeed    = "gs"         // rejectSpeed counts impossible ground speeds.
+	rejectMach     = "mach"       // rejectMach counts impossible Mach numbers.
+	rejectAltitude = "alt_baro"   // rejectAltitude counts impossible altitudes.
+	rejectTeleport = "(teleport)" // rejectTeleport counts positions too far from the previous one.
+)
+
+// positionFix is the last plausible live position of an aircraft and when it was received.
+type positionFix struct {
+	lat  float64
+	lon  float64
+	time time.Time // time is zero until the aircraft reported a plausible position.
+}
+
+// checkPlausibility clears what the aircraft reports implausibly, before it makes it into the
+// records, distances and statistics, and counts it in the rejected records.
+// A position is implausible if the aircraft couldn't have got there from its previous one at
+// maxPlausibleSpeed. Since the reachable distance grows with time, a bogus first position doesn't
+// get all later ones rejected for long.
+func (db *Dashboard) checkPlausibility(sighting *AircraftSighting, aircraft *AircraftRecord, now time.Time) {
+	if aircraft.GroundSpeed < 0 || aircraft.GroundSpeed > maxPlausibleSpeed {
+		aircraft.GroundSpeed = 0
+		db.rejected.add(rejectSpeed)
+	}
+	if aircraft.Mach < 0 || aircraft.Mach > maxPlausibleMach || math.IsNaN(aircraft.Mach) {
+		aircraft.Mach = 0
+		db.rejected.add(rejectMach)
+	}
+	if feet, ok := aircraft.AltBaro.Feet(); ok && (feet < minPlausibleAltitude || feet > maxPlausibleAltitude) {
+		aircraft.AltBaro = Altitude{feet: 0, known: false, ground: false}
+		db.rejected.add(rejectAltitude)
+	}
+
+	if aircraft.Lat == 0 && aircraft.Lon == 0 {
+		return // no live position, which isn't implausible
+	}
+	if !isPlausiblePosition(aircraft.Lat, aircraft.Lon) {
+		aircraft.Lat, aircraft.Lon = 0, 0
+		db.rejected.add(rejectPosition)
+		return
+	}
+
+	fix := positionFix{
+		lat:  aircraft.Lat,
+		lon:  aircraft.Lon,
+		time: now.Add(-time.Duration(aircraft.SeenPos * float64(time.Second))),
+	}
+	if previous := sighting.lastFix; !previous.time.IsZero() {
+		elapsed := math.Max(fix.time.Sub(previous.time).Seconds(), 0)
+		reachable := positionJitter + elapsed*maxPlausibleSpeed*knotsInKmPerSecond
+		jump := dash.Distance(
+			dash.NewCoordinates(previous.lat, previous.lon),
+			dash.NewCoordinates(fix.lat, fix.lon)).Kilometers()
+		if jump > reachable {
+			aircraft.Lat, aircraft.Lon = 0, 0
+			db.rejected.add(rejectTeleport)
+			return
+		}
+	}
+	sighting.lastFix = fix
+}
+
+// isPlausiblePosition tells whether the position is on earth and not at 0,0, where broken
+// transponders and decoders put aircraft.
+func isPlausiblePosition(lat float64, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.Abs(lat) > 90 || math.Abs(lon) > 180 { //nolint:mnd // degrees
+		return false
+	}
+	return math.Abs(lat) >= nullIslandRadius || math.Abs(lon) >= nullIslandRadius
+}
+
+// RejectedRecords returns the reports of the aircraft which were rejected as implausible so far,
+// by reason.
+func (db *Dashboard) RejectedRecords() *DecodeDiagnostics {
+	return db.rejected
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCheckPlausibility(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		aircraft AircraftRecord
+		rejected string
+	}{
+		{
+			name:     "plausible",
+			aircraft: AircraftRecord{Lat: 53.63, Lon: 9.99, GroundSpeed: 450, AltBaro: NewAltitude(36000)}, //nolint:exhaustruct // checked fields only
+			rejected: "",
+		},
+		{
+			name:     "no position",
+			aircraft: AircraftRecord{GroundSpeed: 450}, //nolint:exhaustruct // checked fields only
+			rejected: "",
+		},
+		{
+			name:     "null island",
+			aircraft: AircraftRecord{Lat: 0.01, Lon: 0}, //nolint:exhaustruct // checked fields only
+			rejected: rejectPosition,
+		},
+		{
+			name:     "off the earth",
+			aircraft: AircraftRecord{Lat: 153.63, Lon: 9.99}, //nolint:exhaustruct // checked fields only
+			rejected: rejectPosition,
+		},
+		{
+			name:     "too fast",
+			aircraft: AircraftRecord{GroundSpeed: 9999}, //nolint:exhaustruct // checked fields only
+			rejected: rejectSpeed,
+		},
+		{
+			name:     "too fast for the air",
+			aircraft: AircraftRecord{Mach: 8.5}, //nolint:exhaustruct // checked fields only
+			rejected: rejectMach,
+		},
+		{
+			name:     "supersonic",
+			aircraft: AircraftRecord{Mach: 1.2}, //nolint:exhaustruct // checked fields only
+			rejected: "",
+		},
+		{
+			name:     "too high",
+			aircraft: AircraftRecord{AltBaro: NewAltitude(450000)}, //nolint:exhaustruct // checked fields only
+			rejected: rejectAltitude,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dashboard := &Dashboard{rejected: NewDecodeDiagnostics()} //nolint:exhaustruct // rejected only
+			sighting := &AircraftSighting{}                           //nolint:exhaustruct // position only
+			aircraft := test.aircraft
+			dashboard.checkPlausibility(sighting, &aircraft, now)
+
+			counts := dashboard.RejectedRecords().Counts()
+			if test.rejected == "" && len(counts) > 0 {
+				t.Errorf("checkPlausibility() rejected %v, expected nothing", counts)
+			}
+			if test.rejected != "" && counts[test.rejected] != 1 {
+				t.Errorf("checkPlausibility() rejected %v, expected %s", counts, test.rejected)
+			}
+			if test.rejected == rejectPosition && (aircraft.Lat != 0 || aircraft.Lon != 0) {
+				t.Errorf("checkPlausibility() kept the position %.2f,%.2f", aircraft.Lat, aircraft.Lon)
+			}
+			if test.rejected == rejectMach && aircraft.Mach != 0 {
+				t.Errorf("checkPlausibility() kept Mach %.2f", aircraft.Mach)
+			}
+		})
+	}
+}
+
+func TestCheckPlausibilityRejectsTeleport(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	dashboard := &Dashboard{rejected: NewDecodeDiagnostics()} //nolint:exhaustruct // rejected only
+	sighting := &AircraftSighting{}                           //nolint:exhaustruct // position only
+
+	hamburg := AircraftRecord{Lat: 53.63, Lon: 9.99} //nolint:exhaustruct // position only
+	dashboard.checkPlausibility(sighting, &hamburg, now)
+
+	// Munich is 600 km away, which takes more than 10 seconds even at the highest speed.
+	munich := AircraftRecord{Lat: 48.35, Lon: 11.79} //nolint:exhaustruct // position only
+	dashboard.checkPlausibility(sighting, &munich, now.Add(10*time.Second))
+	if munich.Lat != 0 || dashboard.RejectedRecords().Counts()[rejectTeleport] != 1 {
+		t.Errorf("checkPlausibility() accepted a jump to Munich, rejected %v", dashboard.RejectedRecords())
+	}
+
+	// Flying there takes an hour at 330 kt, which is plausible again.
+	munich = AircraftRecord{Lat: 48.35, Lon: 11.79} //nolint:exhaustruct // position only
+	dashboard.checkPlausibility(sighting, &munich, now.Add(time.Hour))
+	if munich.Lat == 0 {
+		t.Error("checkPlausibility() rejected Munich after an hour")
+	}
+}
+
+func TestBogusRecordsDontMakeRecords(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", GroundSpeed: 480, AltBaro: NewAltitude(38000)},   //nolint:exhaustruct // records only
+		{Hex: "4ca7b5", Flight: "RYR1AB", GroundSpeed: 9000, AltBaro: NewAltitude(990000)}, //nolint:exhaustruct // records only
+	})
+	records := dashboard.Records.Session()
+	if records.Fastest == nil || records.Fastest.Hex != "3c6444" {
+		t.Errorf("fastest = %+v, expected DLH400", records.Fastest)
+	}
+	if records.Highest == nil || records.Highest.Hex != "3c6444" {
+		t.Errorf("highest = %+v, expected DLH400", records.Highest)
+	}
+	if total := dashboard.RejectedRecords().Total(); total != 2 {
+		t.Errorf("RejectedRecords() = %v, expected the speed and altitude of RYR1AB", dashboard.RejectedRecords())
+	}
+}
+
+func TestBogusPositionDoesntSeedSighting(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Lat: 95, Lon: 9.99}, //nolint:exhaustruct // position only
+	})
+	sighting := dashboard.aircraftSightings["3c6444"]
+	if sighting.latitude != 0 || sighting.longitude != 0 {
+		t.Errorf("sighting at %.2f,%.2f, expected the bogus position to be dropped",
+			sighting.latitude, sighting.longitude)
+	}
+}
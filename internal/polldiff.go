@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// lostAfter is how long an aircraft has to be missing from the polls to count as lost, so that an
+// aircraft missing from a single poll isn't reported as lost and as back again.
+const lostAfter = time.Minute
+
+// PollDiff is how the aircraft of a poll differ from those of the previous polls.
+type PollDiff struct {
+	New     []*AircraftRecord // New are the aircraft which started a new flight.
+	Lost    []AircraftRecord  // Lost are the aircraft missing for lostAfter, as last seen.
+	Changed []AircraftChange  // Changed are the aircraft whose identity or status changed.
+}
+
+// AircraftChange is an aircraft whose identity or status differs from the previous poll.
+type AircraftChange struct {
+	Aircraft *AircraftRecord
+	Changes  []string // Changes describe what changed, e.g. "squawk 1000 → 7700".
+}
+
+// IsEmpty tells whether the poll is the same as the previous one, as far as the diff goes.
+func (d PollDiff) IsEmpty() bool {
+	return len(d.New) == 0 && len(d.Lost) == 0 && len(d.Changed) == 0
+}
+
+// pollTracker remembers the aircraft of the recent polls, to tell how a poll differs from them.
+type pollTracker struct {
+	last    map[string]AircraftRecord // last are the aircraft of the recent polls, as last seen.
+	missing map[string]time.Time      // missing maps the aircraft of last missing from the polls to since when.
+}
+
+func newPollTracker() pollTracker {
+	return pollTracker{last: make(map[string]AircraftRecord), missing: make(map[string]time.Time)}
+}
+
+// diff compares the aircraft of a poll at the given time with how they were last seen. The new
+// flights of the poll are new, aircraft missing for lostAfter are lost and forgotten. Fields which
+// weren't reported in either poll don't count as changed.
+func (pt *pollTracker) diff(newFlights []*AircraftRecord, current []AircraftRecord, now time.Time) PollDiff {
+	diff := PollDiff{New: newFlights, Lost: nil, Changed: nil}
+	isNew := make(map[string]bool, len(newFlights))
+	for _, aircraft := range newFlights {
+		isNew[aircraft.Hex] = true
+	}
+
+	seen := make(map[string]bool, len(current))
+	for idx := range current {
+		aircraft := &current[idx]
+		seen[aircraft.Hex] = true
+		delete(pt.missing, aircraft.Hex)
+		if last, ok := pt.last[aircraft.Hex]; ok && !isNew[aircraft.Hex] {
+			if changes := describeChanges(&last, aircraft); len(changes) > 0 {
+				diff.Changed = append(diff.Changed, AircraftChange{Aircraft: aircraft, Changes: changes})
+			}
+		}
+		pt.last[aircraft.Hex] = *aircraft
+	}
+	for hex, last := range pt.last {
+		if seen[hex] {
+			continue
+		}
+		since, ok := pt.missing[hex]
+		if !ok {
+			pt.missing[hex] = now
+			continue
+		}
+		if now.Sub(since) >= lostAfter {
+			diff.Lost = append(diff.Lost, last)
+			delete(pt.last, hex)
+			delete(pt.missing, hex)
+		}
+	}
+	slices.SortFunc(diff.Lost, func(a, b AircraftRecord) int {
+		return strings.Compare(pollDiffLabel(&a), pollDiffLabel(&b))
+	})
+	return diff
+}
+
+// describeChanges describes how the identity and status of the aircraft changed: its callsign,
+// squawk, registration, emergency and whether it is on the ground.
+func describeChanges(last *AircraftRecord, aircraft *AircraftRecord) []string {
+	var changes []string
+	for _, field := range []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "callsign", from: strings.TrimSpace(last.Flight), to: strings.TrimSpace(aircraft.Flight)},
+		{name: "squawk", from: last.Squawk, to: aircraft.Squawk},
+		{name: "registration", from: last.Registration, to: aircraft.Registration},
+		{name: "emergency", from: last.Emergency, to: aircraft.Emergency},
+	} {
+		if field.from != "" && field.to != "" && field.from != field.to {
+			changes = append(changes, fmt.Sprintf("%s %s → %s", field.name, field.from, field.to))
+		}
+	}
+
+	wasGround, isGround := last.AltBaro.IsGround(), aircraft.AltBaro.IsGround()
+	if last.AltBaro.IsKnown() && aircraft.AltBaro.IsKnown() && wasGround != isGround {
+		if isGround {
+			changes = append(changes, "landed")
+		} else {
+			changes = append(changes, "took off")
+		}
+	}
+	return changes
+}
+
+// pollDiffLabel names the aircraft in a diff by its callsign, or by its hex if it has none.
+func pollDiffLabel(aircraft *AircraftRecord) string {
+	if flight := strings.TrimSpace(aircraft.Flight); flight != "" {
+		return flight
+	}
+	return aircraft.Hex
+}
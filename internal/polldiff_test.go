@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollTrackerDiff(t *testing.T) {
+	tracker := newPollTracker()
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	//nolint:exhaustruct // only the compared fields
+	previous := []AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400  ", Squawk: "1000", AltBaro: NewAltitude(35000)},
+		{Hex: "4b1805", Flight: "SWR12", AltBaro: NewAltitude(3000)},
+		{Hex: "a0b1c2", Flight: "UAE15"},
+	}
+	tracker.diff(nil, previous, now)
+
+	//nolint:exhaustruct // only the compared fields
+	current := []AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Squawk: "7700", AltBaro: NewAltitude(34000)},
+		{Hex: "4b1805", Flight: "SWR12", AltBaro: GroundAltitude(), Registration: "HB-JCA"},
+		{Hex: "3c4b26", Flight: "DLH9TK"},
+	}
+	diff := tracker.diff([]*AircraftRecord{&current[2]}, current, now.Add(10*time.Second))
+	if len(diff.New) != 1 || diff.New[0].Hex != "3c4b26" {
+		t.Errorf("New = %+v, expected the new flight", diff.New)
+	}
+	if len(diff.Lost) != 0 {
+		t.Errorf("Lost = %+v, expected the missing aircraft to be lost only after a while", diff.Lost)
+	}
+	changes := make(map[string][]string)
+	for _, change := range diff.Changed {
+		changes[change.Aircraft.Hex] = change.Changes
+	}
+	// The registration wasn't reported before, which doesn't count as a change.
+	expected := map[string][]string{"3c6444": {"squawk 1000 → 7700"}, "4b1805": {"landed"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Changed = %v, expected %v", changes, expected)
+	}
+
+	if diff := tracker.diff(nil, current, now.Add(20*time.Second)); !diff.IsEmpty() {
+		t.Errorf("diff() = %+v for the same aircraft, expected it empty", diff)
+	}
+	diff = tracker.diff(nil, current, now.Add(10*time.Second+lostAfter))
+	if len(diff.Lost) != 1 || diff.Lost[0].Hex != "a0b1c2" {
+		t.Errorf("Lost = %+v, expected the aircraft which is gone for good", diff.Lost)
+	}
+}
+
+func TestPollTrackerFlapping(t *testing.T) {
+	tracker := newPollTracker()
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	//nolint:exhaustruct // identity only
+	aircraft := []AircraftRecord{{Hex: "3c6444", Flight: "DLH400"}, {Hex: "4b1805", Flight: "SWR12"}}
+	tracker.diff(nil, aircraft, now)
+
+	// An aircraft missing from a single poll is neither lost nor new when it is back.
+	if diff := tracker.diff(nil, aircraft[:1], now.Add(10*time.Second)); !diff.IsEmpty() {
+		t.Errorf("diff() = %+v with an aircraft missing once, expected it empty", diff)
+	}
+	if diff := tracker.diff(nil, aircraft, now.Add(20*time.Second)); !diff.IsEmpty() {
+		t.Errorf("diff() = %+v with the aircraft back, expected it empty", diff)
+	}
+	if diff := tracker.diff(nil, aircraft[:1], now.Add(30*time.Second+lostAfter)); !diff.IsEmpty() {
+		t.Errorf("diff() = %+v, expected the missing time to start over when the aircraft was back", diff)
+	}
+}
+
+func TestPrintPollDiff(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	var stdout bytes.Buffer
+	opts := NotifyOptions{Verbosity: VerbosityNormal} //nolint:exhaustruct // defaults
+	notify, err := NewNotify("test", opts, &stdout, io.Discard)
+	if err != nil {
+		t.Fatalf("NewNotify() error = %v", err)
+	}
+
+	//nolint:exhaustruct // identity only
+	dashboard.ProcessAircraftRecords([]AircraftRecord{{Hex: "3c6444", Flight: "DLH400", Squawk: "1000"}})
+	//nolint:exhaustruct // identity only
+	dashboard.ProcessAircraftRecords([]AircraftRecord{{Hex: "3c6444", Flight: "DLH400", Squawk: "1000"}})
+	notify.PrintAircraftUpdates(dashboard)
+	if stdout.Len() != 0 {
+		t.Errorf("PrintAircraftUpdates() = %q without any difference, expected nothing", stdout.String())
+	}
+
+	//nolint:exhaustruct // identity only
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Squawk: "7700"},
+		{Hex: "4b1805", Flight: "SWR12"},
+	})
+	notify.PrintAircraftUpdates(dashboard)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "2 aircraft, 1 new, 0 lost, 1 changed ---") ||
+		!strings.HasPrefix(lines[1], "new FNO SWR12") || lines[2] != "changed DLH400: squawk 1000 → 7700" {
+		t.Errorf("PrintAircraftUpdates() = %q, expected the new and the changed aircraft", lines)
+	}
+
+	// A failed poll has no aircraft, which aren't reported as lost.
+	stdout.Reset()
+	dashboard.ProcessPoll(nil, ErrNonOkResponse)
+	notify.PrintAircraftUpdates(dashboard)
+	if stdout.Len() != 0 {
+		t.Errorf("PrintAircraftUpdates() = %q after a failed poll, expected nothing", stdout.String())
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFastPollInterval is how often aircraft are polled while interesting ones are nearby.
+	DefaultFastPollInterval = 10 * time.Second
+	// DefaultSlowPollInterval is how often aircraft are polled during the night.
+	DefaultSlowPollInterval = 2 * time.Minute
+	// DefaultNearbyRadius is the distance in [km] within which interesting aircraft speed up polling.
+	DefaultNearbyRadius = 30.0
+	// DefaultNightStartHour and DefaultNightEndHour are the night hours, in local time, during
+	// which aircraft are polled slowly.
+	DefaultNightStartHour = 0
+	DefaultNightEndHour   = 6
+)
+
+var errInvalidPolling = errors.New("invalid polling options")
+
+// PollingOptions determines how often aircraft are polled and the ticker sums up.
+type PollingOptions struct {
+	Interval        time.Duration // Interval is the time between aircraft polls.
+	SummaryInterval time.Duration // SummaryInterval is the time between summaries of the ticker.
+	Warmup          time.Duration // Warmup is how long the ticker learns before reporting rarity.
+	// Adaptive polls every FastInterval while rare or watched aircraft are within NearbyRadius,
+	// and every SlowInterval between NightStartHour and NightEndHour, instead of every Interval.
+	Adaptive       bool
+	FastInterval   time.Duration
+	SlowInterval   time.Duration
+	NearbyRadius   float64 // NearbyRadius is in [km].
+	NightStartHour int
+	NightEndHour   int
+}
+
+// Validate checks that all intervals are positive and that the night hours are hours of the day.
+func (opts PollingOptions) Validate() error {
+	if opts.Interval <= 0 || opts.SummaryInterval <= 0 || opts.Warmup < 0 {
+		return fmt.Errorf("PollingOptions.Validate: %w: intervals must be positive", errInvalidPolling)
+	}
+	if !opts.Adaptive {
+		return nil
+	}
+	if opts.FastInterval <= 0 || opts.SlowInterval <= 0 {
+		return fmt.Errorf("PollingOptions.Validate: %w: intervals must be positive", errInvalidPolling)
+	}
+	if opts.NightStartHour < 0 || opts.NightStartHour >= hoursPerDay ||
+		opts.NightEndHour < 0 || opts.NightEndHour >= hoursPerDay {
+		return fmt.Errorf("PollingOptions.Validate: %w: night hours must be between 0 and 23",
+			errInvalidPolling)
+	}
+	return nil
+}
+
+// NextInterval returns how long to wait for the next poll at the given time. Nearby interesting
+// aircraft take precedence over the night, so that a rare night flight isn't missed.
+func (opts PollingOptions) NextInterval(now time.Time, interestingNearby bool) time.Duration {
+	switch {
+	case !opts.Adaptive:
+		return opts.Interval
+	case interestingNearby:
+		return opts.FastInterval
+	case opts.isNight(now):
+		return opts.SlowInterval
+	default:
+		return opts.Interval
+	}
+}
+
+// MaxInterval returns the longest time between two polls.
+func (opts PollingOptions) MaxInterval() time.Duration {
+	if opts.Adaptive {
+		return max(opts.Interval, opts.FastInterval, opts.SlowInterval)
+	}
+	return opts.Interval
+}
+
+// isNight tells whether the given time is within the night hours, which may span midnight.
+// Equal start and end hours mean that there is no night.
+func (opts PollingOptions) isNight(now time.Time) bool {
+	hour := now.Hour()
+	if opts.NightStartHour <= opts.NightEndHour {
+		return hour >= opts.NightStartHour && hour < opts.NightEndHour
+	}
+	return hour >= opts.NightStartHour || hour < opts.NightEndHour
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPollingNextInterval(t *testing.T) {
+	day := time.Date(2025, time.June, 1, 14, 0, 0, 0, time.UTC)
+	night := time.Date(2025, time.June, 1, 23, 30, 0, 0, time.UTC)
+	polling := PollingOptions{
+		Interval:        30 * time.Second,
+		SummaryInterval: time.Hour,
+		Warmup:          time.Hour,
+		Adaptive:        true,
+		FastInterval:    10 * time.Second,
+		SlowInterval:    2 * time.Minute,
+		NearbyRadius:    DefaultNearbyRadius,
+		NightStartHour:  23,
+		NightEndHour:    6,
+	}
+
+	tests := []struct {
+		name        string
+		adaptive    bool
+		now         time.Time
+		interesting bool
+		expected    time.Duration
+	}{
+		{name: "day", adaptive: true, now: day, interesting: false, expected: 30 * time.Second},
+		{name: "night", adaptive: true, now: night, interesting: false, expected: 2 * time.Minute},
+		{name: "early morning", adaptive: true, now: day.Add(-9 * time.Hour), interesting: false,
+			expected: 2 * time.Minute},
+		{name: "interesting at night", adaptive: true, now: night, interesting: true, expected: 10 * time.Second},
+		{name: "not adaptive", adaptive: false, now: night, interesting: true, expected: 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := polling
+			options.Adaptive = test.adaptive
+			if interval := options.NextInterval(test.now, test.interesting); interval != test.expected {
+				t.Errorf("NextInterval() = %v, expected %v", interval, test.expected)
+			}
+		})
+	}
+
+	if maxInterval := polling.MaxInterval(); maxInterval != 2*time.Minute {
+		t.Errorf("MaxInterval() = %v, expected the slow interval", maxInterval)
+	}
+}
+
+func TestPollingValidate(t *testing.T) {
+	valid := PollingOptions{
+		Interval:        AircraftUpdateInterval,
+		SummaryInterval: SummaryInterval,
+		Warmup:          0,
+		Adaptive:        true,
+		FastInterval:    DefaultFastPollInterval,
+		SlowInterval:    DefaultSlowPollInterval,
+		NearbyRadius:    DefaultNearbyRadius,
+		NightStartHour:  DefaultNightStartHour,
+		NightEndHour:    DefaultNightEndHour,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, expected the defaults to be valid", err)
+	}
+
+	noInterval := valid
+	noInterval.Interval = 0
+	lateNight := valid
+	lateNight.NightEndHour = 24
+	for _, invalid := range []PollingOptions{noInterval, lateNight} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, expected an error", invalid)
+		}
+	}
+}
+
+func TestInterestingNearby(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio", FleetRareBelow: 50}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	dashboard.FleetSizes = map[string]int{"A124": 20}
+	dashboard.FinishWarmupPeriod()
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "4ca7b5", Flight: "RYR1AB", IcaoType: "B738"}, //nolint:exhaustruct // type only
+	})
+	if dashboard.InterestingNearby(DefaultNearbyRadius) {
+		t.Error("InterestingNearby() = true, expected nothing interesting about a B738")
+	}
+
+	dashboard.ProcessAircraftRecords([]AircraftRecord{
+		{Hex: "4ca7b5", Flight: "RYR1AB", IcaoType: "B738"},  //nolint:exhaustruct // type only
+		{Hex: "508035", Flight: "ADB3042", IcaoType: "A124"}, //nolint:exhaustruct // type only
+	})
+	if !dashboard.InterestingNearby(DefaultNearbyRadius) {
+		t.Error("InterestingNearby() = false, expected the rare An-124")
+	}
+}
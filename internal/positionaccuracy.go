@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"slices"
+)
+
+// PositionAccuracy tells how far the position of an aircraft can be trusted, as a score from 0 for
+// no position at all to 100 for a precise and sound ADS-B position.
+type PositionAccuracy int
+
+const (
+	AccuracyNone   PositionAccuracy = 0
+	AccuracyLow    PositionAccuracy = 25  // AccuracyLow is e.g. a TIS-B position.
+	AccuracyMedium PositionAccuracy = 50  // AccuracyMedium is e.g. an MLAT position.
+	AccuracyGood   PositionAccuracy = 75  // AccuracyGood is an ADS-B position within about a kilometer.
+	AccuracyHigh   PositionAccuracy = 100 // AccuracyHigh is an ADS-B position within 185 m.
+
+	// accuracyStep is how much a last known position counts less than a live one.
+	accuracyStep = AccuracyGood - AccuracyMedium
+
+	// minRecordAccuracy is the accuracy a position needs to set a distance record: an ADS-B position
+	// which reports to be within about a kilometer. Coarse TIS-B positions, MLAT outliers and
+	// positions which don't report their accuracy, like those of aviationstack, can't claim one.
+	minRecordAccuracy = AccuracyGood
+
+	// containmentHigh, containmentGood and containmentMedium are the radii of containment of the
+	// accuracies, in [meters]: 0.1, 0.6 and 2 NM.
+	containmentHigh   = 185
+	containmentGood   = 1111
+	containmentMedium = 3704
+	// nicHigh, nicGood and nicMedium are the navigation integrity categories of these radii.
+	nicHigh   = 8
+	nicGood   = 6
+	nicMedium = 4
+	// nacHigh, nacGood and nacMedium are the navigation accuracy categories for position of
+	// estimated position uncertainties below 93 m, 556 m and 1852 m.
+	nacHigh   = 8
+	nacGood   = 6
+	nacMedium = 4
+)
+
+// String names the accuracy: "high", "good", "medium", "low" or "none".
+func (a PositionAccuracy) String() string {
+	switch {
+	case a >= AccuracyHigh:
+		return "high"
+	case a >= AccuracyGood:
+		return "good"
+	case a >= AccuracyMedium:
+		return "medium"
+	case a > AccuracyNone:
+		return "low"
+	default:
+		return "none"
+	}
+}
+
+// PositionAccuracy scores the position of the aircraft. ADS-B positions are scored by the worse of
+// their integrity, i.e. the radius of containment or the NIC, and their accuracy, the NACp. Fields
+// which aren't reported count as medium. MLAT positions are medium and TIS-B ones, which are ground
+// radar targets rebroadcast by a ground station, low. A last known position counts a step less.
+func (ac *AircraftRecord) PositionAccuracy() PositionAccuracy {
+	position, ok := ac.KnownPosition()
+	if !ok {
+		return AccuracyNone
+	}
+
+	var accuracy PositionAccuracy
+	switch {
+	case IsTISB(ac.Type) || slices.Contains(ac.Tisb, "lat"):
+		accuracy = AccuracyLow
+	case ac.Type == "mlat" || slices.Contains(ac.Mlat, "lat"):
+		accuracy = AccuracyMedium
+	case position.Stale:
+		accuracy = min(containmentAccuracy(ac.LastPosition.Rc, ac.LastPosition.Nic), nacAccuracy(ac.NacP))
+	default:
+		accuracy = min(containmentAccuracy(ac.RadiusOfCtn, ac.Nic), nacAccuracy(ac.NacP))
+	}
+	if position.Stale {
+		accuracy = max(AccuracyLow, accuracy-accuracyStep)
+	}
+	return accuracy
+}
+
+// containmentAccuracy scores the integrity of a position by its radius of containment in [meters],
+// or by its NIC if there is no radius.
+func containmentAccuracy(containment float64, nic int) PositionAccuracy {
+	switch {
+	case containment > 0 && containment <= containmentHigh:
+		return AccuracyHigh
+	case containment > 0 && containment <= containmentGood:
+		return AccuracyGood
+	case containment > 0 && containment <= containmentMedium:
+		return AccuracyMedium
+	case containment > 0:
+		return AccuracyLow
+	case nic >= nicHigh:
+		return AccuracyHigh
+	case nic >= nicGood:
+		return AccuracyGood
+	case nic >= nicMedium:
+		return AccuracyMedium
+	case nic > 0:
+		return AccuracyLow
+	default:
+		return AccuracyMedium
+	}
+}
+
+// nacAccuracy scores the accuracy of a position by its NACp, 0 being unknown.
+func nacAccuracy(nacP float64) PositionAccuracy {
+	switch {
+	case nacP >= nacHigh:
+		return AccuracyHigh
+	case nacP >= nacGood:
+		return AccuracyGood
+	case nacP >= nacMedium:
+		return AccuracyMedium
+	case nacP > 0:
+		return AccuracyLow
+	default:
+		return AccuracyMedium
+	}
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestPositionAccuracy(t *testing.T) {
+	tests := []struct {
+		name     string
+		aircraft AircraftRecord
+		expected PositionAccuracy
+	}{
+		{
+			name:     "no position",
+			aircraft: AircraftRecord{Type: "adsb_icao", Nic: 8, NacP: 9}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyNone,
+		},
+		{
+			name:     "ADS-B within 0.6 NM",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10, Type: "adsb_icao", Nic: 8, RadiusOfCtn: 186, NacP: 9}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyGood,
+		},
+		{
+			name:     "ADS-B by NIC",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10, Type: "adsb_icao", Nic: 8, NacP: 10}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyHigh,
+		},
+		{
+			name:     "inaccurate ADS-B",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10, Type: "adsb_icao", Nic: 8, NacP: 2}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyLow,
+		},
+		{
+			name:     "nothing reported",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyMedium,
+		},
+		{
+			name:     "MLAT",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10, Type: "mlat"}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyMedium,
+		},
+		{
+			name:     "TIS-B",
+			aircraft: AircraftRecord{Lat: 53.6, Lon: 10, Type: "tisb_trackfile", Nic: 8, NacP: 9}, //nolint:exhaustruct // accuracy only
+			expected: AccuracyLow,
+		},
+		{
+			name: "last position",
+			//nolint:exhaustruct // accuracy only
+			aircraft: AircraftRecord{
+				Type: "adsb_icao", NacP: 10, LastPosition: &LastPosition{Lat: 53.6, Lon: 10, Nic: 8, Rc: 186, SeenPos: 90},
+			},
+			expected: AccuracyMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if accuracy := tt.aircraft.PositionAccuracy(); accuracy != tt.expected {
+				t.Errorf("PositionAccuracy() = %d (%s), expected %d (%s)", accuracy, accuracy, tt.expected, tt.expected)
+			}
+		})
+	}
+}
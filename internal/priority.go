@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// PriorityEvents returns the events of the latest update which deserve attention right away:
+// emergency squawks, aircraft of the watchlist and favourites, notable aircraft, aircraft of a type
+// never seen before, supersonic aircraft and aircraft of a rare type, operator and country at once.
+// The TUI shows them in a banner, since desktop notifications go unnoticed while the terminal is in
+// front. The events are described in the given language.
+func (db *Dashboard) PriorityEvents(lang i18n.Language, now time.Time) []Event {
+	var events []Event
+	for _, change := range db.IdentityChanges {
+		if change.IsEmergency() {
+			events = append(events, identityChangeEvent(lang, change, now))
+		}
+	}
+	for _, noteSighting := range db.NoteSightings {
+		if noteSighting.Note.isAlerting() {
+			events = append(events, noteEvent(lang, noteSighting.Note, noteSighting.Sighting))
+		}
+	}
+	for _, notable := range db.NotableSightings {
+		events = append(events, notableEvent(lang, notable))
+	}
+	for _, firstSighting := range db.FirstSightings {
+		if firstSighting.Firsts[0].Category == "type" {
+			events = append(events, firstSightingEvent(lang, firstSighting))
+		}
+	}
+	for _, alert := range db.MachAlerts {
+		events = append(events, machAlertEvent(lang, alert, now))
+	}
+	for _, rareSighting := range db.RareSightings {
+		if rareSighting.Rarities == RareTypeOperatorCountry {
+			events = append(events, rareTypeOperatorCountryEvent(lang, rareSighting.Sighting))
+		}
+	}
+	return events
+}
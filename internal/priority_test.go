@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestPriorityEvents(t *testing.T) {
+	sighting := &AircraftSighting{lastFlightNo: "DLH400"} //nolint:exhaustruct // flight only
+
+	dashboard := &Dashboard{ //nolint:exhaustruct // latest update only
+		IdentityChanges: []IdentityChange{
+			{Field: ChangeSquawk, From: "1000", To: "7700", Sighting: sighting},
+			{Field: ChangeSquawk, From: "1000", To: "2000", Sighting: sighting},
+		},
+		NoteSightings: []NoteSighting{
+			{Note: Note{Hex: "3c6444", Text: "med-evac", Watch: true, Favourite: false, Updated: time.Time{}}, Sighting: sighting},
+			{Note: Note{Hex: "3c6445", Text: "airshow", Watch: false, Favourite: false, Updated: time.Time{}}, Sighting: sighting},
+		},
+		RareSightings: []RareSighting{
+			{Rarities: RareTypeOperatorCountry, Sighting: sighting},
+			{Rarities: RareType, Sighting: sighting},
+		},
+	}
+
+	events := dashboard.PriorityEvents(i18n.English, time.Now())
+	kinds := make([]string, 0, len(events))
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	expected := []string{EventKindChange, EventKindNote, EventKindRarity}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("PriorityEvents() kinds = %v, expected %v", kinds, expected)
+	}
+}
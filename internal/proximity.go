@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errUnknownTier = errors.New("unknown proximity tier")
+
+// ProximityTier tags aircraft within a distance of our location, e.g.
+//
+//	{"name": "overhead", "max_distance": 5}
+type ProximityTier struct {
+	Name        string  `json:"name"`
+	MaxDistance float64 `json:"max_distance"` // MaxDistance is in [km].
+}
+
+// ProximityTiers are ordered from the closest to the farthest tier.
+type ProximityTiers []ProximityTier
+
+// DefaultProximityTiers returns the tiers used unless the config has its own.
+func DefaultProximityTiers() ProximityTiers {
+	return ProximityTiers{
+		{Name: "overhead", MaxDistance: 5},   //nolint:mnd // default tier
+		{Name: "nearby", MaxDistance: 20},    //nolint:mnd // default tier
+		{Name: "in range", MaxDistance: 100}, //nolint:mnd // default tier
+	}
+}
+
+// Tier returns the index and name of the closest tier the distance in [km] is within, or -1 and
+// an empty name if it's beyond all tiers.
+func (tiers ProximityTiers) Tier(distance float64) (int, string) {
+	for idx, tier := range tiers {
+		if distance <= tier.MaxDistance {
+			return idx, tier.Name
+		}
+	}
+	return -1, ""
+}
+
+// MaxDistance returns the distance in [km] up to which the named tier reaches. An empty name
+// reaches everywhere.
+func (tiers ProximityTiers) MaxDistance(name string) (float64, error) {
+	if name == "" {
+		return math.Inf(1), nil
+	}
+	for _, tier := range tiers {
+		if tier.Name == name {
+			return tier.MaxDistance, nil
+		}
+	}
+	return 0, fmt.Errorf("ProximityTiers.MaxDistance: %w: %q", errUnknownTier, name)
+}
+
+func (tiers ProximityTiers) validate() error {
+	names := make(map[string]bool, len(tiers))
+	previous := 0.0
+	for _, tier := range tiers {
+		if tier.Name == "" || names[tier.Name] {
+			return fmt.Errorf("%w: tier names must be unique and not empty", errInvalidConfig)
+		}
+		if tier.MaxDistance <= previous {
+			return fmt.Errorf("%w: tiers must be ordered by increasing max_distance", errInvalidConfig)
+		}
+		names[tier.Name] = true
+		previous = tier.MaxDistance
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProximityTiers(t *testing.T) {
+	tiers := DefaultProximityTiers()
+	tests := []struct {
+		distance float64
+		index    int
+		name     string
+	}{
+		{2, 0, "overhead"},
+		{5, 0, "overhead"},
+		{12, 1, "nearby"},
+		{80, 2, "in range"},
+		{250, -1, ""},
+	}
+	for _, tt := range tests {
+		if index, name := tiers.Tier(tt.distance); index != tt.index || name != tt.name {
+			t.Errorf("Tier(%.0f) = %d, %q, expected %d, %q", tt.distance, index, name, tt.index, tt.name)
+		}
+	}
+
+	if distance, err := tiers.MaxDistance("nearby"); err != nil || distance != 20 {
+		t.Errorf("MaxDistance(nearby) = %.0f, %v, expected 20", distance, err)
+	}
+	if _, err := tiers.MaxDistance("far away"); err == nil {
+		t.Error("MaxDistance(far away) succeeded, expected an error")
+	}
+}
+
+func TestLoadConfigRejectsInvalidTiers(t *testing.T) {
+	for _, content := range []string{
+		`{"tiers": [{"name": "nearby", "max_distance": 20}, {"name": "overhead", "max_distance": 5}]}`,
+		`{"tiers": [{"name": "close", "max_distance": 5}, {"name": "close", "max_distance": 20}]}`,
+		`{"tiers": [{"name": "", "max_distance": 5}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded, expected an error", content)
+		}
+	}
+}
+
+// recordingSink keeps the events emitted to it.
+type recordingSink struct {
+	events []Event
+}
+
+func (s *recordingSink) Emit(event Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func TestRarityAlertsScopedByDistance(t *testing.T) {
+	disabled := false
+	opts := NotifyOptions{ //nolint:exhaustruct // only the sinks and the alert distance matter
+		Sinks:               SinksConfig{Desktop: SinkConfig{Enabled: &disabled}}, //nolint:exhaustruct // desktop off
+		RarityAlertDistance: 30,
+	}
+	notify, err := NewNotify("test", opts, nil, io.Discard)
+	if err != nil {
+		t.Fatalf("NewNotify() error = %v", err)
+	}
+	alerts, logs := &recordingSink{events: nil}, &recordingSink{events: nil}
+	notify.sinks = append(notify.sinks, alerts, logs)
+	notify.logSinks = append(notify.logSinks, logs)
+
+	near := &AircraftSighting{lastFlightNo: "GAF681", distance: 12, tier: "nearby"}  //nolint:exhaustruct // distance only
+	far := &AircraftSighting{lastFlightNo: "RCH123", distance: 80, tier: "in range"} //nolint:exhaustruct // distance only
+	notify.EmitRarityNotifications([]RareSighting{
+		{Rarities: RareType, Sighting: near},
+		{Rarities: RareType, Sighting: far},
+	})
+
+	if len(alerts.events) != 1 || alerts.events[0].Sighting != near {
+		t.Errorf("alerted %d events, expected only the one within the alert distance", len(alerts.events))
+	}
+	if len(logs.events) != 2 {
+		t.Errorf("logged %d events, expected both", len(logs.events))
+	}
+}
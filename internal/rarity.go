@@ -1,5 +1,13 @@
 package internal
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
 type RarityFlag int
 
 const (
@@ -16,3 +24,217 @@ const (
 	RareOperatorAndCountry  RarityFlag = 0b110
 	RareTypeOperatorCountry RarityFlag = 0b111
 )
+
+// Names of the available rarity scorers, as used in the command line options.
+const (
+	LogScorerName        = "log"
+	RatioScorerName      = "ratio"
+	SmoothedScorerName   = "smoothed"
+	PercentileScorerName = "percentile"
+	DecayedScorerName    = "decayed"
+)
+
+// Default parameters of the rarity scorers.
+const (
+	defaultRarityRatio      = 0.002               // share of all sightings below which a property is rare
+	defaultMinTotal         = 500                 // sightings required before anything is considered rare
+	defaultSmoothingAlpha   = 1.0                 // pseudo count added to every property
+	defaultRarityPercentile = 0.1                 // share of the least common properties which are rare
+	defaultMinDistinct      = 20                  // distinct properties required for percentiles
+	defaultRarityHalfLife   = 30 * 24 * time.Hour // time after which a sighting only counts half
+)
+
+var errUnknownRarityScorer = errors.New("unknown rarity scorer")
+
+// RarityObservation contains everything a RarityScorer may need to decide whether a property
+// which has just been seen is rare.
+type RarityObservation struct {
+	Category string         // Category is either "type", "operator" or "country".
+	Property string         // Property is the type, operator or country that was seen.
+	Count    int            // Count is how often the property has been seen, including this time.
+	Total    int            // Total is how many properties of this category have been seen.
+	Counts   map[string]int // Counts maps all properties of this category to their counts.
+	Time     time.Time      // Time of the sighting.
+}
+
+// RarityScorer decides whether an observed type, operator or country is rare.
+type RarityScorer interface {
+	// IsRare records the observation and reports whether the observed property is rare.
+	IsRare(observation RarityObservation) bool
+	// Name returns the name of the scorer, as used in the command line options.
+	Name() string
+	// Parameters returns a human-readable description of the scorer parameters.
+	Parameters() string
+}
+
+// RarityScorerNames returns the names of all available rarity scorers.
+func RarityScorerNames() []string {
+	return []string{
+		LogScorerName,
+		RatioScorerName,
+		SmoothedScorerName,
+		PercentileScorerName,
+		DecayedScorerName,
+	}
+}
+
+// NewRarityScorer creates the rarity scorer of the given name with its default parameters.
+func NewRarityScorer(name string) (RarityScorer, error) { //nolint:ireturn // factory by design
+	switch name {
+	case LogScorerName, "":
+		return &LogScorer{Constant: RarityConstant}, nil
+	case RatioScorerName:
+		return &RatioScorer{Ratio: defaultRarityRatio, MinTotal: defaultMinTotal}, nil
+	case SmoothedScorerName:
+		return &SmoothedScorer{
+			Alpha:    defaultSmoothingAlpha,
+			Ratio:    defaultRarityRatio,
+			MinTotal: defaultMinTotal,
+		}, nil
+	case PercentileScorerName:
+		return &PercentileScorer{Percentile: defaultRarityPercentile, MinDistinct: defaultMinDistinct}, nil
+	case DecayedScorerName:
+		return NewDecayedScorer(defaultRarityHalfLife, defaultRarityRatio, defaultMinTotal), nil
+	}
+
+	return nil, fmt.Errorf("NewRarityScorer: %w: %s", errUnknownRarityScorer, name)
+}
+
+// LogScorer considers a property rare if it has been seen less often than the logarithm of all
+// sightings, minus a constant.
+// This is the original rarity model of airspottr.
+type LogScorer struct {
+	Constant float64
+}
+
+func (s *LogScorer) IsRare(observation RarityObservation) bool {
+	rarityThreshold := math.Log(float64(observation.Total)) - s.Constant
+	return float64(observation.Count) < rarityThreshold
+}
+
+func (s *LogScorer) Name() string { return LogScorerName }
+
+func (s *LogScorer) Parameters() string {
+	return fmt.Sprintf("count < ln(total) - %.1f", s.Constant)
+}
+
+// RatioScorer considers a property rare if its share of all sightings is below a given ratio.
+type RatioScorer struct {
+	Ratio    float64
+	MinTotal int
+}
+
+func (s *RatioScorer) IsRare(observation RarityObservation) bool {
+	if observation.Total < s.MinTotal {
+		return false
+	}
+	return float64(observation.Count)/float64(observation.Total) < s.Ratio
+}
+
+func (s *RatioScorer) Name() string { return RatioScorerName }
+
+func (s *RatioScorer) Parameters() string {
+	return fmt.Sprintf("count / total < %.4f, min. total %d", s.Ratio, s.MinTotal)
+}
+
+// SmoothedScorer works like the RatioScorer, but adds a pseudo count to every property
+// (additive smoothing) to be less jumpy while only few sightings have been made.
+type SmoothedScorer struct {
+	Alpha    float64
+	Ratio    float64
+	MinTotal int
+}
+
+func (s *SmoothedScorer) IsRare(observation RarityObservation) bool {
+	if observation.Total < s.MinTotal {
+		return false
+	}
+	distinct := float64(len(observation.Counts))
+	smoothedShare := (float64(observation.Count) + s.Alpha) /
+		(float64(observation.Total) + s.Alpha*distinct)
+	return smoothedShare < s.Ratio
+}
+
+func (s *SmoothedScorer) Name() string { return SmoothedScorerName }
+
+func (s *SmoothedScorer) Parameters() string {
+	return fmt.Sprintf(
+		"(count + %.1f) / (total + %.1f * distinct) < %.4f, min. total %d",
+		s.Alpha,
+		s.Alpha,
+		s.Ratio,
+		s.MinTotal)
+}
+
+// PercentileScorer considers a property rare if its count is among the lowest percentile of
+// all counts of the same category.
+type PercentileScorer struct {
+	Percentile  float64
+	MinDistinct int
+}
+
+func (s *PercentileScorer) IsRare(observation RarityObservation) bool {
+	if len(observation.Counts) < s.MinDistinct {
+		return false
+	}
+
+	counts := make([]int, 0, len(observation.Counts))
+	for _, count := range observation.Counts {
+		counts = append(counts, count)
+	}
+	sort.Ints(counts)
+
+	percentileIdx := int(math.Floor(s.Percentile * float64(len(counts)-1)))
+	return observation.Count <= counts[percentileIdx]
+}
+
+func (s *PercentileScorer) Name() string { return PercentileScorerName }
+
+func (s *PercentileScorer) Parameters() string {
+	percent := 100.0
+	return fmt.Sprintf("count <= %.0fth percentile, min. distinct %d", s.Percentile*percent, s.MinDistinct)
+}
+
+// DecayedScorer works like the RatioScorer, but lets every sighting lose weight exponentially over
+// time, so that properties which haven't been seen for a long time become rare again.
+type DecayedScorer struct {
+	HalfLife   time.Duration
+	Ratio      float64
+	MinTotal   int
+	categories map[string]*DecayedCounter
+}
+
+// NewDecayedScorer creates a DecayedScorer with the given half life.
+func NewDecayedScorer(halfLife time.Duration, ratio float64, minTotal int) *DecayedScorer {
+	return &DecayedScorer{
+		HalfLife:   halfLife,
+		Ratio:      ratio,
+		MinTotal:   minTotal,
+		categories: make(map[string]*DecayedCounter),
+	}
+}
+
+func (s *DecayedScorer) IsRare(observation RarityObservation) bool {
+	counter, exists := s.categories[observation.Category]
+	if !exists {
+		counter = NewDecayedCounter(s.HalfLife)
+		s.categories[observation.Category] = counter
+	}
+
+	weight := counter.Add(observation.Property, observation.Time)
+
+	if observation.Total < s.MinTotal {
+		return false
+	}
+	return weight/counter.Total(observation.Time) < s.Ratio
+}
+
+func (s *DecayedScorer) Name() string { return DecayedScorerName }
+
+func (s *DecayedScorer) Parameters() string {
+	return fmt.Sprintf(
+		"decayed count / decayed total < %.4f, half life %s, min. total %d",
+		s.Ratio,
+		s.HalfLife,
+		s.MinTotal)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRarityScorers(t *testing.T) {
+	commonCounts := map[string]int{"common": 999, "rare": 1}
+
+	tests := []struct {
+		name        string
+		scorer      string
+		observation RarityObservation
+		expected    bool
+	}{
+		{
+			name:        "log rare",
+			scorer:      LogScorerName,
+			observation: RarityObservation{"type", "rare", 1, 1000, commonCounts, time.Unix(0, 0)},
+			expected:    false, // ln(1000) - 6 < 1
+		},
+		{
+			name:        "log rare after many sightings",
+			scorer:      LogScorerName,
+			observation: RarityObservation{"type", "rare", 1, 100000, commonCounts, time.Unix(0, 0)},
+			expected:    true,
+		},
+		{
+			name:        "ratio rare",
+			scorer:      RatioScorerName,
+			observation: RarityObservation{"type", "rare", 1, 1000, commonCounts, time.Unix(0, 0)},
+			expected:    true,
+		},
+		{
+			name:        "ratio common",
+			scorer:      RatioScorerName,
+			observation: RarityObservation{"type", "common", 999, 1000, commonCounts, time.Unix(0, 0)},
+			expected:    false,
+		},
+		{
+			name:        "ratio below min total",
+			scorer:      RatioScorerName,
+			observation: RarityObservation{"type", "rare", 1, 10, commonCounts, time.Unix(0, 0)},
+			expected:    false,
+		},
+		{
+			name:        "smoothed rare",
+			scorer:      SmoothedScorerName,
+			observation: RarityObservation{"type", "rare", 1, 2000, commonCounts, time.Unix(0, 0)},
+			expected:    true,
+		},
+		{
+			name:        "percentile too few distinct",
+			scorer:      PercentileScorerName,
+			observation: RarityObservation{"type", "rare", 1, 1000, commonCounts, time.Unix(0, 0)},
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scorer, err := NewRarityScorer(test.scorer)
+			if err != nil {
+				t.Fatalf("NewRarityScorer(%s) failed: %v", test.scorer, err)
+			}
+
+			got := scorer.IsRare(test.observation)
+			if got != test.expected {
+				t.Errorf("%s.IsRare() = %v, want %v", test.scorer, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestPercentileScorer(t *testing.T) {
+	counts := make(map[string]int)
+	for idx := range 20 {
+		counts[string(rune('a'+idx))] = idx + 1
+	}
+
+	scorer := &PercentileScorer{Percentile: 0.1, MinDistinct: 20}
+	if !scorer.IsRare(RarityObservation{"type", "a", 1, 210, counts, time.Unix(0, 0)}) {
+		t.Errorf("expected least common property to be rare")
+	}
+	if scorer.IsRare(RarityObservation{"type", "t", 20, 210, counts, time.Unix(0, 0)}) {
+		t.Errorf("expected most common property not to be rare")
+	}
+}
+
+func TestDecayedScorer(t *testing.T) {
+	scorer := NewDecayedScorer(24*time.Hour, 0.01, 0)
+	start := time.Unix(0, 0)
+
+	// Build up a history where "old" used to be very common.
+	for range 100 {
+		scorer.IsRare(RarityObservation{"operator", "old", 0, 0, nil, start})
+	}
+
+	// Much later, "new" is the common one.
+	later := start.Add(30 * 24 * time.Hour)
+	for range 100 {
+		scorer.IsRare(RarityObservation{"operator", "new", 0, 0, nil, later})
+	}
+
+	if !scorer.IsRare(RarityObservation{"operator", "old", 0, 0, nil, later}) {
+		t.Errorf("expected long unseen operator to be rare again")
+	}
+}
+
+func TestUnknownRarityScorer(t *testing.T) {
+	if _, err := NewRarityScorer("nonsense"); err == nil {
+		t.Errorf("expected error for unknown rarity scorer")
+	}
+}
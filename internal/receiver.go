@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	// readsb serves its statistics and receiver details next to the aircraft.json.
+	receiverStatsFile = "stats.json"
+	receiverInfoFile  = "receiver.json"
+)
+
+var errNoLocalSource = errors.New("local receiver isn't an active source")
+
+// ReceiverStats are the feeding statistics of a local readsb receiver.
+type ReceiverStats struct {
+	Version          string
+	Uptime           time.Duration
+	SamplesProcessed uint64
+	SamplesDropped   uint64
+	Messages         uint64
+	Positions        uint64 // Positions is the number of positions decoded since the receiver started.
+	RecentMessages   uint64 // RecentMessages is the number of messages of the last minute.
+	RecentPositions  uint64 // RecentPositions is the number of positions of the last minute.
+	WithPosition     int    // WithPosition is the number of aircraft currently with a position.
+	WithoutPosition  int
+	Updated          time.Time // Updated is when the receiver wrote the statistics.
+}
+
+// readsbStatsPeriod is one of the periods of the readsb statistics, e.g. the last minute.
+type readsbStatsPeriod struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Local struct {
+		SamplesProcessed uint64 `json:"samples_processed"`
+		SamplesDropped   uint64 `json:"samples_dropped"`
+	} `json:"local"`
+	Messages  uint64 `json:"messages"`
+	Positions uint64 `json:"position_count_total"`
+}
+
+// readsbStatsResponse is the stats.json of readsb.
+type readsbStatsResponse struct {
+	Now                float64           `json:"now"`
+	AircraftWithPos    int               `json:"aircraft_with_pos"`
+	AircraftWithoutPos int               `json:"aircraft_without_pos"`
+	Last1Min           readsbStatsPeriod `json:"last1min"`
+	Total              readsbStatsPeriod `json:"total"`
+}
+
+// readsbReceiverResponse is the receiver.json of readsb.
+type readsbReceiverResponse struct {
+	Version string `json:"version"`
+}
+
+// RequestReceiverStats requests the feeding statistics from the local receiver, which must be
+// one of the active sources.
+func (r *Request) RequestReceiverStats() (ReceiverStats, error) {
+	if !slices.Contains(r.ActiveSources(), SourceLocal) {
+		return ReceiverStats{}, fmt.Errorf("RequestReceiverStats: %w", errNoLocalSource)
+	}
+
+	statsURL, statsURLErr := receiverURL(r.opts.LocalURL, receiverStatsFile)
+	if statsURLErr != nil {
+		return ReceiverStats{}, fmt.Errorf("RequestReceiverStats: %w", statsURLErr)
+	}
+	statsBody, statsErr := r.sendRequestWithHeaders(r.feederClient, statsURL, nil)
+	if statsErr != nil {
+		return ReceiverStats{}, fmt.Errorf("RequestReceiverStats: %w", statsErr)
+	}
+
+	// Not every receiver tells its version, which doesn't make the statistics any less useful.
+	var receiverBody []byte
+	if infoURL, infoURLErr := receiverURL(r.opts.LocalURL, receiverInfoFile); infoURLErr == nil {
+		receiverBody, _ = r.sendRequestWithHeaders(r.feederClient, infoURL, nil)
+	}
+
+	stats, parseErr := parseReceiverStats(statsBody, receiverBody)
+	if parseErr != nil {
+		return stats, fmt.Errorf("RequestReceiverStats: %w", parseErr)
+	}
+	return stats, nil
+}
+
+// receiverURL is the URL of the given file next to the aircraft.json of the local receiver.
+func receiverURL(localURL string, file string) (string, error) {
+	parsed, parseErr := url.Parse(localURL)
+	if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("receiverURL: %w: %q", errInvalidLocal, localURL)
+	}
+	return parsed.ResolveReference(&url.URL{Path: file}).String(), nil //nolint:exhaustruct // path only
+}
+
+// parseReceiverStats reads the stats.json and, if there is one, the receiver.json of readsb.
+func parseReceiverStats(statsBody []byte, receiverBody []byte) (ReceiverStats, error) {
+	var stats readsbStatsResponse
+	if err := json.Unmarshal(statsBody, &stats); err != nil {
+		return ReceiverStats{}, fmt.Errorf("parseReceiverStats: %w", err)
+	}
+
+	var receiver readsbReceiverResponse
+	if len(receiverBody) > 0 {
+		if err := json.Unmarshal(receiverBody, &receiver); err != nil {
+			return ReceiverStats{}, fmt.Errorf("parseReceiverStats: %w", err)
+		}
+	}
+
+	return ReceiverStats{
+		Version:          receiver.Version,
+		Uptime:           secondsToDuration(stats.Total.End - stats.Total.Start),
+		SamplesProcessed: stats.Total.Local.SamplesProcessed,
+		SamplesDropped:   stats.Total.Local.SamplesDropped,
+		Messages:         stats.Total.Messages,
+		Positions:        stats.Total.Positions,
+		RecentMessages:   stats.Last1Min.Messages,
+		RecentPositions:  stats.Last1Min.Positions,
+		WithPosition:     stats.AircraftWithPos,
+		WithoutPosition:  stats.AircraftWithoutPos,
+		Updated:          time.Unix(0, 0).Add(secondsToDuration(stats.Now)),
+	}, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Second)
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testStatsJSON = `{
+	"now": 1750000000.5,
+	"aircraft_with_pos": 12,
+	"aircraft_without_pos": 3,
+	"last1min": {"start": 1749999940.5, "end": 1750000000.5, "messages": 5400,
+		"position_count_total": 310, "local": {"samples_processed": 144000000}},
+	"total": {"start": 1749913600.5, "end": 1750000000.5, "messages": 7200000,
+		"position_count_total": 410000,
+		"local": {"samples_processed": 207360000000, "samples_dropped": 2400}}
+}`
+
+func TestParseReceiverStats(t *testing.T) {
+	stats, err := parseReceiverStats([]byte(testStatsJSON), []byte(`{"version": "readsb 3.14"}`))
+	if err != nil {
+		t.Fatalf("parseReceiverStats() error = %v", err)
+	}
+
+	expected := ReceiverStats{
+		Version:          "readsb 3.14",
+		Uptime:           24 * time.Hour,
+		SamplesProcessed: 207360000000,
+		SamplesDropped:   2400,
+		Messages:         7200000,
+		Positions:        410000,
+		RecentMessages:   5400,
+		RecentPositions:  310,
+		WithPosition:     12,
+		WithoutPosition:  3,
+		Updated:          time.Unix(1750000001, 0),
+	}
+	if !stats.Updated.Equal(expected.Updated) {
+		t.Errorf("parseReceiverStats() updated %v, expected %v", stats.Updated, expected.Updated)
+	}
+	stats.Updated = expected.Updated
+	if stats != expected {
+		t.Errorf("parseReceiverStats() = %+v, expected %+v", stats, expected)
+	}
+
+	if _, err := parseReceiverStats([]byte(`{"total": "forever"}`), nil); err == nil {
+		t.Error("parseReceiverStats() accepted invalid stats")
+	}
+}
+
+func TestReceiverURL(t *testing.T) {
+	tests := []struct {
+		localURL string
+		expected string
+		isErr    bool
+	}{
+		{"http://localhost:8080/data/aircraft.json", "http://localhost:8080/data/stats.json", false},
+		{"http://pi.local/tar1090/data/", "http://pi.local/tar1090/data/stats.json", false},
+		{"ftp://localhost/aircraft.json", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		actual, err := receiverURL(tt.localURL, receiverStatsFile)
+		if (err != nil) != tt.isErr || actual != tt.expected {
+			t.Errorf("receiverURL(%q) = %q, %v, expected %q", tt.localURL, actual, err, tt.expected)
+		}
+	}
+}
+
+func TestRequestReceiverStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/data/stats.json":
+			_, _ = io.WriteString(w, testStatsJSON)
+		default:
+			http.NotFound(w, req) // receivers without a receiver.json still have statistics
+		}
+	}))
+	defer server.Close()
+
+	var stderr io.Writer = io.Discard
+	opts := testRequestOptions(0, 0)
+	opts.Sources = []string{SourceAdsbFi}
+	opts.LocalURL = server.URL + "/data/aircraft.json"
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := request.RequestReceiverStats(); !errors.Is(err, errNoLocalSource) {
+		t.Errorf("RequestReceiverStats() error = %v, expected %v", err, errNoLocalSource)
+	}
+
+	if err := request.SwitchSources([]string{SourceLocal}); err != nil {
+		t.Fatalf("SwitchSources() error = %v", err)
+	}
+	stats, err := request.RequestReceiverStats()
+	if err != nil {
+		t.Fatalf("RequestReceiverStats() error = %v", err)
+	}
+	if stats.Positions != 410000 || stats.Version != "" {
+		t.Errorf("RequestReceiverStats() = %+v", stats)
+	}
+}
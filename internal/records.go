@@ -0,0 +1,224 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// RecordsFileName is the name of the file in the data directory of the user which the all-time
+	// and daily records are kept in if no other path is given.
+	RecordsFileName = "records.json"
+	// recordDays is how many spotting days of records are kept, including today.
+	recordDays = 30
+)
+
+// FlightRecord is a copy of the aircraft which set a record, e.g. the highest one, as it was when
+// it set the record.
+type FlightRecord struct {
+	Hex          string    `json:"hex"`
+	Flight       string    `json:"flight"`
+	Registration string    `json:"registration"`
+	IcaoType     string    `json:"type"`
+	Value        float64   `json:"value"` // altitude in [ft], speed in [kt], Mach number or distance in [km]
+	Time         time.Time `json:"time"`
+}
+
+// String describes the record with the unit of its value, e.g. "DLH400 B748 at 41000 ft".
+func (record FlightRecord) String(unit string) string {
+	return fmt.Sprintf("%s %s at %.0f %s", record.Flight, record.IcaoType, record.Value, unit)
+}
+
+// MachString describes a record of Mach number, e.g. "GAF31 EUFI at Mach 1.20".
+func (record FlightRecord) MachString() string {
+	return fmt.Sprintf("%s %s at Mach %.2f", record.Flight, record.IcaoType, record.Value)
+}
+
+// RecordSet is the highest, the fastest and the farthest aircraft of a period, each nil until one
+// was seen. The fastest are kept by ground speed and by Mach number, since a tailwind inflates the
+// ground speed of an aircraft which isn't all that fast through the air.
+type RecordSet struct {
+	Highest     *FlightRecord `json:"highest,omitempty"`
+	Fastest     *FlightRecord `json:"fastest,omitempty"` // Fastest is by ground speed.
+	FastestMach *FlightRecord `json:"fastest_mach,omitempty"`
+	// Farthest is by distance, of positions accurate enough to tell, see minRecordAccuracy.
+	Farthest *FlightRecord `json:"farthest,omitempty"`
+}
+
+// record copies the aircraft into the set if it beats the highest, fastest or farthest one so far.
+func (set *RecordSet) record(aircraft *AircraftRecord, now time.Time) bool {
+	changed := false
+	if altitude, ok := aircraft.AltBaro.Feet(); ok && (set.Highest == nil || altitude > set.Highest.Value) {
+		set.Highest = newFlightRecord(aircraft, altitude, now)
+		changed = true
+	}
+	if aircraft.GroundSpeed > 0 && (set.Fastest == nil || aircraft.GroundSpeed > set.Fastest.Value) {
+		set.Fastest = newFlightRecord(aircraft, aircraft.GroundSpeed, now)
+		changed = true
+	}
+	if aircraft.Mach > 0 && (set.FastestMach == nil || aircraft.Mach > set.FastestMach.Value) {
+		set.FastestMach = newFlightRecord(aircraft, aircraft.Mach, now)
+		changed = true
+	}
+	if aircraft.CachedDist > 0 && (set.Farthest == nil || aircraft.CachedDist > set.Farthest.Value) &&
+		aircraft.PositionAccuracy() >= minRecordAccuracy {
+		set.Farthest = newFlightRecord(aircraft, aircraft.CachedDist, now)
+		changed = true
+	}
+	return changed
+}
+
+func newFlightRecord(aircraft *AircraftRecord, value float64, now time.Time) *FlightRecord {
+	return &FlightRecord{
+		Hex:          aircraft.Hex,
+		Flight:       aircraft.GetFlightNoAsStr(),
+		Registration: aircraft.Registration,
+		IcaoType:     aircraft.IcaoType,
+		Value:        value,
+		Time:         now.UTC(),
+	}
+}
+
+// persistedRecords is the file format of the records, with the days by their date, e.g.
+// "2026-10-18".
+type persistedRecords struct {
+	AllTime RecordSet            `json:"all_time"`
+	Days    map[string]RecordSet `json:"days"`
+}
+
+// RecordStats keeps the highest, the fastest and the farthest aircraft of the session, of the recent spotting
+// days and of all time. The records are copies, so they stay as they were when they were set while
+// the aircraft are replaced every poll. The daily and all-time records are persisted as a small
+// JSON file when they change, at most once per saveInterval and on closing.
+type RecordStats struct {
+	path     string // path is where the records are persisted, empty keeps them for the session.
+	day      SpottingDay
+	session  RecordSet
+	allTime  RecordSet
+	days     map[string]RecordSet // days maps the dates of the spotting days to their records.
+	schedule saveSchedule
+}
+
+// LoadRecordStats reads the all-time and daily records from the file at the given path.
+// A missing file is not an error, it simply means that no records have been set yet.
+func LoadRecordStats(path string, day SpottingDay) (*RecordStats, error) {
+	stats := &RecordStats{
+		path:     path,
+		day:      day,
+		session:  RecordSet{Highest: nil, Fastest: nil, FastestMach: nil, Farthest: nil},
+		allTime:  RecordSet{Highest: nil, Fastest: nil, FastestMach: nil, Farthest: nil},
+		days:     make(map[string]RecordSet),
+		schedule: saveSchedule{unsaved: false, saved: time.Time{}},
+	}
+	if path == "" {
+		return stats, nil
+	}
+
+	data, readErr := os.ReadFile(path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return stats, nil
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("LoadRecordStats: failed to read %s: %w", path, readErr)
+	}
+	var persisted persistedRecords
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, fmt.Errorf("LoadRecordStats: failed to unmarshal %s: %w", path, err)
+	}
+	stats.allTime = persisted.AllTime
+	for date, records := range persisted.Days {
+		stats.days[date] = records
+	}
+	return stats, nil
+}
+
+// Session returns the records of this session.
+func (rs *RecordStats) Session() RecordSet {
+	return rs.session
+}
+
+// Day returns the records of the spotting day of the given time.
+func (rs *RecordStats) Day(t time.Time) RecordSet {
+	return rs.days[rs.dateOf(t)]
+}
+
+// AllTime returns the records of all sessions, including this one.
+func (rs *RecordStats) AllTime() RecordSet {
+	return rs.allTime
+}
+
+// Record copies the aircraft of a poll into the records they beat and persists the daily and
+// all-time records if any of them changed and they are due to be saved.
+func (rs *RecordStats) Record(now time.Time, aircraft []AircraftRecord) error {
+	date := rs.dateOf(now)
+	today := rs.days[date]
+	changed := false
+	for idx := range aircraft {
+		rs.session.record(&aircraft[idx], now)
+		changed = today.record(&aircraft[idx], now) || changed
+		changed = rs.allTime.record(&aircraft[idx], now) || changed
+	}
+	if changed {
+		rs.days[date] = today
+		rs.dropOldDays(now)
+	}
+	if !rs.schedule.due(now, changed) {
+		return nil
+	}
+
+	saveErr := rs.save()
+	rs.schedule.done(now, saveErr)
+	if saveErr != nil {
+		return fmt.Errorf("RecordStats.Record: %w", saveErr)
+	}
+	return nil
+}
+
+// Flush persists the records which changed since they were saved last, e.g. on quitting.
+func (rs *RecordStats) Flush() error {
+	if !rs.schedule.unsaved {
+		return nil
+	}
+	if err := rs.save(); err != nil {
+		return fmt.Errorf("RecordStats.Flush: %w", err)
+	}
+	rs.schedule.unsaved = false
+	return nil
+}
+
+// dateOf returns the date of the spotting day of the given time.
+func (rs *RecordStats) dateOf(t time.Time) string {
+	return rs.day.Start(t).Format(time.DateOnly)
+}
+
+// dropOldDays forgets the records of the days older than recordDays.
+func (rs *RecordStats) dropOldDays(now time.Time) {
+	oldest := rs.day.Start(now).AddDate(0, 0, 1-recordDays).Format(time.DateOnly)
+	for date := range rs.days {
+		if date < oldest {
+			delete(rs.days, date)
+		}
+	}
+}
+
+// save writes the records to a temporary file first so that a failed write can't destroy the
+// existing records.
+func (rs *RecordStats) save() error {
+	if rs.path == "" {
+		return nil
+	}
+
+	data, marshalErr := json.MarshalIndent(persistedRecords{AllTime: rs.allTime, Days: rs.days}, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("save: %w", marshalErr)
+	}
+
+	if err := replaceFile(rs.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
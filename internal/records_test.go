@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordStatsCopiesAircraft(t *testing.T) {
+	day, dayErr := NewSpottingDay(0)
+	if dayErr != nil {
+		t.Fatal(dayErr)
+	}
+	records, err := LoadRecordStats("", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	poll := []AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", GroundSpeed: 480, Mach: 0.86, AltBaro: NewAltitude(38000)}, //nolint:exhaustruct // records only
+		{Hex: "4ca7b5", Flight: "RYR1AB", GroundSpeed: 520, Mach: 0.78, AltBaro: GroundAltitude()},   //nolint:exhaustruct // records only
+	}
+	if err := records.Record(now, poll); err != nil {
+		t.Fatal(err)
+	}
+	// The aircraft of the next poll replace those of this one.
+	poll[0] = AircraftRecord{Hex: "a1b2c3", Flight: "UAL1", AltBaro: NewAltitude(1000)} //nolint:exhaustruct // records only
+
+	session := records.Session()
+	if session.Highest == nil || session.Highest.Flight != "DLH400" || session.Highest.Value != 38000 {
+		t.Errorf("Session().Highest = %+v, expected DLH400 at 38000 ft", session.Highest)
+	}
+	if session.Fastest == nil || session.Fastest.Flight != "RYR1AB" || !session.Fastest.Time.Equal(now) {
+		t.Errorf("Session().Fastest = %+v, expected RYR1AB at %v", session.Fastest, now)
+	}
+	// The tailwind of RYR1AB doesn't make it the fastest through the air.
+	if session.FastestMach == nil || session.FastestMach.Flight != "DLH400" {
+		t.Errorf("Session().FastestMach = %+v, expected DLH400 at Mach 0.86", session.FastestMach)
+	}
+}
+
+func TestRecordStatsByDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	day, dayErr := NewSpottingDay(0)
+	if dayErr != nil {
+		t.Fatal(dayErr)
+	}
+	records, err := LoadRecordStats(path, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yesterday := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.Local)
+	today := yesterday.AddDate(0, 0, 1)
+
+	fast := []AircraftRecord{{Hex: "3c6444", Flight: "DLH400", GroundSpeed: 560}} //nolint:exhaustruct // speed only
+	slow := []AircraftRecord{{Hex: "4ca7b5", Flight: "RYR1AB", GroundSpeed: 420}} //nolint:exhaustruct // speed only
+	if err := records.Record(yesterday, fast); err != nil {
+		t.Fatal(err)
+	}
+	if err := records.Record(today, slow); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next session starts with the daily and all-time records, but records of its own.
+	next, loadErr := LoadRecordStats(path, day)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if fastest := next.Day(today).Fastest; fastest == nil || fastest.Flight != "RYR1AB" {
+		t.Errorf("Day(today).Fastest = %+v, expected RYR1AB", fastest)
+	}
+	if fastest := next.Day(yesterday).Fastest; fastest == nil || fastest.Flight != "DLH400" {
+		t.Errorf("Day(yesterday).Fastest = %+v, expected DLH400", fastest)
+	}
+	if fastest := next.AllTime().Fastest; fastest == nil || fastest.Value != 560 {
+		t.Errorf("AllTime().Fastest = %+v, expected DLH400 at 560 kt", fastest)
+	}
+	if next.Session().Fastest != nil {
+		t.Errorf("Session().Fastest = %+v, expected none yet", next.Session().Fastest)
+	}
+
+	// Days beyond the retention are dropped whenever the records change.
+	if err := next.Record(today.AddDate(0, 0, recordDays), slow); err != nil {
+		t.Fatal(err)
+	}
+	if fastest := next.Day(yesterday).Fastest; fastest != nil {
+		t.Errorf("Day(yesterday).Fastest = %+v, expected it dropped", fastest)
+	}
+}
+
+func TestRecordStatsFarthestNeedsAccuracy(t *testing.T) {
+	day, dayErr := NewSpottingDay(0)
+	if dayErr != nil {
+		t.Fatal(dayErr)
+	}
+	records, err := LoadRecordStats("", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	//nolint:exhaustruct // positions only
+	poll := []AircraftRecord{
+		{Hex: "3c6444", Flight: "DLH400", Lat: 55, Lon: 10, Type: "adsb_icao", Nic: 8, NacP: 9, CachedDist: 210},
+		{Hex: "4ca7b5", Flight: "RYR1AB", Lat: 57, Lon: 10, Type: "tisb_icao", CachedDist: 420},
+		{Hex: "a1b2c3", Flight: "UAL1", Lat: 56, Lon: 10, Type: "mlat", CachedDist: 310},
+		{Hex: "4b1814", Flight: "SWR1", Lat: 56, Lon: 11, Type: "adsb_icao", CachedDist: 330},
+		{Hex: "4ca123", Flight: "EIN1", Lat: 56, Lon: 12, Type: "adsb_icao", Nic: 7, NacP: 5, CachedDist: 340},
+	}
+	if err := records.Record(now, poll); err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither the TIS-B, the MLAT, the ADS-B position without NIC and NACp nor the one with a coarse
+	// NACp are known to be accurate enough to claim the record.
+	if farthest := records.Session().Farthest; farthest == nil || farthest.Flight != "DLH400" || farthest.Value != 210 {
+		t.Errorf("Session().Farthest = %+v, expected DLH400 at 210 km", farthest)
+	}
+}
+
+func TestRecordStatsSavedAtMostOncePerInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airspottr", "records.json")
+	day, dayErr := NewSpottingDay(0)
+	if dayErr != nil {
+		t.Fatal(dayErr)
+	}
+	records, err := LoadRecordStats(path, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.Local)
+	fastest := func(records *RecordStats) float64 {
+		if fastest := records.AllTime().Fastest; fastest != nil {
+			return fastest.Value
+		}
+		return 0
+	}
+	saved := func() float64 {
+		loaded, loadErr := LoadRecordStats(path, day)
+		if loadErr != nil {
+			t.Fatal(loadErr)
+		}
+		return fastest(loaded)
+	}
+
+	// The first records are saved right away, creating the directory.
+	for idx, speed := range []float64{420, 450, 480} {
+		poll := []AircraftRecord{{Hex: "3c6444", GroundSpeed: speed}} //nolint:exhaustruct // speed only
+		if err := records.Record(now.Add(time.Duration(idx)*saveInterval/2), poll); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, statErr := os.Stat(path); statErr != nil {
+		t.Fatalf("records weren't saved: %v", statErr)
+	}
+	// The second record came too soon after the first to be saved, the third one is due.
+	if speed := saved(); speed != 480 {
+		t.Errorf("saved fastest = %.0f kt, expected 480 kt once due", speed)
+	}
+
+	poll := []AircraftRecord{{Hex: "3c6444", GroundSpeed: 500}} //nolint:exhaustruct // speed only
+	if err := records.Record(now.Add(saveInterval+time.Second), poll); err != nil {
+		t.Fatal(err)
+	}
+	if speed := saved(); speed != 480 {
+		t.Errorf("saved fastest = %.0f kt, expected 480 kt until due", speed)
+	}
+	if err := records.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if speed := saved(); speed != fastest(records) || speed != 500 {
+		t.Errorf("saved fastest = %.0f kt after Flush(), expected 500 kt", speed)
+	}
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+const (
+	// bboxValues is the length of a bounding box, [south, west, north, east].
+	bboxValues = 4
+	// maxRegionCircles is how many circles may cover a region, each of which is requested from
+	// every source on every poll. Germany takes four.
+	maxRegionCircles = 9
+	// minutesPerDegree is how many nautical miles a degree of latitude is, one per arc minute.
+	minutesPerDegree = 60
+)
+
+var errInvalidRegion = errors.New("invalid region")
+
+// RegionConfig is a region to request aircraft from instead of the circle around the location,
+// e.g. a flight corridor or an entire country. It is either a bounding box of
+// [south, west, north, east] or a polygon of [lat, lon] corners, like the watch areas:
+//
+//	{"bbox": [47.3, 5.9, 55.1, 15.0]}
+//	{"polygon": [[53.6, 9.9], [50.0, 8.5], [48.3, 11.8], [52.4, 13.5]]}
+type RegionConfig struct {
+	BBox    []float64   `json:"bbox"`
+	Polygon [][]float64 `json:"polygon"`
+}
+
+// Region is where aircraft are requested from. The sources can only be asked for a circle, so the
+// circle around the region is requested, or several circles covering it if it is larger than the
+// sources allow, and the aircraft outside the region are left out.
+type Region struct {
+	corners []dash.Coordinates
+	center  dash.Coordinates
+	circles []queryCircle // circles cover the bounding box of the region.
+}
+
+// queryCircle is a circle requested from the sources.
+type queryCircle struct {
+	lat    float64
+	lon    float64
+	radius float64 // radius is in whole [NM], as the sources take it.
+}
+
+// NewRegion checks the region of the config, nil if it has neither bounding box nor polygon.
+// Regions can't cross the antimeridian, since their corners are ordered by longitude, and need to
+// be covered by at most maxRegionCircles circles.
+func NewRegion(config RegionConfig) (*Region, error) {
+	var polygon [][]float64
+	switch {
+	case config.BBox != nil && config.Polygon != nil:
+		return nil, fmt.Errorf("NewRegion: %w: either bbox or polygon, not both", errInvalidRegion)
+	case config.BBox != nil:
+		corners, err := bboxCorners(config.BBox)
+		if err != nil {
+			return nil, fmt.Errorf("NewRegion: %w", err)
+		}
+		polygon = corners
+	case config.Polygon != nil:
+		polygon = config.Polygon
+	default:
+		return nil, nil //nolint:nilnil // no region is not an error
+	}
+
+	if len(polygon) < minAreaCorners {
+		return nil, fmt.Errorf("NewRegion: %w: the polygon needs at least %d corners",
+			errInvalidRegion, minAreaCorners)
+	}
+	corners := make([]dash.Coordinates, 0, len(polygon))
+	south, north := float64(maxLatitude), float64(-maxLatitude)
+	west, east := float64(maxLongitude), float64(-maxLongitude)
+	for _, corner := range polygon {
+		if !isValidCorner(corner) {
+			return nil, fmt.Errorf("NewRegion: %w: corner %v, expected [lat, lon]", errInvalidRegion, corner)
+		}
+		corners = append(corners, dash.NewCoordinates(corner[0], corner[1]))
+		south, north = min(south, corner[0]), max(north, corner[0])
+		west, east = min(west, corner[1]), max(east, corner[1])
+	}
+
+	circles, circlesErr := coverBox(south, west, north, east)
+	if circlesErr != nil {
+		return nil, fmt.Errorf("NewRegion: %w", circlesErr)
+	}
+	center := dash.NewCoordinates((south+north)/2, (west+east)/2) //nolint:mnd // middle
+	return &Region{corners: corners, center: center, circles: circles}, nil
+}
+
+// coverBox returns the fewest circles which the sources allow to cover the bounding box with, by
+// splitting it into a grid of ever more rows or columns, whichever are longer.
+func coverBox(south float64, west float64, north float64, east float64) ([]queryCircle, error) {
+	maxDist := maxQueryRadius()
+	// The columns are widest at the latitude closest to the equator.
+	equatorward := 0.0
+	if south > 0 || north < 0 {
+		equatorward = min(math.Abs(south), math.Abs(north))
+	}
+	height := (north - south) * minutesPerDegree
+	width := (east - west) * minutesPerDegree * math.Cos(equatorward*math.Pi/180) //nolint:mnd // radians
+
+	for rows, cols := 1, 1; rows*cols <= maxRegionCircles; {
+		circles := gridCircles(south, west, north, east, rows, cols)
+		if !slices.ContainsFunc(circles, func(circle queryCircle) bool { return circle.radius > maxDist }) {
+			return circles, nil
+		}
+		if height/float64(rows) >= width/float64(cols) {
+			rows++
+		} else {
+			cols++
+		}
+	}
+	return nil, fmt.Errorf("coverBox: %w: it takes more than %d circles of %.0f NM to request",
+		errInvalidRegion, maxRegionCircles, maxDist)
+}
+
+// gridCircles returns the circles around the cells of the bounding box split into the given rows
+// and columns.
+func gridCircles(south float64, west float64, north float64, east float64, rows int, cols int) []queryCircle {
+	circles := make([]queryCircle, 0, rows*cols)
+	cellHeight, cellWidth := (north-south)/float64(rows), (east-west)/float64(cols)
+	for row := range rows {
+		for col := range cols {
+			cellSouth, cellWest := south+float64(row)*cellHeight, west+float64(col)*cellWidth
+			center := dash.NewCoordinates(cellSouth+cellHeight/2, cellWest+cellWidth/2) //nolint:mnd // middle
+			radius := 0.0
+			for _, corner := range []dash.Coordinates{
+				dash.NewCoordinates(cellSouth, cellWest),
+				dash.NewCoordinates(cellSouth+cellHeight, cellWest),
+				dash.NewCoordinates(cellSouth+cellHeight, cellWest+cellWidth),
+				dash.NewCoordinates(cellSouth, cellWest+cellWidth),
+			} {
+				radius = max(radius, dash.Distance(center, corner).NauticalMiles())
+			}
+			circles = append(circles, queryCircle{
+				lat:    center.Latitude,
+				lon:    center.Longitude,
+				radius: math.Ceil(radius),
+			})
+		}
+	}
+	return circles
+}
+
+// bboxCorners returns the corners of the bounding box of [south, west, north, east].
+func bboxCorners(bbox []float64) ([][]float64, error) {
+	if len(bbox) != bboxValues {
+		return nil, fmt.Errorf("bboxCorners: %w: bbox %v, expected [south, west, north, east]",
+			errInvalidRegion, bbox)
+	}
+	south, west, north, east := bbox[0], bbox[1], bbox[2], bbox[3]
+	if south >= north || west >= east {
+		return nil, fmt.Errorf(
+			"bboxCorners: %w: bbox %v, expected south < north and west < east, it can't cross the antimeridian",
+			errInvalidRegion, bbox)
+	}
+	return [][]float64{{south, west}, {north, west}, {north, east}, {south, east}}, nil
+}
+
+// Center returns the latitude and longitude of the middle of the region.
+func (r *Region) Center() (float64, float64) {
+	return r.center.Latitude, r.center.Longitude
+}
+
+// Contains tells whether the position lies within the region.
+func (r *Region) Contains(lat float64, lon float64) bool {
+	return polygonContains(r.corners, dash.NewCoordinates(lat, lon))
+}
+
+// Filter returns the aircraft within the region, in their order, reusing the given slice.
+// Aircraft without a known position can't be told to be inside, so they are left out.
+func (r *Region) Filter(aircraft []AircraftRecord) []AircraftRecord {
+	inside := aircraft[:0]
+	for _, record := range aircraft {
+		if position, ok := record.KnownPosition(); ok && r.Contains(position.Lat, position.Lon) {
+			inside = append(inside, record)
+		}
+	}
+	return inside
+}
+
+// queryCircles returns the circles requested from the sources: the circle around the location, or
+// the circles covering the region if there is one.
+func (opts RequestOptions) queryCircles() []queryCircle {
+	if opts.Region == nil {
+		return []queryCircle{{lat: opts.Lat, lon: opts.Lon, radius: maxQueryRadius()}}
+	}
+	return opts.Region.circles
+}
+
+// contains tells whether the position lies within the circle.
+func (circle queryCircle) contains(lat float64, lon float64) bool {
+	distance := dash.Distance(dash.NewCoordinates(circle.lat, circle.lon), dash.NewCoordinates(lat, lon))
+	return distance.NauticalMiles() <= circle.radius
+}
+
+// maxQueryRadius is the largest radius in [NM] the sources can be asked for.
+func maxQueryRadius() float64 {
+	maxDist, _ := strconv.ParseFloat(aircraftReqDist, 64)
+	return maxDist
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewRegion(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RegionConfig
+		valid  bool
+	}{
+		{name: "bbox", config: RegionConfig{BBox: []float64{53, 10, 54, 11}, Polygon: nil}, valid: true},
+		{
+			name:   "polygon",
+			config: RegionConfig{BBox: nil, Polygon: [][]float64{{53, 10}, {54, 10}, {53.5, 11}}},
+			valid:  true,
+		},
+		{name: "bbox too short", config: RegionConfig{BBox: []float64{53, 10, 54}, Polygon: nil}, valid: false},
+		{name: "bbox upside down", config: RegionConfig{BBox: []float64{54, 10, 53, 11}, Polygon: nil}, valid: false},
+		{name: "bbox beyond a pole", config: RegionConfig{BBox: []float64{53, 10, 95, 11}, Polygon: nil}, valid: false},
+		{
+			name:   "bbox across the antimeridian",
+			config: RegionConfig{BBox: []float64{-50, 165, -34, -178}, Polygon: nil},
+			valid:  false,
+		},
+		{name: "bbox of Europe", config: RegionConfig{BBox: []float64{35, -10, 70, 40}, Polygon: nil}, valid: false},
+		{
+			name:   "too few corners",
+			config: RegionConfig{BBox: nil, Polygon: [][]float64{{53, 10}, {54, 10}}},
+			valid:  false,
+		},
+		{
+			name:   "both",
+			config: RegionConfig{BBox: []float64{53, 10, 54, 11}, Polygon: [][]float64{{53, 10}, {54, 10}, {53.5, 11}}},
+			valid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, err := NewRegion(tt.config)
+			if tt.valid && (err != nil || region == nil) {
+				t.Errorf("NewRegion(%+v) = %v, %v, expected a region", tt.config, region, err)
+			}
+			if !tt.valid && !errors.Is(err, errInvalidRegion) {
+				t.Errorf("NewRegion(%+v) = %v, expected errInvalidRegion", tt.config, err)
+			}
+		})
+	}
+}
+
+func TestNewRegionNone(t *testing.T) {
+	region, err := NewRegion(RegionConfig{BBox: nil, Polygon: nil})
+	if region != nil || err != nil {
+		t.Errorf("NewRegion() = %v, %v, expected no region", region, err)
+	}
+}
+
+func TestRegionFilter(t *testing.T) {
+	region, err := NewRegion(RegionConfig{BBox: []float64{53, 10, 54, 11}, Polygon: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lat, lon := region.Center(); lat != 53.5 || lon != 10.5 {
+		t.Errorf("Center() = %v, %v, expected 53.5, 10.5", lat, lon)
+	}
+
+	//nolint:exhaustruct // positions only
+	aircraft := []AircraftRecord{
+		{Hex: "inside", Lat: 53.5, Lon: 10.5},
+		{Hex: "north", Lat: 54.5, Lon: 10.5},
+		{Hex: "unknown"},
+		{Hex: "last", LastPosition: &LastPosition{Lat: 53.2, Lon: 10.2, SeenPos: 5}},
+	}
+	inside := region.Filter(aircraft)
+	hexes := make([]string, 0, len(inside))
+	for _, record := range inside {
+		hexes = append(hexes, record.Hex)
+	}
+	if strings.Join(hexes, ",") != "inside,last" {
+		t.Errorf("Filter() = %v, expected inside and last", hexes)
+	}
+}
+
+func TestQueryCircles(t *testing.T) {
+	opts := RequestOptions{Lat: 53.63, Lon: 9.99} //nolint:exhaustruct // only the location
+	circles := opts.queryCircles()
+	if len(circles) != 1 || circles[0] != (queryCircle{lat: 53.63, lon: 9.99, radius: 250}) {
+		t.Errorf("queryCircles() = %+v without a region, expected the location and 250 NM", circles)
+	}
+
+	hamburg, err := NewRegion(RegionConfig{BBox: []float64{53.4, 9.7, 53.8, 10.3}, Polygon: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Region = hamburg
+	circles = opts.queryCircles()
+	if len(circles) != 1 || math.Abs(circles[0].lat-53.6) > 1e-9 || circles[0].radius < 1 || circles[0].radius >= 250 {
+		t.Errorf("queryCircles() = %+v for Hamburg, expected a single circle around its middle", circles)
+	}
+	reqURLs, err := createAircraftReqURLs(SourceAdsbFi, opts)
+	if err != nil || len(reqURLs) != 1 {
+		t.Fatalf("createAircraftReqURLs() = %v, %v, expected a single URL", reqURLs, err)
+	}
+	if !strings.HasSuffix(reqURLs[0], "/dist/"+strconv.FormatFloat(circles[0].radius, 'f', 0, 64)) {
+		t.Errorf("createAircraftReqURLs() = %s, expected the distance %v", reqURLs[0], circles[0].radius)
+	}
+
+	// Germany is too large for a single request, so it is covered by several circles.
+	germany, err := NewRegion(RegionConfig{BBox: []float64{47.3, 5.9, 55.1, 15.0}, Polygon: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Region = germany
+	circles = opts.queryCircles()
+	if len(circles) < 2 || len(circles) > maxRegionCircles {
+		t.Fatalf("queryCircles() = %+v for Germany, expected several circles", circles)
+	}
+	for _, corner := range [][]float64{{47.3, 5.9}, {55.1, 5.9}, {55.1, 15.0}, {47.3, 15.0}, {51.2, 10.45}} {
+		covers := func(circle queryCircle) bool { return circle.contains(corner[0], corner[1]) }
+		if !slices.ContainsFunc(circles, covers) {
+			t.Errorf("queryCircles() = %+v for Germany, expected %v covered", circles, corner)
+		}
+	}
+	for _, circle := range circles {
+		if circle.radius > 250 {
+			t.Errorf("queryCircles() = %+v for Germany, expected at most 250 NM each", circles)
+		}
+	}
+	if reqURLs, err := createAircraftReqURLs(SourceAdsbFi, opts); err != nil || len(reqURLs) != len(circles) {
+		t.Errorf("createAircraftReqURLs() = %v, %v, expected one URL per circle", reqURLs, err)
+	}
+	if reqURLs, err := createAircraftReqURLs(SourceAviationstack, opts); err != nil || len(reqURLs) != 1 {
+		t.Errorf("createAircraftReqURLs(aviationstack) = %v, %v, expected a single request", reqURLs, err)
+	}
+}
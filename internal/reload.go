@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// Reload re-reads the datasets and the config file and swaps them into the dashboard and the
+// notifier, e.g. to know about newly added aircraft types without restarting.
+// Of the config, the alert rules, the operator groups and the summary are reloaded. Everything else, like the sinks
+// and the command line options, takes a restart.
+// Nothing is swapped unless all of it could be loaded, so a broken config or dataset keeps the
+// previous ones in place.
+func Reload(configPath string, dashboard *Dashboard, notify *Notify) error {
+	config, configErr := LoadConfig(configPath)
+	if configErr != nil {
+		return fmt.Errorf("Reload: %w", configErr)
+	}
+
+	alertRules, rulesErr := compileRules(config.Rules)
+	if rulesErr != nil {
+		return fmt.Errorf("Reload: %w caused by %w", errCompileRules, rulesErr)
+	}
+
+	// The datasets may have been updated in the meantime.
+	dataDirs := dashboard.DataDirs()
+	loaded := loadDatasets(dataDirs, nil)
+	if loadErr := loaded.err(); loadErr != nil {
+		return fmt.Errorf("Reload: %w", loadErr)
+	}
+
+	dashboard.swapDatasets(loaded, dataDirs, alertRules, config.OperatorGroups)
+	notify.summary = config.Summary
+	return nil
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults apart from the rules
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{RarityScorer: "ratio"}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	notify := &Notify{} //nolint:exhaustruct // only the summary is reloaded
+
+	configPath := filepath.Join(t.TempDir(), "airspottr.json")
+	writeConfig := func(content string) {
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(`{
+		"rules": [{"name": "superjumbo", "condition": "type == \"A388\"", "actions": ["log"]}],
+		"summary": {"types": {"limit": 10}}
+	}`)
+	if err := Reload(configPath, dashboard, notify); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(dashboard.alertRules) != 1 || notify.summary.Types.Limit != 10 {
+		t.Errorf("Reload() swapped in %d rules and summary %+v", len(dashboard.alertRules), notify.summary)
+	}
+	if len(dashboard.IcaoToAircraft) == 0 {
+		t.Error("Reload() left no aircraft types")
+	}
+
+	// A broken config keeps everything as it was.
+	writeConfig(`{"rules": [{"name": "broken", "condition": "type ==", "actions": ["log"]}]}`)
+	if err := Reload(configPath, dashboard, notify); err == nil {
+		t.Error("Reload() accepted a broken rule")
+	}
+	if len(dashboard.alertRules) != 1 || notify.summary.Types.Limit != 10 {
+		t.Error("Reload() swapped in a broken config")
+	}
+}
@@ -2,7 +2,6 @@ package internal
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,26 +9,37 @@ import (
 	"log" //nolint:depguard // Don't feel like using slog
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	// AircraftUpdateInterval determines the update rate for general aircraft.
+	// AircraftUpdateInterval is the default update rate for general aircraft.
 	AircraftUpdateInterval = 30 * time.Second
-	// SummaryInterval determines how often the summary is show.
+	// SummaryInterval is the default of how often the summary is shown.
 	SummaryInterval = 1 * time.Hour
-	// DashboardWarmup determines how long to 'warm up' before showing rarity reports.
+	// DashboardWarmup is the default of how long to 'warm up' before showing rarity reports.
 	DashboardWarmup = 1 * time.Hour
+	// WeeklyReportInterval determines how often the report based on the sighting history is shown.
+	WeeklyReportInterval = 7 * 24 * time.Hour
 
-	aircraftReqHost    = "opendata.adsb.fi"
-	flightrouteReqHost = "api.adsbdb.com"
+	aircraftReqHost = "opendata.adsb.fi"
+	adsbLolReqHost  = "api.adsb.lol"
+	adsbOneReqHost  = "api.adsb.one"
+	// adsbExchangeReqHost is the RapidAPI host of ADS-B Exchange.
+	adsbExchangeReqHost  = "adsbexchange-com1.p.rapidapi.com"
+	aviationstackReqHost = "api.aviationstack.com"
+	flightrouteReqHost   = "api.adsbdb.com"
+	photoReqHost         = "api.planespotters.net"
+
+	// userAgent identifies us towards the APIs, some of them reject requests without one.
+	userAgent = "airspottr"
 
 	requestTimeout = 25 * time.Second
-	// UrlAdsbOne         = "https://api.adsb.one/v2/point/%.6f/%.6f/%d"
-	// UrlAdsbLol         = "https://api.adsb.lol/v2/lat/%.6f/lon/%.6f/dist/%d"
+	// maxResponseSize caps the size of responses, which are read no further, in [bytes]. A 250 NM
+	// radius around a hub rarely takes more than a few MB.
+	maxResponseSize = 64 << 20
 )
 
 var (
@@ -38,102 +48,370 @@ var (
 	ErrNonJSONContent    = errors.New("non-JSON content type")
 	ErrInvalidURL        = errors.New("invalid or insecure URL")
 	ErrUnauthorizedHost  = errors.New("unauthorized host")
+	ErrResponseTooLarge  = errors.New("response too large")
 )
 
 type RequestOptions struct {
-	Lat float64
-	Lon float64
+	Lat     float64
+	Lon     float64
+	Sources []string          // Sources to request aircraft from, in order of preference.
+	APIKeys map[string]string // APIKeys of the sources which need authentication, by source.
+	// LocalURL is where a local receiver like dump1090 serves its aircraft.json, if there is one.
+	LocalURL string
+	// ADSCURL is where a feed of ADS-C or satellite positions serves readsb-style JSON, if any.
+	ADSCURL string
+	// ClientCertFile and ClientKeyFile are the PEM files of the client certificate presented to a
+	// local receiver or private feeder which requires mutual TLS, empty if there is none.
+	ClientCertFile string
+	ClientKeyFile  string
+	// CACertFile is the PEM file of the CA which signed the certificate of the feeder, if it isn't
+	// signed by a public CA.
+	CACertFile string
+	// PhotoCacheDir is where downloaded photos are kept, empty if they aren't kept.
+	PhotoCacheDir string
+	// Region is where aircraft are requested from instead of the circle around the location, nil
+	// if there is none.
+	Region *Region
+	// Follow is the only aircraft to request, from the per-aircraft endpoints of the sources, nil
+	// to request all aircraft around the location.
+	Follow *FollowTarget
+	// ExcludeTISB leaves out the aircraft whose position was received by TIS-B, which is coarse and
+	// late, so that another source may report them by ADS-B or MLAT instead.
+	ExcludeTISB bool
+	// Clock tells the time of the polls, the SystemClock if nil. It should be the Clock of the
+	// dashboard, which the feed status is compared with.
+	Clock Clock
 }
 
 // Request handles http request commands.
 type Request struct {
-	aircraftReqURL string
-	apiClient      *http.Client
-	waitGroup      sync.WaitGroup
-	errOut         log.Logger
+	opts            RequestOptions
+	aircraftSources []aircraftSource
+	apiClient       *http.Client
+	feederClient    *http.Client // feederClient requests the local source, with mutual TLS if needed.
+	waitGroup       sync.WaitGroup
+	errOut          log.Logger
+	clock           Clock
+	pollMutex       sync.Mutex
+	pollingSince    time.Time // pollingSince is when the current sources became active.
+	lastPoll        time.Time // lastPoll is the time of the most recent successful aircraft request.
+	lastPollErr     error     // lastPollErr is the error of the most recent aircraft request.
+	failingSince    time.Time // failingSince is when polls started to fail, zero if the last succeeded.
+	sourceStats     map[string]*SourceStats
+	decodeDiag      *DecodeDiagnostics // decodeDiag counts what couldn't be decoded in responses.
 }
 
 func NewRequest(opts RequestOptions, stderr *io.Writer) (*Request, error) {
-	aircraftReqURL, urlErr := createAircraftReqURL(opts)
-	if urlErr != nil {
-		return nil, fmt.Errorf("NewRequest: %w", urlErr)
+	sourceNames := opts.Sources
+	if len(sourceNames) == 0 {
+		sourceNames = []string{SourceAdsbFi}
+	}
+
+	sources, sourcesErr := newAircraftSources(sourceNames, opts)
+	if sourcesErr != nil {
+		return nil, fmt.Errorf("NewRequest: %w", sourcesErr)
 	}
 
 	client := &http.Client{
 		Timeout: requestTimeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{ //nolint:exhaustruct // too large
-				MinVersion: tls.VersionTLS13,
-				MaxVersion: tls.VersionTLS13,
-			},
+		Transport: &http.Transport{ //nolint:exhaustruct // too large
+			TLSClientConfig: newTLSConfig(),
 		},
 	}
 
+	feederClient, feederErr := newFeederClient(opts, client)
+	if feederErr != nil {
+		return nil, fmt.Errorf("NewRequest: %w", feederErr)
+	}
+
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
 	request := &Request{
-		aircraftReqURL: aircraftReqURL,
-		apiClient:      client,
-		waitGroup:      sync.WaitGroup{},
-		errOut:         *log.New(*stderr, "request ", log.LstdFlags),
+		opts:            opts,
+		aircraftSources: sources,
+		apiClient:       client,
+		feederClient:    feederClient,
+		waitGroup:       sync.WaitGroup{},
+		errOut:          *log.New(*stderr, "request ", log.LstdFlags),
+		clock:           clock,
+		pollMutex:       sync.Mutex{},
+		pollingSince:    clock.Now(),
+		lastPoll:        time.Time{},
+		lastPollErr:     nil,
+		failingSince:    time.Time{},
+		sourceStats:     make(map[string]*SourceStats),
+		decodeDiag:      NewDecodeDiagnostics(),
 	}
+	request.addSourceStats(sources)
 
 	request.errOut.Println("Request init")
 
 	return request, nil
 }
 
-func createAircraftReqURL(opts RequestOptions) (string, error) {
-	latStr := strconv.FormatFloat(opts.Lat, 'f', 6, 32)
-	lonStr := strconv.FormatFloat(opts.Lon, 'f', 6, 32)
-	baseURL := &url.URL{Scheme: "https", Host: aircraftReqHost}
-	fullURL := baseURL.JoinPath("api", "v2", "lat", latStr, "lon", lonStr, "dist", "250")
-	targetURL := fullURL.String()
-	validatedURL, valErr := validateURL(targetURL)
-	if valErr != nil {
-		return "", fmt.Errorf("sendRequest: error validating URL: %w", valErr)
-	}
-	return validatedURL, nil
-}
-
 func validateURL(targetURL string) (string, error) {
 	parsed, err := url.Parse(targetURL)
 	if err != nil || parsed.Scheme != "https" {
 		return "", ErrInvalidURL
 	}
 
-	if parsed.Host != aircraftReqHost && parsed.Host != flightrouteReqHost {
+	if parsed.Host != aircraftReqHost &&
+		parsed.Host != adsbLolReqHost &&
+		parsed.Host != adsbOneReqHost &&
+		parsed.Host != adsbExchangeReqHost &&
+		parsed.Host != aviationstackReqHost &&
+		parsed.Host != flightrouteReqHost &&
+		parsed.Host != photoReqHost {
 		return "", ErrUnauthorizedHost
 	}
 
 	return targetURL, nil
 }
 
+// RequestAircraft requests the aircraft around the location from all sources and fuses them into
+// one list. The poll only fails if none of the sources could be reached.
 func (r *Request) RequestAircraft() []AircraftRecord {
-	body, requestErr := r.sendRequest(r.aircraftReqURL)
-	if requestErr != nil {
-		r.errOut.Println(fmt.Errorf("RequestAircraft: error during request: %w", requestErr))
-		return []AircraftRecord{}
+	return r.poll().Aircraft
+}
+
+// poll requests and fuses the aircraft of all sources like RequestAircraft, and returns them
+// together with the outcome of the poll.
+func (r *Request) poll() PollResult {
+	type sourceResult struct {
+		source   string
+		aircraft []AircraftRecord
+		err      error
+	}
+
+	r.pollMutex.Lock()
+	sources := r.aircraftSources
+	pollStart := r.clock.Now()
+	r.pollMutex.Unlock()
+
+	// The sources are requested and decoded on a bounded pool of workers.
+	results := processBounded(sources, pollWorkers, func(source aircraftSource) sourceResult {
+		aircraft, err := r.requestAircraftFromSource(source)
+		return sourceResult{source: source.name, aircraft: aircraft, err: err}
+	})
+
+	aircraftBySource := make(map[string][]AircraftRecord, len(sources))
+	var pollErr error
+	var sourceResults []sourceResult
+	for result := range results {
+		sourceResults = append(sourceResults, result)
+	}
+	r.pollMutex.Lock()
+	for _, result := range sourceResults {
+		r.sourceStats[result.source].Polls++
+		if result.err != nil {
+			r.errOut.Println(fmt.Errorf("RequestAircraft: %w", result.err))
+			r.sourceStats[result.source].Errors++
+			pollErr = errors.Join(pollErr, result.err)
+			continue
+		}
+		aircraftBySource[result.source] = result.aircraft
+	}
+	if len(aircraftBySource) > 0 {
+		pollErr = nil
+	}
+
+	aircraft := FuseAircraft(sourceNames(sources), aircraftBySource, r.sourceStats)
+	r.pollMutex.Unlock()
+	switch {
+	case r.opts.Follow != nil:
+		aircraft = r.opts.Follow.Filter(aircraft)
+	case r.opts.Region != nil:
+		aircraft = r.opts.Region.Filter(aircraft)
+	}
+
+	failingSince := r.recordPoll(pollErr, pollStart)
+	return PollResult{Aircraft: aircraft, Err: pollErr, FailingSince: failingSince}
+}
+
+// SwitchSources replaces the sources aircraft are requested from, without touching anything that
+// has been spotted so far.
+// The poll state starts over with the new sources, so that the health reflects only them.
+func (r *Request) SwitchSources(names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("SwitchSources: %w: no source given", errUnknownSource)
+	}
+
+	sources, sourcesErr := newAircraftSources(names, r.opts)
+	if sourcesErr != nil {
+		return fmt.Errorf("SwitchSources: %w", sourcesErr)
+	}
+
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	r.aircraftSources = sources
+	r.addSourceStats(sources)
+	r.pollingSince = r.clock.Now()
+	r.lastPoll = time.Time{}
+	r.lastPollErr = nil
+	r.failingSince = time.Time{}
+	r.errOut.Printf("SwitchSources: now requesting aircraft from %s\n", strings.Join(names, ", "))
+	return nil
+}
+
+// ActiveSources returns the names of the sources aircraft are currently requested from.
+func (r *Request) ActiveSources() []string {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	return sourceNames(r.aircraftSources)
+}
+
+// AvailableSources returns the names of all sources which can be switched to, i.e. for which
+// API keys or URLs are configured where needed.
+func (r *Request) AvailableSources() []string {
+	var available []string
+	for _, name := range SourceNames() {
+		if _, err := newAircraftSource(name, r.opts); err == nil {
+			available = append(available, name)
+		}
+	}
+	return available
+}
+
+// addSourceStats starts the attribution stats of the given sources, unless there already are.
+// Requires the poll mutex.
+func (r *Request) addSourceStats(sources []aircraftSource) {
+	for _, source := range sources {
+		if _, ok := r.sourceStats[source.name]; !ok {
+			r.sourceStats[source.name] = &SourceStats{
+				Polls:     0,
+				Errors:    0,
+				Aircraft:  0,
+				Exclusive: 0,
+				Positions: 0,
+			}
+		}
+	}
+}
+
+func (r *Request) requestAircraftFromSource(source aircraftSource) ([]AircraftRecord, error) {
+	client := r.apiClient
+	if source.name == SourceLocal {
+		client = r.feederClient
+	}
+
+	// The circles covering a region overlap, so an aircraft may be in several responses, of which
+	// the first is kept.
+	var aircraft []AircraftRecord
+	previous := make(map[string]bool)
+	for _, reqURL := range source.reqURLs {
+		for pageURL := reqURL; pageURL != ""; {
+			var parsed []AircraftRecord
+			var parseErr error
+			requestErr := r.streamJSON(client, pageURL, source.headers, func(body io.Reader) {
+				parsed, pageURL, parseErr = source.parse(pageURL, body, r.decodeDiag, r.keepRecord)
+			})
+			if requestErr != nil {
+				return nil, fmt.Errorf("%s: error during request: %w", source.name, requestErr)
+			}
+			if parseErr != nil {
+				return nil, fmt.Errorf("%s: %w", source.name, parseErr)
+			}
+			for _, record := range parsed {
+				if !previous[record.Hex] {
+					aircraft = append(aircraft, record)
+				}
+			}
+			for _, record := range parsed {
+				previous[record.Hex] = true
+			}
+		}
 	}
+	return aircraft, nil
+}
 
-	var data aircraftResult
-	if err := json.Unmarshal(body, &data); err != nil {
-		r.errOut.Println(fmt.Errorf("RequestAircraft: failed to unmarshal Json: %w", err))
-		return []AircraftRecord{}
+// keepRecord tells whether to keep an aircraft while it is decoded, which leaves out TIS-B
+// positions if they are excluded and aircraft outside of the region or other than the followed.
+// The fused aircraft are filtered by region and followed aircraft once more, by their fused
+// position and identity.
+func (r *Request) keepRecord(record *AircraftRecord) bool {
+	if r.opts.ExcludeTISB && IsTISB(record.Type) {
+		return false
 	}
+	switch {
+	case r.opts.Follow != nil:
+		return r.opts.Follow.Matches(record)
+	case r.opts.Region != nil:
+		position, ok := record.KnownPosition()
+		return ok && r.opts.Region.Contains(position.Lat, position.Lon)
+	}
+	return true
+}
 
-	foundAircraftCount := len(data.Aircraft)
-	if foundAircraftCount == 0 {
-		return []AircraftRecord{} // Valid outcome, no need to log an error.
+// SourceStats returns the attribution of the aircraft to the sources, in order of preference.
+func (r *Request) SourceStats() ([]string, []SourceStats) {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+
+	names := sourceNames(r.aircraftSources)
+	stats := make([]SourceStats, len(names))
+	for idx, name := range names {
+		stats[idx] = *r.sourceStats[name]
 	}
+	return names, stats
+}
+
+// DecodeDiagnostics returns what couldn't be decoded in the responses of the sources so far.
+func (r *Request) DecodeDiagnostics() *DecodeDiagnostics {
+	return r.decodeDiag
+}
 
-	return data.Aircraft
+// LastPoll returns the time of the most recent successful aircraft request, which is zero if
+// there was none yet, and the error of the most recent aircraft request, if it failed.
+func (r *Request) LastPoll() (time.Time, error) {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	return r.lastPoll, r.lastPollErr
+}
+
+// FailingSince returns when the polls of the current sources started to fail without a single
+// success in between, which is zero unless the most recent poll failed.
+func (r *Request) FailingSince() time.Time {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	return r.failingSince
+}
+
+// PollingSince returns when the current sources became active.
+func (r *Request) PollingSince() time.Time {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	return r.pollingSince
+}
+
+// recordPoll keeps the outcome of a poll started at the given time, unless the sources have been
+// switched in the meantime. It returns since when the polls have been failing as of this one.
+func (r *Request) recordPoll(err error, pollStart time.Time) time.Time {
+	r.pollMutex.Lock()
+	defer r.pollMutex.Unlock()
+	if pollStart.Before(r.pollingSince) {
+		if err == nil {
+			return time.Time{}
+		}
+		return pollStart
+	}
+	r.lastPollErr = err
+	if err == nil {
+		r.lastPoll = r.clock.Now()
+		r.failingSince = time.Time{}
+	} else if r.failingSince.IsZero() {
+		r.failingSince = pollStart
+	}
+	return r.failingSince
 }
 
 func (r *Request) RequestFlightRoutesForCallsigns(callsigns []string) []FlightRouteRecord {
 	r.errOut.Printf("RequestFlightRoutesForCallsigns: %d callsigns requested\n", len(callsigns))
 	// 1. Build input urls
-	urlCount := len(callsigns)
-	urls := make([]string, urlCount)
-	for idx, callsign := range callsigns {
+	urls := make([]string, 0, len(callsigns))
+	for _, callsign := range callsigns {
 		callsignURL, urlErr := createFlightRouteRequestURL(callsign)
 		if urlErr != nil {
 			// Skip invalid urls.
@@ -143,40 +421,29 @@ func (r *Request) RequestFlightRoutesForCallsigns(callsigns []string) []FlightRo
 					urlErr))
 			continue
 		}
-		urls[idx] = callsignURL
-	}
-
-	results := make(chan []byte, urlCount)
-	var waitGroup sync.WaitGroup
-
-	// 2. Fan-out: Launch a goroutine for each URL
-	for _, reqURL := range urls {
-		waitGroup.Add(1)
-		go func(urlStr string) {
-			defer waitGroup.Done()
-
-			body, reqErr := r.sendRequest(urlStr)
-			// Only send body to results if there is no error.
-			if reqErr != nil {
-				r.errOut.Println(
-					fmt.Errorf("RequestFlightRoutesForCallsigns: error requesting url: %s: %w",
-						urlStr,
-						reqErr))
-			} else {
-				results <- body
-			}
-		}(reqURL)
+		urls = append(urls, callsignURL)
 	}
 
-	// 3. Wait and Close: Close the channel once all goroutines finish
-	go func() {
-		waitGroup.Wait()
-		close(results)
-	}()
+	// 2. Fan-out: Request the URLs on a bounded pool of workers, only failed requests yield nil.
+	results := processBounded(urls, pollWorkers, func(urlStr string) []byte {
+		body, reqErr := r.sendRequest(urlStr)
+		if reqErr != nil {
+			r.errOut.Println(
+				fmt.Errorf("RequestFlightRoutesForCallsigns: error requesting url: %s: %w",
+					urlStr,
+					reqErr))
+			return nil
+		}
+		return body
+	})
 
+	// 3. The results are closed once all URLs are done.
 	// 4. Fan-in: Collect and process results
 	var flightrouteRecords []FlightRouteRecord
 	for result := range results {
+		if result == nil {
+			continue
+		}
 		flightrouteRecord, err := r.flightRouteJSONToRecord(result)
 		if err != nil {
 			r.errOut.Println(
@@ -216,18 +483,163 @@ func (r *Request) flightRouteJSONToRecord(jsonBytes []byte) (FlightRouteRecord,
 	return data.Response.Flightroute, nil
 }
 
+// RequestPhotosForRegistrations looks up a photo for each of the given registrations.
+// Unlike flight routes, the requests are sent one after another to stay well within the rate
+// limits of planespotters.net.
+// Registrations for which the request failed are omitted from the result, registrations without
+// any photo are mapped to the default photo record.
+func (r *Request) RequestPhotosForRegistrations(registrations []string) map[string]PhotoRecord {
+	r.errOut.Printf("RequestPhotosForRegistrations: %d registrations requested\n", len(registrations))
+	photos := make(map[string]PhotoRecord)
+	for _, registration := range registrations {
+		photoURL, urlErr := createPhotoRequestURL(registration)
+		if urlErr != nil {
+			r.errOut.Println(
+				fmt.Errorf("RequestPhotosForRegistrations: error constructing url: %w", urlErr))
+			continue
+		}
+
+		body, reqErr := r.sendRequest(photoURL)
+		if reqErr != nil {
+			r.errOut.Println(
+				fmt.Errorf("RequestPhotosForRegistrations: error requesting url: %s: %w",
+					photoURL,
+					reqErr))
+			continue
+		}
+
+		var data PhotoResponse
+		if err := json.Unmarshal(body, &data); err != nil {
+			r.errOut.Println(
+				fmt.Errorf("RequestPhotosForRegistrations: error parsing json: %w", err))
+			continue
+		}
+
+		if len(data.Photos) == 0 {
+			photos[registration] = *GetDefaultPhotoRecord()
+			continue
+		}
+
+		photos[registration] = data.Photos[0]
+	}
+	r.errOut.Printf("RequestPhotosForRegistrations: %d photos found\n", len(photos))
+	return photos
+}
+
+func createPhotoRequestURL(registration string) (string, error) {
+	baseURL := &url.URL{Scheme: "https", Host: photoReqHost}
+	fullURL := baseURL.JoinPath("pub", "photos", "reg", strings.TrimSpace(registration))
+	targetURL := fullURL.String()
+	validatedURL, valErr := validateURL(targetURL)
+	if valErr != nil {
+		return "", fmt.Errorf("sendRequest: error validating URL: %w", valErr)
+	}
+	return validatedURL, nil
+}
+
 // sendRequest builds the API URL from opts, sends an HTTP GET request, and returns the response body.
 // The URL is constructed only from the fixed host and opts (lat/lon); no user-controlled URL input.
 func (r *Request) sendRequest(targetURL string) ([]byte, error) {
+	return r.sendRequestWithHeaders(r.apiClient, targetURL, nil)
+}
+
+// sendRequestWithHeaders is sendRequest with additional headers, e.g. for authentication, sent
+// by the given client.
+// Query parameters are left out of errors, since they may contain API keys.
+func (r *Request) sendRequestWithHeaders(
+	client *http.Client,
+	targetURL string,
+	headers map[string]string,
+) ([]byte, error) {
+	body, contentType, err := r.fetchWithContentType(client, targetURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(contentType, "application/json") {
+		return nil, fmt.Errorf("sendRequest: %w, %s", ErrNonJSONContent, contentType)
+	}
+	return body, nil
+}
+
+// fetch sends an HTTP GET request and returns the response body, whatever its content type.
+func (r *Request) fetch(client *http.Client, targetURL string, headers map[string]string) ([]byte, error) {
+	body, _, err := r.fetchWithContentType(client, targetURL, headers)
+	return body, err
+}
+
+// fetchWithContentType is fetch, which also returns the content type of the response.
+func (r *Request) fetchWithContentType(
+	client *http.Client,
+	targetURL string,
+	headers map[string]string,
+) ([]byte, string, error) {
+	var body []byte
+	var bodyErr error
+	contentType, err := r.openResponse(client, targetURL, headers, func(resp *http.Response) {
+		body, bodyErr = io.ReadAll(newCappedReader(resp.Body, maxResponseSize))
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if bodyErr != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", bodyErr)
+	}
+
+	if len(body) == 0 {
+		return nil, "", fmt.Errorf("sendRequest: %w", ErrEmptyResponseBody)
+	}
+
+	return body, contentType, nil
+}
+
+// streamJSON sends an HTTP GET request and hands the JSON response body to read, as it arrives,
+// rather than reading it into memory first. Reading fails beyond maxResponseSize.
+func (r *Request) streamJSON(
+	client *http.Client,
+	targetURL string,
+	headers map[string]string,
+	read func(body io.Reader),
+) error {
+	var contentErr error
+	_, err := r.openResponse(client, targetURL, headers, func(resp *http.Response) {
+		if contentType := resp.Header.Get("Content-Type"); !strings.Contains(contentType, "application/json") {
+			contentErr = fmt.Errorf("sendRequest: %w, %s", ErrNonJSONContent, contentType)
+			return
+		}
+		read(newCappedReader(resp.Body, maxResponseSize))
+	})
+	if err != nil {
+		return err
+	}
+	return contentErr
+}
+
+// openResponse sends an HTTP GET request and hands the response to read if it is OK, before
+// closing its body. It returns the content type of the response.
+func (r *Request) openResponse(
+	client *http.Client,
+	targetURL string,
+	headers map[string]string,
+	read func(resp *http.Response),
+) (string, error) {
 	ctx := context.Background()
+	loggedURL, _, _ := strings.Cut(targetURL, "?")
 	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 	if reqErr != nil {
-		return nil, fmt.Errorf("sendRequest: invalid request error: %s : %w", targetURL, reqErr)
+		return "", fmt.Errorf("sendRequest: invalid request error: %s : %w", loggedURL, reqErr)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for key, value := range headers {
+		req.Header.Set(key, value)
 	}
 
-	resp, respErr := r.apiClient.Do(req)
+	resp, respErr := client.Do(req)
 	if respErr != nil {
-		return nil, fmt.Errorf("sendRequest: failed to send GET request: %s: %w", targetURL, respErr)
+		var urlErr *url.Error
+		if errors.As(respErr, &urlErr) {
+			respErr = urlErr.Err // url.Error repeats the URL including its query
+		}
+		return "", fmt.Errorf("sendRequest: failed to send GET request: %s: %w", loggedURL, respErr)
 	}
 	defer func() {
 		closeErr := resp.Body.Close()
@@ -238,23 +650,34 @@ func (r *Request) sendRequest(targetURL string) ([]byte, error) {
 
 	// Check if the request was successful (status code 200 OK)
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("sendRequest: %w %s", ErrNonOkResponse, resp.Status)
+		return "", fmt.Errorf("sendRequest: %w %s", ErrNonOkResponse, resp.Status)
 	}
 
-	// Read the response body
-	body, bodyErr := io.ReadAll(resp.Body)
-	if bodyErr != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", bodyErr)
-	}
+	read(resp)
+	return resp.Header.Get("Content-Type"), nil
+}
 
-	if len(body) == 0 {
-		return nil, fmt.Errorf("sendRequest: %w", ErrEmptyResponseBody)
-	}
+// cappedReader reads up to its cap and fails beyond, unlike io.LimitReader, which silently ends
+// there, so that a cut off response isn't mistaken for a complete one.
+type cappedReader struct {
+	reader    io.Reader
+	maxBytes  int64
+	remaining int64
+}
 
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "application/json") {
-		return nil, fmt.Errorf("sendRequest: %w, %s", ErrNonJSONContent, contentType)
-	}
+func newCappedReader(reader io.Reader, maxBytes int64) *cappedReader {
+	return &cappedReader{reader: reader, maxBytes: maxBytes, remaining: maxBytes}
+}
 
-	return body, nil
+func (c *cappedReader) Read(p []byte) (int, error) {
+	// One byte more than remains tells whether there is more than the cap.
+	if int64(len(p)) > c.remaining+1 {
+		p = p[:c.remaining+1]
+	}
+	n, err := c.reader.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining < 0 {
+		return n, fmt.Errorf("cappedReader: %w: more than %d bytes", ErrResponseTooLarge, c.maxBytes)
+	}
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped
 }
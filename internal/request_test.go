@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testRequestOptions returns the options of a request around the given location from no source,
+// to be filled in by the tests.
+func testRequestOptions(lat float64, lon float64) RequestOptions {
+	return RequestOptions{
+		Lat:            lat,
+		Lon:            lon,
+		Sources:        nil,
+		APIKeys:        nil,
+		LocalURL:       "",
+		ADSCURL:        "",
+		ClientCertFile: "",
+		ClientKeyFile:  "",
+		CACertFile:     "",
+		PhotoCacheDir:  "",
+		Region:         nil,
+		Follow:         nil,
+		ExcludeTISB:    false,
+		Clock:          nil,
+	}
+}
+
+func TestCappedReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "below the cap", content: "12345", wantErr: false},
+		{name: "at the cap", content: "12345678", wantErr: false},
+		{name: "beyond the cap", content: "123456789", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := io.ReadAll(newCappedReader(strings.NewReader(tt.content), 8))
+			if tt.wantErr != errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("ReadAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(content) != tt.content {
+				t.Errorf("ReadAll() = %q, expected %q", content, tt.content)
+			}
+		})
+	}
+}
+
+func TestRequestAircraftFiltersWhileDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"aircraft": [
+			{"hex": "3c6444", "lat": 53.6, "lon": 9.9, "type": "adsb_icao"},
+			{"hex": "4b1805", "lat": 53.6, "lon": 9.9, "type": "tisb_icao"},
+			{"hex": "a0b1c2", "lat": 48.1, "lon": 11.6, "type": "adsb_icao"}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	region, err := NewRegion(RegionConfig{BBox: []float64{53, 9, 54, 11}, Polygon: nil})
+	if err != nil {
+		t.Fatalf("NewRegion() error = %v", err)
+	}
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // only the local receiver and the filters
+	request, err := NewRequest(RequestOptions{
+		Sources:     []string{SourceLocal},
+		LocalURL:    server.URL,
+		Region:      region,
+		ExcludeTISB: true,
+	}, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	aircraft := request.RequestAircraft()
+	if len(aircraft) != 1 || aircraft[0].Hex != "3c6444" {
+		t.Errorf("RequestAircraft() = %+v, expected only the ADS-B aircraft within the region", aircraft)
+	}
+}
@@ -0,0 +1,259 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	errUnexpectedToken = errors.New("unexpected token")
+	errUnexpectedEnd   = errors.New("unexpected end of condition")
+	errUnterminatedStr = errors.New("unterminated string")
+	errInvalidNumber   = errors.New("invalid number")
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+}
+
+// Parse turns a condition like `type == "A388" OR (altitude < 3000 AND distance < 5)` into an
+// expression which can be evaluated against the fields of an aircraft.
+//
+// Supported operators are ==, !=, <, <=, >, >= and contains, which can be combined with AND, OR,
+// NOT and parentheses. Keywords are case-insensitive, as are string comparisons.
+func Parse(condition string) (Expr, error) { //nolint:ireturn // expression tree
+	tokens, tokenErr := tokenize(condition)
+	if tokenErr != nil {
+		return nil, fmt.Errorf("Parse: %w", tokenErr)
+	}
+
+	p := parser{tokens: tokens, pos: 0}
+	expr, exprErr := p.parseOr()
+	if exprErr != nil {
+		return nil, fmt.Errorf("Parse: %w", exprErr)
+	}
+
+	if p.peek().kind != tokenEnd {
+		return nil, fmt.Errorf("Parse: %w %q at %d", errUnexpectedToken, p.peek().text, p.peek().start)
+	}
+
+	return expr, nil
+}
+
+//nolint:cyclop,funlen // a tokenizer is a big switch by nature
+func tokenize(condition string) ([]token, error) {
+	var tokens []token
+	runes := []rune(condition)
+
+	for pos := 0; pos < len(runes); {
+		char := runes[pos]
+		switch {
+		case unicode.IsSpace(char):
+			pos++
+		case char == '(':
+			tokens = append(tokens, token{tokenOpenParen, "(", pos})
+			pos++
+		case char == ')':
+			tokens = append(tokens, token{tokenCloseParen, ")", pos})
+			pos++
+		case char == '"' || char == '\'':
+			end := pos + 1
+			for end < len(runes) && runes[end] != char {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("tokenize: %w at %d", errUnterminatedStr, pos)
+			}
+			tokens = append(tokens, token{tokenString, string(runes[pos+1 : end]), pos})
+			pos = end + 1
+		case strings.ContainsRune("=!<>", char):
+			end := pos + 1
+			if end < len(runes) && runes[end] == '=' {
+				end++
+			}
+			operator := string(runes[pos:end])
+			if operator == "=" {
+				operator = "=="
+			}
+			if operator == "!" {
+				return nil, fmt.Errorf("tokenize: %w %q at %d", errUnexpectedToken, operator, pos)
+			}
+			tokens = append(tokens, token{tokenOperator, operator, pos})
+			pos = end
+		case unicode.IsDigit(char) || char == '-' || char == '.':
+			end := pos + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[pos:end]), pos})
+			pos = end
+		case unicode.IsLetter(char) || char == '_':
+			end := pos + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) ||
+				runes[end] == '_') {
+				end++
+			}
+			word := string(runes[pos:end])
+			tokens = append(tokens, token{keywordKind(word), word, pos})
+			pos = end
+		default:
+			return nil, fmt.Errorf("tokenize: %w %q at %d", errUnexpectedToken, string(char), pos)
+		}
+	}
+
+	return append(tokens, token{tokenEnd, "", len(runes)}), nil
+}
+
+func keywordKind(word string) tokenKind {
+	switch strings.ToLower(word) {
+	case "and":
+		return tokenAnd
+	case "or":
+		return tokenOr
+	case "not":
+		return tokenNot
+	case "contains":
+		return tokenOperator
+	}
+	return tokenIdent
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Expr, error) { //nolint:ireturn // expression tree
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, rightErr := p.parseAnd()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) { //nolint:ireturn // expression tree
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, rightErr := p.parseUnary()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) { //nolint:ireturn // expression tree
+	if p.peek().kind == tokenNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) { //nolint:ireturn // expression tree
+	tok := p.next()
+	switch tok.kind {
+	case tokenOpenParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenCloseParen {
+			return nil, unexpected(closing)
+		}
+		return inner, nil
+	case tokenIdent:
+		return p.parseComparison(tok)
+	case tokenEnd, tokenString, tokenNumber, tokenOperator, tokenAnd, tokenOr, tokenNot, tokenCloseParen:
+	}
+	return nil, unexpected(tok)
+}
+
+func (p *parser) parseComparison(field token) (Expr, error) { //nolint:ireturn // expression tree
+	operator := p.next()
+	if operator.kind != tokenOperator {
+		return nil, unexpected(operator)
+	}
+
+	literal := p.next()
+	switch literal.kind {
+	case tokenString, tokenIdent:
+		return comparison{
+			field:    strings.ToLower(field.text),
+			operator: strings.ToLower(operator.text),
+			text:     literal.text,
+			number:   0,
+			isNumber: false,
+		}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(literal.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q at %d", errInvalidNumber, literal.text, literal.start)
+		}
+		return comparison{
+			field:    strings.ToLower(field.text),
+			operator: strings.ToLower(operator.text),
+			text:     literal.text,
+			number:   number,
+			isNumber: true,
+		}, nil
+	case tokenEnd, tokenOperator, tokenAnd, tokenOr, tokenNot, tokenOpenParen, tokenCloseParen:
+	}
+	return nil, unexpected(literal)
+}
+
+func unexpected(tok token) error {
+	if tok.kind == tokenEnd {
+		return errUnexpectedEnd
+	}
+	return fmt.Errorf("%w %q at %d", errUnexpectedToken, tok.text, tok.start)
+}
@@ -0,0 +1,170 @@
+// Package rules provides user-defined alert conditions which are evaluated against every aircraft.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Action is what happens when a rule matches.
+type Action string
+
+const (
+	// ActionNotify sends a desktop notification.
+	ActionNotify Action = "notify"
+	// ActionWebhook posts the matching aircraft as JSON to the rule's webhook URL.
+	ActionWebhook Action = "webhook"
+	// ActionLog prints the matching aircraft to the console.
+	ActionLog Action = "log"
+)
+
+var (
+	errUnknownAction  = errors.New("unknown action")
+	errMissingWebhook = errors.New("webhook action requires a webhook URL")
+)
+
+// Fields maps the field names that conditions can refer to onto the values of one aircraft.
+// Values are either strings or float64.
+type Fields map[string]any
+
+// Expr is a parsed condition.
+type Expr interface {
+	// Eval reports whether the condition holds for the given fields.
+	Eval(fields Fields) bool
+}
+
+// Rule combines a named condition with the actions to take when it matches.
+type Rule struct {
+	Name      string
+	Condition string
+	Actions   []Action
+	Webhook   string
+	// Severity of the events of the rule, empty for the default. Rules don't know the severities,
+	// they are checked by whoever sets it.
+	Severity string
+	expr     Expr
+}
+
+// New parses the condition and validates the actions of a rule.
+func New(name string, condition string, actions []string, webhook string) (*Rule, error) {
+	expr, parseErr := Parse(condition)
+	if parseErr != nil {
+		return nil, fmt.Errorf("rule %q: %w", name, parseErr)
+	}
+
+	ruleActions := make([]Action, len(actions))
+	for idx, action := range actions {
+		switch Action(strings.ToLower(action)) {
+		case ActionNotify, ActionWebhook, ActionLog:
+			ruleActions[idx] = Action(strings.ToLower(action))
+		default:
+			return nil, fmt.Errorf("rule %q: %w: %s", name, errUnknownAction, action)
+		}
+
+		if ruleActions[idx] == ActionWebhook && webhook == "" {
+			return nil, fmt.Errorf("rule %q: %w", name, errMissingWebhook)
+		}
+	}
+
+	return &Rule{
+		Name:      name,
+		Condition: condition,
+		Actions:   ruleActions,
+		Webhook:   webhook,
+		Severity:  "",
+		expr:      expr,
+	}, nil
+}
+
+// Matches reports whether the rule's condition holds for the given fields.
+func (r *Rule) Matches(fields Fields) bool {
+	return r.expr.Eval(fields)
+}
+
+type orExpr struct {
+	left  Expr
+	right Expr
+}
+
+func (e orExpr) Eval(fields Fields) bool {
+	return e.left.Eval(fields) || e.right.Eval(fields)
+}
+
+type andExpr struct {
+	left  Expr
+	right Expr
+}
+
+func (e andExpr) Eval(fields Fields) bool {
+	return e.left.Eval(fields) && e.right.Eval(fields)
+}
+
+type notExpr struct {
+	inner Expr
+}
+
+func (e notExpr) Eval(fields Fields) bool {
+	return !e.inner.Eval(fields)
+}
+
+// comparison compares a field with a literal.
+// Fields which are missing never match, regardless of the operator.
+type comparison struct {
+	field    string
+	operator string
+	text     string
+	number   float64
+	isNumber bool
+}
+
+func (e comparison) Eval(fields Fields) bool {
+	value, exists := fields[e.field]
+	if !exists {
+		return false
+	}
+
+	if num, isNum := value.(float64); isNum && e.isNumber {
+		return compareNumbers(num, e.operator, e.number)
+	}
+
+	return compareStrings(strings.ToLower(fmt.Sprint(value)), e.operator, strings.ToLower(e.text))
+}
+
+func compareNumbers(left float64, operator string, right float64) bool {
+	switch operator {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	}
+	return false
+}
+
+func compareStrings(left string, operator string, right string) bool {
+	switch operator {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "contains":
+		return strings.Contains(left, right)
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package rules
+
+import "testing"
+
+func TestRuleMatches(t *testing.T) {
+	airForceTanker := Fields{
+		"type":     "K35R",
+		"operator": "United States Air Force",
+		"altitude": 24000.0,
+		"distance": 42.0,
+	}
+	lowA320 := Fields{
+		"type":     "A320",
+		"operator": "Lufthansa",
+		"altitude": 2500.0,
+		"distance": 3.0,
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		fields    Fields
+		expected  bool
+	}{
+		{"equal string", `type == "A388"`, lowA320, false},
+		{"equal string case-insensitive", `type == "a320"`, lowA320, true},
+		{"contains", `operator contains "Air Force"`, airForceTanker, true},
+		{"numbers", `altitude < 3000 AND distance < 5`, lowA320, true},
+		{"numbers too far", `altitude < 3000 AND distance < 5`, airForceTanker, false},
+		{
+			"example from docs",
+			`type == "A388" OR (altitude < 3000 AND distance < 5) OR operator contains "Air Force"`,
+			airForceTanker,
+			true,
+		},
+		{"not", `NOT operator contains "Air Force"`, lowA320, true},
+		{"missing field", `squawk == "7700"`, lowA320, false},
+		{"lower case keywords", `type != "A320" and altitude >= 24000`, airForceTanker, true},
+		{"single equals", `type = K35R`, airForceTanker, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := New(test.name, test.condition, []string{"log"}, "")
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			if got := rule.Matches(test.fields); got != test.expected {
+				t.Errorf("Matches() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	conditions := []string{
+		``,
+		`type ==`,
+		`type == "A388`,
+		`(type == "A388"`,
+		`type == "A388")`,
+		`"A388" == type`,
+		`type ! "A388"`,
+		`altitude < 1.2.3`,
+		`type == "A388" AND`,
+	}
+
+	for _, condition := range conditions {
+		if _, err := Parse(condition); err == nil {
+			t.Errorf("Parse(%q) expected error", condition)
+		}
+	}
+}
+
+func TestNewRuleErrors(t *testing.T) {
+	if _, err := New("bad action", `type == "A388"`, []string{"explode"}, ""); err == nil {
+		t.Errorf("expected error for unknown action")
+	}
+	if _, err := New("no webhook", `type == "A388"`, []string{"webhook"}, ""); err == nil {
+		t.Errorf("expected error for webhook action without URL")
+	}
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"iter"
+	"time"
+)
+
+// timedSample is the sample taken from a poll at the given time.
+type timedSample[T any] struct {
+	time   time.Time
+	sample T
+}
+
+// windowedSampler keeps the samples of the polls within a sliding window of time, e.g. the wind
+// reports of the last half hour.
+type windowedSampler[T any] struct {
+	window  time.Duration
+	samples []timedSample[T] // samples are within the window, oldest first.
+}
+
+// newWindowedSampler creates an empty sampler which keeps samples for the given duration.
+func newWindowedSampler[T any](window time.Duration) windowedSampler[T] {
+	return windowedSampler[T]{window: window, samples: nil}
+}
+
+// add appends the sample of a poll and forgets the samples which have fallen out of the window.
+func (ws *windowedSampler[T]) add(now time.Time, sample T) {
+	ws.samples = append(ws.samples, timedSample[T]{time: now, sample: sample})
+
+	expired := 0
+	for expired < len(ws.samples) && now.Sub(ws.samples[expired].time) > ws.window {
+		expired++
+	}
+	ws.samples = ws.samples[expired:]
+}
+
+// all yields the samples within the window, oldest first.
+func (ws *windowedSampler[T]) all() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, sample := range ws.samples {
+			if !yield(sample.sample) {
+				return
+			}
+		}
+	}
+}
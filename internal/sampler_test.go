@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWindowedSampler(t *testing.T) {
+	start := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	sampler := newWindowedSampler[int](10 * time.Minute)
+	sampler.add(start, 1)
+	sampler.add(start.Add(5*time.Minute), 2)
+	sampler.add(start.Add(10*time.Minute), 3)
+	if samples := slices.Collect(sampler.all()); !slices.Equal(samples, []int{1, 2, 3}) {
+		t.Errorf("all() = %v, expected [1 2 3] with the oldest just within the window", samples)
+	}
+
+	sampler.add(start.Add(16*time.Minute), 4)
+	if samples := slices.Collect(sampler.all()); !slices.Equal(samples, []int{3, 4}) {
+		t.Errorf("all() = %v, expected [3 4] once the older ones expired", samples)
+	}
+}
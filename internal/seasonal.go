@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// minSeasonalHistory is how much history is needed before looking for seasonal patterns.
+	minSeasonalHistory = 90 * 24 * time.Hour
+	// minSeasonalCount is how often a type or operator must be seen to detect a pattern.
+	minSeasonalCount = 5
+	// minSeasonalLift is how much more common than usual a type or operator must be during a month
+	// or week to count as a cluster.
+	minSeasonalLift = 3.0
+	// minSeasonalShare is the share of all sightings of a type or operator that the cluster must
+	// contain.
+	minSeasonalShare = 0.5
+)
+
+// SeasonalPattern describes a type or operator whose sightings cluster in a particular month or week.
+type SeasonalPattern struct {
+	Category string  // Category is either "type" or "operator".
+	Property string  // Property is the type or operator.
+	Period   string  // Period is the month or week in which the sightings cluster.
+	Count    int     // Count is how many sightings fall into the period.
+	Total    int     // Total is how many sightings there are overall.
+	Lift     float64 // Lift is how many times more common the property is in the period than usual.
+}
+
+func (p SeasonalPattern) String() string {
+	return fmt.Sprintf(
+		"%s %s clusters in %s (%d of %d sightings, %.1fx more common than usual)",
+		p.Category,
+		p.Property,
+		p.Period,
+		p.Count,
+		p.Total,
+		p.Lift)
+}
+
+// DetectSeasonalPatterns looks for types and operators whose sightings cluster in a particular
+// month of the year (e.g. cargo charters before Christmas) or in a particular week (e.g. military
+// exercises).
+// Clusters are measured relative to the overall traffic in the same period, so that busy months
+// don't make everything look seasonal.
+func DetectSeasonalPatterns(entries []HistoryEntry) []SeasonalPattern {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	first, last := entries[0].Time, entries[0].Time
+	for _, entry := range entries {
+		if entry.Time.Before(first) {
+			first = entry.Time
+		}
+		if entry.Time.After(last) {
+			last = entry.Time
+		}
+	}
+	if last.Sub(first) < minSeasonalHistory {
+		return nil
+	}
+
+	monthOf := func(t time.Time) string { return t.Month().String() }
+	weekOf := func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("week %d of %d", week, year)
+	}
+
+	var patterns []SeasonalPattern
+	for _, period := range []func(time.Time) string{monthOf, weekOf} {
+		patterns = append(patterns, detectClusters(entries, "type", func(e HistoryEntry) string {
+			return e.Type
+		}, period)...)
+		patterns = append(patterns, detectClusters(entries, "operator", func(e HistoryEntry) string {
+			return e.Operator
+		}, period)...)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].Lift > patterns[j].Lift })
+	return patterns
+}
+
+// detectClusters finds the period with the most sightings of every property and reports it if
+// the property is much more common in that period than overall.
+func detectClusters(
+	entries []HistoryEntry,
+	category string,
+	propertyOf func(HistoryEntry) string,
+	periodOf func(time.Time) string,
+) []SeasonalPattern {
+	periodTotals := make(map[string]int)
+	propertyTotals := make(map[string]int)
+	propertyPeriods := make(map[string]map[string]int)
+
+	for _, entry := range entries {
+		period := periodOf(entry.Time)
+		periodTotals[period]++
+
+		property := propertyOf(entry)
+		if property == "" || property == typeUnknown {
+			continue
+		}
+		propertyTotals[property]++
+		if propertyPeriods[property] == nil {
+			propertyPeriods[property] = make(map[string]int)
+		}
+		propertyPeriods[property][period]++
+	}
+
+	var patterns []SeasonalPattern
+	for property, total := range propertyTotals {
+		if total < minSeasonalCount {
+			continue
+		}
+
+		peakPeriod, peakCount := "", 0
+		for period, count := range propertyPeriods[property] {
+			if count > peakCount || (count == peakCount && period < peakPeriod) {
+				peakPeriod, peakCount = period, count
+			}
+		}
+
+		share := float64(peakCount) / float64(total)
+		usualShare := float64(periodTotals[peakPeriod]) / float64(len(entries))
+		lift := share / usualShare
+		if share < minSeasonalShare || lift < minSeasonalLift {
+			continue
+		}
+
+		patterns = append(patterns, SeasonalPattern{
+			Category: category,
+			Property: property,
+			Period:   peakPeriod,
+			Count:    peakCount,
+			Total:    total,
+			Lift:     lift,
+		})
+	}
+	return patterns
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectSeasonalPatterns(t *testing.T) {
+	var entries []HistoryEntry
+	start := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	// A year of steady everyday traffic.
+	for day := range 365 {
+		entries = append(entries, HistoryEntry{
+			Time:     start.AddDate(0, 0, day),
+			Type:     "AIRBUS A-320",
+			Operator: "LUFTHANSA",
+		})
+	}
+
+	// Antonov charters, almost all of them in December.
+	entries = append(entries, HistoryEntry{
+		Time:     start.AddDate(0, 5, 0),
+		Type:     "ANTONOV AN-124",
+		Operator: "VOLGA-DNEPR",
+	})
+	for day := range 6 {
+		entries = append(entries, HistoryEntry{
+			Time:     time.Date(2025, time.December, 3+day, 12, 0, 0, 0, time.UTC),
+			Type:     "ANTONOV AN-124",
+			Operator: "VOLGA-DNEPR",
+		})
+	}
+
+	patterns := DetectSeasonalPatterns(entries)
+
+	foundMonthly := false
+	for _, pattern := range patterns {
+		if pattern.Property == "AIRBUS A-320" || pattern.Property == "LUFTHANSA" {
+			t.Errorf("steady traffic detected as seasonal: %s", pattern)
+		}
+		if pattern.Property == "ANTONOV AN-124" && pattern.Period == "December" {
+			foundMonthly = true
+		}
+	}
+	if !foundMonthly {
+		t.Errorf("expected December cluster of Antonov charters, got %v", patterns)
+	}
+}
+
+func TestDetectSeasonalPatternsShortHistory(t *testing.T) {
+	now := time.Now()
+	entries := []HistoryEntry{
+		{Time: now, Type: "ANTONOV AN-124"},
+		{Time: now.Add(time.Hour), Type: "ANTONOV AN-124"},
+	}
+
+	if patterns := DetectSeasonalPatterns(entries); len(patterns) != 0 {
+		t.Errorf("expected no patterns for a short history, got %v", patterns)
+	}
+}
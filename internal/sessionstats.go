@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const statsFilePerm = 0o600
+
+// SessionStats sums up a spotting session, to be kept once the app quits.
+type SessionStats struct {
+	Start       time.Time     `json:"start"`
+	End         time.Time     `json:"end"`
+	Duration    string        `json:"duration"`
+	Aircraft    int           `json:"aircraft"` // distinct aircraft seen
+	Types       int           `json:"types"`
+	Operators   int           `json:"operators"`
+	Countries   int           `json:"countries"`
+	Highest     *FlightRecord `json:"highest"`      // nil if no aircraft reported its altitude
+	Fastest     *FlightRecord `json:"fastest"`      // nil if no aircraft reported its speed
+	FastestMach *FlightRecord `json:"fastest_mach"` // nil if no aircraft reported its Mach number
+	Farthest    *FlightRecord `json:"farthest"`     // nil if no position was accurate enough
+	RareCatches []RareCatch   `json:"rare_catches"`
+}
+
+// sessionCounter counts the distinct aircraft, types, operators and countries of the session apart
+// from the maps capped by the Limits, so that the session doesn't look smaller once they have
+// forgotten some and an aircraft seen again isn't counted twice. Only hashes of the keys are kept,
+// which take a few bytes each.
+type sessionCounter struct {
+	seed maphash.Seed
+	seen map[string]map[uint64]struct{} // categories mapped to the hashes of their keys
+}
+
+func newSessionCounter() sessionCounter {
+	return sessionCounter{seed: maphash.MakeSeed(), seen: make(map[string]map[uint64]struct{})}
+}
+
+// add counts the key of the category, unless it has been counted before.
+func (c sessionCounter) add(category string, key string) {
+	keys, ok := c.seen[category]
+	if !ok {
+		keys = make(map[uint64]struct{})
+		c.seen[category] = keys
+	}
+	keys[maphash.String(c.seed, key)] = struct{}{}
+}
+
+// count returns how many distinct keys of the category were counted.
+func (c sessionCounter) count(category string) int {
+	return len(c.seen[category])
+}
+
+// RareCatch is a sighting which was rare in at least one of type, operator and country.
+type RareCatch struct {
+	HistoryEntry
+
+	Rare       []string    `json:"rare"` // which of "type", "operator" and "country" were rare
+	Confidence Confidences `json:"confidence"`
+}
+
+// newRareCatch records the rare sighting as it is now.
+func newRareCatch(hex string, rareSighting RareSighting) RareCatch {
+	var rare []string
+	for _, flag := range []struct {
+		flag RarityFlag
+		name string
+	}{{RareType, "type"}, {RareOperator, "operator"}, {RareCountry, "country"}} {
+		if rareSighting.Rarities&flag.flag != 0 {
+			rare = append(rare, flag.name)
+		}
+	}
+	return RareCatch{
+		HistoryEntry: sightingToHistoryEntry(hex, rareSighting.Sighting),
+		Rare:         rare,
+		Confidence:   rareSighting.Sighting.confidence,
+	}
+}
+
+// SessionStats sums up the session so far.
+func (db *Dashboard) SessionStats() SessionStats {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+
+	end := db.Clock().Now()
+	records := db.Records.Session()
+	stats := SessionStats{
+		Start:       db.sessionStart.UTC(),
+		End:         end.UTC(),
+		Duration:    end.Sub(db.sessionStart).Round(time.Second).String(),
+		Aircraft:    db.session.count("aircraft"),
+		Types:       db.session.count("type"),
+		Operators:   db.session.count("operator"),
+		Countries:   db.session.count("country"),
+		Highest:     records.Highest,
+		Fastest:     records.Fastest,
+		FastestMach: records.FastestMach,
+		Farthest:    records.Farthest,
+		RareCatches: db.rareCatches,
+	}
+	return stats
+}
+
+// Export writes the statistics to the file at the given path, replacing it. Paths ending in .csv
+// are written as CSV, everything else as JSON.
+func (stats SessionStats) Export(path string) error {
+	var content strings.Builder
+	var writeErr error
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		writeErr = stats.WriteCSV(&content)
+	} else {
+		encoder := json.NewEncoder(&content)
+		encoder.SetIndent("", "  ")
+		writeErr = encoder.Encode(stats)
+	}
+	if writeErr != nil {
+		return fmt.Errorf("SessionStats.Export: %w", writeErr)
+	}
+
+	if err := os.WriteFile(path, []byte(content.String()), statsFilePerm); err != nil {
+		return fmt.Errorf("SessionStats.Export: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the statistics as rows of a field and its value. Every rare catch is a row of
+// its own, e.g. "rare_catch,DLH400 BOEING 747-8 (D-ABYA): type".
+func (stats SessionStats) WriteCSV(out io.Writer) error {
+	rows := [][]string{
+		{"field", "value"},
+		{"start", stats.Start.Format(time.RFC3339)},
+		{"end", stats.End.Format(time.RFC3339)},
+		{"duration", stats.Duration},
+		{"aircraft", strconv.Itoa(stats.Aircraft)},
+		{"types", strconv.Itoa(stats.Types)},
+		{"operators", strconv.Itoa(stats.Operators)},
+		{"countries", strconv.Itoa(stats.Countries)},
+	}
+	if stats.Highest != nil {
+		rows = append(rows, []string{"highest", stats.Highest.String("ft")})
+	}
+	if stats.Fastest != nil {
+		rows = append(rows, []string{"fastest", stats.Fastest.String("kt")})
+	}
+	if stats.FastestMach != nil {
+		rows = append(rows, []string{"fastest_mach", stats.FastestMach.MachString()})
+	}
+	if stats.Farthest != nil {
+		rows = append(rows, []string{"farthest", stats.Farthest.String("km")})
+	}
+	for _, catch := range stats.RareCatches {
+		rows = append(rows, []string{"rare_catch", fmt.Sprintf("%s %s (%s): %s",
+			catch.Flight, catch.Type, catch.Registration, strings.Join(catch.Rare, ", "))})
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("SessionStats.WriteCSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testSessionStats() SessionStats {
+	start := time.Date(2025, time.June, 1, 20, 0, 0, 0, time.UTC)
+	sighting := &AircraftSighting{ //nolint:exhaustruct // only the recorded fields matter
+		lastSeen:     start.Add(time.Hour),
+		lastFlightNo: "DLH400",
+		registration: "D-ABYA",
+		typeDesc:     "BOEING 747-8",
+		operator:     "Lufthansa",
+		country:      "Germany",
+	}
+	return SessionStats{
+		Start:       start,
+		End:         start.Add(2 * time.Hour),
+		Duration:    "2h0m0s",
+		Aircraft:    42,
+		Types:       12,
+		Operators:   9,
+		Countries:   5,
+		Highest:     &FlightRecord{Flight: "DLH400", IcaoType: "B748", Value: 41000}, //nolint:exhaustruct // exported fields only
+		Fastest:     nil,
+		FastestMach: nil,
+		Farthest:    nil,
+		RareCatches: []RareCatch{
+			newRareCatch("3c4b26", RareSighting{Rarities: RareTypeAndCountry, Sighting: sighting}),
+		},
+	}
+}
+
+func TestNewRareCatch(t *testing.T) {
+	catch := testSessionStats().RareCatches[0]
+	if catch.Hex != "3c4b26" || catch.Flight != "DLH400" {
+		t.Errorf("newRareCatch() = %+v, expected DLH400 with hex 3c4b26", catch)
+	}
+	if expected := []string{"type", "country"}; !slices.Equal(catch.Rare, expected) {
+		t.Errorf("newRareCatch() rare = %v, expected %v", catch.Rare, expected)
+	}
+}
+
+func TestSessionStatsWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	if err := testSessionStats().WriteCSV(&out); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	expected := "field,value\n" +
+		"start,2025-06-01T20:00:00Z\n" +
+		"end,2025-06-01T22:00:00Z\n" +
+		"duration,2h0m0s\n" +
+		"aircraft,42\n" +
+		"types,12\n" +
+		"operators,9\n" +
+		"countries,5\n" +
+		"highest,DLH400 B748 at 41000 ft\n" +
+		"rare_catch,\"DLH400 BOEING 747-8 (D-ABYA): type, country\"\n"
+	if out.String() != expected {
+		t.Errorf("WriteCSV() wrote %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestSessionStatsExportJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	stats := testSessionStats()
+	if err := stats.Export(path); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported SessionStats
+	if err := json.Unmarshal(content, &exported); err != nil {
+		t.Fatalf("Export() wrote invalid JSON: %v", err)
+	}
+	if exported.Aircraft != 42 || exported.Highest == nil || exported.Fastest != nil ||
+		len(exported.RareCatches) != 1 || exported.RareCatches[0].Registration != "D-ABYA" {
+		t.Errorf("Export() wrote %+v, expected %+v", exported, stats)
+	}
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Severity tells how much an event deserves attention. Every event has one, which decides the
+// sinks it is sent to if severities are routed.
+type Severity string
+
+const (
+	// SeverityInfo is an event which is only logged by default, e.g. an arrival in a watch area.
+	SeverityInfo Severity = "info"
+	// SeverityNotable is an event worth a look, e.g. an aircraft of the watchlist or an alert rule.
+	SeverityNotable Severity = "notable"
+	// SeverityRare is a rare sighting, e.g. of a rare type or a supersonic aircraft.
+	SeverityRare Severity = "rare"
+	// SeverityCritical is an event which needs attention right away, e.g. an emergency squawk.
+	SeverityCritical Severity = "critical"
+
+	// routeTUI is the route name of the notification log, which the TUI shows on its notifications
+	// page. Routing a severity to it alone keeps its events in the TUI.
+	routeTUI = "tui"
+)
+
+// Severities lists all severities, from the least to the most severe.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var Severities = []Severity{SeverityInfo, SeverityNotable, SeverityRare, SeverityCritical}
+
+// RouteNames lists the names of the sinks which severities can be routed to.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var RouteNames = []string{"console", "file", "webhook", "desktop", "sound", "social", routeTUI}
+
+// ParseSeverity checks that the name is one of the severities, SeverityXxx.
+func ParseSeverity(name string) (Severity, error) {
+	severity := Severity(name)
+	if !slices.Contains(Severities, severity) {
+		return "", fmt.Errorf("ParseSeverity: %w: unknown severity %q, expected one of %v",
+			errInvalidConfig, name, Severities)
+	}
+	return severity, nil
+}
+
+// RoutingConfig routes the events of each severity to the enabled sinks of the given names, e.g.
+//
+//	"routing": {
+//	  "info": ["tui"],
+//	  "rare": ["desktop", "webhook", "tui"],
+//	  "critical": ["desktop", "sound", "webhook", "tui"]
+//	}
+//
+// Severities which are left out go where their events went before: info events to the console and
+// file sinks, all others to all enabled sinks. The session log of --tee gets all events regardless.
+type RoutingConfig map[Severity][]string
+
+func (c RoutingConfig) validate() error {
+	for severity, names := range c {
+		if !slices.Contains(Severities, severity) {
+			return fmt.Errorf("%w: routing of unknown severity %q, expected any of %v",
+				errInvalidConfig, severity, Severities)
+		}
+		for _, name := range names {
+			if !slices.Contains(RouteNames, name) {
+				return fmt.Errorf("%w: routing of %s: unknown sink %q, expected any of %v",
+					errInvalidConfig, severity, name, RouteNames)
+			}
+		}
+	}
+	return nil
+}
+
+// routeName is the name the sink is routed by, which is its own name except for the notification
+// log, which stands in for the TUI.
+func routeName(sink EventSink) string {
+	if _, ok := sink.(*NotificationLog); ok {
+		return routeTUI
+	}
+	return sink.Name()
+}
+
+// route returns the enabled sinks routed to the severity of the event, and the tee, or the given
+// default sinks if its severity isn't routed.
+func (notify *Notify) route(event Event, defaults []EventSink) []EventSink {
+	names, ok := notify.routing[event.Severity]
+	if !ok {
+		return defaults
+	}
+	return slices.DeleteFunc(slices.Clone(notify.sinks), func(sink EventSink) bool {
+		_, isTee := sink.(*Tee)
+		return !isTee && !slices.Contains(names, routeName(sink))
+	})
+}
+
+// alert sends the event to the sinks routed to its severity, or to all enabled sinks if it isn't
+// routed. If categories are given, the desktop notification is left out unless one of them is on.
+func (notify *Notify) alert(event Event, categories ...NotificationCategory) {
+	sinks := notify.route(event, notify.sinks)
+	if len(categories) > 0 && !slices.ContainsFunc(categories, notify.IsNotifying) {
+		sinks = withoutDesktop(sinks)
+	}
+	notify.emit(event, sinks...)
+}
+
+// logEvent sends the event to the sinks routed to its severity, or to the console and file sinks
+// if it isn't routed.
+func (notify *Notify) logEvent(event Event) {
+	notify.emit(event, notify.route(event, notify.logSinks)...)
+}
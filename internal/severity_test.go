@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// namedSink is a recordingSink which is routed by the given name.
+type namedSink struct {
+	recordingSink
+
+	name string
+}
+
+func (s *namedSink) Name() string { return s.name }
+
+func TestRouteBySeverity(t *testing.T) {
+	disabled := false
+	var console bytes.Buffer
+	opts := NotifyOptions{ //nolint:exhaustruct // only the sinks and the routing matter
+		Sinks:               SinksConfig{Desktop: SinkConfig{Enabled: &disabled}}, //nolint:exhaustruct // desktop off
+		NotificationLogPath: filepath.Join(t.TempDir(), "notifications.jsonl"),
+		Routing:             RoutingConfig{SeverityInfo: {"tui"}, SeverityRare: {"webhook", "console"}},
+	}
+	notify, err := NewNotify("test", opts, &console, io.Discard)
+	if err != nil {
+		t.Fatalf("NewNotify() error = %v", err)
+	}
+	webhook := &namedSink{recordingSink: recordingSink{events: nil}, name: "webhook"}
+	notify.sinks = append(notify.sinks, webhook)
+
+	sighting := &AircraftSighting{lastFlightNo: "DLH400"} //nolint:exhaustruct // flight only
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	notify.EmitAreaMovements([]AreaMovement{{Area: "ramp", Movement: MovementArrival, Sighting: sighting}}, now)
+	if len(notify.Notifications()) != 1 || console.Len() != 0 || len(webhook.events) != 0 {
+		t.Errorf("info event went to %d notifications, %q and %d webhooks, expected only the TUI",
+			len(notify.Notifications()), console.String(), len(webhook.events))
+	}
+
+	notify.EmitRarityNotifications([]RareSighting{{Rarities: RareType, Sighting: sighting}})
+	if len(notify.Notifications()) != 1 || console.Len() == 0 || len(webhook.events) != 1 {
+		t.Errorf("rare event went to %d notifications, %q and %d webhooks, expected console and webhook",
+			len(notify.Notifications()), console.String(), len(webhook.events))
+	}
+
+	// Critical events aren't routed, so they go to all enabled sinks.
+	notify.EmitIdentityChanges([]IdentityChange{{Field: ChangeSquawk, From: "", To: "7700", Sighting: sighting}}, now)
+	if len(notify.Notifications()) != 2 || len(webhook.events) != 2 || webhook.events[1].Severity != SeverityCritical {
+		t.Errorf("critical event went to %d notifications and %d webhooks, expected all sinks",
+			len(notify.Notifications()), len(webhook.events))
+	}
+}
+
+func TestEventSeverity(t *testing.T) {
+	sighting := &AircraftSighting{lastFlightNo: "DLH400"} //nolint:exhaustruct // flight only
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	squawk := func(from string, to string) IdentityChange {
+		return IdentityChange{Field: ChangeSquawk, From: from, To: to, Sighting: sighting}
+	}
+	note := Note{Hex: "3c6444", Text: "local"}  //nolint:exhaustruct // text only
+	watched := Note{Hex: "3c6444", Watch: true} //nolint:exhaustruct // watch only
+	tests := []struct {
+		name     string
+		event    Event
+		expected Severity
+	}{
+		{
+			name:     "squawk change",
+			event:    identityChangeEvent(i18n.English, squawk("1000", "2000"), now),
+			expected: SeverityInfo,
+		},
+		{
+			name:     "emergency",
+			event:    identityChangeEvent(i18n.English, squawk("1000", "7600"), now),
+			expected: SeverityCritical,
+		},
+		{
+			name:     "note",
+			event:    noteEvent(i18n.English, note, sighting),
+			expected: SeverityInfo,
+		},
+		{
+			name:     "watchlist",
+			event:    noteEvent(i18n.English, watched, sighting),
+			expected: SeverityNotable,
+		},
+		{
+			name:     "rule",
+			event:    ruleMatchEvent(i18n.English, "low", SeverityCritical, sighting),
+			expected: SeverityCritical,
+		},
+		{
+			name:     "rarity",
+			event:    rareTypeEvent(i18n.English, sighting),
+			expected: SeverityRare,
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.event.Severity != tt.expected {
+			t.Errorf("%s: severity = %q, expected %q", tt.name, tt.event.Severity, tt.expected)
+		}
+	}
+}
+
+func TestRuleSeverity(t *testing.T) {
+	//nolint:exhaustruct // no webhook
+	compiled, err := compileRules([]RuleConfig{{Name: "low", Condition: "altitude < 1000", Severity: "critical"}})
+	if err != nil || compiled[0].Severity != string(SeverityCritical) {
+		t.Fatalf("compileRules() = %v, %v, expected a critical rule", compiled, err)
+	}
+
+	//nolint:exhaustruct // no webhook
+	_, err = compileRules([]RuleConfig{{Name: "low", Condition: "altitude < 1000", Severity: "urgent"}})
+	if !errors.Is(err, errInvalidConfig) {
+		t.Errorf("compileRules() = %v, expected errInvalidConfig", err)
+	}
+}
+
+func TestLoadConfigRejectsInvalidRouting(t *testing.T) {
+	for _, content := range []string{
+		`{"routing": {"urgent": ["desktop"]}}`,
+		`{"routing": {"rare": ["pager"]}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "airspottr.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); !errors.Is(err, errInvalidConfig) {
+			t.Errorf("LoadConfig(%s) = %v, expected errInvalidConfig", content, err)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SyncInterval determines how often the sightings of the other observers are pulled.
+	SyncInterval = 5 * time.Minute
+	// SyncPath is where the shared store is served next to the health endpoint.
+	SyncPath = "/sync"
+
+	syncTimeout   = 10 * time.Second
+	syncObserverQ = "observer"
+	syncAfterQ    = "after"
+	// syncPullLimit is how many sightings are pulled at most at once, so that the first pull of a
+	// large store is split up into several.
+	syncPullLimit = 5000
+	// maxSyncBody caps the size of the sightings pushed to the sync endpoint, and of the sightings
+	// pulled from it, in [bytes].
+	maxSyncBody = 8 << 20
+)
+
+var (
+	errInvalidObserver = errors.New("invalid observer name")
+	errInvalidSyncURL  = errors.New("invalid sync URL")
+	observerPattern    = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// SharedStore is where several instances of airspottr share their sightings, so that each of
+// them can tell rarity against the sightings of all.
+type SharedStore interface {
+	// Push adds sightings of the given observer to the store.
+	Push(observer string, entries []HistoryEntry) error
+	// Pull returns the sightings of all observers except the given one which were added after the
+	// given position in the store, at most syncPullLimit of them, and the position of the last.
+	// The first pull starts at position 0.
+	Pull(observer string, after int64) ([]HistoryEntry, int64, error)
+}
+
+// NewSharedStore creates the store at the given target, which is either the URL of another
+// instance's sync endpoint, the URL of a PostgreSQL database or the path of an SQLite database,
+// e.g. on a network share. The token is sent to the sync endpoint as bearer token, if any.
+func NewSharedStore(target string, token string) (SharedStore, error) {
+	var store SharedStore
+	var err error
+	switch {
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		store, err = NewHTTPStore(target, token)
+	case isPostgresURL(target):
+		store, err = OpenPostgresStore(target, "")
+	default:
+		store, err = OpenSQLiteStore(strings.TrimPrefix(target, StoreSQLite), "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("NewSharedStore: %w", err)
+	}
+	return store, nil
+}
+
+// ValidateObserver checks that the name can tell observers apart in a shared store.
+func ValidateObserver(observer string) error {
+	if !observerPattern.MatchString(observer) {
+		return fmt.Errorf("%w: %q", errInvalidObserver, observer)
+	}
+	return nil
+}
+
+// HTTPStore shares sightings through the sync endpoint of another instance.
+type HTTPStore struct {
+	url   string
+	token string // token is sent as bearer token, if any.
+}
+
+// syncPush is the body posted to the sync endpoint.
+type syncPush struct {
+	Observer string         `json:"observer"`
+	Entries  []HistoryEntry `json:"entries"`
+}
+
+// syncPull is the response of the sync endpoint to a pull.
+type syncPull struct {
+	Entries  []HistoryEntry `json:"entries"`
+	Position int64          `json:"position"`
+}
+
+// NewHTTPStore creates a store using the sync endpoint at the given URL, which is sent the given
+// bearer token unless it's empty.
+func NewHTTPStore(syncURL string, token string) (*HTTPStore, error) {
+	parsed, parseErr := url.Parse(syncURL)
+	if parseErr != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("NewHTTPStore: %w: %s", errInvalidSyncURL, syncURL)
+	}
+	return &HTTPStore{url: parsed.String(), token: token}, nil
+}
+
+func (s *HTTPStore) Push(observer string, entries []HistoryEntry) error {
+	body, jsonErr := json.Marshal(syncPush{Observer: observer, Entries: entries})
+	if jsonErr != nil {
+		return fmt.Errorf("HTTPStore.Push: failed to encode entries: %w", jsonErr)
+	}
+
+	if err := s.do(http.MethodPost, s.url, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("HTTPStore.Push: %w", err)
+	}
+	return nil
+}
+
+func (s *HTTPStore) Pull(observer string, after int64) ([]HistoryEntry, int64, error) {
+	pullURL, _ := url.Parse(s.url) // parsed in NewHTTPStore already
+	query := pullURL.Query()
+	query.Set(syncObserverQ, observer)
+	query.Set(syncAfterQ, strconv.FormatInt(after, 10))
+	pullURL.RawQuery = query.Encode()
+
+	var pulled syncPull
+	if err := s.do(http.MethodGet, pullURL.String(), nil, &pulled); err != nil {
+		return nil, after, fmt.Errorf("HTTPStore.Pull: %w", err)
+	}
+	return pulled.Entries, pulled.Position, nil
+}
+
+// do sends a request to the sync endpoint and decodes the response into result, unless it's nil.
+func (s *HTTPStore) do(method string, reqURL string, body io.Reader, result any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	req, reqErr := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if reqErr != nil {
+		return fmt.Errorf("invalid request: %w", reqErr)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, respErr := http.DefaultClient.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, respErr)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w %s", ErrNonOkResponse, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(newCappedReader(resp.Body, maxSyncBody)).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// syncHandler serves the store to other instances which send the given token, or to the local
+// ones if there is no token: on GET it returns the sightings of all observers except the one in
+// the query which were added after the position in the query, on POST it adds the posted
+// sightings of up to maxSyncBody.
+func syncHandler(store SharedStore, token string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		if !authorize(writer, req, token) {
+			return
+		}
+		switch req.Method {
+		case http.MethodGet:
+			query := req.URL.Query()
+			observer := query.Get(syncObserverQ)
+			if err := ValidateObserver(observer); err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+			after, afterErr := strconv.ParseInt(query.Get(syncAfterQ), 10, 64)
+			if afterErr != nil && query.Has(syncAfterQ) {
+				http.Error(writer, afterErr.Error(), http.StatusBadRequest)
+				return
+			}
+			entries, position, err := store.Pull(observer, after)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if entries == nil {
+				entries = []HistoryEntry{}
+			}
+			writer.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(writer).Encode(syncPull{Entries: entries, Position: position})
+		case http.MethodPost:
+			var body syncPush
+			if err := json.NewDecoder(http.MaxBytesReader(writer, req.Body, maxSyncBody)).Decode(&body); err != nil {
+				status := http.StatusBadRequest
+				if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				http.Error(writer, err.Error(), status)
+				return
+			}
+			if err := ValidateObserver(body.Observer); err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := store.Push(body.Observer, body.Entries); err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.Error(writer, "only GET and POST are allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// SharedStats pushes our sightings to a shared store and keeps the counts of the sightings of
+// all other observers, which are added to our own counts to tell rarity.
+// Pushing and pulling happen in the background, errors are written to the error log.
+type SharedStats struct {
+	store    SharedStore
+	observer string
+	halfLife time.Duration // halfLife lets older sightings of others weigh less, zero disables it.
+	errOut   *log.Logger
+	mutex    sync.Mutex
+	position int64                         // position is where in the store the last pull ended.
+	counts   map[string]map[string]float64 // categories mapped to properties mapped to weights
+	totals   map[string]float64            // categories mapped to the weight of all sightings
+	// rolledUp caches the counts of the categories whose properties are rolled up until the next
+	// pull, and merged holds the merged counts of every category, which merge reuses.
+	rolledUp map[string]map[string]float64
+	merged   map[string]map[string]int
+	pulled   time.Time // pulled is when the counts were last pulled, they weigh as of then.
+	pulling  bool
+}
+
+// NewSharedStats creates SharedStats for the given observer, nothing is pulled yet.
+func NewSharedStats(
+	store SharedStore,
+	observer string,
+	halfLife time.Duration,
+	errOut *log.Logger,
+) *SharedStats {
+	return &SharedStats{
+		store:    store,
+		observer: observer,
+		halfLife: halfLife,
+		errOut:   errOut,
+		mutex:    sync.Mutex{},
+		position: 0,
+		counts: map[string]map[string]float64{
+			"type":     make(map[string]float64),
+			"operator": make(map[string]float64),
+			"country":  make(map[string]float64),
+		},
+		totals:   make(map[string]float64),
+		rolledUp: make(map[string]map[string]float64),
+		merged:   make(map[string]map[string]int),
+		pulled:   time.Time{},
+		pulling:  false,
+	}
+}
+
+// Push adds our new sightings to the shared store in the background.
+func (s *SharedStats) Push(entries []HistoryEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	go func() {
+		if err := s.store.Push(s.observer, entries); err != nil {
+			s.errOut.Println(fmt.Errorf("SharedStats.Push: %w", err))
+		}
+	}()
+}
+
+// Refresh pulls the sightings of the other observers in the background, if the last pull is
+// older than the SyncInterval.
+func (s *SharedStats) Refresh(now time.Time) {
+	s.mutex.Lock()
+	due := !s.pulling && (s.pulled.IsZero() || now.Sub(s.pulled) >= SyncInterval)
+	if due {
+		s.pulling = true
+	}
+	s.mutex.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		if err := s.pull(now); err != nil {
+			s.errOut.Println(fmt.Errorf("SharedStats.Refresh: %w", err))
+		}
+	}()
+}
+
+// pull adds the sightings which the other observers added to the store since the last pull to
+// their counts, after letting the counts decay since then. The whole store is only read once.
+func (s *SharedStats) pull(now time.Time) error {
+	defer func() {
+		s.mutex.Lock()
+		s.pulling = false
+		s.mutex.Unlock()
+	}()
+
+	s.mutex.Lock()
+	position := s.position
+	s.mutex.Unlock()
+	for {
+		entries, next, pullErr := s.store.Pull(s.observer, position)
+		if pullErr != nil {
+			return fmt.Errorf("SharedStats.pull: %w", pullErr)
+		}
+		s.add(entries, next, now)
+		if len(entries) < syncPullLimit || next == position {
+			return nil
+		}
+		position = next
+	}
+}
+
+// add counts the pulled sightings, which end at the given position in the store.
+func (s *SharedStats) add(entries []HistoryEntry, position int64, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.pulled.IsZero() && now.After(s.pulled) {
+		decay := s.weight(s.pulled, now)
+		for category, counts := range s.counts {
+			for property := range counts {
+				counts[property] *= decay
+			}
+			s.totals[category] *= decay
+		}
+	}
+	for _, entry := range entries {
+		weight := s.weight(entry.Time, now)
+		for category, property := range map[string]string{
+			"type":     entry.Type,
+			"operator": entry.Operator,
+			"country":  entry.Country,
+		} {
+			s.counts[category][property] += weight
+			s.totals[category] += weight
+		}
+	}
+	clear(s.rolledUp)
+	s.position = position
+	s.pulled = now
+}
+
+// weight tells how much a sighting at the given time counts at now.
+func (s *SharedStats) weight(seen time.Time, now time.Time) float64 {
+	if s.halfLife <= 0 || !now.After(seen) {
+		return 1
+	}
+	return math.Pow(0.5, float64(now.Sub(seen))/float64(s.halfLife)) //nolint:mnd // half
+}
+
+// properties returns the properties of the category which others have seen.
+func (s *SharedStats) properties(category string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	properties := make([]string, 0, len(s.counts[category]))
+	for property := range s.counts[category] {
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// merge adds the counts of the other observers to the observation, so that rarity is told
+// against the combined baseline. The merged counts are kept in a map of their category which is
+// reused by the next merge, so that the observation's own counts don't have to be copied into a
+// new map every time. They are only valid until then.
+func (s *SharedStats) merge(observation RarityObservation, rollUp func(string) string) RarityObservation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	total := s.totals[observation.Category]
+	if total == 0 {
+		return observation
+	}
+	decay := s.weight(s.pulled, observation.Time)
+
+	others := s.counts[observation.Category]
+	if rollUp != nil {
+		rolled, ok := s.rolledUp[observation.Category]
+		if !ok {
+			rolled = rollUpCounts(others, rollUp)
+			s.rolledUp[observation.Category] = rolled
+		}
+		others = rolled
+	}
+	counts, ok := s.merged[observation.Category]
+	if !ok {
+		counts = make(map[string]int, len(observation.Counts)+len(others))
+		s.merged[observation.Category] = counts
+	}
+	clear(counts)
+	for property, count := range observation.Counts {
+		counts[property] = count
+	}
+	for property, weight := range others {
+		counts[property] += roundCount(weight * decay)
+	}
+
+	if weight, ok := others[observation.Property]; ok {
+		observation.Count += roundCount(weight * decay)
+	}
+	observation.Total += roundCount(total * decay)
+	observation.Counts = counts
+	return observation
+}
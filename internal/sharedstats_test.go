@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// staticStore is a shared store which always returns the same sightings of others.
+type staticStore struct {
+	entries []HistoryEntry
+}
+
+func (s *staticStore) Push(string, []HistoryEntry) error { return nil }
+
+func (s *staticStore) Pull(_ string, after int64) ([]HistoryEntry, int64, error) {
+	if after >= int64(len(s.entries)) {
+		return nil, after, nil
+	}
+	return s.entries[after:], int64(len(s.entries)), nil
+}
+
+func sharedEntry(seen time.Time, aType string, operator string, country string) HistoryEntry {
+	return HistoryEntry{
+		Time:         seen,
+		Hex:          "3c6444",
+		Flight:       "DLH123",
+		Registration: "D-AIBD",
+		Type:         aType,
+		Operator:     operator,
+		Country:      country,
+	}
+}
+
+func TestValidateObserver(t *testing.T) {
+	tests := []struct {
+		observer string
+		valid    bool
+	}{
+		{observer: "home", valid: true},
+		{observer: "office-2.example.org", valid: true},
+		{observer: "", valid: false},
+		{observer: "../home", valid: false},
+		{observer: "my home", valid: false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateObserver(test.observer); (err == nil) != test.valid {
+			t.Errorf("ValidateObserver(%q) = %v, want valid %v", test.observer, err, test.valid)
+		}
+	}
+}
+
+func TestSQLiteSharedStoreSkipsOwnSightings(t *testing.T) {
+	store, err := NewSharedStore(filepath.Join(t.TempDir(), "shared.db"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := store.Push("home", []HistoryEntry{sharedEntry(seen, "A320", "Lufthansa", "Germany")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Push("office", []HistoryEntry{sharedEntry(seen, "B738", "Ryanair", "Ireland")}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, position, pullErr := store.Pull("home", 0)
+	if pullErr != nil {
+		t.Fatal(pullErr)
+	}
+	if len(entries) != 1 || entries[0].Type != "B738" {
+		t.Errorf("Pull(home) = %v, want only the sighting of the office", entries)
+	}
+
+	if err := store.Push("office", []HistoryEntry{sharedEntry(seen, "A388", "Emirates", "UAE")}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, pullErr = store.Pull("home", position)
+	if pullErr != nil {
+		t.Fatal(pullErr)
+	}
+	if len(entries) != 1 || entries[0].Type != "A388" {
+		t.Errorf("Pull(home) after %d = %v, want only the new sighting of the office", position, entries)
+	}
+}
+
+func TestHTTPStoreThroughSyncEndpoint(t *testing.T) {
+	sqliteStore, sqliteErr := NewSharedStore(filepath.Join(t.TempDir(), "shared.db"), "")
+	if sqliteErr != nil {
+		t.Fatal(sqliteErr)
+	}
+	server := httptest.NewServer(syncHandler(sqliteStore, "secret"))
+	defer server.Close()
+
+	store, err := NewSharedStore(server.URL+SyncPath, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Push("office", []HistoryEntry{sharedEntry(seen, "B738", "Ryanair", "Ireland")}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, position, pullErr := store.Pull("home", 0)
+	if pullErr != nil {
+		t.Fatal(pullErr)
+	}
+	if len(entries) != 1 || entries[0].Operator != "Ryanair" || !entries[0].Time.Equal(seen) {
+		t.Errorf("Pull(home) = %v, want the sighting of the office", entries)
+	}
+	if again, _, againErr := store.Pull("home", position); againErr != nil || len(again) != 0 {
+		t.Errorf("Pull(home) after %d = %v, %v, want no new sightings", position, again, againErr)
+	}
+
+	own, _, ownErr := store.Pull("office", 0)
+	if ownErr != nil {
+		t.Fatal(ownErr)
+	}
+	if len(own) != 0 {
+		t.Errorf("Pull(office) = %v, want no sightings", own)
+	}
+
+	intruder, _ := NewSharedStore(server.URL+SyncPath, "guess")
+	if _, _, err := intruder.Pull("home", 0); err == nil {
+		t.Error("Pull() with a wrong token succeeded, want an error")
+	}
+	if err := intruder.Push("office", nil); err == nil {
+		t.Error("Push() with a wrong token succeeded, want an error")
+	}
+}
+
+func TestSyncHandlerCapsBody(t *testing.T) {
+	handler := syncHandler(&staticStore{entries: nil}, "")
+	body := `{"observer": "office", "entries": [` + strings.Repeat(`{"flight": "DLH123"},`, maxSyncBody/20) + `{}]}`
+	req := localRequest(http.MethodPost, SyncPath, strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST of %d bytes status = %d, want %d", len(body), recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SyncPath+"?observer=home", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("GET from afar without token status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
+
+func TestNewSharedStoreRejectsInvalidURL(t *testing.T) {
+	if _, err := NewSharedStore("https://", ""); err == nil {
+		t.Error("NewSharedStore(https://) succeeded, want an error")
+	}
+}
+
+func TestSharedStatsMerge(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	store := &staticStore{entries: []HistoryEntry{
+		sharedEntry(now.Add(-time.Hour), "A320", "Lufthansa", "Germany"),
+		sharedEntry(now.Add(-time.Hour), "A320", "Lufthansa", "Germany"),
+		sharedEntry(now.Add(-time.Hour), "B738", "Ryanair", "Ireland"),
+	}}
+	errOut := log.New(io.Discard, "", 0)
+
+	tests := []struct {
+		name     string
+		halfLife time.Duration
+		count    int
+		total    int
+		b738     int
+	}{
+		{name: "all-time counts", halfLife: 0, count: 3, total: 4, b738: 1},
+		// Others' sightings of an hour ago weigh half, which rounds 2 x 0.5 to 1.
+		{name: "decayed counts", halfLife: time.Hour, count: 2, total: 3, b738: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shared := NewSharedStats(store, "home", test.halfLife, errOut)
+			if err := shared.pull(now); err != nil {
+				t.Fatal(err)
+			}
+
+			observation := shared.merge(RarityObservation{
+				Category: "type",
+				Property: "A320",
+				Count:    1,
+				Total:    1,
+				Counts:   map[string]int{"A320": 1},
+				Time:     now,
+			}, nil)
+			if observation.Count != test.count || observation.Total != test.total {
+				t.Errorf("merged count %d of %d, want %d of %d",
+					observation.Count, observation.Total, test.count, test.total)
+			}
+			if observation.Counts["B738"] != test.b738 {
+				t.Errorf("merged B738 count %d, want %d", observation.Counts["B738"], test.b738)
+			}
+		})
+	}
+}
+
+func TestSharedStatsMergeWithoutPull(t *testing.T) {
+	shared := NewSharedStats(&staticStore{entries: nil}, "home", 0, log.New(io.Discard, "", 0))
+	observation := RarityObservation{
+		Category: "country",
+		Property: "Germany",
+		Count:    1,
+		Total:    1,
+		Counts:   map[string]int{"Germany": 1},
+		Time:     time.Now(),
+	}
+
+	if merged := shared.merge(observation, nil); merged.Count != 1 || merged.Total != 1 {
+		t.Errorf("merge() = %+v, want the observation unchanged", merged)
+	}
+}
+
+func TestSharedStatsPullsIncrementally(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	store := &staticStore{entries: []HistoryEntry{
+		sharedEntry(now, "A320", "Lufthansa", "Germany"),
+		sharedEntry(now, "A320", "Lufthansa", "Germany"),
+	}}
+	shared := NewSharedStats(store, "home", time.Hour, log.New(io.Discard, "", 0))
+	if err := shared.pull(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// An hour later, the two sightings weigh one together, the new one a whole one.
+	later := now.Add(time.Hour)
+	store.entries = append(store.entries, sharedEntry(later, "A320", "Lufthansa", "Germany"))
+	if err := shared.pull(later); err != nil {
+		t.Fatal(err)
+	}
+	observation := shared.merge(RarityObservation{
+		Category: "type",
+		Property: "A320",
+		Count:    0,
+		Total:    0,
+		Counts:   map[string]int{},
+		Time:     later,
+	}, nil)
+	if observation.Count != 2 || observation.Total != 2 || observation.Counts["A320"] != 2 {
+		t.Errorf("merged %d of %d (%v), want 2 of 2", observation.Count, observation.Total, observation.Counts)
+	}
+}
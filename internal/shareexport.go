@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// Formats the sighting history can be shared with plane spotting communities in. Each of them is
+// written by a text/template, which can be replaced to fit the community.
+const (
+	// ShareFormatMarkdown is a daily log in Markdown, with the rare catches of every day.
+	ShareFormatMarkdown = "markdown"
+	// ShareFormatPlaneAlert is a CSV of the rarely caught aircraft in the columns of plane-alert-db.
+	ShareFormatPlaneAlert = "plane-alert"
+	// ShareFormatPost is the text of a Mastodon or Twitter post for every rare catch.
+	ShareFormatPost = "post"
+)
+
+var errInvalidShareTemplate = errors.New("invalid share template")
+
+// shareTemplates are the built-in templates of the share formats.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant maps
+var shareTemplates = map[string]string{
+	ShareFormatMarkdown: `# Spotting log
+{{range .Days}}
+## {{day .Start}}
+
+{{.Sightings}} sightings of {{.Types}} types, {{.Operators}} operators and {{.Countries}} countries.
+{{- if .Catches}}
+
+| Time | Flight | Registration | Type | Operator | Country | Rare |
+| --- | --- | --- | --- | --- | --- | --- |
+{{- range .Catches}}
+| {{clock .Time}} | {{md .Flight}} | {{md .Registration}} | {{md .Type}} | {{md .Operator}} | {{md .Country}} | {{.Rarity}} |
+{{- end}}
+{{- end}}
+{{end}}`,
+	ShareFormatPlaneAlert: `{{csv "$ICAO" "$Registration" "$Operator" "$Type" "$ICAO Type" "#CMPG" "$Tag 1" "$#Tag 2" "$#Tag 3" "Category" "$#Link"}}
+{{range .Aircraft}}{{csv (upper .Hex) .Registration .Operator .Type "" "" (print "Rare " .Rarity) "" "" "Rare Catch" ""}}
+{{end}}`,
+	ShareFormatPost: `{{range .Catches}}Rare {{.Rarity}} spotted:{{with .Type}} {{.}}{{end}}{{with .Registration}} {{.}}{{end}}
+{{- with .Operator}} of {{.}}{{end}}{{with .Country}} from {{.}}{{end}}{{with .Flight}} as {{.}}{{end}}, {{day .Time}} {{clock .Time}}
+#planespotting #avgeek{{with .Operator}} {{hashtag .}}{{end}}
+
+{{end}}`,
+}
+
+// IsShareFormat tells whether the format is one of the share formats, ShareFormatXxx.
+func IsShareFormat(format string) bool {
+	_, ok := shareTemplates[format]
+	return ok
+}
+
+// Rarity names the categories the catch was rare in, e.g. "type and country".
+func (c RareCatch) Rarity() string {
+	if len(c.Rare) < 2 { //nolint:mnd // a single category needs no joining
+		return strings.Join(c.Rare, "")
+	}
+	return strings.Join(c.Rare[:len(c.Rare)-1], ", ") + " and " + c.Rare[len(c.Rare)-1]
+}
+
+// ShareDay is a spotting day of a share report.
+type ShareDay struct {
+	Start     time.Time // Start is when the spotting day started, in local time.
+	Sightings int
+	Types     int // Types is how many different types were seen, likewise Operators and Countries.
+	Operators int
+	Countries int
+	Catches   []RareCatch // Catches are the rare catches of the day, oldest first.
+}
+
+// ShareReport is what the templates of the share formats are executed with.
+type ShareReport struct {
+	Since     time.Time   // Since is the start of the shared period, zero for all of the history.
+	Sightings int         // Sightings is how many sightings there are in the period.
+	Days      []ShareDay  // Days are the spotting days with sightings, oldest first.
+	Catches   []RareCatch // Catches are the rare catches of the period, oldest first.
+	Aircraft  []RareCatch // Aircraft are the first rare catch of every aircraft, e.g. for aircraft lists.
+}
+
+// RareCatches replays the sighting history through the rarity scorer, as if it had been seen live
+// from the start, and returns the sightings which were rare in any category, oldest first, with
+// their unknown type, operator and country left empty. The scorer must be a fresh instance.
+func RareCatches(entries []HistoryEntry, scorer RarityScorer) []RareCatch {
+	var catches []RareCatch
+	var rare []string
+	replayObservations(entries,
+		func(observation RarityObservation) {
+			if scorer.IsRare(observation) {
+				rare = append(rare, observation.Category)
+			}
+		},
+		func(entry HistoryEntry) {
+			if len(rare) > 0 {
+				//nolint:exhaustruct // the history doesn't record the confidences
+				catches = append(catches, RareCatch{HistoryEntry: knownProperties(entry), Rare: rare})
+			}
+			rare = nil
+		})
+	return catches
+}
+
+// knownProperties returns the entry with its unknown type, operator and country left empty.
+func knownProperties(entry HistoryEntry) HistoryEntry {
+	for _, property := range []*string{&entry.Type, &entry.Operator, &entry.Country} {
+		if !isKnownProperty(*property) {
+			*property = ""
+		}
+	}
+	return entry
+}
+
+// NewShareReport collects the sightings since the given time, or all of them if it is zero, by
+// spotting day. Rarity is scored over the whole history, so that a catch is shared as rare if it
+// was when it was seen.
+func NewShareReport(entries []HistoryEntry, since time.Time, day SpottingDay, scorer RarityScorer) ShareReport {
+	report := ShareReport{Since: since, Sightings: 0, Days: nil, Catches: nil, Aircraft: nil}
+	sorted := slices.Clone(entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var types, operators, countries map[string]bool
+	for _, entry := range sorted {
+		if entry.Time.Before(since) {
+			continue
+		}
+		start := day.Start(entry.Time.Local())
+		if len(report.Days) == 0 || !report.Days[len(report.Days)-1].Start.Equal(start) {
+			report.Days = append(report.Days, ShareDay{
+				Start: start, Sightings: 0, Types: 0, Operators: 0, Countries: 0, Catches: nil,
+			})
+			types, operators, countries = make(map[string]bool), make(map[string]bool), make(map[string]bool)
+		}
+		shareDay := &report.Days[len(report.Days)-1]
+		shareDay.Sightings++
+		report.Sightings++
+		countProperty(types, entry.Type, &shareDay.Types)
+		countProperty(operators, entry.Operator, &shareDay.Operators)
+		countProperty(countries, entry.Country, &shareDay.Countries)
+	}
+
+	seenHexes := make(map[string]bool)
+	for _, catch := range RareCatches(sorted, scorer) {
+		if catch.Time.Before(since) {
+			continue
+		}
+		report.Catches = append(report.Catches, catch)
+		start := day.Start(catch.Time.Local())
+		if idx := slices.IndexFunc(report.Days, func(d ShareDay) bool { return d.Start.Equal(start) }); idx >= 0 {
+			report.Days[idx].Catches = append(report.Days[idx].Catches, catch)
+		}
+		if !seenHexes[catch.Hex] {
+			seenHexes[catch.Hex] = true
+			report.Aircraft = append(report.Aircraft, catch)
+		}
+	}
+	return report
+}
+
+// countProperty counts a known property towards the distinct ones of the day.
+func countProperty(seen map[string]bool, property string, count *int) {
+	if isKnownProperty(property) && !seen[property] {
+		seen[property] = true
+		*count++
+	}
+}
+
+// WriteShare writes the report in a share format, one ShareFormatXxx, by the given template text,
+// or by the built-in template of the format if the text is empty.
+func WriteShare(out io.Writer, format string, templateText string, report ShareReport) error {
+	if templateText == "" {
+		var ok bool
+		if templateText, ok = shareTemplates[format]; !ok {
+			return fmt.Errorf("WriteShare: %w: %q, expected %s, %s or %s",
+				errInvalidHistoryFormat, format, ShareFormatMarkdown, ShareFormatPlaneAlert, ShareFormatPost)
+		}
+	}
+	tmpl, parseErr := template.New(format).Funcs(shareTemplateFuncs()).Parse(templateText)
+	if parseErr != nil {
+		return fmt.Errorf("WriteShare: %w: %w", errInvalidShareTemplate, parseErr)
+	}
+	if err := tmpl.Execute(out, report); err != nil {
+		return fmt.Errorf("WriteShare: %w", err)
+	}
+	return nil
+}
+
+// shareTemplateFuncs are the functions the share templates can call, besides the built-in ones.
+func shareTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"day":     func(t time.Time) string { return t.Local().Format(time.DateOnly) },
+		"clock":   func(t time.Time) string { return t.Local().Format("15:04") },
+		"upper":   strings.ToUpper,
+		"md":      markdownCell,
+		"csv":     csvRecord,
+		"hashtag": hashtag,
+	}
+}
+
+// markdownCell escapes the pipes of a Markdown table cell.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// csvRecord joins the fields to a line of CSV, quoted where needed.
+func csvRecord(fields ...string) (string, error) {
+	var record strings.Builder
+	writer := csv.NewWriter(&record)
+	if err := writer.Write(fields); err != nil {
+		return "", fmt.Errorf("csvRecord: %w", err)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("csvRecord: %w", err)
+	}
+	return strings.TrimSuffix(record.String(), "\n"), nil
+}
+
+// hashtag turns the text into a hashtag, e.g. "#LufthansaCargo" for "Lufthansa Cargo", or
+// returns an empty string if it has no letters or digits.
+func hashtag(text string) string {
+	tag := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, text)
+	if tag == "" {
+		return ""
+	}
+	return "#" + tag
+}
@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func shareEntries() []HistoryEntry {
+	entry := func(day int, hour int, hex string, aType string, operator string, country string) HistoryEntry {
+		return HistoryEntry{
+			Time:         time.Date(2026, time.May, day, hour, 0, 0, 0, time.UTC),
+			Hex:          hex,
+			Flight:       "FL" + hex,
+			Registration: "REG-" + hex,
+			Type:         aType,
+			Operator:     operator,
+			Country:      country,
+		}
+	}
+	// Out of order, as the report must replay them by time.
+	return []HistoryEntry{
+		entry(2, 13, "3c6444", "B748", "Cargolux", "Luxembourg"),
+		entry(1, 10, "3c4b26", "A320", "Lufthansa", "Germany"),
+		entry(1, 11, "3c4b27", "A320", "Lufthansa", "Germany"),
+		entry(1, 12, "3c4b28", "A320", "Lufthansa", "Germany"),
+		entry(2, 12, "3c6444", "B748", "Lufthansa", "Germany"),
+		entry(3, 12, "3c4b29", "A320", typeUnknown, "Germany"),
+	}
+}
+
+func newShareTestReport() ShareReport {
+	since := time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC)
+	return NewShareReport(shareEntries(), since, SpottingDay{}, &RatioScorer{Ratio: 0.3, MinTotal: 3})
+}
+
+func TestNewShareReport(t *testing.T) {
+	report := newShareTestReport()
+
+	if report.Sightings != 3 || len(report.Days) != 2 {
+		t.Fatalf("report has %d sightings on %d days, expected 3 on 2", report.Sightings, len(report.Days))
+	}
+	if day := report.Days[0]; day.Sightings != 2 || day.Types != 1 || day.Operators != 2 || day.Countries != 2 {
+		t.Errorf("first day = %+v, expected 2 sightings of 1 type, 2 operators and 2 countries", day)
+	}
+	if day := report.Days[1]; day.Sightings != 1 || day.Operators != 0 || len(day.Catches) != 0 {
+		t.Errorf("second day = %+v, expected a sighting of no known operator and no catch", day)
+	}
+
+	// The B748 is rare as one of four sightings, the Cargolux one as one of five, while the earlier
+	// sightings count towards rarity without being reported.
+	if len(report.Catches) != 2 || report.Catches[0].Rarity() != "type" ||
+		report.Catches[1].Rarity() != "operator and country" {
+		t.Errorf("Catches = %+v, expected the rare type and then the rare operator and country", report.Catches)
+	}
+	if len(report.Days[0].Catches) != 2 || len(report.Aircraft) != 1 || report.Aircraft[0].Operator != "Lufthansa" {
+		t.Errorf("Aircraft = %+v, expected the first catch of 3c6444 only", report.Aircraft)
+	}
+}
+
+func TestRareCatchRarity(t *testing.T) {
+	tests := []struct {
+		rare     []string
+		expected string
+	}{
+		{rare: nil, expected: ""},
+		{rare: []string{"country"}, expected: "country"},
+		{rare: []string{"type", "country"}, expected: "type and country"},
+		{rare: []string{"type", "operator", "country"}, expected: "type, operator and country"},
+	}
+
+	for _, tt := range tests {
+		catch := RareCatch{Rare: tt.rare} //nolint:exhaustruct // rarities only
+		if rarity := catch.Rarity(); rarity != tt.expected {
+			t.Errorf("Rarity() of %v = %q, expected %q", tt.rare, rarity, tt.expected)
+		}
+	}
+}
+
+func TestWriteShare(t *testing.T) {
+	report := newShareTestReport()
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{
+			format: ShareFormatMarkdown,
+			expected: []string{
+				"## 2026-05-02\n\n2 sightings of 1 types, 2 operators and 2 countries.\n",
+				"| FL3c6444 | REG-3c6444 | B748 | Cargolux | Luxembourg | operator and country |\n",
+				"## 2026-05-03\n\n1 sightings of 1 types, 0 operators and 1 countries.\n",
+			},
+		},
+		{
+			format: ShareFormatPlaneAlert,
+			expected: []string{
+				"$ICAO,$Registration,$Operator,$Type,$ICAO Type,#CMPG,$Tag 1,$#Tag 2,$#Tag 3,Category,$#Link\n" +
+					"3C6444,REG-3c6444,Lufthansa,B748,,,Rare type,,,Rare Catch,\n",
+			},
+		},
+		{
+			format: ShareFormatPost,
+			expected: []string{
+				"Rare type spotted: B748 REG-3c6444 of Lufthansa from Germany as FL3c6444, 2026-05-02",
+				"#planespotting #avgeek #Cargolux\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out strings.Builder
+			if err := WriteShare(&out, tt.format, "", report); err != nil {
+				t.Fatal(err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("WriteShare() = %s, expected it to contain %s", out.String(), expected)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteShareTemplate(t *testing.T) {
+	var out strings.Builder
+	templateText := `{{range .Catches}}{{csv .Flight (hashtag .Operator) "a, b"}};{{end}}`
+	if err := WriteShare(&out, ShareFormatPost, templateText, newShareTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `FL3c6444,#Lufthansa,"a, b";FL3c6444,#Cargolux,"a, b";`; out.String() != expected {
+		t.Errorf("WriteShare() = %s, expected %s", out.String(), expected)
+	}
+
+	err := WriteShare(&out, ShareFormatPost, "{{.Catches", newShareTestReport())
+	if !errors.Is(err, errInvalidShareTemplate) {
+		t.Errorf("WriteShare() = %v, expected errInvalidShareTemplate", err)
+	}
+	if err := WriteShare(&out, "html", "", newShareTestReport()); !errors.Is(err, errInvalidHistoryFormat) {
+		t.Errorf("WriteShare() = %v, expected errInvalidHistoryFormat", err)
+	}
+}
@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"fmt"
 	"math"
 	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
 )
 
 const (
@@ -92,14 +95,28 @@ type AircraftSighting struct {
 	registration string
 	latitude     float64
 	longitude    float64
-	direction    string
-	distance     float64            // distance is the distance of the aircraft to our location [m]
+	lastFix      positionFix        // lastFix is the last plausible live position, to reject jumps
+	direction    string             // direction of the aircraft from our location, e.g. "north"
+	bearing      float64            // bearing of the aircraft from our location in [degrees]
+	track        float64            // track of the aircraft over ground in [degrees]
+	speed        float64            // ground speed of the aircraft in [knots]
+	distance     float64            // distance is the distance of the aircraft to our location [km]
+	tier         string             // tier is the name of the proximity tier of the distance, if any
 	typeShort    string             // typeShort is a short type name, directly from the record
 	typeDesc     string             // typeDesc is the full name of the aircraft type
 	operator     string             // operator can be either airline or military organization
 	country      string             // country of registration
 	info         string             // info contains the aircraft information represented as string
 	flightroute  *FlightRouteRecord // flightroute contains airline, origin and destination
+	photo        *PhotoRecord       // photo of this aircraft, only looked up for rare sightings
+	firedRules   map[string]bool    // names of the custom alert rules already fired for this flight
+	squawk       string             // last squawk received, to notice when it changes
+	confidence   Confidences        // how reliable the type, operator and country are
+	rarityScore  RarityScore        // how rare the type is here and worldwide
+	rarities     RarityFlag         // what made the current flight a rare sighting, if anything
+	machAlerted  bool               // whether the current flight was alerted beyond the Mach threshold
+	circling     circlingState      // turns of the aircraft within a small area, to notice it circling
+	goAround     goAroundState      // approach of the aircraft to a nearby airport, to notice a go-around
 }
 
 // RareSighting combines an aircraft sighting with a rarity flag.
@@ -135,6 +152,76 @@ func toDegrees(rad float64) float64 {
 	return rad * 180.0 / math.Pi
 }
 
+// Approaches of an aircraft relative to our location.
+const (
+	approachTowards = "heading your way"
+	approachPassing = "passing by"
+	approachAway    = "moving away"
+
+	// approachCone is how far off the track may point from our location, or from the opposite
+	// direction, for the aircraft to count as heading towards or away from us, in [degrees].
+	approachCone = 45.0
+	// minApproachSpeed is the ground speed below which an aircraft isn't going anywhere, in [knots].
+	minApproachSpeed = 30.0
+)
+
+// compassPoints are the 16 points of the compass, starting at north.
+var compassPoints = []string{ //nolint:gochecknoglobals // constant
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// compassPoint abbreviates the bearing to the nearest of the 16 points of the compass, e.g. "NNE".
+func compassPoint(bearing float64) string {
+	step := 360.0 / float64(len(compassPoints))
+	idx := int(math.Round(math.Mod(bearing+360, 360)/step)) % len(compassPoints)
+	return compassPoints[idx]
+}
+
+// approach tells whether an aircraft at the given bearing from our location, with the given track
+// and ground speed, is heading our way, passing by or moving away. It is empty if the aircraft
+// doesn't move.
+func approach(bearing float64, track float64, speed float64) string {
+	if speed < minApproachSpeed {
+		return ""
+	}
+
+	// The aircraft heads our way if its track points back along the bearing.
+	offset := math.Abs(math.Mod(track-(bearing+180)+540, 360) - 180) //nolint:mnd // half circles
+	switch {
+	case offset <= approachCone:
+		return approachTowards
+	case offset >= 180-approachCone:
+		return approachAway
+	}
+	return approachPassing
+}
+
+// updatePosition keeps the position of the aircraft relative to our location up to date.
+// Records without a position leave the last known one, unless the feed reports a last position.
+func (s *AircraftSighting) updatePosition(originLat float64, originLon float64, aircraft *AircraftRecord) {
+	s.speed = aircraft.GroundSpeed
+	s.track = aircraft.Track
+	position, ok := aircraft.KnownPosition()
+	if !ok {
+		return
+	}
+	s.latitude = position.Lat
+	s.longitude = position.Lon
+	s.bearing = calculateBearing(originLat, originLon, position.Lat, position.Lon)
+	s.direction = getDirection(originLat, originLon, position.Lat, position.Lon)
+}
+
+// whereabouts describes where the aircraft is relative to our location, e.g.
+// "23 km NNE, heading your way", to decide whether it's worth grabbing the binoculars.
+func (s *AircraftSighting) whereabouts(lang i18n.Language) string {
+	where := fmt.Sprintf("%.0f km %s", s.distance, lang.T(compassPoint(s.bearing)))
+	if how := approach(s.bearing, s.track, s.speed); how != "" {
+		where += ", " + lang.T(how)
+	}
+	return where
+}
+
 // calculateBearing calculates the initial bearing (forward azimuth) from point 1 to point 2.
 func calculateBearing(lat1, lon1, lat2, lon2 float64) float64 {
 	// Convert degrees to radians
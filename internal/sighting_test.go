@@ -3,6 +3,8 @@ package internal
 import (
 	"math"
 	"testing"
+
+	"github.com/micutio/airspottr/internal/i18n"
 )
 
 // Point represents a geographic location.
@@ -92,3 +94,76 @@ func TestBearing(t *testing.T) {
 		})
 	}
 }
+
+func TestCompassPoint(t *testing.T) {
+	tests := []struct {
+		bearing  float64
+		expected string
+	}{
+		{bearing: 0, expected: "N"},
+		{bearing: 11.2, expected: "N"},
+		{bearing: 11.3, expected: "NNE"},
+		{bearing: 22.5, expected: "NNE"},
+		{bearing: 90, expected: "E"},
+		{bearing: 200, expected: "SSW"},
+		{bearing: 348.8, expected: "N"},
+		{bearing: 359.9, expected: "N"},
+	}
+
+	for _, tt := range tests {
+		if point := compassPoint(tt.bearing); point != tt.expected {
+			t.Errorf("compassPoint(%v) = %s, expected %s", tt.bearing, point, tt.expected)
+		}
+	}
+}
+
+func TestApproach(t *testing.T) {
+	tests := []struct {
+		name     string
+		bearing  float64
+		track    float64
+		speed    float64
+		expected string
+	}{
+		{name: "north, flying south", bearing: 0, track: 180, speed: 250, expected: approachTowards},
+		{name: "north, flying north", bearing: 0, track: 0, speed: 250, expected: approachAway},
+		{name: "north, flying east", bearing: 0, track: 90, speed: 250, expected: approachPassing},
+		{name: "NNE, flying SW", bearing: 22, track: 225, speed: 250, expected: approachTowards},
+		{name: "NW, flying SE across north", bearing: 350, track: 150, speed: 250, expected: approachTowards},
+		{name: "parked", bearing: 0, track: 180, speed: 0, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if how := approach(tt.bearing, tt.track, tt.speed); how != tt.expected {
+				t.Errorf("approach() = %q, expected %q", how, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWhereabouts(t *testing.T) {
+	sighting := &AircraftSighting{} //nolint:exhaustruct // position only
+
+	aircraft := &AircraftRecord{ //nolint:exhaustruct // position only
+		Lat:         53.7,
+		Lon:         10.1,
+		Track:       210,
+		GroundSpeed: 280,
+	}
+	sighting.updatePosition(53.5, 10.0, aircraft)
+	sighting.distance = 23.4
+
+	if where := sighting.whereabouts(i18n.English); where != "23 km NNE, heading your way" {
+		t.Errorf("whereabouts() = %q", where)
+	}
+	if sighting.direction == dirUnknown {
+		t.Error("updatePosition() left the direction unknown")
+	}
+
+	// Without a position, the last known one stays.
+	sighting.updatePosition(53.5, 10.0, &AircraftRecord{Track: 30, GroundSpeed: 280}) //nolint:exhaustruct // no position
+	if where := sighting.whereabouts(i18n.English); where != "23 km NNE, moving away" {
+		t.Errorf("whereabouts() = %q without position", where)
+	}
+}
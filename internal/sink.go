@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+const (
+	// DefaultEventFilePath is where the file sink writes events if no other path is given.
+	DefaultEventFilePath = "./airspottr_events.log"
+
+	// Formats of the console and file sinks.
+	SinkFormatText = "text"
+	SinkFormatJSON = "json"
+
+	// Kinds of events.
+	EventKindRarity = "rarity"
+	// EventKindFirst reports a type, operator or country which has never been seen before.
+	EventKindFirst = "first"
+	EventKindRule  = "rule"
+	EventKindNote  = "note"
+	// EventKindChange reports that an aircraft changed its squawk or callsign mid-flight.
+	EventKindChange = "change"
+	// EventKindArea reports that an aircraft entered or left a watch area.
+	EventKindArea = "area"
+	// EventKindPeak reports that more aircraft are visible at once than ever before.
+	EventKindPeak = "peak"
+	// EventKindFeed reports that the feed stalled or recovered, it has no sighting.
+	EventKindFeed = "feed"
+	// EventKindSupersonic reports that a supersonic-capable aircraft exceeded the Mach threshold.
+	EventKindSupersonic = "supersonic"
+	// EventKindFollow reports that the followed aircraft took off, landed, climbed, descended or
+	// changed its speed, or came into or went out of sight.
+	EventKindFollow = "follow"
+	// EventKindAchievement reports that a sighting unlocked an achievement, e.g. 100 different types.
+	EventKindAchievement = "achievement"
+	// EventKindNotable reports that a notable aircraft, e.g. a head-of-state transport, started a new
+	// flight.
+	EventKindNotable = "notable"
+	// EventKindCircling reports that an aircraft flies circles or a holding pattern near our location.
+	EventKindCircling = "circling"
+	// EventKindGoAround reports that an aircraft went around or flew a missed approach at a nearby
+	// airport.
+	EventKindGoAround = "go_around"
+)
+
+var errInvalidSinkFormat = errors.New("invalid sink format")
+
+// Event is something noteworthy that happened while spotting, e.g. a rare sighting or a matched
+// alert rule.
+type Event struct {
+	Kind     string            // Kind of event, one of the EventKind constants.
+	Severity Severity          // Severity decides the sinks of the event if severities are routed.
+	Title    string            // Title is a short headline, as used for desktop notifications.
+	Body     string            // Body is a multi-line description, as used for desktop notifications.
+	Summary  string            // Summary is a one-line description for the console and log files.
+	Time     time.Time         // Time at which the event happened.
+	Sighting *AircraftSighting // Sighting is the aircraft the event is about, nil if there is none.
+	Change   *IdentityChange   // Change is the squawk or callsign change, nil for other events.
+	Movement *AreaMovement     // Movement is the arrival or departure, nil for other events.
+}
+
+// EventPayload is the machine-readable representation of an event, as written by the JSON sinks
+// and posted to webhooks.
+type EventPayload struct {
+	Event        string    `json:"event"`
+	Severity     Severity  `json:"severity,omitempty"`
+	Time         time.Time `json:"time"`
+	Title        string    `json:"title"`
+	Summary      string    `json:"summary"`
+	Flight       string    `json:"flight"`
+	Registration string    `json:"registration"`
+	Type         string    `json:"type"`
+	Operator     string    `json:"operator"`
+	Country      string    `json:"country"`
+	Distance     float64   `json:"distance"`       // distance in [km]
+	Tier         string    `json:"tier,omitempty"` // proximity tier of the distance, e.g. "nearby"
+	Direction    string    `json:"direction"`
+	Bearing      float64   `json:"bearing"`            // bearing from our location in [degrees]
+	Approach     string    `json:"approach,omitempty"` // e.g. "heading your way"
+	Info         string    `json:"info"`
+	Photo        string    `json:"photo,omitempty"`
+	Change       string    `json:"change,omitempty"` // what changed, "squawk" or "callsign"
+	From         string    `json:"from,omitempty"`
+	To           string    `json:"to,omitempty"`
+	Area         string    `json:"area,omitempty"`
+	Movement     string    `json:"movement,omitempty"` // "arrival" or "departure"
+	// Rarity is the combined local and global rarity score of the type, for rarity events only.
+	Rarity float64 `json:"rarity,omitempty"`
+	// Confidence tells how reliable the type, operator and country are, nil without an aircraft.
+	Confidence *Confidences `json:"confidence,omitempty"`
+}
+
+func newEventPayload(event Event) EventPayload {
+	sighting := event.Sighting
+	if sighting == nil {
+		//nolint:exhaustruct // no aircraft
+		return EventPayload{
+			Event:    event.Kind,
+			Severity: event.Severity,
+			Time:     event.Time.UTC(),
+			Title:    event.Title,
+			Summary:  event.Summary,
+		}
+	}
+
+	photo := ""
+	if sighting.photo.HasLink() {
+		photo = sighting.photo.Link
+	}
+
+	change := IdentityChange{Field: "", From: "", To: "", Sighting: nil}
+	if event.Change != nil {
+		change = *event.Change
+	}
+
+	movement := AreaMovement{Area: "", Movement: "", Sighting: nil}
+	if event.Movement != nil {
+		movement = *event.Movement
+	}
+
+	rarity := 0.0
+	if event.Kind == EventKindRarity {
+		rarity = sighting.rarityScore.Combined()
+	}
+
+	return EventPayload{
+		Event:        event.Kind,
+		Severity:     event.Severity,
+		Time:         event.Time.UTC(),
+		Title:        event.Title,
+		Summary:      event.Summary,
+		Flight:       sighting.lastFlightNo,
+		Registration: sighting.registration,
+		Type:         sighting.typeDesc,
+		Operator:     sighting.operator,
+		Country:      sighting.country,
+		Distance:     sighting.distance,
+		Tier:         sighting.tier,
+		Direction:    sighting.direction,
+		Bearing:      sighting.bearing,
+		Approach:     approach(sighting.bearing, sighting.track, sighting.speed),
+		Info:         sighting.info,
+		Photo:        photo,
+		Change:       change.Field,
+		From:         change.From,
+		To:           change.To,
+		Area:         movement.Area,
+		Movement:     movement.Movement,
+		Rarity:       rarity,
+		Confidence:   &sighting.confidence,
+	}
+}
+
+// EventSink is a destination for events, e.g. the console, a file, a webhook or the desktop.
+type EventSink interface {
+	// Emit delivers the event to the sink.
+	Emit(event Event) error
+	// Name returns the name of the sink, as used in the config.
+	Name() string
+}
+
+// ConsoleSink writes events to the console, either as text or as JSON lines.
+type ConsoleSink struct {
+	out    io.Writer
+	format string
+}
+
+// NewConsoleSink creates a sink writing to the given console output.
+func NewConsoleSink(out io.Writer, format string) (*ConsoleSink, error) {
+	if err := validateSinkFormat(format); err != nil {
+		return nil, fmt.Errorf("NewConsoleSink: %w", err)
+	}
+	return &ConsoleSink{out: out, format: format}, nil
+}
+
+func (s *ConsoleSink) Emit(event Event) error {
+	return writeEvent(s.out, s.format, event)
+}
+
+func (s *ConsoleSink) Name() string { return "console" }
+
+// FileSink appends events to a file, either as text or as JSON lines.
+type FileSink struct {
+	path   string
+	format string
+}
+
+// NewFileSink creates a sink appending to the file at the given path.
+func NewFileSink(path string, format string) (*FileSink, error) {
+	if err := validateSinkFormat(format); err != nil {
+		return nil, fmt.Errorf("NewFileSink: %w", err)
+	}
+	return &FileSink{path: path, format: format}, nil
+}
+
+func (s *FileSink) Emit(event Event) error {
+	file, openErr := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if openErr != nil {
+		return fmt.Errorf("FileSink.Emit: failed to open %s: %w", s.path, openErr)
+	}
+
+	writeErr := writeEvent(file, s.format, event)
+	closeErr := file.Close()
+	if writeErr != nil {
+		return fmt.Errorf("FileSink.Emit: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("FileSink.Emit: failed to close %s: %w", s.path, closeErr)
+	}
+	return nil
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+// DesktopSink shows events as desktop notifications.
+type DesktopSink struct{}
+
+func (s *DesktopSink) Emit(event Event) error {
+	if err := beeep.Notify(event.Title, withPhotoLink(event.Body, event.Sighting), appIconPath); err != nil {
+		return fmt.Errorf("DesktopSink.Emit: %w", err)
+	}
+	return nil
+}
+
+func (s *DesktopSink) Name() string { return "desktop" }
+
+func validateSinkFormat(format string) error {
+	switch format {
+	case "", SinkFormatText, SinkFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", errInvalidSinkFormat, format)
+}
+
+// writeEvent writes the event as one line of text, followed by a link to a photo if there is one,
+// or as a single line of JSON.
+func writeEvent(out io.Writer, format string, event Event) error {
+	if format == SinkFormatJSON {
+		if err := json.NewEncoder(out).Encode(newEventPayload(event)); err != nil {
+			return fmt.Errorf("writeEvent: failed to encode event: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(out, event.Summary); err != nil {
+		return fmt.Errorf("writeEvent: %w", err)
+	}
+	if event.Sighting != nil && event.Sighting.photo.HasLink() {
+		if _, err := fmt.Fprintf(out, "photo: %s\n", event.Sighting.photo.Link); err != nil {
+			return fmt.Errorf("writeEvent: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestConsoleSinkFormats(t *testing.T) {
+	sighting := &AircraftSighting{ //nolint:exhaustruct // only the reported fields matter
+		lastFlightNo: "DLH400",
+		registration: "D-ABYA",
+		typeDesc:     "BOEING 747-8",
+		photo:        &PhotoRecord{Link: "https://example.com/photo"}, //nolint:exhaustruct // link only
+	}
+	event := rarityEvent("Rare Aircraft Type Spotted", "", "found rare type B748", sighting)
+	event.Time = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("text", func(t *testing.T) {
+		var out bytes.Buffer
+		sink, err := NewConsoleSink(&out, SinkFormatText)
+		if err != nil {
+			t.Fatalf("NewConsoleSink() error = %v", err)
+		}
+		if err := sink.Emit(event); err != nil {
+			t.Fatalf("Emit() error = %v", err)
+		}
+
+		expected := "found rare type B748\nphoto: https://example.com/photo\n"
+		if out.String() != expected {
+			t.Errorf("Emit() wrote %q, expected %q", out.String(), expected)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		sink, err := NewConsoleSink(&out, SinkFormatJSON)
+		if err != nil {
+			t.Fatalf("NewConsoleSink() error = %v", err)
+		}
+		if err := sink.Emit(event); err != nil {
+			t.Fatalf("Emit() error = %v", err)
+		}
+
+		var payload EventPayload
+		if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+			t.Fatalf("Emit() wrote invalid JSON %q: %v", out.String(), err)
+		}
+		if payload.Event != EventKindRarity || payload.Registration != "D-ABYA" ||
+			payload.Photo != "https://example.com/photo" {
+			t.Errorf("Emit() wrote unexpected payload %+v", payload)
+		}
+	})
+}
+
+func TestNewConsoleSinkRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewConsoleSink(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("NewConsoleSink() accepted unknown format")
+	}
+}
@@ -0,0 +1,320 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Services the social sink can post to.
+	SocialMastodon = "mastodon"
+	SocialBluesky  = "bluesky"
+
+	defaultBlueskyServer      = "https://bsky.social"
+	defaultSocialPostsPerHour = 4
+	socialTimeout             = 10 * time.Second
+
+	// mastodonPostLength and blueskyPostLength are the most characters a post may have.
+	mastodonPostLength = 500
+	blueskyPostLength  = 300
+
+	socialHashtags = "#planespotting #avgeek"
+)
+
+var (
+	errInvalidSocialConfig = errors.New("invalid social config")
+	errSocialRateLimit     = errors.New("rate limit of social posts reached")
+)
+
+// SocialConfig configures posting notable sightings to Mastodon or Bluesky, e.g.
+//
+//	{
+//	  "enabled": true,
+//	  "service": "mastodon",
+//	  "server": "https://mastodon.social",
+//	  "token": "...",
+//	  "max_per_hour": 2
+//	}
+//
+// Bluesky takes the handle and an app password of the account instead of a token.
+type SocialConfig struct {
+	Enabled    *bool    `json:"enabled"`      // nil keeps posting disabled
+	Service    string   `json:"service"`      // "mastodon" or "bluesky"
+	Server     string   `json:"server"`       // Mastodon instance, or Bluesky PDS which defaults to bsky.social
+	Token      string   `json:"token"`        // access token of the Mastodon account, to write statuses
+	Handle     string   `json:"handle"`       // handle of the Bluesky account, e.g. "spotter.bsky.social"
+	Password   string   `json:"password"`     // app password of the Bluesky account
+	MaxPerHour int      `json:"max_per_hour"` // posts per hour, more are dropped, 0 keeps the default of 4
+	DryRun     bool     `json:"dry_run"`      // writes the posts to the log instead of posting them
+	Events     []string `json:"events"`       // kinds of events to post, empty posts rarity and note events
+}
+
+// IsEnabled tells whether posting is enabled, which it isn't by default.
+func (c SocialConfig) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// SocialSink posts notable sightings, i.e. rare ones and those of noted, watched and favourite
+// aircraft, to Mastodon or Bluesky. Like the webhook sink, it posts in the background and logs the
+// errors. Posts beyond the rate limit are dropped, so that a busy day doesn't flood the timeline.
+type SocialSink struct {
+	kinds      []string
+	maxPerHour int
+	maxLength  int
+	dryRun     bool
+	posted     []time.Time // posted are the times of the posts of the last hour, oldest first.
+	// post posts the text, in which link is the link to the photo, if there is one.
+	post   func(text string, link string) error
+	errOut *log.Logger
+}
+
+// NewSocialSink creates a sink posting to the service of the given config.
+func NewSocialSink(config SocialConfig, errOut *log.Logger) (*SocialSink, error) {
+	if config.MaxPerHour < 0 {
+		return nil, fmt.Errorf("NewSocialSink: %w: max_per_hour must not be negative", errInvalidSocialConfig)
+	}
+	sink := SocialSink{
+		kinds:      config.Events,
+		maxPerHour: config.MaxPerHour,
+		maxLength:  mastodonPostLength,
+		dryRun:     config.DryRun,
+		posted:     nil,
+		post:       nil,
+		errOut:     errOut,
+	}
+	if len(sink.kinds) == 0 {
+		sink.kinds = []string{EventKindRarity, EventKindNote}
+	}
+	if sink.maxPerHour == 0 {
+		sink.maxPerHour = defaultSocialPostsPerHour
+	}
+
+	server := strings.TrimSuffix(config.Server, "/")
+	switch config.Service {
+	case SocialMastodon:
+		if server == "" || (config.Token == "" && !config.DryRun) {
+			return nil, fmt.Errorf("NewSocialSink: %w: mastodon requires a server and a token",
+				errInvalidSocialConfig)
+		}
+		sink.post = func(text string, _ string) error { return postMastodon(server, config.Token, text) }
+	case SocialBluesky:
+		if server == "" {
+			server = defaultBlueskyServer
+		}
+		if (config.Handle == "" || config.Password == "") && !config.DryRun {
+			return nil, fmt.Errorf("NewSocialSink: %w: bluesky requires a handle and an app password",
+				errInvalidSocialConfig)
+		}
+		sink.maxLength = blueskyPostLength
+		sink.post = func(text string, link string) error {
+			return postBluesky(server, config.Handle, config.Password, text, link)
+		}
+	default:
+		return nil, fmt.Errorf("NewSocialSink: %w: unknown service %q, expected %s or %s",
+			errInvalidSocialConfig, config.Service, SocialMastodon, SocialBluesky)
+	}
+	return &sink, nil
+}
+
+func (s *SocialSink) Emit(event Event) error {
+	if event.Sighting == nil || !slices.Contains(s.kinds, event.Kind) {
+		return nil
+	}
+	text, link := socialPost(event, s.maxLength)
+
+	hourAgo := event.Time.Add(-time.Hour)
+	s.posted = slices.DeleteFunc(s.posted, func(posted time.Time) bool { return !posted.After(hourAgo) })
+	if len(s.posted) >= s.maxPerHour {
+		return fmt.Errorf("SocialSink.Emit: %w, dropped: %s", errSocialRateLimit, event.Summary)
+	}
+	s.posted = append(s.posted, event.Time)
+
+	if s.dryRun {
+		s.errOut.Printf("social dry run, would post: %q\n", text)
+		return nil
+	}
+	go func() {
+		if err := s.post(text, link); err != nil {
+			s.errOut.Println(fmt.Errorf("SocialSink.Emit: %w", err))
+		}
+	}()
+	return nil
+}
+
+func (s *SocialSink) Name() string { return "social" }
+
+// socialPost writes the post of an event: its title, the aircraft with its type, registration,
+// operator and route, the link to its photo and hashtags. The description is shortened to fit
+// into the length of a post, the link is returned on its own as well.
+func socialPost(event Event, maxLength int) (string, string) {
+	sighting := event.Sighting
+	aircraft := strings.TrimSpace(fmt.Sprintf("%s %s", sighting.lastFlightNo, sighting.typeDesc))
+	if sighting.registration != "" {
+		aircraft += " (" + sighting.registration + ")"
+	}
+	if sighting.operator != "" && !strings.EqualFold(sighting.operator, typeUnknown) {
+		aircraft += ", " + sighting.operator
+	}
+	lines := []string{event.Title, aircraft}
+	if route := socialRoute(sighting.flightroute); route != "" {
+		lines = append(lines, route)
+	}
+	description := strings.Join(lines, "\n")
+
+	link := ""
+	if sighting.photo.HasLink() {
+		link = sighting.photo.Link
+	}
+	tail := socialHashtags
+	if link != "" {
+		tail = link + "\n" + tail
+	}
+
+	// The description, a line break and the tail have to fit.
+	room := maxLength - utf8.RuneCountInString(tail) - 1
+	if runes := []rune(description); len(runes) > room {
+		description = strings.TrimSpace(string(runes[:max(0, room-1)])) + "…"
+	}
+	return description + "\n" + tail, link
+}
+
+// socialRoute describes the route of a flight by its airports, e.g. "EDDF → KJFK", or returns an
+// empty string if it isn't known.
+func socialRoute(route *FlightRouteRecord) string {
+	if route == nil {
+		return ""
+	}
+	airport := func(location LocationRecord) string {
+		for _, code := range []string{location.IcaoCode, location.IataCode} {
+			if code != "" && code != NotAvailable {
+				return code
+			}
+		}
+		return ""
+	}
+	origin, destination := airport(route.Origin), airport(route.Destination)
+	if origin == "" || destination == "" {
+		return ""
+	}
+	return origin + " → " + destination
+}
+
+// postMastodon posts the text as a public status of the account of the token.
+func postMastodon(server string, token string, text string) error {
+	form := url.Values{"status": {text}, "visibility": {"public"}}
+	err := socialRequest(server+"/api/v1/statuses", token, "application/x-www-form-urlencoded",
+		strings.NewReader(form.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("postMastodon: %w", err)
+	}
+	return nil
+}
+
+// blueskySession is the session of a Bluesky account, as created with its app password.
+type blueskySession struct {
+	AccessJwt string `json:"accessJwt"`
+	Did       string `json:"did"`
+}
+
+// blueskyFacet marks the link in a Bluesky post, which isn't linked by itself, by its byte range.
+type blueskyFacet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []blueskyLink `json:"features"`
+}
+
+type blueskyLink struct {
+	Type string `json:"$type"`
+	URI  string `json:"uri"`
+}
+
+// postBluesky posts the text to the account of the handle. Posts are rare enough to create a new
+// session for each of them.
+func postBluesky(server string, handle string, password string, text string, link string) error {
+	credentials, jsonErr := json.Marshal(map[string]string{"identifier": handle, "password": password})
+	if jsonErr != nil {
+		return fmt.Errorf("postBluesky: %w", jsonErr)
+	}
+	var session blueskySession
+	sessionErr := socialRequest(server+"/xrpc/com.atproto.server.createSession", "", "application/json",
+		bytes.NewReader(credentials), &session)
+	if sessionErr != nil {
+		return fmt.Errorf("postBluesky: failed to log in: %w", sessionErr)
+	}
+
+	facets := []blueskyFacet{}
+	if start := strings.Index(text, link); link != "" && start >= 0 {
+		var facet blueskyFacet
+		facet.Index.ByteStart, facet.Index.ByteEnd = start, start+len(link)
+		facet.Features = []blueskyLink{{Type: "app.bsky.richtext.facet#link", URI: link}}
+		facets = append(facets, facet)
+	}
+	record, recordErr := json.Marshal(map[string]any{
+		"repo":       session.Did,
+		"collection": "app.bsky.feed.post",
+		"record": map[string]any{
+			"$type":     "app.bsky.feed.post",
+			"text":      text,
+			"createdAt": time.Now().UTC().Format(time.RFC3339),
+			"facets":    facets,
+		},
+	})
+	if recordErr != nil {
+		return fmt.Errorf("postBluesky: %w", recordErr)
+	}
+	postErr := socialRequest(server+"/xrpc/com.atproto.repo.createRecord", session.AccessJwt, "application/json",
+		bytes.NewReader(record), nil)
+	if postErr != nil {
+		return fmt.Errorf("postBluesky: %w", postErr)
+	}
+	return nil
+}
+
+// socialRequest posts the body to the URL, authorized by the bearer token if there is one, and
+// decodes the JSON response into result, unless it is nil.
+func socialRequest(postURL string, token string, contentType string, body io.Reader, result any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), socialTimeout)
+	defer cancel()
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, postURL, body)
+	if reqErr != nil {
+		return fmt.Errorf("socialRequest: invalid request: %w", reqErr)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, respErr := http.DefaultClient.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("socialRequest: failed to send POST request: %w", respErr)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("socialRequest: %w %s", ErrNonOkResponse, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("socialRequest: failed to decode response: %w", err)
+	}
+	return nil
+}
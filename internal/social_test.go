@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func socialEvent(at time.Time) Event {
+	sighting := &AircraftSighting{ //nolint:exhaustruct // only the posted fields matter
+		lastFlightNo: "DLH400",
+		registration: "D-ABYA",
+		typeDesc:     "BOEING 747-8",
+		operator:     "Lufthansa",
+		photo:        &PhotoRecord{Link: "https://example.com/photo"}, //nolint:exhaustruct // link only
+		flightroute: &FlightRouteRecord{ //nolint:exhaustruct // airports only
+			Origin:      LocationRecord{IcaoCode: "EDDF"},                        //nolint:exhaustruct // code only
+			Destination: LocationRecord{IcaoCode: NotAvailable, IataCode: "JFK"}, //nolint:exhaustruct // codes only
+		},
+	}
+	event := rarityEvent("Rare Aircraft Type Spotted", "", "found rare type B748", sighting)
+	event.Time = at
+	return event
+}
+
+func TestSocialPost(t *testing.T) {
+	event := socialEvent(time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC))
+
+	text, link := socialPost(event, mastodonPostLength)
+	expected := "Rare Aircraft Type Spotted\nDLH400 BOEING 747-8 (D-ABYA), Lufthansa\nEDDF → JFK\n" +
+		"https://example.com/photo\n#planespotting #avgeek"
+	if text != expected || link != "https://example.com/photo" {
+		t.Errorf("socialPost() = %q, %q, expected %q and the photo link", text, link, expected)
+	}
+
+	short, _ := socialPost(event, 80)
+	if len([]rune(short)) != 80 || !strings.HasPrefix(short, "Rare Aircraft Type Spotted\nDLH…\n") ||
+		!strings.HasSuffix(short, "\nhttps://example.com/photo\n#planespotting #avgeek") {
+		t.Errorf("socialPost() = %q, expected the description shortened to 80 characters in all", short)
+	}
+}
+
+func TestSocialSinkRateLimit(t *testing.T) {
+	var logged bytes.Buffer
+	enabled := true
+	//nolint:exhaustruct // no credentials for a dry run
+	sink, err := NewSocialSink(SocialConfig{
+		Enabled: &enabled, Service: SocialBluesky, MaxPerHour: 2, DryRun: true,
+	}, log.New(&logged, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		after     time.Duration
+		isDropped bool
+	}{
+		{after: 0, isDropped: false},
+		{after: 10 * time.Minute, isDropped: false},
+		{after: 20 * time.Minute, isDropped: true},
+		{after: 61 * time.Minute, isDropped: false},
+	}
+	for _, tt := range tests {
+		err := sink.Emit(socialEvent(start.Add(tt.after)))
+		if errors.Is(err, errSocialRateLimit) != tt.isDropped {
+			t.Errorf("Emit() after %s = %v, expected dropped %v", tt.after, err, tt.isDropped)
+		}
+	}
+
+	if posts := strings.Count(logged.String(), "social dry run, would post"); posts != 3 {
+		t.Errorf("dry run logged %d posts, expected 3: %s", posts, logged.String())
+	}
+
+	peak := Event{Kind: EventKindPeak, Time: start, Sighting: nil} //nolint:exhaustruct // kind only
+	if err := sink.Emit(peak); err != nil {
+		t.Errorf("Emit() of a peak = %v, expected it to be left out", err)
+	}
+}
+
+func TestNewSocialSinkValidates(t *testing.T) {
+	//nolint:exhaustruct // only the invalid fields
+	tests := []struct {
+		name   string
+		config SocialConfig
+	}{
+		{name: "unknown service", config: SocialConfig{Service: "myspace"}},
+		{name: "mastodon without token", config: SocialConfig{Service: SocialMastodon, Server: "x"}},
+		{name: "bluesky without password", config: SocialConfig{Service: SocialBluesky, Handle: "a"}},
+		{name: "negative rate", config: SocialConfig{Service: SocialBluesky, MaxPerHour: -1}},
+	}
+
+	for _, tt := range tests {
+		_, err := NewSocialSink(tt.config, log.New(&bytes.Buffer{}, "", 0))
+		if !errors.Is(err, errInvalidSocialConfig) {
+			t.Errorf("%s: NewSocialSink() = %v, expected errInvalidSocialConfig", tt.name, err)
+		}
+	}
+}
+
+func TestPostMastodon(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses" || r.Header.Get("Authorization") != "Bearer secret" ||
+			r.FormValue("status") != "Rare catch" || r.FormValue("visibility") != "public" {
+			t.Errorf("unexpected request %s %v: %v", r.URL.Path, r.Header, r.Form)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	if err := postMastodon(server.URL, "secret", "Rare catch"); err != nil {
+		t.Errorf("postMastodon() = %v", err)
+	}
+}
+
+func TestPostBluesky(t *testing.T) {
+	var record struct {
+		Repo   string `json:"repo"`
+		Record struct {
+			Text   string         `json:"text"`
+			Facets []blueskyFacet `json:"facets"`
+		} `json:"record"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			_, _ = w.Write([]byte(`{"accessJwt": "jwt", "did": "did:plc:spotter"}`))
+		case "/xrpc/com.atproto.repo.createRecord":
+			if r.Header.Get("Authorization") != "Bearer jwt" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			_ = json.NewDecoder(r.Body).Decode(&record)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	link := "https://example.com/photo"
+	text := "Rare → catch\n" + link
+	if err := postBluesky(server.URL, "spotter.bsky.social", "app-password", text, link); err != nil {
+		t.Fatalf("postBluesky() = %v", err)
+	}
+	if record.Repo != "did:plc:spotter" || record.Record.Text != text || len(record.Record.Facets) != 1 {
+		t.Fatalf("postBluesky() posted %+v, expected the text with a link facet", record)
+	}
+	// The arrow takes three bytes, so the link starts at byte 15.
+	if index := record.Record.Facets[0].Index; index.ByteStart != 15 || index.ByteEnd != 40 {
+		t.Errorf("link facet = %+v, expected bytes 15 to 40", index)
+	}
+}
@@ -1,17 +1,26 @@
 package internal
 
-import "sort"
+import (
+	"sort"
+)
 
 type PropertyCountTuple struct {
 	Property string
 	Count    int
 }
 
+// ByCount sorts from least to most common, and properties seen equally often by name, so that
+// they keep their order from update to update.
 type ByCount []PropertyCountTuple
 
-func (a ByCount) Len() int           { return len(a) }
-func (a ByCount) Less(i, j int) bool { return a[i].Count < a[j].Count }
-func (a ByCount) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByCount) Len() int { return len(a) }
+func (a ByCount) Less(i, j int) bool {
+	if a[i].Count != a[j].Count {
+		return a[i].Count < a[j].Count
+	}
+	return a[i].Property < a[j].Property
+}
+func (a ByCount) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 
 func GetSortedCountsForProperty(propertyCountMap map[string]int) []PropertyCountTuple {
 	propertyCounts := make([]PropertyCountTuple, len(propertyCountMap))
@@ -24,3 +33,27 @@ func GetSortedCountsForProperty(propertyCountMap map[string]int) []PropertyCount
 	sort.Sort(ByCount(propertyCounts))
 	return propertyCounts
 }
+
+// SelectCounts filters and orders sorted property counts for the summary according to the config.
+// The given counts must be sorted from least to most common, as returned by
+// GetSortedCountsForProperty.
+func SelectCounts(propertyCounts []PropertyCountTuple, config SummaryListConfig) []PropertyCountTuple {
+	selected := make([]PropertyCountTuple, 0, len(propertyCounts))
+	for _, propertyCount := range propertyCounts {
+		if propertyCount.Count >= config.MinCount {
+			selected = append(selected, propertyCount)
+		}
+	}
+
+	if config.Order == SummaryOrderDescending {
+		for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+			selected[i], selected[j] = selected[j], selected[i]
+		}
+	}
+
+	if config.Limit > 0 && len(selected) > config.Limit {
+		selected = selected[:config.Limit]
+	}
+
+	return selected
+}
@@ -33,7 +33,7 @@ func TestGetSortedCountsForProperty(t *testing.T) {
 		{
 			name:  "items with same count",
 			input: map[string]int{"a": 1, "c": 2, "b": 1},
-			// The order of items with the same count is not guaranteed, so we accept either
+			// Items with the same count are ordered by name.
 			expected: []PropertyCountTuple{
 				{Property: "a", Count: 1},
 				{Property: "b", Count: 1},
@@ -45,25 +45,48 @@ func TestGetSortedCountsForProperty(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			got := GetSortedCountsForProperty(test.input)
-			// For the case with same counts, the order is not guaranteed.
-			// We can't do a deep equal directly.
-			if len(got) != len(test.expected) {
+			if !reflect.DeepEqual(got, test.expected) {
 				t.Errorf("GetSortedCountsForProperty() = %v, want %v", got, test.expected)
 			}
+		})
+	}
+}
 
-			// A simple check for this specific test case. For more complex scenarios,
-			// a more robust comparison would be needed.
-			if test.name == "items with same count" {
-				if (!reflect.DeepEqual(got[0], test.expected[0]) || !reflect.DeepEqual(got[1], test.expected[1])) &&
-					(!reflect.DeepEqual(got[0], test.expected[1]) || !reflect.DeepEqual(got[1], test.expected[0])) {
-					t.Errorf("GetSortedCountsForProperty() got = %v, want %v", got, test.expected)
-				}
-				if !reflect.DeepEqual(got[2], test.expected[2]) {
-					t.Errorf("GetSortedCountsForProperty() got = %v, want %v", got, test.expected)
-				}
-			} else if len(test.expected) > 0 && !reflect.DeepEqual(got, test.expected) {
-				t.Errorf("GetSortedCountsForProperty() = %v, want %v", got, test.expected)
+func TestSelectCounts(t *testing.T) {
+	counts := []PropertyCountTuple{
+		{Property: "one", Count: 1},
+		{Property: "two", Count: 2},
+		{Property: "three", Count: 3},
+		{Property: "four", Count: 4},
+	}
+
+	tests := []struct {
+		name     string
+		config   SummaryListConfig
+		expected []string
+	}{
+		{"all", SummaryListConfig{Limit: 0, MinCount: 0, Order: ""}, []string{"one", "two", "three", "four"}},
+		{"bottom two", SummaryListConfig{Limit: 2, MinCount: 0, Order: "asc"}, []string{"one", "two"}},
+		{"top two", SummaryListConfig{Limit: 2, MinCount: 0, Order: "desc"}, []string{"four", "three"}},
+		{"min count", SummaryListConfig{Limit: 0, MinCount: 3, Order: ""}, []string{"three", "four"}},
+		{"limit above length", SummaryListConfig{Limit: 10, MinCount: 4, Order: ""}, []string{"four"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := SelectCounts(counts, test.config)
+			properties := make([]string, len(got))
+			for idx := range got {
+				properties[idx] = got[idx].Property
+			}
+			if !reflect.DeepEqual(properties, test.expected) {
+				t.Errorf("SelectCounts() = %v, want %v", properties, test.expected)
 			}
 		})
 	}
+
+	// The input must not be modified.
+	if counts[0].Property != "one" {
+		t.Errorf("SelectCounts() modified its input")
+	}
 }
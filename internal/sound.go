@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gen2brain/beeep"
+)
+
+const (
+	// Classes of events which sound alerts can be configured for.
+	SoundClassRarity      = "rarity"
+	SoundClassNote        = "note"
+	SoundClassEmergency   = "emergency"
+	SoundClassFeed        = "feed"
+	SoundClassFirst       = "first"
+	SoundClassSupersonic  = "supersonic"
+	SoundClassFollow      = "follow"
+	SoundClassAchievement = "achievement"
+	SoundClassNotable     = "notable"
+	SoundClassCircling    = "circling"
+	SoundClassGoAround    = "go_around"
+
+	// Placeholders in the player command, replaced by the sound file and the volume in percent.
+	soundPlaceholder  = "{sound}"
+	volumePlaceholder = "{volume}"
+
+	maxSoundVolume = 100
+)
+
+var (
+	errInvalidSoundClass  = errors.New("unknown sound class")
+	errInvalidSoundVolume = errors.New("sound volume must be between 0 and 100")
+)
+
+// SoundConfig configures the sound alerts, e.g.
+//
+//	{
+//	  "enabled": true,
+//	  "command": ["mpv", "--no-video", "--volume={volume}", "{sound}"],
+//	  "volume": 80,
+//	  "classes": {
+//	    "emergency": {"sound": "/usr/share/sounds/alarm.oga", "volume": 100},
+//	    "feed": {"muted": true}
+//	  }
+//	}
+//
+// Without a command, or for classes without a sound, the terminal beeps instead.
+type SoundConfig struct {
+	Enabled *bool                       `json:"enabled"` // nil keeps sounds disabled
+	Command []string                    `json:"command"` // player command, with placeholders
+	Volume  *int                        `json:"volume"`  // volume in percent, nil is full volume
+	Muted   bool                        `json:"muted"`   // mutes all classes
+	Classes map[string]SoundClassConfig `json:"classes"` // classes mapped to their sound
+}
+
+// IsEnabled tells whether sound alerts are enabled, which they aren't by default.
+func (c SoundConfig) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// SoundClassConfig configures the sound of one class of events.
+type SoundClassConfig struct {
+	Sound  string `json:"sound"`  // sound file passed to the player command
+	Volume *int   `json:"volume"` // volume in percent, nil keeps the volume of all classes
+	Muted  bool   `json:"muted"`
+}
+
+// SoundSink plays a sound for events, so that rare or emergency sightings can be heard while not
+// looking at the screen. It receives the same events as the desktop sink.
+type SoundSink struct {
+	config SoundConfig
+	beep   func() error              // beep sounds the terminal bell.
+	start  func(args []string) error // start runs the player command without waiting for it.
+}
+
+// NewSoundSink creates a sink which plays the sounds of the given config.
+func NewSoundSink(config SoundConfig) (*SoundSink, error) {
+	if err := validateSoundVolume(config.Volume); err != nil {
+		return nil, fmt.Errorf("NewSoundSink: %w", err)
+	}
+	for class, classConfig := range config.Classes {
+		if !slices.Contains(soundClasses(), class) {
+			return nil, fmt.Errorf("NewSoundSink: %w: %q", errInvalidSoundClass, class)
+		}
+		if err := validateSoundVolume(classConfig.Volume); err != nil {
+			return nil, fmt.Errorf("NewSoundSink: class %s: %w", class, err)
+		}
+	}
+
+	return &SoundSink{config: config, beep: beep, start: startPlayer}, nil
+}
+
+func (s *SoundSink) Emit(event Event) error {
+	class := soundClass(event)
+	classConfig := s.config.Classes[class]
+	if s.config.Muted || classConfig.Muted {
+		return nil
+	}
+
+	// A volume of 0 mutes the terminal bell as well, which has no volume of its own.
+	volume := maxSoundVolume
+	switch {
+	case classConfig.Volume != nil:
+		volume = *classConfig.Volume
+	case s.config.Volume != nil:
+		volume = *s.config.Volume
+	}
+	if volume == 0 {
+		return nil
+	}
+
+	if len(s.config.Command) == 0 || classConfig.Sound == "" {
+		if err := s.beep(); err != nil {
+			return fmt.Errorf("SoundSink.Emit: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.start(playerArgs(s.config.Command, classConfig.Sound, volume)); err != nil {
+		return fmt.Errorf("SoundSink.Emit: %w", err)
+	}
+	return nil
+}
+
+func (s *SoundSink) Name() string { return "sound" }
+
+// soundClasses lists the classes of events which sounds can be configured for.
+func soundClasses() []string {
+	return []string{
+		SoundClassRarity, SoundClassNote, SoundClassEmergency, SoundClassFeed, SoundClassFirst,
+		SoundClassSupersonic, SoundClassFollow, SoundClassAchievement, SoundClassNotable, SoundClassCircling,
+		SoundClassGoAround,
+	}
+}
+
+// soundClass tells which class of sound the event has. Emergency squawks have a class of their
+// own, all other events are classed by their kind.
+func soundClass(event Event) string {
+	if event.Change != nil && event.Change.IsEmergency() {
+		return SoundClassEmergency
+	}
+	return event.Kind
+}
+
+// playerArgs fills in the placeholders of the player command.
+func playerArgs(command []string, sound string, volume int) []string {
+	replacer := strings.NewReplacer(
+		soundPlaceholder, sound,
+		volumePlaceholder, strconv.Itoa(volume),
+	)
+	args := make([]string, len(command))
+	for idx, arg := range command {
+		args[idx] = replacer.Replace(arg)
+	}
+	return args
+}
+
+func validateSoundVolume(volume *int) error {
+	if volume != nil && (*volume < 0 || *volume > maxSoundVolume) {
+		return fmt.Errorf("%w: %d", errInvalidSoundVolume, *volume)
+	}
+	return nil
+}
+
+func beep() error {
+	if err := beeep.Beep(beeep.DefaultFreq, beeep.DefaultDuration); err != nil {
+		return fmt.Errorf("beep: %w", err)
+	}
+	return nil
+}
+
+// startPlayer starts the player and reaps it once it is done, so that playing a sound doesn't hold
+// up the updates.
+func startPlayer(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // The player is configured by the user.
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("startPlayer: failed to start %s: %w", args[0], err)
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
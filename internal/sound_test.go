@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSoundSinkEmit(t *testing.T) {
+	player := []string{"play", "--volume={volume}", "{sound}"}
+	half := 50
+	silent := 0
+	emergency := &IdentityChange{Field: ChangeSquawk, From: "1000", To: "7700", Sighting: nil}
+
+	tests := []struct {
+		name       string
+		config     SoundConfig
+		event      Event
+		wantBeeps  int
+		wantPlayed []string
+	}{
+		{
+			name:       "beep without command",
+			config:     SoundConfig{}, //nolint:exhaustruct // defaults
+			event:      soundEvent(EventKindRarity, nil),
+			wantBeeps:  1,
+			wantPlayed: nil,
+		},
+		{
+			name: "class sound with class volume",
+			config: SoundConfig{ //nolint:exhaustruct // not enabled by the sink itself
+				Command: player,
+				Classes: map[string]SoundClassConfig{
+					SoundClassRarity: {Sound: "rare.wav", Volume: &half, Muted: false},
+				},
+			},
+			event:      soundEvent(EventKindRarity, nil),
+			wantBeeps:  0,
+			wantPlayed: []string{"play", "--volume=50", "rare.wav"},
+		},
+		{
+			name: "emergency class",
+			config: SoundConfig{ //nolint:exhaustruct // not enabled by the sink itself
+				Command: player,
+				Volume:  &half,
+				Classes: map[string]SoundClassConfig{
+					SoundClassEmergency: {Sound: "alarm.wav", Volume: nil, Muted: false},
+				},
+			},
+			event:      soundEvent(EventKindChange, emergency),
+			wantBeeps:  0,
+			wantPlayed: []string{"play", "--volume=50", "alarm.wav"},
+		},
+		{
+			name: "class without sound beeps",
+			config: SoundConfig{ //nolint:exhaustruct // not enabled by the sink itself
+				Command: player,
+			},
+			event:      soundEvent(EventKindNote, nil),
+			wantBeeps:  1,
+			wantPlayed: nil,
+		},
+		{
+			name: "class muted",
+			config: SoundConfig{ //nolint:exhaustruct // not enabled by the sink itself
+				Classes: map[string]SoundClassConfig{
+					SoundClassFeed: {Sound: "", Volume: nil, Muted: true},
+				},
+			},
+			event:      soundEvent(EventKindFeed, nil),
+			wantBeeps:  0,
+			wantPlayed: nil,
+		},
+		{
+			name:       "volume 0 mutes the beep",
+			config:     SoundConfig{Volume: &silent}, //nolint:exhaustruct // volume only
+			event:      soundEvent(EventKindRarity, nil),
+			wantBeeps:  0,
+			wantPlayed: nil,
+		},
+		{
+			name: "class volume 0 mutes the beep",
+			config: SoundConfig{ //nolint:exhaustruct // not enabled by the sink itself
+				Classes: map[string]SoundClassConfig{
+					SoundClassNote: {Sound: "", Volume: &silent, Muted: false},
+				},
+			},
+			event:      soundEvent(EventKindNote, nil),
+			wantBeeps:  0,
+			wantPlayed: nil,
+		},
+		{
+			name:       "all muted",
+			config:     SoundConfig{Muted: true}, //nolint:exhaustruct // muted only
+			event:      soundEvent(EventKindRarity, nil),
+			wantBeeps:  0,
+			wantPlayed: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewSoundSink(tt.config)
+			if err != nil {
+				t.Fatalf("NewSoundSink() error = %v", err)
+			}
+			beeps := 0
+			var played []string
+			sink.beep = func() error {
+				beeps++
+				return nil
+			}
+			sink.start = func(args []string) error {
+				played = args
+				return nil
+			}
+
+			if err := sink.Emit(tt.event); err != nil {
+				t.Fatalf("Emit() error = %v", err)
+			}
+			if beeps != tt.wantBeeps {
+				t.Errorf("Emit() beeped %d times, expected %d", beeps, tt.wantBeeps)
+			}
+			if !slices.Equal(played, tt.wantPlayed) {
+				t.Errorf("Emit() played %q, expected %q", played, tt.wantPlayed)
+			}
+		})
+	}
+}
+
+func soundEvent(kind string, change *IdentityChange) Event {
+	return Event{
+		Kind:     kind,
+		Title:    "",
+		Body:     "",
+		Summary:  "",
+		Time:     time.Time{},
+		Sighting: nil,
+		Change:   change,
+		Movement: nil,
+	}
+}
+
+func TestNewSoundSinkInvalid(t *testing.T) {
+	loud := 150
+	tests := []struct {
+		name     string
+		config   SoundConfig
+		expected error
+	}{
+		{
+			name:     "volume too high",
+			config:   SoundConfig{Volume: &loud}, //nolint:exhaustruct // volume only
+			expected: errInvalidSoundVolume,
+		},
+		{
+			name: "unknown class",
+			config: SoundConfig{ //nolint:exhaustruct // classes only
+				Classes: map[string]SoundClassConfig{"takeoff": {Sound: "", Volume: nil, Muted: false}},
+			},
+			expected: errInvalidSoundClass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSoundSink(tt.config); !errors.Is(err, tt.expected) {
+				t.Errorf("NewSoundSink() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
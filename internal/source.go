@@ -0,0 +1,336 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Data sources of aircraft, all of which serve readsb-style JSON.
+const (
+	SourceAdsbFi  = "adsbfi"
+	SourceAdsbLol = "adsblol"
+	SourceAdsbOne = "adsbone"
+	// SourceAdsbExchange is ADS-B Exchange via RapidAPI, which requires an API key.
+	SourceAdsbExchange = "adsbx"
+	// SourceAviationstack is aviationstack, which requires an API key.
+	SourceAviationstack = "aviationstack"
+	// SourceLocal is a local receiver like dump1090 or readsb, which requires its URL.
+	SourceLocal = "local"
+	// SourceADSC is a feed of ADS-C or satellite positions, e.g. of oceanic traffic beyond the range
+	// of terrestrial receivers, which requires its URL.
+	SourceADSC = "adsc"
+
+	// messageTypeADSC is the readsb message type of ADS-C positions.
+	messageTypeADSC = "adsc"
+
+	// aircraftReqDist is the radius around the location in which aircraft are requested, in [NM].
+	aircraftReqDist = "250"
+)
+
+var (
+	errUnknownSource = errors.New("unknown data source")
+	errMissingAPIKey = errors.New("missing API key")
+	errInvalidLocal  = errors.New("missing or invalid URL of local receiver")
+	errInvalidADSC   = errors.New("missing or invalid URL of ADS-C feed")
+)
+
+// SourceNames returns the names of all data sources.
+func SourceNames() []string {
+	return []string{
+		SourceAdsbFi,
+		SourceAdsbLol,
+		SourceAdsbOne,
+		SourceAdsbExchange,
+		SourceAviationstack,
+		SourceLocal,
+		SourceADSC,
+	}
+}
+
+// aircraftSource is a data source together with how to request aircraft from it.
+type aircraftSource struct {
+	name    string
+	reqURLs []string          // reqURLs are all requested on every poll, one per circle of a region.
+	headers map[string]string // headers are sent along, e.g. API keys.
+	parse   pageParser
+}
+
+// pageParser turns the response to the given URL into the aircraft the filter keeps, counting what
+// couldn't be decoded. Sources which page their responses also return the URL of the next page, all
+// others and the last page an empty one.
+type pageParser func(
+	reqURL string,
+	body io.Reader,
+	diag *DecodeDiagnostics,
+	keep recordFilter,
+) ([]AircraftRecord, string, error)
+
+// unpaged adapts the parser of a source which responds with all aircraft at once.
+func unpaged(
+	parse func(body io.Reader, diag *DecodeDiagnostics, keep recordFilter) ([]AircraftRecord, error),
+) pageParser {
+	return func(_ string, body io.Reader, diag *DecodeDiagnostics, keep recordFilter) ([]AircraftRecord, string, error) {
+		aircraft, err := parse(body, diag, keep)
+		return aircraft, "", err
+	}
+}
+
+// newAircraftSources sets up the requests of aircraft from the given sources, skipping duplicates.
+func newAircraftSources(names []string, opts RequestOptions) ([]aircraftSource, error) {
+	sources := make([]aircraftSource, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		source, err := newAircraftSource(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+func sourceNames(sources []aircraftSource) []string {
+	names := make([]string, len(sources))
+	for idx, source := range sources {
+		names[idx] = source.name
+	}
+	return names
+}
+
+// newAircraftSource sets up the requests of aircraft around the location from the given source.
+// Sources which need authentication take their API key from the options.
+func newAircraftSource(source string, opts RequestOptions) (aircraftSource, error) {
+	aircraft := aircraftSource{name: source, reqURLs: nil, headers: nil, parse: unpaged(parseReadsbAircraft)}
+
+	if source == SourceLocal {
+		// The receiver is chosen by the user, so it isn't restricted to the known hosts.
+		parsed, parseErr := url.Parse(opts.LocalURL)
+		if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return aircraft, fmt.Errorf("newAircraftSource: %w: %q", errInvalidLocal, opts.LocalURL)
+		}
+		aircraft.reqURLs = []string{parsed.String()}
+		return aircraft, nil
+	}
+	if source == SourceADSC {
+		// Like the local receiver, the feed is chosen by the user. readsb types the positions it
+		// received by ADS-C itself, so they aren't told apart from others of the feed by guessing.
+		parsed, parseErr := url.Parse(opts.ADSCURL)
+		if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return aircraft, fmt.Errorf("newAircraftSource: %w: %q", errInvalidADSC, opts.ADSCURL)
+		}
+		aircraft.reqURLs = []string{parsed.String()}
+		return aircraft, nil
+	}
+
+	apiKey := opts.APIKeys[source]
+	if (source == SourceAdsbExchange || source == SourceAviationstack) && apiKey == "" {
+		return aircraft, fmt.Errorf("newAircraftSource: %w for %s", errMissingAPIKey, source)
+	}
+
+	reqURLs, urlErr := createAircraftReqURLs(source, opts)
+	if urlErr != nil {
+		return aircraft, fmt.Errorf("newAircraftSource: %w", urlErr)
+	}
+	aircraft.reqURLs = reqURLs
+
+	switch source {
+	case SourceAdsbExchange:
+		aircraft.headers = map[string]string{
+			"X-RapidAPI-Key":  apiKey,
+			"X-RapidAPI-Host": adsbExchangeReqHost,
+		}
+	case SourceAviationstack:
+		// aviationstack only takes the key as query parameter, which must not end up in logs.
+		for idx, reqURL := range reqURLs {
+			keyedURL, parseErr := url.Parse(reqURL)
+			if parseErr != nil {
+				return aircraft, fmt.Errorf("newAircraftSource: %w", parseErr)
+			}
+			query := keyedURL.Query()
+			query.Set("access_key", apiKey)
+			keyedURL.RawQuery = query.Encode()
+			aircraft.reqURLs[idx] = keyedURL.String()
+		}
+		aircraft.parse = func(
+			reqURL string,
+			body io.Reader,
+			_ *DecodeDiagnostics,
+			keep recordFilter,
+		) ([]AircraftRecord, string, error) {
+			parsed, page, parseErr := parseAviationstackAircraft(body, opts, keep, time.Now())
+			if parseErr != nil {
+				return nil, "", parseErr
+			}
+			next, nextErr := aviationstackNextPage(reqURL, page)
+			return parsed, next, nextErr
+		}
+	}
+
+	return aircraft, nil
+}
+
+// createAircraftReqURLs builds the URLs to request the aircraft around the location or of the
+// region from the given source, one per circle, or only the followed aircraft. aviationstack can't
+// be asked for a circle, so it is requested once, page by page.
+func createAircraftReqURLs(source string, opts RequestOptions) ([]string, error) {
+	if opts.Follow != nil {
+		reqURL, err := createFollowReqURL(source, *opts.Follow)
+		return []string{reqURL}, err
+	}
+	circles := opts.queryCircles()
+	if source == SourceAviationstack {
+		circles = circles[:1]
+	}
+	reqURLs := make([]string, 0, len(circles))
+	for _, circle := range circles {
+		reqURL, err := createAircraftReqURL(source, circle)
+		if err != nil {
+			return nil, err
+		}
+		reqURLs = append(reqURLs, reqURL)
+	}
+	return reqURLs, nil
+}
+
+// createAircraftReqURL builds the URL to request the aircraft within the circle from the given
+// source.
+func createAircraftReqURL(source string, circle queryCircle) (string, error) {
+	latStr := strconv.FormatFloat(circle.lat, 'f', 6, 32)
+	lonStr := strconv.FormatFloat(circle.lon, 'f', 6, 32)
+	distStr := strconv.FormatFloat(circle.radius, 'f', 0, 64)
+
+	var fullURL *url.URL
+	switch source {
+	case SourceAdsbFi:
+		baseURL := &url.URL{Scheme: "https", Host: aircraftReqHost}
+		fullURL = baseURL.JoinPath("api", "v2", "lat", latStr, "lon", lonStr, "dist", distStr)
+	case SourceAdsbLol:
+		baseURL := &url.URL{Scheme: "https", Host: adsbLolReqHost}
+		fullURL = baseURL.JoinPath("v2", "lat", latStr, "lon", lonStr, "dist", distStr)
+	case SourceAdsbOne:
+		baseURL := &url.URL{Scheme: "https", Host: adsbOneReqHost}
+		fullURL = baseURL.JoinPath("v2", "point", latStr, lonStr, distStr)
+	case SourceAdsbExchange:
+		baseURL := &url.URL{Scheme: "https", Host: adsbExchangeReqHost}
+		fullURL = baseURL.JoinPath("v2", "lat", latStr, "lon", lonStr, "dist", distStr, "/")
+	case SourceAviationstack:
+		// aviationstack can't filter by location, so all live flights are requested.
+		baseURL := &url.URL{Scheme: "https", Host: aviationstackReqHost}
+		fullURL = baseURL.JoinPath("v1", "flights")
+		fullURL.RawQuery = url.Values{"flight_status": {"active"}}.Encode()
+	default:
+		return "", fmt.Errorf("createAircraftReqURL: %w: %s", errUnknownSource, source)
+	}
+
+	validatedURL, valErr := validateURL(fullURL.String())
+	if valErr != nil {
+		return "", fmt.Errorf("createAircraftReqURL: error validating URL: %w", valErr)
+	}
+	return validatedURL, nil
+}
+
+// SourceStats attributes the fused aircraft to the data source which reported them.
+type SourceStats struct {
+	Polls     int // Polls is how often the source was requested.
+	Errors    int // Errors is how many of these requests failed.
+	Aircraft  int // Aircraft is how many aircraft the source reported over all polls.
+	Exclusive int // Exclusive is how many of them no other source reported.
+	Positions int // Positions is how many fused aircraft took their position from the source.
+}
+
+func (s SourceStats) String() string {
+	return fmt.Sprintf(
+		"%d aircraft, %d exclusive, %d positions, %d of %d polls failed",
+		s.Aircraft,
+		s.Exclusive,
+		s.Positions,
+		s.Errors,
+		s.Polls)
+}
+
+// FuseAircraft merges the aircraft lists of several sources into one, deduplicated by hex.
+// Of all records of the same aircraft, the one with the most recent position is used, and any
+// fields missing from it are filled in from the other records.
+// The sources are given in order of preference, which breaks ties. Each aircraft is tagged with
+// the source its position was taken from.
+// The attribution of the result to the sources is added to the given stats.
+func FuseAircraft(
+	sources []string,
+	aircraftBySource map[string][]AircraftRecord,
+	stats map[string]*SourceStats,
+) []AircraftRecord {
+	type report struct {
+		source string
+		record *AircraftRecord
+	}
+
+	var hexOrder []string
+	reportsByHex := make(map[string][]report)
+	for _, source := range sources {
+		for idx := range aircraftBySource[source] {
+			record := &aircraftBySource[source][idx]
+			if _, ok := reportsByHex[record.Hex]; !ok {
+				hexOrder = append(hexOrder, record.Hex)
+			}
+			reportsByHex[record.Hex] = append(reportsByHex[record.Hex], report{source, record})
+			stats[source].Aircraft++
+		}
+	}
+
+	fused := make([]AircraftRecord, 0, len(hexOrder))
+	for _, hex := range hexOrder {
+		reports := reportsByHex[hex]
+		sort.SliceStable(reports, func(i, j int) bool {
+			return positionAge(reports[i].record) < positionAge(reports[j].record)
+		})
+
+		if len(reports) == 1 {
+			stats[reports[0].source].Exclusive++
+		}
+		if positionAge(reports[0].record) < math.Inf(1) {
+			stats[reports[0].source].Positions++
+		}
+
+		record := *reports[0].record
+		for _, other := range reports[1:] {
+			fillMissingFields(&record, other.record)
+		}
+		record.Source = reports[0].source
+		fused = append(fused, record)
+	}
+	return fused
+}
+
+// positionAge returns how many seconds ago the position of the aircraft was updated, infinity if
+// it has no position at all. Last known positions count as well, since they are older than any
+// live one anyway.
+func positionAge(record *AircraftRecord) float64 {
+	position, ok := record.KnownPosition()
+	if !ok {
+		return math.Inf(1)
+	}
+	return position.SeenPos
+}
+
+// fillMissingFields copies every field which is unset in the record from the other record.
+func fillMissingFields(record *AircraftRecord, other *AircraftRecord) {
+	target := reflect.ValueOf(record).Elem()
+	source := reflect.ValueOf(other).Elem()
+	for idx := range target.NumField() {
+		field := target.Field(idx)
+		if field.IsZero() && !source.Field(idx).IsZero() {
+			field.Set(source.Field(idx))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFuseAircraft(t *testing.T) {
+	//nolint:exhaustruct // only the fused fields matter
+	aircraftBySource := map[string][]AircraftRecord{
+		SourceAdsbFi: {
+			{Hex: "3c6444", Lat: 53.5, Lon: 9.9, SeenPos: 5, Flight: "DLH4AB"},
+			{Hex: "4b1805", Lat: 53.1, Lon: 9.1, SeenPos: 1},
+		},
+		SourceAdsbLol: {
+			{Hex: "3c6444", Lat: 53.6, Lon: 10.0, SeenPos: 1, Registration: "D-AIBL"},
+			{Hex: "a12345"},
+		},
+	}
+	stats := map[string]*SourceStats{
+		SourceAdsbFi:  {Polls: 0, Errors: 0, Aircraft: 0, Exclusive: 0, Positions: 0},
+		SourceAdsbLol: {Polls: 0, Errors: 0, Aircraft: 0, Exclusive: 0, Positions: 0},
+	}
+
+	fused := FuseAircraft([]string{SourceAdsbFi, SourceAdsbLol}, aircraftBySource, stats)
+	if len(fused) != 3 {
+		t.Fatalf("FuseAircraft() returned %d aircraft, expected 3", len(fused))
+	}
+
+	merged := fused[0]
+	if merged.Hex != "3c6444" || merged.Lat != 53.6 || merged.SeenPos != 1 {
+		t.Errorf("FuseAircraft() didn't prefer the most recent position: %+v", merged)
+	}
+	if merged.Flight != "DLH4AB" || merged.Registration != "D-AIBL" {
+		t.Errorf("FuseAircraft() didn't fill in missing fields: %+v", merged)
+	}
+	if merged.Source != SourceAdsbLol || fused[1].Source != SourceAdsbFi {
+		t.Errorf("FuseAircraft() tagged the sources %s and %s, expected %s and %s",
+			merged.Source, fused[1].Source, SourceAdsbLol, SourceAdsbFi)
+	}
+
+	expected := map[string]SourceStats{
+		SourceAdsbFi:  {Polls: 0, Errors: 0, Aircraft: 2, Exclusive: 1, Positions: 1},
+		SourceAdsbLol: {Polls: 0, Errors: 0, Aircraft: 2, Exclusive: 1, Positions: 1},
+	}
+	for source, expectedStats := range expected {
+		if *stats[source] != expectedStats {
+			t.Errorf("stats of %s = %+v, expected %+v", source, *stats[source], expectedStats)
+		}
+	}
+}
+
+func TestCreateAircraftReqURLs(t *testing.T) {
+	opts := testRequestOptions(53.5, 9.9)
+	for _, source := range SourceNames() {
+		if source == SourceLocal || source == SourceADSC {
+			continue // the local receiver and the ADS-C feed have no fixed URL
+		}
+		if reqURLs, err := createAircraftReqURLs(source, opts); err != nil || len(reqURLs) != 1 {
+			t.Errorf("createAircraftReqURLs(%s) = %v, %v, expected a single URL", source, reqURLs, err)
+		}
+	}
+
+	if _, err := createAircraftReqURLs("nosuchsource", opts); err == nil {
+		t.Error("createAircraftReqURLs() accepted unknown source")
+	}
+}
+
+func TestSwitchSources(t *testing.T) {
+	var stderr io.Writer = io.Discard
+	opts := testRequestOptions(53.5, 9.9)
+	opts.Sources = []string{SourceAdsbFi}
+	opts.LocalURL = "http://localhost:8080/data/aircraft.json"
+	request, err := NewRequest(opts, &stderr)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	request.recordPoll(nil, time.Now())
+
+	if err := request.SwitchSources([]string{SourceAdsbExchange}); err == nil {
+		t.Error("SwitchSources() switched to a source without API key")
+	}
+	if active := request.ActiveSources(); len(active) != 1 || active[0] != SourceAdsbFi {
+		t.Errorf("ActiveSources() = %v after failed switch, expected [%s]", active, SourceAdsbFi)
+	}
+
+	if err := request.SwitchSources([]string{SourceLocal, SourceAdsbLol}); err != nil {
+		t.Fatalf("SwitchSources() error = %v", err)
+	}
+	if active := request.ActiveSources(); len(active) != 2 || active[0] != SourceLocal {
+		t.Errorf("ActiveSources() = %v, expected [%s %s]", active, SourceLocal, SourceAdsbLol)
+	}
+	if lastPoll, _ := request.LastPoll(); !lastPoll.IsZero() {
+		t.Errorf("LastPoll() = %v after switch, expected no poll yet", lastPoll)
+	}
+	if names, _ := request.SourceStats(); len(names) != 2 {
+		t.Errorf("SourceStats() names = %v, expected the active sources", names)
+	}
+}
+
+func TestADSCSource(t *testing.T) {
+	opts := testRequestOptions(53.5, 9.9)
+	if _, err := newAircraftSource(SourceADSC, opts); err == nil {
+		t.Error("newAircraftSource(adsc) succeeded without URL")
+	}
+
+	opts.ADSCURL = "http://localhost:8090/data/aircraft.json"
+	source, err := newAircraftSource(SourceADSC, opts)
+	if err != nil {
+		t.Fatalf("newAircraftSource(adsc) error = %v", err)
+	}
+	body := []byte(`{"aircraft": [{"hex": "4ca7b4", "lat": 52.1, "lon": -35.2, "type": "adsc"},
+		{"hex": "a0b1c2", "lat": 48.9, "lon": -40.3, "type": "adsb_icao"},
+		{"hex": "3c6444", "lat": 53.6, "lon": 9.9}]}`)
+	aircraft, _, parseErr := source.parse(opts.ADSCURL, bytes.NewReader(body), NewDecodeDiagnostics(), nil)
+	if parseErr != nil {
+		t.Fatalf("parse() error = %v", parseErr)
+	}
+	types := make([]string, len(aircraft))
+	for idx, record := range aircraft {
+		types[idx] = record.Type
+	}
+	if !slices.Equal(types, []string{messageTypeADSC, "adsb_icao", ""}) {
+		t.Errorf("parse() types = %q, expected them as reported by the feed", types)
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errInvalidDayStart = errors.New("day start hour must be between 0 and 23")
+
+// SpottingDay tells when the days of the daily statistics and reports start. Sessions often go on
+// past midnight, so the day may roll over later, e.g. at 03:00, to keep the sightings of a night
+// together. The zero value starts the days at midnight.
+type SpottingDay struct {
+	startHour int // startHour is the hour of the day, in local time, at which a new day starts.
+}
+
+// NewSpottingDay creates spotting days which start at the given hour of the day.
+func NewSpottingDay(startHour int) (SpottingDay, error) {
+	if startHour < 0 || startHour >= hoursPerDay {
+		return SpottingDay{}, fmt.Errorf("NewSpottingDay: %w: %d", errInvalidDayStart, startHour)
+	}
+	return SpottingDay{startHour: startHour}, nil
+}
+
+// Start returns when the spotting day of the given time started, in the location of the time.
+func (d SpottingDay) Start(t time.Time) time.Time {
+	year, month, day := t.Date()
+	start := time.Date(year, month, day, d.startHour, 0, 0, 0, t.Location())
+	if t.Before(start) {
+		start = time.Date(year, month, day-1, d.startHour, 0, 0, 0, t.Location())
+	}
+	return start
+}
+
+// StartHour returns the hour of the day at which the spotting days start.
+func (d SpottingDay) StartHour() int {
+	return d.startHour
+}
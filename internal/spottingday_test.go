@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSpottingDayStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		startHour int
+		time      time.Time
+		expected  time.Time
+	}{
+		{
+			name:      "midnight",
+			startHour: 0,
+			time:      time.Date(2025, time.June, 3, 2, 30, 0, 0, time.UTC),
+			expected:  time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "before the rollover",
+			startHour: 3,
+			time:      time.Date(2025, time.June, 3, 2, 59, 0, 0, time.UTC),
+			expected:  time.Date(2025, time.June, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "at the rollover",
+			startHour: 3,
+			time:      time.Date(2025, time.June, 3, 3, 0, 0, 0, time.UTC),
+			expected:  time.Date(2025, time.June, 3, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "across months",
+			startHour: 4,
+			time:      time.Date(2025, time.July, 1, 1, 0, 0, 0, time.UTC),
+			expected:  time.Date(2025, time.June, 30, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, err := NewSpottingDay(tt.startHour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start := day.Start(tt.time); !start.Equal(tt.expected) {
+				t.Errorf("Start(%v) = %v, expected %v", tt.time, start, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewSpottingDayInvalid(t *testing.T) {
+	for _, hour := range []int{-1, 24} {
+		if _, err := NewSpottingDay(hour); !errors.Is(err, errInvalidDayStart) {
+			t.Errorf("NewSpottingDay(%d) error = %v, expected %v", hour, err, errInvalidDayStart)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Prefixes of the locations of the sighting stores, which are history files without any.
+const (
+	StoreSQLite     = "sqlite:"       // StoreSQLite prefixes the path of an SQLite database.
+	StoreBolt       = "bolt:"         // StoreBolt prefixes the path of a BoltDB database.
+	StorePostgres   = "postgres://"   // StorePostgres starts the URL of a PostgreSQL database.
+	storePostgreSQL = "postgresql://" // storePostgreSQL is the long form of StorePostgres.
+)
+
+// SightingStore persists the sightings, one entry per flight of an aircraft.
+type SightingStore interface {
+	// Append adds the sightings to the store.
+	Append(entries []HistoryEntry) error
+	// Load returns all sightings of the store, oldest first.
+	Load() ([]HistoryEntry, error)
+	// Check tells whether the store can be written to.
+	Check() error
+	// Close releases the store, which can't be used afterwards.
+	Close() error
+}
+
+// NotificationStore keeps the notifications, so that they can be reviewed after a restart. The
+// sighting stores keep them as well, next to the sightings.
+type NotificationStore interface {
+	// AppendNotification adds the notification to the store.
+	AppendNotification(entry NotificationEntry) error
+	// LoadNotifications returns the last notifications of the store, oldest first, at most limit
+	// of them, and how many entries were skipped since they couldn't be read.
+	LoadNotifications(limit int) ([]NotificationEntry, int, error)
+}
+
+// OpenSightingStore opens the store at the given location: an SQLite or BoltDB database with the
+// prefix StoreSQLite or StoreBolt, a PostgreSQL database by its URL, or a history file otherwise.
+// Databases may be shared by several receivers, whose sightings are told apart by observer.
+func OpenSightingStore(location string, observer string) (SightingStore, error) {
+	var store SightingStore
+	var err error
+	switch {
+	case strings.HasPrefix(location, StoreSQLite):
+		store, err = OpenSQLiteStore(strings.TrimPrefix(location, StoreSQLite), observer)
+	case strings.HasPrefix(location, StoreBolt):
+		store, err = OpenBoltStore(strings.TrimPrefix(location, StoreBolt))
+	case isPostgresURL(location):
+		store, err = OpenPostgresStore(location, observer)
+	default:
+		store = NewHistory(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("OpenSightingStore: %w", err)
+	}
+	return store, nil
+}
+
+func isPostgresURL(location string) bool {
+	return strings.HasPrefix(location, StorePostgres) || strings.HasPrefix(location, storePostgreSQL)
+}
+
+// Close does nothing, history files are only open while they are read or appended to.
+func (h *History) Close() error {
+	return nil
+}
+
+// AppendNotification adds the notification to the notification file next to the history file.
+func (h *History) AppendNotification(entry NotificationEntry) error {
+	return h.notifications().AppendNotification(entry)
+}
+
+// LoadNotifications reads the last notifications of the notification file next to the history
+// file, see NotificationFile.LoadNotifications.
+func (h *History) LoadNotifications(limit int) ([]NotificationEntry, int, error) {
+	return h.notifications().LoadNotifications(limit)
+}
+
+// notifications is the notification file next to the history file, e.g.
+// "airspottr_history_notifications.jsonl" for "airspottr_history.jsonl".
+func (h *History) notifications() *NotificationFile {
+	return NewNotificationFile(strings.TrimSuffix(h.path, filepath.Ext(h.path)) + "_notifications.jsonl")
+}
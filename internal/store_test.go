@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSightingStores(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		location string
+	}{
+		{name: "history file", location: filepath.Join(dir, "history.jsonl")},
+		{name: "sqlite", location: StoreSQLite + filepath.Join(dir, "history.db")},
+		{name: "bolt", location: StoreBolt + filepath.Join(dir, "history.bolt")},
+	}
+
+	seen := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	first := sharedEntry(seen, "A320", "Lufthansa", "Germany")
+	second := sharedEntry(seen.Add(time.Minute), "B738", "Ryanair", "Ireland")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, openErr := OpenSightingStore(test.location, "home")
+			if openErr != nil {
+				t.Fatal(openErr)
+			}
+			if err := store.Append([]HistoryEntry{first}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Append([]HistoryEntry{second}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Check(); err != nil {
+				t.Errorf("Check() = %v, expected no error", err)
+			}
+			if err := store.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// The sightings outlive the store.
+			reopened, reopenErr := OpenSightingStore(test.location, "home")
+			if reopenErr != nil {
+				t.Fatal(reopenErr)
+			}
+			defer func() {
+				_ = reopened.Close()
+			}()
+			entries, loadErr := reopened.Load()
+			if loadErr != nil {
+				t.Fatal(loadErr)
+			}
+			if expected := []HistoryEntry{first, second}; !reflect.DeepEqual(entries, expected) {
+				t.Errorf("Load() = %+v, expected %+v", entries, expected)
+			}
+		})
+	}
+}
+
+func TestNotificationStores(t *testing.T) {
+	dir := t.TempDir()
+	locations := []string{
+		filepath.Join(dir, "history.jsonl"),
+		StoreSQLite + filepath.Join(dir, "history.db"),
+		StoreBolt + filepath.Join(dir, "history.bolt"),
+	}
+
+	sent := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	//nolint:exhaustruct // no aircraft
+	entries := []NotificationEntry{
+		{Time: sent, Kind: EventKindRarity, Title: "Rare type", Body: "GAF681", Flight: "GAF681"},
+		{Time: sent.Add(time.Minute), Kind: EventKindFeed, Title: "Feed stalled", Body: "no aircraft"},
+		{Time: sent.Add(2 * time.Minute), Kind: EventKindPeak, Title: "New peak", Body: "42 aircraft"},
+	}
+
+	for _, location := range locations {
+		t.Run(location, func(t *testing.T) {
+			store, openErr := OpenSightingStore(location, "home")
+			if openErr != nil {
+				t.Fatal(openErr)
+			}
+			defer func() {
+				_ = store.Close()
+			}()
+			notifications, ok := store.(NotificationStore)
+			if !ok {
+				t.Fatalf("%T keeps no notifications", store)
+			}
+			for _, entry := range entries {
+				if err := notifications.AppendNotification(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+			last, skipped, loadErr := notifications.LoadNotifications(2)
+			if loadErr != nil {
+				t.Fatal(loadErr)
+			}
+			if !reflect.DeepEqual(last, entries[1:]) || skipped != 0 {
+				t.Errorf("LoadNotifications(2) = %+v, %d, expected the last two, oldest first", last, skipped)
+			}
+		})
+	}
+}
+
+func TestSQLStoreByObserver(t *testing.T) {
+	store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "history.db"), "home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	seen := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	if err := store.Append([]HistoryEntry{sharedEntry(seen, "A320", "Lufthansa", "Germany")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Push("office", []HistoryEntry{sharedEntry(seen, "B738", "Ryanair", "Ireland")}); err != nil {
+		t.Fatal(err)
+	}
+
+	own, loadErr := store.Load()
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if len(own) != 1 || own[0].Type != "A320" {
+		t.Errorf("Load() = %+v, expected only the A320 of home", own)
+	}
+	others, _, pullErr := store.Pull("home", 0)
+	if pullErr != nil {
+		t.Fatal(pullErr)
+	}
+	if len(others) != 1 || others[0].Type != "B738" {
+		t.Errorf("Pull(home) = %+v, expected only the B738 of office", others)
+	}
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"slices"
+	"time"
+
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// DefaultMachAlert is the Mach number beyond which supersonic-capable aircraft are alerted.
+const DefaultMachAlert = 1.0
+
+// supersonicTypes are the ICAO types which can fly faster than sound. Only they are alerted when
+// they exceed the Mach threshold, since a Mach number beyond it reported by any other aircraft is
+// more likely a glitch than a record.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var supersonicTypes = []string{
+	"B1", "EUFI", "F4", "F5", "F14", "F15", "F16", "F18H", "F18S", "F22", "F35", "MG29", "MIR2",
+	"RFAL", "SB39", "SU27", "T38", "T160", "TOR",
+}
+
+// MachAlert is a supersonic-capable aircraft which exceeded the Mach threshold.
+type MachAlert struct {
+	Mach     float64
+	Sighting *AircraftSighting
+}
+
+// IsSupersonicCapable tells whether aircraft of the ICAO type can fly faster than sound.
+func IsSupersonicCapable(icaoType string) bool {
+	return slices.Contains(supersonicTypes, icaoType)
+}
+
+// checkMach reports a supersonic-capable aircraft which exceeds the Mach threshold, once per
+// flight.
+func (db *Dashboard) checkMach(sighting *AircraftSighting, aircraft *AircraftRecord) (MachAlert, bool) {
+	if db.machAlert <= 0 || sighting.machAlerted || aircraft.Mach < db.machAlert ||
+		!IsSupersonicCapable(aircraft.IcaoType) {
+		return MachAlert{Mach: 0, Sighting: nil}, false
+	}
+	sighting.machAlerted = true
+	return MachAlert{Mach: aircraft.Mach, Sighting: sighting}, true
+}
+
+// EmitMachAlerts sends an event for every aircraft beyond the Mach threshold to all enabled sinks.
+func (notify *Notify) EmitMachAlerts(alerts []MachAlert, now time.Time) {
+	for _, alert := range alerts {
+		notify.alert(machAlertEvent(notify.language, alert, now), NotifySupersonic)
+	}
+}
+
+func machAlertEvent(lang i18n.Language, alert MachAlert, now time.Time) Event {
+	sighting := alert.Sighting
+	msgBody := lang.Sprintf(
+		"%s %s (%s) at Mach %.2f\n%s",
+		sighting.lastFlightNo,
+		sighting.typeDesc,
+		sighting.registration,
+		alert.Mach,
+		sighting.whereabouts(lang))
+	return Event{
+		Kind:     EventKindSupersonic,
+		Severity: SeverityRare,
+		Title:    lang.T("Supersonic aircraft"),
+		Body:     msgBody,
+		Summary:  lang.Sprintf("supersonic at Mach %.2f: %s", alert.Mach, sighting.info),
+		Time:     now,
+		Sighting: sighting,
+		Change:   nil,
+		Movement: nil,
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import "testing"
+
+func TestCheckMach(t *testing.T) {
+	db := &Dashboard{machAlert: DefaultMachAlert} //nolint:exhaustruct // only the threshold
+	tests := []struct {
+		name     string
+		icaoType string
+		mach     float64
+		alerted  bool
+		expected bool
+	}{
+		{name: "supersonic fighter", icaoType: "EUFI", mach: 1.2, alerted: false, expected: true},
+		{name: "subsonic fighter", icaoType: "EUFI", mach: 0.9, alerted: false, expected: false},
+		{name: "already alerted", icaoType: "EUFI", mach: 1.3, alerted: true, expected: false},
+		{name: "glitch of an airliner", icaoType: "A320", mach: 1.1, alerted: false, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sighting := &AircraftSighting{machAlerted: test.alerted}              //nolint:exhaustruct // alert state only
+			aircraft := &AircraftRecord{IcaoType: test.icaoType, Mach: test.mach} //nolint:exhaustruct // type and speed only
+			alert, ok := db.checkMach(sighting, aircraft)
+			if ok != test.expected {
+				t.Fatalf("checkMach() ok = %v, expected %v", ok, test.expected)
+			}
+			if ok && (alert.Mach != test.mach || !sighting.machAlerted) {
+				t.Errorf("checkMach() = %+v, expected Mach %.2f and the flight marked", alert, test.mach)
+			}
+		})
+	}
+
+	db.machAlert = 0
+	sighting := &AircraftSighting{}                         //nolint:exhaustruct // fresh sighting
+	aircraft := &AircraftRecord{IcaoType: "F22", Mach: 1.5} //nolint:exhaustruct // type and speed only
+	if _, ok := db.checkMach(sighting, aircraft); ok {
+		t.Error("checkMach() alerted with the alert disabled")
+	}
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// EventKindAircraft is the kind of the lines which report a single aircraft of an update.
+const EventKindAircraft = "aircraft"
+
+// AircraftPayload is the machine-readable representation of an aircraft of an update, as written
+// to the tee output.
+type AircraftPayload struct {
+	Event        string    `json:"event"`
+	Time         time.Time `json:"time"`
+	New          bool      `json:"new"` // whether the aircraft started a new flight with this update
+	Hex          string    `json:"hex"`
+	Flight       string    `json:"flight"`
+	Registration string    `json:"registration"`
+	Type         string    `json:"type"`
+	Altitude     string    `json:"altitude"` // altitude in [feet] or "ground"
+	Speed        float64   `json:"speed"`    // ground speed in [knots]
+	Heading      float64   `json:"heading"`
+	Distance     float64   `json:"distance"` // distance in [km]
+	Lat          float64   `json:"lat"`
+	Lon          float64   `json:"lon"`
+}
+
+// Tee writes a machine-readable log of the session as newline-delimited JSON: every aircraft of
+// every update, followed by the events the update caused.
+// It works with any kind of file, including FIFOs, so it can be run alongside the TUI.
+type Tee struct {
+	file    *os.File
+	encoder *json.Encoder
+	sink    *ConsoleSink // sink writes the events in the same format as the JSON console sink.
+}
+
+// NewTee opens the file at the given path for appending, creating it if necessary.
+// Opening a FIFO blocks until it has a reader.
+func NewTee(path string) (*Tee, error) {
+	file, openErr := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if openErr != nil {
+		return nil, fmt.Errorf("NewTee: failed to open %s: %w", path, openErr)
+	}
+
+	sink, sinkErr := NewConsoleSink(file, SinkFormatJSON)
+	if sinkErr != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("NewTee: %w", sinkErr)
+	}
+
+	return &Tee{file: file, encoder: json.NewEncoder(file), sink: sink}, nil
+}
+
+// WriteAircraft writes a line for every current aircraft of the dashboard.
+func (tee *Tee) WriteAircraft(dash *Dashboard, now time.Time) error {
+	isNew := make(map[string]bool, len(dash.NewAircraft))
+	for _, aircraft := range dash.NewAircraft {
+		isNew[aircraft.Hex] = true
+	}
+
+	for idx := range dash.CurrentAircraft {
+		aircraft := &dash.CurrentAircraft[idx]
+		aType := aircraft.Description
+		if aType == "" {
+			aType = aircraft.CachedType
+		}
+
+		payload := AircraftPayload{
+			Event:        EventKindAircraft,
+			Time:         now.UTC(),
+			New:          isNew[aircraft.Hex],
+			Hex:          aircraft.Hex,
+			Flight:       aircraft.GetFlightNoAsStr(),
+			Registration: aircraft.Registration,
+			Type:         aType,
+			Altitude:     strings.TrimSpace(aircraft.AltBaro.String()),
+			Speed:        aircraft.GroundSpeed,
+			Heading:      aircraft.NavHeading,
+			Distance:     aircraft.CachedDist,
+			Lat:          aircraft.Lat,
+			Lon:          aircraft.Lon,
+		}
+		if err := tee.encoder.Encode(payload); err != nil {
+			return fmt.Errorf("Tee.WriteAircraft: %w", err)
+		}
+	}
+	return nil
+}
+
+// Emit writes the event as a line of JSON.
+func (tee *Tee) Emit(event Event) error {
+	return tee.sink.Emit(event)
+}
+
+func (tee *Tee) Name() string { return "tee" }
+
+// Close closes the tee output.
+func (tee *Tee) Close() error {
+	if err := tee.file.Close(); err != nil {
+		return fmt.Errorf("Tee.Close: %w", err)
+	}
+	return nil
+}
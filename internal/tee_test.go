@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTeeWritesNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.ndjson")
+	tee, err := NewTee(path)
+	if err != nil {
+		t.Fatalf("NewTee() error = %v", err)
+	}
+
+	dash := &Dashboard{ //nolint:exhaustruct // aircraft only
+		CurrentAircraft: []AircraftRecord{
+			{Hex: "3c6444", Flight: "DLH4AB  ", AltBaro: NewAltitude(35000)}, //nolint:exhaustruct // reported fields only
+			{Hex: "4b1805", AltBaro: GroundAltitude()},                       //nolint:exhaustruct // reported fields only
+		},
+	}
+	dash.NewAircraft = []*AircraftRecord{&dash.CurrentAircraft[1]}
+
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	if err := tee.WriteAircraft(dash, now); err != nil {
+		t.Fatalf("WriteAircraft() error = %v", err)
+	}
+	sighting := &AircraftSighting{registration: "D-AIBL"} //nolint:exhaustruct // registration only
+	if err := tee.Emit(rarityEvent("Rare", "", "found rare type A320", sighting)); err != nil {
+		t.Fatalf("Emit() error = %v", err)
+	}
+	if err := tee.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open tee output: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("tee output line %q isn't JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("tee output has %d lines, expected 3", len(lines))
+	}
+	if lines[0]["event"] != EventKindAircraft || lines[0]["flight"] != "DLH4AB" ||
+		lines[0]["altitude"] != "35000" || lines[0]["new"] != false {
+		t.Errorf("first aircraft line = %v", lines[0])
+	}
+	if lines[1]["altitude"] != "ground" || lines[1]["new"] != true {
+		t.Errorf("second aircraft line = %v", lines[1])
+	}
+	if lines[2]["event"] != EventKindRarity || lines[2]["registration"] != "D-AIBL" {
+		t.Errorf("event line = %v", lines[2])
+	}
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// temperatureWindow is how long the temperature reports of aircraft are taken into account.
+	temperatureWindow = 30 * time.Minute
+	// temperatureBandWidth is the height of each temperature band in [feet], finer than the
+	// altitude bands since inversions are rarely deep. The last band is open.
+	temperatureBandWidth = 5000
+	temperatureBandCount = 9
+
+	// inversionMargin is how much warmer in [C] a band has to be than the one below to be flagged,
+	// so that the noise of a few reports isn't taken for an inversion.
+	inversionMargin = 1.0
+	// inversionReports is how many reports both bands need at least to flag an inversion.
+	inversionReports = 3
+)
+
+// TemperatureBand is the outside air temperature within a range of altitudes, averaged over the
+// reports of all aircraft.
+type TemperatureBand struct {
+	Label       string  // Label names the range, e.g. "5-10k".
+	Reports     int     // Reports is how many aircraft reports the temperature is averaged over.
+	Temperature float64 // Temperature is the average outside air temperature in [C].
+	Inversion   bool    // Inversion is set if the band is warmer than the one below.
+}
+
+// temperaturePoll is the temperature reported in a poll, by temperature band.
+type temperaturePoll struct {
+	sums    [temperatureBandCount]float64
+	reports [temperatureBandCount]int
+}
+
+// TemperatureStats averages the outside air temperature reported by airborne aircraft by
+// altitude, to show the temperature profile of the last half hour and flag inversions.
+// Aircraft which don't report any temperature, or no altitude, aren't counted.
+type TemperatureStats struct {
+	polls windowedSampler[temperaturePoll]
+}
+
+// NewTemperatureStats creates empty temperature statistics.
+func NewTemperatureStats() *TemperatureStats {
+	return &TemperatureStats{polls: newWindowedSampler[temperaturePoll](temperatureWindow)}
+}
+
+// temperatureBandIndex returns the temperature band of the given altitude in [feet].
+func temperatureBandIndex(feet float64) int {
+	return min(temperatureBandCount-1, max(0, int(feet/temperatureBandWidth)))
+}
+
+// temperatureBandLabel names the band of the given index, e.g. "0-5k" or "40k+".
+func temperatureBandLabel(idx int) string {
+	lower := idx * temperatureBandWidth / 1000
+	if idx == temperatureBandCount-1 {
+		return fmt.Sprintf("%dk+", lower)
+	}
+	return fmt.Sprintf("%d-%dk", lower, lower+temperatureBandWidth/1000)
+}
+
+// Record adds the temperature reported by the airborne aircraft of a poll and forgets polls which
+// have fallen out of the window.
+func (ts *TemperatureStats) Record(now time.Time, aircraft []AircraftRecord) {
+	poll := temperaturePoll{
+		sums:    [temperatureBandCount]float64{},
+		reports: [temperatureBandCount]int{},
+	}
+	for idx := range aircraft {
+		feet, ok := aircraft[idx].AltBaro.Feet()
+		if !ok || aircraft[idx].Oat == nil {
+			continue
+		}
+		band := temperatureBandIndex(feet)
+		poll.sums[band] += *aircraft[idx].Oat
+		poll.reports[band]++
+	}
+	ts.polls.add(now, poll)
+}
+
+// Bands returns the temperature of all bands, lowest first.
+func (ts *TemperatureStats) Bands() []TemperatureBand {
+	var sums [temperatureBandCount]float64
+	var reports [temperatureBandCount]int
+	for poll := range ts.polls.all() {
+		for idx := range temperatureBandCount {
+			sums[idx] += poll.sums[idx]
+			reports[idx] += poll.reports[idx]
+		}
+	}
+
+	bands := make([]TemperatureBand, temperatureBandCount)
+	for idx := range bands {
+		bands[idx] = TemperatureBand{
+			Label:       temperatureBandLabel(idx),
+			Reports:     reports[idx],
+			Temperature: 0,
+			Inversion:   false,
+		}
+		if reports[idx] > 0 {
+			bands[idx].Temperature = sums[idx] / float64(reports[idx])
+		}
+	}
+	// Bands without enough reports are skipped, to compare with the next band below which has.
+	below := -1
+	for idx := range bands {
+		if bands[idx].Reports < inversionReports {
+			continue
+		}
+		if below >= 0 && bands[idx].Temperature > bands[below].Temperature+inversionMargin {
+			bands[idx].Inversion = true
+		}
+		below = idx
+	}
+	return bands
+}
+
+// Inversions returns the bands which are warmer than the one below.
+func (ts *TemperatureStats) Inversions() []TemperatureBand {
+	var inversions []TemperatureBand
+	for _, band := range ts.Bands() {
+		if band.Inversion {
+			inversions = append(inversions, band)
+		}
+	}
+	return inversions
+}
+
+// TemperatureProfile renders the temperature of each band, one line per band with the highest
+// band on top like in the sky, e.g. "5-10k   +3.5°C  (12) inversion". Bands without reports are
+// left out.
+func TemperatureProfile(bands []TemperatureBand) []string {
+	lines := make([]string, 0, len(bands))
+	for idx := len(bands) - 1; idx >= 0; idx-- {
+		band := bands[idx]
+		if band.Reports == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%-6s %+6.1f°C  (%d)", band.Label, band.Temperature, band.Reports)
+		if band.Inversion {
+			line += " inversion"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no reports")
+	}
+	return lines
+}
+
+// WriteCSV writes the temperature of all bands, lowest first, as CSV with a header row.
+func (ts *TemperatureStats) WriteCSV(out io.Writer) error {
+	bands := ts.Bands()
+	records := make([][]string, 0, len(bands))
+	for _, band := range bands {
+		records = append(records, []string{
+			band.Label,
+			strconv.Itoa(band.Reports),
+			strconv.FormatFloat(band.Temperature, 'f', 1, 64),
+			strconv.FormatBool(band.Inversion),
+		})
+	}
+	if err := writeCSV(out, []string{"band", "reports", "oat_c", "inversion"}, records); err != nil {
+		return fmt.Errorf("TemperatureStats.WriteCSV: %w", err)
+	}
+	return nil
+}
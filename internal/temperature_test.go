@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func temperatureRecords(feet float64, temperatures ...float64) []AircraftRecord {
+	records := make([]AircraftRecord, len(temperatures))
+	for idx, temperature := range temperatures {
+		//nolint:exhaustruct // altitude and temperature only
+		records[idx] = AircraftRecord{AltBaro: NewAltitude(feet), Oat: &temperature}
+	}
+	return records
+}
+
+func TestTemperatureStats(t *testing.T) {
+	start := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	stats := NewTemperatureStats()
+	// Expires with the next polls.
+	stats.Record(start, temperatureRecords(2000, 30, 30, 30))
+
+	var aircraft []AircraftRecord
+	aircraft = append(aircraft, temperatureRecords(2000, -2, -4, -3)...)
+	aircraft = append(aircraft, temperatureRecords(7000, 1, 2, 3)...) // warmer above: inversion
+	aircraft = append(aircraft, temperatureRecords(12000, 2)...)      // too few reports to compare
+	aircraft = append(aircraft, temperatureRecords(16000, -8, -9, -10)...)
+	aircraft = append(aircraft, temperatureRecords(42000, -56)...)
+	aircraft = append(aircraft, AircraftRecord{AltBaro: NewAltitude(30000)}) //nolint:exhaustruct // no temperature
+	stats.Record(start.Add(31*time.Minute), aircraft)
+
+	bands := stats.Bands()
+	if len(bands) != temperatureBandCount {
+		t.Fatalf("Bands() returned %d bands, expected %d", len(bands), temperatureBandCount)
+	}
+	expected := []TemperatureBand{
+		{Label: "0-5k", Reports: 3, Temperature: -3, Inversion: false},
+		{Label: "5-10k", Reports: 3, Temperature: 2, Inversion: true},
+		{Label: "10-15k", Reports: 1, Temperature: 2, Inversion: false},
+		{Label: "15-20k", Reports: 3, Temperature: -9, Inversion: false},
+	}
+	for idx, band := range expected {
+		got := bands[idx]
+		if got.Label != band.Label || got.Reports != band.Reports || got.Inversion != band.Inversion ||
+			math.Abs(got.Temperature-band.Temperature) > 0.01 {
+			t.Errorf("Bands()[%d] = %+v, expected %+v", idx, got, band)
+		}
+	}
+	if bands[6].Reports != 0 || bands[8].Label != "40k+" || bands[8].Reports != 1 {
+		t.Errorf("Bands() = %+v, expected no reports at 30k and one above 40k", bands)
+	}
+	if inversions := stats.Inversions(); len(inversions) != 1 || inversions[0].Label != "5-10k" {
+		t.Errorf("Inversions() = %+v, expected 5-10k", inversions)
+	}
+
+	lines := TemperatureProfile(bands)
+	if len(lines) != 5 || lines[0] != "40k+    -56.0°C  (1)" || lines[3] != "5-10k    +2.0°C  (3) inversion" {
+		t.Errorf("TemperatureProfile() = %q", lines)
+	}
+
+	var out bytes.Buffer
+	if err := stats.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	csvLines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(csvLines) != temperatureBandCount+1 || csvLines[2] != "5-10k,3,2.0,true" {
+		t.Errorf("WriteCSV() = %q", out.String())
+	}
+}
+
+func TestTemperatureProfileEmpty(t *testing.T) {
+	lines := TemperatureProfile(NewTemperatureStats().Bands())
+	if len(lines) != 1 || lines[0] != "no reports" {
+		t.Errorf("TemperatureProfile() = %q, expected no reports", lines)
+	}
+}
+
+func TestTemperatureFreezing(t *testing.T) {
+	stats := NewTemperatureStats()
+	aircraft := temperatureRecords(3000, 0, 0)
+	// Reports no temperature, not one of 0°C.
+	aircraft = append(aircraft, AircraftRecord{AltBaro: NewAltitude(3000)}) //nolint:exhaustruct // no temperature
+	stats.Record(time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC), aircraft)
+
+	if band := stats.Bands()[0]; band.Reports != 2 || band.Temperature != 0 {
+		t.Errorf("Bands()[0] = %+v, expected two reports of 0°C", band)
+	}
+}
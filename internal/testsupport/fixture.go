@@ -0,0 +1,60 @@
+package testsupport
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+// fixtures are canned polls recorded from readsb, by name.
+//
+//go:embed testdata/*.json
+var fixtures embed.FS
+
+// Fixture returns the canned poll of the given name, e.g. "hamburg" for testdata/hamburg.json.
+func Fixture(t testing.TB, name string) []byte {
+	t.Helper()
+
+	poll, err := fixtures.ReadFile("testdata/" + name + ".json")
+	if err != nil {
+		t.Fatalf("Fixture: %v", err)
+	}
+	return poll
+}
+
+// Poll encodes the aircraft as a poll of a readsb receiver.
+func Poll(t testing.TB, aircraft ...internal.AircraftRecord) []byte {
+	t.Helper()
+
+	poll, err := json.Marshal(struct {
+		Now      float64                   `json:"now"`
+		Aircraft []internal.AircraftRecord `json:"aircraft"`
+	}{Now: float64(Start.Unix()), Aircraft: aircraft})
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	return poll
+}
+
+// Crowd returns the given number of aircraft of the same type and airline, each of them its own
+// German airframe. Crowds make a baseline which anything else stands out against as rare.
+func Crowd(count int, icaoType string, airline string) []internal.AircraftRecord {
+	crowd := make([]internal.AircraftRecord, count)
+	for idx := range crowd {
+		crowd[idx] = internal.AircraftRecord{ //nolint:exhaustruct // fields of a typical readsb record
+			Hex:          fmt.Sprintf("3c%04x", idx),
+			Flight:       fmt.Sprintf("%s%-5d", airline, idx),
+			Registration: fmt.Sprintf("D-A%03d", idx),
+			IcaoType:     icaoType,
+			Lat:          ObserverLat + float64(idx%50)/100,                  //nolint:mnd // spread over the sky
+			Lon:          ObserverLon + float64(idx/50)/100,                  //nolint:mnd // spread over the sky
+			AltBaro:      internal.NewAltitude(float64(10000 + idx%30*1000)), //nolint:mnd // FL100 to FL390
+			GroundSpeed:  float64(250 + idx%200),                             //nolint:mnd // typical speeds
+			Squawk:       "1000",
+		}
+	}
+	return crowd
+}
@@ -0,0 +1,96 @@
+package testsupport
+
+import (
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+const (
+	// ObserverLat and ObserverLon are where the harness observes from, Hamburg, which the
+	// fixtures are around.
+	ObserverLat = 53.63
+	ObserverLon = 9.99
+	// PollInterval is how far the clock advances with every poll, like the default polling.
+	PollInterval = 10 * time.Second
+)
+
+// Start is when the clock of the harness starts, also the time of the fixtures.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant times
+var Start = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+// Harness drives the whole pipeline of the apps: every poll requests the aircraft from the fake
+// feed as the local source and processes them in the dashboard, at the time of a manual clock.
+type Harness struct {
+	Server    *Server
+	Clock     *internal.ManualClock
+	Request   *internal.Request
+	Dashboard *internal.Dashboard
+	t         testing.TB
+}
+
+// NewHarness sets up a dashboard with the given options and a request of a feed serving the given
+// polls, both on the clock of the harness. The test is run from the root of the
+// repository, where the datasets are found.
+func NewHarness(t testing.TB, opts internal.DashboardOptions, polls ...[]byte) *Harness {
+	t.Helper()
+	t.Chdir(repositoryRoot(t))
+
+	server := NewServer(t, polls...)
+	clock := internal.NewManualClock(Start)
+	var stderr io.Writer = io.Discard
+
+	request, requestErr := internal.NewRequest(internal.RequestOptions{ //nolint:exhaustruct // only the feed
+		Lat:      ObserverLat,
+		Lon:      ObserverLon,
+		Sources:  []string{internal.SourceLocal},
+		LocalURL: server.URL(),
+		Clock:    clock,
+	}, &stderr)
+	if requestErr != nil {
+		t.Fatalf("NewHarness: %v", requestErr)
+	}
+
+	opts.Clock = clock
+	dashboard, dashboardErr := internal.NewDashboard(ObserverLat, ObserverLon, opts, &stderr)
+	if dashboardErr != nil {
+		t.Fatalf("NewHarness: %v", dashboardErr)
+	}
+	t.Cleanup(func() {
+		if err := dashboard.Close(); err != nil {
+			t.Errorf("NewHarness: %v", err)
+		}
+	})
+
+	return &Harness{Server: server, Clock: clock, Request: request, Dashboard: dashboard, t: t}
+}
+
+// Poll advances the clock by the PollInterval, requests the next poll from the feed and processes
+// it, like every tick of the apps. It returns the aircraft as processed by the dashboard.
+func (h *Harness) Poll() []internal.AircraftRecord {
+	h.t.Helper()
+
+	h.Clock.Advance(PollInterval)
+	aircraft := h.Request.RequestAircraft()
+	if _, err := h.Request.LastPoll(); err != nil {
+		h.t.Fatalf("Poll: %v", err)
+	}
+	h.Dashboard.ProcessAircraftRecords(aircraft)
+	return h.Dashboard.CurrentAircraft
+}
+
+// repositoryRoot returns the root of the repository, two levels above this package.
+func repositoryRoot(t testing.TB) string {
+	t.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("repositoryRoot: unable to locate the source of the harness")
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
@@ -0,0 +1,137 @@
+package testsupport
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestServerRepeatsLastPoll(t *testing.T) {
+	first := Poll(t, Crowd(1, "A320", "DLH")...)
+	second := Poll(t, Crowd(2, "A320", "DLH")...)
+	opts := internal.DashboardOptions{RarityScorer: "ratio"} //nolint:exhaustruct // defaults
+	harness := NewHarness(t, opts, first, second)
+
+	for _, expected := range []int{1, 2, 2} {
+		if aircraft := harness.Poll(); len(aircraft) != expected {
+			t.Errorf("Poll() = %d aircraft, expected %d", len(aircraft), expected)
+		}
+	}
+	if harness.Server.Served() != 3 {
+		t.Errorf("Served() = %d, expected 3", harness.Server.Served())
+	}
+}
+
+func TestPipelineStats(t *testing.T) {
+	opts := internal.DashboardOptions{RarityScorer: "ratio"} //nolint:exhaustruct // defaults
+	harness := NewHarness(t, opts, Fixture(t, "hamburg"))
+
+	aircraft := harness.Poll()
+	if len(aircraft) != 5 {
+		t.Fatalf("Poll() = %d aircraft, expected the 5 of the fixture", len(aircraft))
+	}
+	// The broken speed of the An-124 costs only that field, not the whole aircraft.
+	if diag := harness.Request.DecodeDiagnostics().String(); diag == "" {
+		t.Error("DecodeDiagnostics() is empty, expected the broken speed")
+	}
+
+	stats := harness.Dashboard.SessionStats()
+	if stats.Aircraft != 5 || stats.Types != 5 || stats.Operators != 4 || stats.Countries != 4 {
+		t.Errorf("SessionStats() = %d aircraft, %d types, %d operators, %d countries, "+
+			"expected 5, 5, 4 and 4", stats.Aircraft, stats.Types, stats.Operators, stats.Countries)
+	}
+	if stats.Highest == nil || stats.Highest.Registration != "A6-EUA" || stats.Highest.Value != 38000 {
+		t.Errorf("SessionStats().Highest = %+v, expected the A380 at 38000 ft", stats.Highest)
+	}
+	if stats.Fastest == nil || stats.Fastest.Registration != "A6-EUA" {
+		t.Errorf("SessionStats().Fastest = %+v, expected the A380", stats.Fastest)
+	}
+	if stats.End.Sub(stats.Start) != PollInterval {
+		t.Errorf("SessionStats() lasted %v, expected one poll of %v", stats.End.Sub(stats.Start), PollInterval)
+	}
+	if count := harness.Dashboard.SeenTypeCount[harness.Dashboard.IcaoToAircraft["A320"].Make]; count != 1 {
+		t.Errorf("SeenTypeCount of the A320 = %d, expected 1", count)
+	}
+
+	// Aircraft which stay in sight aren't counted again.
+	harness.Poll()
+	if again := harness.Dashboard.SessionStats(); again.Aircraft != 5 || len(harness.Dashboard.NewAircraft) != 0 {
+		t.Errorf("SessionStats() after the second poll = %d aircraft and %d new, expected 5 and none",
+			again.Aircraft, len(harness.Dashboard.NewAircraft))
+	}
+}
+
+func TestPipelineRarity(t *testing.T) {
+	// The ratio scorer needs 500 sightings before anything can be rare.
+	baseline := Crowd(600, "A320", "DLH")
+	rare := Poll(t, slices.Concat(baseline, rareAircraft())...)
+	harness := NewHarness(t, internal.DashboardOptions{RarityScorer: "ratio"}, //nolint:exhaustruct // defaults
+		Poll(t, baseline...), rare)
+
+	harness.Poll()
+	if len(harness.Dashboard.RareSightings) != 0 {
+		t.Errorf("RareSightings = %d during the warmup, expected none", len(harness.Dashboard.RareSightings))
+	}
+	harness.Dashboard.FinishWarmupPeriod()
+
+	harness.Poll()
+	sightings := harness.Dashboard.RareSightings
+	if len(sightings) != 1 {
+		t.Fatalf("RareSightings = %d, expected only the An-124", len(sightings))
+	}
+	for _, flag := range []internal.RarityFlag{internal.RareType, internal.RareOperator, internal.RareCountry} {
+		if sightings[0].Rarities&flag == 0 {
+			t.Errorf("RareSightings[0].Rarities = %b, expected %b to be set", sightings[0].Rarities, flag)
+		}
+	}
+
+	catches := harness.Dashboard.SessionStats().RareCatches
+	if len(catches) != 1 || catches[0].Hex != "508035" || !slices.Contains(catches[0].Rare, "type") {
+		t.Errorf("SessionStats().RareCatches = %+v, expected the An-124 with a rare type", catches)
+	}
+
+	// The An-124 stays in sight, which doesn't make it rare again.
+	harness.Poll()
+	if len(harness.Dashboard.RareSightings) != 0 {
+		t.Errorf("RareSightings = %d for the same flight, expected none", len(harness.Dashboard.RareSightings))
+	}
+}
+
+func TestPipelineFirstSightings(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	opts := internal.DashboardOptions{RarityScorer: "ratio", HistoryPath: historyPath} //nolint:exhaustruct // defaults
+
+	// Without a past session, everything would be a first.
+	first := NewHarness(t, opts, Poll(t, Crowd(3, "A320", "DLH")...))
+	first.Poll()
+	if len(first.Dashboard.FirstSightings) != 0 {
+		t.Errorf("FirstSightings = %d in the first session, expected none", len(first.Dashboard.FirstSightings))
+	}
+	if err := first.Dashboard.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewHarness(t, opts, Poll(t, slices.Concat(Crowd(3, "A320", "DLH"), rareAircraft())...))
+	second.Poll()
+	firsts := second.Dashboard.FirstSightings
+	if len(firsts) != 1 || len(firsts[0].Firsts) != 3 {
+		t.Errorf("FirstSightings = %+v, expected the type, operator and country of the An-124", firsts)
+	}
+}
+
+// rareAircraft is an An-124, which stands out against any crowd of airliners.
+func rareAircraft() []internal.AircraftRecord {
+	return []internal.AircraftRecord{{ //nolint:exhaustruct // fields of a typical readsb record
+		Hex:          "508035",
+		Flight:       "ADB3474 ",
+		Registration: "UR-82007",
+		IcaoType:     "A124",
+		Lat:          53.517,
+		Lon:          10.391,
+		AltBaro:      internal.NewAltitude(31000),
+		GroundSpeed:  440,
+		Squawk:       "5212",
+	}}
+}
@@ -0,0 +1,68 @@
+// Package testsupport drives airspottr end to end in tests. A fake ADS-B feed serves canned polls
+// of aircraft, which a harness requests and processes in the dashboard, just like the apps do.
+package testsupport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// aircraftPath is where the fake feed serves its aircraft, like a local readsb receiver.
+const aircraftPath = "/data/aircraft.json"
+
+// Server is a fake ADS-B feed, which serves its polls in order, one per request. Once all of them
+// have been served, it keeps serving the last one, like a receiver with nothing new in sight.
+type Server struct {
+	server *httptest.Server
+	mutex  sync.Mutex
+	polls  [][]byte
+	served int
+}
+
+// NewServer starts a feed serving the given polls, e.g. from Fixture or Poll, which is closed
+// once the test is done.
+func NewServer(t testing.TB, polls ...[]byte) *Server {
+	t.Helper()
+
+	feed := &Server{server: nil, mutex: sync.Mutex{}, polls: polls, served: 0}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+aircraftPath, feed.serveAircraft)
+	feed.server = httptest.NewServer(mux)
+	t.Cleanup(feed.server.Close)
+	return feed
+}
+
+// URL returns where the feed serves its aircraft, to be requested as the local source.
+func (s *Server) URL() string {
+	return s.server.URL + aircraftPath
+}
+
+// Add queues more polls, which are served after those already queued.
+func (s *Server) Add(polls ...[]byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.polls = append(s.polls, polls...)
+}
+
+// Served returns how many polls have been requested so far.
+func (s *Server) Served() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.served
+}
+
+func (s *Server) serveAircraft(writer http.ResponseWriter, _ *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.polls) == 0 {
+		http.Error(writer, "no polls", http.StatusServiceUnavailable)
+		return
+	}
+	poll := s.polls[min(s.served, len(s.polls)-1)]
+	s.served++
+	writer.Header().Set("Content-Type", "application/json")
+	_, _ = writer.Write(poll)
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log" //nolint:depguard // Don't feel like using slog
+	"strings"
+	"time"
+)
+
+const (
+	// TimeZoneLocal and TimeZoneUTC are the time zones to display times in, besides the names of
+	// the IANA time zone database, e.g. "Europe/Berlin".
+	TimeZoneLocal = "local"
+	TimeZoneUTC   = "utc"
+
+	// DefaultTimeFormat is the layout times are displayed in, unless another one is chosen.
+	DefaultTimeFormat = time.TimeOnly
+)
+
+var errInvalidTimeFormat = errors.New("invalid time format")
+
+// TimeDisplay formats times for display, in the chosen time zone and layout. Times which are
+// persisted, e.g. in the sighting history, are always stored in UTC instead.
+// The zero value displays local times as hours, minutes and seconds.
+type TimeDisplay struct {
+	location *time.Location // location to display times in, local time if nil
+	layout   string         // layout of the time package, DefaultTimeFormat if empty
+}
+
+// NewTimeDisplay creates a display of times in the given time zone, TimeZoneLocal, TimeZoneUTC or
+// an IANA name, and in the given layout of the time package, e.g. "15:04" or time.DateTime.
+func NewTimeDisplay(zone string, layout string) (TimeDisplay, error) {
+	var location *time.Location
+	switch strings.ToLower(zone) {
+	case "", TimeZoneLocal:
+		location = time.Local
+	case TimeZoneUTC:
+		location = time.UTC
+	default:
+		loaded, err := time.LoadLocation(zone)
+		if err != nil {
+			return TimeDisplay{}, fmt.Errorf("NewTimeDisplay: %w", err)
+		}
+		location = loaded
+	}
+
+	// A layout without any element of the reference time prints the same text for any time, so it
+	// is told apart by two times which differ in every element.
+	morning := time.Date(2001, time.February, 3, 4, 5, 6, 7, time.UTC)
+	evening := time.Date(2012, time.November, 14, 15, 16, 17, 18, time.UTC)
+	if layout != "" && morning.Format(layout) == evening.Format(layout) {
+		return TimeDisplay{}, fmt.Errorf("NewTimeDisplay: %w: %q", errInvalidTimeFormat, layout)
+	}
+
+	return TimeDisplay{location: location, layout: layout}, nil
+}
+
+// Format displays the time in the chosen time zone and layout.
+func (d TimeDisplay) Format(t time.Time) string {
+	layout := d.layout
+	if layout == "" {
+		layout = DefaultTimeFormat
+	}
+	return t.In(d.Location()).Format(layout)
+}
+
+// Location returns the time zone times are displayed in.
+func (d TimeDisplay) Location() *time.Location {
+	if d.location == nil {
+		return time.Local
+	}
+	return d.location
+}
+
+// LogFlags adds log.LUTC to the flags of a logger if times are displayed in UTC, so that the log
+// matches the rest of the output. Other time zones than UTC can't be logged in, so those are
+// logged in local time.
+func (d TimeDisplay) LogFlags(flags int) int {
+	if d.location == time.UTC {
+		return flags | log.LUTC
+	}
+	return flags
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"errors"
+	"log" //nolint:depguard // Don't feel like using slog
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeDisplayFormat(t *testing.T) {
+	moment := time.Date(2025, time.June, 1, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		zone     string
+		layout   string
+		expected string
+	}{
+		{name: "utc", zone: "UTC", layout: "", expected: "22:30:00"},
+		{name: "named zone", zone: "Europe/Berlin", layout: "", expected: "00:30:00"},
+		{name: "layout", zone: TimeZoneUTC, layout: time.DateTime, expected: "2025-06-01 22:30:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display, err := NewTimeDisplay(tt.zone, tt.layout)
+			if err != nil {
+				t.Fatalf("NewTimeDisplay() error = %v", err)
+			}
+			if formatted := display.Format(moment); formatted != tt.expected {
+				t.Errorf("Format() = %q, expected %q", formatted, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewTimeDisplayInvalid(t *testing.T) {
+	if _, err := NewTimeDisplay("Mars/Olympus_Mons", ""); err == nil {
+		t.Error("NewTimeDisplay() accepted unknown time zone")
+	}
+	if _, err := NewTimeDisplay(TimeZoneLocal, "hh:mm"); !errors.Is(err, errInvalidTimeFormat) {
+		t.Errorf("NewTimeDisplay() error = %v, expected %v", err, errInvalidTimeFormat)
+	}
+}
+
+func TestTimeDisplayLogFlags(t *testing.T) {
+	utc, _ := NewTimeDisplay(TimeZoneUTC, "")
+	if flags := utc.LogFlags(log.LstdFlags); flags != log.LstdFlags|log.LUTC {
+		t.Errorf("LogFlags() = %b, expected UTC", flags)
+	}
+	if flags := (TimeDisplay{}).LogFlags(log.LstdFlags); flags != log.LstdFlags {
+		t.Errorf("LogFlags() = %b, expected local time", flags)
+	}
+}
+
+func TestHistoryStoresUTC(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	history := NewHistory(path)
+	entry := HistoryEntry{
+		Time:         time.Date(2025, time.June, 2, 0, 30, 0, 0, berlin),
+		Hex:          "3c6444",
+		Flight:       "DLH400",
+		Registration: "D-ABYA",
+		Type:         "BOEING 747-8",
+		Operator:     "Lufthansa",
+		Country:      "GERMANY",
+	}
+	if err := history.Append([]HistoryEntry{entry}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"time":"2025-06-01T22:30:00Z"`) {
+		t.Errorf("Append() wrote %s, expected the time in UTC", content)
+	}
+}
@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// trafficRetention is how long hourly traffic buckets are kept.
+	trafficRetention = 30 * 24 * time.Hour
+
+	hoursPerDay = 24
+	daysPerWeek = 7
+
+	// Extent of the traffic charts and how many of the busiest hours are listed.
+	trafficChartHours   = 24
+	trafficChartDays    = 14
+	trafficBusiestHours = 3
+)
+
+// sparkBars are the characters of a sparkline, from lowest to highest value.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// TrafficBucket holds the aircraft counts of all polls within one hour or day.
+type TrafficBucket struct {
+	Start         time.Time // Start of the hour or day.
+	Polls         int       // Polls is how many polls fall into the bucket.
+	TotalAircraft int       // TotalAircraft is the sum of the aircraft counts of all polls.
+	MaxAircraft   int       // MaxAircraft is the highest aircraft count of a single poll.
+}
+
+// Average returns the average number of aircraft per poll, zero if there was no poll.
+func (b TrafficBucket) Average() float64 {
+	if b.Polls == 0 {
+		return 0
+	}
+	return float64(b.TotalAircraft) / float64(b.Polls)
+}
+
+func (b *TrafficBucket) add(other TrafficBucket) {
+	b.Polls += other.Polls
+	b.TotalAircraft += other.TotalAircraft
+	b.MaxAircraft = max(b.MaxAircraft, other.MaxAircraft)
+}
+
+// TrafficStats records how many aircraft are around on every poll, bucketed by hour, to show how
+// the traffic volume changes over time and when the airspace is busiest.
+type TrafficStats struct {
+	hourly map[time.Time]*TrafficBucket
+	day    SpottingDay // day tells when the days of the daily buckets start.
+}
+
+// NewTrafficStats creates empty traffic statistics, with daily buckets of the given spotting days.
+func NewTrafficStats(day SpottingDay) *TrafficStats {
+	return &TrafficStats{hourly: make(map[time.Time]*TrafficBucket), day: day}
+}
+
+// Record adds the aircraft count of a poll at the given time and forgets about buckets which are
+// older than the retention period.
+func (ts *TrafficStats) Record(now time.Time, aircraftCount int) {
+	start := startOfHour(now)
+	bucket, ok := ts.hourly[start]
+	if !ok {
+		bucket = &TrafficBucket{Start: start, Polls: 0, TotalAircraft: 0, MaxAircraft: 0}
+		ts.hourly[start] = bucket
+	}
+	bucket.add(TrafficBucket{
+		Start:         start,
+		Polls:         1,
+		TotalAircraft: aircraftCount,
+		MaxAircraft:   aircraftCount,
+	})
+
+	for hour := range ts.hourly {
+		if now.Sub(hour) > trafficRetention {
+			delete(ts.hourly, hour)
+		}
+	}
+}
+
+// Hourly returns the buckets of the last given number of hours up to now, oldest first.
+// Hours without polls are included as empty buckets, so that gaps show up in charts.
+func (ts *TrafficStats) Hourly(now time.Time, hours int) []TrafficBucket {
+	buckets := make([]TrafficBucket, hours)
+	for idx := range hours {
+		start := startOfHour(now.Add(-time.Duration(hours-1-idx) * time.Hour))
+		buckets[idx] = TrafficBucket{Start: start, Polls: 0, TotalAircraft: 0, MaxAircraft: 0}
+		if bucket, ok := ts.hourly[start]; ok {
+			buckets[idx] = *bucket
+		}
+	}
+	return buckets
+}
+
+// startOfHour returns when the hour of the given time started, in the location of the time. Unlike
+// Truncate, which counts the hours in UTC, it keeps to the local hours of time zones which are offset
+// by a fraction of an hour.
+func startOfHour(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+}
+
+// Daily returns the buckets of the last given number of spotting days up to now, oldest first.
+func (ts *TrafficStats) Daily(now time.Time, days int) []TrafficBucket {
+	buckets := make([]TrafficBucket, days)
+	dayToIdx := make(map[time.Time]int, days)
+	today := ts.day.Start(now)
+	for idx := range days {
+		start := today.AddDate(0, 0, idx-days+1)
+		buckets[idx] = TrafficBucket{Start: start, Polls: 0, TotalAircraft: 0, MaxAircraft: 0}
+		dayToIdx[start] = idx
+	}
+
+	for hour, bucket := range ts.hourly {
+		if idx, ok := dayToIdx[ts.day.Start(hour)]; ok {
+			buckets[idx].add(*bucket)
+		}
+	}
+	return buckets
+}
+
+// ByHourOfDay returns the average number of aircraft per poll for every hour of the day, in
+// local time, over the entire retention period.
+func (ts *TrafficStats) ByHourOfDay() [hoursPerDay]float64 {
+	var buckets [hoursPerDay]TrafficBucket
+	for hour, bucket := range ts.hourly {
+		buckets[hour.Hour()].add(*bucket)
+	}
+
+	var averages [hoursPerDay]float64
+	for idx := range buckets {
+		averages[idx] = buckets[idx].Average()
+	}
+	return averages
+}
+
+// BusiestHours returns the given number of hours of the day with the most traffic on average,
+// busiest first. Hours without any polls are left out.
+func (ts *TrafficStats) BusiestHours(count int) []int {
+	averages := ts.ByHourOfDay()
+	hours := make([]int, 0, hoursPerDay)
+	for hour, average := range averages {
+		if average > 0 {
+			hours = append(hours, hour)
+		}
+	}
+	sort.SliceStable(hours, func(i, j int) bool { return averages[hours[i]] > averages[hours[j]] })
+	return hours[:min(count, len(hours))]
+}
+
+// WriteCSV writes all hourly buckets, oldest first, as CSV with a header row.
+func (ts *TrafficStats) WriteCSV(out io.Writer) error {
+	starts := make([]time.Time, 0, len(ts.hourly))
+	for start := range ts.hourly {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	records := make([][]string, 0, len(starts))
+	for _, start := range starts {
+		bucket := ts.hourly[start]
+		records = append(records, []string{
+			start.UTC().Format(time.RFC3339),
+			strconv.Itoa(bucket.Polls),
+			strconv.FormatFloat(bucket.Average(), 'f', 1, 64),
+			strconv.Itoa(bucket.MaxAircraft),
+		})
+	}
+	if err := writeCSV(out, []string{"hour", "polls", "avg_aircraft", "max_aircraft"}, records); err != nil {
+		return fmt.Errorf("TrafficStats.WriteCSV: %w", err)
+	}
+	return nil
+}
+
+// Sparkline renders the average aircraft count of each bucket as a bar, scaled to the busiest
+// bucket. Buckets without polls are shown as blanks.
+func Sparkline(buckets []TrafficBucket) string {
+	values := make([]float64, len(buckets))
+	for idx, bucket := range buckets {
+		values[idx] = bucket.Average()
+		if bucket.Polls == 0 {
+			values[idx] = -1
+		}
+	}
+	return sparkline(values)
+}
+
+// sparkline renders every value as a bar, scaled to the highest value.
+// Negative values are shown as blanks.
+func sparkline(values []float64) string {
+	highest := 0.0
+	for _, value := range values {
+		highest = max(highest, value)
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		if value < 0 {
+			line.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if highest > 0 {
+			level = int(value / highest * float64(len(sparkBars)-1))
+		}
+		line.WriteRune(sparkBars[level])
+	}
+	return line.String()
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrafficStatsBuckets(t *testing.T) {
+	now := time.Date(2025, time.June, 2, 18, 30, 0, 0, time.UTC)
+	traffic := NewTrafficStats(SpottingDay{})
+	traffic.Record(now.Add(-25*time.Hour), 4)
+	traffic.Record(now.Add(-2*time.Hour), 10)
+	traffic.Record(now.Add(-2*time.Hour+time.Minute), 20)
+	traffic.Record(now, 5)
+
+	hourly := traffic.Hourly(now, 3)
+	if len(hourly) != 3 {
+		t.Fatalf("Hourly() returned %d buckets, expected 3", len(hourly))
+	}
+	if hourly[0].Polls != 2 || hourly[0].Average() != 15 || hourly[0].MaxAircraft != 20 {
+		t.Errorf("Hourly()[0] = %+v, expected 2 polls averaging 15 with max 20", hourly[0])
+	}
+	if hourly[1].Polls != 0 {
+		t.Errorf("Hourly()[1] = %+v, expected an empty bucket", hourly[1])
+	}
+	if hourly[2].Polls != 1 || hourly[2].Average() != 5 {
+		t.Errorf("Hourly()[2] = %+v, expected 1 poll of 5", hourly[2])
+	}
+
+	daily := traffic.Daily(now, 2)
+	if daily[0].Polls != 1 || daily[1].Polls != 3 {
+		t.Errorf("Daily() polls = %d, %d, expected 1, 3", daily[0].Polls, daily[1].Polls)
+	}
+
+	busiest := traffic.BusiestHours(2)
+	if len(busiest) != 2 || busiest[0] != 16 || busiest[1] != 18 {
+		t.Errorf("BusiestHours() = %v, expected [16 18]", busiest)
+	}
+}
+
+func TestTrafficStatsDailyRollover(t *testing.T) {
+	day, err := NewSpottingDay(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.June, 3, 2, 30, 0, 0, time.UTC)
+	traffic := NewTrafficStats(day)
+	traffic.Record(time.Date(2025, time.June, 2, 2, 0, 0, 0, time.UTC), 1)
+	traffic.Record(time.Date(2025, time.June, 2, 22, 0, 0, 0, time.UTC), 2)
+	traffic.Record(now, 3)
+
+	daily := traffic.Daily(now, 2)
+	if daily[0].Polls != 1 || daily[1].Polls != 2 {
+		t.Errorf("Daily() polls = %d, %d, expected 1, 2", daily[0].Polls, daily[1].Polls)
+	}
+}
+
+func TestTrafficStatsLocalHours(t *testing.T) {
+	india := time.FixedZone("IST", 5*60*60+30*60)
+	now := time.Date(2025, time.June, 2, 18, 10, 0, 0, india)
+	traffic := NewTrafficStats(SpottingDay{})
+	traffic.Record(now.Add(-15*time.Minute), 4) // 17:55 local, but 12:25 UTC like now
+	traffic.Record(now, 6)
+
+	hourly := traffic.Hourly(now, 2)
+	if !hourly[1].Start.Equal(time.Date(2025, time.June, 2, 18, 0, 0, 0, india)) {
+		t.Errorf("Hourly()[1] starts at %v, expected 18:00 local time", hourly[1].Start)
+	}
+	if hourly[0].Polls != 1 || hourly[1].Polls != 1 {
+		t.Errorf("Hourly() polls = %d, %d, expected 1, 1", hourly[0].Polls, hourly[1].Polls)
+	}
+}
+
+func TestTrafficStatsRetention(t *testing.T) {
+	now := time.Date(2025, time.June, 2, 18, 30, 0, 0, time.UTC)
+	traffic := NewTrafficStats(SpottingDay{})
+	traffic.Record(now.Add(-trafficRetention-time.Hour), 7)
+	traffic.Record(now, 3)
+
+	if len(traffic.hourly) != 1 {
+		t.Errorf("Record() kept %d buckets, expected 1", len(traffic.hourly))
+	}
+}
+
+func TestTrafficStatsWriteCSV(t *testing.T) {
+	now := time.Date(2025, time.June, 2, 18, 30, 0, 0, time.UTC)
+	traffic := NewTrafficStats(SpottingDay{})
+	traffic.Record(now, 3)
+	traffic.Record(now.Add(-time.Hour), 4)
+
+	var out bytes.Buffer
+	if err := traffic.WriteCSV(&out); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"hour,polls,avg_aircraft,max_aircraft",
+		"2025-06-02T17:00:00Z,1,4.0,4",
+		"2025-06-02T18:00:00Z,1,3.0,3",
+		"",
+	}, "\n")
+	if out.String() != expected {
+		t.Errorf("WriteCSV() wrote\n%s\nexpected\n%s", out.String(), expected)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	buckets := []TrafficBucket{
+		{Start: time.Time{}, Polls: 1, TotalAircraft: 0, MaxAircraft: 0},
+		{Start: time.Time{}, Polls: 0, TotalAircraft: 0, MaxAircraft: 0},
+		{Start: time.Time{}, Polls: 2, TotalAircraft: 10, MaxAircraft: 6},
+		{Start: time.Time{}, Polls: 1, TotalAircraft: 10, MaxAircraft: 10},
+	}
+
+	if line := Sparkline(buckets); line != "▁ ▄█" {
+		t.Errorf("Sparkline() = %q, expected %q", line, "▁ ▄█")
+	}
+}
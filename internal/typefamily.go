@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// Granularities at which the rarity of types is told.
+const (
+	TypeRarityType   = "type"   // TypeRarityType tells the rarity of every type on its own.
+	TypeRarityFamily = "family" // TypeRarityFamily tells the rarity of type families, e.g. the A320 family.
+)
+
+var errInvalidTypeRarity = errors.New("invalid type rarity granularity")
+
+// ValidateTypeRarity checks that the granularity is one of the TypeRarityXxx ones, or empty for
+// TypeRarityType.
+func ValidateTypeRarity(granularity string) error {
+	switch granularity {
+	case "", TypeRarityType, TypeRarityFamily:
+		return nil
+	}
+	return fmt.Errorf("ValidateTypeRarity: %w: %q", errInvalidTypeRarity, granularity)
+}
+
+// familiesByType maps the types, as described by their make and model, to their family. The
+// families are listed by ICAO type designator in the dataset.
+func familiesByType(icaoToAircraft map[string]dash.IcaoAircraft, typeFamilies map[string]string) map[string]string {
+	families := make(map[string]string, len(typeFamilies))
+	for icaoType, family := range typeFamilies {
+		if aircraft, ok := icaoToAircraft[icaoType]; ok && aircraft.Make != "" {
+			families[aircraft.Make] = family
+		}
+	}
+	return families
+}
+
+// TypeFamily returns the family of the type, or the type itself if it is in none.
+func (db *Dashboard) TypeFamily(aircraftType string) string {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return db.typeFamily(aircraftType)
+}
+
+// typeFamily is TypeFamily, which requires the dataset mutex.
+func (db *Dashboard) typeFamily(aircraftType string) string {
+	if family, ok := db.typeFamilies[aircraftType]; ok {
+		return family
+	}
+	return aircraftType
+}
+
+// FamilyTypeCount rolls up the seen-counts of the types to their families.
+func (db *Dashboard) FamilyTypeCount() map[string]int {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return rollUpCounts(db.SeenTypeCount, db.typeFamily)
+}
+
+// FamilyFirstSeen rolls up when the types were first seen to their families.
+func (db *Dashboard) FamilyFirstSeen(firstSeen map[string]time.Time) map[string]time.Time {
+	db.datasetMutex.Lock()
+	defer db.datasetMutex.Unlock()
+	return rollUpFirstSeen(firstSeen, db.typeFamily)
+}
+
+// rarityRollUp returns how the properties of the category are rolled up before telling their
+// rarity, nil if they are told on their own.
+func (db *Dashboard) rarityRollUp(category string) func(string) string {
+	if category == "type" && db.typeRarity == TypeRarityFamily {
+		return db.typeFamily
+	}
+	return nil
+}
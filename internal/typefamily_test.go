@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+func TestValidateTypeRarity(t *testing.T) {
+	for _, granularity := range []string{"", TypeRarityType, TypeRarityFamily} {
+		if err := ValidateTypeRarity(granularity); err != nil {
+			t.Errorf("ValidateTypeRarity(%q) = %v, expected no error", granularity, err)
+		}
+	}
+	if err := ValidateTypeRarity("model"); err == nil {
+		t.Error("ValidateTypeRarity(model) accepted an unknown granularity")
+	}
+}
+
+func TestFamilyTypeCount(t *testing.T) {
+	icaoToAircraft := map[string]dash.IcaoAircraft{
+		"A320": {Make: "AIRBUS A-320"},    //nolint:exhaustruct // make only
+		"A20N": {Make: "AIRBUS A-320neo"}, //nolint:exhaustruct // make only
+		"B738": {Make: "BOEING 737-800"},  //nolint:exhaustruct // make only
+	}
+	dashboard := &Dashboard{ //nolint:exhaustruct // type counts only
+		SeenTypeCount: map[string]int{"AIRBUS A-320": 3, "AIRBUS A-320neo": 2, "BOEING 737-800": 4},
+		typeFamilies: familiesByType(icaoToAircraft, map[string]string{
+			"A320": "Airbus A320 family",
+			"A20N": "Airbus A320 family",
+		}),
+		typeRarity: TypeRarityType,
+	}
+
+	counts := dashboard.FamilyTypeCount()
+	if len(counts) != 2 || counts["Airbus A320 family"] != 5 || counts["BOEING 737-800"] != 4 {
+		t.Errorf("FamilyTypeCount() = %v, expected the A320 family 5 and the 737-800 4", counts)
+	}
+	if dashboard.rarityRollUp("type") != nil {
+		t.Error("rarityRollUp(type) rolls up types, expected them on their own")
+	}
+
+	dashboard.typeRarity = TypeRarityFamily
+	rollUp := dashboard.rarityRollUp("type")
+	if rollUp == nil || rollUp("AIRBUS A-320neo") != "Airbus A320 family" {
+		t.Error("rarityRollUp(type) doesn't roll up types to their family")
+	}
+	if dashboard.rarityRollUp("operator") != nil {
+		t.Error("rarityRollUp(operator) rolls up operators, expected them on their own")
+	}
+}
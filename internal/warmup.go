@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWarmupTypes and DefaultWarmupOperators are how many distinct types and operators
+	// make a baseline which rarity can be told against.
+	DefaultWarmupTypes     = 20
+	DefaultWarmupOperators = 10
+)
+
+// WarmupStatus tells whether rarity is still being learned, and how far the baseline has come.
+// Rare sightings aren't reported during the warmup, since everything is rare at first.
+type WarmupStatus struct {
+	Active       bool
+	Types        int // Types is how many distinct types are in the baseline.
+	Operators    int // Operators is how many distinct operators are in the baseline.
+	MinTypes     int
+	MinOperators int
+}
+
+// String describes the status, e.g. "warming up, 12/20 types, 4/10 operators".
+func (s WarmupStatus) String() string {
+	if !s.Active {
+		return fmt.Sprintf("ready, %d types, %d operators", s.Types, s.Operators)
+	}
+	return fmt.Sprintf("warming up, %d/%d types, %d/%d operators",
+		s.Types, s.MinTypes, s.Operators, s.MinOperators)
+}
+
+// historyStore is a shared store of our own past sightings, so that the sighting history can be
+// merged into the rarity baseline like the sightings of other observers.
+type historyStore struct {
+	entries []HistoryEntry
+}
+
+func (s historyStore) Push(string, []HistoryEntry) error { return nil }
+
+// Pull returns all entries on the first pull and none later.
+func (s historyStore) Pull(_ string, after int64) ([]HistoryEntry, int64, error) {
+	if after >= int64(len(s.entries)) {
+		return nil, after, nil
+	}
+	return s.entries[after:], int64(len(s.entries)), nil
+}
+
+// loadBaseline counts the sightings of past sessions towards rarity.
+func (db *Dashboard) loadBaseline(entries []HistoryEntry, halfLife time.Duration, now time.Time) error {
+	db.baseline = NewSharedStats(historyStore{entries: entries}, "", halfLife, &db.errOut)
+	if err := db.baseline.pull(now); err != nil {
+		return fmt.Errorf("loadBaseline: %w", err)
+	}
+	return nil
+}
+
+// Warmup returns the status of the warmup.
+func (db *Dashboard) Warmup() WarmupStatus {
+	db.warmupMutex.Lock()
+	defer db.warmupMutex.Unlock()
+	return db.warmupStatus()
+}
+
+// FinishWarmupPeriod ends the warmup, whether the baseline is complete or not, e.g. because the
+// warmup has taken long enough at a quiet location.
+func (db *Dashboard) FinishWarmupPeriod() {
+	db.warmupMutex.Lock()
+	defer db.warmupMutex.Unlock()
+	db.isWarmup = false
+}
+
+// isWarmingUp tells whether rare sightings are still held back.
+func (db *Dashboard) isWarmingUp() bool {
+	db.warmupMutex.Lock()
+	defer db.warmupMutex.Unlock()
+	return db.isWarmup
+}
+
+// checkWarmup ends the warmup once the baseline has enough distinct types and operators.
+// Without minimums, only FinishWarmupPeriod ends it.
+func (db *Dashboard) checkWarmup() {
+	db.warmupMutex.Lock()
+	defer db.warmupMutex.Unlock()
+	if !db.isWarmup || db.warmupTypes+db.warmupOperators == 0 {
+		return
+	}
+	status := db.warmupStatus()
+	if status.Types >= status.MinTypes && status.Operators >= status.MinOperators {
+		db.isWarmup = false
+		db.errOut.Printf("Warmup finished, baseline of %d types and %d operators", status.Types, status.Operators)
+	}
+}
+
+func (db *Dashboard) warmupStatus() WarmupStatus {
+	return WarmupStatus{
+		Active:       db.isWarmup,
+		Types:        db.distinct("type", db.SeenTypeCount),
+		Operators:    db.distinct("operator", db.SeenOperatorCount),
+		MinTypes:     db.warmupTypes,
+		MinOperators: db.warmupOperators,
+	}
+}
+
+// distinct counts the known properties of the category seen in this session or in the baseline.
+func (db *Dashboard) distinct(category string, seen map[string]int) int {
+	if db.baseline == nil {
+		return len(seen)
+	}
+	count := len(seen)
+	for _, property := range db.baseline.properties(category) {
+		if _, ok := seen[property]; !ok && property != "" && !strings.EqualFold(property, typeUnknown) {
+			count++
+		}
+	}
+	return count
+}
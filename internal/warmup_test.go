@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarmupHoldsBackRareSightings(t *testing.T) {
+	t.Chdir("..") // the datasets are found relative to the root of the repository
+
+	var stderr io.Writer = io.Discard
+	//nolint:exhaustruct // defaults
+	dashboard, err := NewDashboard(0, 0, DashboardOptions{
+		RarityScorer:   "ratio",
+		FleetRareBelow: 50,
+		WarmupTypes:    2,
+	}, &stderr)
+	if err != nil {
+		t.Fatalf("NewDashboard() error = %v", err)
+	}
+	dashboard.FleetSizes = map[string]int{"A124": 20}
+
+	polls := [][]AircraftRecord{
+		{{Hex: "508035", Flight: "ADB3042", IcaoType: "A124"}}, //nolint:exhaustruct // type only
+		{{Hex: "4ca7b5", Flight: "RYR1AB", IcaoType: "B738"}},  //nolint:exhaustruct // type only
+		{{Hex: "508036", Flight: "ADB3043", IcaoType: "A124"}}, //nolint:exhaustruct // type only
+	}
+	expected := []struct {
+		rare   int
+		active bool
+	}{
+		{rare: 0, active: true},
+		{rare: 0, active: false},
+		{rare: 1, active: false},
+	}
+	for idx, poll := range polls {
+		dashboard.ProcessAircraftRecords(poll)
+		if len(dashboard.RareSightings) != expected[idx].rare {
+			t.Errorf("poll %d: RareSightings = %+v, expected %d", idx, dashboard.RareSightings, expected[idx].rare)
+		}
+		if status := dashboard.Warmup(); status.Active != expected[idx].active {
+			t.Errorf("poll %d: Warmup() = %s, expected active %v", idx, status, expected[idx].active)
+		}
+	}
+}
+
+func TestWarmupBaselineFromHistory(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	seen := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	if err := NewHistory(historyPath).Append([]HistoryEntry{
+		sharedEntry(seen, "AIRBUS, A-320", "Lufthansa", "Germany"),
+		sharedEntry(seen, "BOEING, 737-800", "Ryanair", "Ireland"),
+		sharedEntry(seen, typeUnknown, operatorUnknown, countryUnknown),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir("..")
+
+	for _, fromHistory := range []bool{false, true} {
+		var stderr io.Writer = io.Discard
+		//nolint:exhaustruct // defaults
+		dashboard, err := NewDashboard(0, 0, DashboardOptions{
+			RarityScorer:        "ratio",
+			HistoryPath:         historyPath,
+			WarmupTypes:         2,
+			WarmupOperators:     2,
+			BaselineFromHistory: fromHistory,
+		}, &stderr)
+		if err != nil {
+			t.Fatalf("NewDashboard() error = %v", err)
+		}
+
+		if status := dashboard.Warmup(); status.Active == fromHistory {
+			t.Errorf("baseline from history %v: Warmup() = %s", fromHistory, status)
+		}
+	}
+}
+
+func TestWarmupStatusString(t *testing.T) {
+	status := WarmupStatus{Active: true, Types: 12, Operators: 4, MinTypes: 20, MinOperators: 10}
+	if text := status.String(); text != "warming up, 12/20 types, 4/10 operators" {
+		t.Errorf("String() = %q", text)
+	}
+	status.Active = false
+	if text := status.String(); text != "ready, 12 types, 4 operators" {
+		t.Errorf("String() = %q", text)
+	}
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+)
+
+var errInvalidWebhookURL = errors.New("invalid webhook URL")
+
+// WebhookSink posts events as JSON to a webhook.
+// Posting happens in the background, so that slow webhooks don't hold up the processing of
+// aircraft. Errors are therefore not returned but written to the error log.
+type WebhookSink struct {
+	url    string
+	errOut *log.Logger
+}
+
+// NewWebhookSink creates a sink posting to the given URL.
+func NewWebhookSink(webhookURL string, errOut *log.Logger) (*WebhookSink, error) {
+	parsed, parseErr := url.Parse(webhookURL)
+	if parseErr != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return nil, fmt.Errorf("NewWebhookSink: %w: %s", errInvalidWebhookURL, webhookURL)
+	}
+	return &WebhookSink{url: parsed.String(), errOut: errOut}, nil
+}
+
+func (s *WebhookSink) Emit(event Event) error {
+	payload := newEventPayload(event)
+	go func() {
+		if err := sendWebhook(s.url, payload); err != nil {
+			s.errOut.Println(fmt.Errorf("WebhookSink.Emit: %w", err))
+		}
+	}()
+	return nil
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func sendWebhook(webhookURL string, payload EventPayload) error {
+	body, jsonErr := json.Marshal(payload)
+	if jsonErr != nil {
+		return fmt.Errorf("sendWebhook: failed to encode payload: %w", jsonErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if reqErr != nil {
+		return fmt.Errorf("sendWebhook: invalid request: %w", reqErr)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, respErr := http.DefaultClient.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("sendWebhook: failed to send POST request: %w", respErr)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sendWebhook: %w %s", ErrNonOkResponse, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// windWindow is how long the wind reports of aircraft are taken into account, since the wind
+// changes over the day.
+const windWindow = 30 * time.Minute
+
+// downwindArrows point where the wind blows to, by compass sector starting at north.
+//
+//nolint:gochecknoglobals // constant, but Go can't have constant slices
+var downwindArrows = []string{"↑", "↗", "→", "↘", "↓", "↙", "←", "↖"}
+
+// WindBand is the wind within a range of altitudes, averaged over the reports of all aircraft.
+type WindBand struct {
+	Label     string  // Label names the range, e.g. "10-20k".
+	Reports   int     // Reports is how many aircraft reports the wind is averaged over.
+	Direction float64 // Direction the wind comes from in [degrees], 0-359.
+	Speed     float64 // Speed of the wind in [knots].
+}
+
+// windSum adds up the wind reports of a band as vectors, pointing where the wind comes from.
+type windSum struct {
+	east    float64
+	north   float64
+	reports int
+}
+
+// WindStats averages the wind direction and speed reported by airborne aircraft by altitude band,
+// to build a local winds-aloft profile of the last half hour.
+// Aircraft which don't report any wind, or no altitude, aren't counted.
+type WindStats struct {
+	polls windowedSampler[[altitudeBandCount]windSum] // polls are the wind reports by altitude band.
+}
+
+// NewWindStats creates empty wind statistics.
+func NewWindStats() *WindStats {
+	return &WindStats{polls: newWindowedSampler[[altitudeBandCount]windSum](windWindow)}
+}
+
+// Record adds the wind reported by the airborne aircraft of a poll and forgets polls which have
+// fallen out of the window.
+func (ws *WindStats) Record(now time.Time, aircraft []AircraftRecord) {
+	var poll [altitudeBandCount]windSum
+	for idx := range aircraft {
+		feet, ok := aircraft[idx].AltBaro.Feet()
+		if !ok || aircraft[idx].WindSpeed <= 0 {
+			continue
+		}
+		radians := aircraft[idx].WindDirection * math.Pi / 180 //nolint:mnd // degrees to radians
+		sum := &poll[altitudeBandIndex(feet)]
+		sum.east += aircraft[idx].WindSpeed * math.Sin(radians)
+		sum.north += aircraft[idx].WindSpeed * math.Cos(radians)
+		sum.reports++
+	}
+	ws.polls.add(now, poll)
+}
+
+// Bands returns the wind of all bands, lowest first.
+func (ws *WindStats) Bands() []WindBand {
+	var sums [altitudeBandCount]windSum
+	for poll := range ws.polls.all() {
+		for idx, sum := range poll {
+			sums[idx].east += sum.east
+			sums[idx].north += sum.north
+			sums[idx].reports += sum.reports
+		}
+	}
+
+	bands := make([]WindBand, altitudeBandCount)
+	for idx, sum := range sums {
+		bands[idx] = WindBand{Label: altitudeBandLabel(idx), Reports: sum.reports, Direction: 0, Speed: 0}
+		if sum.reports == 0 {
+			continue
+		}
+		east, north := sum.east/float64(sum.reports), sum.north/float64(sum.reports)
+		bands[idx].Speed = math.Hypot(east, north)
+		degrees := math.Atan2(east, north) * 180 / math.Pi //nolint:mnd // radians to degrees
+		bands[idx].Direction = math.Mod(degrees+360, 360)  //nolint:mnd // full circle
+	}
+	return bands
+}
+
+// WindProfile renders the wind of each band, one line per band with the highest band on top like
+// in the sky, e.g. "30k+   270° → 85 kt  (12)".
+func WindProfile(bands []WindBand) []string {
+	lines := make([]string, 0, len(bands))
+	for idx := len(bands) - 1; idx >= 0; idx-- {
+		band := bands[idx]
+		if band.Reports == 0 {
+			lines = append(lines, fmt.Sprintf("%-6s    no reports", band.Label))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%-6s %03.0f° %s %3.0f kt  (%d)",
+			band.Label,
+			band.Direction,
+			downwindArrow(band.Direction),
+			band.Speed,
+			band.Reports))
+	}
+	return lines
+}
+
+// downwindArrow points where wind from the given direction in [degrees] blows to.
+func downwindArrow(direction float64) string {
+	sector := 360.0 / float64(len(downwindArrows))    //nolint:mnd // full circle
+	downwind := math.Mod(direction+180+sector/2, 360) //nolint:mnd // opposite direction
+	return downwindArrows[int(downwind/sector)%len(downwindArrows)]
+}
+
+// WriteCSV writes the wind of all bands, lowest first, as CSV with a header row.
+func (ws *WindStats) WriteCSV(out io.Writer) error {
+	bands := ws.Bands()
+	records := make([][]string, 0, len(bands))
+	for _, band := range bands {
+		records = append(records, []string{
+			band.Label,
+			strconv.Itoa(band.Reports),
+			strconv.FormatFloat(band.Direction, 'f', 0, 64),
+			strconv.FormatFloat(band.Speed, 'f', 1, 64),
+		})
+	}
+	if err := writeCSV(out, []string{"band", "reports", "direction_deg", "speed_kt"}, records); err != nil {
+		return fmt.Errorf("WindStats.WriteCSV: %w", err)
+	}
+	return nil
+}
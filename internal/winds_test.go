@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func windRecord(feet float64, direction, speed float64) AircraftRecord {
+	//nolint:exhaustruct // altitude and wind only
+	return AircraftRecord{AltBaro: NewAltitude(feet), WindDirection: direction, WindSpeed: speed}
+}
+
+func TestWindStats(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	stats := NewWindStats()
+	// Expires with the next polls.
+	stats.Record(start, []AircraftRecord{windRecord(5000, 90, 50)})
+	stats.Record(start.Add(20*time.Minute), []AircraftRecord{
+		windRecord(5000, 350, 20),
+		windRecord(6000, 10, 20),
+		windRecord(35000, 270, 80),
+		windRecord(36000, 0, 0),                   // no wind reported
+		{AltBaro: GroundAltitude(), WindSpeed: 5}, //nolint:exhaustruct // on the ground
+	})
+	stats.Record(start.Add(40*time.Minute), []AircraftRecord{windRecord(38000, 270, 100)})
+
+	bands := stats.Bands()
+	if len(bands) != altitudeBandCount {
+		t.Fatalf("Bands() returned %d bands, expected %d", len(bands), altitudeBandCount)
+	}
+	low := bands[0]
+	if low.Reports != 2 || math.Abs(low.Direction) > 0.01 && math.Abs(low.Direction-360) > 0.01 ||
+		math.Abs(low.Speed-20*math.Cos(10*math.Pi/180)) > 0.01 {
+		t.Errorf("Bands()[0] = %+v, expected wind from the north averaged as vectors", low)
+	}
+	if high := bands[3]; high.Reports != 2 || math.Abs(high.Direction-270) > 0.01 ||
+		math.Abs(high.Speed-90) > 0.01 {
+		t.Errorf("Bands()[3] = %+v, expected 90 kt from 270°", high)
+	}
+	if bands[1].Reports != 0 || bands[2].Reports != 0 {
+		t.Errorf("Bands() = %+v, expected no reports between 10k and 30k", bands)
+	}
+
+	lines := WindProfile(bands)
+	if lines[0] != "30k+   270° →  90 kt  (2)" || !strings.HasSuffix(lines[1], "no reports") {
+		t.Errorf("WindProfile() = %q", lines)
+	}
+
+	var out bytes.Buffer
+	if err := stats.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	csvLines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(csvLines) != 5 || csvLines[4] != "30k+,2,270,90.0" {
+		t.Errorf("WriteCSV() = %q", out.String())
+	}
+}
+
+func TestDownwindArrow(t *testing.T) {
+	tests := []struct {
+		direction float64
+		expected  string
+	}{
+		{0, "↓"},
+		{359, "↓"},
+		{90, "←"},
+		{225, "↗"},
+		{270, "→"},
+	}
+	for _, test := range tests {
+		if arrow := downwindArrow(test.direction); arrow != test.expected {
+			t.Errorf("downwindArrow(%v) = %q, expected %q", test.direction, arrow, test.expected)
+		}
+	}
+}
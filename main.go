@@ -2,8 +2,17 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
 	"github.com/micutio/airspottr/internal"
-	"github.com/micutio/airspottr/tickerapp"
 	"github.com/micutio/airspottr/tuiapp"
 	"github.com/spf13/pflag"
 )
@@ -11,63 +20,1033 @@ import (
 const (
 	// thisAppName is the name of this application as shown on notifications.
 	thisAppName = "airspottr"
+	// updateDataCommand downloads and installs updated datasets.
+	updateDataCommand = "update-data"
+	// notificationsCommand prints the last notifications, e.g. to review those which were missed.
+	notificationsCommand      = "notifications"
+	defaultNotificationsShown = 20
+	dataUpdateTimeout         = 2 * time.Minute
 )
 
+// predefinedLocations can be chosen with --location besides the locations of the config file.
+//
+//nolint:gochecknoglobals // constant lookup
+var predefinedLocations = map[string][]float64{
+	"hamburg":   {53.5511, 9.9937},
+	"new-york":  {40.7128, -74.0060},
+	"singapore": {1.3521, 103.8198},
+}
+
 func main() {
-	predefinedLocations := map[string][]float64{
-		"hamburg":   {53.5511, 9.9937},
-		"new-york":  {40.7128, -74.0060},
-		"singapore": {1.3521, 103.8198},
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		printUsage(os.Stdout)
+		os.Exit(0)
+	}
+	if len(args) > 0 && args[0] == completeCommand {
+		runComplete(args[1:])
+		os.Exit(0)
 	}
+	cmd, cmdArgs := findCommand(args)
+	runCommand(cmd, cmdArgs)
+}
+
+// spotArgs are the command line options of the commands which spot planes.
+type spotArgs struct {
+	latLon                []float64
+	bbox                  []float64
+	follow                string
+	location              string
+	rarityScorer          string
+	statsHalfLife         time.Duration
+	configPath            string
+	historyPath           string
+	notesPath             string
+	notificationLogPath   string
+	isQuiet               bool
+	isVerbose             bool
+	healthAddr            string
+	healthToken           string
+	acarsAddr             string
+	trafficCSVPath        string
+	altitudeCSVPath       string
+	windCSVPath           string
+	temperatureCSVPath    string
+	statsFile             string
+	snapshotFormat        string
+	isSnapshotClipboard   bool
+	sources               []string
+	localURL              string
+	adscURL               string
+	isTISBExcluded        bool
+	clientCert            string
+	clientKey             string
+	caCert                string
+	teeOutput             string
+	stallAfter            time.Duration
+	pollInterval          time.Duration
+	summaryInterval       time.Duration
+	warmup                time.Duration
+	warmupTypes           int
+	warmupOperators       int
+	isBaselineFromHistory bool
+	isAdaptivePolling     bool
+	fastPollInterval      time.Duration
+	slowPollInterval      time.Duration
+	nearbyRadius          float64
+	nightStartHour        int
+	nightEndHour          int
+	compareScorer         string
+	dataDir               string
+	dayStartHour          int
+	timeZone              string
+	timeFormat            string
+	syncTarget            string
+	syncToken             string
+	observer              string
+	peakPath              string
+	recordsPath           string
+	dailyPath             string
+	isPeakAlert           bool
+	isGoAroundAlert       bool
+	machAlert             float64
+	circlingAlert         float64
+	maxSightings          int
+	maxCounterKeys        int
+	maxMemory             int
+	fleetRareBelow        int
+	typeRarity            string
+	images                string
+	theme                 string
+	alertTier             string
+}
 
-	var argIsUseTicker bool
-	var argLatLon []float64
-	var argLocation string
+// options turns the parsed command line options into the options of the app, after loading the
+// config file and setting the options which weren't given from it. It exits if they are invalid.
+func (args *spotArgs) options(flags *pflag.FlagSet) internal.AppOptions {
+	config := loadConfig(flags, args.configPath)
 
-	setupCommandLineFlags(&argIsUseTicker, &argLatLon, &argLocation)
+	if args.isQuiet && args.isVerbose {
+		fmt.Fprintln(os.Stderr, "--quiet and --verbose are mutually exclusive")
+		os.Exit(1)
+	}
 
-	// Parse all arguments provided to the program on launch.
-	pflag.Parse()
+	timeDisplay, timeErr := internal.NewTimeDisplay(args.timeZone, args.timeFormat)
+	if timeErr != nil {
+		fmt.Fprintf(os.Stderr, "invalid time display: %v\n", timeErr)
+		os.Exit(1)
+	}
+	log.SetFlags(timeDisplay.LogFlags(log.LstdFlags))
 
-	if val, ok := predefinedLocations[argLocation]; ok {
-		argLatLon = val
+	verbosity := internal.VerbosityNormal
+	if args.isQuiet {
+		verbosity = internal.VerbosityQuiet
+	} else if args.isVerbose {
+		verbosity = internal.VerbosityVerbose
 	}
 
-	options := internal.RequestOptions{
-		Lat: argLatLon[0],
-		Lon: argLatLon[1],
+	if val, ok := config.Locations[args.location]; ok {
+		args.latLon = val
+	} else if val, ok := predefinedLocations[args.location]; ok {
+		args.latLon = val
 	}
 
-	if argIsUseTicker {
-		tickerapp.Run(thisAppName, options)
-	} else {
-		tuiapp.Run(thisAppName, options)
+	regionConfig := config.Region
+	if flags.Changed("bbox") {
+		regionConfig = internal.RegionConfig{BBox: args.bbox, Polygon: nil}
+	}
+	region, regionErr := internal.NewRegion(regionConfig)
+	if regionErr != nil {
+		fmt.Fprintf(os.Stderr, "invalid region: %v\n", regionErr)
+		os.Exit(1)
+	}
+	// Without a location, the region is spotted from its middle.
+	if region != nil && !flags.Changed("latlon") && args.location == "" {
+		lat, lon := region.Center()
+		args.latLon = []float64{lat, lon}
 	}
-}
 
-func setupCommandLineFlags(argIsUseTicker *bool, argLatLon *[]float64, argLocation *string) {
-	// Whether to launch the Ticker or TUI app.
-	pflag.BoolVarP(
-		argIsUseTicker,
-		"ticker",
-		"t",
-		false,
-		"print plane spotting information on the command line without TUI")
-	pflag.Lookup("ticker").NoOptDefVal = "true"
+	var follow *internal.FollowTarget
+	if args.follow != "" {
+		target, followErr := internal.ParseFollowTarget(args.follow)
+		if followErr != nil {
+			fmt.Fprintf(os.Stderr, "invalid --follow: %v\n", followErr)
+			os.Exit(1)
+		}
+		follow = &target
+	}
 
+	tiers := config.Tiers
+	if len(tiers) == 0 {
+		tiers = internal.DefaultProximityTiers()
+	}
+	alertDistance := 0.0
+	if args.alertTier != "" {
+		distance, tierErr := tiers.MaxDistance(args.alertTier)
+		if tierErr != nil {
+			fmt.Fprintf(os.Stderr, "invalid alert tier: %v\n", tierErr)
+			os.Exit(1)
+		}
+		alertDistance = distance
+	}
+
+	// The feed status of the request is compared with the time of the sightings.
+	clock := internal.SystemClock{}
+	options := internal.AppOptions{
+		Request: internal.RequestOptions{
+			Lat:            args.latLon[0],
+			Lon:            args.latLon[1],
+			Sources:        args.sources,
+			APIKeys:        internal.ResolveAPIKeys(config.APIKeys),
+			LocalURL:       args.localURL,
+			ADSCURL:        args.adscURL,
+			ClientCertFile: args.clientCert,
+			ClientKeyFile:  args.clientKey,
+			CACertFile:     args.caCert,
+			PhotoCacheDir:  internal.DefaultPhotoCacheDir(),
+			Region:         region,
+			Follow:         follow,
+			ExcludeTISB:    args.isTISBExcluded,
+			Clock:          clock,
+		},
+		Dashboard: internal.DashboardOptions{
+			RarityScorer:        args.rarityScorer,
+			StatsHalfLife:       args.statsHalfLife,
+			Rules:               config.Rules,
+			Areas:               config.Areas,
+			HistoryPath:         args.historyPath,
+			NotesPath:           args.notesPath,
+			PeakPath:            args.peakPath,
+			RecordsPath:         args.recordsPath,
+			DailyPath:           args.dailyPath,
+			FleetRareBelow:      args.fleetRareBelow,
+			MachAlert:           args.machAlert,
+			CirclingAlert:       args.circlingAlert,
+			Limits:              args.limits(),
+			TypeRarity:          args.typeRarity,
+			CompareScorer:       args.compareScorer,
+			Clock:               clock,
+			LoadProgress:        internal.PrintLoadProgress(os.Stderr),
+			DataDir:             args.dataDir,
+			DayStartHour:        args.dayStartHour,
+			SyncTarget:          args.syncTarget,
+			SyncToken:           args.syncToken,
+			Observer:            args.observer,
+			WarmupTypes:         args.warmupTypes,
+			WarmupOperators:     args.warmupOperators,
+			BaselineFromHistory: args.isBaselineFromHistory,
+			Tiers:               tiers,
+			OperatorGroups:      config.OperatorGroups,
+			Follow:              follow,
+		},
+		Notify: internal.NotifyOptions{
+			Summary:             config.Summary,
+			Verbosity:           verbosity,
+			Sinks:               config.Sinks,
+			TeePath:             args.teeOutput,
+			StallAfter:          args.stallAfter,
+			TimeDisplay:         timeDisplay,
+			Language:            config.Language,
+			PeakAlert:           args.isPeakAlert,
+			GoAroundAlert:       args.isGoAroundAlert,
+			RarityAlertDistance: alertDistance,
+			Notifications:       config.Notifications,
+			NotificationLogPath: args.notificationLogPath,
+			Routing:             config.Routing,
+		},
+		Health: internal.HealthOptions{
+			Addr:  args.healthAddr,
+			Token: args.healthToken,
+		},
+		Acars: internal.AcarsOptions{
+			Addr: args.acarsAddr,
+		},
+		Export: internal.ExportOptions{
+			TrafficCSVPath:     args.trafficCSVPath,
+			AltitudeCSVPath:    args.altitudeCSVPath,
+			WindCSVPath:        args.windCSVPath,
+			TemperatureCSVPath: args.temperatureCSVPath,
+			StatsPath:          args.statsFile,
+			SnapshotFormat:     args.snapshotFormat,
+			SnapshotClipboard:  args.isSnapshotClipboard,
+		},
+		Polling: internal.PollingOptions{
+			Interval:        args.pollInterval,
+			SummaryInterval: args.summaryInterval,
+			Warmup:          args.warmup,
+			Adaptive:        args.isAdaptivePolling,
+			FastInterval:    args.fastPollInterval,
+			SlowInterval:    args.slowPollInterval,
+			NearbyRadius:    args.nearbyRadius,
+			NightStartHour:  args.nightStartHour,
+			NightEndHour:    args.nightEndHour,
+		},
+		Layout:     config.Layout,
+		Images:     args.images,
+		Theme:      args.theme,
+		ConfigPath: args.configPath,
+	}
+	if err := options.Polling.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return options
+}
+
+// register adds the command line options shared by all commands which spot planes.
+func (args *spotArgs) register(flags *pflag.FlagSet) {
 	// Location to plane spot, provided as lat,lon coordinates
-	pflag.Float64SliceVarP(
-		argLatLon,
+	flags.Float64SliceVarP(
+		&args.latLon,
 		"latlon",
 		"l",
 		[]float64{0, 0},
 		"define the location where to spot planes")
 
-	pflag.StringVarP(
-		argLocation,
+	// Where aircraft data comes from, several sources are fused into one picture.
+	flags.StringSliceVar(
+		&args.sources,
+		"sources",
+		[]string{internal.SourceAdsbFi},
+		"data sources to request aircraft from, in order of preference, any of: "+
+			strings.Join(internal.SourceNames(), ", "),
+	)
+
+	flags.StringVar(
+		&args.localURL,
+		"local-url",
+		"",
+		"URL of the aircraft.json of a local receiver like dump1090, used by the local source",
+	)
+
+	// Oceanic traffic beyond the range of terrestrial receivers.
+	flags.StringVar(
+		&args.adscURL,
+		"adsc-url",
+		"",
+		"URL of readsb-style JSON of ADS-C or satellite positions, used by the adsc source",
+	)
+
+	// Rebroadcast ground radar positions are coarse and late.
+	flags.BoolVar(
+		&args.isTISBExcluded,
+		"exclude-tisb",
+		false,
+		"leave out aircraft whose position was received by TIS-B",
+	)
+
+	// Private feeders may require mutual TLS.
+	flags.StringVar(
+		&args.clientCert,
+		"client-cert",
+		"",
+		"PEM file of the client certificate presented to the local source for mutual TLS",
+	)
+
+	flags.StringVar(
+		&args.clientKey,
+		"client-key",
+		"",
+		"PEM file of the key of the client certificate",
+	)
+
+	flags.StringVar(
+		&args.caCert,
+		"ca-cert",
+		"",
+		"PEM file of the CA which signed the certificate of the local source, if it is private",
+	)
+
+	flags.StringVarP(
+		&args.location,
 		"location",
 		"L",
 		"",
-		"define a predefined location, e.g. hamburg, new-york, singapore",
+		"define a predefined location, e.g. hamburg, new-york, singapore, or one of the "+
+			"locations of the config file",
+	)
+
+	// A flight corridor or a whole country rather than the circle around the location.
+	flags.Float64SliceVar(
+		&args.bbox,
+		"bbox",
+		nil,
+		"south,west,north,east of a bounding box to spot planes in instead of the circle around the location",
+	)
+
+	// A single flight from takeoff to landing, rather than everything around the location.
+	flags.StringVar(
+		&args.follow,
+		"follow",
+		"",
+		"callsign or hex of an aircraft to follow along its flight instead of spotting all aircraft around the "+
+			"location, e.g. DLH4AB or 3c6586, or callsign:CFE123 for a callsign which looks like a hex",
+	)
+
+	// Strategy to decide whether a type, operator or country is rare.
+	flags.StringVar(
+		&args.rarityScorer,
+		"rarity-scorer",
+		internal.LogScorerName,
+		"rarity scoring strategy, one of: "+strings.Join(internal.RarityScorerNames(), ", "),
+	)
+
+	// Types which are rare worldwide, like the An-124, are worth a notification every time.
+	flags.IntVar(
+		&args.fleetRareBelow,
+		"fleet-rare-below",
+		0,
+		"always consider types with fewer aircraft in service worldwide rare, 0 disables it",
+	)
+
+	// Families like the A320 family may be more telling than their individual variants.
+	flags.StringVar(
+		&args.typeRarity,
+		"type-rarity",
+		internal.TypeRarityType,
+		"tell the rarity of every aircraft type on its own (type) or of their families (family)",
+	)
+
+	// Tune the rarity settings by comparing two scorers on the same sightings.
+	flags.StringVar(
+		&args.compareScorer,
+		"compare-scorer",
+		"",
+		"rarity scorer to evaluate alongside --rarity-scorer, to compare how many notifications "+
+			"each produces",
 	)
+
+	// Let the statistics used for rarity forget about old sightings.
+	flags.DurationVar(
+		&args.statsHalfLife,
+		"stats-half-life",
+		0,
+		"half life of seen-counts used for rarity, e.g. 2160h for roughly 3 months, 0 disables decay",
+	)
+
+	// Keep late-night sessions within one day of the daily statistics and reports.
+	flags.IntVar(
+		&args.dayStartHour,
+		"day-start-hour",
+		0,
+		"hour of the day (0-23, local time) at which a new spotting day starts, e.g. 3",
+	)
+
+	// Show times in another time zone or layout, e.g. UTC to match the times of flight plans.
+	flags.StringVar(
+		&args.timeZone,
+		"time-zone",
+		internal.TimeZoneLocal,
+		"time zone to show times in: local, utc or a name like Europe/Berlin",
+	)
+
+	flags.StringVar(
+		&args.timeFormat,
+		"time-format",
+		internal.DefaultTimeFormat,
+		"layout to show times in, as in Go's time package, e.g. 15:04 or \"2006-01-02 15:04:05\"",
+	)
+
+	// Optional config file, e.g. for custom alert rules.
+	flags.StringVarP(
+		&args.configPath,
+		"config",
+		"c",
+		internal.DefaultConfigPath,
+		"path to the JSON config file",
+	)
+
+	// Updated datasets, see the update-data command.
+	flags.StringVar(
+		&args.dataDir,
+		"data-dir",
+		defaultDataDir(),
+		"directory of the datasets installed by update-data, empty uses only the bundled ones",
+	)
+
+	// Long-term record of all sightings, e.g. for seasonal patterns in the weekly report.
+	flags.StringVar(
+		&args.historyPath,
+		"history",
+		internal.DefaultDataPath(internal.HistoryFileName),
+		"path to the sighting history file, or sqlite:PATH, bolt:PATH or a postgres:// URL of a "+
+			"database, empty disables the history",
+	)
+
+	// Notes on aircraft and the watchlist, as edited in the TUI.
+	flags.StringVar(
+		&args.notesPath,
+		"notes",
+		internal.DefaultDataPath(internal.NotesFileName),
+		"path to the file of notes on aircraft, empty keeps notes for this session only",
+	)
+
+	// Every notification, to review the missed ones in the TUI or with the notifications command.
+	flags.StringVar(
+		&args.notificationLogPath,
+		"notification-log",
+		"",
+		"path to a file of all notifications, empty keeps them in the store of --history, or for this session only",
+	)
+
+	// The busiest moment ever, as shown in the header.
+	flags.StringVar(
+		&args.peakPath,
+		"peak-file",
+		internal.DefaultDataPath(internal.PeakFileName),
+		"path to the file of the all-time peak of aircraft visible at once, empty keeps it for this session only",
+	)
+
+	// The highest and fastest aircraft of every day and ever, as shown in the header.
+	flags.StringVar(
+		&args.recordsPath,
+		"records-file",
+		internal.DefaultDataPath(internal.RecordsFileName),
+		"path to the file of the daily and all-time highest and fastest aircraft, empty keeps them for this session only",
+	)
+
+	// How many distinct aircraft were seen every day, as a long-term record.
+	flags.StringVar(
+		&args.dailyPath,
+		"daily-file",
+		internal.DefaultDataPath(internal.DailyFileName),
+		"path to the CSV file the count of distinct aircraft of every day is written to, empty disables it",
+	)
+
+	// Only alert on rare sightings close by, e.g. within the "nearby" tier of the config.
+	flags.StringVar(
+		&args.alertTier,
+		"alert-tier",
+		"",
+		"farthest proximity tier in which rare sightings are alerted on all sinks, farther ones are only logged, "+
+			"empty alerts at any distance",
+	)
+
+	flags.BoolVar(
+		&args.isPeakAlert,
+		"peak-alert",
+		false,
+		"notify on all event sinks when more aircraft are visible at once than ever before")
+
+	// Go-arounds are only logged by default, since they happen every day at busy airports.
+	flags.BoolVar(
+		&args.isGoAroundAlert,
+		"go-around-alert",
+		false,
+		"notify on all event sinks when an aircraft goes around at a nearby airport")
+
+	// Supersonic-capable aircraft actually flying supersonic, or close to it.
+	flags.Float64Var(
+		&args.machAlert,
+		"mach-alert",
+		internal.DefaultMachAlert,
+		"notify on all event sinks when a supersonic-capable aircraft exceeds this Mach number, 0 disables it")
+
+	// Police helicopters, survey aircraft and holding patterns.
+	flags.Float64Var(
+		&args.circlingAlert,
+		"circling-alert",
+		internal.DefaultCirclingAlert,
+		"notify on all event sinks when an aircraft flies circles or a holding pattern within this distance in km, "+
+			"0 disables it")
+
+	// Runs of several weeks on small devices mustn't grow without bound.
+	flags.IntVar(
+		&args.maxSightings,
+		"max-sightings",
+		0,
+		"how many aircraft to remember, forgetting the least recently seen beyond, 0 for no limit")
+
+	flags.IntVar(
+		&args.maxCounterKeys,
+		"max-counter-keys",
+		0,
+		"how many types, operators and countries to count each, adding up the least seen as other beyond, "+
+			"0 for no limit")
+
+	flags.IntVar(
+		&args.maxMemory,
+		"max-memory",
+		0,
+		"heap size in MB beyond which half of the remembered aircraft and counts are let go, 0 for no limit")
+
+	// Machine-readable log of the session, also while watching the TUI.
+	flags.StringVar(
+		&args.teeOutput,
+		"tee-output",
+		"",
+		"file or FIFO to log every update and event to as NDJSON, empty disables it")
+
+	// Escalate when the feed dies instead of showing stale aircraft.
+	flags.DurationVar(
+		&args.stallAfter,
+		"stall-after",
+		internal.DefaultStallAfter,
+		"how long all polls may fail before the feed counts as stalled and is reported, 0 disables it",
+	)
+
+	// Poll more or less often, e.g. to go easy on a rate limited source.
+	flags.DurationVar(
+		&args.pollInterval,
+		"poll-interval",
+		internal.AircraftUpdateInterval,
+		"time between aircraft polls",
+	)
+
+	flags.DurationVar(
+		&args.summaryInterval,
+		"summary-interval",
+		internal.SummaryInterval,
+		"time between summaries of the ticker",
+	)
+
+	flags.DurationVar(
+		&args.warmup,
+		"warmup",
+		internal.DashboardWarmup,
+		"how long to learn what is common at most before reporting rarity",
+	)
+
+	// End the warmup as soon as there's enough to tell rarity against.
+	flags.IntVar(
+		&args.warmupTypes,
+		"warmup-types",
+		internal.DefaultWarmupTypes,
+		"how many distinct types end the warmup, together with --warmup-operators",
+	)
+
+	flags.IntVar(
+		&args.warmupOperators,
+		"warmup-operators",
+		internal.DefaultWarmupOperators,
+		"how many distinct operators end the warmup, together with --warmup-types",
+	)
+
+	flags.BoolVar(
+		&args.isBaselineFromHistory,
+		"baseline-from-history",
+		false,
+		"count the sightings of the history towards rarity, skipping the warmup if they suffice",
+	)
+
+	// Don't miss a rare visitor, and don't poll an empty sky all night.
+	flags.BoolVar(
+		&args.isAdaptivePolling,
+		"adaptive-polling",
+		false,
+		"poll faster while rare or watched aircraft are nearby and slower during the night",
+	)
+
+	flags.DurationVar(
+		&args.fastPollInterval,
+		"fast-poll-interval",
+		internal.DefaultFastPollInterval,
+		"time between polls while rare or watched aircraft are nearby, with --adaptive-polling",
+	)
+
+	flags.DurationVar(
+		&args.slowPollInterval,
+		"slow-poll-interval",
+		internal.DefaultSlowPollInterval,
+		"time between polls during the night, with --adaptive-polling",
+	)
+
+	flags.Float64Var(
+		&args.nearbyRadius,
+		"nearby-radius",
+		internal.DefaultNearbyRadius,
+		"distance in km within which rare or watched aircraft speed up polling",
+	)
+
+	flags.IntVar(
+		&args.nightStartHour,
+		"night-start-hour",
+		internal.DefaultNightStartHour,
+		"hour of the day (0-23, local time) at which the night of slow polling starts",
+	)
+
+	flags.IntVar(
+		&args.nightEndHour,
+		"night-end-hour",
+		internal.DefaultNightEndHour,
+		"hour of the day (0-23, local time) at which the night of slow polling ends",
+	)
+
+	// Supervision by container orchestration and uptime monitors.
+	flags.StringVar(
+		&args.healthAddr,
+		"health-addr",
+		"",
+		"address to serve the "+internal.HealthPath+" and "+internal.SourcesPath+
+			" endpoints on, e.g. :8080, empty disables them")
+	flags.StringVar(
+		&args.healthToken,
+		"health-token",
+		"",
+		"bearer token required to switch sources or sync through the endpoints, "+
+			"without it only requests from localhost may")
+
+	// Context like gate times from the ACARS messages of the aircraft, decoded by acarsdec.
+	flags.StringVar(
+		&args.acarsAddr,
+		"acars-listen",
+		"",
+		"UDP address to receive the JSON output of acarsdec or vdlm2dec on, e.g. :5555, "+
+			"empty disables it")
+
+	// Traffic volume per hour, e.g. for "when is my airspace busiest" analysis in a spreadsheet.
+	flags.StringVar(
+		&args.trafficCSVPath,
+		"traffic-csv",
+		"",
+		"path to export the hourly traffic volume to as CSV, empty disables the export")
+
+	// Traffic mix by altitude band, e.g. to tell approaches from overflights.
+	flags.StringVar(
+		&args.altitudeCSVPath,
+		"altitude-csv",
+		"",
+		"path to export the altitude band distribution to as CSV, empty disables the export")
+
+	// Winds aloft as reported by aircraft, e.g. for gliding or ballooning.
+	flags.StringVar(
+		&args.windCSVPath,
+		"wind-csv",
+		"",
+		"path to export the winds-aloft profile to as CSV, empty disables the export")
+
+	// Temperature by altitude as reported by aircraft, including inversions.
+	flags.StringVar(
+		&args.temperatureCSVPath,
+		"temperature-csv",
+		"",
+		"path to export the temperature profile to as CSV, empty disables the export")
+
+	// Sum up the session on quitting, which the TUI otherwise forgets about.
+	flags.StringVar(
+		&args.statsFile,
+		"stats-file",
+		"",
+		"path to write the session statistics to on quitting, as CSV if it ends in .csv, JSON otherwise")
+
+	// Rarity against the sightings of several instances, e.g. at home and at the office.
+	flags.StringVar(
+		&args.syncTarget,
+		"sync",
+		"",
+		"SQLite database on a shared path, URL of another instance's "+internal.SyncPath+
+			" endpoint or postgres:// URL to share sightings through, empty disables sharing")
+	flags.StringVar(
+		&args.syncToken,
+		"sync-token",
+		"",
+		"bearer token to send to the "+internal.SyncPath+" endpoint of another instance, "+
+			"its --health-token")
+
+	flags.StringVar(
+		&args.observer,
+		"observer",
+		defaultObserver(),
+		"name of this instance in the shared sightings, must be unique among the observers")
+}
+
+// registerTUI adds the command line options of the TUI.
+func (args *spotArgs) registerTUI(flags *pflag.FlagSet) {
+	// Colours of the TUI, e.g. for terminals which can't show many.
+	flags.StringVar(
+		&args.theme,
+		"theme",
+		tuiapp.ThemeDefault,
+		"colours of the TUI, one of: "+strings.Join(tuiapp.ThemeNames(), ", "),
+	)
+
+	// Photos in the details view of the TUI, for terminals which can show images.
+	flags.StringVar(
+		&args.images,
+		"images",
+		tuiapp.ImagesAuto,
+		"how the TUI shows photos of aircraft: auto, kitty, iterm or off for ASCII silhouettes",
+	)
+
+	// Snapshots of the TUI, written with a key, e.g. to share a catch.
+	flags.StringVar(
+		&args.snapshotFormat,
+		"snapshot-format",
+		tuiapp.SnapshotText,
+		"format of the snapshots written by pressing x in the TUI: text, csv or json")
+
+	flags.BoolVar(
+		&args.isSnapshotClipboard,
+		"snapshot-clipboard",
+		false,
+		"also copy a summary of each snapshot to the clipboard of the terminal")
+}
+
+// limits returns the caps of what the dashboard keeps in memory, or exits if they are negative.
+// The garbage collector is told about the memory cap, so that it collects harder before it.
+func (args *spotArgs) limits() internal.Limits {
+	if args.maxSightings < 0 || args.maxCounterKeys < 0 || args.maxMemory < 0 {
+		fmt.Fprintln(os.Stderr, "--max-sightings, --max-counter-keys and --max-memory must not be negative")
+		os.Exit(1)
+	}
+	if args.maxMemory > 0 {
+		debug.SetMemoryLimit(int64(args.maxMemory) << 20) //nolint:mnd // MB
+	}
+	return internal.Limits{
+		MaxSightings:   args.maxSightings,
+		MaxCounterKeys: args.maxCounterKeys,
+		MaxMemory:      uint64(args.maxMemory) << 20, //nolint:mnd // MB
+	}
+}
+
+// registerTicker adds the command line options of the ticker.
+func (args *spotArgs) registerTicker(flags *pflag.FlagSet) {
+	// How much the ticker prints about individual aircraft.
+	flags.BoolVarP(
+		&args.isQuiet,
+		"quiet",
+		"q",
+		false,
+		"ticker only prints rarity events and summaries")
+
+	flags.BoolVarP(
+		&args.isVerbose,
+		"verbose",
+		"v",
+		false,
+		"ticker prints every aircraft on every update instead of how they differ from the previous one")
+}
+
+// runHealthcheck queries the health endpoint of a running instance and exits with 0 if it is
+// healthy and 1 otherwise, e.g. for container health checks.
+func runHealthcheck(healthAddr string) {
+	if healthAddr == "" {
+		fmt.Fprintln(os.Stderr, "healthcheck requires --health-addr")
+		os.Exit(1)
+	}
+
+	if err := internal.CheckHealth(healthAddr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runImportHistory backfills the sighting history from the globe_history directory of readsb and
+// prints how many flights were added.
+func runImportHistory(dir string, options internal.AppOptions) {
+	opts := options.Dashboard
+	if opts.HistoryPath == "" {
+		fmt.Fprintln(os.Stderr, "import-history requires --history")
+		os.Exit(1)
+	}
+	// The imported flights are only added to our own history, not shared.
+	opts.SyncTarget = ""
+	opts.CompareScorer = ""
+
+	stderr := io.Writer(os.Stderr)
+	dashboard, dashboardErr := internal.NewDashboard(options.Request.Lat, options.Request.Lon, opts, &stderr)
+	if dashboardErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to create dashboard: %v\n", dashboardErr)
+		os.Exit(1)
+	}
+	summary, importErr := dashboard.ImportHistory(dir)
+	closeErr := dashboard.Close()
+	if err := errors.Join(importErr, closeErr); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to import history: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d flights from %d files of %s, skipped %d already in the history\n",
+		summary.Flights, summary.Files, dir, summary.Skipped)
+	if summary.Failed > 0 {
+		fmt.Printf("Failed to read %d files\n", summary.Failed)
+	}
+}
+
+// runCompareHistory replays the sighting history through the active and the compared rarity
+// scorer and prints how many notifications each would have produced.
+func runCompareHistory(historyPath string, observer string, scorerName string, compareName string) {
+	scorer, scorerErr := internal.NewRarityScorer(scorerName)
+	compareScorer, compareErr := internal.NewRarityScorer(compareName)
+	if scorerErr != nil || compareErr != nil {
+		fmt.Fprintln(os.Stderr, errors.Join(scorerErr, compareErr))
+		os.Exit(1)
+	}
+
+	entries, historyErr := loadHistory(historyPath, observer)
+	if historyErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load sighting history: %v\n", historyErr)
+		os.Exit(1)
+	}
+
+	comparison := internal.ReplayScorerComparison(entries, scorer, compareScorer)
+	fmt.Printf("Replayed %d sightings of %s\n", comparison.Sightings, historyPath)
+	fmt.Println(comparison.A)
+	fmt.Println(comparison.B)
+	fmt.Printf("Notified by both: %d\n", comparison.Both)
+}
+
+// loadHistory returns all sightings of the observer from the sighting store at the given location.
+func loadHistory(location string, observer string) ([]internal.HistoryEntry, error) {
+	store, openErr := internal.OpenSightingStore(location, observer)
+	if openErr != nil {
+		return nil, fmt.Errorf("loadHistory: %w", openErr)
+	}
+	entries, loadErr := store.Load()
+	return entries, errors.Join(loadErr, store.Close())
+}
+
+// runBacktest replays the sighting history against a rule condition or a watchlist and prints the
+// sightings they would have fired for.
+func runBacktest(
+	historyPath string,
+	observer string,
+	condition string,
+	watch []string,
+	period time.Duration,
+	dayStartHour int,
+	timeDisplay internal.TimeDisplay,
+) {
+	spottingDay, dayErr := internal.NewSpottingDay(dayStartHour)
+	if dayErr != nil {
+		fmt.Fprintln(os.Stderr, dayErr)
+		os.Exit(1)
+	}
+
+	entries, historyErr := loadHistory(historyPath, observer)
+	if historyErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load sighting history: %v\n", historyErr)
+		os.Exit(1)
+	}
+
+	since := time.Time{}
+	if period > 0 {
+		since = time.Now().Add(-period)
+	}
+
+	var results []internal.BacktestResult
+	if condition != "" {
+		result, ruleErr := internal.BacktestRule(entries, condition, since)
+		if ruleErr != nil {
+			fmt.Fprintf(os.Stderr, "invalid rule: %v\n", ruleErr)
+			os.Exit(1)
+		}
+		results = append(results, result)
+	}
+	if len(watch) > 0 {
+		results = append(results, internal.BacktestWatchlist(entries, watch, since))
+	}
+
+	for _, result := range results {
+		fmt.Printf("Backtest of %s over %d sightings of %s\n", result.Name, result.Sightings, historyPath)
+		for _, entry := range result.Matches {
+			fmt.Printf("  %s  %-8s %-8s %s, %s, %s\n",
+				entry.Time.In(timeDisplay.Location()).Format(time.DateTime),
+				entry.Flight, entry.Registration, entry.Type, entry.Operator, entry.Country)
+		}
+		fmt.Printf("Would have fired %d times on %d days\n", len(result.Matches), result.Days(spottingDay))
+	}
+}
+
+// runUpdateData downloads the datasets from the "data_urls" of the config and installs them as a
+// new version of the data directory, or rolls back to the version before.
+func runUpdateData(configPath string, dataDir string, isRollback bool) {
+	if dataDir == "" {
+		fmt.Fprintln(os.Stderr, "update-data requires --data-dir")
+		os.Exit(1)
+	}
+	store := internal.NewDataStore(dataDir)
+
+	if isRollback {
+		version, err := store.Rollback()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to roll back: %v\n", err)
+			os.Exit(1)
+		}
+		if version == "" {
+			version = "the bundled datasets"
+		}
+		fmt.Printf("Rolled back to %s\n", version)
+		return
+	}
+
+	config, configErr := internal.LoadConfig(configPath)
+	if configErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", configErr)
+		os.Exit(1)
+	}
+
+	client := &http.Client{Timeout: dataUpdateTimeout} //nolint:exhaustruct // timeout only
+	version, updates, err := internal.UpdateData(store, config.DataURLs, client, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to update datasets, keeping the ones in use: %v\n", err)
+		os.Exit(1)
+	}
+	for _, update := range updates {
+		fmt.Printf("%s: %d entries (%d before)\n", update.Name, update.Entries, update.Previous)
+	}
+	fmt.Printf("Installed datasets %s into %s\n", version, dataDir)
+}
+
+// runNotifications prints the last notifications, oldest first.
+func runNotifications(path string, historyPath string, observer string, count int) {
+	entries, err := loadNotifications(path, historyPath, observer, count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load notifications: %v\n", err)
+		os.Exit(1)
+	}
+	for _, entry := range entries {
+		fmt.Printf("%s  %s\n", entry.Time.Local().Format(time.DateTime), entry.Title)
+		for line := range strings.SplitSeq(strings.TrimSpace(entry.Body), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+// loadNotifications reads the last notifications of the file at the given path, or of the sighting
+// store if there is none.
+func loadNotifications(
+	path string,
+	historyPath string,
+	observer string,
+	count int,
+) ([]internal.NotificationEntry, error) {
+	if path != "" {
+		entries, _, err := internal.NewNotificationFile(path).LoadNotifications(count)
+		return entries, err //nolint:wrapcheck // printed as is
+	}
+	if historyPath == "" {
+		return nil, nil
+	}
+	store, openErr := internal.OpenSightingStore(historyPath, observer)
+	if openErr != nil {
+		return nil, openErr //nolint:wrapcheck // printed as is
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	notifications, isNotificationStore := store.(internal.NotificationStore)
+	if !isNotificationStore {
+		return nil, nil
+	}
+	entries, _, err := notifications.LoadNotifications(count)
+	return entries, err //nolint:wrapcheck // printed as is
+}
+
+// defaultDataDir is the user data directory, or empty if there is none.
+func defaultDataDir() string {
+	dir, err := internal.UserDataDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// defaultObserver names this instance after the host it runs on.
+func defaultObserver() string {
+	hostname, err := os.Hostname()
+	if err != nil || internal.ValidateObserver(hostname) != nil {
+		return thisAppName
+	}
+	return hostname
 }
@@ -11,7 +11,6 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
-	"time"
 
 	"github.com/micutio/airspottr/internal"
 )
@@ -19,9 +18,10 @@ import (
 // TickerApp holds the state and dependencies for the ticker application.
 type TickerApp struct {
 	appName   string
-	options   internal.RequestOptions
+	options   internal.AppOptions
 	logger    *slog.Logger
 	request   *internal.Request
+	feed      *internal.AircraftFeed
 	dashboard *internal.Dashboard
 	notify    *internal.Notify
 	done      chan bool
@@ -29,16 +29,30 @@ type TickerApp struct {
 }
 
 // New creates and initializes a new TickerApp.
-func New(appName string, options internal.RequestOptions, stdout, stderr io.Writer) (*TickerApp, error) {
+func New(appName string, options internal.AppOptions, stdout, stderr io.Writer) (*TickerApp, error) {
 	logger := slog.Default() // Or a custom logger
-	notify := internal.NewNotify(appName, &stdout)
+	notify, notifyErr := internal.NewNotify(appName, options.Notify, stdout, stderr)
+	if notifyErr != nil {
+		return nil, fmt.Errorf("unable to create notifier: %w", notifyErr)
+	}
 
-	dashboard, dashboardErr := internal.NewDashboard(options.Lat, options.Lon, &stderr)
+	dashboard, dashboardErr := internal.NewDashboard(
+		options.Request.Lat,
+		options.Request.Lon,
+		options.Dashboard,
+		&stderr)
 	if dashboardErr != nil {
 		return nil, fmt.Errorf("unable to create dashboard: %w", dashboardErr)
 	}
+	if err := notify.KeepNotifications(dashboard.NotificationStore()); err != nil {
+		return nil, fmt.Errorf("unable to load notifications: %w", err)
+	}
+	for _, missing := range dashboard.MissingDatasets() {
+		logger.Warn("dataset missing, running without "+missing.Enrichment,
+			slog.String("dataset", missing.Name), slog.Any("error", missing.Err))
+	}
 
-	request, requestErr := internal.NewRequest(options, &stderr)
+	request, requestErr := internal.NewRequest(options.Request, &stderr)
 	if requestErr != nil {
 		return nil, fmt.Errorf("unable to create request: %w", requestErr)
 	}
@@ -48,6 +62,7 @@ func New(appName string, options internal.RequestOptions, stdout, stderr io.Writ
 		options:   options,
 		logger:    logger,
 		request:   request,
+		feed:      internal.NewAircraftFeed(request),
 		dashboard: dashboard,
 		notify:    notify,
 		done:      make(chan bool),
@@ -55,39 +70,99 @@ func New(appName string, options internal.RequestOptions, stdout, stderr io.Writ
 }
 
 // Run is the main entry point for the ticker application.
-func Run(appName string, options internal.RequestOptions) {
-	app, err := New(appName, options, os.Stdout, os.Stderr)
+func Run(appName string, options internal.AppOptions) {
+	app := run(appName, options, os.Stdout)
+	fmt.Printf("%s launching at Lat: %.3f, Lon: %.3f\n", appName, options.Request.Lat, options.Request.Lon)
+
+	app.start()
+	app.waitForShutdown()
+}
+
+// Serve runs the ticker application without printing anything, e.g. as a service: events only go
+// to the event sinks besides the console and the endpoints at the health address are served.
+func Serve(appName string, options internal.AppOptions) {
+	app := run(appName, options, io.Discard)
+	app.logger.Info("serving", slog.String("addr", options.Health.Addr),
+		slog.Float64("lat", options.Request.Lat), slog.Float64("lon", options.Request.Lon))
+
+	app.start()
+	app.waitForShutdown()
+}
+
+// run creates the ticker application and serves its health endpoint, if enabled, or exits.
+func run(appName string, options internal.AppOptions, stdout io.Writer) *TickerApp {
+	app, err := New(appName, options, stdout, os.Stderr)
 	if err != nil {
 		slog.Default().Error("failed to initialize ticker app", slog.Any("error", err))
 		os.Exit(1)
 	}
 
-	fmt.Printf("%s launching at Lat: %.3f, Lon: %.3f\n", appName, options.Lat, options.Lon)
-
-	app.start()
-	app.waitForShutdown()
+	if options.Health.Addr != "" {
+		health := internal.NewHealth(app.request, app.dashboard, options.Polling.MaxInterval())
+		stderr := io.Writer(os.Stderr)
+		if healthErr := internal.ServeHealth(options.Health, health, &stderr); healthErr != nil {
+			slog.Default().Error("failed to serve health endpoint", slog.Any("error", healthErr))
+			os.Exit(1)
+		}
+	}
+	return app
 }
 
 // start begins the application's main event loop in a goroutine.
 func (app *TickerApp) start() {
-	// Set a timeout for the warmup period.
-	time.AfterFunc(internal.DashboardWarmup, func() {
-		app.dashboard.FinishWarmupPeriod()
-	})
+	clock := app.dashboard.Clock()
+
+	polling := app.options.Polling
 
-	aircraftUpdateTicker := time.NewTicker(internal.AircraftUpdateInterval)
-	summaryTicker := time.NewTicker(internal.SummaryInterval)
+	// The warmup ends once there's a baseline of sightings, or after the warmup at the latest.
+	clock.AfterFunc(polling.Warmup, app.dashboard.FinishWarmupPeriod)
+
+	pollInterval := polling.NextInterval(clock.Now(), false)
+	aircraftUpdateTicker := clock.NewTicker(pollInterval)
+	summaryTicker := clock.NewTicker(polling.SummaryInterval)
+	weeklyReportTicker := clock.NewTicker(internal.WeeklyReportInterval)
+
+	// Datasets and config are reloaded on SIGHUP.
+	reloadSignal := make(chan os.Signal, 1)
+	signal.Notify(reloadSignal, syscall.SIGHUP)
 
 	app.wg.Go(func() {
-		defer aircraftUpdateTicker.Stop()
+		defer func() { aircraftUpdateTicker.Stop() }() // the ticker is replaced when adapting
 		defer summaryTicker.Stop()
+		defer weeklyReportTicker.Stop()
+		defer signal.Stop(reloadSignal)
 
 		for {
 			select {
-			case <-aircraftUpdateTicker.C:
-				aircraftRecords := app.request.RequestAircraft()
-				app.dashboard.ProcessAircraftRecords(aircraftRecords)
+			case <-aircraftUpdateTicker.C():
+				app.feed.Poll()
+			case poll := <-app.feed.Polls():
+				app.checkFeed(poll)
+				app.dashboard.ProcessPoll(poll.Aircraft, poll.Err)
+				app.notify.PrintAircraftUpdates(app.dashboard)
+				app.notify.TeeAircraftUpdates(app.dashboard)
+
+				// Look up photos of rare sightings, so that they can be linked in the notifications.
+				app.dashboard.AssignCachedPhotos()
+				registrationsWithoutPhoto := app.dashboard.RegistrationsWithoutPhoto()
+				if len(registrationsWithoutPhoto) > 0 {
+					photos := app.request.RequestPhotosForRegistrations(registrationsWithoutPhoto)
+					app.dashboard.AssignPhotos(registrationsWithoutPhoto, photos)
+				}
 				app.notify.EmitRarityNotifications(app.dashboard.RareSightings)
+				app.notify.EmitRuleAlerts(app.dashboard.RuleMatches)
+				app.notify.EmitNoteNotifications(app.dashboard.NoteSightings)
+				app.notify.EmitIdentityChanges(app.dashboard.IdentityChanges, clock.Now())
+				app.notify.EmitAreaMovements(app.dashboard.AreaMovements, clock.Now())
+				app.notify.EmitFirstSightings(app.dashboard.FirstSightings)
+				app.notify.EmitPeak(app.dashboard.NewPeak)
+				app.notify.EmitMachAlerts(app.dashboard.MachAlerts, clock.Now())
+				app.notify.EmitFollowEvents(app.dashboard.FollowEvents, clock.Now())
+				app.notify.EmitAchievements(app.dashboard.UnlockedAchievements, clock.Now())
+				app.notify.EmitNotableSightings(app.dashboard.NotableSightings)
+				app.notify.EmitCirclingAlerts(app.dashboard.CirclingAlerts, clock.Now())
+				app.notify.EmitGoArounds(app.dashboard.GoAroundAlerts, clock.Now())
+				app.notify.PrintFollowUpdate(app.dashboard.Follow)
 
 				// This method checks whether we have flight routes in the cache for all sightings.
 				callsignsWithoutRoute := app.dashboard.AssignRouteToCallsigns()
@@ -96,8 +171,26 @@ func (app *TickerApp) start() {
 					routes := app.request.RequestFlightRoutesForCallsigns(callsignsWithoutRoute)
 					app.dashboard.AssignFlightRoutes(routes)
 				}
-			case <-summaryTicker.C:
+
+				// Poll faster or slower from now on, if adaptive polling calls for it.
+				interesting := polling.Adaptive && app.dashboard.InterestingNearby(polling.NearbyRadius)
+				if next := polling.NextInterval(clock.Now(), interesting); next != pollInterval {
+					aircraftUpdateTicker.Stop()
+					aircraftUpdateTicker = clock.NewTicker(next)
+					pollInterval = next
+				}
+			case <-summaryTicker.C():
 				app.notify.PrintSummary(app.dashboard)
+				app.notify.PrintSourceStats(app.request.SourceStats())
+				app.exportCSV()
+			case <-weeklyReportTicker.C():
+				entries, historyErr := app.dashboard.LoadHistory()
+				if historyErr != nil {
+					app.logger.Error("failed to load sighting history", slog.Any("error", historyErr))
+				}
+				app.notify.PrintWeeklyReport(entries, clock.Now(), app.dashboard.SpottingDay())
+			case <-reloadSignal:
+				app.reload()
 			case <-app.done:
 				slog.Info("Stopping HTTP GET request routine.")
 				return
@@ -116,4 +209,44 @@ func (app *TickerApp) waitForShutdown() {
 	close(app.done)
 	// Wait for the main goroutine to finish.
 	app.wg.Wait()
+	app.exportCSV()
+	app.exportStats()
+	if err := app.notify.Close(); err != nil {
+		app.logger.Error("failed to close notifier", slog.Any("error", err))
+	}
+	if err := app.dashboard.Close(); err != nil {
+		app.logger.Error("failed to close sighting history", slog.Any("error", err))
+	}
+}
+
+// reload swaps in the datasets and config as they are on disk now.
+func (app *TickerApp) reload() {
+	if err := internal.Reload(app.options.ConfigPath, app.dashboard, app.notify); err != nil {
+		app.logger.Error("failed to reload datasets and config", slog.Any("error", err))
+		return
+	}
+	app.logger.Info("Reloaded datasets and config.")
+}
+
+// checkFeed reports when the feed stalls or recovers, as of the given poll.
+func (app *TickerApp) checkFeed(poll internal.PollResult) {
+	app.notify.CheckFeed(poll.FailingSince, poll.Err, app.dashboard.Clock().Now())
+}
+
+// exportCSV writes the statistics to the CSV files which are enabled.
+func (app *TickerApp) exportCSV() {
+	if err := app.dashboard.ExportCSV(app.options.Export); err != nil {
+		app.logger.Error("failed to export statistics", slog.Any("error", err))
+	}
+}
+
+// exportStats writes the session statistics to the stats file, if enabled.
+func (app *TickerApp) exportStats() {
+	path := app.options.Export.StatsPath
+	if path == "" {
+		return
+	}
+	if err := app.dashboard.SessionStats().Export(path); err != nil {
+		app.logger.Error("failed to export session statistics", slog.Any("error", err))
+	}
 }
@@ -0,0 +1,71 @@
+package tuiapp
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+const achievementsPageChrome = 3 // title and border of the achievements box
+
+// toggleAchievementsPage shows the achievements instead of the current aircraft, or goes back to
+// them.
+func (m *model) toggleAchievementsPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = achievementsPage
+	case achievementsPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage:
+	}
+}
+
+func (m *model) processAchievementsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "a", "esc":
+		m.toggleAchievementsPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// viewAchievements lists the achievements with their progress, and when they were unlocked.
+func (m *model) viewAchievements() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	progress := m.dashboard.Achievements.Progress()
+	shownCount := max(1, m.height-m.layout.headerHeight()-m.bannerHeight()-achievementsPageChrome)
+	shown := make([]string, 0, min(shownCount, len(progress)))
+	for _, achievement := range progress[:min(shownCount, len(progress))] {
+		shown = append(shown, fitCell(m.viewAchievement(achievement), m.width-2))
+	}
+
+	title := m.language.Sprintf("Achievements, %d of %d unlocked (a to go back)",
+		m.dashboard.Achievements.Unlocked(), len(progress))
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(shown, "\n"),
+	))
+}
+
+// viewAchievement describes an achievement in a line: its title, what it takes, how far it has come
+// and the day it was unlocked.
+func (m *model) viewAchievement(achievement internal.AchievementProgress) string {
+	state := m.language.T("locked")
+	if !achievement.Unlocked.IsZero() {
+		state = m.language.Sprintf("unlocked %s",
+			achievement.Unlocked.In(m.timeDisplay.Location()).Format(notificationDateFormat))
+	}
+	return fmt.Sprintf("%-22s %-46s %4d/%-4d %s",
+		m.language.T(achievement.Title),
+		m.language.T(achievement.Description),
+		achievement.Count,
+		achievement.Goal,
+		state)
+}
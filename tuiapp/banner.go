@@ -0,0 +1,84 @@
+package tuiapp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+const (
+	// alertBannerDuration is how long a high-priority event is shown at the top of the screen.
+	alertBannerDuration = 15 * time.Second
+	alertBannerHeight   = 1
+)
+
+// alertBanner is a high-priority event, shown at the top of the screen until it expires.
+type alertBanner struct {
+	event internal.Event
+	until time.Time
+}
+
+// showAlertBanners adds the events to the banner, newest first.
+func (m *model) showAlertBanners(events []internal.Event, now time.Time) {
+	if len(events) == 0 {
+		return
+	}
+	wasShown := len(m.alertBanners) > 0
+	for _, event := range events {
+		m.alertBanners = append(
+			[]alertBanner{{event: event, until: now.Add(alertBannerDuration)}}, m.alertBanners...)
+	}
+	if !wasShown {
+		m.resizeTables() // to make room for the banner
+	}
+}
+
+// expireAlertBanners drops the events which have been shown long enough.
+func (m *model) expireAlertBanners(now time.Time) {
+	if len(m.alertBanners) == 0 {
+		return
+	}
+	active := m.alertBanners[:0]
+	for _, banner := range m.alertBanners {
+		if now.Before(banner.until) {
+			active = append(active, banner)
+		}
+	}
+	m.alertBanners = active
+	if len(m.alertBanners) == 0 {
+		m.resizeTables() // to take back the room of the banner
+	}
+}
+
+// bannerHeight is how many lines the banners take up above the header.
+func (m *model) bannerHeight() int {
+	height := 0
+	if stalled, _ := m.notify.FeedStalled(); stalled {
+		height += stallBannerHeight
+	}
+	if len(m.alertBanners) > 0 {
+		height += alertBannerHeight
+	}
+	return height + m.errorBarHeight()
+}
+
+// viewAlertBanner shows the newest high-priority event and how many others are still shown.
+func (m *model) viewAlertBanner() string {
+	if len(m.alertBanners) == 0 {
+		return ""
+	}
+
+	newest := m.alertBanners[0].event
+	banner := fmt.Sprintf(" %s: %s", newest.Title, newest.Summary)
+	if others := len(m.alertBanners) - 1; others > 0 {
+		banner += fmt.Sprintf(" (+%d more)", others)
+	}
+	return m.baseStyle.
+		Bold(true).
+		Foreground(m.theme.OnYellow).
+		Background(m.theme.Yellow).
+		Width(m.width).
+		MaxHeight(alertBannerHeight).
+		Render(fitCell(banner, m.width))
+}
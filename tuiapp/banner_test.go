@@ -0,0 +1,38 @@
+package tuiapp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestAlertBanners(t *testing.T) {
+	m := &model{ //nolint:exhaustruct // only what the banner needs
+		width:     80,
+		baseStyle: lipgloss.NewStyle(),
+		theme:     getDefaultTheme(),
+	}
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	m.showAlertBanners([]internal.Event{
+		{Title: "Watchlist aircraft", Summary: "note \"local med-evac\""}, //nolint:exhaustruct // text only
+		{Title: "Emergency squawk", Summary: "DLH400 squawks 7700"},       //nolint:exhaustruct // text only
+	}, now)
+	banner := m.viewAlertBanner()
+	if !strings.Contains(banner, "Emergency squawk: DLH400 squawks 7700") ||
+		!strings.Contains(banner, "(+1 more)") {
+		t.Errorf("viewAlertBanner() = %q, expected the newest event and a count of the others", banner)
+	}
+
+	m.expireAlertBanners(now.Add(alertBannerDuration - time.Second))
+	if len(m.alertBanners) != 2 {
+		t.Errorf("expireAlertBanners() left %d banners before they expired", len(m.alertBanners))
+	}
+	m.expireAlertBanners(now.Add(alertBannerDuration))
+	if banner := m.viewAlertBanner(); banner != "" {
+		t.Errorf("viewAlertBanner() = %q after the events expired", banner)
+	}
+}
@@ -0,0 +1,122 @@
+package tuiapp
+
+import (
+	"fmt"
+	"log" //nolint:depguard // Don't feel like using slog for now.
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxErrorHistory is how many errors are kept for the error page, older ones are dropped.
+	maxErrorHistory = 100
+	errorBarHeight  = 1
+	errorPageChrome = 3 // title and border of the error box
+)
+
+// ErrorMsg carries an error of a command, e.g. a failed download, to be shown in the status bar
+// and kept in the error history.
+type ErrorMsg struct {
+	source string // source tells what failed, e.g. "aircraft poll".
+	err    error
+}
+
+// errorEntry is an error as kept in the error history.
+type errorEntry struct {
+	time   time.Time
+	source string
+	err    error
+}
+
+// recordError keeps the error for the status bar and the error page, and logs it.
+func (m *model) recordError(source string, err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("%s failed: %v", source, err)
+	entry := errorEntry{time: m.now(), source: source, err: err}
+	hadErrors := len(m.errorHistory) > 0
+	m.errorHistory = append(m.errorHistory, entry)
+	if len(m.errorHistory) > maxErrorHistory {
+		m.errorHistory = m.errorHistory[len(m.errorHistory)-maxErrorHistory:]
+	}
+	if !hadErrors {
+		m.resizeTables() // to make room for the status bar
+	}
+}
+
+// now returns the time of the dashboard's clock, or the system time while starting up.
+func (m *model) now() time.Time {
+	if m.dashboard == nil {
+		return time.Now()
+	}
+	return m.dashboard.Clock().Now()
+}
+
+// errorBarHeight is the height of the status bar, which only shows once there was an error.
+func (m *model) errorBarHeight() int {
+	if len(m.errorHistory) == 0 {
+		return 0
+	}
+	return errorBarHeight
+}
+
+// toggleErrorPage shows the error history instead of the current aircraft, or goes back to them.
+func (m *model) toggleErrorPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = errorPage
+	case errorPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	}
+}
+
+func (m *model) processErrorKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "E", "esc":
+		m.toggleErrorPage()
+	case "c":
+		m.errorHistory = nil
+		m.uiState = mainPage
+		m.resizeTables()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// viewErrorBar shows the last error with its time, empty if there was none.
+func (m *model) viewErrorBar() string {
+	if len(m.errorHistory) == 0 {
+		return ""
+	}
+	last := m.errorHistory[len(m.errorHistory)-1]
+	text := fmt.Sprintf(" %s %s failed: %s (%d errors, E to show)",
+		m.timeDisplay.Format(last.time), last.source, last.err, len(m.errorHistory))
+	return m.baseStyle.Foreground(m.theme.Red).Render(fitCell(text, m.width))
+}
+
+// viewErrors shows the newest errors which fit on the page.
+func (m *model) viewErrors() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	shownCount := max(1, m.height-m.layout.headerHeight()-m.bannerHeight()-errorPageChrome)
+	shown := make([]string, 0, shownCount)
+	for idx := len(m.errorHistory) - 1; idx >= 0 && len(shown) < shownCount; idx-- {
+		entry := m.errorHistory[idx]
+		line := fmt.Sprintf("%s %s failed: %s", m.timeDisplay.Format(entry.time), entry.source, entry.err)
+		shown = append(shown, fitCell(line, m.width-2))
+	}
+
+	title := m.language.Sprintf("Errors, newest first, %d kept (E to go back, c to clear)", len(m.errorHistory))
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(shown, "\n"),
+	))
+}
@@ -0,0 +1,65 @@
+package tuiapp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestErrorHistory(t *testing.T) {
+	timeDisplay, err := internal.NewTimeDisplay(internal.TimeZoneUTC, "15:04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled := false
+	notify, notifyErr := internal.NewNotify("test", internal.NotifyOptions{ //nolint:exhaustruct // no sinks
+		Sinks: internal.SinksConfig{Desktop: internal.SinkConfig{Enabled: &disabled}}, //nolint:exhaustruct // desktop off
+	}, nil, io.Discard)
+	if notifyErr != nil {
+		t.Fatal(notifyErr)
+	}
+	m := &model{ //nolint:exhaustruct // only what the status bar and the error page need
+		width:       120,
+		height:      40,
+		baseStyle:   lipgloss.NewStyle(),
+		viewStyle:   lipgloss.NewStyle(),
+		theme:       getDefaultTheme(),
+		timeDisplay: timeDisplay,
+		uiState:     mainPage,
+		notify:      notify,
+	}
+
+	m.recordError("aircraft poll", nil)
+	if m.errorBarHeight() != 0 || m.viewErrorBar() != "" {
+		t.Error("recordError(nil) showed the status bar")
+	}
+
+	for idx := range maxErrorHistory + 1 {
+		m.recordError("aircraft poll", errors.New("timeout"))
+		if idx == 0 && m.errorBarHeight() != errorBarHeight {
+			t.Error("recordError() didn't make room for the status bar")
+		}
+	}
+	m.recordError("photo of D-AIXA", errors.New("unauthorized host"))
+	if len(m.errorHistory) != maxErrorHistory {
+		t.Errorf("kept %d errors, expected at most %d", len(m.errorHistory), maxErrorHistory)
+	}
+	if bar := m.viewErrorBar(); !strings.Contains(bar, "photo of D-AIXA failed: unauthorized host") {
+		t.Errorf("viewErrorBar() = %q, expected the last error", bar)
+	}
+
+	m.toggleErrorPage()
+	page := m.viewErrors()
+	if m.uiState != errorPage || strings.Index(page, "D-AIXA") > strings.Index(page, "timeout") {
+		t.Errorf("viewErrors() = %q, expected the newest error first", page)
+	}
+	m.processErrorKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if len(m.errorHistory) != 0 || m.uiState != mainPage {
+		t.Error("clearing the errors didn't go back to the main page")
+	}
+}
@@ -0,0 +1,138 @@
+package tuiapp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+const (
+	favouritesPageChrome = 3   // title and border of the favourites box
+	favouriteMarker      = "*" // favouriteMarker precedes the flight of favourites in the aircraft table.
+	distanceColumn       = 0   // distanceColumn is the index of the distance in the rows of the aircraft table.
+	flightColumn         = 2   // flightColumn is the index of the flight in the rows of the aircraft table.
+)
+
+// toggleFavouritesPage shows the favourite aircraft instead of the current ones, or goes back to
+// them.
+func (m *model) toggleFavouritesPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = favouritesPage
+	case favouritesPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, errorPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	}
+}
+
+func (m *model) processFavouritesKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "F", "esc":
+		m.toggleFavouritesPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// toggleFavourite makes the aircraft shown in the details view, or selected in the aircraft table,
+// a favourite or takes it off the favourites.
+func (m *model) toggleFavourite() {
+	var hex string
+	switch m.uiState {
+	case aircraftDetails:
+		if m.detailAircraft == nil {
+			return
+		}
+		hex = m.detailAircraft.Hex
+	case mainPage:
+		selected, ok := m.currentAircraftTbl.selectedKey()
+		if !ok || !m.currentAircraftTbl.table.Focused() {
+			return
+		}
+		hex = selected
+	case globalStats, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+		return
+	}
+
+	note, _ := m.dashboard.Notes.Get(hex)
+	note.Hex = hex
+	note.Favourite = !note.Favourite
+	note.Updated = time.Now()
+	m.noteErr = m.dashboard.Notes.Set(note)
+	m.recordError("saving note", m.noteErr)
+	m.updateAircraftTable() // to pin it to the top or let it go
+}
+
+// pinFavourites moves the rows of favourite aircraft to the top, in their order, and marks them.
+func (m *model) pinFavourites(keys []string, rows []table.Row, tints []lipgloss.TerminalColor) {
+	pinned := 0
+	for idx := range keys {
+		if !m.dashboard.Notes.IsFavourite(keys[idx]) {
+			continue
+		}
+		rows[idx][flightColumn] = favouriteMarker + rows[idx][flightColumn]
+		// Shift the rows in between down by one to keep them in order.
+		key, row, tint := keys[idx], rows[idx], tints[idx]
+		copy(keys[pinned+1:idx+1], keys[pinned:idx])
+		copy(rows[pinned+1:idx+1], rows[pinned:idx])
+		copy(tints[pinned+1:idx+1], tints[pinned:idx])
+		keys[pinned], rows[pinned], tints[pinned] = key, row, tint
+		pinned++
+	}
+}
+
+// viewFavourites lists the favourite aircraft which fit on the page, those in sight first.
+func (m *model) viewFavourites() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+	now := m.dashboard.Clock().Now()
+
+	var inSight, outOfSight []string
+	for _, note := range m.dashboard.Notes.Favourites() {
+		idx := slices.IndexFunc(m.dashboard.CurrentAircraft, func(aircraft internal.AircraftRecord) bool {
+			return aircraft.Hex == note.Hex
+		})
+		if idx >= 0 {
+			aircraft := &m.dashboard.CurrentAircraft[idx]
+			seen := fmt.Sprintf("in sight as %s, %s", aircraft.GetFlightNoAsStr(), m.viewDistance(aircraft))
+			inSight = append(inSight, viewFavourite(note, seen, m.width-2))
+			continue
+		}
+		seen := "not seen yet"
+		if legs := m.dashboard.PreviousFlights(note.Hex, ""); len(legs) > 0 {
+			seen = "last seen as " + legs[0].Describe(now)
+		}
+		outOfSight = append(outOfSight, viewFavourite(note, seen, m.width-2))
+	}
+
+	shownCount := max(1, m.height-m.layout.headerHeight()-m.bannerHeight()-favouritesPageChrome)
+	shown := append(inSight, outOfSight...)
+	shown = shown[:min(len(shown), shownCount)]
+	if len(shown) == 0 {
+		shown = []string{"No favourites yet, press * on an aircraft to add it"}
+	}
+
+	title := m.language.Sprintf("Favourites, %d in sight (F to go back)", len(inSight))
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(shown, "\n"),
+	))
+}
+
+// viewFavourite describes a favourite aircraft in a line: its hex, when it was seen and its note.
+func viewFavourite(note internal.Note, seen string, width int) string {
+	line := note.Hex + "  " + seen
+	if note.Text != "" {
+		line += "  " + note.Text
+	}
+	return fitCell(line, width)
+}
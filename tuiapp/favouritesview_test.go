@@ -0,0 +1,43 @@
+package tuiapp
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestPinFavourites(t *testing.T) {
+	notes, err := internal.LoadNotes(filepath.Join(t.TempDir(), "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hex := range []string{"4ca7b5", "440123"} {
+		//nolint:exhaustruct // only favourites
+		if setErr := notes.Set(internal.Note{Hex: hex, Favourite: true}); setErr != nil {
+			t.Fatal(setErr)
+		}
+	}
+	m := &model{ //nolint:exhaustruct // only what pinning needs
+		dashboard: &internal.Dashboard{Notes: notes}, //nolint:exhaustruct // only the notes
+	}
+
+	keys := []string{"3c6444", "4ca7b5", "3c4b26", "440123"}
+	rows := []table.Row{{"10", "●●●", "DLH400"}, {"20", "●●○", "RYR1"}, {"30", "●●●", "DLH401"}, {"40", "", ""}}
+	red, blue := lipgloss.Color("1"), lipgloss.Color("4")
+	tints := []lipgloss.TerminalColor{red, blue, red, blue}
+	m.pinFavourites(keys, rows, tints)
+
+	if expected := []string{"4ca7b5", "440123", "3c6444", "3c4b26"}; !slices.Equal(keys, expected) {
+		t.Errorf("pinFavourites() keys = %v, expected %v", keys, expected)
+	}
+	if rows[0][2] != "*RYR1" || rows[1][2] != "*" || rows[2][2] != "DLH400" || rows[3][0] != "30" {
+		t.Errorf("pinFavourites() rows = %v, expected the favourites marked and first", rows)
+	}
+	if tints[0] != blue || tints[1] != blue || tints[2] != red {
+		t.Errorf("pinFavourites() tints = %v, expected them to follow their rows", tints)
+	}
+}
@@ -0,0 +1,101 @@
+package tuiapp
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+// followPageChrome is the title, the details, the altitude chart, the header of the track and the
+// border of the follow box.
+const followPageChrome = 16
+
+// toggleFollowPage shows the followed aircraft instead of the current ones, or goes back to them.
+// There's only a follow page when following an aircraft with --follow.
+func (m *model) toggleFollowPage() {
+	if m.dashboard == nil || m.dashboard.Follow == nil {
+		return
+	}
+	switch m.uiState {
+	case mainPage:
+		m.uiState = followPage
+	case followPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, achievementsPage:
+	}
+}
+
+func (m *model) processFollowKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "T", "esc":
+		m.toggleFollowPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// viewFollow shows the followed aircraft as of the latest poll it was in sight, its altitude along
+// the track and the points of the track which fit on the page, newest first.
+func (m *model) viewFollow() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	followItem := func(key string, value string) string {
+		return fmt.Sprintf("%s %s", keyStyle.Render(fmt.Sprintf("%12s:", key)), value)
+	}
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	track := m.dashboard.Follow
+	title := keyStyle.Render(m.language.Sprintf("Following %s (T to go back)", track.Target))
+	aircraft := track.Aircraft
+	if aircraft == nil {
+		return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			m.language.Sprintf("Waiting for %s to come into sight", track.Target)))
+	}
+
+	phase := m.language.T(string(track.Phase()))
+	if !track.InSight() {
+		phase = m.language.T("out of sight")
+	}
+	route, ok := m.dashboard.CachedFlightRoutes[aircraft.GetFlightNoAsStr()]
+	if !ok {
+		route = internal.GetDefaultFlightrouteRecord()
+	}
+
+	shownCount := max(1, m.height-m.layout.headerHeight()-m.bannerHeight()-followPageChrome)
+	points := track.Points
+	rows := make([]string, 0, min(shownCount, len(points)))
+	for idx := len(points) - 1; idx >= 0 && len(rows) < shownCount; idx-- {
+		point := points[idx]
+		rows = append(rows, fitCell(fmt.Sprintf("%8s %9.4f %9.4f %6s %5.0f %6.0f %4.0f",
+			m.timeDisplay.Format(point.Time),
+			point.Lat,
+			point.Lon,
+			strings.TrimSpace(point.Altitude.String()),
+			point.GroundSpeed,
+			point.VerticalRate,
+			point.Track), m.width-2))
+	}
+
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		followItem(m.language.T("Flight"), fmt.Sprintf("%s (%s)", aircraft.GetFlightNoAsStr(), phase)),
+		followItem(m.language.T("Registration"), aircraft.Registration),
+		followItem(m.language.T("Hex"), aircraft.Hex),
+		followItem(m.language.T("Description"), aircraft.Description),
+		followItem(m.language.T("Origin"), route.Origin.Airport),
+		followItem(m.language.T("Destination"), route.Destination.Airport),
+		followItem(m.language.T("Distance"), m.viewDistance(aircraft)),
+		followItem(m.language.T("Altitude"), aircraft.AltBaro.String()),
+		followItem(m.language.T("Speed"), fmt.Sprintf("%.0f kt", aircraft.GroundSpeed)),
+		followItem(m.language.T("Squawk"), aircraft.Squawk),
+		followItem(m.language.T("Profile"), track.AltitudeSparkline(m.width-18)), //nolint:mnd // room for the label
+		"",
+		keyStyle.Render(fitCell("    Time       Lat       Lon    Alt    GS    V/S  Trk", m.width-2)),
+		strings.Join(rows, "\n"),
+	))
+}
@@ -0,0 +1,321 @@
+package tuiapp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // photos of planespotters.net are JPEG
+	"image/png"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+// Modes of showing photos of aircraft in the details view.
+const (
+	ImagesAuto  = "auto"  // ImagesAuto picks the graphics protocol of the terminal, if it has one.
+	ImagesKitty = "kitty" // ImagesKitty uses the kitty graphics protocol, also known to WezTerm and Ghostty.
+	ImagesITerm = "iterm" // ImagesITerm uses the inline images of iTerm2.
+	ImagesOff   = "off"   // ImagesOff always shows ASCII silhouettes.
+)
+
+const (
+	// imageColumns and imageRows are the size of a photo in the details view, in cells.
+	imageColumns = 32
+	imageRows    = 9
+	// kittyChunkSize is the longest base64 payload of a single kitty graphics escape sequence.
+	kittyChunkSize = 4096
+	// maxPhotoImages is how many photos are kept encoded for the terminal. Beyond, the least
+	// recently shown are dropped, and deleted from the terminal with kitty.
+	maxPhotoImages = 20
+	// graphicsFlushDelay is how long escape sequences which are sent once are written along with the
+	// view, so that they make it into a frame of the renderer.
+	graphicsFlushDelay = 500 * time.Millisecond
+)
+
+var errInvalidImages = errors.New("invalid images mode")
+
+// graphicsProtocol is how the terminal shows images, if it can.
+type graphicsProtocol int
+
+const (
+	graphicsNone graphicsProtocol = iota
+	graphicsKitty
+	graphicsITerm
+)
+
+// ValidateImages checks that the mode is one of the ImagesXxx modes.
+func ValidateImages(mode string) error {
+	switch mode {
+	case ImagesAuto, ImagesKitty, ImagesITerm, ImagesOff:
+		return nil
+	}
+	return fmt.Errorf("ValidateImages: %w: %q", errInvalidImages, mode)
+}
+
+// detectGraphics returns the protocol of the mode, or the one of the terminal as told by the
+// environment for ImagesAuto.
+func detectGraphics(mode string, getenv func(string) string) graphicsProtocol {
+	switch mode {
+	case ImagesKitty:
+		return graphicsKitty
+	case ImagesITerm:
+		return graphicsITerm
+	case ImagesAuto:
+		switch {
+		case getenv("KITTY_WINDOW_ID") != "" || getenv("TERM") == "xterm-kitty" ||
+			getenv("TERM") == "xterm-ghostty":
+			return graphicsKitty
+		case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("TERM_PROGRAM") == "WezTerm":
+			return graphicsITerm
+		}
+	}
+	return graphicsNone
+}
+
+// photoImage is the photo of a registration encoded for the terminal.
+type photoImage struct {
+	id uint32 // id is the number of the image in the terminal with kitty.
+	// transmit sends the image to the terminal once with kitty, empty with other protocols.
+	transmit string
+	// place shows the image, followed by blank lines to make room for it. Empty while downloading
+	// or if there is no photo.
+	place string
+}
+
+// encodeImage turns a photo into the escape sequences which show it in the terminal. With kitty
+// the image is transmitted once under the given id and then only placed, with iTerm2 the whole
+// image is sent every time. The kitty protocol only takes PNG, so other images are converted first.
+func encodeImage(protocol graphicsProtocol, photo []byte, id uint32) (photoImage, error) {
+	encoded := photoImage{id: id, transmit: "", place: ""}
+	switch protocol {
+	case graphicsKitty:
+		decoded, _, decodeErr := image.Decode(bytes.NewReader(photo))
+		if decodeErr != nil {
+			return encoded, fmt.Errorf("encodeImage: %w", decodeErr)
+		}
+		var converted bytes.Buffer
+		if err := png.Encode(&converted, decoded); err != nil {
+			return encoded, fmt.Errorf("encodeImage: %w", err)
+		}
+		encoded.transmit = kittyTransmit(converted.Bytes(), id)
+		encoded.place = kittyPlace(id, imageColumns, imageRows)
+	case graphicsITerm:
+		encoded.place = itermImage(photo, imageColumns, imageRows)
+	case graphicsNone:
+	}
+	return encoded, nil
+}
+
+// kittyTransmit sends a PNG image to the terminal under the given id with the kitty graphics
+// protocol, without showing it yet. The image is sent in chunks.
+func kittyTransmit(pngImage []byte, id uint32) string {
+	payload := base64.StdEncoding.EncodeToString(pngImage)
+	var builder strings.Builder
+	for start := 0; start < len(payload) || start == 0; start += kittyChunkSize {
+		end := min(start+kittyChunkSize, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if start == 0 {
+			fmt.Fprintf(&builder, "\x1b_Gf=100,a=t,i=%d,q=2,m=%d;%s\x1b\\", id, more, payload[start:end])
+		} else {
+			fmt.Fprintf(&builder, "\x1b_Gm=%d;%s\x1b\\", more, payload[start:end])
+		}
+	}
+	return builder.String()
+}
+
+// kittyPlace shows the transmitted image of the given id in the given number of cells. Placing it
+// again replaces the placement, and the cursor stays put, so that the blank lines below make room
+// for it.
+func kittyPlace(id uint32, columns int, rows int) string {
+	return fmt.Sprintf("\x1b_Ga=p,i=%d,p=1,q=2,C=1,c=%d,r=%d\x1b\\", id, columns, rows) +
+		strings.Repeat("\n", rows-1)
+}
+
+// kittyDelete takes the image of the given id off the screen, and frees it in the terminal as
+// well if it won't be shown again.
+func kittyDelete(id uint32, free bool) string {
+	target := 'i'
+	if free {
+		target = 'I'
+	}
+	return fmt.Sprintf("\x1b_Ga=d,d=%c,i=%d,q=2\x1b\\", target, id)
+}
+
+// itermImage shows an image in the given number of cells with the inline images of iTerm2,
+// which move the cursor below the image themselves.
+func itermImage(photo []byte, columns int, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(photo), columns, rows, base64.StdEncoding.EncodeToString(photo))
+}
+
+// Silhouettes of aircraft shown in place of photos.
+var (
+	jetSilhouette = []string{
+		`            __|__`,
+		`     --o--o--(_)--o--o--`,
+		`           /  |  \`,
+	}
+	propSilhouette = []string{
+		`             |`,
+		`     -------(_)-------`,
+		`            -|-`,
+	}
+	helicopterSilhouette = []string{
+		`     ----------+----------`,
+		`        _____|___`,
+		`   ----/____[]___|`,
+		`          -+--+-`,
+	}
+)
+
+// silhouette returns the ASCII art matching the class and engine of an aircraft type.
+func silhouette(aircraft dash.IcaoAircraft) []string {
+	switch {
+	case aircraft.Class == "Helicopter" || aircraft.Class == "Gyrocopter" || aircraft.Class == "Tiltrotor":
+		return helicopterSilhouette
+	case strings.Contains(aircraft.Engine, "Jet"):
+		return jetSilhouette
+	default:
+		return propSilhouette
+	}
+}
+
+// PhotoImageMsg carries the photo of a registration, encoded for the terminal.
+type PhotoImageMsg struct {
+	registration string
+	image        photoImage
+}
+
+// graphicsSentMsg tells that the escape sequences queued up to the given generation have been
+// written along with the view for long enough.
+type graphicsSentMsg struct {
+	generation int
+}
+
+func requestPhotoImageCmd(
+	request *internal.Request,
+	protocol graphicsProtocol,
+	registration string,
+	photo *internal.PhotoRecord,
+	id uint32,
+) tea.Cmd {
+	return func() tea.Msg {
+		image, reqErr := request.RequestPhotoImage(registration, photo)
+		if reqErr != nil {
+			return ErrorMsg{source: "photo of " + registration, err: reqErr}
+		}
+		encoded, encodeErr := encodeImage(protocol, image, id)
+		if encodeErr != nil {
+			return ErrorMsg{source: "photo of " + registration, err: encodeErr}
+		}
+		return PhotoImageMsg{registration: registration, image: encoded}
+	}
+}
+
+// requestDetailImage downloads the photo of the aircraft in the details view, if the terminal
+// can show it and it hasn't been tried yet.
+func (m *model) requestDetailImage() tea.Cmd {
+	if m.graphics == graphicsNone || m.detailAircraft == nil {
+		return nil
+	}
+	registration := m.detailAircraft.Registration
+	photo := m.dashboard.GetPhotoForRegistration(registration)
+	if !photo.HasLink() {
+		return nil
+	}
+	if _, ok := m.photoImages[registration]; ok {
+		m.keepPhotoImage(registration)
+		return nil
+	}
+	// Only try once, the message fills it in.
+	m.nextImageID++
+	m.photoImages[registration] = photoImage{id: m.nextImageID, transmit: "", place: ""}
+	return tea.Batch(
+		m.keepPhotoImage(registration),
+		requestPhotoImageCmd(m.request, m.graphics, registration, photo, m.nextImageID))
+}
+
+// receivePhotoImage keeps the downloaded photo and transmits it to the terminal, unless it has
+// been dropped in the meantime.
+func (m *model) receivePhotoImage(msg PhotoImageMsg) tea.Cmd {
+	if _, ok := m.photoImages[msg.registration]; !ok {
+		return nil
+	}
+	m.photoImages[msg.registration] = msg.image
+	return m.queueGraphics(msg.image.transmit)
+}
+
+// keepPhotoImage marks the photo of the registration as the most recently shown, and drops the
+// least recently shown photos beyond maxPhotoImages.
+func (m *model) keepPhotoImage(registration string) tea.Cmd {
+	m.photoOrder = append(slices.DeleteFunc(m.photoOrder, func(kept string) bool {
+		return kept == registration
+	}), registration)
+
+	var deletions strings.Builder
+	for len(m.photoOrder) > maxPhotoImages {
+		dropped := m.photoOrder[0]
+		m.photoOrder = m.photoOrder[1:]
+		if m.graphics == graphicsKitty {
+			deletions.WriteString(kittyDelete(m.photoImages[dropped].id, true))
+		}
+		delete(m.photoImages, dropped)
+	}
+	return m.queueGraphics(deletions.String())
+}
+
+// syncDetailImage takes the kitty image of the details view off the screen once another page or
+// aircraft is shown, since kitty keeps images on the screen until they are deleted.
+func (m *model) syncDetailImage() tea.Cmd {
+	if m.graphics != graphicsKitty {
+		return nil
+	}
+	var shown uint32
+	if m.uiState == aircraftDetails && m.detailAircraft != nil {
+		if image := m.photoImages[m.detailAircraft.Registration]; image.place != "" {
+			shown = image.id
+		}
+	}
+	if shown == m.shownImage {
+		return nil
+	}
+	previous := m.shownImage
+	m.shownImage = shown
+	if previous == 0 {
+		return nil
+	}
+	return m.queueGraphics(kittyDelete(previous, false))
+}
+
+// queueGraphics writes the escape sequences along with the view for graphicsFlushDelay, which is
+// long enough for the renderer to write them once, but not with every view.
+func (m *model) queueGraphics(sequences string) tea.Cmd {
+	if sequences == "" {
+		return nil
+	}
+	m.pendingGraphics += sequences
+	m.graphicsGeneration++
+	generation := m.graphicsGeneration
+	return tea.Tick(graphicsFlushDelay, func(time.Time) tea.Msg {
+		return graphicsSentMsg{generation: generation}
+	})
+}
+
+// viewDetailImage shows the photo of the aircraft in the details view, or a silhouette of its
+// type if there is no photo or the terminal can't show it.
+func (m *model) viewDetailImage(aircraft *internal.AircraftRecord) string {
+	if image := m.photoImages[aircraft.Registration]; image.place != "" {
+		return image.place
+	}
+	return strings.Join(silhouette(m.dashboard.IcaoToAircraft[aircraft.IcaoType]), "\n")
+}
@@ -0,0 +1,136 @@
+package tuiapp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"strings"
+	"testing"
+
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/dash"
+)
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		mode     string
+		env      map[string]string
+		expected graphicsProtocol
+	}{
+		{ImagesAuto, map[string]string{"TERM": "xterm-kitty"}, graphicsKitty},
+		{ImagesAuto, map[string]string{"KITTY_WINDOW_ID": "1"}, graphicsKitty},
+		{ImagesAuto, map[string]string{"TERM_PROGRAM": "iTerm.app"}, graphicsITerm},
+		{ImagesAuto, map[string]string{"TERM": "xterm-256color"}, graphicsNone},
+		{ImagesOff, map[string]string{"TERM": "xterm-kitty"}, graphicsNone},
+		{ImagesITerm, map[string]string{}, graphicsITerm},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if protocol := detectGraphics(tt.mode, getenv); protocol != tt.expected {
+			t.Errorf("detectGraphics(%q, %v) = %d, expected %d", tt.mode, tt.env, protocol, tt.expected)
+		}
+	}
+
+	if err := ValidateImages("sixel"); err == nil {
+		t.Error("ValidateImages(\"sixel\") succeeded, expected an error")
+	}
+}
+
+func TestKittyTransmitChunks(t *testing.T) {
+	// 6000 bytes take 8000 characters of base64, which are sent in two chunks.
+	encoded := kittyTransmit(make([]byte, 6000), 7)
+	if count := strings.Count(encoded, "\x1b_G"); count != 2 {
+		t.Errorf("kittyTransmit() has %d chunks, expected 2", count)
+	}
+	if !strings.HasPrefix(encoded, "\x1b_Gf=100,a=t,i=7,") {
+		t.Errorf("kittyTransmit() doesn't transmit under the id: %q", encoded[:64])
+	}
+	if !strings.Contains(encoded, "m=1;") || !strings.Contains(encoded, "\x1b_Gm=0;") {
+		t.Errorf("kittyTransmit() doesn't mark the last chunk: %q", encoded[:64])
+	}
+	if place := kittyPlace(7, imageColumns, imageRows); strings.Count(place, "\n") != imageRows-1 {
+		t.Errorf("kittyPlace() makes room of %d lines, expected %d", strings.Count(place, "\n"), imageRows-1)
+	}
+}
+
+func TestEncodeImage(t *testing.T) {
+	var photo bytes.Buffer
+	if err := jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 4, 3)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	kitty, kittyErr := encodeImage(graphicsKitty, photo.Bytes(), 3)
+	if kittyErr != nil || !strings.HasPrefix(kitty.transmit, "\x1b_Gf=100,a=t,i=3,") ||
+		!strings.HasPrefix(kitty.place, "\x1b_Ga=p,i=3,") {
+		t.Errorf("encodeImage(kitty) = %+v, %v, expected a PNG transmitted once and placed", kitty, kittyErr)
+	}
+	iterm, itermErr := encodeImage(graphicsITerm, photo.Bytes(), 4)
+	if itermErr != nil || iterm.transmit != "" || !strings.HasPrefix(iterm.place, "\x1b]1337;File=inline=1;") {
+		t.Errorf("encodeImage(iterm) = %+v, %v, expected an inline image", iterm, itermErr)
+	}
+	if _, err := encodeImage(graphicsKitty, []byte("not an image"), 5); err == nil {
+		t.Error("encodeImage(kitty) succeeded on garbage, expected an error")
+	}
+}
+
+func TestSilhouette(t *testing.T) {
+	tests := []struct {
+		aircraft dash.IcaoAircraft
+		expected []string
+	}{
+		{dash.IcaoAircraft{Class: "LandPlane", Engine: "2/Jet", Make: "AIRBUS, A-320"}, jetSilhouette},
+		{dash.IcaoAircraft{Class: "LandPlane", Engine: "1/Piston", Make: "CESSNA, 172"}, propSilhouette},
+		{dash.IcaoAircraft{Class: "Helicopter", Engine: "2/Turboprop/Turboshaft", Make: "EC-135"}, helicopterSilhouette},
+		{dash.IcaoAircraft{Class: "", Engine: "", Make: ""}, propSilhouette},
+	}
+	for _, tt := range tests {
+		if art := silhouette(tt.aircraft); art[1] != tt.expected[1] {
+			t.Errorf("silhouette(%v) = %v, expected %v", tt.aircraft, art, tt.expected)
+		}
+	}
+}
+
+func TestKittyImagesOfTheDetailsView(t *testing.T) {
+	m := &model{ //nolint:exhaustruct // only what the photos need
+		uiState:     aircraftDetails,
+		graphics:    graphicsKitty,
+		photoImages: make(map[string]photoImage),
+	}
+	m.detailAircraft = &internal.AircraftRecord{Registration: "D-AIBD"} //nolint:exhaustruct // registration only
+	m.photoImages["D-AIBD"] = photoImage{id: 1, transmit: "", place: ""}
+	m.keepPhotoImage("D-AIBD")
+
+	image := photoImage{id: 1, transmit: kittyTransmit([]byte("png"), 1), place: kittyPlace(1, 2, 2)}
+	m.Update(PhotoImageMsg{registration: "D-AIBD", image: image})
+	if m.pendingGraphics != image.transmit {
+		t.Errorf("pending graphics %q, expected the photo to be transmitted", m.pendingGraphics)
+	}
+	m.Update(graphicsSentMsg{generation: m.graphicsGeneration})
+	if m.pendingGraphics != "" || m.shownImage != 1 {
+		t.Errorf("pending graphics %q with image %d shown, expected it to be sent once and shown",
+			m.pendingGraphics, m.shownImage)
+	}
+	if view := m.viewDetailImage(m.detailAircraft); view != image.place {
+		t.Errorf("viewDetailImage() = %q, expected only the placement", view)
+	}
+
+	m.uiState = mainPage
+	m.Update(graphicsSentMsg{generation: m.graphicsGeneration})
+	if m.pendingGraphics != kittyDelete(1, false) || m.shownImage != 0 {
+		t.Errorf("pending graphics %q on the main page, expected the placement deleted", m.pendingGraphics)
+	}
+
+	m.pendingGraphics = ""
+	for idx := range maxPhotoImages {
+		registration := fmt.Sprintf("D-A%03d", idx)
+		m.photoImages[registration] = photoImage{id: uint32(idx + 2), transmit: "", place: ""} //nolint:gosec // small
+		m.keepPhotoImage(registration)
+	}
+	if _, ok := m.photoImages["D-AIBD"]; ok || len(m.photoImages) != maxPhotoImages {
+		t.Errorf("%d photos kept, expected the least recently shown D-AIBD dropped", len(m.photoImages))
+	}
+	if m.pendingGraphics != kittyDelete(1, true) {
+		t.Errorf("pending graphics %q, expected the dropped photo freed", m.pendingGraphics)
+	}
+}
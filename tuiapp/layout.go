@@ -0,0 +1,126 @@
+package tuiapp
+
+import (
+	"github.com/micutio/airspottr/internal"
+)
+
+const (
+	// headerHeight is the height of the header, six lines within its border, together with the
+	// border of the tables below it.
+	headerHeight = 10
+	// statsLinesHeight is the height of the statistics above the rarity tables: rarity scorer,
+	// traffic, altitude bands, message types, discovery and sources.
+	statsLinesHeight = 10
+
+	rarityTableCount = 3
+	// tableShareStep is how much of the width a rarity table gains or loses per key press.
+	tableShareStep = 0.05
+	// minTableShare is the least of the width a rarity table keeps.
+	minTableShare = 0.1
+)
+
+// layout arranges the panels of the TUI. Its zero value shows all panels and splits the width
+// evenly between the rarity tables.
+type layout struct {
+	hideHeader bool
+	hideStats  bool
+	shares     [rarityTableCount]float64 // shares of the width of the rarity tables, zero if even
+}
+
+// newLayout restores the layout kept in the config.
+func newLayout(config internal.LayoutConfig) layout {
+	restored := layout{
+		hideHeader: config.HideHeader,
+		hideStats:  config.HideStats,
+		shares:     [rarityTableCount]float64{},
+	}
+	if len(config.Splits) == rarityTableCount-1 {
+		restored.shares = [rarityTableCount]float64{
+			config.Splits[0],
+			config.Splits[1],
+			1 - config.Splits[0] - config.Splits[1],
+		}
+	}
+	return restored
+}
+
+// config returns the layout to be kept in the config.
+func (l *layout) config() internal.LayoutConfig {
+	var splits []float64
+	if l.shares != [rarityTableCount]float64{} {
+		splits = []float64{l.shares[0], l.shares[1]}
+	}
+	return internal.LayoutConfig{HideHeader: l.hideHeader, HideStats: l.hideStats, Splits: splits}
+}
+
+// headerHeight is the height of the header, zero if it is collapsed.
+func (l *layout) headerHeight() int {
+	if l.hideHeader {
+		return 0
+	}
+	return headerHeight
+}
+
+// statsHeight is the height of the statistics above the rarity tables, zero if they are collapsed.
+func (l *layout) statsHeight() int {
+	if l.hideStats {
+		return 0
+	}
+	return statsLinesHeight
+}
+
+// tableShares returns the shares of the width of the rarity tables.
+func (l *layout) tableShares() [rarityTableCount]float64 {
+	if l.shares == [rarityTableCount]float64{} {
+		return [rarityTableCount]float64{1.0 / rarityTableCount, 1.0 / rarityTableCount, 1.0 / rarityTableCount}
+	}
+	return l.shares
+}
+
+// resizeTable widens the rarity table of the given index by delta, or narrows it if delta is
+// negative, at the expense of its neighbour to the right, or to the left for the last table.
+// It returns false if either table would become too narrow.
+func (l *layout) resizeTable(idx int, delta float64) bool {
+	shares := l.tableShares()
+	neighbour := idx + 1
+	if neighbour == rarityTableCount {
+		neighbour = idx - 1
+	}
+	shares[idx] += delta
+	shares[neighbour] -= delta
+	if shares[idx] < minTableShare || shares[neighbour] < minTableShare {
+		return false
+	}
+	l.shares = shares
+	return true
+}
+
+// tableWidths divides the given width between the rarity tables, the last one takes what is
+// left after rounding.
+func (l *layout) tableWidths(width int) [rarityTableCount]int {
+	shares := l.tableShares()
+	var widths [rarityTableCount]int
+	rest := width
+	for idx := range rarityTableCount - 1 {
+		widths[idx] = int(float64(width) * shares[idx])
+		rest -= widths[idx]
+	}
+	widths[rarityTableCount-1] = rest
+	return widths
+}
+
+// resizeSelectedTable widens or narrows the selected rarity table, if one is selected.
+func (m *model) resizeSelectedTable(delta float64) {
+	tables := [rarityTableCount]*autoFormatTable{&m.typeRarityTbl, &m.operatorRarityTbl, &m.countryRarityTbl}
+	for idx, table := range tables {
+		if m.selectedTable == table && m.layout.resizeTable(idx, delta) {
+			m.adjustLayout()
+		}
+	}
+}
+
+// adjustLayout resizes the tables to the adjusted layout and keeps it in the config file.
+func (m *model) adjustLayout() {
+	m.resizeTables()
+	m.recordError("saving layout", internal.SaveLayout(m.configPath, m.layout.config()))
+}
@@ -0,0 +1,45 @@
+package tuiapp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/micutio/airspottr/internal"
+)
+
+func TestLayoutTableWidths(t *testing.T) {
+	var even layout
+	if widths := even.tableWidths(100); widths != [rarityTableCount]int{33, 33, 34} {
+		t.Errorf("tableWidths() = %v, expected an even split", widths)
+	}
+
+	adjusted := newLayout(internal.LayoutConfig{HideHeader: true, HideStats: false, Splits: []float64{0.5, 0.25}})
+	if widths := adjusted.tableWidths(100); widths != [rarityTableCount]int{50, 25, 25} {
+		t.Errorf("tableWidths() = %v, expected the restored split", widths)
+	}
+	if adjusted.headerHeight() != 0 || adjusted.statsHeight() != statsLinesHeight {
+		t.Errorf("heights = %d, %d, expected a collapsed header", adjusted.headerHeight(), adjusted.statsHeight())
+	}
+}
+
+func TestLayoutResizeTable(t *testing.T) {
+	var adjusted layout
+	// The last table grows at the expense of the one to its left.
+	if !adjusted.resizeTable(2, 0.2) {
+		t.Fatal("resizeTable(2, 0.2) = false, expected room to grow")
+	}
+	shares := adjusted.tableShares()
+	if shares[1] > 0.14 || shares[2] < 0.53 {
+		t.Errorf("tableShares() = %v, expected the operator table to give way", shares)
+	}
+	if adjusted.resizeTable(2, 0.1) {
+		t.Error("resizeTable(2, 0.1) = true, expected the operator table to keep its minimum")
+	}
+
+	config := adjusted.config()
+	restoredLayout := newLayout(config)
+	restored := restoredLayout.tableShares()
+	if restored[0] != shares[0] || restored[1] != shares[1] || math.Abs(restored[2]-shares[2]) > 1e-9 {
+		t.Errorf("newLayout(%+v) = %v, expected %v", config, restored, shares)
+	}
+}
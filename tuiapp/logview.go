@@ -0,0 +1,100 @@
+package tuiapp
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	logExportFileFormat = "airspottr-20060102T150405.log"
+	logPageChrome       = 3 // title and border of the log box
+)
+
+// toggleLogPage shows the recent log output instead of the current aircraft, or goes back to them.
+func (m *model) toggleLogPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = logPage
+		m.logScroll = 0
+	case logPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	}
+}
+
+// processLogKey scrolls through the log output and exports it. The lines are counted from the
+// newest one, so that new output keeps showing unless scrolled back.
+func (m *model) processLogKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		m.scrollLog(1)
+	case "pgup":
+		m.scrollLog(m.logLinesShown() - 1)
+	case "down", "j":
+		m.scrollLog(-1)
+	case "pgdown":
+		m.scrollLog(-(m.logLinesShown() - 1))
+	case "e":
+		m.exportLog()
+	case "L", "esc":
+		m.toggleLogPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *model) scrollLog(lines int) {
+	maxScroll := max(0, len(m.logRing.Lines())-m.logLinesShown())
+	m.logScroll = min(max(0, m.logScroll+lines), maxScroll)
+}
+
+// exportLog writes the log output to a file named after the current time, in the working
+// directory.
+func (m *model) exportLog() {
+	path := m.dashboard.Clock().Now().Format(logExportFileFormat)
+	m.logExportErr = m.logRing.Export(path)
+	m.recordError("log export", m.logExportErr)
+	m.logExported = path
+}
+
+// logLinesShown is how many lines of log output fit on the page.
+func (m *model) logLinesShown() int {
+	headerHeight := m.layout.headerHeight() + m.bannerHeight()
+	return max(1, m.height-headerHeight-logPageChrome)
+}
+
+// viewLog shows the newest log output which fits on the page, or older output if scrolled back.
+func (m *model) viewLog() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	lines := m.logRing.Lines()
+	end := len(lines) - m.logScroll
+	start := max(0, end-m.logLinesShown())
+	shown := make([]string, 0, end-start)
+	for _, line := range lines[start:end] {
+		shown = append(shown, fitCell(line, m.width-2))
+	}
+
+	title := m.language.Sprintf("Log, %d lines (L to go back, e to export)", len(lines))
+	if dropped := m.logRing.Dropped(); dropped > 0 {
+		title = m.language.Sprintf("Log, %d lines, %d older dropped (L to go back, e to export)",
+			len(lines), dropped)
+	}
+	switch {
+	case m.logExportErr != nil:
+		title += fmt.Sprintf(" - export failed: %s", m.logExportErr)
+	case m.logExported != "":
+		title += " - exported to " + m.logExported
+	}
+
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(shown, "\n"),
+	))
+}
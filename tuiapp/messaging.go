@@ -20,21 +20,31 @@ func updateTick() tea.Cmd {
 
 type AircraftQueryTickMsg time.Time
 
-func aircraftQueryTick() tea.Cmd {
-	return tea.Every(
-		internal.AircraftUpdateInterval,
+// aircraftQueryTick asks for the next poll once the interval has passed. The interval may change
+// from poll to poll with adaptive polling.
+func aircraftQueryTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(
+		interval,
 		func(t time.Time) tea.Msg {
 			return AircraftQueryTickMsg(t)
 		},
 	)
 }
 
-type AircraftResponseMsg []internal.AircraftRecord
+// nextPollInterval returns how long to wait for the next poll, which is sooner while interesting
+// aircraft are nearby and later during the night with adaptive polling.
+func (m *model) nextPollInterval() time.Duration {
+	interesting := m.polling.Adaptive && m.dashboard.InterestingNearby(m.polling.NearbyRadius)
+	return m.polling.NextInterval(m.dashboard.Clock().Now(), interesting)
+}
+
+// AircraftResponseMsg carries the aircraft of a poll together with its error.
+type AircraftResponseMsg internal.PollResult
 
-func requestAircraftDataCmd(request *internal.Request) tea.Cmd {
+// waitForAircraft waits for the result of the next poll of the feed.
+func waitForAircraft(feed *internal.AircraftFeed) tea.Cmd {
 	return func() tea.Msg {
-		aircraftData := request.RequestAircraft()
-		return AircraftResponseMsg(aircraftData)
+		return AircraftResponseMsg(<-feed.Polls())
 	}
 }
 
@@ -46,3 +56,38 @@ func requestFlightRouteDataCmd(request *internal.Request, callsigns []string) te
 		return FlightRoutesResponseMsg(flightRoutes)
 	}
 }
+
+// PhotosResponseMsg carries the photos looked up for the requested registrations together with
+// the rare sightings which are waiting for them before being notified about.
+type PhotosResponseMsg struct {
+	registrations []string
+	photos        map[string]internal.PhotoRecord
+	rareSightings []internal.RareSighting
+}
+
+func requestPhotoDataCmd(
+	request *internal.Request,
+	registrations []string,
+	rareSightings []internal.RareSighting,
+) tea.Cmd {
+	return func() tea.Msg {
+		photos := request.RequestPhotosForRegistrations(registrations)
+		return PhotosResponseMsg{registrations: registrations, photos: photos, rareSightings: rareSightings}
+	}
+}
+
+// ReceiverStatsMsg carries the feeding statistics of the local receiver, or why there are none.
+type ReceiverStatsMsg struct {
+	stats internal.ReceiverStats
+	err   error
+}
+
+func requestReceiverStatsCmd(request *internal.Request) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := request.RequestReceiverStats()
+		return ReceiverStatsMsg{stats: stats, err: err}
+	}
+}
+
+// ReloadMsg asks to reload the datasets and the config, e.g. on SIGHUP.
+type ReloadMsg struct{}
@@ -2,13 +2,31 @@ package tuiapp
 
 import (
 	"fmt"
+	"log" //nolint:depguard // Don't feel like using slog for now.
 	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/dash"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+const (
+	// Extent of the traffic chart and how many of the busiest hours are listed on the stats page.
+	hoursShown        = 24
+	busiestHoursShown = 3
+	discoveryDays     = 30
+	stallBannerHeight = 1
+	// previousFlightsShown is how many earlier flights of an airframe the details view lists.
+	previousFlightsShown = 3
+	// frameBudget is how long updating the tables may take, about a frame at 60 frames per second.
+	frameBudget = 16 * time.Millisecond
 )
 
 // Model implements the bubbletea.Model interface, which requires three methods:
@@ -32,14 +50,66 @@ type model struct {
 	countryRarityTbl   autoFormatTable
 	// Pointer to active UI Element
 	selectedTable *autoFormatTable
+	// Arrangement of the panels, which is kept in the config file whenever it is adjusted.
+	layout layout
+	// groupOperators rolls up the operator rarity table to the operator groups of the config.
+	groupOperators bool
+	// familyTypes rolls up the type rarity table to the type families.
+	familyTypes bool
+	// nextRarityTable is the rarity table to update first, the one left out last if any.
+	nextRarityTable int
+	// Aircraft shown in the details view, copied from the current aircraft table.
+	detailAircraft *internal.AircraftRecord
+	// Input for the note on the aircraft shown in the details view, focused while editing.
+	noteInput textinput.Model
+	noteErr   error // noteErr is the error of the last attempt to save a note, nil if it succeeded.
+	// How the terminal shows photos in the details view, and the photos encoded for it by
+	// registration, along with the registrations from the least to the most recently shown.
+	graphics    graphicsProtocol
+	photoImages map[string]photoImage
+	photoOrder  []string
+	nextImageID uint32 // nextImageID is the kitty id of the last photo requested.
+	shownImage  uint32 // shownImage is the kitty id of the photo placed on the screen, 0 if none.
+	// Escape sequences which are written along with the view until graphicsFlushDelay has passed
+	// since the last of them was queued, e.g. to transmit a photo once.
+	pendingGraphics    string
+	graphicsGeneration int
+	// Feeding statistics of the local receiver, requested along with the aircraft.
+	receiverStats    internal.ReceiverStats
+	receiverStatsErr error
+	// High-priority events shown at the top of the screen, newest first.
+	alertBanners []alertBanner
+	// Outcome of the last reload of the datasets and the config, zero if there was none.
+	reloaded  time.Time
+	reloadErr error
+	// Log output of the request, dashboard and notifier, and where it was last exported to.
+	logRing      *internal.LogRing
+	logScroll    int // logScroll is how many lines the log page is scrolled back from the newest.
+	logExported  string
+	logExportErr error
+	// Errors of the session, oldest first, the last one of which is shown in the status bar.
+	errorHistory []errorEntry
+	// settingsCursor is the category of notifications selected on the settings page.
+	settingsCursor int
+	// How snapshots of the TUI are written, and where the last one went.
+	export       internal.ExportOptions
+	snapshotPath string
 	// Data
-	uiState    uiState
-	startTime  time.Time
-	lastUpdate time.Time
-	request    *internal.Request
-	dashboard  *internal.Dashboard
-	notify     *internal.Notify
-	options    internal.RequestOptions
+	uiState     uiState
+	startTime   time.Time
+	lastUpdate  time.Time
+	timeDisplay internal.TimeDisplay // timeDisplay formats times in the chosen zone and layout.
+	language    i18n.Language        // language is what labels and banners are shown in.
+	request     *internal.Request
+	feed        *internal.AircraftFeed // feed polls the request in the background.
+	dashboard   *internal.Dashboard
+	notify      *internal.Notify
+	options     internal.RequestOptions
+	polling     internal.PollingOptions // polling tells how long to wait for the next poll.
+	configPath  string
+	// Startup, which sets up request, dashboard and notify in the background.
+	startup         startupState
+	startupMessages <-chan tea.Msg
 }
 
 // Init calls the tickEvery function to set up a command that sends a TickMsg every second.
@@ -53,7 +123,7 @@ func (m *model) Init() tea.Cmd {
 	m.countryRarityTbl.table.Blur()
 	m.operatorRarityTbl.table.SetStyles(m.tableStyle)
 	m.operatorRarityTbl.table.Blur()
-	return tea.Batch(updateTick(), aircraftQueryTick(), requestAircraftDataCmd(m.request))
+	return tea.Batch(updateTick(), waitForStartup(m.startupMessages))
 }
 
 func (m *model) UnfocusSelectedTable() {
@@ -68,9 +138,16 @@ func (m *model) FocusSelectedTable() {
 	m.selectedTable.table.Focus()
 }
 
-// Update takes a tea.Msg as input and uses a type switch to handle different types of messages.
-// Each case in the switch statement corresponds to a specific message type.
+// Update takes a tea.Msg as input and hands it to update, then takes the photo of the details
+// view off the screen if it isn't shown anymore.
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:ireturn // required by interface
+	_, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.syncDetailImage())
+}
+
+// update uses a type switch to handle different types of messages.
+// Each case in the switch statement corresponds to a specific message type.
+func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:ireturn // like Update
 	switch thisMsg := msg.(type) {
 	// message is sent when the window size changes
 	// save to reflect the new dimensions of the terminal window.
@@ -82,14 +159,51 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:ireturn // r
 	case tea.KeyMsg:
 		return m, m.processKeyMsg(thisMsg)
 	case UpdateTickMsg:
+		if m.dashboard != nil {
+			m.expireAlertBanners(m.dashboard.Clock().Now())
+			if m.uiState == mainPage {
+				m.moveAircraftDistances()
+			}
+		}
 		return m, updateTick()
 	case AircraftQueryTickMsg:
-		return m, tea.Batch(requestAircraftDataCmd(m.request), aircraftQueryTick())
+		m.feed.Poll()
+		return m, tea.Batch(
+			requestReceiverStatsCmd(m.request),
+			aircraftQueryTick(m.nextPollInterval()))
 	case AircraftResponseMsg:
-		return m, m.processAircraftResponse(thisMsg)
+		return m, tea.Batch(m.processAircraftResponse(thisMsg), waitForAircraft(m.feed))
 	case FlightRoutesResponseMsg:
 		m.processFlightRouteResponse(thisMsg)
 		return m, nil
+	case PhotosResponseMsg:
+		m.dashboard.AssignPhotos(thisMsg.registrations, thisMsg.photos)
+		m.notify.EmitRarityNotifications(thisMsg.rareSightings)
+		return m, m.requestDetailImage()
+	case PhotoImageMsg:
+		return m, m.receivePhotoImage(thisMsg)
+	case graphicsSentMsg:
+		if thisMsg.generation == m.graphicsGeneration {
+			m.pendingGraphics = ""
+		}
+		return m, nil
+	case ErrorMsg:
+		m.recordError(thisMsg.source, thisMsg.err)
+		return m, nil
+	case StartupProgressMsg:
+		m.startup.datasetsLoaded = thisMsg.loaded
+		m.startup.datasetsTotal = thisMsg.total
+		m.startup.lastDataset = thisMsg.dataset
+		return m, waitForStartup(m.startupMessages)
+	case StartupDoneMsg:
+		return m, m.processStartupDone(thisMsg)
+	case ReloadMsg:
+		m.reload()
+		return m, nil
+	case ReceiverStatsMsg:
+		m.receiverStats = thisMsg.stats
+		m.receiverStatsErr = thisMsg.err
+		return m, nil
 	}
 
 	// If the message type does not match any of the handled cases, the model is returned unchanged,
@@ -98,50 +212,79 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint:ireturn // r
 }
 
 func (m *model) resizeTables() {
-	headerHeight := 8 // TODO: Make this cleaner and clearer.
+	if m.notify == nil {
+		return // still starting up
+	}
+	headerHeight := m.layout.headerHeight() + m.bannerHeight()
 
 	m.currentAircraftTbl.SetHeight(m.height - headerHeight)
-	m.typeRarityTbl.SetHeight(m.height - headerHeight)
-	m.operatorRarityTbl.SetHeight(m.height - headerHeight)
-	m.countryRarityTbl.SetHeight(m.height - headerHeight)
+	// The rarity tables share the page with lines describing the rarity scorer and the traffic.
+	statsHeight := m.layout.statsHeight()
+	m.typeRarityTbl.SetHeight(m.height - headerHeight - statsHeight)
+	m.operatorRarityTbl.SetHeight(m.height - headerHeight - statsHeight)
+	m.countryRarityTbl.SetHeight(m.height - headerHeight - statsHeight)
 
 	// TODO: Set type column width of current aircraft table to variable size.
 
 	// Adjust widths of all tables
-	// leftSideWidthRatio := 0.5
-	// leftSideWidth := int(float64(m.width) * leftSideWidthRatio)
-	leftSideWidth := m.width - 1
-	rightSideWidth := m.width // - leftSideWidth
-	rightSideTableCount := 3.0
-	rightSideTableRatio := 1.0 / rightSideTableCount
-	rightSideTableWidth := int(float64(rightSideWidth) * rightSideTableRatio)
-
-	caErr := m.currentAircraftTbl.resize(leftSideWidth)
+	caErr := m.currentAircraftTbl.resize(m.width - 1)
 	if caErr != nil {
-		m.notify.Stdout.Panicf("%s", caErr)
+		log.Panicf("%s", caErr)
 	}
-	trErr := m.typeRarityTbl.resize(rightSideTableWidth)
+	widths := m.layout.tableWidths(m.width)
+	trErr := m.typeRarityTbl.resize(widths[0])
 	if trErr != nil {
-		m.notify.Stdout.Panicf("%s", trErr)
+		log.Panicf("%s", trErr)
 	}
-	orErr := m.operatorRarityTbl.resize(rightSideTableWidth)
+	orErr := m.operatorRarityTbl.resize(widths[1])
 	if orErr != nil {
-		m.notify.Stdout.Panicf("%s", orErr)
+		log.Panicf("%s", orErr)
 	}
-	crErr := m.countryRarityTbl.resize(
-		rightSideWidth -
-			rightSideTableWidth -
-			rightSideTableWidth -
-			2 - len(m.countryRarityTbl.table.Columns()))
+	crErr := m.countryRarityTbl.resize(widths[2] - 2 - len(m.countryRarityTbl.table.Columns()))
 	if crErr != nil {
-		m.notify.Stdout.Panicf("%s", crErr)
+		log.Panicf("%s", crErr)
 	}
 }
 
 func (m *model) processKeyMsg(msg tea.KeyMsg) tea.Cmd {
+	if m.noteInput.Focused() {
+		return m.processNoteInput(msg)
+	}
+	if m.uiState == startupPage {
+		if key := msg.String(); key == "q" || key == "ctrl+c" {
+			return tea.Quit
+		}
+		return nil
+	}
+	if m.uiState == logPage {
+		return m.processLogKey(msg)
+	}
+	if m.uiState == errorPage {
+		return m.processErrorKey(msg)
+	}
+	if m.uiState == favouritesPage {
+		return m.processFavouritesKey(msg)
+	}
+	if m.uiState == settingsPage {
+		return m.processSettingsKey(msg)
+	}
+	if m.uiState == notificationsPage {
+		return m.processNotificationsKey(msg)
+	}
+	if m.uiState == followPage {
+		return m.processFollowKey(msg)
+	}
+	if m.uiState == achievementsPage {
+		return m.processAchievementsKey(msg)
+	}
+
 	switch msg.String() {
 	// Toggles the focus state of the aircraft table
 	case "esc":
+		if m.uiState == aircraftDetails {
+			m.closeAircraftDetails()
+			return nil
+		}
 		if m.selectedTable.table.Focused() {
 			m.UnfocusSelectedTable()
 		} else {
@@ -150,28 +293,98 @@ func (m *model) processKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	// Moves the focus up in the aircraft table if the table is focused.
 	case "up", "k":
 		if m.selectedTable.table.Focused() {
-			m.selectedTable.table.MoveUp(1)
+			m.selectedTable.moveUp(1)
 		}
 	case "pgup":
 		if m.selectedTable.table.Focused() {
-			m.selectedTable.table.MoveUp(m.selectedTable.table.Height() - 1)
+			m.selectedTable.moveUp(m.selectedTable.pageSize() - 1)
 		}
 	// Moves the focus down in the aircraft table if the table is focused.
 	case "down", "j":
 		if m.selectedTable.table.Focused() {
-			m.selectedTable.table.MoveDown(1)
+			m.selectedTable.moveDown(1)
 		}
 	case "pgdown":
 		if m.selectedTable.table.Focused() {
-			m.selectedTable.table.MoveDown(m.selectedTable.table.Height() - 1)
+			m.selectedTable.moveDown(m.selectedTable.pageSize() - 1)
 		}
 	case "left", "h":
 		m.selectTableToTheLeft()
 	case "right", "l":
 		m.selectTableToTheRight()
+	// Show or hide the details of the selected aircraft.
+	case "enter":
+		return m.toggleAircraftDetails()
 	// Switch between main and global view
 	case " ": // space
 		m.toggleGlobalView()
+	// Show or hide the feeding statistics of the local receiver.
+	case "r":
+		m.toggleReceiverStats()
+	// Show the recent log output.
+	case "L":
+		m.toggleLogPage()
+	// Show the errors of the session.
+	case "E":
+		m.toggleErrorPage()
+	// Write a snapshot of the current aircraft and the stats to a file.
+	case "x":
+		m.saveSnapshot()
+	// Roll up the operators to their groups, or show them on their own again.
+	case "g":
+		if m.uiState == globalStats {
+			m.toggleOperatorGroups()
+		}
+	// Roll up the types to their families, or show them on their own again.
+	case "f":
+		if m.uiState == globalStats {
+			m.toggleTypeFamilies()
+		}
+	// Reload the datasets and the config.
+	case "R":
+		m.reload()
+	// Switch to the next available data source.
+	case "s":
+		m.switchToNextSource()
+	// Edit the note on the aircraft shown in the details view.
+	case "n":
+		return m.editNote()
+	// Put the aircraft shown in the details view on the watchlist or take it off.
+	case "w":
+		m.toggleWatchlist()
+	// Make the selected aircraft a favourite or take it off the favourites.
+	case "*":
+		m.toggleFavourite()
+	// Show the favourite aircraft.
+	case "F":
+		m.toggleFavouritesPage()
+	// Switch categories of desktop notifications on or off.
+	case "N":
+		m.toggleSettingsPage()
+	// Show the latest notifications, to review the missed ones.
+	case "A":
+		m.toggleNotificationsPage()
+	// Show the aircraft followed with --follow and its track.
+	case "T":
+		m.toggleFollowPage()
+	// Show the achievements and how far each of them has come.
+	case "a":
+		m.toggleAchievementsPage()
+	// Collapse or expand the header and the statistics above the rarity tables.
+	case "H":
+		m.layout.hideHeader = !m.layout.hideHeader
+		m.adjustLayout()
+	case "S":
+		m.layout.hideStats = !m.layout.hideStats
+		m.adjustLayout()
+	// Narrow or widen the selected rarity table, or split the width evenly again.
+	case "[":
+		m.resizeSelectedTable(-tableShareStep)
+	case "]":
+		m.resizeSelectedTable(tableShareStep)
+	case "=":
+		m.layout.shares = [rarityTableCount]float64{}
+		m.adjustLayout()
 	// Quits the program by returning the tea.Quit command.
 	case "q", "ctrl+c":
 		return tea.Quit
@@ -182,24 +395,66 @@ func (m *model) processKeyMsg(msg tea.KeyMsg) tea.Cmd {
 // processAircraftResponse processes new data from the ADS-B data source and
 // updates the tables accordingly.
 func (m *model) processAircraftResponse(msg AircraftResponseMsg) tea.Cmd {
-	m.lastUpdate = time.Now()
-	aircraftRecords := []internal.AircraftRecord(msg)
-	m.dashboard.ProcessAircraftRecords(aircraftRecords)
+	if m.uiState == startupPage {
+		m.uiState = mainPage
+		if m.dashboard.Follow != nil {
+			m.uiState = followPage
+		}
+		m.resizeTables()
+	}
+
+	// Failed polls don't count as updates, so that stale aircraft are recognisable as such.
+	pollErr := msg.Err
+	if pollErr == nil {
+		m.lastUpdate = m.now()
+	}
+	m.recordError("aircraft poll", pollErr)
+	wasStalled, _ := m.notify.FeedStalled()
+	m.notify.CheckFeed(msg.FailingSince, pollErr, m.dashboard.Clock().Now())
+	if isStalled, _ := m.notify.FeedStalled(); isStalled != wasStalled {
+		m.resizeTables() // to make room for the banner or take it back
+	}
+
+	m.dashboard.ProcessPoll(msg.Aircraft, pollErr)
+	m.notify.TeeAircraftUpdates(m.dashboard)
+	m.notify.EmitRuleAlerts(m.dashboard.RuleMatches)
+	m.notify.EmitNoteNotifications(m.dashboard.NoteSightings)
+	m.notify.EmitIdentityChanges(m.dashboard.IdentityChanges, m.dashboard.Clock().Now())
+	m.notify.EmitAreaMovements(m.dashboard.AreaMovements, m.dashboard.Clock().Now())
+	m.notify.EmitFirstSightings(m.dashboard.FirstSightings)
+	m.notify.EmitPeak(m.dashboard.NewPeak)
+	m.notify.EmitMachAlerts(m.dashboard.MachAlerts, m.dashboard.Clock().Now())
+	m.notify.EmitFollowEvents(m.dashboard.FollowEvents, m.dashboard.Clock().Now())
+	m.notify.EmitAchievements(m.dashboard.UnlockedAchievements, m.dashboard.Clock().Now())
+	m.notify.EmitNotableSightings(m.dashboard.NotableSightings)
+	m.notify.EmitCirclingAlerts(m.dashboard.CirclingAlerts, m.dashboard.Clock().Now())
+	m.notify.EmitGoArounds(m.dashboard.GoAroundAlerts, m.dashboard.Clock().Now())
+	m.showAlertBanners(
+		m.dashboard.PriorityEvents(m.language, m.dashboard.Clock().Now()), m.dashboard.Clock().Now())
+
 	// Send out notifications for any rare sightings that occurred.
-	m.notify.EmitRarityNotifications(m.dashboard.RareSightings)
+	// If photos of them have to be looked up first, the notifications are sent once they arrive.
+	var photoCmd tea.Cmd
+	m.dashboard.AssignCachedPhotos()
+	registrationsWithoutPhoto := m.dashboard.RegistrationsWithoutPhoto()
+	if len(registrationsWithoutPhoto) > 0 {
+		photoCmd = requestPhotoDataCmd(m.request, registrationsWithoutPhoto, m.dashboard.RareSightings)
+	} else {
+		m.notify.EmitRarityNotifications(m.dashboard.RareSightings)
+	}
 
 	callsignsWithoutRoute := m.dashboard.AssignRouteToCallsigns()
 	if callsignsWithoutRoute != nil {
 		// Get route data for new or previously unknown flights.
 		// This returns early and delays the table update until the route data is available.
-		return requestFlightRouteDataCmd(m.request, callsignsWithoutRoute)
+		return tea.Batch(photoCmd, requestFlightRouteDataCmd(m.request, callsignsWithoutRoute))
 	}
 
 	// Update all tables with the new information.
 	m.updateAllTables()
 
-	// Since the aircraft requests come on a regular schedule, there is nothing to return now.
-	return nil
+	// Since the aircraft requests come on a regular schedule, there is nothing else to return now.
+	return photoCmd
 }
 
 func (m *model) processFlightRouteResponse(msg FlightRoutesResponseMsg) {
@@ -208,51 +463,112 @@ func (m *model) processFlightRouteResponse(msg FlightRoutesResponseMsg) {
 	m.updateAllTables()
 }
 
+// updateAllTables brings all tables up to date with the dashboard. The aircraft are always
+// updated, the rarity tables take turns as long as the frame budget lasts, so that the TUI stays
+// responsive in busy airspace. Those left out are updated next time.
 func (m *model) updateAllTables() {
-	// Update current aircraft table.
-	currentAircraftRows := make([]table.Row, len(m.dashboard.CurrentAircraft))
-	for idx, aircraft := range m.dashboard.CurrentAircraft {
-		aircraftType := m.dashboard.IcaoToAircraft[aircraft.IcaoType].Make
-		flightRoute, ok := m.dashboard.CachedFlightRoutes[aircraft.GetFlightNoAsStr()]
-		if !ok {
-			flightRoute = internal.GetDefaultFlightrouteRecord()
+	start := time.Now()
+	m.updateAircraftTable()
+
+	updates := []func(){m.updateTypeRarityTable, m.updateOperatorRarityTable, m.updateCountryRarityTable}
+	for range updates {
+		if time.Since(start) > frameBudget {
+			break
 		}
+		updates[m.nextRarityTable%len(updates)]()
+		m.nextRarityTable = (m.nextRarityTable + 1) % len(updates)
+	}
+}
 
-		// Filter out aircraft where both flight number and type are unknown.
+// updateAircraftTable lists the current aircraft, favourites first, leaving out those where both
+// flight number and type are unknown.
+func (m *model) updateAircraftTable() {
+	aircraftKeys := make([]string, 0, len(m.dashboard.CurrentAircraft))
+	aircraftRows := make([]table.Row, 0, len(m.dashboard.CurrentAircraft))
+	aircraftTints := make([]lipgloss.TerminalColor, 0, len(m.dashboard.CurrentAircraft))
+	tiers := m.dashboard.Tiers()
+	for idx := range m.dashboard.CurrentAircraft {
+		aircraft := &m.dashboard.CurrentAircraft[idx]
+		aircraftType := m.dashboard.IcaoToAircraft[aircraft.IcaoType].Make
 		if aircraft.GetFlightNoAsStr() == "" && aircraftType == "" {
 			continue
 		}
 
-		currentAircraftRows[idx] = aircraftToRow(&aircraft, flightRoute)
+		flightRoute, ok := m.dashboard.CachedFlightRoutes[aircraft.GetFlightNoAsStr()]
+		if !ok {
+			flightRoute = internal.GetDefaultFlightrouteRecord()
+		}
+		aircraftKeys = append(aircraftKeys, aircraft.Hex)
+		estimated, isEstimated := m.dashboard.EstimatedDistance(aircraft, m.sincePoll())
+		row := aircraftToRow(aircraft, flightRoute, estimated, isEstimated)
+		tier, _ := tiers.Tier(aircraft.CachedDist)
+		tint := m.theme.tierColor(tier)
+		// Notable aircraft stand out from the others no matter how close they are.
+		if _, isNotable := m.dashboard.Notable(aircraft.Hex); isNotable {
+			row[flightColumn] = notableMarker + row[flightColumn]
+			tint = m.theme.Highlight
+		}
+		aircraftRows = append(aircraftRows, row)
+		aircraftTints = append(aircraftTints, tint)
 	}
-	m.currentAircraftTbl.table.SetRows(currentAircraftRows)
+	m.pinFavourites(aircraftKeys, aircraftRows, aircraftTints)
+	m.currentAircraftTbl.setRows(aircraftKeys, aircraftRows)
+	m.currentAircraftTbl.setTints(aircraftTints)
+}
 
-	// Update current type rarity table.
-	// typeRarities := m.dashboard.GetTypeRarities()
-	typeRarities := internal.GetSortedCountsForProperty(m.dashboard.SeenTypeCount)
-	typeRarityRows := make([]table.Row, len(typeRarities))
-	for typeIdx := range typeRarities {
-		typeRarityRows[typeIdx] = propertyCountToRow(typeRarities[typeIdx])
+// moveAircraftDistances moves the estimated distances of the aircraft table on between polls,
+// leaving the other cells and the order of the rows as they were.
+func (m *model) moveAircraftDistances() {
+	aircraftByHex := make(map[string]*internal.AircraftRecord, len(m.dashboard.CurrentAircraft))
+	for idx := range m.dashboard.CurrentAircraft {
+		aircraftByHex[m.dashboard.CurrentAircraft[idx].Hex] = &m.dashboard.CurrentAircraft[idx]
 	}
-	m.typeRarityTbl.table.SetRows(typeRarityRows)
+	sincePoll := m.sincePoll()
+	m.currentAircraftTbl.setColumn(distanceColumn, func(hex string) (string, bool) {
+		aircraft, ok := aircraftByHex[hex]
+		if !ok {
+			return "", false
+		}
+		estimated, isEstimated := m.dashboard.EstimatedDistance(aircraft, sincePoll)
+		return aircraftDistance(aircraft, estimated, isEstimated), true
+	})
+}
 
-	// Update current operator rarity table.
-	// operatorRarities := m.dashboard.GetOperatorRarities()
-	operatorRarities := internal.GetSortedCountsForProperty(m.dashboard.SeenOperatorCount)
-	operatorRarityRows := make([]table.Row, len(operatorRarities))
-	for operatorIdx := range operatorRarities {
-		operatorRarityRows[operatorIdx] = propertyCountToRow(operatorRarities[operatorIdx])
+func (m *model) updateTypeRarityTable() {
+	elapsed := m.dashboard.Clock().Now().Sub(m.startTime)
+	firstSeen := m.dashboard.Discovery.FirstSeen("type")
+	if m.familyTypes {
+		m.typeRarityTbl.setRows(propertyCountRows(
+			m.dashboard.FamilyTypeCount(),
+			elapsed,
+			m.dashboard.FamilyFirstSeen(firstSeen),
+			m.timeDisplay.Location()))
+	} else {
+		m.typeRarityTbl.setRows(propertyCountRows(
+			m.dashboard.SeenTypeCount, elapsed, firstSeen, m.timeDisplay.Location()))
 	}
-	m.operatorRarityTbl.table.SetRows(operatorRarityRows)
+}
 
-	// Update current type rarity table.
-	// countryRarities := m.dashboard.GetCountryRarities()
-	countryRarities := internal.GetSortedCountsForProperty(m.dashboard.SeenCountryCount)
-	countryRarityRows := make([]table.Row, len(countryRarities))
-	for countryIdx := range countryRarities {
-		countryRarityRows[countryIdx] = propertyCountToRow(countryRarities[countryIdx])
+func (m *model) updateOperatorRarityTable() {
+	elapsed := m.dashboard.Clock().Now().Sub(m.startTime)
+	firstSeen := m.dashboard.Discovery.FirstSeen("operator")
+	if m.groupOperators {
+		m.operatorRarityTbl.setRows(propertyCountRows(
+			m.dashboard.GroupedOperatorCount(),
+			elapsed,
+			m.dashboard.GroupedFirstSeen(firstSeen),
+			m.timeDisplay.Location()))
+	} else {
+		m.operatorRarityTbl.setRows(propertyCountRows(
+			m.dashboard.SeenOperatorCount, elapsed, firstSeen, m.timeDisplay.Location()))
 	}
-	m.countryRarityTbl.table.SetRows(countryRarityRows)
+}
+
+func (m *model) updateCountryRarityTable() {
+	elapsed := m.dashboard.Clock().Now().Sub(m.startTime)
+	m.countryRarityTbl.setRows(propertyCountRows(
+		m.dashboard.SeenCountryCount, elapsed, m.dashboard.Discovery.FirstSeen("country"),
+		m.timeDisplay.Location()))
 }
 
 func (m *model) selectTableToTheLeft() {
@@ -305,12 +621,181 @@ func (m *model) toggleGlobalView() {
 		m.selectedTable.table.Blur()
 		m.selectedTable = &m.currentAircraftTbl
 		m.selectedTable.table.Focus()
+	case aircraftDetails, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	default:
+	}
+}
+
+// toggleOperatorGroups switches the operator rarity table between the operators and their groups.
+func (m *model) toggleOperatorGroups() {
+	m.groupOperators = !m.groupOperators
+	title := m.language.T("Operator")
+	if m.groupOperators {
+		title = m.language.T("Operator group")
+	}
+	columns := m.operatorRarityTbl.table.Columns()
+	columns[len(columns)-1].Title = title
+	m.operatorRarityTbl.table.SetColumns(columns)
+	m.updateOperatorRarityTable()
+}
+
+// toggleTypeFamilies switches the type rarity table between the types and their families.
+func (m *model) toggleTypeFamilies() {
+	m.familyTypes = !m.familyTypes
+	title := m.language.T("Type")
+	if m.familyTypes {
+		title = m.language.T("Type family")
+	}
+	columns := m.typeRarityTbl.table.Columns()
+	columns[len(columns)-1].Title = title
+	m.typeRarityTbl.table.SetColumns(columns)
+	m.updateTypeRarityTable()
+}
+
+// reload swaps in the datasets and config as they are on disk now. The outcome is shown on the
+// stats page.
+func (m *model) reload() {
+	if m.dashboard == nil {
+		return // still starting up, so everything is loaded fresh anyway
+	}
+	m.reloaded = m.dashboard.Clock().Now()
+	m.reloadErr = internal.Reload(m.configPath, m.dashboard, m.notify)
+	m.recordError("reload", m.reloadErr)
+}
+
+// toggleReceiverStats shows the feeding statistics of the local receiver instead of the current
+// aircraft, or goes back to them.
+func (m *model) toggleReceiverStats() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = receiverStats
+	case receiverStats:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	}
+}
+
+// switchToNextSource requests aircraft from the data source following the first active one in the
+// list of available sources. Everything spotted so far is kept.
+func (m *model) switchToNextSource() {
+	available := m.request.AvailableSources()
+	if len(available) == 0 {
+		return
+	}
+
+	next := available[0]
+	active := m.request.ActiveSources()
+	for idx, name := range available {
+		if len(active) > 0 && name == active[0] {
+			next = available[(idx+1)%len(available)]
+			break
+		}
+	}
+
+	// Available sources are known to be valid, so switching to them can't fail.
+	_ = m.request.SwitchSources([]string{next})
+}
+
+// toggleAircraftDetails opens the details of the aircraft selected in the current aircraft table,
+// or closes them if they are already open.
+// If there is no photo of the selected aircraft yet, it will be looked up.
+func (m *model) toggleAircraftDetails() tea.Cmd {
+	switch m.uiState {
 	case aircraftDetails:
+		m.closeAircraftDetails()
+		return nil
+	case mainPage:
+		if !m.currentAircraftTbl.table.Focused() {
+			return nil
+		}
+		hex, ok := m.currentAircraftTbl.selectedKey()
+		if !ok {
+			return nil
+		}
+		idx := slices.IndexFunc(m.dashboard.CurrentAircraft, func(aircraft internal.AircraftRecord) bool {
+			return aircraft.Hex == hex
+		})
+		if idx < 0 {
+			return nil
+		}
+		aircraft := m.dashboard.CurrentAircraft[idx]
+		m.detailAircraft = &aircraft
+		m.uiState = aircraftDetails
+
+		registration := aircraft.Registration
+		if registration == "" {
+			return nil
+		}
+		if m.dashboard.GetPhotoForRegistration(registration) != nil {
+			return m.requestDetailImage()
+		}
+		return requestPhotoDataCmd(m.request, []string{registration}, nil)
+	case globalStats, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, notificationsPage, followPage, achievementsPage:
+	}
+	return nil
+}
+
+// editNote starts editing the note on the aircraft shown in the details view.
+func (m *model) editNote() tea.Cmd {
+	if m.uiState != aircraftDetails || m.detailAircraft == nil {
+		return nil
+	}
+	note, _ := m.dashboard.Notes.Get(m.detailAircraft.Hex)
+	m.noteInput.SetValue(note.Text)
+	m.noteInput.CursorEnd()
+	return m.noteInput.Focus()
+}
+
+// processNoteInput passes keys to the note input while it is being edited.
+// Enter saves the note, esc discards the changes.
+func (m *model) processNoteInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type { //nolint:exhaustive // all other keys are typed into the note
+	case tea.KeyEnter:
+		m.noteInput.Blur()
+		note, _ := m.dashboard.Notes.Get(m.detailAircraft.Hex)
+		note.Text = m.noteInput.Value()
+		m.saveNote(note)
+		return nil
+	case tea.KeyEsc:
+		m.noteInput.Blur()
+		return nil
 	default:
+		var cmd tea.Cmd
+		m.noteInput, cmd = m.noteInput.Update(msg)
+		return cmd
+	}
+}
+
+// toggleWatchlist puts the aircraft shown in the details view on the watchlist or takes it off.
+func (m *model) toggleWatchlist() {
+	if m.uiState != aircraftDetails || m.detailAircraft == nil {
+		return
 	}
+	note, _ := m.dashboard.Notes.Get(m.detailAircraft.Hex)
+	note.Watch = !note.Watch
+	m.saveNote(note)
+}
+
+func (m *model) saveNote(note internal.Note) {
+	note.Hex = m.detailAircraft.Hex
+	note.Updated = time.Now()
+	m.noteErr = m.dashboard.Notes.Set(note)
+	m.recordError("saving note", m.noteErr)
+}
+
+func (m *model) closeAircraftDetails() {
+	m.uiState = mainPage
+	m.detailAircraft = nil
 }
 
 func (m *model) View() string {
+	if m.uiState == startupPage {
+		return m.baseStyle.Width(m.width).Height(m.height).Render(m.viewStartup())
+	}
+
 	// Sets the width of the column to the width of the terminal (m.width) and adds padding of 1 unit
 	// on the top.
 	// Render is a method from the lipgloss package that applies the defined style and returns
@@ -322,25 +807,66 @@ func (m *model) View() string {
 	case mainPage:
 		tableContent = m.viewAircraft()
 	case globalStats:
-		tableContent = lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			m.viewTypeRarity(),
-			m.viewOperatorRarity(),
-			m.viewCountryRarity(),
+		var panels []string
+		if !m.layout.hideStats {
+			panels = append(panels,
+				m.viewRarityScorer(),
+				m.viewTraffic(),
+				m.viewAltitudeBands(),
+				m.viewMessageTypes(),
+				m.viewWinds(),
+				m.viewTemperatures(),
+				m.viewDiscovery(),
+				m.viewSources())
+		}
+		tableContent = lipgloss.JoinVertical(
+			lipgloss.Left,
+			append(panels, lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				m.viewTypeRarity(),
+				m.viewOperatorRarity(),
+				m.viewCountryRarity(),
+			))...,
 		)
 	case aircraftDetails:
+		tableContent = m.viewAircraftDetails()
+	case receiverStats:
+		tableContent = m.viewReceiverStats()
+	case logPage:
+		tableContent = m.viewLog()
+	case errorPage:
+		tableContent = m.viewErrors()
+	case favouritesPage:
+		tableContent = m.viewFavourites()
+	case settingsPage:
+		tableContent = m.viewSettings()
+	case notificationsPage:
+		tableContent = m.viewNotifications()
+	case followPage:
+		tableContent = m.viewFollow()
+	case achievementsPage:
+		tableContent = m.viewAchievements()
+	case startupPage: // rendered on its own above
+	}
+	rows := []string{column(tableContent)}
+	if !m.layout.hideHeader {
+		rows = append([]string{column(m.viewHeader())}, rows...)
+	}
+	if banner := m.viewStallBanner(); banner != "" {
+		rows = append([]string{banner}, rows...)
+	}
+	if banner := m.viewAlertBanner(); banner != "" {
+		rows = append([]string{banner}, rows...)
+	}
+	if bar := m.viewErrorBar(); bar != "" {
+		rows = append(rows, bar)
 	}
 	content := m.baseStyle.
 		Width(m.width).
 		Height(m.height).
-		Render(
-			lipgloss.JoinVertical(lipgloss.Left,
-				column(m.viewHeader()),
-				column(tableContent),
-			),
-		)
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
 
-	return content
+	return m.pendingGraphics + content
 }
 
 // Uses lipgloss.JoinVertical and lipgloss.JoinHorizontal to arrange the header content.
@@ -370,8 +896,10 @@ func (m *model) viewHeader() string {
 		return fmt.Sprintf("%s %s ", listItemKey(key), listItemValue)
 	}
 
-	highest := m.dashboard.Highest
-	fastest := m.dashboard.Fastest
+	records := m.dashboard.Records
+	today := records.Day(m.dashboard.Clock().Now())
+	highest := records.Session().Highest
+	fastest := records.Session().Fastest
 
 	if highest == nil || fastest == nil {
 		return ""
@@ -379,7 +907,7 @@ func (m *model) viewHeader() string {
 
 	minutesInHour := 60.0
 	secsInMinute := 60.0
-	tSince := time.Since(m.startTime)
+	tSince := m.now().Sub(m.startTime)
 	hours := tSince.Hours()
 	mins := math.Mod(math.Floor(tSince.Minutes()), minutesInHour)
 	secs := math.Mod(math.Floor(tSince.Seconds()), secsInMinute)
@@ -390,25 +918,37 @@ func (m *model) viewHeader() string {
 				lipgloss.JoinVertical(lipgloss.Left,
 					fmt.Sprintf("   Location %.3f, %.3f", m.dashboard.Lat, m.dashboard.Lon),
 					fmt.Sprintf("     UpTime %.0f Hr %02.0f Min %02.0f Sec", hours, mins, secs),
-					fmt.Sprintf("Last Update %02.0f seconds ago", time.Since(m.lastUpdate).Seconds())),
+					fmt.Sprintf("Last Update %02.0f seconds ago", m.now().Sub(m.lastUpdate).Seconds()),
+					fmt.Sprintf("       Peak %d aircraft, all-time %d",
+						m.dashboard.Peaks.Session().Aircraft,
+						m.dashboard.Peaks.AllTime().Aircraft),
+					fmt.Sprintf("      Today %d aircraft, %d go-arounds",
+						m.dashboard.DailyAircraft.Today(m.dashboard.Clock().Now()),
+						m.dashboard.GoArounds.Today(m.dashboard.Clock().Now())),
+					fmt.Sprintf("   Baseline %s", m.dashboard.Warmup())),
 			),
 			list.Border(lipgloss.RoundedBorder()).Render(
 				lipgloss.JoinVertical(lipgloss.Left,
 					listHeader("Highest"),
 					lipgloss.JoinHorizontal(
 						lipgloss.Left,
-						listItem("ALT", highest.GetAltitudeAsStr()),
-						listItem("FNO", highest.GetFlightNoAsStr()),
+						listItem("ALT", fmt.Sprintf("%5.0f", highest.Value)),
+						listItem("FNO", highest.Flight),
 						listItem("REG", highest.Registration),
 						listItem("TID", m.dashboard.IcaoToAircraft[highest.IcaoType].Make),
+						listItem("DAY", viewRecordValue(today.Highest)),
+						listItem("EVER", viewRecordValue(records.AllTime().Highest)),
 					),
 					listHeader("Fastest"),
 					lipgloss.JoinHorizontal(
 						lipgloss.Left,
-						listItem("SPD", fmt.Sprintf("%5.0f", fastest.GroundSpeed)),
-						listItem("FNO", fastest.GetFlightNoAsStr()),
+						listItem("SPD", fmt.Sprintf("%5.0f", fastest.Value)),
+						listItem("FNO", fastest.Flight),
 						listItem("REG", fastest.Registration),
 						listItem("TID", m.dashboard.IcaoToAircraft[fastest.IcaoType].Make),
+						listItem("DAY", viewRecordValue(today.Fastest)),
+						listItem("EVER", viewRecordValue(records.AllTime().Fastest)),
+						listItem("MACH", viewMachRecord(records.AllTime().FastestMach)),
 					),
 				),
 			),
@@ -416,18 +956,452 @@ func (m *model) viewHeader() string {
 	)
 }
 
+// viewAirspeed shows the indicated and true airspeed and the Mach number, as far as the aircraft
+// reports them. Unlike the ground speed they aren't inflated by a tailwind.
+func viewAirspeed(aircraft *internal.AircraftRecord) string {
+	var speeds []string
+	if aircraft.Ias > 0 {
+		speeds = append(speeds, fmt.Sprintf("IAS %.0f kt", aircraft.Ias))
+	}
+	if aircraft.Tas > 0 {
+		speeds = append(speeds, fmt.Sprintf("TAS %.0f kt", aircraft.Tas))
+	}
+	if aircraft.Mach > 0 {
+		speeds = append(speeds, fmt.Sprintf("Mach %.2f", aircraft.Mach))
+	}
+	if len(speeds) == 0 {
+		return "n/a"
+	}
+	return strings.Join(speeds, ", ")
+}
+
+// viewMachRecord shows the Mach number of the record and who set it, n/a if there is none yet.
+func viewMachRecord(record *internal.FlightRecord) string {
+	if record == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f %s", record.Value, record.Flight)
+}
+
+// viewRecordValue shows the altitude or speed of the record, n/a if there is none yet.
+func viewRecordValue(record *internal.FlightRecord) string {
+	if record == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%5.0f", record.Value)
+}
+
+// viewStallBanner warns prominently that the feed stalled and the aircraft shown are stale, if it
+// did. It is empty otherwise.
+func (m *model) viewStallBanner() string {
+	stalled, since := m.notify.FeedStalled()
+	if !stalled {
+		return ""
+	}
+
+	reason := "unknown error"
+	if _, pollErr := m.request.LastPoll(); pollErr != nil {
+		reason = pollErr.Error()
+	}
+	banner := fmt.Sprintf(
+		" FEED STALLED: no aircraft data since %s (%s), aircraft shown are stale: %s",
+		m.timeDisplay.Format(since),
+		m.now().Sub(since).Round(time.Second),
+		reason)
+	return m.baseStyle.
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Background(m.theme.Red).
+		Width(m.width).
+		MaxHeight(stallBannerHeight).
+		Render(banner)
+}
+
 func (m *model) viewAircraft() string {
-	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.currentAircraftTbl.table.View())
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.currentAircraftTbl.view())
+}
+
+// viewRarityScorer shows the active rarity scoring strategy and its parameters.
+func (m *model) viewRarityScorer() string {
+	scorer := m.dashboard.RarityScorer()
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	decay := "all-time counts"
+	if halfLife := m.dashboard.StatsHalfLife(); halfLife > 0 {
+		decay = "counts decaying with half life " + halfLife.String()
+	}
+	comparison := ""
+	if compared := m.dashboard.ScorerComparison(); compared != nil {
+		comparison = fmt.Sprintf(
+			"  %s %d vs. %d notifications (%d by both)",
+			keyStyle.Render(m.language.Sprintf("Compared to %s:", compared.B.Scorer)),
+			compared.A.Notifications,
+			compared.B.Notifications,
+			compared.Both)
+	}
+	return fmt.Sprintf(
+		" %s %s (%s), %s%s",
+		keyStyle.Render(m.language.T("Rarity:")),
+		scorer.Name(),
+		scorer.Parameters(),
+		decay,
+		comparison)
+}
+
+// viewTraffic charts the traffic volume of the last day and lists the busiest hours of the day.
+func (m *model) viewTraffic() string {
+	traffic := m.dashboard.Traffic
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+
+	busiest := make([]string, 0, busiestHoursShown)
+	for _, hour := range traffic.BusiestHours(busiestHoursShown) {
+		busiest = append(busiest, fmt.Sprintf("%02d:00", hour))
+	}
+	if len(busiest) == 0 {
+		busiest = append(busiest, "-")
+	}
+
+	return fmt.Sprintf(
+		" %s %s  %s %s",
+		keyStyle.Render(m.language.T("Traffic 24h:")),
+		internal.Sparkline(traffic.Hourly(m.dashboard.Clock().Now(), hoursShown)),
+		keyStyle.Render(m.language.T("Busiest:")),
+		strings.Join(busiest, ", "))
+}
+
+// viewAltitudeBands charts the share of airborne aircraft in each altitude band, next to how many
+// are in it right now.
+func (m *model) viewAltitudeBands() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	lines := []string{" " + keyStyle.Render(m.language.T("Altitudes (now, share):"))}
+	for _, line := range internal.AltitudeHistogram(m.dashboard.Altitudes.Bands()) {
+		lines = append(lines, "   "+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewMessageTypes tells how the positions of the aircraft were received, e.g. by ADS-B or MLAT, now
+// and as a share of the session.
+func (m *model) viewMessageTypes() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	return fmt.Sprintf(" %s %s", keyStyle.Render(m.language.T("Received by (now, share):")), m.dashboard.MessageTypes)
+}
+
+// viewWinds lists the wind reported by aircraft in each altitude band over the last half hour.
+func (m *model) viewWinds() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	lines := []string{" " + keyStyle.Render(m.language.T("Winds aloft (from, speed, reports):"))}
+	for _, line := range internal.WindProfile(m.dashboard.Winds.Bands()) {
+		lines = append(lines, "   "+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewTemperatures lists the temperature reported by aircraft by altitude over the last half
+// hour, with inversions highlighted.
+func (m *model) viewTemperatures() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	inversionStyle := m.baseStyle.Foreground(m.theme.Yellow)
+	lines := []string{" " + keyStyle.Render(m.language.T("Temperatures aloft (OAT, reports):"))}
+	for _, line := range internal.TemperatureProfile(m.dashboard.Temperatures.Bands()) {
+		if strings.HasSuffix(line, "inversion") {
+			line = inversionStyle.Render(line)
+		}
+		lines = append(lines, "   "+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewDiscovery charts how many new types, operators and countries were discovered per day and
+// how thoroughly the airspace has been explored.
+func (m *model) viewDiscovery() string {
+	discovery := m.dashboard.Discovery
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	return fmt.Sprintf(
+		" %s %s  %s",
+		keyStyle.Render(m.language.T("Discoveries 30d:")),
+		internal.DiscoverySparkline(discovery.Daily(m.dashboard.Clock().Now(), discoveryDays)),
+		discovery.Summary())
+}
+
+// viewSources attributes the aircraft to the data sources which reported them.
+func (m *model) viewSources() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	names, stats := m.request.SourceStats()
+	attributions := make([]string, len(names))
+	for idx, name := range names {
+		attributions[idx] = fmt.Sprintf(
+			"%s %d (%d exclusive)",
+			name,
+			stats[idx].Aircraft,
+			stats[idx].Exclusive)
+	}
+	decodeErrors := ""
+	if diag := m.request.DecodeDiagnostics(); diag.Total() > 0 {
+		decodeErrors = fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Decode errors:")), diag)
+	}
+	if rejected := m.dashboard.RejectedRecords(); rejected.Total() > 0 {
+		decodeErrors += fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Rejected:")), rejected)
+	}
+	if missing := m.dashboard.MissingDatasets(); len(missing) > 0 {
+		disabled := make([]string, len(missing))
+		for idx, dataset := range missing {
+			disabled[idx] = dataset.Enrichment
+		}
+		decodeErrors += fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Disabled:")), strings.Join(disabled, ", "))
+	}
+	reload := ""
+	if m.reloadErr != nil {
+		reload = fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Reload failed:")), m.reloadErr)
+	} else if !m.reloaded.IsZero() {
+		reload = fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Reloaded:")), m.timeDisplay.Format(m.reloaded))
+	}
+	snapshot := ""
+	if m.snapshotPath != "" {
+		snapshot = fmt.Sprintf("  %s %s", keyStyle.Render(m.language.T("Snapshot:")), m.snapshotPath)
+	}
+	return fmt.Sprintf(
+		" %s %s (s to switch)%s%s%s",
+		keyStyle.Render(m.language.T("Sources:")),
+		strings.Join(attributions, ", "),
+		decodeErrors,
+		reload,
+		snapshot)
 }
 
 func (m *model) viewTypeRarity() string {
-	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.typeRarityTbl.table.View())
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.typeRarityTbl.view())
 }
 
 func (m *model) viewOperatorRarity() string {
-	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.operatorRarityTbl.table.View())
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.operatorRarityTbl.view())
 }
 
 func (m *model) viewCountryRarity() string {
-	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.countryRarityTbl.table.View())
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Render(m.countryRarityTbl.view())
+}
+
+func (m *model) viewAircraftDetails() string {
+	aircraft := m.detailAircraft
+	if aircraft == nil {
+		return ""
+	}
+
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	detailItem := func(key string, value string) string {
+		return fmt.Sprintf("%s %s", keyStyle.Render(fmt.Sprintf("%12s:", key)), value)
+	}
+
+	route, ok := m.dashboard.CachedFlightRoutes[aircraft.GetFlightNoAsStr()]
+	if !ok {
+		route = internal.GetDefaultFlightrouteRecord()
+	}
+
+	note, _ := m.dashboard.Notes.Get(aircraft.Hex)
+	noteText := note.Text + " (n to edit)"
+	if m.noteInput.Focused() {
+		noteText = m.noteInput.View()
+	} else if m.noteErr != nil {
+		noteText = fmt.Sprintf("%s (failed to save: %s)", note.Text, m.noteErr)
+	}
+	watched := "no (w to add)"
+	if note.Watch {
+		watched = "yes (w to remove)"
+	}
+	favourite := "no (* to add)"
+	if note.Favourite {
+		favourite = "yes (* to remove)"
+	}
+
+	model := m.dashboard.IcaoToAircraft[aircraft.IcaoType].Make
+	specs, threeView := "n/a", "n/a"
+	if spec, ok := m.dashboard.TypeSpecs[aircraft.IcaoType]; ok {
+		specs = spec.String()
+	}
+	if model != "" {
+		threeView = dash.ThreeViewLink(model)
+	}
+	if family := m.dashboard.TypeFamily(model); family != model {
+		model += ", part of the " + family
+	}
+
+	resolved, _ := m.dashboard.Resolved(aircraft.Hex)
+	operator := viewResolved(resolved.Operator, resolved.Confidence.Operator)
+	if group := m.dashboard.OperatorGroup(resolved.Operator); group != resolved.Operator {
+		operator += ", part of " + group
+	}
+
+	previously := "n/a"
+	if legs := m.dashboard.PreviousFlights(aircraft.Hex, aircraft.GetFlightNoAsStr()); len(legs) > 0 {
+		now := m.dashboard.Clock().Now()
+		described := make([]string, 0, previousFlightsShown)
+		for _, leg := range legs[:min(len(legs), previousFlightsShown)] {
+			described = append(described, leg.Describe(now))
+		}
+		previously = strings.Join(described, ", ")
+	}
+
+	acarsText, oooi := m.viewAcars(aircraft)
+
+	photoLink := "n/a"
+	if photo := m.dashboard.GetPhotoForRegistration(aircraft.Registration); photo.HasLink() {
+		photoLink = fmt.Sprintf("%s (by %s)", photo.Link, photo.Photographer)
+	}
+
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			detailItem(m.language.T("Flight"), aircraft.GetFlightNoAsStr()),
+			detailItem(m.language.T("Previously"), previously),
+			detailItem(m.language.T("Registration"), aircraft.Registration),
+			detailItem(m.language.T("Hex"), aircraft.Hex),
+			detailItem(m.language.T("Type"), model),
+			detailItem(m.language.T("Description"), aircraft.Description),
+			detailItem(m.language.T("Notable"), m.viewNotable(aircraft.Hex)),
+			detailItem(m.language.T("Operator"), operator),
+			detailItem(m.language.T("Country"), viewResolved(resolved.Country, resolved.Confidence.Country)),
+			detailItem(m.language.T("Specs"), specs),
+			detailItem(m.language.T("3-view"), threeView),
+			detailItem(m.language.T("Origin"), route.Origin.Airport),
+			detailItem(m.language.T("Destination"), route.Destination.Airport),
+			detailItem(m.language.T("Distance"), m.viewDistance(aircraft)),
+			detailItem(m.language.T("Source"), viewSource(aircraft)),
+			detailItem(m.language.T("Accuracy"), viewAccuracy(aircraft)),
+			detailItem(m.language.T("Altitude"), aircraft.AltBaro.String()),
+			detailItem(m.language.T("Speed"), fmt.Sprintf("%.0f kt", aircraft.GroundSpeed)),
+			detailItem(m.language.T("Airspeed"), viewAirspeed(aircraft)),
+			detailItem(m.language.T("Heading"), fmt.Sprintf("%.0f", aircraft.NavHeading)),
+			detailItem(m.language.T("Squawk"), aircraft.Squawk),
+			detailItem(m.language.T("ACARS"), acarsText),
+			detailItem(m.language.T("OOOI"), oooi),
+			detailItem(m.language.T("Photo"), photoLink),
+			detailItem(m.language.T("Note"), noteText),
+			detailItem(m.language.T("Watchlist"), watched),
+			detailItem(m.language.T("Favourite"), favourite),
+			"",
+			m.viewDetailImage(aircraft),
+		),
+	)
+}
+
+// viewNotable tells what makes the aircraft notable, highlighted, or "no" if it isn't.
+func (m *model) viewNotable(hex string) string {
+	notable, ok := m.dashboard.Notable(hex)
+	if !ok {
+		return "no"
+	}
+	return m.baseStyle.Foreground(m.theme.Highlight).Render(
+		fmt.Sprintf("%s%s, %s", notableMarker, notable.Category, notable.Description))
+}
+
+// viewReceiverStats shows the feeding statistics of the local receiver, if it is an active source.
+func (m *model) viewReceiverStats() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	statsItem := func(key string, value string) string {
+		return fmt.Sprintf("%s %s", keyStyle.Render(fmt.Sprintf("%12s:", key)), value)
+	}
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	if m.receiverStatsErr != nil {
+		return box.Render(fmt.Sprintf(
+			"No receiver statistics (r to go back), they require a local readsb source: %s",
+			m.receiverStatsErr))
+	}
+
+	stats := m.receiverStats
+	version := stats.Version
+	if version == "" {
+		version = "n/a"
+	}
+	return box.Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			statsItem("Receiver", version+" (r to go back)"),
+			statsItem("Uptime", stats.Uptime.String()),
+			statsItem("Samples", fmt.Sprintf(
+				"%d processed, %d dropped", stats.SamplesProcessed, stats.SamplesDropped)),
+			statsItem("Messages", fmt.Sprintf(
+				"%d total, %d last minute", stats.Messages, stats.RecentMessages)),
+			statsItem("Positions", fmt.Sprintf(
+				"%d total, %d last minute", stats.Positions, stats.RecentPositions)),
+			statsItem("Aircraft", fmt.Sprintf(
+				"%d with position, %d without", stats.WithPosition, stats.WithoutPosition)),
+			statsItem("Updated", m.timeDisplay.Format(stats.Updated)),
+		),
+	)
+}
+
+// viewResolved shows a resolved operator or country, marked unless it is an exact hit in the
+// datasets, e.g. "GERMANY ? (guess)".
+func viewResolved(value string, confidence internal.Confidence) string {
+	switch confidence {
+	case internal.ConfidenceNone:
+		return "n/a"
+	case internal.ConfidenceExact:
+		return value
+	case internal.ConfidenceHeuristic, internal.ConfidenceGuess:
+	}
+	return fmt.Sprintf("%s %s (%s)", value, confidence.Marker(), confidence)
+}
+
+// viewDistance tells the distance to the aircraft and, if it has no live position, how old its
+// last known position is. Between polls it tells the distance estimated from track and speed.
+func (m *model) viewDistance(aircraft *internal.AircraftRecord) string {
+	distance := fmt.Sprintf("%.0f km", aircraft.CachedDist)
+	if position, ok := aircraft.KnownPosition(); ok && position.Stale {
+		age := time.Duration(position.SeenPos * float64(time.Second)).Round(time.Second)
+		distance += fmt.Sprintf(" (last known position, %s ago)", age)
+	} else if estimated, ok := m.dashboard.EstimatedDistance(aircraft, m.sincePoll()); ok {
+		distance = fmt.Sprintf("%s%.0f km (estimated, %s when polled)", estimatedMarker, estimated, distance)
+	}
+	return distance
+}
+
+// sincePoll is how long ago the aircraft were polled.
+func (m *model) sincePoll() time.Duration {
+	return m.now().Sub(m.lastUpdate)
+}
+
+// viewAcars shows the latest ACARS message of the aircraft and how many there are, and the latest
+// OOOI times along with the route of the message reporting them.
+func (m *model) viewAcars(aircraft *internal.AircraftRecord) (string, string) {
+	messages := m.dashboard.Acars.Messages(aircraft.Registration, aircraft.Hex)
+	if len(messages) == 0 {
+		return "n/a", "n/a"
+	}
+	latest := messages[0]
+	text := fmt.Sprintf("%s [%s] %s (%d messages)",
+		m.timeDisplay.Format(latest.Time), latest.Label, strings.Join(strings.Fields(latest.Text), " "),
+		len(messages))
+
+	oooi := "n/a"
+	for _, msg := range messages {
+		if times := msg.OOOI(); times != "" {
+			oooi = times
+			if msg.Departure != "" || msg.Destination != "" {
+				oooi += fmt.Sprintf(" (%s-%s)", msg.Departure, msg.Destination)
+			}
+			break
+		}
+	}
+	return fitCell(text, m.width-16), oooi //nolint:mnd // width of the detail keys and border
+}
+
+// viewAccuracy tells how far the position of the aircraft can be trusted, with the integrity and
+// accuracy it was reported with, e.g. "high (NIC 8, Rc 186 m, NACp 9)".
+func viewAccuracy(aircraft *internal.AircraftRecord) string {
+	accuracy := aircraft.PositionAccuracy()
+	if aircraft.Nic == 0 && aircraft.RadiusOfCtn == 0 && aircraft.NacP == 0 {
+		return accuracy.String()
+	}
+	return fmt.Sprintf("%s (NIC %d, Rc %.0f m, NACp %.0f)", accuracy, aircraft.Nic, aircraft.RadiusOfCtn, aircraft.NacP)
+}
+
+// viewSource tells which data source the position of the aircraft was taken from and how it was
+// received, e.g. "adsc (ADS-C)" for oceanic traffic.
+func viewSource(aircraft *internal.AircraftRecord) string {
+	if aircraft.Source == "" {
+		return "n/a"
+	}
+	if aircraft.Type == "" {
+		return aircraft.Source
+	}
+	return fmt.Sprintf("%s (%s)", aircraft.Source, internal.MessageTypeLabel(aircraft.Type))
 }
@@ -0,0 +1,67 @@
+package tuiapp
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	notificationsPageChrome = 3 // title and border of the notifications box
+	notificationDateFormat  = "Jan 02"
+)
+
+// toggleNotificationsPage shows the latest notifications instead of the current aircraft, or goes
+// back to them.
+func (m *model) toggleNotificationsPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = notificationsPage
+	case notificationsPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, errorPage, favouritesPage,
+		settingsPage, followPage, achievementsPage:
+	}
+}
+
+func (m *model) processNotificationsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "A", "esc":
+		m.toggleNotificationsPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// viewNotifications shows the newest notifications which fit on the page, also those of earlier
+// sessions, so that missed ones can be reviewed.
+func (m *model) viewNotifications() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	notifications := m.notify.Notifications()
+	shownCount := max(1, m.height-m.layout.headerHeight()-m.bannerHeight()-notificationsPageChrome)
+	shown := make([]string, 0, min(shownCount, len(notifications)))
+	for _, entry := range notifications[:min(shownCount, len(notifications))] {
+		line := fmt.Sprintf("%s %s  %s",
+			entry.Time.In(m.timeDisplay.Location()).Format(notificationDateFormat),
+			m.timeDisplay.Format(entry.Time),
+			entry.Title)
+		if aircraft := entry.Aircraft(); aircraft != "" {
+			line += ": " + aircraft
+		}
+		shown = append(shown, fitCell(line, m.width-2))
+	}
+	if len(shown) == 0 {
+		shown = append(shown, "No notifications yet")
+	}
+
+	title := m.language.Sprintf("Notifications, newest first, %d kept (A to go back)", len(notifications))
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(shown, "\n"),
+	))
+}
@@ -0,0 +1,64 @@
+package tuiapp
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+)
+
+// toggleSettingsPage shows the categories of desktop notifications instead of the current
+// aircraft, or goes back to them.
+func (m *model) toggleSettingsPage() {
+	switch m.uiState {
+	case mainPage:
+		m.uiState = settingsPage
+	case settingsPage:
+		m.uiState = mainPage
+	case aircraftDetails, globalStats, receiverStats, startupPage, logPage, errorPage,
+		favouritesPage, notificationsPage, followPage, achievementsPage:
+	}
+}
+
+// processSettingsKey selects a category of desktop notifications and switches it on or off.
+func (m *model) processSettingsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		m.settingsCursor = max(0, m.settingsCursor-1)
+	case "down", "j":
+		m.settingsCursor = min(len(internal.NotificationCategories)-1, m.settingsCursor+1)
+	case "enter", " ":
+		m.notify.ToggleNotifying(internal.NotificationCategories[m.settingsCursor])
+	case "N", "esc":
+		m.toggleSettingsPage()
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// viewSettings lists the categories of desktop notifications and whether they are on.
+func (m *model) viewSettings() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	box := m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2)
+
+	lines := make([]string, 0, len(internal.NotificationCategories))
+	for idx, category := range internal.NotificationCategories {
+		cursor := "  "
+		if idx == m.settingsCursor {
+			cursor = "> "
+		}
+		state := "[ ]"
+		if m.notify.IsNotifying(category) {
+			state = "[x]"
+		}
+		lines = append(lines, fitCell(cursor+state+" "+m.language.T(category.Label()), m.width-2))
+	}
+
+	title := m.language.T("Desktop notifications for this session (enter to switch, N to go back)")
+	return box.Render(lipgloss.JoinVertical(lipgloss.Left,
+		keyStyle.Render(title),
+		strings.Join(lines, "\n"),
+	))
+}
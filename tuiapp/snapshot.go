@@ -0,0 +1,216 @@
+package tuiapp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log" //nolint:depguard // Don't feel like using slog for now.
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+// Formats of the snapshots of the TUI.
+const (
+	SnapshotText = "text" // SnapshotText writes the tables aligned in columns, to be read by people.
+	SnapshotCSV  = "csv"  // SnapshotCSV writes each table as a titled section of comma-separated values.
+	SnapshotJSON = "json" // SnapshotJSON writes all tables as a single JSON object.
+)
+
+const (
+	snapshotFileFormat = "airspottr-20060102T150405"
+	snapshotFilePerm   = 0o644
+	// summaryAircraftShown is how many of the closest aircraft the clipboard summary lists.
+	summaryAircraftShown = 10
+)
+
+var errInvalidSnapshotFormat = errors.New("invalid snapshot format")
+
+// snapshotTable is one table of a snapshot, with its cells trimmed of the padding of the TUI.
+type snapshotTable struct {
+	Title   string     `json:"title"`
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// snapshot is what the TUI shows at a point in time: the statistics of the header, the current
+// aircraft and the rarity tables.
+type snapshot struct {
+	Time   time.Time       `json:"time"`
+	Tables []snapshotTable `json:"tables"`
+}
+
+// ValidateSnapshotFormat checks that the format is one of the SnapshotXxx formats.
+func ValidateSnapshotFormat(format string) error {
+	switch format {
+	case SnapshotText, SnapshotCSV, SnapshotJSON:
+		return nil
+	}
+	return fmt.Errorf("ValidateSnapshotFormat: %w: %q", errInvalidSnapshotFormat, format)
+}
+
+// snapshotFileName names the snapshot after its time, with the extension of its format.
+func snapshotFileName(now time.Time, format string) string {
+	extension := format
+	if format == SnapshotText {
+		extension = "txt"
+	}
+	return now.Format(snapshotFileFormat) + "." + extension
+}
+
+// tableSnapshot copies the full cell values of a table, not the ones cut to fit the columns.
+func tableSnapshot(title string, aft *autoFormatTable) snapshotTable {
+	columns := make([]string, 0, len(aft.table.Columns()))
+	for _, column := range aft.table.Columns() {
+		columns = append(columns, column.Title)
+	}
+	rows := make([][]string, 0, len(aft.rows))
+	for _, row := range aft.rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, strings.TrimSpace(cell))
+		}
+		rows = append(rows, cells)
+	}
+	return snapshotTable{Title: title, Columns: columns, Rows: rows}
+}
+
+// takeSnapshot collects what the TUI shows right now.
+func (m *model) takeSnapshot() snapshot {
+	now := m.dashboard.Clock().Now()
+	stats := [][]string{
+		{"Time", m.timeDisplay.Format(now)},
+		{"Location", fmt.Sprintf("%.3f, %.3f", m.dashboard.Lat, m.dashboard.Lon)},
+		{"Aircraft", fmt.Sprint(len(m.currentAircraftTbl.rows))},
+		{"Peak", fmt.Sprintf("%d aircraft, all-time %d",
+			m.dashboard.Peaks.Session().Aircraft, m.dashboard.Peaks.AllTime().Aircraft)},
+		{"Today", fmt.Sprintf("%d aircraft", m.dashboard.DailyAircraft.Today(now))},
+		{"Go-arounds", fmt.Sprintf("%d today, session: %s", m.dashboard.GoArounds.Today(now), m.dashboard.GoArounds)},
+		{"Baseline", m.dashboard.Warmup().String()},
+		{"Discoveries", m.dashboard.Discovery.Summary()},
+	}
+	records := m.dashboard.Records.Session()
+	if highest := records.Highest; highest != nil {
+		stats = append(stats, []string{"Highest", fmt.Sprintf("%s %s at %.0f ft",
+			highest.Flight, highest.Registration, highest.Value)})
+	}
+	if fastest := records.Fastest; fastest != nil {
+		stats = append(stats, []string{"Fastest", fmt.Sprintf("%s %s at %.0f kt",
+			fastest.Flight, fastest.Registration, fastest.Value)})
+	}
+
+	return snapshot{
+		Time: now,
+		Tables: []snapshotTable{
+			{Title: "Stats", Columns: []string{"Name", "Value"}, Rows: stats},
+			tableSnapshot("Aircraft", &m.currentAircraftTbl),
+			tableSnapshot("Types", &m.typeRarityTbl),
+			tableSnapshot("Operators", &m.operatorRarityTbl),
+			tableSnapshot("Countries", &m.countryRarityTbl),
+		},
+	}
+}
+
+// writeSnapshot writes the snapshot in one of the SnapshotXxx formats.
+func writeSnapshot(writer io.Writer, format string, snap snapshot) error {
+	switch format {
+	case SnapshotJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(snap); err != nil {
+			return fmt.Errorf("writeSnapshot: %w", err)
+		}
+	case SnapshotCSV:
+		csvWriter := csv.NewWriter(writer)
+		for _, tbl := range snap.Tables {
+			records := append([][]string{{tbl.Title}, tbl.Columns}, tbl.Rows...)
+			if err := csvWriter.WriteAll(append(records, []string{""})); err != nil {
+				return fmt.Errorf("writeSnapshot: %w", err)
+			}
+		}
+	case SnapshotText:
+		for idx, tbl := range snap.Tables {
+			if err := writeTextTable(writer, tbl, -1); err != nil {
+				return fmt.Errorf("writeSnapshot: %w", err)
+			}
+			if idx < len(snap.Tables)-1 {
+				if _, err := fmt.Fprintln(writer); err != nil {
+					return fmt.Errorf("writeSnapshot: %w", err)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("writeSnapshot: %w: %q", errInvalidSnapshotFormat, format)
+	}
+	return nil
+}
+
+// writeTextTable writes the title of the table and its rows aligned in columns, at most maxRows
+// of them unless it's negative.
+func writeTextTable(writer io.Writer, tbl snapshotTable, maxRows int) error {
+	rows := tbl.Rows
+	if maxRows >= 0 && len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+	if _, err := fmt.Fprintln(writer, tbl.Title); err != nil {
+		return fmt.Errorf("writeTextTable: %w", err)
+	}
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // padding between columns
+	for _, cells := range append([][]string{tbl.Columns}, rows...) {
+		if _, err := fmt.Fprintln(tabWriter, strings.Join(cells, "\t")); err != nil {
+			return fmt.Errorf("writeTextTable: %w", err)
+		}
+	}
+	if err := tabWriter.Flush(); err != nil {
+		return fmt.Errorf("writeTextTable: %w", err)
+	}
+	return nil
+}
+
+// snapshotSummary formats the stats and the closest aircraft of a snapshot for sharing, e.g. in a
+// chat.
+func snapshotSummary(snap snapshot) string {
+	var builder strings.Builder
+	for _, tbl := range snap.Tables {
+		switch tbl.Title {
+		case "Stats":
+			for _, stat := range tbl.Rows {
+				fmt.Fprintf(&builder, "%s: %s\n", stat[0], stat[1])
+			}
+			builder.WriteString("\n")
+		case "Aircraft":
+			_ = writeTextTable(&builder, tbl, summaryAircraftShown) // a strings.Builder doesn't fail
+		}
+	}
+	return builder.String()
+}
+
+// saveSnapshot writes what the TUI shows to a file named after the current time in the working
+// directory and, if enabled, copies a summary of it to the clipboard of the terminal.
+func (m *model) saveSnapshot() {
+	snap := m.takeSnapshot()
+	path := snapshotFileName(snap.Time, m.export.SnapshotFormat)
+	file, createErr := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, snapshotFilePerm)
+	if createErr != nil {
+		m.recordError("snapshot", createErr)
+		return
+	}
+	writeErr := writeSnapshot(file, m.export.SnapshotFormat, snap)
+	closeErr := file.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		m.recordError("snapshot", err)
+		return
+	}
+	log.Printf("snapshot written to %s", path)
+	m.snapshotPath = path
+
+	if m.export.SnapshotClipboard {
+		// The terminal takes the summary with an OSC 52 escape sequence, which works over SSH too.
+		termenv.DefaultOutput().Copy(snapshotSummary(snap))
+	}
+}
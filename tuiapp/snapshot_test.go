@@ -0,0 +1,70 @@
+package tuiapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSnapshot() snapshot {
+	return snapshot{
+		Time: time.Date(2026, 10, 18, 14, 3, 0, 0, time.UTC),
+		Tables: []snapshotTable{
+			{Title: "Stats", Columns: []string{"Name", "Value"}, Rows: [][]string{{"Aircraft", "2"}}},
+			{
+				Title:   "Aircraft",
+				Columns: []string{"DST", "FNO", "TID"},
+				Rows:    [][]string{{"3", "GAF681", "A400M, Atlas"}, {"12", "DLH4AB", "A320"}},
+			},
+		},
+	}
+}
+
+func TestWriteSnapshot(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{SnapshotText, "Stats\nName      Value\nAircraft  2\n\nAircraft\nDST  FNO     TID\n" +
+			"3    GAF681  A400M, Atlas\n12   DLH4AB  A320\n"},
+		{SnapshotCSV, "Stats\nName,Value\nAircraft,2\n\nAircraft\nDST,FNO,TID\n" +
+			"3,GAF681,\"A400M, Atlas\"\n12,DLH4AB,A320\n\n"},
+	}
+	for _, tt := range tests {
+		var buffer bytes.Buffer
+		if err := writeSnapshot(&buffer, tt.format, testSnapshot()); err != nil {
+			t.Fatalf("writeSnapshot(%s) error = %v", tt.format, err)
+		}
+		if buffer.String() != tt.expected {
+			t.Errorf("writeSnapshot(%s) =\n%s\nexpected\n%s", tt.format, buffer.String(), tt.expected)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := writeSnapshot(&buffer, SnapshotJSON, testSnapshot()); err != nil {
+		t.Fatalf("writeSnapshot(json) error = %v", err)
+	}
+	var decoded snapshot
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeSnapshot(json) wrote invalid JSON: %v", err)
+	}
+	if len(decoded.Tables) != 2 || decoded.Tables[1].Rows[0][2] != "A400M, Atlas" {
+		t.Errorf("writeSnapshot(json) = %s, expected both tables", buffer.String())
+	}
+
+	if err := writeSnapshot(&buffer, "xml", testSnapshot()); err == nil {
+		t.Error("writeSnapshot(xml) succeeded, expected an error")
+	}
+}
+
+func TestSnapshotSummaryAndFileName(t *testing.T) {
+	summary := snapshotSummary(testSnapshot())
+	if !strings.HasPrefix(summary, "Aircraft: 2\n\nAircraft\n") || !strings.Contains(summary, "GAF681") {
+		t.Errorf("snapshotSummary() = %q, expected the stats followed by the aircraft", summary)
+	}
+	if name := snapshotFileName(testSnapshot().Time, SnapshotText); name != "airspottr-20261018T140300.txt" {
+		t.Errorf("snapshotFileName() = %s", name)
+	}
+}
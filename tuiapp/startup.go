@@ -0,0 +1,160 @@
+package tuiapp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+// StartupProgressMsg tells that another dataset has been loaded during startup.
+type StartupProgressMsg struct {
+	dataset string
+	loaded  int
+	total   int
+}
+
+// StartupDoneMsg carries what has been set up during startup, or why it failed.
+type StartupDoneMsg struct {
+	request   *internal.Request
+	dashboard *internal.Dashboard
+	notify    *internal.Notify
+	err       error
+}
+
+// startupState is what the startup screen shows until the first aircraft arrive.
+type startupState struct {
+	configPath     string
+	datasetsLoaded int
+	datasetsTotal  int
+	lastDataset    string
+	ready          bool  // ready is true once everything is set up and the first fetch is in flight.
+	err            error // err is why the startup failed, if it did.
+}
+
+// startup sets up the request, dashboard and notifier in the background and reports its progress
+// on the returned channel, which is closed once done.
+func startup(appName string, options internal.AppOptions, errWriter io.Writer) <-chan tea.Msg {
+	messages := make(chan tea.Msg)
+	options.Dashboard.LoadProgress = func(dataset string, loaded int, total int) {
+		messages <- StartupProgressMsg{dataset: dataset, loaded: loaded, total: total}
+	}
+
+	go func() {
+		defer close(messages)
+		request, dashboard, notify, err := setupDashboardAndNotifier(appName, options, errWriter)
+		if err == nil && options.Health.Addr != "" {
+			health := internal.NewHealth(request, dashboard, options.Polling.MaxInterval())
+			if healthErr := internal.ServeHealth(options.Health, health, &errWriter); healthErr != nil {
+				err = fmt.Errorf("failed to serve health endpoint: %w", healthErr)
+			}
+		}
+		if err == nil && options.Acars.Addr != "" {
+			if acarsErr := internal.ListenAcars(options.Acars.Addr, dashboard.Acars, &errWriter); acarsErr != nil {
+				err = fmt.Errorf("failed to listen for ACARS messages: %w", acarsErr)
+			}
+		}
+		if err != nil {
+			messages <- StartupDoneMsg{request: nil, dashboard: nil, notify: nil, err: err}
+			return
+		}
+		// The warmup ends once there's a baseline of sightings, or after the warmup at the latest.
+		dashboard.Clock().AfterFunc(options.Polling.Warmup, dashboard.FinishWarmupPeriod)
+		messages <- StartupDoneMsg{request: request, dashboard: dashboard, notify: notify, err: nil}
+	}()
+
+	return messages
+}
+
+// waitForStartup waits for the next message of the startup.
+func waitForStartup(messages <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-messages
+	}
+}
+
+// processStartupDone takes over what has been set up and sends out the first requests, or keeps
+// showing the startup screen with the reason it failed.
+func (m *model) processStartupDone(msg StartupDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.startup.err = msg.err
+		return nil
+	}
+
+	m.request = msg.request
+	m.feed = internal.NewAircraftFeed(m.request)
+	m.dashboard = msg.dashboard
+	m.notify = msg.notify
+	m.startTime = m.dashboard.Clock().Now()
+	m.startup.ready = true
+	for _, missing := range m.dashboard.MissingDatasets() {
+		m.recordError("loading "+missing.Name, missing.Err)
+	}
+	m.feed.Poll()
+	return tea.Batch(
+		aircraftQueryTick(m.nextPollInterval()),
+		waitForAircraft(m.feed),
+		requestReceiverStatsCmd(m.request))
+}
+
+// viewStartup shows the progress of the startup, or why it failed.
+func (m *model) viewStartup() string {
+	keyStyle := m.baseStyle.Foreground(lipgloss.AdaptiveColor{Light: "#383838", Dark: "#F988F9"})
+	step := func(done bool, text string) string {
+		mark := "…"
+		if done {
+			mark = "✓"
+		}
+		return fmt.Sprintf(" %s %s", keyStyle.Render(mark), text)
+	}
+
+	state := m.startup
+	datasets := m.language.T("loading datasets")
+	if state.datasetsTotal > 0 {
+		datasets = m.language.Sprintf(
+			"loading datasets %d/%d (%s)", state.datasetsLoaded, state.datasetsTotal, state.lastDataset)
+	}
+	datasetsDone := state.datasetsTotal > 0 && state.datasetsLoaded == state.datasetsTotal
+
+	lines := []string{
+		m.baseStyle.Bold(true).Render(" " + m.language.T("airspottr is starting up")),
+		"",
+		step(true, m.language.Sprintf("config parsed (%s)", state.configPath)),
+		step(datasetsDone, datasets),
+	}
+	switch {
+	case state.err != nil:
+		lines = append(lines,
+			"",
+			m.baseStyle.Bold(true).Foreground(m.theme.Red).Render(
+				" "+m.language.Sprintf("Startup failed: %s", state.err)),
+			" "+startupHint(m.language, state.err),
+			" "+m.language.T("Press q to quit."))
+	case state.ready:
+		lines = append(lines, step(false, m.language.T("first aircraft request in flight")))
+	}
+
+	return m.viewStyle.Border(lipgloss.RoundedBorder()).Width(m.width - 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// startupHint suggests what to do about a failed startup, in the given language.
+func startupHint(lang i18n.Language, err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		if errors.Is(err, fs.ErrNotExist) {
+			return lang.Sprintf(
+				"%s doesn't exist. Datasets are read from ./data, so start airspottr from the "+
+					"directory which contains it, and check the paths given on the command line.",
+				pathErr.Path)
+		}
+		return lang.Sprintf("%s can't be read, check its permissions.", pathErr.Path)
+	}
+	return lang.T("Check the command line options (airspottr --help) and the log, which is printed on " +
+		"quitting.")
+}
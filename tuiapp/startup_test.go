@@ -0,0 +1,52 @@
+package tuiapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/micutio/airspottr/internal/i18n"
+)
+
+func TestStartupHint(t *testing.T) {
+	_, missingErr := os.Open("./data/ICAOList.csv")
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{fmt.Errorf("newDashboard: %w", missingErr), "start airspottr from the directory"},
+		{errors.New("missing API key for adsbx"), "airspottr --help"},
+	}
+
+	for _, test := range tests {
+		if hint := startupHint(i18n.English, test.err); !strings.Contains(hint, test.expected) {
+			t.Errorf("startupHint(%v) = %q, expected it to contain %q", test.err, hint, test.expected)
+		}
+	}
+}
+
+func TestStartupFailure(t *testing.T) {
+	m := &model{ //nolint:exhaustruct // only what the startup screen needs
+		width:     80,
+		height:    24,
+		baseStyle: lipgloss.NewStyle(),
+		viewStyle: lipgloss.NewStyle(),
+		theme:     getDefaultTheme(),
+		uiState:   startupPage,
+	}
+	m.Update(StartupProgressMsg{dataset: "airlines", loaded: 1, total: 4})
+	if view := m.View(); !strings.Contains(view, "1/4 (airlines)") {
+		t.Errorf("View() doesn't show the progress:\n%s", view)
+	}
+
+	m.Update(StartupDoneMsg{request: nil, dashboard: nil, notify: nil, err: errors.New("no luck")})
+	if m.uiState != startupPage || m.dashboard != nil {
+		t.Errorf("failed startup left the startup page for %d", m.uiState)
+	}
+	if view := m.View(); !strings.Contains(view, "Startup failed: no luck") {
+		t.Errorf("View() doesn't show the failure:\n%s", view)
+	}
+}
@@ -3,9 +3,14 @@ package tuiapp
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/i18n"
 )
 
 // Error types
@@ -25,6 +30,12 @@ const (
 	fill
 )
 
+// estimatedMarker precedes distances which are estimated from track and speed since the last poll.
+const estimatedMarker = "≈"
+
+// notableMarker precedes the flight of notable aircraft, e.g. head-of-state transports.
+const notableMarker = "!"
+
 // TODO: Add header name (string) to the format.
 type columnFormat struct {
 	option tableColumnSizingOption
@@ -67,9 +78,17 @@ func newTableFormat(items ...columnFormat) tableFormat {
 
 // Integrated Formatted Table Type
 
+// autoFormatTable only hands the page of rows around the cursor to the table, so that fitting and
+// rendering them takes the same time however many rows there are, e.g. in busy airspace.
 type autoFormatTable struct {
 	table  table.Model
 	format tableFormat
+	keys   []string    // keys identify the rows, e.g. aircraft by hex, to keep the selection stable.
+	rows   []table.Row // rows are the full cell values, before fitting them into the columns.
+	// tints are the colours of the rows, nil for rows in the default colour.
+	tints  []lipgloss.TerminalColor
+	cursor int // cursor is the index of the selected row among all rows.
+	offset int // offset is the index of the first row of the page shown.
 }
 
 // TODO: Take table padding into account!
@@ -109,15 +128,185 @@ func (aft *autoFormatTable) resize(newWidth int) error {
 		}
 	}
 
+	// The cells were fitted into the old column widths.
+	aft.refreshPage()
+
 	return nil
 }
 
+// fitRows fits the cells of the rows into the current column widths by display width.
+func (aft *autoFormatTable) fitRows(rows []table.Row) []table.Row {
+	columns := aft.table.Columns()
+	fitted := make([]table.Row, len(rows))
+	for rowIdx, row := range rows {
+		fitted[rowIdx] = make(table.Row, len(row))
+		for colIdx, value := range row {
+			if colIdx < len(columns) {
+				value = fitCell(value, columns[colIdx].Width)
+			}
+			fitted[rowIdx][colIdx] = value
+		}
+	}
+	return fitted
+}
+
 func (aft *autoFormatTable) SetHeight(height int) {
 	aft.table.SetHeight(height)
+	aft.refreshPage()
+}
+
+// isPaged tells whether there are more rows than fit into the table, so that its last line tells
+// which of them are shown instead.
+func (aft *autoFormatTable) isPaged() bool {
+	return len(aft.rows) > aft.table.Height()
+}
+
+// pageSize is how many rows are shown at once.
+func (aft *autoFormatTable) pageSize() int {
+	if aft.isPaged() {
+		return max(aft.table.Height()-1, 1)
+	}
+	return max(aft.table.Height(), 1)
+}
+
+// moveUp moves the cursor up by the given number of rows, as far as the first row.
+func (aft *autoFormatTable) moveUp(rows int) {
+	aft.setCursor(aft.cursor - rows)
+}
+
+// moveDown moves the cursor down by the given number of rows, as far as the last row.
+func (aft *autoFormatTable) moveDown(rows int) {
+	aft.setCursor(aft.cursor + rows)
+}
+
+// setCursor selects the row with the given index among all rows.
+func (aft *autoFormatTable) setCursor(cursor int) {
+	aft.cursor = cursor
+	aft.refreshPage()
+}
+
+// refreshPage scrolls the page to the cursor and hands its rows, fitted into the columns, to the
+// table.
+func (aft *autoFormatTable) refreshPage() {
+	pageSize := aft.pageSize()
+	aft.cursor = max(min(aft.cursor, len(aft.rows)-1), 0)
+	if aft.cursor < aft.offset {
+		aft.offset = aft.cursor
+	}
+	if aft.cursor >= aft.offset+pageSize {
+		aft.offset = aft.cursor - pageSize + 1
+	}
+	aft.offset = max(min(aft.offset, len(aft.rows)-pageSize), 0)
+
+	end := min(aft.offset+pageSize, len(aft.rows))
+	aft.table.SetRows(aft.fitRows(aft.rows[aft.offset:end]))
+	aft.table.SetCursor(aft.cursor - aft.offset)
+}
+
+// setRows updates the table to the given rows, each of which is identified by its key.
+// If the rows didn't change, the table is left alone. Otherwise the cursor follows the selected
+// row to wherever it moved and only stays at its position if the row is gone.
+func (aft *autoFormatTable) setRows(keys []string, rows []table.Row) {
+	if slices.Equal(keys, aft.keys) && slices.EqualFunc(rows, aft.rows, slices.Equal) {
+		return
+	}
+
+	cursor := aft.cursor
+	if selected, ok := aft.selectedKey(); ok {
+		if idx := slices.Index(keys, selected); idx >= 0 {
+			cursor = idx
+		}
+	}
+	aft.keys = keys
+	aft.rows = rows
+	aft.setCursor(cursor)
+}
+
+// setColumn sets the cells of the given column to the values returned for the keys of their rows,
+// leaving the rows without a value alone. The page is only handed to the table again if any of its
+// cells changed.
+func (aft *autoFormatTable) setColumn(column int, value func(key string) (string, bool)) {
+	pageChanged := false
+	for idx, key := range aft.keys {
+		cell, ok := value(key)
+		if !ok || aft.rows[idx][column] == cell {
+			continue
+		}
+		aft.rows[idx][column] = cell
+		if idx >= aft.offset && idx < aft.offset+len(aft.table.Rows()) {
+			pageChanged = true
+		}
+	}
+	if pageChanged {
+		aft.refreshPage()
+	}
+}
+
+// setTints colours the rows, the tints being in the order of the rows set last.
+func (aft *autoFormatTable) setTints(tints []lipgloss.TerminalColor) {
+	aft.tints = tints
+}
+
+// view renders the page of the table with its rows tinted. The table can't colour single rows
+// itself and counts colours in cells towards their width, so the rendered rows are tinted instead,
+// all but the selected one, which keeps its highlight. If there are more rows than fit, the last
+// line tells which of them are shown.
+func (aft *autoFormatTable) view() string {
+	rendered := aft.table.View()
+	if len(aft.tints) == 0 && !aft.isPaged() {
+		return rendered
+	}
+
+	tintsByRow := make(map[string]lipgloss.TerminalColor, len(aft.table.Rows()))
+	for idx, row := range aft.table.Rows() {
+		if tintIdx := aft.offset + idx; tintIdx < len(aft.tints) && aft.tints[tintIdx] != nil {
+			tintsByRow[aft.renderPlainRow(row)] = aft.tints[tintIdx]
+		}
+	}
+
+	lines := strings.Split(rendered, "\n")
+	for idx, line := range lines {
+		if tint, ok := tintsByRow[collapseSpaces(line)]; ok {
+			lines[idx] = lipgloss.NewStyle().Foreground(tint).Render(line)
+		}
+	}
+	if aft.isPaged() {
+		lines[len(lines)-1] = fmt.Sprintf(" %d-%d of %d",
+			aft.offset+1, aft.offset+len(aft.table.Rows()), len(aft.rows))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPlainRow renders a row like the table does for rows which aren't selected, with the spaces
+// collapsed, since the padding of the cells is up to the style of the table.
+func (aft *autoFormatTable) renderPlainRow(row table.Row) string {
+	cells := make([]string, 0, len(row))
+	for idx, column := range aft.table.Columns() {
+		if column.Width <= 0 || idx >= len(row) {
+			continue
+		}
+		cells = append(cells,
+			lipgloss.NewStyle().Width(column.Width).MaxWidth(column.Width).Inline(true).Render(row[idx]))
+	}
+	return collapseSpaces(strings.Join(cells, " "))
+}
+
+// collapseSpaces trims the line and replaces the spaces between words with a single one.
+func collapseSpaces(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// selectedKey returns the key of the row under the cursor, if there is one.
+func (aft *autoFormatTable) selectedKey() (string, bool) {
+	if aft.cursor < 0 || aft.cursor >= len(aft.keys) {
+		return "", false
+	}
+	return aft.keys[aft.cursor], true
 }
 
 func newCurrentAircraftTable(tableStyle table.Styles) autoFormatTable {
 	dstLen := 4
+	accLen := 4
 	fnoLen := 9
 	tidLen := 0
 	depLen := 4
@@ -128,6 +317,7 @@ func newCurrentAircraftTable(tableStyle table.Styles) autoFormatTable {
 	initialTableHeight := 5
 	format := newTableFormat(
 		columnFormat{fixed, float32(dstLen)},
+		columnFormat{fixed, float32(accLen)},
 		columnFormat{fixed, float32(fnoLen)},
 		columnFormat{fill, float32(tidLen)},
 		columnFormat{fixed, float32(depLen)},
@@ -142,6 +332,7 @@ func newCurrentAircraftTable(tableStyle table.Styles) autoFormatTable {
 		table.WithColumns(
 			[]table.Column{
 				{Title: "DST", Width: dstLen},
+				{Title: "ACC", Width: accLen},
 				{Title: "FNO", Width: fnoLen},
 				{Title: "TID", Width: tidLen},
 				{Title: "DEP", Width: tidLen},
@@ -161,56 +352,42 @@ func newCurrentAircraftTable(tableStyle table.Styles) autoFormatTable {
 	return autoFormatTable{
 		table:  currentAircraftTbl,
 		format: format,
+		keys:   nil,
+		rows:   nil,
+		tints:  nil,
+		cursor: 0,
+		offset: 0,
 	}
 }
 
-func newTypeRarityTable(tableStyle table.Styles) autoFormatTable {
-	countLen := 6
-	typeNameLen := 12
-	initialTableHeight := 5
-	format := newTableFormat(
-		columnFormat{fixed, float32(countLen)},
-		columnFormat{fill, float32(typeNameLen)},
-	)
-
-	// Create a new table with specified columns and initial empty rows.
-	typeRarityTbl := table.New(
-		// table header
-		table.WithColumns(
-			[]table.Column{
-				{Title: "Count", Width: countLen},
-				{Title: "Type", Width: typeNameLen},
-			},
-		),
-		table.WithRows([]table.Row{}),
-		table.WithFocused(true),
-		table.WithHeight(initialTableHeight),
-		table.WithStyles(tableStyle),
-	)
-	typeRarityTbl.Blur()
-
-	return autoFormatTable{
-		table:  typeRarityTbl,
-		format: format,
-	}
-}
-
-func newOperatorRarityTable(tableStyle table.Styles) autoFormatTable {
+// newPropertyCountTable creates a rarity table, which lists how often each type, operator or
+// country has been seen, what share of all sightings that is, how many per hour and when it was
+// first seen ever. The titles of the columns are shown in the given language.
+func newPropertyCountTable(lang i18n.Language, propertyTitle string, tableStyle table.Styles) autoFormatTable {
 	countLen := 6
-	operatorNameLen := 12
+	shareLen := 7
+	rateLen := 7
+	firstSeenLen := 11
+	propertyNameLen := 12
 	initialTableHeight := 5
 	format := newTableFormat(
 		columnFormat{fixed, float32(countLen)},
-		columnFormat{fill, float32(operatorNameLen)},
+		columnFormat{fixed, float32(shareLen)},
+		columnFormat{fixed, float32(rateLen)},
+		columnFormat{fixed, float32(firstSeenLen)},
+		columnFormat{fill, float32(propertyNameLen)},
 	)
 
 	// Create a new table with specified columns and initial empty rows.
-	operatorRarityTbl := table.New(
+	propertyCountTbl := table.New(
 		// table header
 		table.WithColumns(
 			[]table.Column{
-				{Title: "Count", Width: countLen},
-				{Title: "operator", Width: operatorNameLen},
+				{Title: lang.T("Count"), Width: countLen},
+				{Title: lang.T("Share"), Width: shareLen},
+				{Title: lang.T("Per h"), Width: rateLen},
+				{Title: lang.T("First seen"), Width: firstSeenLen},
+				{Title: lang.T(propertyTitle), Width: propertyNameLen},
 			},
 		),
 		table.WithRows([]table.Row{}),
@@ -218,58 +395,108 @@ func newOperatorRarityTable(tableStyle table.Styles) autoFormatTable {
 		table.WithHeight(initialTableHeight),
 		table.WithStyles(tableStyle),
 	)
-	operatorRarityTbl.Blur()
+	propertyCountTbl.Blur()
 
 	return autoFormatTable{
-		table:  operatorRarityTbl,
+		table:  propertyCountTbl,
 		format: format,
+		keys:   nil,
+		rows:   nil,
+		tints:  nil,
+		cursor: 0,
+		offset: 0,
 	}
 }
 
-func newCountryRarityTable(tableStyle table.Styles) autoFormatTable {
-	countLen := 6
-	countryNameLen := 12
-	initialTableHeight := 5
-	format := newTableFormat(
-		columnFormat{fixed, float32(countLen)},
-		columnFormat{fill, float32(countryNameLen)},
-	)
-
-	// Create a new table with specified columns and initial empty rows.
-	countryRarityTbl := table.New(
-		// table header
-		table.WithColumns(
-			[]table.Column{
-				{Title: "Count", Width: countLen},
-				{Title: "country", Width: countryNameLen},
-			},
-		),
-		table.WithRows([]table.Row{}),
-		table.WithFocused(false),
-		table.WithHeight(initialTableHeight),
-		table.WithStyles(tableStyle),
-	)
-	countryRarityTbl.Blur()
-
-	return autoFormatTable{
-		table:  countryRarityTbl,
-		format: format,
-	}
-}
-
-func aircraftToRow(aircraft *internal.AircraftRecord, route *internal.FlightRouteRecord) table.Row {
+// aircraftToRow renders the aircraft as a row of the aircraft table, with the distance estimated
+// since the last poll if isEstimated.
+func aircraftToRow(
+	aircraft *internal.AircraftRecord,
+	route *internal.FlightRouteRecord,
+	estimated float64,
+	isEstimated bool,
+) table.Row {
 	return table.Row{
-		fmt.Sprintf("%3.0f", aircraft.CachedDist),
+		aircraftDistance(aircraft, estimated, isEstimated),
+		accuracyIndicator(aircraft.PositionAccuracy()),
 		aircraft.GetFlightNoAsStr(),
 		aircraft.CachedType,
 		route.Origin.IataCode,
 		route.Destination.IataCode,
-		aircraft.GetAltitudeAsStr(),
+		aircraft.AltBaro.String(),
 		fmt.Sprintf("%3.0f", aircraft.GroundSpeed),
 		fmt.Sprintf("%3.0f", aircraft.NavHeading),
 	}
 }
 
-func propertyCountToRow(propCount internal.PropertyCountTuple) table.Row {
-	return table.Row{fmt.Sprintf("%5d", propCount.Count), propCount.Property}
+// aircraftDistance renders the distance cell of the aircraft table. Distances from the last known
+// position are marked, since the aircraft has moved on since, and so are estimated ones.
+func aircraftDistance(aircraft *internal.AircraftRecord, estimated float64, isEstimated bool) string {
+	if position, ok := aircraft.KnownPosition(); ok && position.Stale {
+		return fmt.Sprintf("%3s", fmt.Sprintf("~%.0f", aircraft.CachedDist))
+	}
+	if isEstimated {
+		return fmt.Sprintf("%3s", fmt.Sprintf("%s%.0f", estimatedMarker, estimated))
+	}
+	return fmt.Sprintf("%3.0f", aircraft.CachedDist)
+}
+
+// accuracyIndicator shows the accuracy of a position as up to three dots, e.g. "●●○" for MLAT.
+func accuracyIndicator(accuracy internal.PositionAccuracy) string {
+	switch {
+	case accuracy >= internal.AccuracyGood:
+		return "●●●"
+	case accuracy >= internal.AccuracyMedium:
+		return "●●○"
+	case accuracy > internal.AccuracyNone:
+		return "●○○"
+	default:
+		return "○○○"
+	}
+}
+
+// propertyCountRows renders the rows of a rarity table from least to most common, keyed by
+// property. The rate is per hour of the given elapsed time, but at least of one hour, so that it
+// doesn't jump around in the first minutes. The first sightings are shown as dates in the given
+// location.
+func propertyCountRows(
+	propertyCountMap map[string]int,
+	elapsed time.Duration,
+	firstSeen map[string]time.Time,
+	location *time.Location,
+) ([]string, []table.Row) {
+	propertyCounts := internal.GetSortedCountsForProperty(propertyCountMap)
+	total := 0
+	for _, propCount := range propertyCounts {
+		total += propCount.Count
+	}
+	hours := max(elapsed.Hours(), 1)
+
+	keys := make([]string, len(propertyCounts))
+	rows := make([]table.Row, len(propertyCounts))
+	for idx, propCount := range propertyCounts {
+		keys[idx] = propCount.Property
+		first := ""
+		if seen, ok := firstSeen[propCount.Property]; ok {
+			first = seen.In(location).Format(time.DateOnly)
+		}
+		rows[idx] = propertyCountToRow(propCount, total, hours, first)
+	}
+	return keys, rows
+}
+
+func propertyCountToRow(
+	propCount internal.PropertyCountTuple,
+	total int,
+	hours float64,
+	firstSeen string,
+) table.Row {
+	percent := 100.0
+	return table.Row{
+		fmt.Sprintf("%5d", propCount.Count),
+		fmt.Sprintf("%5.1f%%", percent*float64(propCount.Count)/float64(total)),
+		fmt.Sprintf("%6.2f", float64(propCount.Count)/hours),
+		firstSeen,
+		propCount.Property,
+	}
 }
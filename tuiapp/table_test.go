@@ -1,9 +1,17 @@
 package tuiapp
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/micutio/airspottr/internal/i18n"
+	"github.com/muesli/termenv"
 )
 
 func TestTableFormat(t *testing.T) {
@@ -150,6 +158,11 @@ func TestAutoFormatTableInit(t *testing.T) {
 			aft := autoFormatTable{
 				table:  test.tableModel,
 				format: test.tableFormat,
+				keys:   nil,
+				rows:   nil,
+				tints:  nil,
+				cursor: 0,
+				offset: 0,
 			}
 
 			err := aft.resize(test.resizeWidth)
@@ -179,3 +192,241 @@ func TestAutoFormatTableInit(t *testing.T) {
 		})
 	}
 }
+
+func TestAutoFormatTableSetRows(t *testing.T) {
+	aft := autoFormatTable{
+		table:  table.New(table.WithColumns([]table.Column{{Title: "A", Width: 10}})),
+		format: newTableFormat(columnFormat{fill, .0}),
+		keys:   nil,
+		rows:   nil,
+		tints:  nil,
+		cursor: 0,
+		offset: 0,
+	}
+	rows := func(values ...string) []table.Row {
+		result := make([]table.Row, len(values))
+		for idx, value := range values {
+			result[idx] = table.Row{value}
+		}
+		return result
+	}
+
+	aft.setRows([]string{"a", "b", "c"}, rows("1", "2", "3"))
+	aft.setCursor(1)
+
+	// The selected row moves to the end and stays selected.
+	aft.setRows([]string{"d", "a", "c", "b"}, rows("4", "1", "3", "2"))
+	if key, _ := aft.selectedKey(); key != "b" || aft.cursor != 3 {
+		t.Errorf("selected %q at %d, expected b at 3", key, aft.cursor)
+	}
+
+	// The selected row is gone, so the cursor stays where it is as far as possible.
+	aft.setRows([]string{"d", "a"}, rows("4", "1"))
+	if key, _ := aft.selectedKey(); key != "a" || aft.cursor != 1 {
+		t.Errorf("selected %q at %d, expected a at 1", key, aft.cursor)
+	}
+
+	// Rows are compacted, so every row has a key.
+	if len(aft.table.Rows()) != len(aft.keys) {
+		t.Errorf("%d rows but %d keys", len(aft.table.Rows()), len(aft.keys))
+	}
+
+	aft.setRows(nil, nil)
+	if _, ok := aft.selectedKey(); ok {
+		t.Error("selectedKey() found a key in an empty table")
+	}
+}
+
+func TestAutoFormatTableSetColumn(t *testing.T) {
+	aft := newCurrentAircraftTable(table.DefaultStyles())
+	aft.SetHeight(4) // the header and two lines
+	if err := aft.resize(80); err != nil {
+		t.Fatalf("resize() error = %v", err)
+	}
+	keys := []string{"3c6444", "4b1805", "a0b1c2"}
+	rows := []table.Row{
+		{" 12", "●●●", "DLH1", "A320", "", "", "35000", "450", "270"},
+		{" 30", "●●●", "SWR2", "A220", "", "", "12000", "300", "90"},
+		{" 45", "●●●", "AFR3", "A321", "", "", "38000", "460", "180"},
+	}
+	aft.setRows(keys, rows)
+	aft.setCursor(1)
+
+	// Only the distances move, the rows keep their order and the selection.
+	moved := map[string]string{"3c6444": "≈10", "a0b1c2": "≈43"}
+	aft.setColumn(distanceColumn, func(key string) (string, bool) {
+		cell, ok := moved[key]
+		return cell, ok
+	})
+	distances := []string{aft.rows[0][distanceColumn], aft.rows[1][distanceColumn], aft.rows[2][distanceColumn]}
+	if !slices.Equal(distances, []string{"≈10", " 30", "≈43"}) || !slices.Equal(aft.keys, keys) {
+		t.Errorf("distances = %q of %q, expected the estimates in the same order", distances, aft.keys)
+	}
+	if key, _ := aft.selectedKey(); key != "4b1805" {
+		t.Errorf("selected %q, expected 4b1805", key)
+	}
+	if page := aft.table.Rows(); page[0][distanceColumn] != "≈10" {
+		t.Errorf("page starts with %q, expected the moved distance", page[0])
+	}
+}
+
+func TestPropertyCountRows(t *testing.T) {
+	counts := map[string]int{"A320": 6, "B748": 1, "A388": 3}
+
+	firstSeen := map[string]time.Time{
+		"B748": time.Date(2025, time.June, 1, 23, 30, 0, 0, time.UTC),
+		"A320": time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC),
+	}
+
+	keys, rows := propertyCountRows(counts, 2*time.Hour, firstSeen, time.FixedZone("CEST", 2*60*60))
+	expectedKeys := []string{"B748", "A388", "A320"}
+	expectedRows := []table.Row{
+		{"    1", " 10.0%", "  0.50", "2025-06-02", "B748"},
+		{"    3", " 30.0%", "  1.50", "", "A388"},
+		{"    6", " 60.0%", "  3.00", "2024-03-05", "A320"},
+	}
+	if !slices.Equal(keys, expectedKeys) {
+		t.Errorf("keys = %v, expected %v", keys, expectedKeys)
+	}
+	if !slices.EqualFunc(rows, expectedRows, slices.Equal) {
+		t.Errorf("rows = %q, expected %q", rows, expectedRows)
+	}
+
+	// Rates are per hour of at least one hour.
+	_, rows = propertyCountRows(map[string]int{"A320": 2}, time.Minute, nil, time.UTC)
+	if rate := rows[0][2]; rate != "  2.00" {
+		t.Errorf("rate after a minute = %q, expected 2.00", rate)
+	}
+}
+
+func TestAutoFormatTableWideCharacters(t *testing.T) {
+	aft := newPropertyCountTable(i18n.English, "Operator", table.DefaultStyles())
+	if err := aft.resize(40); err != nil {
+		t.Fatalf("resize() error = %v", err)
+	}
+	aft.setRows(
+		[]string{"jal", "aeroflot", "cargolux", "nl"},
+		[]table.Row{
+			{"1", "25.0 %", "1.0", "2025-06-01", "日本航空インターナショナル株式会社"},
+			{"1", "25.0 %", "1.0", "2025-06-01", "Аэрофлот — Российские авиалинии"},
+			{"1", "25.0 %", "1.0", "2025-06-01", "Cargolux Airlines International"},
+			{"1", "25.0 %", "1.0", "2025-06-01", "Koninklijke\nLuchtvaart Maatschappij"},
+		},
+	)
+
+	lines := strings.Split(aft.table.View(), "\n")
+	for idx, line := range lines {
+		if ansi.StringWidth(line) != ansi.StringWidth(lines[0]) {
+			t.Errorf("line %d is %d cells wide, expected %d: %q",
+				idx, ansi.StringWidth(line), ansi.StringWidth(lines[0]), line)
+		}
+	}
+
+	// Resizing fits the full values again rather than the truncated ones.
+	if err := aft.resize(200); err != nil {
+		t.Fatalf("resize() error = %v", err)
+	}
+	if !strings.Contains(aft.table.View(), "日本航空インターナショナル株式会社") {
+		t.Error("View() doesn't contain the full name after widening the table")
+	}
+}
+
+func TestFitCell(t *testing.T) {
+	tests := []struct {
+		value    string
+		width    int
+		expected string
+	}{
+		{"Lufthansa", 12, "Lufthansa"},
+		{"Lufthansa", 5, "Luft…"},
+		{"日本航空", 5, "日本…"},
+		{"Ålandsflyg", 4, "Åla…"},
+		{"a\tb", 3, "a b"},
+		{"Lufthansa", 0, ""},
+	}
+
+	for _, test := range tests {
+		actual := fitCell(test.value, test.width)
+		if actual != test.expected {
+			t.Errorf("fitCell(%q, %d) = %q, expected %q", test.value, test.width, actual, test.expected)
+		}
+		if ansi.StringWidth(actual) > test.width {
+			t.Errorf("fitCell(%q, %d) is %d cells wide", test.value, test.width, ansi.StringWidth(actual))
+		}
+	}
+}
+
+func TestAutoFormatTableTints(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.Ascii) })
+
+	aft := autoFormatTable{
+		table:  table.New(table.WithColumns([]table.Column{{Title: "A", Width: 4}, {Title: "B", Width: 6}})),
+		format: newTableFormat(columnFormat{fixed, 5}, columnFormat{fill, .0}),
+		keys:   nil,
+		rows:   nil,
+		tints:  nil,
+		cursor: 0,
+		offset: 0,
+	}
+	aft.table.SetHeight(5)
+	aft.setRows([]string{"a", "b", "c"}, []table.Row{{"1", "one"}, {"2", "two"}, {"3", "three"}})
+	aft.setCursor(0)
+	plain := aft.view()
+
+	red := lipgloss.Color("#FF0000")
+	aft.setTints([]lipgloss.TerminalColor{red, red, nil})
+	lines := strings.Split(aft.view(), "\n")
+	plainLines := strings.Split(plain, "\n")
+
+	// The header, the selected first row and the untinted last row stay as they are.
+	for idx, tinted := range []bool{false, false, true, false} {
+		if changed := lines[idx] != plainLines[idx]; changed != tinted {
+			t.Errorf("line %d %q tinted = %t, expected %t", idx, lines[idx], changed, tinted)
+		}
+	}
+}
+
+func TestAutoFormatTablePages(t *testing.T) {
+	aft := newCurrentAircraftTable(table.DefaultStyles())
+	aft.SetHeight(6) // the header and five lines
+	if err := aft.resize(80); err != nil {
+		t.Fatalf("resize() error = %v", err)
+	}
+	keys := make([]string, 300)
+	rows := make([]table.Row, len(keys))
+	for idx := range keys {
+		keys[idx] = fmt.Sprintf("%06x", idx)
+		rows[idx] = table.Row{"1", "●●●", fmt.Sprintf("DLH%d", idx), "A320", "", "", "35000", "450", "270"}
+	}
+	aft.setRows(keys, rows)
+
+	// Only the page is handed to the table, the last line tells which rows it shows.
+	if len(aft.table.Rows()) != 4 {
+		t.Errorf("table has %d rows, expected the page of 4", len(aft.table.Rows()))
+	}
+	lines := strings.Split(aft.view(), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "1-4 of 300" {
+		t.Errorf("last line = %q, expected 1-4 of 300", last)
+	}
+
+	// The page follows the cursor.
+	aft.moveDown(5)
+	if key, _ := aft.selectedKey(); key != keys[5] || aft.offset != 2 {
+		t.Errorf("selected %q on the page at %d, expected %q at 2", key, aft.offset, keys[5])
+	}
+	aft.moveDown(1000)
+	aft.moveUp(1)
+	if key, _ := aft.selectedKey(); key != keys[298] || aft.offset != 296 {
+		t.Errorf("selected %q on the page at %d, expected %q at 296", key, aft.offset, keys[298])
+	}
+	if !strings.Contains(aft.view(), "DLH299") {
+		t.Error("view() doesn't show the last row on the last page")
+	}
+
+	// Without more rows than fit, there are no pages.
+	aft.setRows(keys[:3], rows[:3])
+	if aft.isPaged() || aft.offset != 0 || len(aft.table.Rows()) != 3 {
+		t.Errorf("paged %t at %d with %d rows, expected all 3 rows", aft.isPaged(), aft.offset, len(aft.table.Rows()))
+	}
+}
@@ -1,6 +1,19 @@
 package tuiapp
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Themes of the TUI.
+const (
+	ThemeDefault = "default" // ThemeDefault is colourful, adapting to light and dark terminals.
+	ThemeMono    = "mono"    // ThemeMono only uses shades of grey, e.g. for monochrome terminals.
+)
+
+var errInvalidTheme = errors.New("invalid theme")
 
 type Theme struct {
 	Primary   lipgloss.AdaptiveColor
@@ -9,6 +22,8 @@ type Theme struct {
 	Border    lipgloss.AdaptiveColor
 	Green     lipgloss.AdaptiveColor
 	Red       lipgloss.AdaptiveColor
+	Yellow    lipgloss.AdaptiveColor
+	OnYellow  lipgloss.AdaptiveColor // OnYellow is the color of text on yellow, e.g. in banners.
 }
 
 func getDefaultTheme() Theme {
@@ -19,5 +34,58 @@ func getDefaultTheme() Theme {
 		Border:    lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"},
 		Green:     lipgloss.AdaptiveColor{Light: "#00FF00", Dark: "#00FF00"},
 		Red:       lipgloss.AdaptiveColor{Light: "#FF0000", Dark: "#FF0000"},
+		Yellow:    lipgloss.AdaptiveColor{Light: "#FFD700", Dark: "#FFD700"},
+		OnYellow:  lipgloss.AdaptiveColor{Light: "#000000", Dark: "#000000"},
+	}
+}
+
+// getMonoTheme tells apart what the default theme tells apart by colour by brightness instead.
+func getMonoTheme() Theme {
+	return Theme{
+		Primary:   lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+		Secondary: lipgloss.AdaptiveColor{Light: "#8A8A8A", Dark: "#6C6C6C"},
+		Highlight: lipgloss.AdaptiveColor{Light: "#4E4E4E", Dark: "#BCBCBC"},
+		Border:    lipgloss.AdaptiveColor{Light: "#D0D0D0", Dark: "#3A3A3A"},
+		Green:     lipgloss.AdaptiveColor{Light: "#9E9E9E", Dark: "#808080"},
+		Red:       lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+		Yellow:    lipgloss.AdaptiveColor{Light: "#585858", Dark: "#D0D0D0"},
+		OnYellow:  lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+	}
+}
+
+// ThemeNames lists the names of all themes.
+func ThemeNames() []string {
+	return []string{ThemeDefault, ThemeMono}
+}
+
+// ValidateTheme checks that the name is one of the ThemeXxx themes.
+func ValidateTheme(name string) error {
+	switch name {
+	case ThemeDefault, ThemeMono:
+		return nil
+	}
+	return fmt.Errorf("ValidateTheme: %w: %q", errInvalidTheme, name)
+}
+
+// getTheme returns the theme of the given name, the default theme if there is none of that name.
+func getTheme(name string) Theme {
+	if name == ThemeMono {
+		return getMonoTheme()
+	}
+	return getDefaultTheme()
+}
+
+// tierColor returns the colour of the rows of aircraft in the proximity tier of the given index,
+// from the closest to the farthest tier, nil for farther tiers or aircraft beyond all tiers.
+func (theme Theme) tierColor(tier int) lipgloss.TerminalColor {
+	switch tier {
+	case 0:
+		return theme.Red
+	case 1:
+		return theme.Yellow
+	case 2: //nolint:mnd // the third tier
+		return theme.Green
+	default:
+		return nil
 	}
 }
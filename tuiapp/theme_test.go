@@ -0,0 +1,21 @@
+package tuiapp
+
+import "testing"
+
+func TestThemes(t *testing.T) {
+	for _, name := range ThemeNames() {
+		if err := ValidateTheme(name); err != nil {
+			t.Errorf("ValidateTheme(%q) error = %v", name, err)
+		}
+	}
+	if err := ValidateTheme("neon"); err == nil {
+		t.Error("ValidateTheme(\"neon\") succeeded, expected an error")
+	}
+
+	if theme := getTheme(ThemeMono); theme.Red == getDefaultTheme().Red {
+		t.Error("getTheme(mono) has the red of the default theme")
+	}
+	if theme := getTheme("neon"); theme != getDefaultTheme() {
+		t.Error("getTheme(neon) isn't the default theme")
+	}
+}
@@ -23,43 +23,50 @@ import (
 	"io"
 	"log" //nolint:depguard // Don't feel like using slog for now.
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/i18n"
 )
 
-const (
-	errLogFilePath = "./airspottr.log"
-)
-
-// setupLogger creates and configures the error log file.
-func setupLogger() (*os.File, error) {
-	errLogFile, err := os.OpenFile(errLogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
-	}
-	return errLogFile, nil
-}
+const noteCharLimit = 200
 
-// setupRequestAndDashboard initializes the dashboard and notification system.
-func setupRequestAndDashboard(
-	requestOptions internal.RequestOptions,
+// setupDashboardAndNotifier initializes the request, dashboard and notification system.
+// The TUI has no console, so the events the notifier would print to it are logged instead.
+func setupDashboardAndNotifier(
+	appName string,
+	options internal.AppOptions,
 	errWriter io.Writer,
-) (*internal.Request, *internal.Dashboard, error) {
-	request, reqErr := internal.NewRequest(requestOptions, &errWriter)
+) (*internal.Request, *internal.Dashboard, *internal.Notify, error) {
+	request, reqErr := internal.NewRequest(options.Request, &errWriter)
 	if reqErr != nil {
-		return nil, nil, fmt.Errorf("failed to create request: %w", reqErr)
+		return nil, nil, nil, fmt.Errorf("failed to create request: %w", reqErr)
 	}
 
-	dashboard, dbErr := internal.NewDashboard(requestOptions.Lat, requestOptions.Lon, &errWriter)
+	dashboard, dbErr := internal.NewDashboard(
+		options.Request.Lat,
+		options.Request.Lon,
+		options.Dashboard,
+		&errWriter)
 	if dbErr != nil {
-		return nil, nil, fmt.Errorf("failed to create dashboard: %w", dbErr)
+		return nil, nil, nil, fmt.Errorf("failed to create dashboard: %w", dbErr)
 	}
 
-	return request, dashboard, nil
+	notify, notifyErr := internal.NewNotify(appName, options.Notify, errWriter, errWriter)
+	if notifyErr != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create notifier: %w", notifyErr)
+	}
+	if err := notify.KeepNotifications(dashboard.NotificationStore()); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load notifications: %w", err)
+	}
+
+	return request, dashboard, notify, nil
 }
 
 type tableSetup struct {
@@ -71,7 +78,7 @@ type tableSetup struct {
 }
 
 // initTables creates and configures all tables used in the TUI.
-func initTables(theme Theme) tableSetup {
+func initTables(theme Theme, lang i18n.Language) tableSetup {
 	tableStyle := table.DefaultStyles()
 	tableStyle.Header.Padding(0)
 	tableStyle.Cell.Padding(0)
@@ -79,42 +86,28 @@ func initTables(theme Theme) tableSetup {
 
 	return tableSetup{
 		current:   newCurrentAircraftTable(tableStyle),
-		types:     newTypeRarityTable(tableStyle),
-		operators: newOperatorRarityTable(tableStyle),
-		countries: newCountryRarityTable(tableStyle),
+		types:     newPropertyCountTable(lang, "Type", tableStyle),
+		operators: newPropertyCountTable(lang, "Operator", tableStyle),
+		countries: newPropertyCountTable(lang, "Country", tableStyle),
 		style:     tableStyle,
 	}
 }
 
-func Run(appName string, requestOptions internal.RequestOptions) {
-	// Set up logging
-	errLogFile, err := setupLogger()
-	if err != nil {
-		log.Fatalf("failed to set up logging: %v", err)
-	}
-	defer func() {
-		if closeErr := errLogFile.Close(); err != nil {
-			log.Printf("error closing log file: %v", closeErr)
-		}
-	}()
-
-	// Using io.Discard for notifications as we don't need to close it
-	notify := internal.NewNotify(appName, new(io.Discard))
-
-	// Initialise dashboard and notification system
-	request, dashboard, err := setupRequestAndDashboard(requestOptions, errLogFile)
-	if err != nil {
-		log.Printf("failed to set up dashboard and notifier: %v", err)
-	}
-
-	dashboard.FinishWarmupPeriod()
+// newNoteInput creates the input for notes on aircraft, e.g. "saw this one at the airshow".
+func newNoteInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "note on this aircraft"
+	input.CharLimit = noteCharLimit
+	return input
+}
 
-	// Initialise tables and theme
-	theme := getDefaultTheme()
-	tables := initTables(theme)
+// newModel creates the application model, which takes over the request, dashboard and notifier
+// once the startup sends them.
+func newModel(options internal.AppOptions, logRing *internal.LogRing, startupMessages <-chan tea.Msg) model {
+	theme := getTheme(options.Theme)
+	tables := initTables(theme, options.Notify.Language)
 
-	// Initialise and run the application model
-	appModel := model{
+	return model{
 		width:              0,
 		height:             0,
 		baseStyle:          lipgloss.NewStyle(),
@@ -126,18 +119,106 @@ func Run(appName string, requestOptions internal.RequestOptions) {
 		operatorRarityTbl:  tables.operators,
 		countryRarityTbl:   tables.countries,
 		selectedTable:      &tables.current,
-		uiState:            mainPage,
+		layout:             newLayout(options.Layout),
+		groupOperators:     false,
+		familyTypes:        false,
+		nextRarityTable:    0,
+		detailAircraft:     nil,
+		noteInput:          newNoteInput(),
+		noteErr:            nil,
+		graphics:           detectGraphics(options.Images, os.Getenv),
+		photoImages:        make(map[string]photoImage),
+		photoOrder:         nil,
+		nextImageID:        0,
+		shownImage:         0,
+		pendingGraphics:    "",
+		graphicsGeneration: 0,
+		receiverStats:      internal.ReceiverStats{},
+		receiverStatsErr:   nil,
+		alertBanners:       nil,
+		reloaded:           time.Time{},
+		reloadErr:          nil,
+		logRing:            logRing,
+		logScroll:          0,
+		logExported:        "",
+		logExportErr:       nil,
+		errorHistory:       nil,
+		settingsCursor:     0,
+		export:             options.Export,
+		snapshotPath:       "",
+		uiState:            startupPage,
 		startTime:          time.Now(),
 		lastUpdate:         time.Unix(0, 0),
-		request:            request,
-		dashboard:          dashboard,
-		notify:             notify,
-		options:            requestOptions,
+		timeDisplay:        options.Notify.TimeDisplay,
+		language:           options.Notify.Language,
+		request:            nil,
+		feed:               nil,
+		dashboard:          nil,
+		notify:             nil,
+		options:            options.Request,
+		polling:            options.Polling,
+		configPath:         options.ConfigPath,
+		startup: startupState{
+			configPath:     options.ConfigPath,
+			datasetsLoaded: 0,
+			datasetsTotal:  0,
+			lastDataset:    "",
+			ready:          false,
+			err:            nil,
+		},
+		startupMessages: startupMessages,
 	}
+}
+
+func Run(appName string, options internal.AppOptions) {
+	// All log output is kept in memory while the TUI covers the terminal, to be viewed and
+	// exported from the log page.
+	logRing := internal.NewLogRing(internal.DefaultLogRingBytes)
+	log.SetOutput(logRing)
+
+	appModel := newModel(options, logRing, startup(appName, options, logRing))
 
 	// Create and run Bubble Tea program with alternate screen
 	p := tea.NewProgram(&appModel, tea.WithAltScreen())
-	if _, progErr := p.Run(); progErr != nil {
+
+	// Datasets and config are reloaded on SIGHUP, as well as with a key.
+	reloadSignal := make(chan os.Signal, 1)
+	signal.Notify(reloadSignal, syscall.SIGHUP)
+	defer signal.Stop(reloadSignal)
+	go func() {
+		for range reloadSignal {
+			p.Send(ReloadMsg{})
+		}
+	}()
+	_, progErr := p.Run()
+	log.SetOutput(os.Stderr)
+	if progErr != nil {
 		log.Printf("error running program: %v", progErr)
 	}
+
+	if appModel.startup.err != nil {
+		for _, line := range logRing.Lines() {
+			fmt.Fprintln(os.Stderr, line)
+		}
+		log.Printf("failed to start: %v", appModel.startup.err)
+	}
+	if appModel.notify == nil {
+		return // quit before the startup finished
+	}
+
+	if closeErr := appModel.notify.Close(); closeErr != nil {
+		log.Printf("failed to close notifier: %v", closeErr)
+	}
+
+	if exportErr := appModel.dashboard.ExportCSV(options.Export); exportErr != nil {
+		log.Printf("failed to export statistics: %v", exportErr)
+	}
+	if path := options.Export.StatsPath; path != "" {
+		if exportErr := appModel.dashboard.SessionStats().Export(path); exportErr != nil {
+			log.Printf("failed to export session statistics: %v", exportErr)
+		}
+	}
+	if closeErr := appModel.dashboard.Close(); closeErr != nil {
+		log.Printf("failed to close sighting history: %v", closeErr)
+	}
 }
@@ -3,7 +3,16 @@ package tuiapp
 type uiState int
 
 const (
-	mainPage        uiState = iota     // first page on startup, showing current aircraft
-	aircraftDetails uiState = iota + 1 // current aircraft, overlaid by details of selected
-	globalStats     uiState = iota + 2 // second page, showing type, operator and country rarity
+	mainPage          uiState = iota      // first page on startup, showing current aircraft
+	aircraftDetails   uiState = iota + 1  // current aircraft, overlaid by details of selected
+	globalStats       uiState = iota + 2  // second page, showing type, operator and country rarity
+	receiverStats     uiState = iota + 3  // feeding statistics of the local receiver
+	startupPage       uiState = iota + 4  // progress of the startup, until the first aircraft arrive
+	logPage           uiState = iota + 5  // recent log output, which can be exported
+	errorPage         uiState = iota + 6  // errors of the session, newest first
+	favouritesPage    uiState = iota + 7  // favourite aircraft, those in sight first
+	settingsPage      uiState = iota + 8  // categories of desktop notifications, to switch on or off
+	notificationsPage uiState = iota + 9  // latest notifications, newest first
+	followPage        uiState = iota + 10 // the aircraft followed with --follow and its track
+	achievementsPage  uiState = iota + 11 // achievements and how far each of them has come
 )
@@ -0,0 +1,158 @@
+package tuiapp
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/micutio/airspottr/internal"
+	"github.com/micutio/airspottr/internal/testsupport"
+	"github.com/muesli/termenv"
+)
+
+// goldenTimeout is how long a view may take to show up, which is far longer than it should take.
+const goldenTimeout = 5 * time.Second
+
+func init() { //nolint:gochecknoinits // the golden views must not depend on the terminal running the tests
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+}
+
+// newGoldenModel creates the model as the app does, with times shown in UTC.
+func newGoldenModel(t *testing.T, startupMessages <-chan tea.Msg) *model {
+	t.Helper()
+
+	timeDisplay, err := internal.NewTimeDisplay(internal.TimeZoneUTC, "15:04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The next poll is far off, so that the view only changes with the keys of the test.
+	polling := internal.PollingOptions{Interval: time.Hour, SummaryInterval: time.Hour} //nolint:exhaustruct // fixed
+
+	options := internal.AppOptions{ //nolint:exhaustruct // defaults
+		ConfigPath: "airspottr.json",
+		Notify:     internal.NotifyOptions{TimeDisplay: timeDisplay}, //nolint:exhaustruct // defaults
+		Polling:    polling,
+	}
+	m := newModel(options, internal.NewLogRing(internal.DefaultLogRingBytes), startupMessages)
+	return &m
+}
+
+// goldenRoutes are the routes of the flights of the hamburg fixture, so that none are looked up.
+func goldenRoutes() []internal.FlightRouteRecord {
+	routes := make([]internal.FlightRouteRecord, 0, 5) //nolint:mnd // flights of the fixture
+	for _, flight := range [][3]string{
+		{"DLH4AB", "HAM", "FRA"},
+		{"RYR8VA", "DUB", "KRK"},
+		{"UAE15", "DXB", "IAD"},
+		{"DLH2YC", "HAM", "MUC"},
+		{"ADB3474", "GML", "LEJ"},
+	} {
+		route := *internal.GetDefaultFlightrouteRecord()
+		route.Callsign = flight[0]
+		route.Origin.IataCode = flight[1]
+		route.Destination.IataCode = flight[2]
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// startGolden starts the app on the hamburg fixture and waits for its aircraft to show up.
+func startGolden(t *testing.T, width int, height int) *teatest.TestModel {
+	t.Helper()
+
+	opts := internal.DashboardOptions{RarityScorer: "ratio"} //nolint:exhaustruct // defaults
+	harness := testsupport.NewHarness(t, opts, testsupport.Fixture(t, "hamburg"))
+	harness.Dashboard.AssignFlightRoutes(goldenRoutes())
+	disabled := false
+	sinks := internal.SinksConfig{Desktop: internal.SinkConfig{Enabled: &disabled}} //nolint:exhaustruct // desktop off
+	notify, notifyErr := internal.NewNotify(
+		"test", internal.NotifyOptions{Sinks: sinks}, nil, io.Discard) //nolint:exhaustruct // no sinks
+	if notifyErr != nil {
+		t.Fatal(notifyErr)
+	}
+
+	startupMessages := make(chan tea.Msg, 1)
+	startupMessages <- StartupDoneMsg{
+		request:   harness.Request,
+		dashboard: harness.Dashboard,
+		notify:    notify,
+		err:       nil,
+	}
+	close(startupMessages)
+
+	tm := teatest.NewTestModel(t, newGoldenModel(t, startupMessages), teatest.WithInitialTermSize(width, height))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("UAE15"))
+	}, teatest.WithDuration(goldenTimeout))
+	return tm
+}
+
+// requireGoldenView quits the app and compares its final view with the golden file of the test.
+// Golden files are updated by running the tests with -update.
+func requireGoldenView(t *testing.T, tm *teatest.TestModel, testDir string) {
+	t.Helper()
+
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	final, ok := tm.FinalModel(t, teatest.WithFinalTimeout(goldenTimeout)).(*model)
+	if !ok {
+		t.Fatal("FinalModel() isn't the model of the app")
+	}
+	view := final.View()
+	// The harness runs the app from the root of the repository, the golden files are with the tests.
+	t.Chdir(testDir)
+	golden.RequireEqual(t, []byte(view))
+}
+
+func TestGoldenViews(t *testing.T) {
+	testDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		keys   []tea.KeyMsg
+	}{
+		{name: "main_80x24", width: 80, height: 24, keys: nil},
+		{name: "main_160x48", width: 160, height: 48, keys: nil},
+		{name: "rarity_120x40", width: 120, height: 40, keys: []tea.KeyMsg{{Type: tea.KeySpace, Runes: []rune(" ")}}},
+		{name: "rarity_200x50", width: 200, height: 50, keys: []tea.KeyMsg{{Type: tea.KeySpace, Runes: []rune(" ")}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tm := startGolden(t, test.width, test.height)
+			for _, key := range test.keys {
+				tm.Send(key)
+			}
+			requireGoldenView(t, tm, testDir)
+		})
+	}
+}
+
+func TestGoldenStartupView(t *testing.T) {
+	testDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startupMessages := make(chan tea.Msg, 1)
+	startupMessages <- StartupProgressMsg{dataset: "Airlines.csv", loaded: 3, total: 9}
+	close(startupMessages)
+
+	tm := teatest.NewTestModel(t, newGoldenModel(t, startupMessages), teatest.WithInitialTermSize(80, 12))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("3/9"))
+	}, teatest.WithDuration(goldenTimeout))
+	requireGoldenView(t, tm, testDir)
+}
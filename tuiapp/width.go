@@ -0,0 +1,28 @@
+package tuiapp
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ellipsis marks truncated cells.
+const ellipsis = "…"
+
+// fitCell makes the value fit into a column of the given width, measured in terminal cells rather
+// than bytes or runes, so that wide characters like CJK take up two cells and combining accents
+// none. Longer values are truncated with an ellipsis. Control characters, e.g. line breaks in the
+// feed data, are replaced by spaces, since they would break up the row.
+func fitCell(value string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, value)
+	return ansi.Truncate(value, width, ellipsis)
+}